@@ -0,0 +1,125 @@
+package benchmarks
+
+import (
+	"os"
+	"os/exec"
+	"sync"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+	"github.com/devblac/go-semver-audit/internal/fixture"
+)
+
+// loaded is a generated fixture module loaded once and shared by every benchmark
+type loaded struct {
+	pkgs []*packages.Package // version 1, type-checked
+	old  *analyzer.Snapshot
+	new  *analyzer.Snapshot
+	err  error
+}
+
+var (
+	fixturesMu sync.Mutex
+	fixtures   = make(map[string]*loaded)
+)
+
+// loadFixture writes and loads both versions of the fixture of the given
+// size the first time it is asked for
+func loadFixture(tb testing.TB, size Size) *loaded {
+	tb.Helper()
+	if _, err := exec.LookPath("go"); err != nil {
+		tb.Skip("go command not available")
+	}
+
+	fixturesMu.Lock()
+	defer fixturesMu.Unlock()
+	f, ok := fixtures[size.Name]
+	if !ok {
+		f = &loaded{}
+		var v1, v2 []*packages.Package
+		if v1, f.err = loadVersion(size, false); f.err == nil {
+			v2, f.err = loadVersion(size, true)
+		}
+		if f.err == nil {
+			f.pkgs = v1
+			f.old = &analyzer.Snapshot{Module: fixture.DefaultModule, Version: "v1.0.0", API: analyzer.ExtractAPI(v1)}
+			f.new = &analyzer.Snapshot{Module: fixture.DefaultModule, Version: "v2.0.0", API: analyzer.ExtractAPI(v2)}
+		}
+		fixtures[size.Name] = f
+	}
+	if f.err != nil {
+		tb.Fatalf("failed to load %s fixture: %v", size.Name, f.err)
+	}
+	return f
+}
+
+// loadVersion type-checks one version of a fixture in a temporary directory
+func loadVersion(size Size, newVersion bool) ([]*packages.Package, error) {
+	dir, err := os.MkdirTemp("", "go-semver-audit-bench-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	if err := fixture.Write(dir, size.Spec, newVersion); err != nil {
+		return nil, err
+	}
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax |
+			packages.NeedTypesInfo,
+		Dir: dir,
+		Env: append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod"),
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return nil, err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, os.ErrInvalid
+	}
+	return pkgs, nil
+}
+
+func BenchmarkExtractAPI(b *testing.B) {
+	for _, size := range Sizes {
+		b.Run(size.Name, func(b *testing.B) { benchExtractAPI(b, size) })
+	}
+}
+
+func benchExtractAPI(b *testing.B, size Size) {
+	f := loadFixture(b, size)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		analyzer.ExtractAPI(f.pkgs)
+	}
+}
+
+func BenchmarkDiffAPIs(b *testing.B) {
+	for _, size := range Sizes {
+		b.Run(size.Name, func(b *testing.B) { benchDiffAPIs(b, size) })
+	}
+}
+
+func benchDiffAPIs(b *testing.B, size Size) {
+	f := loadFixture(b, size)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		analyzer.DiffSnapshots(f.old, f.new)
+	}
+}
+
+func TestFixtureChanges(t *testing.T) {
+	f := loadFixture(t, Sizes[0])
+
+	changes := analyzer.DiffSnapshots(f.old, f.new).Changes
+	// Per package of 32 declarations: 2 functions and 2 methods removed, 2
+	// functions changed, and 2 interfaces gained Close
+	pkgs := Sizes[0].Spec.Packages
+	if len(changes.Removed) != 4*pkgs || len(changes.Changed) != 2*pkgs || len(changes.InterfaceChanges) != 2*pkgs {
+		t.Errorf("diff has %d removed, %d changed, %d interface changes, want %d, %d, %d",
+			len(changes.Removed), len(changes.Changed), len(changes.InterfaceChanges), 4*pkgs, 2*pkgs, 2*pkgs)
+	}
+}
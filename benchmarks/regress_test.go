@@ -0,0 +1,132 @@
+package benchmarks
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+var (
+	benchRegress   = flag.Bool("bench-regress", false, "Run the benchmarks and fail if any is slower or allocates more than its baseline in "+baselinesPath)
+	benchUpdate    = flag.Bool("bench-update", false, "Run the benchmarks and rewrite "+baselinesPath+" with their results")
+	benchTolerance = flag.Float64("bench-tolerance", 0.25, "Fraction by which -bench-regress lets a benchmark exceed its baseline")
+)
+
+// baselinesPath holds the stored results -bench-regress compares against
+var baselinesPath = filepath.Join("testdata", "baselines.json")
+
+// baseline is the stored result of one benchmark
+type baseline struct {
+	NsPerOp     int64 `json:"ns_per_op"`
+	AllocsPerOp int64 `json:"allocs_per_op"`
+	BytesPerOp  int64 `json:"bytes_per_op"`
+}
+
+// suite lists the benchmarks -bench-regress runs, by the name go test -bench
+// reports them under
+func suite() map[string]func(*testing.B) {
+	benchmarks := make(map[string]func(*testing.B))
+	for _, size := range Sizes {
+		size := size
+		benchmarks["ExtractAPI/"+size.Name] = func(b *testing.B) { benchExtractAPI(b, size) }
+		benchmarks["DiffAPIs/"+size.Name] = func(b *testing.B) { benchDiffAPIs(b, size) }
+	}
+	return benchmarks
+}
+
+func TestBenchRegress(t *testing.T) {
+	if !*benchRegress && !*benchUpdate {
+		t.Skip("run with -bench-regress to compare against the baselines, or -bench-update to rewrite them")
+	}
+	for _, size := range Sizes {
+		loadFixture(t, size) // outside the timed runs
+	}
+
+	results := make(map[string]baseline)
+	for name, bench := range suite() {
+		r := testing.Benchmark(bench)
+		results[name] = baseline{NsPerOp: r.NsPerOp(), AllocsPerOp: r.AllocsPerOp(), BytesPerOp: r.AllocedBytesPerOp()}
+		t.Logf("%s: %d ns/op, %d allocs/op, %d B/op", name, r.NsPerOp(), r.AllocsPerOp(), r.AllocedBytesPerOp())
+	}
+
+	if *benchUpdate {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(baselinesPath, append(data, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(baselinesPath)
+	if err != nil {
+		t.Fatalf("failed to read baselines: %v", err)
+	}
+	var baselines map[string]baseline
+	if err := json.Unmarshal(data, &baselines); err != nil {
+		t.Fatalf("failed to parse %s: %v", baselinesPath, err)
+	}
+	for _, msg := range regressions(baselines, results, *benchTolerance) {
+		t.Error(msg)
+	}
+}
+
+// regressions describes every result exceeding its baseline by more than
+// tolerance, and every benchmark without a baseline
+func regressions(baselines, results map[string]baseline, tolerance float64) []string {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	exceeds := func(got, want int64) bool {
+		return float64(got) > float64(want)*(1+tolerance)
+	}
+	var msgs []string
+	for _, name := range names {
+		got := results[name]
+		want, ok := baselines[name]
+		if !ok {
+			msgs = append(msgs, fmt.Sprintf("%s has no baseline; rewrite them with -bench-update", name))
+			continue
+		}
+		if exceeds(got.NsPerOp, want.NsPerOp) {
+			msgs = append(msgs, fmt.Sprintf("%s: %d ns/op, baseline %d", name, got.NsPerOp, want.NsPerOp))
+		}
+		if exceeds(got.AllocsPerOp, want.AllocsPerOp) {
+			msgs = append(msgs, fmt.Sprintf("%s: %d allocs/op, baseline %d", name, got.AllocsPerOp, want.AllocsPerOp))
+		}
+		if exceeds(got.BytesPerOp, want.BytesPerOp) {
+			msgs = append(msgs, fmt.Sprintf("%s: %d B/op, baseline %d", name, got.BytesPerOp, want.BytesPerOp))
+		}
+	}
+	return msgs
+}
+
+func TestRegressions(t *testing.T) {
+	baselines := map[string]baseline{
+		"ExtractAPI/small": {NsPerOp: 1000, AllocsPerOp: 100, BytesPerOp: 4096},
+		"DiffAPIs/small":   {NsPerOp: 1000, AllocsPerOp: 100, BytesPerOp: 4096},
+	}
+	results := map[string]baseline{
+		"ExtractAPI/small": {NsPerOp: 1200, AllocsPerOp: 100, BytesPerOp: 4096}, // within 25%
+		"DiffAPIs/small":   {NsPerOp: 900, AllocsPerOp: 150, BytesPerOp: 4096},
+		"DiffAPIs/huge":    {NsPerOp: 1},
+	}
+
+	got := regressions(baselines, results, 0.25)
+	want := []string{
+		"DiffAPIs/huge has no baseline; rewrite them with -bench-update",
+		"DiffAPIs/small: 150 allocs/op, baseline 100",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("regressions() = %q, want %q", got, want)
+	}
+}
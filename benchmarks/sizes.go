@@ -0,0 +1,48 @@
+// Package benchmarks measures API extraction and diffing on generated fixture
+// modules of several sizes, so performance work such as caching or loading
+// from export data can be measured in-repo.
+//
+// Run the suite with
+//
+//	go test ./benchmarks -run '^$' -bench . -benchmem
+//
+// and gate on regressions against testdata/baselines.json with
+//
+//	go test ./benchmarks -run TestBenchRegress -bench-regress
+//
+// Timings depend on the machine: after a deliberate change, or on a new CI
+// runner, rewrite the baselines with -bench-update.
+package benchmarks
+
+import "github.com/devblac/go-semver-audit/internal/fixture"
+
+// Size is a fixture module every benchmark runs on
+type Size struct {
+	Name string
+	Spec fixture.Spec
+}
+
+// Sizes are the fixture modules every benchmark runs on
+var Sizes = []Size{
+	{Name: "small", Spec: spec(2, 32)},
+	{Name: "medium", Spec: spec(8, 128)},
+	{Name: "large", Spec: spec(32, 256)},
+}
+
+// spec describes a fixture whose packages split decls declarations evenly
+// between functions, structs, interfaces, and constants. Of every 16
+// declarations, the new version removes a function, changes the signature of
+// another, drops the method of a struct, and adds a method to an interface.
+func spec(packages, decls int) fixture.Spec {
+	return fixture.Spec{
+		Packages:          packages,
+		Funcs:             decls / 4,
+		Structs:           decls / 4,
+		Interfaces:        decls / 4,
+		Consts:            decls / 4,
+		RemovedFuncs:      decls / 16,
+		ChangedFuncs:      decls / 16,
+		RemovedMethods:    decls / 16,
+		ChangedInterfaces: decls / 16,
+	}
+}
@@ -0,0 +1,68 @@
+// Command gen-fixture writes the old and new versions of a synthetic module
+// with a chosen number of API changes, for testing and profiling
+// go-semver-audit on modules of any size. It is a development tool.
+//
+// Usage:
+//
+//	go run ./cmd/gen-fixture -out /tmp/fixture -packages 10 -funcs 200 -removed 5 -changed 5
+//
+// writes /tmp/fixture/old and /tmp/fixture/new.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/devblac/go-semver-audit/internal/fixture"
+)
+
+// Allow overriding in tests
+var (
+	stdoutWriter io.Writer = os.Stdout
+	stderrWriter io.Writer = os.Stderr
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(stderrWriter, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("gen-fixture", flag.ContinueOnError)
+	fs.SetOutput(stderrWriter)
+	var spec fixture.Spec
+	out := fs.String("out", "", "Directory to write the old and new versions to (required)")
+	fs.StringVar(&spec.Module, "module", fixture.DefaultModule, "Module path of the fixture")
+	fs.IntVar(&spec.Packages, "packages", 1, "Number of packages")
+	fs.IntVar(&spec.Funcs, "funcs", 10, "Exported functions per package")
+	fs.IntVar(&spec.Structs, "structs", 5, "Exported structs per package, each with two fields and a method")
+	fs.IntVar(&spec.Interfaces, "interfaces", 2, "Exported interfaces per package")
+	fs.IntVar(&spec.Consts, "consts", 0, "Exported constants per package")
+	fs.IntVar(&spec.RemovedFuncs, "removed", 0, "Functions per package the new version removes")
+	fs.IntVar(&spec.ChangedFuncs, "changed", 0, "Functions per package whose signature the new version changes")
+	fs.IntVar(&spec.AddedFuncs, "added", 0, "Functions per package the new version adds")
+	fs.IntVar(&spec.RemovedFields, "removed-fields", 0, "Structs per package that lose a field")
+	fs.IntVar(&spec.RemovedMethods, "removed-methods", 0, "Structs per package that lose their method")
+	fs.IntVar(&spec.ChangedInterfaces, "changed-interfaces", 0, "Interfaces per package that gain a method")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" || fs.NArg() > 0 {
+		return fmt.Errorf("usage: gen-fixture -out dir [options]")
+	}
+
+	oldDir, newDir := filepath.Join(*out, "old"), filepath.Join(*out, "new")
+	if err := fixture.Write(oldDir, spec, false); err != nil {
+		return err
+	}
+	if err := fixture.Write(newDir, spec, true); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdoutWriter, "Wrote %s with %d package(s) to %s and %s\n", spec.Module, spec.Packages, oldDir, newDir)
+	return nil
+}
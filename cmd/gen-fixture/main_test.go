@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	var stdout bytes.Buffer
+	stdoutWriter = &stdout
+	defer func() { stdoutWriter = os.Stdout }()
+
+	out := t.TempDir()
+	if err := run([]string{"-out", out, "-packages", "2", "-funcs", "3", "-removed", "1"}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	oldSrc, err := os.ReadFile(filepath.Join(out, "old", "p1", "p1.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newSrc, err := os.ReadFile(filepath.Join(out, "new", "p1", "p1.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(oldSrc), "func P1F0(") || strings.Contains(string(newSrc), "func P1F0(") {
+		t.Errorf("P1F0 should be in the old version only:\nold:\n%s\nnew:\n%s", oldSrc, newSrc)
+	}
+	if !strings.Contains(stdout.String(), "with 2 package(s)") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	stderrWriter = io.Discard
+	defer func() { stderrWriter = os.Stderr }()
+
+	if err := run(nil); err == nil || !strings.Contains(err.Error(), "usage") {
+		t.Errorf("run() without -out error = %v, want usage", err)
+	}
+	if err := run([]string{"-out", t.TempDir(), "-funcs", "1", "-removed", "2"}); err == nil {
+		t.Error("run() with more removed than functions succeeded")
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+	"github.com/devblac/go-semver-audit/internal/report"
+)
+
+// snapshotFn extracts the exported API of a module version
+var snapshotFn = func(module, version string) (*analyzer.Snapshot, error) {
+	a, err := analyzer.New(".")
+	if err != nil {
+		return nil, err
+	}
+	return a.Snapshot(module, version)
+}
+
+// runAPI implements `go-semver-audit api`
+func runAPI(args []string) error {
+	fs := flag.NewFlagSet("api", flag.ContinueOnError)
+	fs.SetOutput(stderrWriter)
+	jsonOutput := fs.Bool("json", false, "Output the API as a JSON snapshot")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: go-semver-audit api [-json] module@version")
+	}
+
+	spec, err := analyzer.ParseUpgrade(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	snap, err := snapshotFn(spec.Module, spec.NewVersion)
+	if err != nil {
+		return err
+	}
+
+	output := report.FormatAPIText(snap)
+	if *jsonOutput {
+		output, err = report.FormatAPIJSON(snap)
+		if err != nil {
+			return fmt.Errorf("failed to generate report: %w", err)
+		}
+	}
+	fmt.Fprint(stdoutWriter, output)
+	return nil
+}
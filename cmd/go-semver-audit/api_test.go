@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestRunAPI(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+	oldSnapshot := snapshotFn
+	defer func() { snapshotFn = oldSnapshot }()
+
+	var gotModule, gotVersion string
+	snapshotFn = func(module, version string) (*analyzer.Snapshot, error) {
+		gotModule, gotVersion = module, version
+		return &analyzer.Snapshot{Module: module, Version: "v1.2.0", API: &analyzer.API{
+			Funcs: map[string]*analyzer.Function{"Open": {Name: "Open", Signature: analyzer.Signature{Text: "func()"}}},
+		}}, nil
+	}
+	var stdout bytes.Buffer
+	stdoutWriter = &stdout
+
+	if err := runAPI([]string{"example.com/lib@latest"}); err != nil {
+		t.Fatalf("runAPI() error = %v", err)
+	}
+	if gotModule != "example.com/lib" || gotVersion != "latest" {
+		t.Errorf("snapshot of %s@%s", gotModule, gotVersion)
+	}
+	if !strings.Contains(stdout.String(), "  - Open func()\n") {
+		t.Errorf("unexpected output:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := runAPI([]string{"-json", "example.com/lib@latest"}); err != nil {
+		t.Fatalf("runAPI(-json) error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), `"version": "v1.2.0"`) {
+		t.Errorf("unexpected JSON output:\n%s", stdout.String())
+	}
+
+	if err := runAPI(nil); err == nil {
+		t.Error("runAPI without a module should fail")
+	}
+	if err := runAPI([]string{"example.com/lib"}); err == nil {
+		t.Error("runAPI without a version should fail")
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+	"github.com/devblac/go-semver-audit/internal/report"
+)
+
+// defaultAttestationPath is where -sign writes the envelope unless -attestation is set
+const defaultAttestationPath = "go-semver-audit.intoto.json"
+
+// writeAttestation signs the JSON report and writes the envelope next to it
+func writeAttestation(cfg config, result *analyzer.Result, output string) error {
+	keyData, err := os.ReadFile(cfg.signKey)
+	if err != nil {
+		return fmt.Errorf("failed to read signing key: %w", err)
+	}
+	key, err := report.LoadSigningKey(keyData)
+	if err != nil {
+		return fmt.Errorf("failed to parse signing key %s: %w", cfg.signKey, err)
+	}
+	envelope, err := report.Attest(result, classifier(cfg), []byte(output), key)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(cfg.attestation, []byte(envelope), 0o644); err != nil {
+		return fmt.Errorf("failed to write attestation: %w", err)
+	}
+	if cfg.verbose {
+		fmt.Fprintf(stderrWriter, "Wrote attestation to %s\n", cfg.attestation)
+	}
+	return nil
+}
+
+// runVerifyAttestation implements `go-semver-audit verify-attestation`
+func runVerifyAttestation(args []string) error {
+	fs := flag.NewFlagSet("verify-attestation", flag.ContinueOnError)
+	fs.SetOutput(stderrWriter)
+	keyPath := fs.String("key", "", "PEM public key of the signer (required)")
+	reportPath := fs.String("report", "", "JSON report the attestation should cover (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *keyPath == "" || *reportPath == "" || fs.NArg() != 1 {
+		return fmt.Errorf("usage: go-semver-audit verify-attestation -key pub.pem -report report.json attestation.json")
+	}
+
+	keyData, err := os.ReadFile(*keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to read key: %w", err)
+	}
+	pub, err := report.LoadPublicKey(keyData)
+	if err != nil {
+		return fmt.Errorf("failed to parse key %s: %w", *keyPath, err)
+	}
+	reportData, err := os.ReadFile(*reportPath)
+	if err != nil {
+		return fmt.Errorf("failed to read report: %w", err)
+	}
+	envelope, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to read attestation: %w", err)
+	}
+
+	stmt, err := report.VerifyAttestation(envelope, reportData, pub)
+	if err != nil {
+		return fmt.Errorf("attestation verification failed: %w", err)
+	}
+	p := stmt.Predicate
+	fmt.Fprintf(stdoutWriter, "Verified: %s %s -> %s, passed=%t breaking=%t (%d, %d accepted) incomplete=%t\n",
+		p.Module, p.OldVersion, p.NewVersion, p.Passed, p.Breaking, p.BreakingCount, p.AcceptedCount, p.Incomplete)
+	return nil
+}
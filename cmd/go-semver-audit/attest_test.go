@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestSignAndVerifyAttestation(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	dir := t.TempDir()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM := func(name, typ string, der []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	privDER, _ := x509.MarshalPKCS8PrivateKey(priv)
+	pubDER, _ := x509.MarshalPKIXPublicKey(pub)
+	keyPath := writePEM("key.pem", "PRIVATE KEY", privDER)
+	pubPath := writePEM("pub.pem", "PUBLIC KEY", pubDER)
+
+	var stdout bytes.Buffer
+	stdoutWriter = &stdout
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/lib", NewVersion: "v1.1.0"}, nil
+	}
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		return &stubAnalyzer{analyzeResult: &analyzer.Result{Module: "example.com/lib", NewVersion: "v1.1.0", Changes: &analyzer.Diff{}}}, nil
+	}
+
+	attestation := filepath.Join(dir, "audit.intoto.json")
+	cfg := config{projectPath: ".", upgrade: "example.com/lib@v1.1.0", jsonOutput: true, signKey: keyPath, attestation: attestation}
+	if err := run(cfg); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	reportPath := filepath.Join(dir, "report.json")
+	if err := os.WriteFile(reportPath, stdout.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout.Reset()
+	if err := runVerifyAttestation([]string{"-key", pubPath, "-report", reportPath, attestation}); err != nil {
+		t.Fatalf("runVerifyAttestation returned error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Verified: example.com/lib") {
+		t.Errorf("unexpected verify output %q", stdout.String())
+	}
+
+	// Any edit to the report invalidates the attestation
+	reportData, _ := os.ReadFile(reportPath)
+	if err := os.WriteFile(reportPath, append(reportData, ' '), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runVerifyAttestation([]string{"-key", pubPath, "-report", reportPath, attestation}); err == nil {
+		t.Error("runVerifyAttestation should reject a modified report")
+	}
+
+	cfg.jsonOutput = false
+	if err := run(cfg); err == nil || !strings.Contains(err.Error(), "-sign requires -json") {
+		t.Errorf("expected -sign without -json to fail, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+	"github.com/devblac/go-semver-audit/internal/report"
+)
+
+// Files of a -bundle archive; the HTML report is the entry point and links the rest
+const (
+	bundleIndex    = "index.html"
+	bundleJSON     = "report.json"
+	bundleSARIF    = "report.sarif"
+	bundleSnippets = "snippets.txt"
+	bundleGraph    = "imports.dot"
+)
+
+// bundleEntry is one file of a -bundle archive
+type bundleEntry struct {
+	name, content string
+}
+
+// writeBundle writes a compressed archive of every report on the result,
+// suitable for uploading as a single CI artifact
+func writeBundle(path string, result *analyzer.Result, groupBy string) error {
+	attachments := []string{bundleJSON, bundleSARIF, bundleSnippets}
+	if result.ImportGraph != nil {
+		attachments = append(attachments, bundleGraph)
+	}
+	index, err := formatHTMLFn(result, report.HTMLOptions{GroupBy: groupBy, Attachments: attachments})
+	if err != nil {
+		return fmt.Errorf("failed to generate bundle: %w", err)
+	}
+	jsonReport, err := formatJSONFn(result)
+	if err != nil {
+		return fmt.Errorf("failed to generate bundle: %w", err)
+	}
+	sarif, err := formatSARIFFn(result)
+	if err != nil {
+		return fmt.Errorf("failed to generate bundle: %w", err)
+	}
+	entries := []bundleEntry{
+		{bundleIndex, index},
+		{bundleJSON, jsonReport},
+		{bundleSARIF, sarif},
+		{bundleSnippets, report.FormatSnippets(result)},
+	}
+	if result.ImportGraph != nil {
+		entries = append(entries, bundleEntry{bundleGraph, report.FormatDOT(result)})
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for _, entry := range entries {
+		w, err := zw.Create(entry.name)
+		if err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+		if _, err := w.Write([]byte(entry.content)); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	fmt.Fprintf(stderrWriter, "Wrote report bundle to %s\n", path)
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+	"github.com/devblac/go-semver-audit/internal/report"
+)
+
+func TestRun_Bundle(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	var stdout, stderr bytes.Buffer
+	stdoutWriter = &stdout
+	stderrWriter = &stderr
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/mod"}, nil
+	}
+	var gotOpts analyzer.Options
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		gotOpts = opts
+		return &stubAnalyzer{analyzeResult: &analyzer.Result{Module: "example.com/mod", Changes: &analyzer.Diff{}}}, nil
+	}
+	var htmlOpts report.HTMLOptions
+	formatHTMLFn = func(res *analyzer.Result, opts report.HTMLOptions) (string, error) {
+		htmlOpts = opts
+		return "<html></html>\n", nil
+	}
+	formatJSONFn = func(res *analyzer.Result) (string, error) { return "{}\n", nil }
+	formatSARIFFn = func(res *analyzer.Result) (string, error) { return "{\"version\":\"2.1.0\"}\n", nil }
+	formatTextFn = func(res *analyzer.Result, opts report.TextOptions) (string, error) { return "text\n", nil }
+
+	path := filepath.Join(t.TempDir(), "audit.zip")
+	if err := run(config{projectPath: ".", upgrade: "example.com/mod@v1.0.0", bundle: path}); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if stdout.String() != "text\n" {
+		t.Errorf("stdout = %q, want the text report", stdout.String())
+	}
+	if !gotOpts.Provenance || !gotOpts.Directories {
+		t.Errorf("Provenance = %v, Directories = %v, want both for the bundled reports", gotOpts.Provenance, gotOpts.Directories)
+	}
+	if !strings.Contains(stderr.String(), "Wrote report bundle to "+path) {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+	if !reflect.DeepEqual(htmlOpts.Attachments, []string{"report.json", "report.sarif", "snippets.txt"}) {
+		t.Errorf("index attachments = %v", htmlOpts.Attachments)
+	}
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("failed to open bundle: %v", err)
+	}
+	defer zr.Close()
+	got := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", f.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", f.Name, err)
+		}
+		got[f.Name] = string(data)
+	}
+	want := map[string]string{
+		"index.html":   "<html></html>\n",
+		"report.json":  "{}\n",
+		"report.sarif": "{\"version\":\"2.1.0\"}\n",
+		"snippets.txt": "✓ No affected source locations.\n",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("bundle contents = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+	"github.com/devblac/go-semver-audit/internal/report"
+)
+
+// runCandidates audits the upgrade of one module to each version listed in
+// -candidates and prints a table comparing them. The comparison informs the
+// choice of target, so it never fails on breaking changes. Every candidate is
+// audited against the same project, so options that rewrite it or write a
+// single result are rejected.
+func runCandidates(cfg config, opts analyzer.Options) error {
+	switch {
+	case cfg.minVersion != "":
+		return fmt.Errorf("cannot combine -candidates with -require-at-least")
+	case cfg.reproduce != "":
+		return fmt.Errorf("cannot combine -candidates with -reproduce")
+	case cfg.fix, cfg.shims != "", cfg.patch != "":
+		return fmt.Errorf("cannot combine -candidates with -fix, -shims, or -patch")
+	case cfg.save != "":
+		return fmt.Errorf("cannot combine -candidates with -save")
+	case cfg.signKey != "":
+		return fmt.Errorf("cannot combine -candidates with -sign")
+	case strings.Contains(cfg.upgrade, "@"):
+		return fmt.Errorf("with -candidates, -upgrade takes the module only; list the versions in -candidates")
+	}
+	var versions []string
+	for _, v := range strings.Split(cfg.candidates, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			versions = append(versions, v)
+		}
+	}
+	if len(versions) == 0 {
+		return fmt.Errorf("-candidates lists no versions")
+	}
+
+	formats, err := outputFormats(cfg)
+	if err != nil {
+		return err
+	}
+	if len(formats) != 1 || (formats[0] != formatText && formats[0] != formatJSON) {
+		return fmt.Errorf("-candidates prints a text or JSON comparison only")
+	}
+
+	a, err := newClient(cfg, opts)
+	if err != nil {
+		return fmt.Errorf("failed to initialize analyzer: %w", err)
+	}
+	results := make([]*analyzer.Result, 0, len(versions))
+	for _, v := range versions {
+		upgrade, err := parseUpgradeFn(cfg.upgrade + "@" + v)
+		if err != nil {
+			return fmt.Errorf("invalid candidate %s: %w", v, err)
+		}
+		result, err := a.Analyze(upgrade)
+		if err != nil {
+			return fmt.Errorf("analysis of %s failed: %w", v, err)
+		}
+		results = append(results, result)
+	}
+
+	output := report.FormatCandidatesText(results)
+	if formats[0] == formatJSON {
+		output, err = report.FormatCandidatesJSON(results)
+		if err != nil {
+			return fmt.Errorf("failed to generate report: %w", err)
+		}
+	}
+	fmt.Fprint(stdoutWriter, output)
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+type candidatesAnalyzer struct {
+	stubAnalyzer
+	audited []string
+}
+
+func (c *candidatesAnalyzer) Analyze(u *analyzer.Upgrade) (*analyzer.Result, error) {
+	c.audited = append(c.audited, u.NewVersion)
+	return &analyzer.Result{Module: u.Module, OldVersion: "v1.0.0", NewVersion: u.NewVersion, Changes: &analyzer.Diff{}}, nil
+}
+
+func TestRun_Candidates(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	stub := &candidatesAnalyzer{}
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) { return stub, nil }
+	var stdout bytes.Buffer
+	stdoutWriter = &stdout
+
+	if err := run(config{upgrade: "example.com/lib", candidates: "v1.8.0, v1.9.0,v2.0.0"}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if got := strings.Join(stub.audited, ","); got != "v1.8.0,v1.9.0,v2.0.0" {
+		t.Errorf("audited %s", got)
+	}
+	if !strings.Contains(stdout.String(), "Recommended target: v2.0.0") {
+		t.Errorf("unexpected output:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := run(config{upgrade: "example.com/lib", candidates: "v1.8.0", jsonOutput: true}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), `"recommended": "v1.8.0"`) {
+		t.Errorf("unexpected JSON output:\n%s", stdout.String())
+	}
+
+	stub.audited = nil
+	for _, cfg := range []config{
+		{upgrade: "example.com/lib@v1.8.0", candidates: "v1.9.0"},
+		{upgrade: "example.com/lib", candidates: " , "},
+		{upgrade: "example.com/lib", candidates: "v1.9.0", htmlOutput: true},
+		{minVersion: "example.com/lib@v1.8.0", candidates: "v1.9.0"},
+		{upgrade: "example.com/lib", candidates: "v1.9.0", fix: true},
+		{upgrade: "example.com/lib", candidates: "v1.9.0", shims: "shims"},
+		{upgrade: "example.com/lib", candidates: "v1.9.0", fix: true, patch: "fix.patch"},
+		{upgrade: "example.com/lib", candidates: "v1.9.0", save: "result.json"},
+		{upgrade: "example.com/lib", candidates: "v1.9.0", jsonOutput: true, signKey: "key.pem"},
+	} {
+		if err := run(cfg); err == nil {
+			t.Errorf("run(%+v) should fail", cfg)
+		}
+	}
+	if len(stub.audited) != 0 {
+		t.Errorf("audited %v despite rejected options", stub.audited)
+	}
+}
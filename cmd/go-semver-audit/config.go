@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// fileConfig is the JSON file given with -config, for settings too
+// structured to pass as flags:
+//
+//	{"severities": {"interface_added_method": "error", "signature_param_rename": "info"}, "telemetry": "on"}
+type fileConfig struct {
+	// Severities remaps finding categories to error, warning, or info
+	Severities map[string]string `json:"severities"`
+	// Telemetry opts in to a local log of anonymous usage statistics with
+	// "on"; -telemetry wins
+	Telemetry string `json:"telemetry"`
+}
+
+// loadConfigFile reads and validates a -config file
+func loadConfigFile(path string) (*fileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	var fc fileConfig
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&fc); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if err := analyzer.ValidateSeverities(fc.Severities); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	return &fc, nil
+}
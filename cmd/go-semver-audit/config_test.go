@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestRun_ConfigSeverities(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "semver-audit.json")
+	if err := os.WriteFile(path, []byte(`{"severities": {"interface_added_method": "error", "signature_param_rename": "info"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/lib", NewVersion: "v1.1.0"}, nil
+	}
+	var gotOpts analyzer.Options
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		gotOpts = opts
+		return &stubAnalyzer{analyzeResult: &analyzer.Result{Module: "example.com/lib", Changes: &analyzer.Diff{}}}, nil
+	}
+	stdoutWriter = &strings.Builder{}
+
+	if err := run(config{upgrade: "example.com/lib@v1.1.0", configPath: path}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	want := map[string]string{"interface_added_method": "error", "signature_param_rename": "info"}
+	if !reflect.DeepEqual(gotOpts.Severities, want) {
+		t.Errorf("Severities = %v, want %v", gotOpts.Severities, want)
+	}
+
+	for content, wantErr := range map[string]string{
+		`{"severities": {"interface_added_method": "fatal"}}`: "invalid severity",
+		`{"severity": {}}`: "unknown field",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		err := run(config{upgrade: "example.com/lib@v1.1.0", configPath: path})
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("run() with %s error = %v, want %q", content, err, wantErr)
+		}
+	}
+}
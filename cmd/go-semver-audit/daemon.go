@@ -0,0 +1,378 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// socketEnv overrides the daemon socket path; setting it to "off" disables delegation
+const socketEnv = "GO_SEMVER_AUDIT_SOCKET"
+
+// daemonDialTimeout bounds how long the CLI waits before falling back to in-process analysis
+const daemonDialTimeout = 200 * time.Millisecond
+
+// daemonRequest is one call from the CLI to the daemon
+type daemonRequest struct {
+	Method  string            `json:"method"` // "analyze" or "unused"
+	Project string            `json:"project"`
+	Options analyzer.Options  `json:"options"`
+	Upgrade *analyzer.Upgrade `json:"upgrade,omitempty"`
+	Env     []string          `json:"env"` // the client's Go build variables, see isBuildEnv
+}
+
+// daemonResponse is the daemon's answer to a daemonRequest
+type daemonResponse struct {
+	Result *analyzer.Result `json:"result,omitempty"`
+	Unused []string         `json:"unused,omitempty"`
+	Error  string           `json:"error,omitempty"`
+}
+
+// Allow overriding in tests
+var daemonAnalyzerFn = func(projectPath string, opts analyzer.Options) (analyzerClient, error) {
+	return analyzer.NewWithOptions(projectPath, opts)
+}
+
+// defaultSocketPath returns where the daemon listens unless told otherwise:
+// in $XDG_RUNTIME_DIR, or else in a directory of the temporary directory
+// that only the current user may use
+func defaultSocketPath() string {
+	if path := os.Getenv(socketEnv); path != "" {
+		return path
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "go-semver-audit.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("go-semver-audit-%d", os.Getuid()), "daemon.sock")
+}
+
+// checkSocketPath reports an error unless the socket and the directory
+// holding it belong to the current user and no one else may write to them,
+// so that neither the daemon nor its clients talk to another user's process
+func checkSocketPath(socket string) error {
+	if err := checkPrivate(filepath.Dir(socket)); err != nil {
+		return err
+	}
+	if _, err := os.Lstat(socket); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return checkPrivate(socket)
+}
+
+// runDaemon implements `go-semver-audit daemon`
+func runDaemon(args []string) error {
+	fs := flag.NewFlagSet("daemon", flag.ContinueOnError)
+	fs.SetOutput(stderrWriter)
+	socket := fs.String("socket", defaultSocketPath(), "Unix socket to listen on")
+	pprofAddr := fs.String("pprof", "", "Serve net/http/pprof profiles on this address, such as localhost:6060")
+	heapDir := fs.String("heap-profiles", "", "Directory to write periodic heap profiles to, keeping the newest "+fmt.Sprint(maxHeapSnapshots))
+	heapInterval := fs.Duration("heap-interval", 10*time.Minute, "How often -heap-profiles writes a heap profile")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *heapDir != "" && *heapInterval <= 0 {
+		return fmt.Errorf("-heap-interval must be positive")
+	}
+
+	// The default directory in the temporary directory is created private
+	if err := os.Mkdir(filepath.Dir(*socket), 0o700); err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(*socket), err)
+	}
+	if err := checkSocketPath(*socket); err != nil {
+		return fmt.Errorf("refusing to listen on %s: %w", *socket, err)
+	}
+
+	// A socket left behind by a crashed daemon would make Listen fail
+	if conn, err := net.DialTimeout("unix", *socket, daemonDialTimeout); err == nil {
+		conn.Close()
+		return fmt.Errorf("a daemon is already listening on %s", *socket)
+	}
+	os.Remove(*socket)
+
+	ln, err := listenPrivate(*socket)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", *socket, err)
+	}
+	defer os.Remove(*socket)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-stop
+		ln.Close()
+	}()
+
+	if *pprofAddr != "" {
+		stopPprof, err := startPprof(*pprofAddr)
+		if err != nil {
+			ln.Close()
+			return err
+		}
+		defer stopPprof()
+	}
+	if *heapDir != "" {
+		stopSnapshots := make(chan struct{})
+		defer close(stopSnapshots)
+		go writeHeapSnapshots(*heapDir, *heapInterval, stopSnapshots)
+	}
+
+	fmt.Fprintf(stderrWriter, "go-semver-audit daemon listening on %s\n", *socket)
+	return serveDaemon(ln, newDaemonState())
+}
+
+// daemonState is shared by the requests a daemon serves. Each request runs in
+// its client's environment, so requests run one at a time, and requests made
+// with the same Go settings share a cache.
+type daemonState struct {
+	mu     sync.Mutex
+	caches map[string]*analyzer.Cache
+}
+
+func newDaemonState() *daemonState {
+	return &daemonState{caches: make(map[string]*analyzer.Cache)}
+}
+
+// serve runs a request in its client's environment
+func (s *daemonState) serve(req daemonRequest) daemonResponse {
+	if req.Env == nil {
+		return daemonResponse{Error: "request does not carry the client's environment"}
+	}
+	for _, kv := range req.Env {
+		if name, _, _ := strings.Cut(kv, "="); !isBuildEnv(name) {
+			return daemonResponse{Error: fmt.Sprintf("request sets %s, which is not a Go build variable", name)}
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	restore := setEnviron(req.Env)
+	defer restore()
+
+	key := goEnvKey(req.Env)
+	cache := s.caches[key]
+	if cache == nil {
+		cache = analyzer.NewCache()
+		s.caches[key] = cache
+	}
+	return serveDaemonRequest(req, cache)
+}
+
+// buildEnvVars are the variables besides GO* and CGO_* that change how the
+// go command finds, downloads, and builds packages
+var buildEnvVars = map[string]bool{
+	"PATH": true, "HOME": true, "XDG_CONFIG_HOME": true, "XDG_CACHE_HOME": true, "CC": true, "CXX": true,
+}
+
+// isBuildEnv reports whether a variable is one of the Go build variables a
+// client sends the daemon. Nothing else of its environment, such as tokens,
+// leaves the client.
+func isBuildEnv(name string) bool {
+	return (strings.HasPrefix(name, "GO") && name != socketEnv) || strings.HasPrefix(name, "CGO_") || buildEnvVars[name]
+}
+
+// buildEnviron returns the Go build variables of env
+func buildEnviron(env []string) []string {
+	kept := []string{}
+	for _, kv := range env {
+		if name, _, _ := strings.Cut(kv, "="); isBuildEnv(name) {
+			kept = append(kept, kv)
+		}
+	}
+	return kept
+}
+
+// goEnvKey identifies the Go build variables of an environment
+func goEnvKey(env []string) string {
+	kept := buildEnviron(env)
+	sort.Strings(kept)
+	return strings.Join(kept, "\x00")
+}
+
+// setEnviron replaces the Go build variables of the process environment with
+// env and returns a function restoring the previous ones
+func setEnviron(env []string) (restore func()) {
+	old := buildEnviron(os.Environ())
+	apply := func(env []string) {
+		for _, kv := range buildEnviron(os.Environ()) {
+			name, _, _ := strings.Cut(kv, "=")
+			os.Unsetenv(name)
+		}
+		for _, kv := range env {
+			if name, value, ok := strings.Cut(kv, "="); ok {
+				os.Setenv(name, value)
+			}
+		}
+	}
+	apply(env)
+	return func() { apply(old) }
+}
+
+// serveDaemon answers requests until the listener is closed. Every request gets
+// a fresh analyzer.
+func serveDaemon(ln net.Listener, state *daemonState) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go handleDaemonConn(conn, state)
+	}
+}
+
+// handleDaemonConn serves a single request on conn
+func handleDaemonConn(conn net.Conn, state *daemonState) {
+	defer conn.Close()
+
+	var req daemonRequest
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		json.NewEncoder(conn).Encode(daemonResponse{Error: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+
+	json.NewEncoder(conn).Encode(state.serve(req))
+}
+
+// serveDaemonRequest runs the analysis a request asks for. Options that write
+// files or run programs are refused whatever the client, since they would act
+// as the daemon's process.
+func serveDaemonRequest(req daemonRequest, cache *analyzer.Cache) daemonResponse {
+	if inProcessOnly(req.Options) {
+		return daemonResponse{Error: "the daemon does not serve options that write files or run programs"}
+	}
+	opts := req.Options
+	opts.Cache = cache
+	a, err := daemonAnalyzerFn(req.Project, opts)
+	if err != nil {
+		return daemonResponse{Error: err.Error()}
+	}
+
+	switch req.Method {
+	case "analyze":
+		if req.Upgrade == nil {
+			return daemonResponse{Error: "analyze requires an upgrade"}
+		}
+		result, err := a.Analyze(req.Upgrade)
+		if err != nil {
+			return daemonResponse{Error: err.Error()}
+		}
+		return daemonResponse{Result: result}
+	case "unused":
+		unused, err := a.FindUnusedDependencies()
+		if err != nil {
+			return daemonResponse{Error: err.Error()}
+		}
+		return daemonResponse{Unused: unused}
+	default:
+		return daemonResponse{Error: fmt.Sprintf("unknown method %q", req.Method)}
+	}
+}
+
+// newClient returns the analyzer for a run: with -daemon, a running daemon
+// when there is one and the options are safe to delegate, otherwise an
+// in-process analyzer
+func newClient(cfg config, opts analyzer.Options) (analyzerClient, error) {
+	if cfg.daemon && !inProcessOnly(opts) {
+		if c := dialDaemonFn(defaultSocketPath(), cfg.projectPath, opts); c != nil {
+			return c, nil
+		}
+	}
+	return newAnalyzerFn(cfg.projectPath, opts)
+}
+
+// inProcessOnly reports whether options write to the project or run programs,
+// which must happen in the caller's process rather than the daemon's
+func inProcessOnly(opts analyzer.Options) bool {
+	return opts.Fix || opts.Shims != "" || opts.BinaryImpact != "" || opts.Bench != "" || opts.RunTests ||
+		opts.PackagesDriver != "" || opts.ModFlag == analyzer.ModMod
+}
+
+// daemonClient forwards analyses to a running daemon
+type daemonClient struct {
+	socket  string
+	project string
+	opts    analyzer.Options
+}
+
+// dialDaemon returns a client for the daemon at socket, or nil if none is
+// running or the socket could belong to another user
+func dialDaemon(socket, projectPath string, opts analyzer.Options) analyzerClient {
+	if socket == "off" {
+		return nil
+	}
+	if err := checkSocketPath(socket); err != nil {
+		fmt.Fprintf(stderrWriter, "Warning: not using the daemon at %s: %v\n", socket, err)
+		return nil
+	}
+	conn, err := net.DialTimeout("unix", socket, daemonDialTimeout)
+	if err != nil {
+		return nil
+	}
+	conn.Close()
+
+	abs, err := filepath.Abs(projectPath)
+	if err != nil {
+		return nil
+	}
+	return &daemonClient{socket: socket, project: abs, opts: opts}
+}
+
+func (c *daemonClient) Analyze(upgrade *analyzer.Upgrade) (*analyzer.Result, error) {
+	resp, err := c.call(daemonRequest{Method: "analyze", Upgrade: upgrade})
+	if err != nil {
+		return nil, err
+	}
+	// The daemon resolves the current version; keep the caller's upgrade in sync
+	if resp.Result != nil {
+		upgrade.OldVersion = resp.Result.OldVersion
+	}
+	return resp.Result, nil
+}
+
+func (c *daemonClient) FindUnusedDependencies() ([]string, error) {
+	resp, err := c.call(daemonRequest{Method: "unused"})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Unused, nil
+}
+
+// call sends one request and waits for the response
+func (c *daemonClient) call(req daemonRequest) (*daemonResponse, error) {
+	req.Project = c.project
+	req.Options = c.opts
+	req.Env = buildEnviron(os.Environ())
+
+	conn, err := net.Dial("unix", c.socket)
+	if err != nil {
+		return nil, fmt.Errorf("daemon unavailable: %w", err)
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("failed to send request to daemon: %w", err)
+	}
+	var resp daemonResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to read daemon response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
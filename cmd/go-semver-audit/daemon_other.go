@@ -0,0 +1,19 @@
+//go:build !unix
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+)
+
+// checkPrivate cannot check who owns a socket here, so the daemon is not used
+func checkPrivate(path string) error {
+	return fmt.Errorf("cannot check who owns %s on %s", path, runtime.GOOS)
+}
+
+// listenPrivate is not supported here
+func listenPrivate(socket string) (net.Listener, error) {
+	return nil, fmt.Errorf("the daemon is not supported on %s", runtime.GOOS)
+}
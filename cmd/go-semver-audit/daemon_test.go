@@ -0,0 +1,192 @@
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestDaemonRoundTrip(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the daemon is not supported on Windows")
+	}
+	dir, err := os.MkdirTemp("", "gsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "d.sock")
+
+	var gotOpts analyzer.Options
+	oldFn := daemonAnalyzerFn
+	defer func() { daemonAnalyzerFn = oldFn }()
+	daemonAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		gotOpts = opts
+		return &stubAnalyzer{
+			analyzeResult: &analyzer.Result{
+				Module:     "example.com/mod",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes: &analyzer.Diff{Removed: []analyzer.RemovedSymbol{
+					{Name: "Old", Type: "function", UsedIn: []analyzer.Location{{File: "main.go", Line: 3}}},
+				}},
+			},
+			unused: []string{"example.com/unused"},
+		}, nil
+	}
+
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- serveDaemon(ln, newDaemonState()) }()
+
+	client := dialDaemon(socket, ".", analyzer.Options{Footprint: true})
+	if client == nil {
+		t.Fatal("dialDaemon() = nil with a daemon listening")
+	}
+
+	upgrade := &analyzer.Upgrade{Module: "example.com/mod", NewVersion: "v2.0.0"}
+	result, err := client.Analyze(upgrade)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if !result.HasBreakingChanges() || result.Changes.Removed[0].UsedIn[0].Line != 3 {
+		t.Fatalf("Analyze() result = %+v", result)
+	}
+	if upgrade.OldVersion != "v1.0.0" {
+		t.Errorf("upgrade.OldVersion = %q, want v1.0.0", upgrade.OldVersion)
+	}
+	if !gotOpts.Footprint || gotOpts.Cache == nil {
+		t.Errorf("daemon options = %+v, want footprint and shared cache", gotOpts)
+	}
+
+	unused, err := client.FindUnusedDependencies()
+	if err != nil || len(unused) != 1 {
+		t.Fatalf("FindUnusedDependencies() = %v, %v", unused, err)
+	}
+
+	ln.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("serveDaemon() error = %v", err)
+	}
+}
+
+func TestDialDaemonWithoutDaemon(t *testing.T) {
+	if c := dialDaemon(filepath.Join(t.TempDir(), "missing.sock"), ".", analyzer.Options{}); c != nil {
+		t.Fatal("dialDaemon() returned a client without a daemon")
+	}
+	if c := dialDaemon("off", ".", analyzer.Options{}); c != nil {
+		t.Fatal("dialDaemon() returned a client when disabled")
+	}
+}
+
+func TestDaemonStateRunsInClientEnvironment(t *testing.T) {
+	t.Setenv("GOFLAGS", "-tags=daemon")
+
+	var gotFlags []string
+	var gotCaches []*analyzer.Cache
+	oldFn := daemonAnalyzerFn
+	defer func() { daemonAnalyzerFn = oldFn }()
+	daemonAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		gotFlags = append(gotFlags, os.Getenv("GOFLAGS"))
+		gotCaches = append(gotCaches, opts.Cache)
+		return &stubAnalyzer{}, nil
+	}
+
+	state := newDaemonState()
+	for _, env := range [][]string{{"GOFLAGS=-tags=a"}, {"GOFLAGS=-tags=b"}, {"GOFLAGS=-tags=a"}} {
+		if resp := state.serve(daemonRequest{Method: "unused", Env: env}); resp.Error != "" {
+			t.Fatalf("serve() error = %s", resp.Error)
+		}
+	}
+
+	if want := []string{"-tags=a", "-tags=b", "-tags=a"}; !reflect.DeepEqual(gotFlags, want) {
+		t.Errorf("GOFLAGS seen by the analyses = %v, want the clients' %v", gotFlags, want)
+	}
+	if gotCaches[0] != gotCaches[2] || gotCaches[0] == gotCaches[1] {
+		t.Error("caches are not shared by exactly the requests with the same Go settings")
+	}
+	if got := os.Getenv("GOFLAGS"); got != "-tags=daemon" {
+		t.Errorf("GOFLAGS after serving = %q, want the daemon's own", got)
+	}
+	if resp := state.serve(daemonRequest{Method: "unused"}); resp.Error == "" {
+		t.Error("expected an error for a request without the client's environment")
+	}
+	if resp := state.serve(daemonRequest{Method: "unused", Env: []string{"LD_PRELOAD=/tmp/x.so"}}); resp.Error == "" {
+		t.Error("expected an error for a request setting a variable other than a Go build variable")
+	}
+}
+
+func TestBuildEnviron(t *testing.T) {
+	env := []string{"GOFLAGS=-mod=mod", "CGO_ENABLED=0", "PATH=/usr/bin", "GITHUB_TOKEN=secret", "AWS_SECRET_ACCESS_KEY=x", socketEnv + "=/tmp/s"}
+	want := []string{"GOFLAGS=-mod=mod", "CGO_ENABLED=0", "PATH=/usr/bin"}
+	if got := buildEnviron(env); !reflect.DeepEqual(got, want) {
+		t.Errorf("buildEnviron() = %v, want %v", got, want)
+	}
+}
+
+func TestNewClientDelegation(t *testing.T) {
+	defer stubGlobals()()
+
+	dialed := 0
+	oldDial := dialDaemonFn
+	defer func() { dialDaemonFn = oldDial }()
+	dialDaemonFn = func(socket, projectPath string, opts analyzer.Options) analyzerClient {
+		dialed++
+		return &stubAnalyzer{}
+	}
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		return &stubAnalyzer{}, nil
+	}
+
+	tests := []struct {
+		name string
+		cfg  config
+		opts analyzer.Options
+		want int
+	}{
+		{"without -daemon", config{}, analyzer.Options{}, 0},
+		{"with -daemon", config{daemon: true}, analyzer.Options{Footprint: true}, 1},
+		{"fix stays in-process", config{daemon: true}, analyzer.Options{Fix: true}, 0},
+		{"tests stay in-process", config{daemon: true}, analyzer.Options{RunTests: true}, 0},
+		{"packages driver stays in-process", config{daemon: true}, analyzer.Options{PackagesDriver: "driver"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialed = 0
+			if _, err := newClient(tt.cfg, tt.opts); err != nil {
+				t.Fatalf("newClient() error = %v", err)
+			}
+			if dialed != tt.want {
+				t.Errorf("dialed the daemon %d time(s), want %d", dialed, tt.want)
+			}
+		})
+	}
+}
+
+func TestServeDaemonRequestErrors(t *testing.T) {
+	oldFn := daemonAnalyzerFn
+	defer func() { daemonAnalyzerFn = oldFn }()
+	daemonAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		return &stubAnalyzer{}, nil
+	}
+
+	if resp := serveDaemonRequest(daemonRequest{Method: "analyze"}, nil); resp.Error == "" {
+		t.Error("expected an error for analyze without upgrade")
+	}
+	if resp := serveDaemonRequest(daemonRequest{Method: "bogus"}, nil); resp.Error == "" {
+		t.Error("expected an error for an unknown method")
+	}
+	for _, opts := range []analyzer.Options{{Fix: true}, {Shims: "shims"}, {RunTests: true}, {PackagesDriver: "/tmp/driver"}, {ModFlag: analyzer.ModMod}} {
+		if resp := serveDaemonRequest(daemonRequest{Method: "unused", Options: opts}, nil); resp.Error == "" {
+			t.Errorf("expected an error for options %+v, which only run in-process", opts)
+		}
+	}
+}
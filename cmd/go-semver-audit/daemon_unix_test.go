@@ -0,0 +1,56 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckSocketPath(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	socket := filepath.Join(dir, "d.sock")
+	if err := checkSocketPath(socket); err != nil {
+		t.Errorf("checkSocketPath() error = %v for a private directory", err)
+	}
+
+	shared := filepath.Join(dir, "shared")
+	if err := os.Mkdir(shared, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(shared, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSocketPath(filepath.Join(shared, "d.sock")); err == nil || !strings.Contains(err.Error(), "writable by other users") {
+		t.Errorf("checkSocketPath() error = %v, want a world-writable directory refused", err)
+	}
+
+	link := filepath.Join(dir, "link.sock")
+	if err := os.Symlink(filepath.Join(shared, "d.sock"), link); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSocketPath(link); err == nil || !strings.Contains(err.Error(), "symbolic link") {
+		t.Errorf("checkSocketPath() error = %v, want a symbolic link refused", err)
+	}
+}
+
+func TestListenPrivate(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "d.sock")
+	ln, err := listenPrivate(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	info, err := os.Stat(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm&0o077 != 0 {
+		t.Errorf("socket mode = %v, want no access for group or others", perm)
+	}
+}
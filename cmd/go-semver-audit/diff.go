@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+	"github.com/devblac/go-semver-audit/internal/report"
+)
+
+// readSnapshotFn reads an archived API snapshot
+var readSnapshotFn = analyzer.ReadSnapshot
+
+// runDiff implements `go-semver-audit diff`. It exits non-zero when the
+// newer snapshot breaks the older one.
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(stderrWriter)
+	jsonOutput := fs.Bool("json", false, "Output results as JSON")
+	verbose := fs.Bool("v", false, "Verbose output")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: go-semver-audit diff [-json] [-v] old.json new.json")
+	}
+
+	oldSnap, err := readSnapshotFn(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	newSnap, err := readSnapshotFn(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	result := analyzer.DiffSnapshots(oldSnap, newSnap)
+
+	var output string
+	if *jsonOutput {
+		output, err = report.FormatJSON(result)
+	} else {
+		output, err = report.FormatText(result, *verbose)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+	fmt.Fprint(stdoutWriter, output)
+
+	if code := determineExitCode(result, analyzer.Classifier{MaxAffected: noAffectedLimit}); code != 0 {
+		exitFunc(code)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestRunDiff(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+	oldRead := readSnapshotFn
+	defer func() { readSnapshotFn = oldRead }()
+
+	snapshots := map[string]*analyzer.Snapshot{
+		"v1.json": {Module: "example.com/lib", Version: "v1.0.0", API: &analyzer.API{
+			Funcs: map[string]*analyzer.Function{"Open": {Name: "Open", Signature: analyzer.Signature{Text: "func()"}}},
+		}},
+		"v2.json": {Module: "example.com/lib", Version: "v2.0.0", API: &analyzer.API{}},
+	}
+	readSnapshotFn = func(path string) (*analyzer.Snapshot, error) {
+		if snap, ok := snapshots[path]; ok {
+			return snap, nil
+		}
+		return nil, fmt.Errorf("no snapshot %s", path)
+	}
+	var stdout bytes.Buffer
+	stdoutWriter = &stdout
+	exitCode := 0
+	exitFunc = func(code int) { exitCode = code }
+
+	if err := runDiff([]string{"v1.json", "v2.json"}); err != nil {
+		t.Fatalf("runDiff() error = %v", err)
+	}
+	if exitCode != 1 {
+		t.Errorf("exit code = %d, want 1 for a breaking change", exitCode)
+	}
+	for _, want := range []string{"Analyzing upgrade: example.com/lib v1.0.0 -> v2.0.0", "  - Open (function)\n"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, stdout.String())
+		}
+	}
+
+	stdout.Reset()
+	exitCode = 0
+	if err := runDiff([]string{"-json", "v1.json", "v1.json"}); err != nil {
+		t.Fatalf("runDiff(-json) error = %v", err)
+	}
+	if exitCode != 0 || !strings.Contains(stdout.String(), `"module": "example.com/lib"`) {
+		t.Errorf("exit code %d, output:\n%s", exitCode, stdout.String())
+	}
+
+	if err := runDiff([]string{"v1.json"}); err == nil {
+		t.Error("runDiff with one snapshot should fail")
+	}
+	if err := runDiff([]string{"v1.json", "v3.json"}); err == nil {
+		t.Error("runDiff with a missing snapshot should fail")
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+	"github.com/devblac/go-semver-audit/internal/report"
+)
+
+// Report formats accepted by -format
+const (
+	formatText  = "text"
+	formatJSON  = "json"
+	formatHTML  = "html"
+	formatLSP   = "lsp"
+	formatSARIF = "sarif"
+)
+
+// defaultOutputBase is where -format writes its reports when several are requested
+const defaultOutputBase = "semver-audit"
+
+// formatExtensions name the file each format is written to when a run
+// produces several reports
+var formatExtensions = map[string]string{
+	formatText:  ".txt",
+	formatJSON:  ".json",
+	formatHTML:  ".html",
+	formatLSP:   ".lsp.json",
+	formatSARIF: ".sarif",
+}
+
+// outputFormats resolves the reports a run produces from -format, or from
+// the single-format -json, -html, and -lsp flags
+func outputFormats(cfg config) ([]string, error) {
+	if cfg.format == "" {
+		switch {
+		case cfg.jsonOutput && cfg.htmlOutput:
+			return nil, fmt.Errorf("cannot use -json and -html together")
+		case cfg.lspOutput && (cfg.jsonOutput || cfg.htmlOutput):
+			return nil, fmt.Errorf("cannot combine -lsp with -json or -html")
+		case cfg.jsonOutput:
+			return []string{formatJSON}, nil
+		case cfg.htmlOutput:
+			return []string{formatHTML}, nil
+		case cfg.lspOutput:
+			return []string{formatLSP}, nil
+		default:
+			return []string{formatText}, nil
+		}
+	}
+
+	if cfg.jsonOutput || cfg.htmlOutput || cfg.lspOutput {
+		return nil, fmt.Errorf("cannot combine -format with -json, -html, or -lsp")
+	}
+	var formats []string
+	seen := make(map[string]bool)
+	for _, f := range strings.Split(cfg.format, ",") {
+		f = strings.TrimSpace(f)
+		if _, ok := formatExtensions[f]; !ok {
+			return nil, fmt.Errorf("-format: unknown format %q (expected text, json, html, lsp, or sarif)", f)
+		}
+		if !seen[f] {
+			seen[f] = true
+			formats = append(formats, f)
+		}
+	}
+	return formats, nil
+}
+
+// hasFormat reports whether formats includes format
+func hasFormat(formats []string, format string) bool {
+	for _, f := range formats {
+		if strings.TrimSpace(f) == format {
+			return true
+		}
+	}
+	return false
+}
+
+// renderReport formats the result in one output format
+func renderReport(format string, result *analyzer.Result, textOpts report.TextOptions) (string, error) {
+	switch format {
+	case formatJSON:
+		return formatJSONFn(result)
+	case formatHTML:
+		return formatHTMLFn(result, report.HTMLOptions{GroupBy: textOpts.GroupBy})
+	case formatLSP:
+		return formatLSPFn(result)
+	case formatSARIF:
+		return formatSARIFFn(result)
+	default:
+		return formatTextFn(result, textOpts)
+	}
+}
+
+// writeReports writes each report to its own file next to base, so one
+// analysis can feed several consumers
+func writeReports(base string, formats []string, outputs map[string]string) error {
+	for _, format := range formats {
+		path := base + formatExtensions[format]
+		if err := os.WriteFile(path, []byte(outputs[format]), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s report: %w", format, err)
+		}
+		fmt.Fprintf(stderrWriter, "Wrote %s report to %s\n", format, path)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+	"github.com/devblac/go-semver-audit/internal/report"
+)
+
+func TestOutputFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config
+		want    []string
+		wantErr string
+	}{
+		{name: "default", cfg: config{}, want: []string{"text"}},
+		{name: "json flag", cfg: config{jsonOutput: true}, want: []string{"json"}},
+		{name: "list", cfg: config{format: "json, html,text,json"}, want: []string{"json", "html", "text"}},
+		{name: "sarif", cfg: config{format: "sarif,json"}, want: []string{"sarif", "json"}},
+		{name: "unknown", cfg: config{format: "json,xml"}, wantErr: `unknown format "xml"`},
+		{name: "mixed with flag", cfg: config{format: "json", htmlOutput: true}, wantErr: "cannot combine -format"},
+		{name: "conflicting flags", cfg: config{jsonOutput: true, htmlOutput: true}, wantErr: "cannot use -json and -html together"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := outputFormats(tt.cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("outputFormats() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("outputFormats() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_MultipleFormats(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	var stdout, stderr bytes.Buffer
+	stdoutWriter = &stdout
+	stderrWriter = &stderr
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/mod"}, nil
+	}
+	jsonRenders := 0
+	var gotOpts analyzer.Options
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		gotOpts = opts
+		return &stubAnalyzer{analyzeResult: &analyzer.Result{Module: "example.com/mod", Changes: &analyzer.Diff{}}}, nil
+	}
+	formatJSONFn = func(res *analyzer.Result) (string, error) {
+		jsonRenders++
+		return "{}\n", nil
+	}
+	formatHTMLFn = func(res *analyzer.Result, opts report.HTMLOptions) (string, error) { return "<html></html>\n", nil }
+	formatTextFn = func(res *analyzer.Result, opts report.TextOptions) (string, error) { return "text\n", nil }
+
+	base := filepath.Join(t.TempDir(), "audit")
+	if err := run(config{projectPath: ".", upgrade: "example.com/mod@v1.0.0", format: "json, html, text", outputBase: base}); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("expected reports in files only, got stdout %q", stdout.String())
+	}
+	if !gotOpts.Provenance || !gotOpts.Directories || jsonRenders != 1 {
+		t.Errorf("Provenance = %v, Directories = %v, JSON rendered %d time(s)", gotOpts.Provenance, gotOpts.Directories, jsonRenders)
+	}
+	for ext, want := range map[string]string{".json": "{}\n", ".html": "<html></html>\n", ".txt": "text\n"} {
+		data, err := os.ReadFile(base + ext)
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v, want %q", base+ext, data, err, want)
+		}
+	}
+	if !strings.Contains(stderr.String(), "Wrote html report to "+base+".html") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+
+	// A single format still goes to stdout
+	stdout.Reset()
+	if err := run(config{projectPath: ".", upgrade: "example.com/mod@v1.0.0", format: "html", outputBase: base}); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if stdout.String() != "<html></html>\n" {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
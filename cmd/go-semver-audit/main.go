@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/devblac/go-semver-audit/internal/analyzer"
 	"github.com/devblac/go-semver-audit/internal/report"
+	"github.com/devblac/go-semver-audit/internal/telemetry"
 )
 
 const version = "0.1.0"
@@ -15,12 +19,65 @@ const version = "0.1.0"
 type config struct {
 	projectPath string
 	upgrade     string
+	minVersion  string
+	modules     string
+	pkgDriver   string
+	modFlag     string
+	pprof       string
+	allowErrors bool
 	jsonOutput  bool
 	htmlOutput  bool
+	lspOutput   bool
 	strict      bool
 	unused      bool
 	verbose     bool
 	showVersion bool
+	newDep      bool
+	from        string
+	candidates  string
+	ignoreRepl  bool
+	footprint   bool
+	sumdb       bool
+	binImpact   string
+	bench       string
+	runTests    bool
+	docs        bool
+	examples    bool
+	hints       bool
+	todos       bool
+	deadCode    bool
+	failFast    bool
+	maxAffected int
+	adapterMin  int
+	groupBy     string
+	includeTest bool
+	topFixes    int
+	maxFindings int
+	width       int
+	color       string
+	shims       string
+	fix         bool
+	fixContext  string
+	fixPolicy   string
+	allowDirty  bool
+	dryRun      bool
+	patch       string
+	shards      int
+	daemon      bool
+	reproduce   string
+	signKey     string
+	attestation string
+	history     string
+	configPath  string
+	format      string
+	outputBase  string
+	bundle      string
+	graph       string
+	save        string
+	telemetry   string
+	platforms   string
+	platformSet []string
+	severities  map[string]string
 }
 
 // Allow dependency injection for testing.
@@ -31,18 +88,99 @@ type analyzerClient interface {
 
 var (
 	parseUpgradeFn = analyzer.ParseUpgrade
-	newAnalyzerFn  = func(projectPath string) (analyzerClient, error) {
-		return analyzer.New(projectPath)
-	}
-	formatJSONFn           = report.FormatJSON
-	formatHTMLFn           = report.FormatHTML
-	formatTextFn           = report.FormatText
-	exitFunc               = os.Exit
-	stdoutWriter io.Writer = os.Stdout
-	stderrWriter io.Writer = os.Stderr
+	newAnalyzerFn  = func(projectPath string, opts analyzer.Options) (analyzerClient, error) {
+		return analyzer.NewWithOptions(projectPath, opts)
+	}
+	dialDaemonFn       = dialDaemon
+	recordProvenanceFn = func(projectPath string, opts analyzer.Options, upgrade *analyzer.Upgrade) (*analyzer.Provenance, error) {
+		a, err := analyzer.NewWithOptions(projectPath, opts)
+		if err != nil {
+			return nil, err
+		}
+		return a.RecordProvenance(upgrade)
+	}
+	formatJSONFn            = report.FormatJSON
+	formatHTMLFn            = report.FormatHTMLWithOptions
+	formatLSPFn             = report.FormatLSP
+	formatSARIFFn           = report.FormatSARIF
+	formatTextFn            = report.FormatTextWithOptions
+	exitFunc                = os.Exit
+	stdoutWriter  io.Writer = os.Stdout
+	stderrWriter  io.Writer = os.Stderr
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "daemon":
+			if err := runDaemon(os.Args[2:]); err != nil {
+				fmt.Fprintf(stderrWriter, "Error: %v\n", err)
+				exitFunc(1)
+			}
+			return
+		case "renovate-config":
+			if err := runRenovateConfig(os.Args[2:]); err != nil {
+				fmt.Fprintf(stderrWriter, "Error: %v\n", err)
+				exitFunc(1)
+			}
+			return
+		case "replaces":
+			if err := runReplaces(os.Args[2:]); err != nil {
+				fmt.Fprintf(stderrWriter, "Error: %v\n", err)
+				exitFunc(1)
+			}
+			return
+		case "api":
+			if err := runAPI(os.Args[2:]); err != nil {
+				fmt.Fprintf(stderrWriter, "Error: %v\n", err)
+				exitFunc(1)
+			}
+			return
+		case "diff":
+			if err := runDiff(os.Args[2:]); err != nil {
+				fmt.Fprintf(stderrWriter, "Error: %v\n", err)
+				exitFunc(1)
+			}
+			return
+		case "merge":
+			if err := runMerge(os.Args[2:]); err != nil {
+				fmt.Fprintf(stderrWriter, "Error: %v\n", err)
+				exitFunc(1)
+			}
+			return
+		case "report":
+			if err := runReport(os.Args[2:]); err != nil {
+				fmt.Fprintf(stderrWriter, "Error: %v\n", err)
+				exitFunc(1)
+			}
+			return
+		case "uses":
+			if err := runUses(os.Args[2:]); err != nil {
+				fmt.Fprintf(stderrWriter, "Error: %v\n", err)
+				exitFunc(1)
+			}
+			return
+		case "verify":
+			if err := runVerify(os.Args[2:]); err != nil {
+				fmt.Fprintf(stderrWriter, "Error: %v\n", err)
+				exitFunc(1)
+			}
+			return
+		case "verify-attestation":
+			if err := runVerifyAttestation(os.Args[2:]); err != nil {
+				fmt.Fprintf(stderrWriter, "Error: %v\n", err)
+				exitFunc(1)
+			}
+			return
+		case analyzer.ShardCommand:
+			if err := runShardWorker(os.Stdin, stdoutWriter); err != nil {
+				fmt.Fprintf(stderrWriter, "Error: %v\n", err)
+				exitFunc(1)
+			}
+			return
+		}
+	}
+
 	cfg := parseFlags()
 
 	if cfg.showVersion {
@@ -51,7 +189,7 @@ func main() {
 		return
 	}
 
-	if cfg.upgrade == "" {
+	if cfg.upgrade == "" && cfg.minVersion == "" && cfg.reproduce == "" {
 		fmt.Fprintln(stderrWriter, "Error: -upgrade flag is required")
 		fmt.Fprintln(stderrWriter, "Usage: go-semver-audit -upgrade module@version [options]")
 		flag.Usage()
@@ -70,22 +208,85 @@ func parseFlags() config {
 	cfg := config{}
 
 	flag.StringVar(&cfg.projectPath, "path", ".", "Path to Go project to analyze")
-	flag.StringVar(&cfg.upgrade, "upgrade", "", "Dependency upgrade in format module@version (required)")
+	flag.StringVar(&cfg.upgrade, "upgrade", "", "Dependency upgrade in format module@version, or go@1.N for a toolchain upgrade (required)")
+	flag.StringVar(&cfg.minVersion, "require-at-least", "", "Check that the project requires module@version or newer, auditing the upgrade to it when behind (replaces -upgrade)")
+	flag.StringVar(&cfg.modules, "modules", "", "Dependency versions for projects without a go.mod (Bazel): a MODULE.bazel, go_deps.bzl, or file of \"module version\" lines")
+	flag.StringVar(&cfg.pkgDriver, "packages-driver", os.Getenv("GOPACKAGESDRIVER"), "GOPACKAGESDRIVER binary used to load the project's packages, such as the rules_go driver (defaults to $GOPACKAGESDRIVER)")
+	flag.StringVar(&cfg.modFlag, "mod", "", "-mod mode for loading module versions: readonly or mod (defaults to the -mod in $GOFLAGS, or readonly); other GOFLAGS are kept")
+	flag.BoolVar(&cfg.allowErrors, "allow-errors", false, "Analyze a project that does not compile; findings in packages with errors are marked approximate")
 	flag.BoolVar(&cfg.jsonOutput, "json", false, "Output results as JSON")
 	flag.BoolVar(&cfg.htmlOutput, "html", false, "Output results as HTML")
+	flag.BoolVar(&cfg.lspOutput, "lsp", false, "Output findings as LSP publishDiagnostics JSON for editor integrations")
+	flag.StringVar(&cfg.format, "format", "", "Comma-separated report formats (text, json, html, lsp, sarif); several formats are written to files named by -output")
+	flag.StringVar(&cfg.outputBase, "output", defaultOutputBase, "File name without extension for the reports of a multi-format run")
+	flag.StringVar(&cfg.save, "save", "", "Also save the full analysis result as JSON, to render other report formats later with the report subcommand")
+	flag.StringVar(&cfg.bundle, "bundle", "", "Also write a zip with HTML (index.html), JSON, and SARIF reports and source snippets, for upload as a CI artifact")
 	flag.BoolVar(&cfg.strict, "strict", false, "Exit non-zero on warnings (not just errors)")
 	flag.BoolVar(&cfg.unused, "unused", false, "Report unused dependencies after upgrade")
 	flag.BoolVar(&cfg.verbose, "v", false, "Verbose output")
 	flag.BoolVar(&cfg.showVersion, "version", false, "Show version information")
+	flag.BoolVar(&cfg.newDep, "new", false, "Allow auditing a module the project does not require yet")
+	flag.StringVar(&cfg.candidates, "candidates", "", "Comma-separated versions to audit the module given by -upgrade against, printing a table comparing their breaking changes and effort")
+	flag.StringVar(&cfg.from, "from", "", "Version to diff the upgrade from, such as the last tagged release when go.mod pins a pseudo-version; takes precedence over go.mod and its replace directives")
+	flag.BoolVar(&cfg.ignoreRepl, "ignore-replace", false, "Diff the required version even if go.mod replaces the module")
+	flag.BoolVar(&cfg.footprint, "footprint", false, "Report download size, package, and module graph changes, including modules you require that the upgrade raises")
+	flag.IntVar(&cfg.adapterMin, "adapter-threshold", analyzer.DefaultAdapterThreshold, "Suggest an internal adapter package when more than N project packages use the dependency (0 disables the advisory)")
+	flag.BoolVar(&cfg.sumdb, "sumdb", false, "Report whether the new version was verified against the checksum database or fetched with GOSUMDB, GONOSUMDB, or GOPRIVATE bypassing it")
+	flag.StringVar(&cfg.binImpact, "binary-impact", "", "Main package to build before and after the upgrade to report binary size change")
+	flag.StringVar(&cfg.bench, "bench", "", "Package pattern whose benchmarks are compared before and after the upgrade")
+	flag.BoolVar(&cfg.runTests, "run-tests", false, "Run the project's tests against the upgrade and report new failures")
+	flag.StringVar(&cfg.groupBy, "group-by", report.GroupBySymbol, "Group text and HTML findings by symbol, file, or package")
+	flag.IntVar(&cfg.maxAffected, "max-affected", noAffectedLimit, "Only fail on breaking changes when more than N locations are affected (-1 fails on any)")
+	flag.BoolVar(&cfg.failFast, "fail-fast", false, "Stop at the first used breaking change and print a minimal report, skipping optional checks")
+	flag.BoolVar(&cfg.examples, "examples", false, "Embed usage examples from the new version for changed, moved, and removed symbols")
+	flag.StringVar(&cfg.graph, "graph", "", "Write a Graphviz DOT graph of the project packages importing the module, colored by finding severity (also embedded in HTML reports)")
+	flag.BoolVar(&cfg.hints, "hints", false, "Suggest functions added in the new version that may replace project code")
+	flag.BoolVar(&cfg.todos, "todos", false, "Report TODO and FIXME comments mentioning the dependency that symbols added in the new version may address")
+	flag.BoolVar(&cfg.deadCode, "dead-code", false, "Downgrade findings only used in code unreachable from main, init, and tests to warnings, and suggest deleting that code")
+	flag.BoolVar(&cfg.docs, "docs", false, "Report deprecations and changed error or panic wording in the docs of used symbols")
+	flag.StringVar(&cfg.platforms, "platforms", "", "Comma-separated GOOS/GOARCH pairs to diff the dependency's API for, such as linux/amd64,windows/amd64; findings limited to some platforms name them")
+	flag.BoolVar(&cfg.includeTest, "include-test-pkgs", false, "Include the dependency's test, example, and testdata packages in the diff")
+	flag.StringVar(&cfg.shims, "shims", "", "Project directory to write semver_audit_shims.go with adapters keeping the old signatures of changed functions")
+	flag.BoolVar(&cfg.fix, "fix", false, "Rewrite call sites of changed functions that can be migrated mechanically: functions that gained a leading context.Context or a new error result, and stub methods that implemented interfaces gained")
+	flag.StringVar(&cfg.fixContext, "fix-context", analyzer.FixContextTODO, "Context -fix passes to functions that gained a leading context.Context: todo or background")
+	flag.StringVar(&cfg.fixPolicy, "fix-policy", analyzer.FixPolicyTODO, "How -fix receives a newly returned error: todo (assign to err and check it under a TODO) or discard (assign to _)")
+	flag.BoolVar(&cfg.dryRun, "dry-run", false, "With -fix or -shims, print unified diffs of the proposed edits instead of writing them")
+	flag.StringVar(&cfg.patch, "patch", "", "With -fix or -shims, write the proposed edits and shim file to this patch file instead of the project, to apply with git apply from the project directory")
+	flag.BoolVar(&cfg.allowDirty, "allow-dirty", false, "Let -fix and -shims edit a git tree with uncommitted changes")
+	flag.IntVar(&cfg.shards, "shards", 0, "Split loading the dependency's API across N worker processes (for very large modules)")
+	flag.BoolVar(&cfg.daemon, "daemon", false, "Delegate the audit to a running go-semver-audit daemon to reuse its warm caches; audits that write files or run programs stay in-process")
+	flag.StringVar(&cfg.reproduce, "reproduce", "", "Re-run the audit recorded in a JSON report's provenance block and fail if any input differs")
+	flag.StringVar(&cfg.signKey, "sign", "", "PEM Ed25519 or ECDSA private key used to sign an in-toto attestation of the JSON report (requires -json)")
+	flag.StringVar(&cfg.attestation, "attestation", defaultAttestationPath, "Where -sign writes the DSSE attestation envelope")
+	flag.StringVar(&cfg.configPath, "config", "", "JSON config file, e.g. with \"severities\" remapping finding categories to error, warning, or info")
+	flag.StringVar(&cfg.telemetry, "telemetry", "", "Opt in to a local log of anonymous usage statistics (counts and durations, no module names) with on; off disables it. Events are only sent anywhere when $"+telemetry.EnvEndpoint+" names an endpoint. $"+telemetry.EnvMode+"=off or $"+telemetry.EnvDoNotTrack+" always disables them")
+	flag.StringVar(&cfg.history, "history", "", "Directory of past JSON reports used to score the dependency's breaking-change history")
+	flag.IntVar(&cfg.topFixes, "top-fixes", report.DefaultTopFixes, "Number of most-used findings listed under \"What to fix next\" (0 hides the list)")
+	flag.IntVar(&cfg.maxFindings, "max-findings", 0, "Maximum findings listed per category in text output, with a note on how many were omitted (0 means unlimited)")
+	flag.StringVar(&cfg.color, "color", "auto", "Highlight signature diffs in text output: auto, always, or never")
+	flag.StringVar(&cfg.pprof, "pprof", "", "Serve net/http/pprof profiles on this address while auditing, such as localhost:6060, to diagnose memory use on large modules")
+	flag.IntVar(&cfg.width, "width", terminalWidth(), "Maximum text report width; long signatures are truncated, or wrapped with -v (0 means unlimited, defaults to $COLUMNS)")
 
 	flag.Usage = func() {
-		fmt.Fprintf(stderrWriter, "Usage: go-semver-audit [options]\n\n")
+		fmt.Fprintf(stderrWriter, "Usage: go-semver-audit [options]\n")
+		fmt.Fprintf(stderrWriter, "       go-semver-audit daemon [-socket path] [-pprof addr] [-heap-profiles dir]\n")
+		fmt.Fprintf(stderrWriter, "       go-semver-audit renovate-config report.json|dir...\n")
+		fmt.Fprintf(stderrWriter, "       go-semver-audit replaces [-path dir] [-json] [module@version...]\n")
+		fmt.Fprintf(stderrWriter, "       go-semver-audit uses [-path dir] [-json] example.com/lib.Symbol\n")
+		fmt.Fprintf(stderrWriter, "       go-semver-audit api [-json] module@version\n")
+		fmt.Fprintf(stderrWriter, "       go-semver-audit diff [-json] [-v] old.json new.json\n")
+		fmt.Fprintf(stderrWriter, "       go-semver-audit report [-format text] [-output base] [-v] -in result.json\n")
+		fmt.Fprintf(stderrWriter, "       go-semver-audit merge [-o combined.json] report.json|dir...\n")
+		fmt.Fprintf(stderrWriter, "       go-semver-audit verify [-path dir] [-json] -from vOld -upgrade module@vNew\n")
+		fmt.Fprintf(stderrWriter, "       go-semver-audit verify-attestation -key pub.pem -report report.json attestation.json\n\n")
 		fmt.Fprintf(stderrWriter, "Analyze breaking changes in Go dependency upgrades.\n\n")
 		fmt.Fprintf(stderrWriter, "Options:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(stderrWriter, "\nExample:\n")
 		fmt.Fprintf(stderrWriter, "  go-semver-audit -upgrade github.com/pkg/errors@v0.9.1\n")
 		fmt.Fprintf(stderrWriter, "  go-semver-audit -path ./myproject -upgrade github.com/gin-gonic/gin@v1.9.0 -json\n")
+		fmt.Fprintf(stderrWriter, "  go-semver-audit -require-at-least golang.org/x/net@v0.23.0\n")
+		fmt.Fprintf(stderrWriter, "\nWith -daemon, audits are delegated to a daemon of the same user, with only the Go build variables (GO*, CGO_*, PATH, HOME, CC, CXX) of this environment. Set %s to its socket, or to \"off\" to run in-process.\n", socketEnv)
 	}
 
 	flag.Parse()
@@ -93,12 +294,166 @@ func parseFlags() config {
 	return cfg
 }
 
+// terminalWidth reports the width advertised by the shell in $COLUMNS, or 0
+// when it is unset or invalid
+func terminalWidth() int {
+	width, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || width < 0 {
+		return 0
+	}
+	return width
+}
+
+// colorOutput resolves the -color mode. In auto mode, color is used only when
+// writing to a terminal and NO_COLOR is unset.
+func colorOutput(mode string, w io.Writer) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "", "auto":
+		if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+			return false, nil
+		}
+		f, ok := w.(*os.File)
+		if !ok {
+			return false, nil
+		}
+		info, err := f.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+	default:
+		return false, fmt.Errorf("-color must be auto, always, or never, got %q", mode)
+	}
+}
+
 func run(cfg config) error {
+	start := time.Now()
+
+	if cfg.pprof != "" {
+		stopPprof, err := startPprof(cfg.pprof)
+		if err != nil {
+			return err
+		}
+		defer stopPprof()
+	}
+
+	// A minimum version check audits the upgrade to the minimum
+	if cfg.minVersion != "" {
+		if cfg.upgrade != "" {
+			return fmt.Errorf("cannot combine -require-at-least with -upgrade")
+		}
+		if cfg.from != "" {
+			return fmt.Errorf("cannot combine -require-at-least with -from")
+		}
+		cfg.upgrade = cfg.minVersion
+	}
+
+	if cfg.configPath != "" {
+		fc, err := loadConfigFile(cfg.configPath)
+		if err != nil {
+			return err
+		}
+		cfg.severities = fc.Severities
+		if cfg.telemetry == "" {
+			cfg.telemetry = fc.Telemetry
+		}
+	}
+	telemetryOn, err := telemetry.Enabled(cfg.telemetry, os.Getenv)
+	if err != nil {
+		return fmt.Errorf("-telemetry: %w", err)
+	}
+
+	cfg.platformSet, err = analyzer.ParsePlatforms(cfg.platforms)
+	if err != nil {
+		return fmt.Errorf("-platforms: %w", err)
+	}
+
+	opts := analyzerOptions(cfg)
+
+	// Several targets are compared rather than audited one by one
+	if cfg.candidates != "" {
+		return runCandidates(cfg, opts)
+	}
+
+	// Re-run a recorded audit with its upgrade and options
+	var recorded *analyzer.Provenance
+	if cfg.reproduce != "" {
+		if cfg.upgrade != "" {
+			return fmt.Errorf("cannot combine -reproduce with -upgrade or -require-at-least")
+		}
+		data, err := os.ReadFile(cfg.reproduce)
+		if err != nil {
+			return fmt.Errorf("failed to read provenance: %w", err)
+		}
+		recorded, err = report.ParseProvenance(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse provenance %s: %w", cfg.reproduce, err)
+		}
+		cfg.upgrade = recorded.Module + "@" + recorded.NewVersion
+		opts = reproducibleOptions(opts, recorded.Options)
+		opts.Provenance = true
+	}
+
 	// Parse the upgrade specification
 	moduleUpgrade, err := parseUpgradeFn(cfg.upgrade)
 	if err != nil {
 		return fmt.Errorf("invalid upgrade specification: %w", err)
 	}
+	if cfg.topFixes < 0 {
+		return fmt.Errorf("-top-fixes must not be negative")
+	}
+	if cfg.maxFindings < 0 {
+		return fmt.Errorf("-max-findings must not be negative")
+	}
+	if cfg.adapterMin < 0 {
+		return fmt.Errorf("-adapter-threshold must not be negative")
+	}
+	if cfg.maxAffected < noAffectedLimit {
+		return fmt.Errorf("-max-affected must be -1 or more")
+	}
+	if cfg.width < 0 {
+		return fmt.Errorf("-width must not be negative")
+	}
+	color, err := colorOutput(cfg.color, stdoutWriter)
+	if err != nil {
+		return err
+	}
+	groupBy, err := report.ParseGroupBy(cfg.groupBy)
+	if err != nil {
+		return fmt.Errorf("-group-by: %w", err)
+	}
+	if cfg.shards < 0 {
+		return fmt.Errorf("-shards must not be negative")
+	}
+	switch cfg.fixContext {
+	case "", analyzer.FixContextTODO, analyzer.FixContextBackground:
+	default:
+		return fmt.Errorf("-fix-context must be %s or %s", analyzer.FixContextTODO, analyzer.FixContextBackground)
+	}
+	if cfg.dryRun && !cfg.fix && cfg.shims == "" {
+		return fmt.Errorf("-dry-run requires -fix or -shims")
+	}
+	if cfg.patch != "" && !cfg.fix && cfg.shims == "" {
+		return fmt.Errorf("-patch requires -fix or -shims")
+	}
+	switch cfg.modFlag {
+	case "", analyzer.ModReadonly, analyzer.ModMod:
+	default:
+		return fmt.Errorf("-mod must be %s or %s", analyzer.ModReadonly, analyzer.ModMod)
+	}
+	switch cfg.fixPolicy {
+	case "", analyzer.FixPolicyTODO, analyzer.FixPolicyDiscard:
+	default:
+		return fmt.Errorf("-fix-policy must be %s or %s", analyzer.FixPolicyTODO, analyzer.FixPolicyDiscard)
+	}
+	formats, err := outputFormats(cfg)
+	if err != nil {
+		return err
+	}
+	if cfg.signKey != "" && !hasFormat(formats, formatJSON) {
+		return fmt.Errorf("-sign requires -json or -format json")
+	}
 
 	if cfg.verbose {
 		fmt.Fprintf(stderrWriter, "Analyzing project at: %s\n", cfg.projectPath)
@@ -106,8 +461,15 @@ func run(cfg config) error {
 			moduleUpgrade.Module, moduleUpgrade.OldVersion, moduleUpgrade.NewVersion)
 	}
 
+	// A recorded run is only repeated on the inputs it was recorded with
+	if recorded != nil {
+		if err := checkReproducible(cfg.projectPath, opts, recorded); err != nil {
+			return err
+		}
+	}
+
 	// Create analyzer
-	a, err := newAnalyzerFn(cfg.projectPath)
+	a, err := newClient(cfg, opts)
 	if err != nil {
 		return fmt.Errorf("failed to initialize analyzer: %w", err)
 	}
@@ -117,57 +479,237 @@ func run(cfg config) error {
 	if err != nil {
 		return fmt.Errorf("analysis failed: %w", err)
 	}
+	if result.Provenance != nil {
+		result.Provenance.ToolVersion = version
+	}
+	if recorded != nil {
+		if result.Provenance == nil {
+			return fmt.Errorf("run is not reproducible: no provenance recorded")
+		}
+		if mismatches := result.Provenance.Mismatches(recorded); len(mismatches) > 0 {
+			return fmt.Errorf("run is not reproducible: %s", strings.Join(mismatches, "; "))
+		}
+	}
+
+	// A fail-fast result already answers the question
+	if result.StoppedEarly {
+		cfg.unused = false
+	}
+
+	if cfg.history != "" && result.Risk != nil {
+		if err := addRiskHistory(result, cfg.history); err != nil {
+			return fmt.Errorf("failed to load history: %w", err)
+		}
+	}
 
 	// Check for unused dependencies if requested
 	if cfg.unused {
 		unused, err := a.FindUnusedDependencies()
-		if err != nil && cfg.verbose {
-			fmt.Fprintf(stderrWriter, "Warning: failed to detect unused dependencies: %v\n", err)
+		if err != nil {
+			result.Warnings = append(result.Warnings, analyzer.Warning{
+				Code:    analyzer.WarnUnusedDeps,
+				Message: fmt.Sprintf("failed to detect unused dependencies: %v", err),
+			})
 		} else {
 			result.UnusedDeps = unused
 		}
 	}
 
-	// Generate report
-	var output string
-	if cfg.jsonOutput && cfg.htmlOutput {
-		return fmt.Errorf("cannot use -json and -html together")
+	// Generate reports
+	textOpts := report.TextOptions{Verbose: cfg.verbose, TopFixes: cfg.topFixes, Width: cfg.width, Color: color, GroupBy: groupBy, MaxFindings: cfg.maxFindings}
+	if len(formats) > 1 {
+		// Files are not terminals
+		textOpts.Color = false
+	}
+	outputs := make(map[string]string, len(formats))
+	for _, format := range formats {
+		outputs[format], err = renderReport(format, result, textOpts)
+		if err != nil {
+			return fmt.Errorf("failed to generate report: %w", err)
+		}
 	}
 
-	switch {
-	case cfg.jsonOutput:
-		output, err = formatJSONFn(result)
-	case cfg.htmlOutput:
-		output, err = formatHTMLFn(result)
-	default:
-		output, err = formatTextFn(result, cfg.verbose)
+	if cfg.signKey != "" {
+		if err := writeAttestation(cfg, result, outputs[formatJSON]); err != nil {
+			return err
+		}
 	}
-	if err != nil {
-		return fmt.Errorf("failed to generate report: %w", err)
+
+	if len(formats) == 1 {
+		fmt.Fprint(stdoutWriter, outputs[formats[0]])
+	} else if err := writeReports(cfg.outputBase, formats, outputs); err != nil {
+		return err
+	}
+	if cfg.graph != "" {
+		if err := os.WriteFile(cfg.graph, []byte(report.FormatDOT(result)), 0o644); err != nil {
+			return fmt.Errorf("failed to write import graph: %w", err)
+		}
+		fmt.Fprintf(stderrWriter, "Wrote import graph to %s\n", cfg.graph)
+	}
+	if cfg.patch != "" {
+		if err := os.WriteFile(cfg.patch, []byte(report.FormatPatch(result)), 0o644); err != nil {
+			return fmt.Errorf("failed to write patch: %w", err)
+		}
+		fmt.Fprintf(stderrWriter, "Wrote patch of %d file(s) to %s\n", len(report.PatchDiffs(result)), cfg.patch)
+	}
+	if cfg.bundle != "" {
+		if err := writeBundle(cfg.bundle, result, groupBy); err != nil {
+			return err
+		}
+	}
+	if cfg.save != "" {
+		if err := analyzer.SaveResult(cfg.save, result); err != nil {
+			return err
+		}
+		fmt.Fprintf(stderrWriter, "Saved result to %s\n", cfg.save)
 	}
 
-	fmt.Fprint(stdoutWriter, output)
+	if telemetryOn {
+		recordRun(cfg, formats, result, time.Since(start))
+	}
 
 	// Determine exit code
-	exitCode := determineExitCode(result, cfg.strict)
-	if exitCode != 0 {
-		exitFunc(exitCode)
+	classification := classifier(cfg).Classify(result)
+	if classification.ExitCode == 0 && classification.Accepted && cfg.verbose {
+		fmt.Fprintf(stderrWriter, "Breaking changes affect %d location(s), within -max-affected %d\n",
+			classification.AffectedLocations, cfg.maxAffected)
+	}
+	if classification.ExitCode != 0 {
+		exitFunc(classification.ExitCode)
 		return nil
 	}
 
 	return nil
 }
 
-func determineExitCode(result *analyzer.Result, strict bool) int {
-	// Exit non-zero if there are breaking changes
-	if result.HasBreakingChanges() {
-		return 1
+// analyzerOptions maps CLI flags onto analyzer options
+func analyzerOptions(cfg config) analyzer.Options {
+	// An invalid -format is reported when the reports are rendered
+	formats, _ := outputFormats(cfg)
+	return analyzer.Options{
+		NewDependency:       cfg.newDep,
+		FromVersion:         cfg.from,
+		IgnoreReplace:       cfg.ignoreRepl,
+		Footprint:           cfg.footprint,
+		SumDB:               cfg.sumdb,
+		BinaryImpact:        cfg.binImpact,
+		Bench:               cfg.bench,
+		RunTests:            cfg.runTests,
+		IncludeTestPackages: cfg.includeTest,
+		Shims:               cfg.shims,
+		Fix:                 cfg.fix,
+		FixContext:          cfg.fixContext,
+		FixPolicy:           cfg.fixPolicy,
+		AllowDirty:          cfg.allowDirty,
+		DryRun:              cfg.dryRun || cfg.patch != "",
+		Shards:              cfg.shards,
+		Docs:                cfg.docs,
+		Examples:            cfg.examples,
+		Hints:               cfg.hints,
+		TODOs:               cfg.todos,
+		DeadCode:            cfg.deadCode,
+		ImportGraph:         cfg.graph != "",
+		Platforms:           cfg.platformSet,
+		Severities:          cfg.severities,
+		FailFast:            cfg.failFast,
+		RequireAtLeast:      cfg.minVersion != "",
+		Modules:             cfg.modules,
+		PackagesDriver:      cfg.pkgDriver,
+		ModFlag:             cfg.modFlag,
+		AllowErrors:         cfg.allowErrors,
+		AdapterThreshold:    cfg.adapterMin,
+		// The JSON report records what is needed to reproduce the run; a failure to
+		// record it is reported as a warning rather than failing the audit
+		Provenance: hasFormat(formats, formatJSON) || cfg.bundle != "",
+		// The HTML report draws a heatmap of findings per directory
+		Directories: hasFormat(formats, formatHTML) || cfg.bundle != "",
 	}
+}
 
-	// In strict mode, exit non-zero if there are any warnings
-	if strict && result.HasWarnings() {
-		return 1
+// noAffectedLimit makes any breaking change fail the run
+const noAffectedLimit = -1
+
+// reproducibleOptions copies the options of a recorded run that only change
+// what is analyzed onto the options of this run. A report file is untrusted
+// input, so options that write files, run programs, or run the project's
+// code, such as Fix, Shims, Bench, RunTests, or PackagesDriver, are never
+// taken from it.
+func reproducibleOptions(opts, recorded analyzer.Options) analyzer.Options {
+	opts.NewDependency = recorded.NewDependency
+	opts.IgnoreReplace = recorded.IgnoreReplace
+	opts.Footprint = recorded.Footprint
+	opts.IncludeTestPackages = recorded.IncludeTestPackages
+	opts.Docs = recorded.Docs
+	opts.FailFast = recorded.FailFast
+	opts.Examples = recorded.Examples
+	opts.Hints = recorded.Hints
+	opts.TODOs = recorded.TODOs
+	opts.DeadCode = recorded.DeadCode
+	opts.ImportGraph = recorded.ImportGraph
+	opts.Platforms = recorded.Platforms
+	opts.Directories = recorded.Directories
+	opts.Severities = recorded.Severities
+	opts.RequireAtLeast = recorded.RequireAtLeast
+	opts.ModFlag = recorded.ModFlag
+	opts.AllowErrors = recorded.AllowErrors
+	opts.FromVersion = recorded.FromVersion
+	opts.AdapterThreshold = recorded.AdapterThreshold
+	opts.SumDB = recorded.SumDB
+	return opts
+}
+
+// checkReproducible compares the toolchain, environment, versions, and module
+// checksums of a recorded run with the current ones before anything is
+// analyzed
+func checkReproducible(projectPath string, opts analyzer.Options, recorded *analyzer.Provenance) error {
+	upgrade := &analyzer.Upgrade{Module: recorded.Module, OldVersion: recorded.OldVersion, NewVersion: recorded.NewVersion}
+	actual, err := recordProvenanceFn(projectPath, opts, upgrade)
+	if err != nil {
+		return fmt.Errorf("run is not reproducible: %w", err)
+	}
+	actual.ToolVersion = version
+	if mismatches := actual.Mismatches(recorded); len(mismatches) > 0 {
+		return fmt.Errorf("run is not reproducible: %s", strings.Join(mismatches, "; "))
 	}
+	return nil
+}
+
+// classifier builds the classifier that decides the exit code from the
+// severity overrides, -strict, and -max-affected
+func classifier(cfg config) analyzer.Classifier {
+	return analyzer.Classifier{
+		Severities:  cfg.severities,
+		Strict:      cfg.strict,
+		MaxAffected: cfg.maxAffected,
+	}
+}
+
+// determineExitCode returns the exit code a classifier assigns a result: it
+// fails on breaking changes, unless they affect no more than MaxAffected
+// locations; accepted breakages then only fail in strict mode. A project
+// below a required minimum version, or an audit that could not diff the API,
+// always fails.
+func determineExitCode(result *analyzer.Result, c analyzer.Classifier) int {
+	return c.Classify(result).ExitCode
+}
 
-	return 0
+// addRiskHistory folds past audits of the same module into the risk score;
+// usage-only audits, which could not diff the API, are left out
+func addRiskHistory(result *analyzer.Result, dir string) error {
+	reports, err := loadReports([]string{dir})
+	if err != nil {
+		return err
+	}
+	audits, breaking := 0, 0
+	for _, r := range reports {
+		if r.Module != result.Module || r.UsageOnly != nil {
+			continue
+		}
+		audits++
+		if r.Breaking {
+			breaking++
+		}
+	}
+	result.Risk.AddHistory(audits, breaking)
+	return nil
 }
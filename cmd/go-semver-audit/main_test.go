@@ -6,10 +6,14 @@ import (
 	"flag"
 	"io"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/devblac/go-semver-audit/internal/analyzer"
+	"github.com/devblac/go-semver-audit/internal/report"
+	"github.com/devblac/go-semver-audit/internal/telemetry"
 )
 
 func TestDetermineExitCode(t *testing.T) {
@@ -85,7 +89,7 @@ func TestDetermineExitCode(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := determineExitCode(tt.result, tt.strict)
+			got := determineExitCode(tt.result, analyzer.Classifier{Strict: tt.strict, MaxAffected: noAffectedLimit})
 			if got != tt.want {
 				t.Errorf("determineExitCode() = %v, want %v", got, tt.want)
 			}
@@ -171,12 +175,12 @@ func TestRun_GeneratesTextReportWithUnusedDeps(t *testing.T) {
 		},
 		unused: []string{"github.com/unused/dep"},
 	}
-	newAnalyzerFn = func(path string) (analyzerClient, error) {
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
 		fakeAnalyzer.projectPath = path
 		return fakeAnalyzer, nil
 	}
 
-	formatTextFn = func(res *analyzer.Result, verbose bool) (string, error) {
+	formatTextFn = func(res *analyzer.Result, opts report.TextOptions) (string, error) {
 		return "text report\n", nil
 	}
 
@@ -227,7 +231,7 @@ func TestRun_JSONStrictExitsOnWarnings(t *testing.T) {
 			Changes: &analyzer.Diff{Added: []analyzer.AddedSymbol{{Name: "New", Type: "func"}}},
 		},
 	}
-	newAnalyzerFn = func(path string) (analyzerClient, error) {
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
 		return fakeAnalyzer, nil
 	}
 
@@ -285,8 +289,8 @@ func TestRun_HTMLReport(t *testing.T) {
 			Changes: &analyzer.Diff{},
 		},
 	}
-	newAnalyzerFn = func(path string) (analyzerClient, error) { return fakeAnalyzer, nil }
-	formatHTMLFn = func(res *analyzer.Result) (string, error) { return "<html>ok</html>", nil }
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) { return fakeAnalyzer, nil }
+	formatHTMLFn = func(res *analyzer.Result, opts report.HTMLOptions) (string, error) { return "<html>ok</html>", nil }
 
 	cfg := config{
 		projectPath: "testdata/userproject",
@@ -313,7 +317,7 @@ func TestRun_JSONAndHTMLConflict(t *testing.T) {
 	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
 		return &analyzer.Upgrade{Module: "example.com/mod"}, nil
 	}
-	newAnalyzerFn = func(path string) (analyzerClient, error) {
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
 		return &stubAnalyzer{analyzeResult: &analyzer.Result{Module: "example.com/mod", Changes: &analyzer.Diff{}}}, nil
 	}
 
@@ -329,6 +333,33 @@ func TestRun_JSONAndHTMLConflict(t *testing.T) {
 	}
 }
 
+func TestRun_LSPOutput(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	var stdout bytes.Buffer
+	stdoutWriter = &stdout
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/mod"}, nil
+	}
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		return &stubAnalyzer{analyzeResult: &analyzer.Result{Module: "example.com/mod", Changes: &analyzer.Diff{}}}, nil
+	}
+	formatLSPFn = func(res *analyzer.Result) (string, error) { return "[]\n", nil }
+
+	if err := run(config{projectPath: ".", upgrade: "example.com/mod@v1.0.0", lspOutput: true}); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if stdout.String() != "[]\n" {
+		t.Fatalf("expected LSP output, got %q", stdout.String())
+	}
+
+	err := run(config{projectPath: ".", upgrade: "example.com/mod@v1.0.0", lspOutput: true, jsonOutput: true})
+	if err == nil || !strings.Contains(err.Error(), "cannot combine -lsp") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+}
+
 func TestRun_ParseUpgradeError(t *testing.T) {
 	restore := stubGlobals()
 	defer restore()
@@ -343,7 +374,51 @@ func TestRun_ParseUpgradeError(t *testing.T) {
 	}
 }
 
-func TestRun_LogsWarningOnUnusedDepsErrorVerbose(t *testing.T) {
+func TestRun_RejectsNegativeTopFixes(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/lib", NewVersion: "v1.0.0"}, nil
+	}
+
+	err := run(config{upgrade: "example.com/lib@v1.0.0", topFixes: -1})
+	if err == nil || !strings.Contains(err.Error(), "-top-fixes") {
+		t.Fatalf("expected -top-fixes error, got %v", err)
+	}
+}
+
+func TestRun_PassesMaxFindings(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/lib", NewVersion: "v1.0.0"}, nil
+	}
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		return &stubAnalyzer{analyzeResult: &analyzer.Result{Changes: &analyzer.Diff{}}}, nil
+	}
+	var got report.TextOptions
+	formatTextFn = func(res *analyzer.Result, opts report.TextOptions) (string, error) {
+		got = opts
+		return "", nil
+	}
+	stdoutWriter = &bytes.Buffer{}
+
+	if err := run(config{upgrade: "example.com/lib@v1.0.0", maxFindings: 50}); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if got.MaxFindings != 50 {
+		t.Errorf("MaxFindings = %d, want 50", got.MaxFindings)
+	}
+
+	err := run(config{upgrade: "example.com/lib@v1.0.0", maxFindings: -1})
+	if err == nil || !strings.Contains(err.Error(), "-max-findings") {
+		t.Fatalf("expected -max-findings error, got %v", err)
+	}
+}
+
+func TestRun_RecordsWarningOnUnusedDepsError(t *testing.T) {
 	restore := stubGlobals()
 	defer restore()
 
@@ -363,22 +438,25 @@ func TestRun_LogsWarningOnUnusedDepsErrorVerbose(t *testing.T) {
 		},
 		unusedErr: errors.New("boom"),
 	}
-	newAnalyzerFn = func(path string) (analyzerClient, error) { return fakeAnalyzer, nil }
-	formatTextFn = func(res *analyzer.Result, verbose bool) (string, error) { return "ok\n", nil }
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) { return fakeAnalyzer, nil }
+	var warnings []analyzer.Warning
+	formatTextFn = func(res *analyzer.Result, opts report.TextOptions) (string, error) {
+		warnings = res.Warnings
+		return "ok\n", nil
+	}
 
 	cfg := config{
 		projectPath: ".",
 		upgrade:     "example.com/mod@v1.2.0",
 		unused:      true,
-		verbose:     true,
 	}
 
 	if err := run(cfg); err != nil {
 		t.Fatalf("run returned error: %v", err)
 	}
 
-	if !strings.Contains(stderr.String(), "failed to detect unused dependencies") {
-		t.Fatalf("expected warning, got %q", stderr.String())
+	if len(warnings) != 1 || warnings[0].Code != analyzer.WarnUnusedDeps || !strings.Contains(warnings[0].Message, "boom") {
+		t.Fatalf("expected unused-deps warning, got %+v", warnings)
 	}
 	if !strings.Contains(stdout.String(), "ok") {
 		t.Fatalf("expected report output, got %q", stdout.String())
@@ -476,9 +554,13 @@ func (s *stubAnalyzer) FindUnusedDependencies() ([]string, error) {
 func stubGlobals() func() {
 	oldParseUpgrade := parseUpgradeFn
 	oldNewAnalyzer := newAnalyzerFn
+	oldRecordProvenance := recordProvenanceFn
 	oldFormatJSON := formatJSONFn
 	oldFormatHTML := formatHTMLFn
+	oldFormatLSP := formatLSPFn
+	oldFormatSARIF := formatSARIFFn
 	oldFormatText := formatTextFn
+	oldRecordTelemetry := recordTelemetryFn
 	oldExit := exitFunc
 	oldStdout := stdoutWriter
 	oldStderr := stderrWriter
@@ -488,9 +570,13 @@ func stubGlobals() func() {
 	return func() {
 		parseUpgradeFn = oldParseUpgrade
 		newAnalyzerFn = oldNewAnalyzer
+		recordProvenanceFn = oldRecordProvenance
 		formatJSONFn = oldFormatJSON
 		formatHTMLFn = oldFormatHTML
+		formatLSPFn = oldFormatLSP
+		formatSARIFFn = oldFormatSARIF
 		formatTextFn = oldFormatText
+		recordTelemetryFn = oldRecordTelemetry
 		exitFunc = oldExit
 		stdoutWriter = oldStdout
 		stderrWriter = oldStderr
@@ -498,3 +584,631 @@ func stubGlobals() func() {
 		flag.CommandLine = oldCommandLine
 	}
 }
+
+func TestRun_Reproduce(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	recorded := &analyzer.Provenance{
+		ToolVersion: version,
+		GoVersion:   "go1.21.13",
+		Module:      "example.com/lib",
+		OldVersion:  "v1.0.0",
+		NewVersion:  "v1.1.0",
+		NewSum:      "h1:a=",
+		Options: analyzer.Options{
+			Footprint: true,
+			// A crafted report must not make the run write files or execute anything
+			Fix: true, Shims: "shims", AllowDirty: true, BinaryImpact: "./cmd/app", Bench: "./...",
+			RunTests: true, PackagesDriver: "/tmp/evil", Modules: "/etc/passwd",
+		},
+	}
+	out, err := report.FormatJSON(&analyzer.Result{Module: "example.com/lib", Changes: &analyzer.Diff{}, Provenance: recorded})
+	if err != nil {
+		t.Fatalf("FormatJSON() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "provenance.json")
+	if err := os.WriteFile(path, []byte(out), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotUpgrade string
+	var gotOpts analyzer.Options
+	analyzed := 0
+	actual := *recorded
+	recordProvenanceFn = func(path string, opts analyzer.Options, upgrade *analyzer.Upgrade) (*analyzer.Provenance, error) {
+		if upgrade.OldVersion != recorded.OldVersion || upgrade.NewVersion != recorded.NewVersion {
+			t.Errorf("provenance checked for %+v, want the recorded versions", upgrade)
+		}
+		p := actual
+		p.ToolVersion = ""
+		return &p, nil
+	}
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		gotUpgrade = spec
+		return &analyzer.Upgrade{Module: "example.com/lib", NewVersion: "v1.1.0"}, nil
+	}
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		gotOpts = opts
+		analyzed++
+		p := actual
+		p.ToolVersion = ""
+		return &stubAnalyzer{analyzeResult: &analyzer.Result{Module: "example.com/lib", Changes: &analyzer.Diff{}, Provenance: &p}}, nil
+	}
+	stdoutWriter = &bytes.Buffer{}
+
+	if err := run(config{projectPath: ".", reproduce: path}); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if gotUpgrade != "example.com/lib@v1.1.0" {
+		t.Errorf("upgrade = %q, want the recorded one", gotUpgrade)
+	}
+	if !gotOpts.Footprint || !gotOpts.Provenance {
+		t.Errorf("options = %+v, want the recorded options with provenance", gotOpts)
+	}
+	if gotOpts.Fix || gotOpts.Shims != "" || gotOpts.AllowDirty || gotOpts.BinaryImpact != "" || gotOpts.Bench != "" ||
+		gotOpts.RunTests || gotOpts.PackagesDriver != "" || gotOpts.Modules != "" {
+		t.Errorf("options = %+v, want no option that writes files or runs programs", gotOpts)
+	}
+
+	// A mismatch stops the run before anything is analyzed
+	analyzed = 0
+	actual.NewSum = "h1:b="
+	err = run(config{projectPath: ".", reproduce: path})
+	if err == nil || !strings.Contains(err.Error(), "not reproducible") || !strings.Contains(err.Error(), "new sum") {
+		t.Fatalf("expected reproducibility error, got %v", err)
+	}
+	if analyzed != 0 {
+		t.Errorf("analyzed %d time(s) despite the mismatch", analyzed)
+	}
+
+	err = run(config{projectPath: ".", reproduce: path, upgrade: "example.com/lib@v1.2.0"})
+	if err == nil || !strings.Contains(err.Error(), "cannot combine -reproduce") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+}
+
+func TestRun_RejectsNegativeWidth(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/lib", NewVersion: "v1.0.0"}, nil
+	}
+
+	err := run(config{upgrade: "example.com/lib@v1.0.0", width: -1})
+	if err == nil || !strings.Contains(err.Error(), "-width") {
+		t.Fatalf("expected -width error, got %v", err)
+	}
+}
+
+func TestTerminalWidth(t *testing.T) {
+	tests := []struct {
+		columns string
+		want    int
+	}{
+		{"120", 120},
+		{"", 0},
+		{"wide", 0},
+		{"-5", 0},
+	}
+	for _, tt := range tests {
+		t.Setenv("COLUMNS", tt.columns)
+		if got := terminalWidth(); got != tt.want {
+			t.Errorf("terminalWidth() with COLUMNS=%q = %d, want %d", tt.columns, got, tt.want)
+		}
+	}
+}
+
+func TestColorOutput(t *testing.T) {
+	var buf bytes.Buffer
+	tests := []struct {
+		mode    string
+		want    bool
+		wantErr bool
+	}{
+		{mode: "always", want: true},
+		{mode: "never", want: false},
+		{mode: "auto", want: false}, // not a terminal
+		{mode: "", want: false},
+		{mode: "rainbow", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := colorOutput(tt.mode, &buf)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("colorOutput(%q) = %v, %v, want %v (error %v)", tt.mode, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestRun_PassesColorToTextReport(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/lib", NewVersion: "v1.0.0"}, nil
+	}
+	newAnalyzerFn = func(projectPath string, opts analyzer.Options) (analyzerClient, error) {
+		return &stubAnalyzer{analyzeResult: &analyzer.Result{Module: "example.com/lib", Changes: &analyzer.Diff{}}}, nil
+	}
+	var got report.TextOptions
+	formatTextFn = func(result *analyzer.Result, opts report.TextOptions) (string, error) {
+		got = opts
+		return "", nil
+	}
+	stdoutWriter = &bytes.Buffer{}
+
+	if err := run(config{upgrade: "example.com/lib@v1.0.0", color: "always"}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if !got.Color {
+		t.Errorf("expected -color always to enable color")
+	}
+}
+
+func TestRun_FailFastSkipsUnusedDependencies(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/lib", NewVersion: "v2.0.0"}, nil
+	}
+	var gotOpts analyzer.Options
+	fake := &stubAnalyzer{
+		analyzeResult: &analyzer.Result{
+			Module:       "example.com/lib",
+			StoppedEarly: true,
+			Changes: &analyzer.Diff{
+				Removed: []analyzer.RemovedSymbol{{Name: "Open", Type: "function", UsedIn: []analyzer.Location{{File: "main.go", Line: 1}}}},
+			},
+		},
+		unusedErr: errors.New("should not be called"),
+	}
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		gotOpts = opts
+		return fake, nil
+	}
+	var warnings []analyzer.Warning
+	formatTextFn = func(res *analyzer.Result, opts report.TextOptions) (string, error) {
+		warnings = res.Warnings
+		return "", nil
+	}
+	stdoutWriter = &bytes.Buffer{}
+	exitCode := 0
+	exitFunc = func(code int) { exitCode = code }
+
+	if err := run(config{upgrade: "example.com/lib@v2.0.0", failFast: true, unused: true}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if exitCode != 1 {
+		t.Errorf("exit code = %d, want 1 for the breaking finding", exitCode)
+	}
+	if !gotOpts.FailFast {
+		t.Errorf("expected -fail-fast to set Options.FailFast")
+	}
+	if len(warnings) != 0 {
+		t.Errorf("expected unused dependency detection to be skipped, got %+v", warnings)
+	}
+}
+
+func TestDetermineExitCode_MaxAffected(t *testing.T) {
+	result := &analyzer.Result{
+		Changes: &analyzer.Diff{
+			Removed: []analyzer.RemovedSymbol{
+				{Name: "OldFunc", Type: "function", UsedIn: []analyzer.Location{{File: "a.go", Line: 1}, {File: "b.go", Line: 2}}},
+			},
+			Changed: []analyzer.ChangedSignature{
+				{Name: "Preview", UsedIn: []analyzer.Location{{File: "c.go", Line: 3}}, Unstable: true},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		maxAffected int
+		strict      bool
+		want        int
+	}{
+		{name: "no limit", maxAffected: noAffectedLimit, want: 1},
+		{name: "over the limit", maxAffected: 1, want: 1},
+		{name: "at the limit", maxAffected: 2, want: 0},
+		{name: "unstable locations do not count", maxAffected: 2, want: 0},
+		{name: "strict still fails", maxAffected: 10, strict: true, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := determineExitCode(result, analyzer.Classifier{Strict: tt.strict, MaxAffected: tt.maxAffected}); got != tt.want {
+				t.Errorf("determineExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_RejectsUnknownGroupBy(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/lib", NewVersion: "v1.0.0"}, nil
+	}
+
+	err := run(config{upgrade: "example.com/lib@v1.0.0", groupBy: "module"})
+	if err == nil || !strings.Contains(err.Error(), "-group-by") {
+		t.Fatalf("expected -group-by error, got %v", err)
+	}
+}
+
+func TestRun_RequireAtLeast(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	var gotSpec string
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		gotSpec = spec
+		return &analyzer.Upgrade{Module: "example.com/lib", NewVersion: "v1.5.0"}, nil
+	}
+	var gotOpts analyzer.Options
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		gotOpts = opts
+		return &stubAnalyzer{analyzeResult: &analyzer.Result{
+			Module:  "example.com/lib",
+			Changes: &analyzer.Diff{},
+			Floor:   &analyzer.Floor{Required: "v1.5.0", Current: "v1.2.0", Gap: "3 minor version(s) behind"},
+		}}, nil
+	}
+	formatTextFn = func(res *analyzer.Result, opts report.TextOptions) (string, error) { return "", nil }
+	stdoutWriter = &bytes.Buffer{}
+	exitCode := 0
+	exitFunc = func(code int) { exitCode = code }
+
+	if err := run(config{minVersion: "example.com/lib@v1.5.0", maxAffected: 10}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if gotSpec != "example.com/lib@v1.5.0" || !gotOpts.RequireAtLeast {
+		t.Errorf("expected -require-at-least to audit %q with RequireAtLeast, got %q %+v", "example.com/lib@v1.5.0", gotSpec, gotOpts)
+	}
+	if exitCode != 1 {
+		t.Errorf("exit code = %d, want 1 for a project below the minimum", exitCode)
+	}
+
+	err := run(config{minVersion: "example.com/lib@v1.5.0", upgrade: "example.com/lib@v2.0.0"})
+	if err == nil || !strings.Contains(err.Error(), "-require-at-least") {
+		t.Errorf("expected error combining -require-at-least with -upgrade, got %v", err)
+	}
+}
+
+func TestRun_From(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/lib", NewVersion: "v1.5.0"}, nil
+	}
+	var gotOpts analyzer.Options
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		gotOpts = opts
+		return &stubAnalyzer{analyzeResult: &analyzer.Result{Changes: &analyzer.Diff{}}}, nil
+	}
+	formatTextFn = func(res *analyzer.Result, opts report.TextOptions) (string, error) { return "", nil }
+	stdoutWriter = &bytes.Buffer{}
+
+	if err := run(config{upgrade: "example.com/lib@v1.5.0", from: "v1.2.0"}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if gotOpts.FromVersion != "v1.2.0" {
+		t.Errorf("FromVersion = %q, want v1.2.0", gotOpts.FromVersion)
+	}
+
+	err := run(config{minVersion: "example.com/lib@v1.5.0", from: "v1.2.0"})
+	if err == nil || !strings.Contains(err.Error(), "-from") {
+		t.Errorf("expected error combining -require-at-least with -from, got %v", err)
+	}
+}
+
+func TestRun_AdapterThreshold(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/lib", NewVersion: "v1.5.0"}, nil
+	}
+	var gotOpts analyzer.Options
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		gotOpts = opts
+		return &stubAnalyzer{analyzeResult: &analyzer.Result{Changes: &analyzer.Diff{}}}, nil
+	}
+	formatTextFn = func(res *analyzer.Result, opts report.TextOptions) (string, error) { return "", nil }
+	stdoutWriter = &bytes.Buffer{}
+
+	if err := run(config{upgrade: "example.com/lib@v1.5.0", adapterMin: 3}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if gotOpts.AdapterThreshold != 3 {
+		t.Errorf("AdapterThreshold = %d, want 3", gotOpts.AdapterThreshold)
+	}
+
+	err := run(config{upgrade: "example.com/lib@v1.5.0", adapterMin: -1})
+	if err == nil || !strings.Contains(err.Error(), "-adapter-threshold") {
+		t.Errorf("expected -adapter-threshold error, got %v", err)
+	}
+}
+
+func TestRun_Fix(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/lib", NewVersion: "v1.5.0"}, nil
+	}
+	var gotOpts analyzer.Options
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		gotOpts = opts
+		return &stubAnalyzer{analyzeResult: &analyzer.Result{Changes: &analyzer.Diff{}}}, nil
+	}
+	formatTextFn = func(res *analyzer.Result, opts report.TextOptions) (string, error) { return "", nil }
+	stdoutWriter = &bytes.Buffer{}
+
+	if err := run(config{upgrade: "example.com/lib@v1.5.0", fix: true, fixContext: analyzer.FixContextBackground, fixPolicy: analyzer.FixPolicyDiscard, allowDirty: true, dryRun: true}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if !gotOpts.AllowDirty || !gotOpts.DryRun {
+		t.Errorf("AllowDirty = %v, DryRun = %v, want both true", gotOpts.AllowDirty, gotOpts.DryRun)
+	}
+	if !gotOpts.Fix || gotOpts.FixContext != analyzer.FixContextBackground || gotOpts.FixPolicy != analyzer.FixPolicyDiscard {
+		t.Errorf("Fix = %v, FixContext = %q, FixPolicy = %q, want true, %q, %q",
+			gotOpts.Fix, gotOpts.FixContext, gotOpts.FixPolicy, analyzer.FixContextBackground, analyzer.FixPolicyDiscard)
+	}
+
+	err := run(config{upgrade: "example.com/lib@v1.5.0", fix: true, fixContext: "nil"})
+	if err == nil || !strings.Contains(err.Error(), "-fix-context") {
+		t.Errorf("expected -fix-context error, got %v", err)
+	}
+	err = run(config{upgrade: "example.com/lib@v1.5.0", dryRun: true})
+	if err == nil || !strings.Contains(err.Error(), "-dry-run requires -fix") {
+		t.Errorf("expected -dry-run error, got %v", err)
+	}
+	err = run(config{upgrade: "example.com/lib@v1.5.0", fix: true, fixPolicy: "panic"})
+	if err == nil || !strings.Contains(err.Error(), "-fix-policy") {
+		t.Errorf("expected -fix-policy error, got %v", err)
+	}
+}
+
+func TestRun_Patch(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	var stderr bytes.Buffer
+	stdoutWriter = &bytes.Buffer{}
+	stderrWriter = &stderr
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/lib", NewVersion: "v1.5.0"}, nil
+	}
+	diff := "--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-lib.Fetch()\n+lib.Fetch(context.TODO())\n"
+	var gotOpts analyzer.Options
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		gotOpts = opts
+		return &stubAnalyzer{analyzeResult: &analyzer.Result{
+			Changes: &analyzer.Diff{},
+			Fixes:   &analyzer.FixReport{DryRun: true, Diffs: []analyzer.FileDiff{{File: "main.go", Diff: diff}}},
+		}}, nil
+	}
+	formatTextFn = func(res *analyzer.Result, opts report.TextOptions) (string, error) { return "", nil }
+
+	path := filepath.Join(t.TempDir(), "fix.patch")
+	if err := run(config{upgrade: "example.com/lib@v1.5.0", fix: true, patch: path}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if !gotOpts.DryRun {
+		t.Error("-patch should keep -fix from writing to the project")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != diff {
+		t.Errorf("patch = %q, want %q", data, diff)
+	}
+	if !strings.Contains(stderr.String(), "Wrote patch of 1 file(s) to "+path) {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+
+	stderr.Reset()
+	shim := "--- /dev/null\n+++ b/compat/semver_audit_shims.go\n@@ -0,0 +1 @@\n+package compat\n"
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		gotOpts = opts
+		return &stubAnalyzer{analyzeResult: &analyzer.Result{
+			Changes: &analyzer.Diff{},
+			Shims:   &analyzer.ShimFile{DryRun: true, Diff: analyzer.FileDiff{File: "compat/semver_audit_shims.go", Diff: shim}},
+		}}, nil
+	}
+	if err := run(config{upgrade: "example.com/lib@v1.5.0", shims: "compat", patch: path}); err != nil {
+		t.Fatalf("run() with -shims error = %v", err)
+	}
+	if !gotOpts.DryRun {
+		t.Error("-patch should keep -shims from writing to the project")
+	}
+	if data, _ := os.ReadFile(path); string(data) != shim {
+		t.Errorf("patch = %q, want the new shim file %q", data, shim)
+	}
+	if !strings.Contains(stderr.String(), "Wrote patch of 1 file(s) to "+path) {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+
+	err = run(config{upgrade: "example.com/lib@v1.5.0", patch: path})
+	if err == nil || !strings.Contains(err.Error(), "-patch requires -fix") {
+		t.Errorf("expected -patch error, got %v", err)
+	}
+}
+
+func TestRun_Graph(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	var stdout, stderr bytes.Buffer
+	stdoutWriter = &stdout
+	stderrWriter = &stderr
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/mod"}, nil
+	}
+	var gotOpts analyzer.Options
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		gotOpts = opts
+		return &stubAnalyzer{analyzeResult: &analyzer.Result{
+			Module:  "example.com/mod",
+			Changes: &analyzer.Diff{},
+			ImportGraph: &analyzer.ImportGraph{
+				Nodes:          []analyzer.GraphNode{{Package: "example.com/app", Imports: []string{"example.com/mod"}, Direct: true}},
+				ModulePackages: []string{"example.com/mod"},
+			},
+		}}, nil
+	}
+	formatTextFn = func(res *analyzer.Result, opts report.TextOptions) (string, error) { return "text\n", nil }
+
+	path := filepath.Join(t.TempDir(), "imports.dot")
+	if err := run(config{projectPath: ".", upgrade: "example.com/mod@v1.0.0", graph: path}); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if !gotOpts.ImportGraph {
+		t.Error("expected -graph to request the import graph")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read graph: %v", err)
+	}
+	if !strings.Contains(string(data), `"example.com/app" -> "example.com/mod";`) {
+		t.Errorf("graph = %q", data)
+	}
+	if !strings.Contains(stderr.String(), "Wrote import graph to "+path) {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestRun_Save(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	var stdout, stderr bytes.Buffer
+	stdoutWriter = &stdout
+	stderrWriter = &stderr
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/mod"}, nil
+	}
+	want := &analyzer.Result{
+		Module:     "example.com/mod",
+		OldVersion: "v1.0.0",
+		NewVersion: "v1.1.0",
+		Changes: &analyzer.Diff{
+			Added: []analyzer.AddedSymbol{{Name: "New", Type: "function"}},
+		},
+	}
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		return &stubAnalyzer{analyzeResult: want}, nil
+	}
+	formatTextFn = func(res *analyzer.Result, opts report.TextOptions) (string, error) { return "text\n", nil }
+
+	path := filepath.Join(t.TempDir(), "result.json")
+	if err := run(config{projectPath: ".", upgrade: "example.com/mod@v1.1.0", save: path}); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	got, err := analyzer.LoadResult(path)
+	if err != nil {
+		t.Fatalf("LoadResult() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("saved result = %+v, want %+v", got, want)
+	}
+	if !strings.Contains(stderr.String(), "Saved result to "+path) {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestRun_Telemetry(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	stdoutWriter = &bytes.Buffer{}
+	stderrWriter = &bytes.Buffer{}
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/mod"}, nil
+	}
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		return &stubAnalyzer{analyzeResult: &analyzer.Result{
+			Module: "example.com/mod",
+			Changes: &analyzer.Diff{Moved: []analyzer.MovedSymbol{
+				{Name: "Old", UsedIn: []analyzer.Location{{File: "main.go", Line: 1}}},
+			}},
+		}}, nil
+	}
+	formatTextFn = func(res *analyzer.Result, opts report.TextOptions) (string, error) { return "text\n", nil }
+	exitFunc = func(int) {}
+	var events []telemetry.Event
+	recordTelemetryFn = func(ev telemetry.Event) error {
+		events = append(events, ev)
+		return nil
+	}
+
+	t.Setenv(telemetry.EnvMode, "")
+	t.Setenv(telemetry.EnvDoNotTrack, "")
+	cfg := config{projectPath: ".", upgrade: "example.com/mod@v1.0.0"}
+	if err := run(cfg); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("telemetry recorded without opt in: %+v", events)
+	}
+
+	cfg.telemetry = "on"
+	if err := run(cfg); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("events = %d, want 1", len(events))
+	}
+	ev := events[0]
+	if ev.Version != version || !ev.Breaking || ev.Findings[analyzer.CategoryMoved] != 1 || len(ev.Formats) != 1 {
+		t.Errorf("event = %+v", ev)
+	}
+
+	// The environment kill switch wins over the flag
+	t.Setenv(telemetry.EnvMode, "off")
+	if err := run(cfg); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Errorf("telemetry recorded despite %s=off", telemetry.EnvMode)
+	}
+
+	cfg.telemetry = "maybe"
+	t.Setenv(telemetry.EnvMode, "")
+	if err := run(cfg); err == nil || !strings.Contains(err.Error(), "-telemetry") {
+		t.Errorf("run() error = %v, want invalid -telemetry", err)
+	}
+}
+
+func TestRun_ModFlag(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/lib", NewVersion: "v1.5.0"}, nil
+	}
+	var gotOpts analyzer.Options
+	newAnalyzerFn = func(path string, opts analyzer.Options) (analyzerClient, error) {
+		gotOpts = opts
+		return &stubAnalyzer{analyzeResult: &analyzer.Result{Changes: &analyzer.Diff{}}}, nil
+	}
+	formatTextFn = func(res *analyzer.Result, opts report.TextOptions) (string, error) { return "", nil }
+	stdoutWriter = &bytes.Buffer{}
+
+	if err := run(config{upgrade: "example.com/lib@v1.5.0", modFlag: analyzer.ModMod}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if gotOpts.ModFlag != analyzer.ModMod {
+		t.Errorf("ModFlag = %q, want %q", gotOpts.ModFlag, analyzer.ModMod)
+	}
+
+	err := run(config{upgrade: "example.com/lib@v1.5.0", modFlag: "vendor"})
+	if err == nil || !strings.Contains(err.Error(), "-mod must be") {
+		t.Errorf("expected -mod error, got %v", err)
+	}
+}
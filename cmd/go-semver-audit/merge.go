@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/devblac/go-semver-audit/internal/report"
+)
+
+// runMerge implements `go-semver-audit merge`, which combines the JSON
+// reports of several audits into one, keeping the file each came from
+func runMerge(args []string) error {
+	fs := flag.NewFlagSet("merge", flag.ContinueOnError)
+	fs.SetOutput(stderrWriter)
+	output := fs.String("o", "", "Write the merged report to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: go-semver-audit merge [-o combined.json] report.json|dir...")
+	}
+
+	files, err := reportFiles(fs.Args())
+	if err != nil {
+		return err
+	}
+	sources := make([]report.MergedSource, 0, len(files))
+	for _, file := range files {
+		r, err := readReport(file)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, report.MergedSource{Source: file, JSONReport: r})
+	}
+	out, err := report.FormatMerged(sources)
+	if err != nil {
+		return fmt.Errorf("failed to generate merged report: %w", err)
+	}
+
+	if *output == "" {
+		fmt.Fprint(stdoutWriter, out)
+		return nil
+	}
+	if err := os.WriteFile(*output, []byte(out), 0o644); err != nil {
+		return fmt.Errorf("failed to write merged report: %w", err)
+	}
+	fmt.Fprintf(stderrWriter, "Wrote merged report of %d audit(s) to %s\n", len(sources), *output)
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/report"
+)
+
+func TestRunMerge(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	dir := t.TempDir()
+	api := filepath.Join(dir, "api.json")
+	if err := os.WriteFile(api, []byte(`{"module":"example.com/lib","breaking":true,"breaking_count":2}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	web := filepath.Join(t.TempDir(), "web.json")
+	if err := os.WriteFile(web, []byte(`{"module":"example.com/other","breaking":false}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	stdoutWriter = &stdout
+	stderrWriter = &stderr
+	combined := filepath.Join(t.TempDir(), "combined.json")
+	if err := runMerge([]string{"-o", combined, dir, web}); err != nil {
+		t.Fatalf("runMerge() error = %v", err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("stdout = %q, want the report in %s", stdout.String(), combined)
+	}
+	if !strings.Contains(stderr.String(), "Wrote merged report of 2 audit(s) to "+combined) {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+	data, err := os.ReadFile(combined)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var merged report.MergedReport
+	if err := json.Unmarshal(data, &merged); err != nil {
+		t.Fatalf("invalid merged report: %v", err)
+	}
+	if len(merged.Sources) != 2 || merged.Sources[0].Source != api || merged.Sources[1].Source != web {
+		t.Fatalf("sources = %+v", merged.Sources)
+	}
+	if !merged.Breaking || merged.BreakingCount != 2 || merged.Sources[0].Module != "example.com/lib" {
+		t.Errorf("merged report = %+v", merged)
+	}
+
+	stdout.Reset()
+	if err := runMerge([]string{web}); err != nil {
+		t.Fatalf("runMerge() to stdout error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), `"source": "`+web+`"`) {
+		t.Errorf("stdout = %s", stdout.String())
+	}
+
+	if err := runMerge(nil); err == nil {
+		t.Error("runMerge without reports should fail")
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	rpprof "runtime/pprof"
+	"sort"
+	"time"
+)
+
+// maxHeapSnapshots bounds how many heap profiles a long-running daemon keeps
+const maxHeapSnapshots = 10
+
+// startPprof serves the net/http/pprof endpoints on addr, such as
+// localhost:6060, so a long audit can be profiled while it runs. It warns when
+// addr is not a loopback address, since the endpoints expose the command line
+// and memory of the process. It returns a function that stops the server.
+func startPprof(addr string) (func(), error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("-pprof: %w", err)
+	}
+
+	// Register on a mux of our own rather than http.DefaultServeMux
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+
+	fmt.Fprintf(stderrWriter, "pprof listening on http://%s/debug/pprof/\n", ln.Addr())
+	if tcp, ok := ln.Addr().(*net.TCPAddr); ok && !tcp.IP.IsLoopback() {
+		fmt.Fprintf(stderrWriter, "Warning: -pprof %s is reachable from other hosts; use localhost:%d to serve profiles locally only\n", addr, tcp.Port)
+	}
+	return func() { srv.Close() }, nil
+}
+
+// writeHeapSnapshots writes a heap profile to dir every interval until stop
+// is closed, keeping the newest maxHeapSnapshots. Profiles are named by the
+// time they were taken, e.g. heap-20240102T150405.pb.gz, for go tool pprof.
+func writeHeapSnapshots(dir string, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			if err := writeHeapSnapshot(dir, now); err != nil {
+				fmt.Fprintf(stderrWriter, "Warning: heap snapshot: %v\n", err)
+			}
+		}
+	}
+}
+
+// writeHeapSnapshot writes one heap profile to dir and prunes old ones
+func writeHeapSnapshot(dir string, now time.Time) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, "heap-"+now.UTC().Format("20060102T150405")+".pb.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	// Collect first so the profile reflects live memory, not garbage
+	runtime.GC()
+	if err := rpprof.Lookup("heap").WriteTo(f, 0); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	old, err := filepath.Glob(filepath.Join(dir, "heap-*.pb.gz"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(old)
+	for len(old) > maxHeapSnapshots {
+		os.Remove(old[0])
+		old = old[1:]
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartPprof(t *testing.T) {
+	defer stubGlobals()()
+	var stderr bytes.Buffer
+	stderrWriter = &stderr
+
+	stop, err := startPprof("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("startPprof() error = %v", err)
+	}
+	defer stop()
+
+	url := strings.TrimSpace(strings.TrimPrefix(stderr.String(), "pprof listening on "))
+	resp, err := http.Get(url + "heap?debug=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "heap profile") {
+		t.Errorf("GET %sheap = %d %q, want a heap profile", url, resp.StatusCode, body)
+	}
+}
+
+func TestStartPprof_WarnsOnNonLoopback(t *testing.T) {
+	defer stubGlobals()()
+	var stderr bytes.Buffer
+	stderrWriter = &stderr
+
+	stop, err := startPprof(":0")
+	if err != nil {
+		t.Fatalf("startPprof() error = %v", err)
+	}
+	stop()
+	if !strings.Contains(stderr.String(), "Warning: -pprof :0 is reachable from other hosts") {
+		t.Errorf("stderr = %q, want a warning about the non-loopback address", stderr.String())
+	}
+
+	stderr.Reset()
+	stop, err = startPprof("localhost:0")
+	if err != nil {
+		t.Fatalf("startPprof() error = %v", err)
+	}
+	stop()
+	if strings.Contains(stderr.String(), "Warning") {
+		t.Errorf("stderr = %q, want no warning for localhost", stderr.String())
+	}
+}
+
+func TestStartPprof_AddressInUse(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	if _, err := startPprof(ln.Addr().String()); err == nil || !strings.HasPrefix(err.Error(), "-pprof:") {
+		t.Errorf("startPprof() error = %v, want a -pprof error", err)
+	}
+}
+
+func TestWriteHeapSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	for i := 0; i < maxHeapSnapshots+2; i++ {
+		if err := writeHeapSnapshot(dir, start.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("writeHeapSnapshot() error = %v", err)
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "heap-*.pb.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != maxHeapSnapshots {
+		t.Fatalf("kept %d snapshots, want %d", len(files), maxHeapSnapshots)
+	}
+	if oldest := filepath.Base(files[0]); oldest != "heap-20240102T150605.pb.gz" {
+		t.Errorf("oldest snapshot = %s, want the two oldest pruned", oldest)
+	}
+	info, err := os.Stat(files[len(files)-1])
+	if err != nil || info.Size() == 0 {
+		t.Errorf("newest snapshot is empty: %v", err)
+	}
+}
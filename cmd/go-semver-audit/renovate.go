@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/devblac/go-semver-audit/internal/report"
+)
+
+// runRenovateConfig implements `go-semver-audit renovate-config`
+func runRenovateConfig(args []string) error {
+	fs := flag.NewFlagSet("renovate-config", flag.ContinueOnError)
+	fs.SetOutput(stderrWriter)
+	opts := report.DefaultRenovateOptions
+	fs.IntVar(&opts.MinAudits, "min-audits", opts.MinAudits, "Clean audits needed before patch upgrades are automerged")
+	fs.Float64Var(&opts.BreakRatio, "break-ratio", opts.BreakRatio, "Share of breaking audits at which upgrades need approval")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: go-semver-audit renovate-config [-min-audits n] [-break-ratio r] report.json|dir...")
+	}
+
+	reports, err := loadReports(fs.Args())
+	if err != nil {
+		return err
+	}
+	out, err := report.FormatRenovateConfig(reports, opts)
+	if err != nil {
+		return fmt.Errorf("failed to generate Renovate config: %w", err)
+	}
+	fmt.Fprint(stdoutWriter, out)
+	return nil
+}
+
+// loadReports reads JSON reports; directories contribute every *.json file in them
+func loadReports(paths []string) ([]report.JSONReport, error) {
+	files, err := reportFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+	reports := make([]report.JSONReport, 0, len(files))
+	for _, file := range files {
+		r, err := readReport(file)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, r)
+	}
+	return reports, nil
+}
+
+// reportFiles expands directories in paths to the *.json files in them
+func reportFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// readReport reads one JSON report
+func readReport(file string) (report.JSONReport, error) {
+	var r report.JSONReport
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return r, err
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return r, fmt.Errorf("failed to parse report %s: %w", file, err)
+	}
+	return r, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunRenovateConfig(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	dir := t.TempDir()
+	for i := 0; i < 3; i++ {
+		data := `{"module":"example.com/clean","breaking":false}`
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("audit%d.json", i)), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	extra := filepath.Join(t.TempDir(), "breaking.json")
+	if err := os.WriteFile(extra, []byte(`{"module":"example.com/risky","breaking":true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+	stdoutWriter = &stdout
+	if err := runRenovateConfig([]string{dir, extra}); err != nil {
+		t.Fatalf("runRenovateConfig returned error: %v", err)
+	}
+	for _, want := range []string{`"example.com/clean"`, `"example.com/risky"`, `"automerge": true`, `"dependencyDashboardApproval": true`} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("output missing %s:\n%s", want, stdout.String())
+		}
+	}
+
+	if err := runRenovateConfig(nil); err == nil {
+		t.Error("runRenovateConfig without reports should fail")
+	}
+}
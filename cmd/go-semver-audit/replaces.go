@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+	"github.com/devblac/go-semver-audit/internal/report"
+)
+
+// auditReplacesFn compares the replace directives of a project with upstream
+var auditReplacesFn = func(projectPath string, upstream map[string]string) ([]analyzer.ReplaceDrift, error) {
+	a, err := analyzer.New(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	return a.AuditReplaces(upstream)
+}
+
+// runReplaces implements `go-semver-audit replaces`
+func runReplaces(args []string) error {
+	fs := flag.NewFlagSet("replaces", flag.ContinueOnError)
+	fs.SetOutput(stderrWriter)
+	projectPath := fs.String("path", ".", "Path to Go project whose replace directives are audited")
+	jsonOutput := fs.Bool("json", false, "Output results as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Arguments pin the upstream version a replacement is compared with
+	upstream := make(map[string]string)
+	for _, spec := range fs.Args() {
+		up, err := analyzer.ParseUpgrade(spec)
+		if err != nil {
+			return fmt.Errorf("usage: go-semver-audit replaces [-path dir] [-json] [module@version...]: %w", err)
+		}
+		upstream[up.Module] = up.NewVersion
+	}
+
+	drifts, err := auditReplacesFn(*projectPath, upstream)
+	if err != nil {
+		return fmt.Errorf("failed to audit replace directives: %w", err)
+	}
+
+	output := report.FormatReplacesText(drifts)
+	if *jsonOutput {
+		output, err = report.FormatReplacesJSON(drifts)
+		if err != nil {
+			return fmt.Errorf("failed to generate report: %w", err)
+		}
+	}
+	fmt.Fprint(stdoutWriter, output)
+	return nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestRunReplaces(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+	oldAudit := auditReplacesFn
+	defer func() { auditReplacesFn = oldAudit }()
+
+	var gotPath string
+	var gotUpstream map[string]string
+	auditReplacesFn = func(projectPath string, upstream map[string]string) ([]analyzer.ReplaceDrift, error) {
+		gotPath, gotUpstream = projectPath, upstream
+		return []analyzer.ReplaceDrift{{
+			Module: "example.com/lib", Fork: "github.com/me/lib", ForkVersion: "v1.0.1",
+			UpstreamVersion: "v1.4.0", Missing: []string{"Retry"}, Shared: 3,
+		}}, nil
+	}
+	var stdout bytes.Buffer
+	stdoutWriter = &stdout
+
+	if err := runReplaces([]string{"-path", "proj", "-json", "example.com/lib@v1.4.0"}); err != nil {
+		t.Fatalf("runReplaces() error = %v", err)
+	}
+	if gotPath != "proj" || gotUpstream["example.com/lib"] != "v1.4.0" {
+		t.Errorf("audited %q with upstream %v", gotPath, gotUpstream)
+	}
+	for _, want := range []string{`"module": "example.com/lib"`, `"divergence": 0.25`, `"missing": [`} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("output missing %s:\n%s", want, stdout.String())
+		}
+	}
+
+	if err := runReplaces([]string{"example.com/lib"}); err == nil {
+		t.Error("runReplaces with an invalid upstream version should fail")
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+	"github.com/devblac/go-semver-audit/internal/report"
+)
+
+// loadResultFn reads a result saved with -save
+var loadResultFn = analyzer.LoadResult
+
+// runReport implements `go-semver-audit report`, which renders a saved
+// result in any report format without analyzing again. The exit code was
+// decided by the run that saved the result, so rendering always succeeds.
+func runReport(args []string) error {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	fs.SetOutput(stderrWriter)
+	in := fs.String("in", "", "Result file written by -save (may also be given as the argument)")
+	format := fs.String("format", formatText, "Comma-separated report formats: text, json, html, lsp, sarif; several formats are written to files named by -output")
+	outputBase := fs.String("output", defaultOutputBase, "Base path of the files written when -format lists several formats")
+	verbose := fs.Bool("v", false, "Verbose output")
+	groupBy := fs.String("group-by", report.GroupBySymbol, "Group text and HTML findings by symbol, file, or package")
+	topFixes := fs.Int("top-fixes", report.DefaultTopFixes, "Number of most-used findings listed under \"What to fix next\" (0 hides the list)")
+	maxFindings := fs.Int("max-findings", 0, "Maximum findings listed per category in text output (0 means unlimited)")
+	colorMode := fs.String("color", "auto", "Highlight signature diffs in text output: auto, always, or never")
+	width := fs.Int("width", terminalWidth(), "Maximum text report width (0 means unlimited, defaults to $COLUMNS)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	path := *in
+	if path == "" && fs.NArg() == 1 {
+		path = fs.Arg(0)
+	}
+	if path == "" || fs.NArg() > 1 || (*in != "" && fs.NArg() > 0) {
+		return fmt.Errorf("usage: go-semver-audit report [-format text] [-output base] [-v] -in result.json")
+	}
+
+	formats, err := outputFormats(config{format: *format})
+	if err != nil {
+		return err
+	}
+	group, err := report.ParseGroupBy(*groupBy)
+	if err != nil {
+		return fmt.Errorf("-group-by: %w", err)
+	}
+	if *topFixes < 0 {
+		return fmt.Errorf("-top-fixes must not be negative")
+	}
+	if *maxFindings < 0 {
+		return fmt.Errorf("-max-findings must not be negative")
+	}
+	color, err := colorOutput(*colorMode, stdoutWriter)
+	if err != nil {
+		return err
+	}
+
+	result, err := loadResultFn(path)
+	if err != nil {
+		return err
+	}
+
+	textOpts := report.TextOptions{Verbose: *verbose, TopFixes: *topFixes, Width: *width, Color: color && len(formats) == 1, GroupBy: group, MaxFindings: *maxFindings}
+	outputs := make(map[string]string, len(formats))
+	for _, f := range formats {
+		outputs[f], err = renderReport(f, result, textOpts)
+		if err != nil {
+			return fmt.Errorf("failed to generate report: %w", err)
+		}
+	}
+	if len(formats) == 1 {
+		fmt.Fprint(stdoutWriter, outputs[formats[0]])
+		return nil
+	}
+	return writeReports(*outputBase, formats, outputs)
+}
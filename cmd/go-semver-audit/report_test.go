@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestRunReport(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+
+	dir := t.TempDir()
+	saved := filepath.Join(dir, "result.json")
+	result := &analyzer.Result{
+		Module:     "example.com/lib",
+		OldVersion: "v1.0.0",
+		NewVersion: "v2.0.0",
+		Changes: &analyzer.Diff{
+			Removed: []analyzer.RemovedSymbol{{Name: "Open", Type: "function"}},
+		},
+	}
+	if err := analyzer.SaveResult(saved, result); err != nil {
+		t.Fatalf("SaveResult() error = %v", err)
+	}
+	var stdout, stderr bytes.Buffer
+	stdoutWriter = &stdout
+	stderrWriter = &stderr
+	exitFunc = func(code int) { t.Errorf("report exited with %d", code) }
+
+	if err := runReport([]string{"-color", "never", saved}); err != nil {
+		t.Fatalf("runReport() error = %v", err)
+	}
+	for _, want := range []string{"Analyzing upgrade: example.com/lib v1.0.0 -> v2.0.0", "  - Open (function)\n"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("text output missing %q:\n%s", want, stdout.String())
+		}
+	}
+
+	base := filepath.Join(dir, "audit")
+	if err := runReport([]string{"-in", saved, "-format", "json,html", "-output", base}); err != nil {
+		t.Fatalf("runReport(-format json,html) error = %v", err)
+	}
+	for _, ext := range []string{".json", ".html"} {
+		data, err := os.ReadFile(base + ext)
+		if err != nil {
+			t.Fatalf("report %s not written: %v", ext, err)
+		}
+		if !strings.Contains(string(data), "Open") {
+			t.Errorf("%s report does not mention Open", ext)
+		}
+	}
+	if !strings.Contains(stderr.String(), "Wrote html report to "+base+".html") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+
+	for _, args := range [][]string{
+		nil,
+		{"-in", saved, saved},
+		{"-format", "pdf", saved},
+		{filepath.Join(dir, "missing.json")},
+	} {
+		if err := runReport(args); err == nil {
+			t.Errorf("runReport(%q) succeeded, want error", args)
+		}
+	}
+}
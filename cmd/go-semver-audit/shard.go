@@ -0,0 +1,24 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// runShardWorker implements the hidden api-shard subcommand that the analyzer
+// starts once per shard when -shards is set
+func runShardWorker(in io.Reader, out io.Writer) error {
+	var req analyzer.ShardRequest
+	if err := json.NewDecoder(in).Decode(&req); err != nil {
+		return fmt.Errorf("invalid shard request: %w", err)
+	}
+
+	api, err := analyzer.LoadShardAPI(req)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(out).Encode(api)
+}
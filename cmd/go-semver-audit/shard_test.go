@@ -0,0 +1,18 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunShardWorkerRejectsInvalidRequest(t *testing.T) {
+	var out bytes.Buffer
+	err := runShardWorker(strings.NewReader("not json"), &out)
+	if err == nil || !strings.Contains(err.Error(), "invalid shard request") {
+		t.Fatalf("runShardWorker() error = %v", err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("runShardWorker() wrote %q on error", out.String())
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+	"github.com/devblac/go-semver-audit/internal/telemetry"
+)
+
+// recordTelemetryFn stores a telemetry event; tests substitute it
+var recordTelemetryFn = func(ev telemetry.Event) error {
+	return telemetry.NewRecorder(os.Getenv).Record(context.Background(), ev)
+}
+
+// recordRun logs the anonymous statistics of a completed audit. Telemetry
+// never fails a run; problems are only mentioned in verbose mode.
+func recordRun(cfg config, formats []string, result *analyzer.Result, elapsed time.Duration) {
+	ev := telemetry.Event{
+		Version:    version,
+		GOOS:       runtime.GOOS,
+		GOARCH:     runtime.GOARCH,
+		DurationMS: elapsed.Milliseconds(),
+		Formats:    formats,
+		Findings:   telemetry.Findings(result),
+		Breaking:   result.HasBreakingChanges(),
+	}
+	flag.Visit(func(f *flag.Flag) {
+		ev.Flags = append(ev.Flags, f.Name)
+	})
+	if err := recordTelemetryFn(ev); err != nil && cfg.verbose {
+		fmt.Fprintf(stderrWriter, "Telemetry: %v\n", err)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+	"github.com/devblac/go-semver-audit/internal/report"
+)
+
+// findUsesFn lists the locations in a project using a symbol
+var findUsesFn = func(projectPath, pkgPath, symbol string) ([]analyzer.Location, error) {
+	a, err := analyzer.New(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	return a.FindUses(pkgPath, symbol)
+}
+
+// runUses implements `go-semver-audit uses`
+func runUses(args []string) error {
+	fs := flag.NewFlagSet("uses", flag.ContinueOnError)
+	fs.SetOutput(stderrWriter)
+	projectPath := fs.String("path", ".", "Path to Go project to search")
+	jsonOutput := fs.Bool("json", false, "Output results as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: go-semver-audit uses [-path dir] [-json] example.com/lib.Symbol")
+	}
+
+	spec := fs.Arg(0)
+	pkgPath, symbol, err := analyzer.ParseSymbol(spec)
+	if err != nil {
+		return err
+	}
+	locations, err := findUsesFn(*projectPath, pkgPath, symbol)
+	if err != nil {
+		return err
+	}
+
+	output := report.FormatUsesText(spec, locations)
+	if *jsonOutput {
+		output, err = report.FormatUsesJSON(spec, locations)
+		if err != nil {
+			return fmt.Errorf("failed to generate report: %w", err)
+		}
+	}
+	fmt.Fprint(stdoutWriter, output)
+	return nil
+}
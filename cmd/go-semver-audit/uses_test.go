@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestRunUses(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+	oldFind := findUsesFn
+	defer func() { findUsesFn = oldFind }()
+
+	var gotPkg, gotSymbol string
+	findUsesFn = func(projectPath, pkgPath, symbol string) ([]analyzer.Location, error) {
+		gotPkg, gotSymbol = pkgPath, symbol
+		return []analyzer.Location{{File: "main.go", Line: 9, Column: 7, Kind: analyzer.UsageCall}}, nil
+	}
+	var stdout bytes.Buffer
+	stdoutWriter = &stdout
+
+	if err := runUses([]string{"example.com/lib.Client.Do"}); err != nil {
+		t.Fatalf("runUses() error = %v", err)
+	}
+	if gotPkg != "example.com/lib" || gotSymbol != "Client.Do" {
+		t.Errorf("searched %q %q", gotPkg, gotSymbol)
+	}
+	for _, want := range []string{"main.go:9:7 (called)\n", "1 use(s) of example.com/lib.Client.Do"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, stdout.String())
+		}
+	}
+
+	if err := runUses(nil); err == nil {
+		t.Error("runUses without a symbol should fail")
+	}
+	if err := runUses([]string{"example.com/lib"}); err == nil {
+		t.Error("runUses with an unqualified symbol should fail")
+	}
+}
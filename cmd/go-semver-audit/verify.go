@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+	"github.com/devblac/go-semver-audit/internal/report"
+)
+
+// verifyMigrationFn checks that a project finished migrating to an upgrade
+var verifyMigrationFn = func(projectPath string, opts analyzer.Options, upgrade *analyzer.Upgrade) (*analyzer.Verification, error) {
+	a, err := analyzer.NewWithOptions(projectPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	return a.Verify(upgrade)
+}
+
+// runVerify implements `go-semver-audit verify`, which runs after a
+// migration and fails while references to the old API remain
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	fs.SetOutput(stderrWriter)
+	projectPath := fs.String("path", ".", "Path to Go project to verify")
+	upgrade := fs.String("upgrade", "", "Module and version migrated to (e.g., github.com/pkg/errors@v0.9.1)")
+	from := fs.String("from", "", "Version the migration started from")
+	jsonOutput := fs.Bool("json", false, "Output results as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *upgrade == "" || *from == "" || fs.NArg() > 0 {
+		return fmt.Errorf("usage: go-semver-audit verify [-path dir] [-json] -from vOld -upgrade module@vNew")
+	}
+
+	moduleUpgrade, err := parseUpgradeFn(*upgrade)
+	if err != nil {
+		return fmt.Errorf("invalid upgrade specification: %w", err)
+	}
+	v, err := verifyMigrationFn(*projectPath, analyzer.Options{FromVersion: *from}, moduleUpgrade)
+	if err != nil {
+		return err
+	}
+
+	output := report.FormatVerifyText(v)
+	if *jsonOutput {
+		output, err = report.FormatVerifyJSON(v)
+		if err != nil {
+			return fmt.Errorf("failed to generate report: %w", err)
+		}
+	}
+	fmt.Fprint(stdoutWriter, output)
+	if !v.Complete() {
+		return fmt.Errorf("migration incomplete: %d straggler(s) left", len(v.Stragglers))
+	}
+	return nil
+}
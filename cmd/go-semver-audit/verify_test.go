@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestRunVerify(t *testing.T) {
+	restore := stubGlobals()
+	defer restore()
+	oldVerify := verifyMigrationFn
+	defer func() { verifyMigrationFn = oldVerify }()
+
+	parseUpgradeFn = func(spec string) (*analyzer.Upgrade, error) {
+		return &analyzer.Upgrade{Module: "example.com/lib", NewVersion: "v2.0.0"}, nil
+	}
+	var stragglers []analyzer.Straggler
+	var gotFrom string
+	verifyMigrationFn = func(projectPath string, opts analyzer.Options, upgrade *analyzer.Upgrade) (*analyzer.Verification, error) {
+		gotFrom = opts.FromVersion
+		return &analyzer.Verification{Module: upgrade.Module, OldVersion: opts.FromVersion, NewVersion: upgrade.NewVersion, Stragglers: stragglers}, nil
+	}
+	var stdout bytes.Buffer
+	stdoutWriter = &stdout
+
+	if err := runVerify([]string{"-from", "v1.0.0", "-upgrade", "example.com/lib@v2.0.0"}); err != nil {
+		t.Fatalf("runVerify() error = %v", err)
+	}
+	if gotFrom != "v1.0.0" || !strings.Contains(stdout.String(), "Migration complete") {
+		t.Errorf("from %q, output:\n%s", gotFrom, stdout.String())
+	}
+
+	stdout.Reset()
+	stragglers = []analyzer.Straggler{{Symbol: "OldFunc", Message: "undefined: lib.OldFunc"}}
+	err := runVerify([]string{"-json", "-from", "v1.0.0", "-upgrade", "example.com/lib@v2.0.0"})
+	if err == nil || !strings.Contains(err.Error(), "1 straggler(s)") {
+		t.Errorf("runVerify() with stragglers error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), `"complete": false`) {
+		t.Errorf("unexpected JSON output:\n%s", stdout.String())
+	}
+
+	if err := runVerify([]string{"-upgrade", "example.com/lib@v2.0.0"}); err == nil {
+		t.Error("runVerify without -from should fail")
+	}
+}
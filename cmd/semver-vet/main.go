@@ -0,0 +1,16 @@
+// Command semver-vet runs the semvercheck analyzer standalone or as a go vet tool:
+//
+//	go-semver-audit -upgrade example.com/lib@v2.0.0 -json > upgrade.json
+//	semver-vet -diff upgrade.json ./...
+//	go vet -vettool=$(which semver-vet) -diff upgrade.json ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/devblac/go-semver-audit/semvercheck"
+)
+
+func main() {
+	singlechecker.Main(semvercheck.Analyzer)
+}
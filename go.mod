@@ -2,6 +2,7 @@ module github.com/devblac/go-semver-audit
 
 go 1.21
 
-require golang.org/x/tools v0.16.0
-
-require golang.org/x/mod v0.14.0 // indirect
+require (
+	golang.org/x/mod v0.14.0
+	golang.org/x/tools v0.16.0
+)
@@ -0,0 +1,98 @@
+package analyzer
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// reexportedModule returns the module whose type an exported alias of the
+// package re-exports, such as go.uber.org/zap for type Logger = zap.Logger.
+// It returns "" for declarations that are not aliases, and for aliases of
+// types of the package's own module or the standard library.
+func reexportedModule(pkg *packages.Package, obj *types.TypeName) string {
+	if !obj.IsAlias() || pkg.Module == nil {
+		return ""
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	target := findImport(pkg, named.Obj().Pkg().Path())
+	if target == nil || target.Module == nil || target.Module.Path == pkg.Module.Path {
+		return ""
+	}
+	return target.Module.Path
+}
+
+// findImport returns the package with an import path among the transitive
+// imports of pkg, or nil
+func findImport(pkg *packages.Package, path string) *packages.Package {
+	seen := make(map[*packages.Package]bool)
+	queue := []*packages.Package{pkg}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, imp := range p.Imports {
+			if imp.PkgPath == path {
+				return imp
+			}
+			if !seen[imp] {
+				seen[imp] = true
+				queue = append(queue, imp)
+			}
+		}
+	}
+	return nil
+}
+
+// reexportedTypes maps the types that the target packages re-export through
+// exported aliases to the alias names, so that uses of zap.Logger methods
+// through lib.Logger are recorded as Logger.Method. Only aliases of types
+// from outside the target packages are mapped.
+func reexportedTypes(pkgs []*packages.Package, target func(*packages.Package) bool) map[*types.TypeName]string {
+	aliases := make(map[*types.TypeName]string)
+	seen := make(map[*packages.Package]bool)
+	for _, pkg := range pkgs {
+		for _, imp := range pkg.Imports {
+			if seen[imp] || !target(imp) || imp.Types == nil {
+				continue
+			}
+			seen[imp] = true
+			scope := imp.Types.Scope()
+			for _, name := range scope.Names() {
+				obj, ok := scope.Lookup(name).(*types.TypeName)
+				if !ok || !obj.Exported() || !obj.IsAlias() {
+					continue
+				}
+				named, ok := obj.Type().(*types.Named)
+				if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg() == imp.Types {
+					continue
+				}
+				aliases[named.Obj()] = name
+			}
+		}
+	}
+	return aliases
+}
+
+// typeName returns the name changes to a type of the target packages are
+// keyed by: its own, or the alias a target package re-exports it under.
+// It reports false for other types.
+func (u *Usage) typeName(named *types.Named) (string, bool) {
+	obj := named.Obj()
+	if obj.Pkg() != nil && u.Imports[obj.Pkg().Path()] {
+		return obj.Name(), true
+	}
+	name, ok := u.aliases[obj]
+	return name, ok
+}
+
+// viaModule returns the module a changed symbol is re-exported from,
+// preferring the new version's
+func viaModule(oldVia, newVia string) string {
+	if newVia != "" {
+		return newVia
+	}
+	return oldVia
+}
@@ -0,0 +1,109 @@
+package analyzer
+
+import (
+	"go/types"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// aliasVersion type-checks a zap package and a lib package re-exporting its
+// Logger, as they would load from two modules
+func aliasVersion(t *testing.T, zapSrc string) *packages.Package {
+	t.Helper()
+	zap := checkSource(t, "go.uber.org/zap", zapSrc, nil)
+	zap.Module = &packages.Module{Path: "go.uber.org/zap"}
+	lib := checkSource(t, "example.com/lib", `package lib
+
+import "go.uber.org/zap"
+
+// Logger is zap's logger.
+type Logger = zap.Logger
+
+// Own is declared by lib itself.
+type Own struct{}
+
+// Local aliases a type of the same module.
+type Local = Own
+`, map[string]*types.Package{"go.uber.org/zap": zap.Types})
+	lib.Module = &packages.Module{Path: "example.com/lib"}
+	lib.Imports = map[string]*packages.Package{"go.uber.org/zap": zap}
+	return lib
+}
+
+func TestExtractAPI_ReexportedAlias(t *testing.T) {
+	api := extractAPI([]*packages.Package{aliasVersion(t, `package zap
+
+type Logger struct{ Name string }
+
+func (l *Logger) Info(msg string) {}
+`)})
+
+	if got := api.Types["Logger"]; got == nil || got.ViaModule != "go.uber.org/zap" || got.PkgPath != "example.com/lib" {
+		t.Fatalf("Types[Logger] = %+v, want it re-exported via go.uber.org/zap", got)
+	}
+	if got := api.Funcs["Logger.Info"]; got == nil || got.ViaModule != "go.uber.org/zap" {
+		t.Errorf("Funcs[Logger.Info] = %+v, want it re-exported via go.uber.org/zap", got)
+	}
+	if got := api.Types["Local"]; got == nil || got.ViaModule != "" {
+		t.Errorf("Types[Local] = %+v, want no via module for an alias within the module", got)
+	}
+	if got := api.Types["Own"]; got == nil || got.ViaModule != "" {
+		t.Errorf("Types[Own] = %+v, want no via module", got)
+	}
+}
+
+func TestDiffAPIs_ReexportedAliasChange(t *testing.T) {
+	oldAPI := extractAPI([]*packages.Package{aliasVersion(t, `package zap
+
+type Logger struct{ Name string }
+
+func (l *Logger) Info(msg string) {}
+`)})
+	newAPI := extractAPI([]*packages.Package{aliasVersion(t, `package zap
+
+type Logger struct{}
+
+func (l *Logger) Info(msg string, fields ...any) {}
+`)})
+	usage := &Usage{Symbols: map[string][]Location{
+		"Logger.Info": {{File: "main.go", Line: 5}},
+		"Logger.Name": {{File: "main.go", Line: 6, Kind: UsageKeyedField}},
+	}}
+
+	diff := diffAPIs(oldAPI, newAPI, usage)
+	if len(diff.Changed) != 1 || diff.Changed[0].ViaModule != "go.uber.org/zap" {
+		t.Errorf("Changed = %+v, want Logger.Info via go.uber.org/zap", diff.Changed)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Name != "Logger.Name" || diff.Removed[0].ViaModule != "go.uber.org/zap" {
+		t.Errorf("Removed = %+v, want field Logger.Name via go.uber.org/zap", diff.Removed)
+	}
+}
+
+func TestFindUsage_ReexportedAlias(t *testing.T) {
+	lib := aliasVersion(t, `package zap
+
+type Logger struct{ Name string }
+
+func (l *Logger) Info(msg string) {}
+`)
+	app := checkSource(t, "example.com/app", `package app
+
+import "example.com/lib"
+
+func run(l *lib.Logger) {
+	l.Info("hi")
+	_ = lib.Logger{Name: "app"}
+}
+`, map[string]*types.Package{"example.com/lib": lib.Types})
+	app.Imports = map[string]*packages.Package{"example.com/lib": lib}
+
+	a := &Analyzer{pkgs: []*packages.Package{app}}
+	usage := a.findUsage("example.com/lib")
+	if got := usage.Symbols["Logger.Info"]; len(got) != 1 || got[0].Line != 6 {
+		t.Errorf("Logger.Info uses = %+v, want line 6", got)
+	}
+	if got := usage.Symbols["Logger.Name"]; len(got) != 1 || got[0].Kind != UsageKeyedField {
+		t.Errorf("Logger.Name uses = %+v, want a keyed field", got)
+	}
+}
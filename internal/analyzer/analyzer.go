@@ -2,27 +2,215 @@ package analyzer
 
 import (
 	"fmt"
+	"go/ast"
 	"go/types"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"golang.org/x/tools/go/packages"
 )
 
 // Allow overriding in tests
 var (
-	packagesLoad        = packages.Load
+	packagesLoad        = loadIsolated
 	packagesPrintErrors = packages.PrintErrors
 )
 
-// Analyzer performs static analysis on Go projects
+// Analyzer performs static analysis on Go projects. It is safe for
+// concurrent use: every exported method runs on its own copy of the
+// analyzer, and project loads are serialized and never modified once loaded.
 type Analyzer struct {
 	projectPath string
-	pkgs        []*packages.Package
+	opts        Options
+	state       *projectState       // project load shared with concurrent calls
+	pkgs        []*packages.Package // project packages of the current call
+	warnings    []Warning           // collected during the current call
+	modules     map[string]string   // dependency versions from Options.Modules
+	platform    string              // GOOS/GOARCH module APIs are loaded for, empty for the host
+}
+
+// projectState is the latest project load of an analyzer. Loaded packages are
+// only ever replaced by a newer load, so calls keep a consistent snapshot.
+type projectState struct {
+	mu      sync.Mutex
+	pkgs    []*packages.Package
+	modules map[string]string
+}
+
+// Options configures optional analysis behavior
+type Options struct {
+	// NewDependency allows auditing a module the project does not require yet.
+	// The old API is treated as empty, so every exported symbol is reported as added.
+	NewDependency bool `json:"new_dependency,omitempty"`
+
+	// IgnoreReplace diffs the version required in go.mod even when a replace
+	// directive points the module at a fork or local checkout.
+	IgnoreReplace bool `json:"ignore_replace,omitempty"`
+
+	// Footprint measures download size, package count, and module requirement
+	// changes between the two versions, and which of the project's direct
+	// requirements the new version raises.
+	Footprint bool `json:"footprint,omitempty"`
+
+	// BinaryImpact is a main package to build before and after the upgrade
+	// to report the binary size delta. Empty disables the check.
+	BinaryImpact string `json:"binary_impact,omitempty"`
+
+	// Bench is a package pattern whose benchmarks run against both versions.
+	// Empty disables benchmarking.
+	Bench string `json:"bench,omitempty"`
+
+	// RunTests runs the project's tests against the upgraded dependency and
+	// reports tests that start failing.
+	RunTests bool `json:"run_tests,omitempty"`
+
+	// IncludeTestPackages keeps test-only, example, and testdata packages of
+	// the dependency in the API diff. They are filtered out by default.
+	IncludeTestPackages bool `json:"include_test_packages,omitempty"`
+
+	// Shims is a project directory where compatibility shims for changed
+	// functions are written. Empty disables shim generation.
+	Shims string `json:"shims,omitempty"`
+
+	// Fix rewrites call sites of changed functions that can be migrated
+	// mechanically: functions that gained a leading context.Context parameter
+	// or a new error result. It also stubs the methods that project types
+	// lack to keep satisfying interfaces that gained them.
+	Fix bool `json:"fix,omitempty"`
+
+	// FixContext is the context Fix passes to functions that gained a
+	// leading context.Context: FixContextTODO (the default) or
+	// FixContextBackground.
+	FixContext string `json:"fix_context,omitempty"`
+
+	// DryRun previews the edits of Fix and Shims as unified diffs in
+	// Result.Fixes.Diffs and Result.Shims.Diff without writing any file.
+	DryRun bool `json:"dry_run,omitempty"`
+
+	// AllowDirty lets Fix and Shims edit a project whose git tree has
+	// uncommitted changes, which they refuse by default so every edit can be
+	// reviewed and reverted on its own.
+	AllowDirty bool `json:"allow_dirty,omitempty"`
+
+	// FixPolicy is how Fix receives the error a function newly returns:
+	// FixPolicyTODO (the default) assigns it to err and checks it under a
+	// TODO comment, FixPolicyDiscard assigns it to _.
+	FixPolicy string `json:"fix_policy,omitempty"`
+
+	// Shards splits loading a module's API across this many worker processes
+	// to bound memory on very large modules. Values below 2 load in-process.
+	// Workers re-execute the running binary with the ShardCommand subcommand
+	// and call LoadShardAPI, so this is only for the go-semver-audit CLI and
+	// programs that dispatch ShardCommand the same way; other embedders
+	// should leave it unset.
+	Shards int `json:"shards,omitempty"`
+
+	// Provenance records the environment and module checksums the analysis
+	// ran with, so the run can be reproduced and verified later.
+	Provenance bool `json:"provenance,omitempty"`
+
+	// Docs compares the doc comments of used symbols and reports deprecations
+	// and changed error or panic wording.
+	Docs bool `json:"docs,omitempty"`
+
+	// FailFast stops at the first used, stable breaking change and returns a
+	// minimal result holding only that finding, skipping every optional check.
+	FailFast bool `json:"fail_fast,omitempty"`
+
+	// Examples embeds usage examples from the new version for changed,
+	// moved, and removed symbols.
+	Examples bool `json:"examples,omitempty"`
+
+	// Hints suggests functions added in the new version that may replace
+	// functions the project wrote itself, matched by name and signature.
+	Hints bool `json:"hints,omitempty"`
+
+	// TODOs reports the project's TODO and FIXME comments that mention the
+	// upgraded module and name, or share a word with, a symbol the new
+	// version adds.
+	TODOs bool `json:"todos,omitempty"`
+
+	// DeadCode loads the project with its tests to find the functions no
+	// entry point reaches, downgrades findings only those functions use to
+	// warnings, and suggests deleting them instead of migrating them.
+	DeadCode bool `json:"dead_code,omitempty"`
+
+	// ImportGraph records which project packages import the upgraded module,
+	// directly or through other project packages, for graph visualizations.
+	ImportGraph bool `json:"import_graph,omitempty"`
+
+	// Platforms diffs the dependency's API once per GOOS/GOARCH pair, such as
+	// linux/amd64, and qualifies findings that only occur on some of them.
+	Platforms []string `json:"platforms,omitempty"`
+
+	// Directories counts the Go files in every project directory, for the
+	// HTML report's heatmap of findings.
+	Directories bool `json:"directories,omitempty"`
+
+	// Severities remaps the severity of finding categories, such as
+	// interface_added_method to error or signature_param_rename to warning.
+	// Only findings at error severity count as breaking.
+	Severities map[string]string `json:"severities,omitempty"`
+
+	// RequireAtLeast treats the upgrade version as a minimum the project must
+	// meet. Projects at or above it are not audited; otherwise the result
+	// reports the gap and the breaking cost of upgrading to the minimum.
+	RequireAtLeast bool `json:"require_at_least,omitempty"`
+
+	// Modules names the file declaring dependency versions for projects built
+	// without a go.mod, such as Bazel workspaces: a MODULE.bazel, a Gazelle
+	// go_deps.bzl, or a list of "module version" lines. Loaded packages are
+	// attributed to these modules.
+	Modules string `json:"modules,omitempty"`
+
+	// PackagesDriver is a GOPACKAGESDRIVER binary, such as the rules_go
+	// driver, that loads the project's packages in place of the go command.
+	PackagesDriver string `json:"packages_driver,omitempty"`
+
+	// ModFlag is the -mod mode module versions are loaded with: ModReadonly
+	// or ModMod. Empty keeps the -mod of the user's GOFLAGS, or readonly.
+	// Other GOFLAGS, such as -tags, are always kept.
+	ModFlag string `json:"mod_flag,omitempty"`
+
+	// AllowErrors analyzes projects that do not compile, such as projects in
+	// the middle of a migration, with best-effort type information. Findings
+	// in packages with errors are marked approximate and the load errors are
+	// listed in Result.LoadErrors.
+	AllowErrors bool `json:"allow_errors,omitempty"`
+
+	// FromVersion is the version the upgrade is diffed from, such as the last
+	// tagged release when go.mod pins a pseudo-version. It takes precedence
+	// over both the version go.mod requires and any replace directive.
+	FromVersion string `json:"from_version,omitempty"`
+
+	// AdapterThreshold suggests wrapping the dependency in an internal
+	// adapter package when more project packages than this use it. Zero
+	// disables the advisory.
+	AdapterThreshold int `json:"adapter_threshold,omitempty"`
+
+	// SumDB records whether the new version was verified against the
+	// checksum database or fetched with verification bypassed by GOSUMDB,
+	// GONOSUMDB, or GOPRIVATE.
+	SumDB bool `json:"sumdb,omitempty"`
+
+	// Loader, when set, replaces packages.Load for every package load of the
+	// analyzer. Sharded loads (see Shards) run in worker processes and
+	// always use the go command.
+	Loader Loader `json:"-"`
+
+	// Cache, when set, reuses module APIs and unchanged project loads across
+	// analyzers, such as the requests served by a daemon.
+	Cache *Cache `json:"-"`
 }
 
 // New creates a new Analyzer for the given project path
 func New(projectPath string) (*Analyzer, error) {
+	return NewWithOptions(projectPath, Options{})
+}
+
+// NewWithOptions creates a new Analyzer for the given project path and options
+func NewWithOptions(projectPath string, opts Options) (*Analyzer, error) {
 	absPath, err := filepath.Abs(projectPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve project path: %w", err)
@@ -32,53 +220,374 @@ func New(projectPath string) (*Analyzer, error) {
 		return nil, fmt.Errorf("project path does not exist: %s", absPath)
 	}
 
+	if err := ValidateSeverities(opts.Severities); err != nil {
+		return nil, err
+	}
+	if err := ValidatePlatforms(opts.Platforms); err != nil {
+		return nil, err
+	}
+
 	return &Analyzer{
 		projectPath: absPath,
+		opts:        opts,
+		state:       &projectState{},
 	}, nil
 }
 
-// Analyze performs the dependency upgrade analysis
+// call returns the copy of the analyzer an exported method runs on, with its
+// own warnings and project snapshot
+func (a *Analyzer) call() *Analyzer {
+	c := *a
+	c.warnings = nil
+	if c.state == nil {
+		c.state = &projectState{} // analyzers built without NewWithOptions
+	}
+	return &c
+}
+
+// Analyze performs the dependency upgrade analysis. The upgrade is not modified.
 func (a *Analyzer) Analyze(upgrade *Upgrade) (*Result, error) {
+	a = a.call()
+	result, err := a.analyze(upgrade)
+	if err != nil {
+		return nil, err
+	}
+	result.Warnings = append(result.Warnings, a.warnings...)
+	if result.Changes != nil {
+		Classifier{Severities: a.opts.Severities}.Apply(result.Changes)
+	}
+	if a.opts.AllowErrors {
+		result.LoadErrors = projectLoadErrors(a.pkgs)
+	}
+	return result, nil
+}
+
+// analyze runs the audit for Analyze, which attaches the warnings it records
+func (a *Analyzer) analyze(upgrade *Upgrade) (*Result, error) {
+	// Work on a copy, since the module path is resolved below
+	resolved := *upgrade
+	upgrade = &resolved
+
+	// Refuse edits before spending time on the analysis
+	if a.opts.editsCode() {
+		if err := a.checkClean(); err != nil {
+			return nil, err
+		}
+	}
+
 	// Load the project packages
 	if err := a.loadProject(); err != nil {
 		return nil, fmt.Errorf("failed to load project: %w", err)
 	}
 
+	// go@1.N audits the standard library of a toolchain upgrade
+	if upgrade.Module == ToolchainModule {
+		if a.opts.RequireAtLeast {
+			return nil, fmt.Errorf("minimum version checks are not supported for %s", ToolchainModule)
+		}
+		return a.analyzeToolchain(upgrade)
+	}
+
+	// Accept a package path in place of its module
+	upgrade.Module = a.resolveModulePath(upgrade.Module)
+
 	// Get current version from project dependencies
 	currentVersion, err := a.getCurrentVersion(upgrade.Module)
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine current version: %w", err)
+	if err != nil && !a.opts.NewDependency {
+		return nil, fmt.Errorf("failed to determine current version: %w (use -new to audit a dependency the project does not require yet)", err)
+	}
+	// An explicit from-version takes precedence over go.mod
+	var requiredVersion string
+	if from := a.opts.FromVersion; from != "" {
+		if err == nil && currentVersion != from {
+			requiredVersion = currentVersion
+		}
+		currentVersion, err = from, nil
 	}
+	newDependency := err != nil
 	upgrade.OldVersion = currentVersion
 
+	// A project at or above the minimum version has nothing to close
+	var floor *Floor
+	if a.opts.RequireAtLeast {
+		if newDependency {
+			return nil, fmt.Errorf("cannot check the minimum version of %s: the project does not require it", upgrade.Module)
+		}
+		floor, err = checkFloor(currentVersion, upgrade.NewVersion)
+		if err != nil {
+			return nil, err
+		}
+		if floor.Satisfied {
+			return &Result{
+				Module:     upgrade.Module,
+				OldVersion: currentVersion,
+				NewVersion: currentVersion,
+				Changes:    &Diff{},
+				Floor:      floor,
+			}, nil
+		}
+	}
+
+	// Tool dependencies have no API the project calls, so compare module metadata instead
+	if !newDependency && a.isToolDependency(upgrade.Module) {
+		result, err := a.analyzeToolDependency(upgrade)
+		if err != nil {
+			return nil, err
+		}
+		result.RequiredVersion = requiredVersion
+		a.checkModuleStatus(result, upgrade, newDependency)
+		result.Floor = floor
+		if err := a.measureUpgrade(result, upgrade, newDependency, nil, nil); err != nil {
+			return nil, err
+		}
+		if _, err := a.testUpgrade(result, upgrade); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	// Commands cannot be imported, so compare their module metadata instead
+	if len(a.importedPackages(upgrade.Module)) == 0 && a.isMainOnlyModule(upgrade.Module, upgrade.NewVersion) {
+		result, err := a.analyzeMainOnlyModule(upgrade, newDependency)
+		if err != nil {
+			return nil, err
+		}
+		result.RequiredVersion = requiredVersion
+		a.checkModuleStatus(result, upgrade, newDependency)
+		result.Floor = floor
+		if err := a.measureUpgrade(result, upgrade, newDependency, nil, nil); err != nil {
+			return nil, err
+		}
+		if _, err := a.testUpgrade(result, upgrade); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
 	// Load API surface for old and new versions
-	oldAPI, err := a.loadModuleAPI(upgrade.Module, upgrade.OldVersion)
+	// Without both APIs there is nothing to diff, so when a version cannot be
+	// downloaded fall back to listing the project's uses of the module
+	oldAPI, replacement, err := a.loadOldAPI(upgrade, newDependency)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load old API: %w", err)
+		err = fmt.Errorf("failed to load old API: %w", err)
+	}
+	var newAPI *API
+	if err == nil {
+		newAPI, err = a.loadModuleAPI(upgrade.Module, upgrade.NewVersion)
+		if err != nil {
+			err = fmt.Errorf("failed to load new API: %w", err)
+		}
+	}
+	if err != nil && !isUnreachable(err) {
+		return nil, err
 	}
-
-	newAPI, err := a.loadModuleAPI(upgrade.Module, upgrade.NewVersion)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load new API: %w", err)
+		usage := a.findUsage(upgrade.Module)
+		coupling := measureCoupling(usage)
+		return &Result{
+			Module:          upgrade.Module,
+			OldVersion:      upgrade.OldVersion,
+			NewVersion:      upgrade.NewVersion,
+			RequiredVersion: requiredVersion,
+			NewDependency:   newDependency,
+			Changes:         &Diff{},
+			Coupling:        coupling,
+			Adapter:         adviseAdapter(usage, coupling, a.opts.AdapterThreshold),
+			Floor:           floor,
+			UsageOnly:       usageOnly(err.Error(), usage),
+		}, nil
 	}
 
-	// Find usage of the dependency in the project
+	// Find usage of the dependency in the project, leaving out copies of it
 	usage := a.findUsage(upgrade.Module)
+	copies, err := a.findDependencyCopies(upgrade.Module)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look for copies of %s: %w", upgrade.Module, err)
+	}
+	a.excludeCopies(usage, copies)
 
-	// Diff the APIs
+	// A yes/no answer only needs one breaking change
+	if a.opts.FailFast {
+		if diff := firstBreaking(oldAPI, newAPI, usage); diff != nil {
+			return &Result{
+				Module:          upgrade.Module,
+				OldVersion:      upgrade.OldVersion,
+				NewVersion:      upgrade.NewVersion,
+				RequiredVersion: requiredVersion,
+				NewDependency:   newDependency,
+				Replacement:     replacement,
+				Changes:         diff,
+				StoppedEarly:    true,
+				Floor:           floor,
+			}, nil
+		}
+	}
+
+	// Diff the APIs, per platform when requested
 	diff := diffAPIs(oldAPI, newAPI, usage)
+	if len(a.opts.Platforms) > 0 {
+		diff, err = a.diffPlatforms(upgrade, newDependency, usage)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	return &Result{
-		Module:     upgrade.Module,
-		OldVersion: upgrade.OldVersion,
-		NewVersion: upgrade.NewVersion,
-		Changes:    diff,
-		UnusedDeps: nil, // Filled by separate call if requested
-	}, nil
+	coupling := measureCoupling(usage)
+	result := &Result{
+		Module:          upgrade.Module,
+		OldVersion:      upgrade.OldVersion,
+		NewVersion:      upgrade.NewVersion,
+		RequiredVersion: requiredVersion,
+		NewDependency:   newDependency,
+		Replacement:     replacement,
+		Changes:         diff,
+		Risk:            assessRisk(newAPI, usage, diff),
+		Coupling:        coupling,
+		Adapter:         adviseAdapter(usage, coupling, a.opts.AdapterThreshold),
+		Copies:          copies,
+		UnusedDeps:      nil, // Filled by separate call if requested
+		Floor:           floor,
+	}
+	a.checkModuleStatus(result, upgrade, newDependency)
+	result.DuplicateMajors = a.findDuplicateMajors(upgrade.Module)
+
+	// A new dependency has no old version to diff against, so report what it brings along instead
+	if newDependency {
+		result.Requirements, err = a.moduleRequirements(upgrade.Module, upgrade.NewVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load requirements: %w", err)
+		}
+	}
+
+	if a.opts.Docs {
+		result.DocChanges = diffDocs(oldAPI, newAPI, usage)
+	}
+
+	if a.opts.Examples {
+		result.Examples, err = a.findExamples(upgrade.Module, upgrade.NewVersion, diff)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find examples: %w", err)
+		}
+	}
+
+	if a.opts.Hints {
+		result.Hints = a.adoptionHints(newAPI, diff)
+	}
+
+	if a.opts.TODOs {
+		result.TODOs = a.todoMatches(upgrade.Module, diff)
+	}
+
+	if a.opts.DeadCode {
+		result.DeadCode, err = a.findDeadCode(diff)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find dead code: %w", err)
+		}
+	}
+
+	if a.opts.ImportGraph {
+		result.ImportGraph = a.buildImportGraph(upgrade.Module)
+	}
+
+	if a.opts.Directories {
+		result.Directories = a.directoryStats()
+	}
+
+	if err := a.measureUpgrade(result, upgrade, newDependency, oldAPI, newAPI); err != nil {
+		return nil, err
+	}
+
+	// Tests run before Shims and Fix edit the project, so the run with the
+	// current version builds the project as it is; the edits are tested
+	// afterwards
+	baseline, err := a.testUpgrade(result, upgrade)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.opts.Shims != "" {
+		result.Shims, err = a.writeShims(a.opts.Shims, result, oldAPI, newAPI)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write shims: %w", err)
+		}
+	}
+
+	if a.opts.Fix {
+		result.Fixes, err = a.fixSites(result.Changes, newAPI)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fix call sites: %w", err)
+		}
+		if err := a.checkFixedTests(result.Fixes, upgrade, baseline); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// measureUpgrade adds what is measured for every kind of upgrade, whether or
+// not its API was diffed: footprint, binary impact, benchmarks, provenance,
+// and checksum verification. oldAPI and newAPI are nil without a diff.
+func (a *Analyzer) measureUpgrade(result *Result, upgrade *Upgrade, newDependency bool, oldAPI, newAPI *API) error {
+	var err error
+	if a.opts.Footprint {
+		result.Footprint, err = a.measureFootprint(upgrade.Module, upgrade.OldVersion, upgrade.NewVersion, oldAPI.withoutNil(), newAPI.withoutNil())
+		if err != nil {
+			return fmt.Errorf("failed to measure footprint: %w", err)
+		}
+	}
+
+	if a.opts.BinaryImpact != "" {
+		result.BinaryImpact, err = a.measureBinaryImpact(a.opts.BinaryImpact, upgrade.Module, upgrade.NewVersion)
+		if err != nil {
+			return fmt.Errorf("failed to measure binary impact: %w", err)
+		}
+	}
+
+	if a.opts.Bench != "" {
+		result.Benchmarks, err = a.compareBenchmarks(a.opts.Bench, upgrade.Module, upgrade.NewVersion)
+		if err != nil {
+			return fmt.Errorf("failed to compare benchmarks: %w", err)
+		}
+	}
+
+	// Provenance is bookkeeping, so failing to record it does not fail the audit
+	if a.opts.Provenance {
+		if result.Provenance, err = a.recordProvenance(upgrade, newDependency); err != nil {
+			a.warn(WarnProvenance, "failed to record provenance: %v", err)
+		}
+	}
+
+	if a.opts.SumDB {
+		result.SumDB, err = a.checkSumDB(upgrade)
+		if err != nil {
+			return fmt.Errorf("failed to check checksum verification: %w", err)
+		}
+	}
+	return nil
+}
+
+// testUpgrade runs the project's tests against the new version with RunTests,
+// returning the failures of the current version
+func (a *Analyzer) testUpgrade(result *Result, upgrade *Upgrade) (map[TestFailure]bool, error) {
+	if !a.opts.RunTests {
+		return nil, nil
+	}
+	var err error
+	var baseline map[TestFailure]bool
+	result.TestFailures, baseline, err = a.runProjectTests(upgrade.Module, upgrade.NewVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run tests: %w", err)
+	}
+	return baseline, nil
 }
 
 // FindUnusedDependencies identifies dependencies that are no longer used
 func (a *Analyzer) FindUnusedDependencies() ([]string, error) {
+	a = a.call()
+	if len(a.pkgs) == 0 {
+		a.pkgs, a.modules = a.state.loaded()
+	}
 	if len(a.pkgs) == 0 {
 		if err := a.loadProject(); err != nil {
 			return nil, err
@@ -103,10 +612,14 @@ func (a *Analyzer) FindUnusedDependencies() ([]string, error) {
 		}
 	}
 
+	// Tool dependencies are used through tools.go even though no package imports
+	// them; detection is best effort and never fails the unused check
+	tools, _ := a.toolModules()
+
 	// Identify unused dependencies
 	var unused []string
 	for _, dep := range dependencies {
-		if !imported[dep] {
+		if !imported[dep] && !tools[dep] {
 			unused = append(unused, dep)
 		}
 	}
@@ -114,25 +627,74 @@ func (a *Analyzer) FindUnusedDependencies() ([]string, error) {
 	return unused, nil
 }
 
-// loadProject loads the Go packages for the project
+// loaded returns the latest project load, or nil when there is none yet
+func (s *projectState) loaded() ([]*packages.Package, map[string]string) {
+	if s == nil {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pkgs, s.modules
+}
+
+// loadProject loads the Go packages for the project. Concurrent loads of the
+// same analyzer are serialized.
 func (a *Analyzer) loadProject() error {
+	if a.state == nil {
+		a.state = &projectState{}
+	}
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+
+	// Build systems without a go.mod declare module versions separately
+	if a.opts.Modules != "" && a.state.modules == nil {
+		modules, err := loadModuleList(a.opts.Modules)
+		if err != nil {
+			return err
+		}
+		a.state.modules = modules
+	}
+	a.modules = a.state.modules
+
+	var fingerprint string
+	if a.opts.Cache != nil {
+		var err error
+		fingerprint, err = projectFingerprint(a.projectPath)
+		if err == nil {
+			if pkgs, ok := a.opts.Cache.project(a.projectPath, fingerprint); ok {
+				a.pkgs, a.state.pkgs = pkgs, pkgs
+				return nil
+			}
+		}
+	}
+
 	cfg := &packages.Config{
 		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports |
 			packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax |
 			packages.NeedTypesInfo | packages.NeedModule,
 		Dir: a.projectPath,
 	}
+	if a.opts.PackagesDriver != "" {
+		cfg.Env = append(os.Environ(), "GOPACKAGESDRIVER="+a.opts.PackagesDriver)
+	}
 
-	pkgs, err := packagesLoad(cfg, "./...")
+	pkgs, err := a.load(cfg, "./...")
 	if err != nil {
 		return fmt.Errorf("failed to load packages: %w", err)
 	}
 
-	if packagesPrintErrors(pkgs) > 0 {
-		return fmt.Errorf("packages contain errors")
+	if !a.opts.AllowErrors && packagesPrintErrors(pkgs) > 0 {
+		return fmt.Errorf("packages contain errors (use -allow-errors to analyze them anyway)")
+	}
+
+	if a.modules != nil {
+		assignModules(pkgs, a.modules)
 	}
 
-	a.pkgs = pkgs
+	a.pkgs, a.state.pkgs = pkgs, pkgs
+	if a.opts.Cache != nil && fingerprint != "" {
+		a.opts.Cache.storeProject(a.projectPath, fingerprint, pkgs)
+	}
 	return nil
 }
 
@@ -151,6 +713,20 @@ func (a *Analyzer) getCurrentVersion(module string) (string, error) {
 		}
 	}
 
+	// Modules only imported from files excluded by build constraints (such as
+	// tools.go) never appear in the loaded packages, so fall back to go.mod
+	// or the build system's module list
+	if version, ok := a.modules[module]; ok {
+		return version, nil
+	}
+	if f, err := a.projectModFile(); err == nil {
+		for _, req := range f.Require {
+			if req.Mod.Path == module {
+				return req.Mod.Version, nil
+			}
+		}
+	}
+
 	return "", fmt.Errorf("module %s not found in project dependencies", module)
 }
 
@@ -169,17 +745,108 @@ func (a *Analyzer) getDependencyModules(pkg *packages.Package) []*packages.Modul
 	return modules
 }
 
+// loadCurrentAPI loads the API the project builds against today, honoring any
+// replace directive for the module in the project's go.mod
+func (a *Analyzer) loadCurrentAPI(module, version string) (*API, *Replacement, error) {
+	replacement := a.findReplacement(module, version)
+	if replacement == nil || a.opts.IgnoreReplace {
+		if replacement != nil {
+			replacement.Ignored = true
+		}
+		api, err := a.loadModuleAPI(module, version)
+		return api, replacement, err
+	}
+
+	if replacement.Version == "" {
+		api, err := a.loadDirAPI(replacement.Dir)
+		return api, replacement, err
+	}
+
+	api, err := a.loadModuleAPI(replacement.Path, replacement.Version)
+	return api, replacement, err
+}
+
+// loadOldAPI loads the API an upgrade is diffed from: none for a new
+// dependency, Options.FromVersion when set, and the current version otherwise
+func (a *Analyzer) loadOldAPI(upgrade *Upgrade, newDependency bool) (*API, *Replacement, error) {
+	switch {
+	case newDependency:
+		return emptyAPI(), nil, nil
+	case a.opts.FromVersion != "":
+		api, err := a.loadModuleAPI(upgrade.Module, upgrade.OldVersion)
+		return api, nil, err
+	default:
+		return a.loadCurrentAPI(upgrade.Module, upgrade.OldVersion)
+	}
+}
+
+// findReplacement returns the replace directive that applies to module@version, if any
+func (a *Analyzer) findReplacement(module, version string) *Replacement {
+	f, err := a.projectModFile()
+	if err != nil {
+		return nil
+	}
+
+	for _, rep := range f.Replace {
+		if rep.Old.Path != module || (rep.Old.Version != "" && rep.Old.Version != version) {
+			continue
+		}
+		replacement := &Replacement{
+			Path:    rep.New.Path,
+			Version: rep.New.Version,
+		}
+		// Local directory replacements are relative to the go.mod that declares them
+		if rep.New.Version == "" {
+			replacement.Dir = rep.New.Path
+			if !filepath.IsAbs(replacement.Dir) {
+				replacement.Dir = filepath.Join(filepath.Dir(f.Syntax.Name), replacement.Dir)
+			}
+		}
+		return replacement
+	}
+
+	return nil
+}
+
 // loadModuleAPI loads the exported API surface for a specific module version
 func (a *Analyzer) loadModuleAPI(module, version string) (*API, error) {
+	if a.opts.Cache == nil {
+		return a.loadModuleAPIUncached(module, version)
+	}
+
+	_, scope := a.apiPatterns(module, version)
+	key := apiKey(module, version, a.opts.IncludeTestPackages) + scopeKey(scope)
+	if a.platform != "" {
+		key += "|platform=" + a.platform
+	}
+	if api, ok := a.opts.Cache.api(key); ok {
+		return api, nil
+	}
+	a.warn(WarnCacheMiss, "%s@%s was not cached and was loaded from scratch", module, version)
+	api, err := a.loadModuleAPIUncached(module, version)
+	if err != nil {
+		return nil, err
+	}
+	a.opts.Cache.storeAPI(key, api)
+	return api, nil
+}
+
+// loadModuleAPIUncached loads a module version's API with the go command
+func (a *Analyzer) loadModuleAPIUncached(module, version string) (*API, error) {
+	if a.opts.Shards > 1 {
+		return a.loadModuleAPISharded(module, version)
+	}
+
 	// Load the module at the specified version
 	cfg := &packages.Config{
 		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax |
 			packages.NeedTypesInfo,
-		Env: append(os.Environ(), "GOFLAGS=-mod=readonly"),
+		Env: platformEnv(moduleCacheEnv(a.opts.ModFlag), a.platform),
 	}
 
+	patterns, _ := a.apiPatterns(module, version)
 	modulePattern := fmt.Sprintf("%s@%s", module, version)
-	pkgs, err := packagesLoad(cfg, modulePattern)
+	pkgs, err := a.load(cfg, patterns...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load module %s: %w", modulePattern, err)
 	}
@@ -188,31 +855,88 @@ func (a *Analyzer) loadModuleAPI(module, version string) (*API, error) {
 		return nil, fmt.Errorf("no packages found for module %s", modulePattern)
 	}
 
-	// Extract exported symbols
-	api := &API{
-		Funcs:      make(map[string]*Function),
-		Types:      make(map[string]*Type),
-		Interfaces: make(map[string]*Interface),
+	pkgs = a.filterPackages(pkgs)
+	a.checkLoadedPackages(modulePattern, pkgs)
+	return extractAPI(pkgs), nil
+}
+
+// loadDirAPI loads the exported API surface of a module checked out in a local directory
+func (a *Analyzer) loadDirAPI(dir string) (*API, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax |
+			packages.NeedTypesInfo,
+		Dir: dir,
+	}
+	if a.platform != "" {
+		cfg.Env = platformEnv(os.Environ(), a.platform)
+	}
+
+	pkgs, err := a.load(cfg, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load module in %s: %w", dir, err)
+	}
+
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no packages found in %s", dir)
 	}
 
+	pkgs = a.filterPackages(pkgs)
+	a.checkLoadedPackages(dir, pkgs)
+	return extractAPI(pkgs), nil
+}
+
+// ExtractAPI collects the exported symbols of packages loaded with at least
+// NeedName, NeedTypes, NeedSyntax, and NeedTypesInfo, such as by a Loader
+func ExtractAPI(pkgs []*packages.Package) *API {
+	return extractAPI(pkgs)
+}
+
+// extractAPI collects the exported symbols of the loaded packages
+func extractAPI(pkgs []*packages.Package) *API {
+	api := emptyAPI()
+
 	for _, pkg := range pkgs {
 		if pkg.Types == nil {
 			continue
 		}
+		api.Packages = append(api.Packages, pkg.PkgPath)
+		api.fset = pkg.Fset
+		if isGeneratedPackage(pkg) {
+			api.Generated[pkg.PkgPath] = true
+		}
+		docs := collectDocs(pkg)
+		pkgUnstable := packageUnstable(pkg)
+		unstable := func(obj types.Object) bool {
+			return pkgUnstable || isUnstableDoc(docs[obj.Pos()])
+		}
+
 		scope := pkg.Types.Scope()
 		for _, name := range scope.Names() {
 			obj := scope.Lookup(name)
 			if !obj.Exported() {
 				continue
 			}
+			api.noteOwner(name, pkg.PkgPath)
 
 			switch obj := obj.(type) {
+			case *types.Const:
+				api.Consts[obj.Name()] = &Const{
+					Name:    obj.Name(),
+					Type:    obj.Type().String(),
+					Value:   obj.Val().ExactString(),
+					PkgPath: pkg.PkgPath,
+					Doc:     docs[obj.Pos()],
+				}
+
 			case *types.Func:
 				sig := obj.Type().(*types.Signature)
 				api.Funcs[obj.Name()] = &Function{
 					Name:      obj.Name(),
-					Signature: sig.String(),
+					Signature: signatureOf(sig),
 					PkgPath:   pkg.PkgPath,
+					Unstable:  unstable(obj),
+					Doc:       docs[obj.Pos()],
+					obj:       obj,
 				}
 
 			case *types.TypeName:
@@ -220,60 +944,146 @@ func (a *Analyzer) loadModuleAPI(module, version string) (*API, error) {
 				if !ok {
 					continue
 				}
+				via := reexportedModule(pkg, obj)
 
 				// Check if it's an interface
 				iface, isInterface := named.Underlying().(*types.Interface)
 				if isInterface {
-					methods := make([]string, iface.NumMethods())
-					for i := 0; i < iface.NumMethods(); i++ {
-						methods[i] = iface.Method(i).String()
-					}
+					methods, embedded := interfaceMethods(named, iface)
 					api.Interfaces[obj.Name()] = &Interface{
-						Name:    obj.Name(),
-						Methods: methods,
-						PkgPath: pkg.PkgPath,
+						Name:     obj.Name(),
+						Methods:  methods,
+						Embedded: embedded,
+						obj:      obj,
+						PkgPath:  pkg.PkgPath,
+						Unstable: unstable(obj),
+						Doc:      docs[obj.Pos()],
+
+						ViaModule: via,
 					}
 				} else {
 					// Regular type
 					api.Types[obj.Name()] = &Type{
-						Name:    obj.Name(),
-						Kind:    named.Underlying().String(),
-						PkgPath: pkg.PkgPath,
+						Name:       obj.Name(),
+						Kind:       named.Underlying().String(),
+						TypeParams: typeParamsString(named.TypeParams()),
+						Fields:     structFields(named),
+						PkgPath:    pkg.PkgPath,
+						Unstable:   unstable(obj),
+						Doc:        docs[obj.Pos()],
+						ViaModule:  via,
+						obj:        obj,
 					}
 
-					// Add methods for this type
-					for i := 0; i < named.NumMethods(); i++ {
-						method := named.Method(i)
-						if method.Exported() {
-							key := fmt.Sprintf("%s.%s", obj.Name(), method.Name())
-							sig := method.Type().(*types.Signature)
-							api.Funcs[key] = &Function{
-								Name:      key,
-								Signature: sig.String(),
-								PkgPath:   pkg.PkgPath,
-								IsMethod:  true,
-							}
+					// Add the complete method set of *T, so methods promoted from
+					// embedded types are diffed even when those types are unexported
+					mset := types.NewMethodSet(types.NewPointer(named))
+					for i := 0; i < mset.Len(); i++ {
+						method := mset.At(i).Obj().(*types.Func)
+						if !method.Exported() {
+							continue
+						}
+						key := fmt.Sprintf("%s.%s", obj.Name(), method.Name())
+						sig := method.Type().(*types.Signature)
+						fn := &Function{
+							Name:      key,
+							Signature: signatureOf(sig),
+							PkgPath:   pkg.PkgPath,
+							IsMethod:  true,
+							Unstable:  unstable(obj) || isUnstableDoc(docs[method.Pos()]),
+							Doc:       docs[method.Pos()],
+							ViaModule: via,
+							obj:       method,
+						}
+						if len(mset.At(i).Index()) > 1 {
+							fn.PromotedFrom = receiverName(sig)
 						}
+						api.Funcs[key] = fn
 					}
 				}
 			}
 		}
 	}
 
-	return api, nil
+	return api
+}
+
+// receiverName returns the name of the type a method is declared on
+func receiverName(sig *types.Signature) string {
+	if sig.Recv() == nil {
+		return ""
+	}
+	t := sig.Recv().Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return t.String()
+}
+
+// emptyAPI returns an API surface with no symbols
+func emptyAPI() *API {
+	return &API{
+		Funcs:      make(map[string]*Function),
+		Types:      make(map[string]*Type),
+		Interfaces: make(map[string]*Interface),
+		Consts:     make(map[string]*Const),
+		Generated:  make(map[string]bool),
+		Ambiguous:  make(map[string]bool),
+	}
+}
+
+// owner returns the package exporting a top-level name of the API, or ""
+func (api *API) owner(name string) string {
+	if fn := api.Funcs[name]; fn != nil {
+		return fn.PkgPath
+	}
+	if t := api.Types[name]; t != nil {
+		return t.PkgPath
+	}
+	if iface := api.Interfaces[name]; iface != nil {
+		return iface.PkgPath
+	}
+	if c := api.Consts[name]; c != nil {
+		return c.PkgPath
+	}
+	return ""
+}
+
+// noteOwner marks a name ambiguous when a package other than pkgPath already
+// exports it, since the maps are keyed by bare name
+func (api *API) noteOwner(name, pkgPath string) {
+	if owner := api.owner(name); owner != "" && owner != pkgPath {
+		if api.Ambiguous == nil {
+			api.Ambiguous = make(map[string]bool)
+		}
+		api.Ambiguous[name] = true
+	}
 }
 
 // findUsage identifies which exported symbols from the module are used in the project
 func (a *Analyzer) findUsage(module string) *Usage {
+	return a.findUsageOf(func(imp *packages.Package) bool {
+		return imp.Module != nil && imp.Module.Path == module
+	})
+}
+
+// findUsageOf identifies the exported symbols the project uses from the
+// imported packages matching target
+func (a *Analyzer) findUsageOf(target func(imp *packages.Package) bool) *Usage {
 	usage := &Usage{
 		Symbols: make(map[string][]Location),
 		Imports: make(map[string]bool),
+		types:   projectTypes(a.pkgs),
+		aliases: reexportedTypes(a.pkgs, target),
 	}
 
 	for _, pkg := range a.pkgs {
-		// Check if this package imports the target module
+		// Check if this package imports the target
 		for _, imp := range pkg.Imports {
-			if imp.Module != nil && imp.Module.Path == module {
+			if target(imp) {
 				usage.Imports[imp.PkgPath] = true
 			}
 		}
@@ -283,6 +1093,8 @@ func (a *Analyzer) findUsage(module string) *Usage {
 			continue
 		}
 
+		kinds := usageKinds(pkg)
+		approximate := a.opts.AllowErrors && approximateUsage(pkg)
 		for ident, obj := range pkg.TypesInfo.Uses {
 			if obj == nil || !obj.Exported() {
 				continue
@@ -309,16 +1121,154 @@ func (a *Analyzer) findUsage(module string) *Usage {
 				symbolName := obj.Name()
 				pos := pkg.Fset.Position(ident.Pos())
 				usage.Symbols[symbolName] = append(usage.Symbols[symbolName], Location{
-					File: pos.Filename,
-					Line: pos.Line,
+					File:        pos.Filename,
+					Line:        pos.Line,
+					Column:      pos.Column,
+					Kind:        kinds[ident],
+					Approximate: approximate,
 				})
 			}
 		}
+
+		// Method calls are also recorded as Type.Method of the receiver they
+		// are selected on, which is how the API names them even when promoted
+		for expr, sel := range pkg.TypesInfo.Selections {
+			if sel.Kind() == types.FieldVal || !sel.Obj().Exported() {
+				continue
+			}
+			recv := sel.Recv()
+			if ptr, ok := recv.(*types.Pointer); ok {
+				recv = ptr.Elem()
+			}
+			named, ok := recv.(*types.Named)
+			if !ok {
+				continue
+			}
+			typeName, ok := usage.typeName(named)
+			if !ok {
+				continue
+			}
+			symbolName := typeName + "." + sel.Obj().Name()
+			pos := pkg.Fset.Position(expr.Sel.Pos())
+			usage.Symbols[symbolName] = append(usage.Symbols[symbolName], Location{
+				File:        pos.Filename,
+				Line:        pos.Line,
+				Column:      pos.Column,
+				Kind:        kinds[expr.Sel],
+				Approximate: approximate,
+			})
+		}
+
+		recordInstances(usage, pkg, approximate)
+		recordKeyedFields(usage, pkg, approximate)
 	}
 
+	for name, locations := range usage.Symbols {
+		usage.Symbols[name] = dedupeLocations(locations)
+	}
 	return usage
 }
 
+// usageKinds finds the role of identifiers whose breakage differs from plain
+// references: calls, conversions, assertions, composite literals, embedding,
+// implementation assertions, and type declarations
+func usageKinds(pkg *packages.Package) map[*ast.Ident]string {
+	kinds := make(map[*ast.Ident]string)
+	mark := func(expr ast.Expr, kind string) {
+		// Unwrap pointers, parentheses, and generic instantiations
+		for unwrapped := false; !unwrapped; {
+			switch e := expr.(type) {
+			case *ast.StarExpr:
+				expr = e.X
+			case *ast.ParenExpr:
+				expr = e.X
+			case *ast.IndexExpr:
+				expr = e.X
+			case *ast.IndexListExpr:
+				expr = e.X
+			default:
+				unwrapped = true
+			}
+		}
+		switch e := expr.(type) {
+		case *ast.Ident:
+			kinds[e] = kind
+		case *ast.SelectorExpr:
+			kinds[e.Sel] = kind
+		}
+	}
+
+	for _, file := range pkg.Syntax {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.TypeAssertExpr:
+				if n.Type != nil { // nil in x.(type) switches, handled below
+					mark(n.Type, UsageAssertion)
+				}
+			case *ast.TypeSwitchStmt:
+				for _, stmt := range n.Body.List {
+					for _, expr := range stmt.(*ast.CaseClause).List {
+						mark(expr, UsageAssertion)
+					}
+				}
+			case *ast.CallExpr:
+				if tv, ok := pkg.TypesInfo.Types[n.Fun]; ok && tv.IsType() {
+					mark(n.Fun, UsageConversion)
+				} else {
+					mark(n.Fun, UsageCall)
+				}
+			case *ast.CompositeLit:
+				if n.Type != nil { // nil for elided element types
+					mark(n.Type, UsageCompositeLit)
+				}
+			case *ast.StructType:
+				markEmbedded(n.Fields, mark)
+			case *ast.InterfaceType:
+				markEmbedded(n.Methods, mark)
+			case *ast.TypeSpec:
+				mark(n.Type, UsageTypeDecl)
+			case *ast.ValueSpec:
+				if implementsAssertion(pkg.TypesInfo, n) {
+					mark(n.Type, UsageImplemented)
+				}
+			}
+			return true
+		})
+	}
+	return kinds
+}
+
+// markEmbedded marks the embedded fields of a struct or interface
+func markEmbedded(fields *ast.FieldList, mark func(ast.Expr, string)) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		if len(field.Names) == 0 {
+			mark(field.Type, UsageEmbedded)
+		}
+	}
+}
+
+// implementsAssertion reports whether a declaration such as
+// var _ I = (*T)(nil) assigns a concrete value to an interface type
+func implementsAssertion(info *types.Info, spec *ast.ValueSpec) bool {
+	if spec.Type == nil {
+		return false
+	}
+	if t := info.TypeOf(spec.Type); t == nil || !types.IsInterface(t) {
+		return false
+	}
+	for _, value := range spec.Values {
+		if t := info.TypeOf(value); t != nil && !types.IsInterface(t) {
+			if basic, ok := t.(*types.Basic); !ok || basic.Kind() != types.UntypedNil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // getDirectDependencies retrieves direct dependencies from go.mod
 func (a *Analyzer) getDirectDependencies() ([]string, error) {
 	// This is a simplified implementation
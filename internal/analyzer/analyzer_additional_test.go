@@ -2,12 +2,16 @@ package analyzer
 
 import (
 	"errors"
+	"fmt"
 	"go/ast"
+	"go/parser"
 	"go/token"
 	"go/types"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 
 	"golang.org/x/tools/go/packages"
@@ -204,6 +208,8 @@ func TestLoadModuleAPI(t *testing.T) {
 }
 
 func TestAnalyzeWithMockLoader(t *testing.T) {
+	defer mockLatestGoMod(t, "module example.com/lib\n")()
+
 	const module = "example.com/lib"
 
 	// Packages representing the user's project
@@ -239,9 +245,9 @@ func TestAnalyzeWithMockLoader(t *testing.T) {
 		switch patterns[0] {
 		case "./...":
 			return []*packages.Package{projectPkg}, nil
-		case module + "@v1.0.0":
+		case module + "/...@v1.0.0":
 			return []*packages.Package{oldAPIPkg}, nil
-		case module + "@v2.0.0":
+		case module + "/...@v2.0.0":
 			return []*packages.Package{newAPIPkg}, nil
 		default:
 			return nil, nil
@@ -269,6 +275,118 @@ func TestAnalyzeWithMockLoader(t *testing.T) {
 	}
 }
 
+func TestAnalyzeFromVersion(t *testing.T) {
+	defer mockLatestGoMod(t, "module example.com/lib\n")()
+
+	const module = "example.com/lib"
+	projectPkg := buildUsagePackage(module)
+	oldAPIPkg := buildAPIPackageWithChanges(module, apiDefinition{
+		funcs: map[string]*types.Signature{"OldFunc": newSignature(nil, nil)},
+	})
+	newAPIPkg := buildAPIPackageWithChanges(module, apiDefinition{})
+
+	var loaded []string
+	restore := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		loaded = append(loaded, patterns[0])
+		switch patterns[0] {
+		case "./...":
+			return []*packages.Package{projectPkg}, nil
+		case module + "/...@v0.9.0", module + "/...@v1.0.0":
+			return []*packages.Package{oldAPIPkg}, nil
+		case module + "/...@v2.0.0":
+			return []*packages.Package{newAPIPkg}, nil
+		default:
+			return nil, nil
+		}
+	})
+	defer restore()
+
+	a := &Analyzer{projectPath: ".", opts: Options{FromVersion: "v0.9.0"}}
+	result, err := a.Analyze(&Upgrade{Module: module, NewVersion: "v2.0.0"})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if result.OldVersion != "v0.9.0" || result.RequiredVersion != "v1.0.0" {
+		t.Errorf("Analyze() versions = %s (go.mod %s), want v0.9.0 (go.mod v1.0.0)", result.OldVersion, result.RequiredVersion)
+	}
+	if len(result.Changes.Removed) != 1 || result.Changes.Removed[0].Name != "OldFunc" {
+		t.Errorf("Analyze() removed = %+v, want OldFunc from v0.9.0", result.Changes.Removed)
+	}
+	for _, pattern := range loaded {
+		if pattern == module+"/...@v1.0.0" {
+			t.Errorf("Analyze() loaded the go.mod version despite FromVersion: %v", loaded)
+		}
+	}
+
+	a = &Analyzer{projectPath: ".", opts: Options{FromVersion: "v1.0.0"}}
+	result, err = a.Analyze(&Upgrade{Module: module, NewVersion: "v2.0.0"})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if result.RequiredVersion != "" {
+		t.Errorf("RequiredVersion = %q, want none when FromVersion matches go.mod", result.RequiredVersion)
+	}
+}
+
+func TestAnalyzeConcurrentCalls(t *testing.T) {
+	defer mockLatestGoMod(t, "module example.com/lib\n")()
+
+	const module = "example.com/lib"
+	projectPkg := buildUsagePackage(module)
+	oldAPIPkg := buildAPIPackageWithChanges(module, apiDefinition{
+		funcs: map[string]*types.Signature{"OldFunc": newSignature(nil, nil)},
+	})
+	newAPIPkg := buildAPIPackageWithChanges(module, apiDefinition{})
+
+	restore := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		switch patterns[0] {
+		case "./...":
+			return []*packages.Package{projectPkg}, nil
+		case module + "/...@v1.0.0":
+			return []*packages.Package{oldAPIPkg}, nil
+		case module + "/...@v2.0.0":
+			return []*packages.Package{newAPIPkg}, nil
+		default:
+			return nil, nil
+		}
+	})
+	defer restore()
+
+	a, err := NewWithOptions(".", Options{Cache: NewCache()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	upgrade := &Upgrade{Module: module, NewVersion: "v2.0.0"}
+
+	const calls = 8
+	results := make([]*Result, calls)
+	errs := make([]error, calls)
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = a.Analyze(upgrade)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range results {
+		if errs[i] != nil {
+			t.Fatalf("Analyze() call %d error = %v", i, errs[i])
+		}
+		if len(results[i].Changes.Removed) != 1 || results[i].Changes.Removed[0].Name != "OldFunc" {
+			t.Errorf("Analyze() call %d removed = %+v, want OldFunc", i, results[i].Changes.Removed)
+		}
+		if len(results[i].Warnings) > 2 {
+			t.Errorf("Analyze() call %d warnings = %+v, want at most one cache miss per version", i, results[i].Warnings)
+		}
+	}
+	if upgrade.OldVersion != "" {
+		t.Errorf("Analyze() modified the upgrade: %+v", upgrade)
+	}
+}
+
 func TestAnalyzeFailsWhenProjectCannotLoad(t *testing.T) {
 	restoreLoad := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
 		return nil, errors.New("load failure")
@@ -305,6 +423,116 @@ func TestAnalyzeFailsWhenModuleVersionMissing(t *testing.T) {
 	}
 }
 
+func TestAnalyzeNewDependency(t *testing.T) {
+	const module = "example.com/lib"
+
+	restoreLoad := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		switch patterns[0] {
+		case "./...":
+			return []*packages.Package{{PkgPath: "example.com/app", Imports: map[string]*packages.Package{}}}, nil
+		case module + "/...@v1.0.0":
+			return []*packages.Package{buildAPIPackage(module)}, nil
+		default:
+			return nil, nil
+		}
+	})
+	defer restoreLoad()
+	restorePrint := mockPackagesPrintErrors(func(pkgs []*packages.Package) int { return 0 })
+	defer restorePrint()
+	restoreCmd := mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+		return []byte(`{"Path":"example.com/lib","Version":"v1.0.0","GoMod":"lib.mod"}`), nil
+	})
+	defer restoreCmd()
+	restoreRead := mockReadFile(map[string]string{
+		"lib.mod": "module example.com/lib\n\nrequire example.com/dep v1.0.0\n",
+	})
+	defer restoreRead()
+
+	a := &Analyzer{projectPath: "proj", opts: Options{NewDependency: true}}
+	result, err := a.Analyze(&Upgrade{Module: module, NewVersion: "v1.0.0"})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if !result.NewDependency {
+		t.Fatalf("Analyze() expected NewDependency result")
+	}
+	if result.HasBreakingChanges() {
+		t.Fatalf("Analyze() new dependency should not report breaking changes")
+	}
+	if len(result.Changes.Added) == 0 {
+		t.Fatalf("Analyze() expected the adopted API to be reported as added")
+	}
+	if len(result.Requirements) != 1 || result.Requirements[0].Path != "example.com/dep" {
+		t.Fatalf("Analyze() requirements = %+v", result.Requirements)
+	}
+}
+
+func TestFindReplacement(t *testing.T) {
+	restore := mockReadFile(map[string]string{
+		filepath.Join("proj", "go.mod"): "module example.com/app\n\n" +
+			"replace example.com/lib => github.com/ourfork/lib v1.3.0\n\n" +
+			"replace example.com/local v1.0.0 => ../local\n",
+	})
+	defer restore()
+
+	a := &Analyzer{projectPath: "proj"}
+
+	rep := a.findReplacement("example.com/lib", "v1.2.0")
+	if rep == nil || rep.Path != "github.com/ourfork/lib" || rep.Version != "v1.3.0" {
+		t.Fatalf("findReplacement() = %+v, want fork replacement", rep)
+	}
+
+	rep = a.findReplacement("example.com/local", "v1.0.0")
+	if rep == nil || rep.Dir != filepath.Join("proj", "..", "local") {
+		t.Fatalf("findReplacement() = %+v, want local directory replacement", rep)
+	}
+
+	if rep := a.findReplacement("example.com/local", "v2.0.0"); rep != nil {
+		t.Fatalf("findReplacement() should ignore version-specific replacements for other versions, got %+v", rep)
+	}
+	if rep := a.findReplacement("example.com/other", "v1.0.0"); rep != nil {
+		t.Fatalf("findReplacement() = %+v, want nil", rep)
+	}
+}
+
+func TestLoadCurrentAPIUsesFork(t *testing.T) {
+	restoreRead := mockReadFile(map[string]string{
+		filepath.Join("proj", "go.mod"): "module example.com/app\n\nreplace example.com/lib => github.com/ourfork/lib v1.3.0\n",
+	})
+	defer restoreRead()
+
+	var loaded []string
+	restoreLoad := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		loaded = append(loaded, patterns[0])
+		return []*packages.Package{buildAPIPackage("example.com/lib")}, nil
+	})
+	defer restoreLoad()
+
+	a := &Analyzer{projectPath: "proj"}
+	_, rep, err := a.loadCurrentAPI("example.com/lib", "v1.2.0")
+	if err != nil {
+		t.Fatalf("loadCurrentAPI() error = %v", err)
+	}
+	if rep == nil || rep.Ignored {
+		t.Fatalf("loadCurrentAPI() replacement = %+v, want applied replacement", rep)
+	}
+
+	a.opts.IgnoreReplace = true
+	_, rep, err = a.loadCurrentAPI("example.com/lib", "v1.2.0")
+	if err != nil {
+		t.Fatalf("loadCurrentAPI() error = %v", err)
+	}
+	if rep == nil || !rep.Ignored {
+		t.Fatalf("loadCurrentAPI() replacement = %+v, want ignored replacement", rep)
+	}
+
+	want := []string{"github.com/ourfork/lib/...@v1.3.0", "example.com/lib/...@v1.2.0"}
+	if !reflect.DeepEqual(loaded, want) {
+		t.Fatalf("loadCurrentAPI() loaded %v, want %v", loaded, want)
+	}
+}
+
 func TestLoadProjectFailsOnPackageErrors(t *testing.T) {
 	restoreLoad := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
 		return []*packages.Package{{PkgPath: "example.com/app"}}, nil
@@ -481,3 +709,218 @@ func newSignatureWithRecv(recv *types.Var, params []*types.Var, results []*types
 	}
 	return types.NewSignatureType(recv, nil, nil, ptuple, rtuple, false)
 }
+
+func TestExtractAPI_PromotedMethods(t *testing.T) {
+	const oldSrc = `package lib
+
+type inner struct{}
+
+func (inner) Do(n int) error { return nil }
+
+type Outer struct{ inner }
+
+func (*Outer) Own() {}
+`
+	const newSrc = `package lib
+
+type inner struct{}
+
+func (inner) Do(n int, force bool) error { return nil }
+
+type Outer struct{ inner }
+
+func (*Outer) Own() {}
+`
+	oldAPI := extractAPI([]*packages.Package{checkSource(t, "example.com/lib", oldSrc, nil)})
+	newAPI := extractAPI([]*packages.Package{checkSource(t, "example.com/lib", newSrc, nil)})
+
+	do, ok := newAPI.Funcs["Outer.Do"]
+	if !ok {
+		t.Fatalf("promoted method Outer.Do missing from API: %v", newAPI.Funcs)
+	}
+	if do.PromotedFrom != "inner" || !do.IsMethod {
+		t.Errorf("Outer.Do = %+v, want a method promoted from inner", do)
+	}
+	if own := newAPI.Funcs["Outer.Own"]; own == nil || own.PromotedFrom != "" {
+		t.Errorf("Outer.Own = %+v, want a declared method", own)
+	}
+
+	usage := &Usage{Symbols: map[string][]Location{"Outer.Do": {{File: "main.go", Line: 3}}}}
+	diff := diffAPIs(oldAPI, newAPI, usage)
+	if len(diff.Changed) != 1 || diff.Changed[0].Name != "Outer.Do" || diff.Changed[0].PromotedFrom != "inner" {
+		t.Fatalf("Changed = %+v, want Outer.Do promoted from inner", diff.Changed)
+	}
+}
+
+func TestFindUsage_MethodsByReceiver(t *testing.T) {
+	lib := checkSource(t, "example.com/lib", `package lib
+
+type inner struct{}
+
+func (inner) Do() {}
+
+type Outer struct{ inner }
+`, nil)
+	app := checkSource(t, "example.com/app", `package app
+
+import "example.com/lib"
+
+func run(o *lib.Outer) { o.Do() }
+`, map[string]*types.Package{"example.com/lib": lib.Types})
+	app.Imports = map[string]*packages.Package{
+		"example.com/lib": {PkgPath: "example.com/lib", Module: &packages.Module{Path: "example.com/lib"}},
+	}
+
+	a := &Analyzer{pkgs: []*packages.Package{app}}
+	usage := a.findUsage("example.com/lib")
+	locations := usage.Symbols["Outer.Do"]
+	if len(locations) != 1 || locations[0].Line != 5 {
+		t.Fatalf("Symbols[Outer.Do] = %v, want the call on line 5", locations)
+	}
+}
+
+// checkSource type-checks a single-file package the way packages.Load would
+func checkSource(t *testing.T, pkgPath, src string, deps map[string]*types.Package) *packages.Package {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, pkgPath+".go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		Instances:  make(map[*ast.Ident]types.Instance),
+	}
+	conf := types.Config{Importer: importerFunc(func(path string) (*types.Package, error) {
+		if pkg, ok := deps[path]; ok {
+			return pkg, nil
+		}
+		return nil, fmt.Errorf("unknown import %s", path)
+	})}
+	pkg, err := conf.Check(pkgPath, fset, []*ast.File{file}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &packages.Package{
+		PkgPath:   pkgPath,
+		Fset:      fset,
+		Syntax:    []*ast.File{file},
+		Types:     pkg,
+		TypesInfo: info,
+	}
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+func TestFindUsage_AssertionsAndConversions(t *testing.T) {
+	lib := checkSource(t, "example.com/lib", `package lib
+
+type Handler interface{ Serve() }
+
+type Config map[string]string
+
+type Options struct{}
+`, nil)
+	app := checkSource(t, "example.com/app", `package app
+
+import "example.com/lib"
+
+func run(x interface{}, m map[string]string) {
+	_ = x.(lib.Handler)
+	_ = lib.Config(m)
+	switch x.(type) {
+	case *lib.Options:
+	}
+	var _ lib.Handler
+}
+`, map[string]*types.Package{"example.com/lib": lib.Types})
+	app.Imports = map[string]*packages.Package{
+		"example.com/lib": {PkgPath: "example.com/lib", Module: &packages.Module{Path: "example.com/lib"}},
+	}
+
+	a := &Analyzer{pkgs: []*packages.Package{app}}
+	usage := a.findUsage("example.com/lib")
+
+	kinds := func(name string) []string {
+		var got []string
+		for _, loc := range usage.Symbols[name] {
+			got = append(got, fmt.Sprintf("%d:%s", loc.Line, loc.Kind))
+		}
+		sort.Strings(got)
+		return got
+	}
+	if got, want := kinds("Handler"), []string{"11:", "6:assertion"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Handler kinds = %v, want %v", got, want)
+	}
+	if got, want := kinds("Config"), []string{"7:conversion"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Config kinds = %v, want %v", got, want)
+	}
+	if got, want := kinds("Options"), []string{"9:assertion"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Options kinds = %v, want %v", got, want)
+	}
+}
+
+func TestFindUsage_Roles(t *testing.T) {
+	lib := checkSource(t, "example.com/lib", `package lib
+
+type Handler interface{ Serve() }
+
+type Base struct{ ID int }
+
+type Client struct{}
+
+func (c *Client) Do() {}
+
+func New() *Client { return nil }
+`, nil)
+	app := checkSource(t, "example.com/app", `package app
+
+import "example.com/lib"
+
+type server struct{ lib.Base }
+
+func (s *server) Serve() {}
+
+var _ lib.Handler = (*server)(nil)
+
+type myClient lib.Client
+
+func run() {
+	c := lib.New()
+	c.Do()
+	_ = lib.Base{ID: 1}
+}
+`, map[string]*types.Package{"example.com/lib": lib.Types})
+	app.Imports = map[string]*packages.Package{
+		"example.com/lib": {PkgPath: "example.com/lib", Module: &packages.Module{Path: "example.com/lib"}},
+	}
+
+	a := &Analyzer{pkgs: []*packages.Package{app}}
+	usage := a.findUsage("example.com/lib")
+
+	kinds := func(name string) []string {
+		var got []string
+		for _, loc := range usage.Symbols[name] {
+			got = append(got, fmt.Sprintf("%d:%s", loc.Line, loc.Kind))
+		}
+		sort.Strings(got)
+		return got
+	}
+	tests := map[string][]string{
+		"Base":      {"16:composite literal", "5:embedded"},
+		"Handler":   {"9:implemented"},
+		"Client":    {"11:type declaration"},
+		"New":       {"14:called"},
+		"Client.Do": {"15:called"},
+	}
+	for name, want := range tests {
+		if got := kinds(name); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s kinds = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package analyzer
+
+import "strings"
+
+// Behavior changes a changed signature is labeled with
+const (
+	// BehaviorPanicToError marks functions that now return an error where
+	// they used to panic
+	BehaviorPanicToError = "panic-to-error"
+)
+
+// panicToErrorPhrases are doc comment wordings announcing that a function
+// reports failures as errors instead of panicking
+var panicToErrorPhrases = []string{
+	"no longer panics",
+	"instead of panicking",
+	"rather than panicking",
+	"instead of a panic",
+}
+
+// behaviorChange labels a signature change whose calls likely need more
+// than a mechanical update, or returns "" when there is nothing to say
+func behaviorChange(oldFunc, newFunc *Function) string {
+	if returnsError(newFunc.Signature.Text) && !returnsError(oldFunc.Signature.Text) && mentionsPanicToError(newFunc.Doc) {
+		return BehaviorPanicToError
+	}
+	return ""
+}
+
+// mentionsPanicToError reports whether a doc comment says the function
+// stopped panicking
+func mentionsPanicToError(doc string) bool {
+	doc = strings.ToLower(strings.Join(strings.Fields(doc), " "))
+	for _, phrase := range panicToErrorPhrases {
+		if strings.Contains(doc, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// returnsError reports whether the last result of a function signature,
+// as printed by go/types, is an error
+func returnsError(sig string) bool {
+	results := strings.TrimSpace(signatureResults(sig))
+	if strings.HasPrefix(results, "(") {
+		results = strings.TrimSuffix(strings.TrimPrefix(results, "("), ")")
+	}
+	if i := strings.LastIndex(results, ","); i >= 0 {
+		results = results[i+1:]
+	}
+	fields := strings.Fields(results)
+	return len(fields) > 0 && fields[len(fields)-1] == "error"
+}
+
+// signatureResults returns the text after the parameter list of a signature
+func signatureResults(sig string) string {
+	start := strings.Index(sig, "(")
+	if start < 0 {
+		return ""
+	}
+	depth := 0
+	for i := start; i < len(sig); i++ {
+		switch sig[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return sig[i+1:]
+			}
+		}
+	}
+	return ""
+}
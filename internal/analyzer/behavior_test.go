@@ -0,0 +1,44 @@
+package analyzer
+
+import "testing"
+
+func TestReturnsError(t *testing.T) {
+	tests := map[string]bool{
+		"func() error":                       true,
+		"func(s string) (int, error)":        true,
+		"func() (n int, err error)":          true,
+		"func(err error)":                    false,
+		"func(fn func() error) int":          false,
+		"func() (error, int)":                false,
+		"func(opts ...func() error) []error": false,
+	}
+	for sig, want := range tests {
+		if got := returnsError(sig); got != want {
+			t.Errorf("returnsError(%q) = %v, want %v", sig, got, want)
+		}
+	}
+}
+
+func TestDiffAPIs_PanicToError(t *testing.T) {
+	oldAPI := emptyAPI()
+	oldAPI.Funcs["MustParse"] = &Function{Name: "MustParse", Signature: Signature{Text: "func(s string) int"}, Doc: "MustParse panics on invalid input.\n"}
+	oldAPI.Funcs["Load"] = &Function{Name: "Load", Signature: Signature{Text: "func()"}}
+	newAPI := emptyAPI()
+	newAPI.Funcs["MustParse"] = &Function{Name: "MustParse", Signature: Signature{Text: "func(s string) (int, error)"}, Doc: "MustParse no longer\npanics on invalid input.\n"}
+	newAPI.Funcs["Load"] = &Function{Name: "Load", Signature: Signature{Text: "func() error"}}
+	usage := &Usage{Symbols: map[string][]Location{
+		"MustParse": {{File: "main.go", Line: 3}},
+		"Load":      {{File: "main.go", Line: 4}},
+	}}
+
+	diff := diffAPIs(oldAPI, newAPI, usage)
+	if len(diff.Changed) != 2 {
+		t.Fatalf("Changed = %+v", diff.Changed)
+	}
+	if got := diff.Changed[0]; got.Name != "Load" || got.BehaviorChange != "" {
+		t.Errorf("Load without a doc note = %+v", got)
+	}
+	if got := diff.Changed[1]; got.Name != "MustParse" || got.BehaviorChange != BehaviorPanicToError {
+		t.Errorf("MustParse = %+v, want labeled %s", got, BehaviorPanicToError)
+	}
+}
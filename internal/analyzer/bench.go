@@ -0,0 +1,149 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// benchCount is how many times each benchmark runs per version
+	benchCount = 5
+	// benchRegressionPercent is the slowdown above which a benchmark is flagged
+	benchRegressionPercent = 10.0
+)
+
+// compareBenchmarks runs the project's benchmarks against the current and the
+// upgraded dependency version and compares their ns/op
+func (a *Analyzer) compareBenchmarks(pattern, module, version string) ([]BenchmarkDelta, error) {
+	args := []string{"test", "-run", "^$", "-bench", ".", "-count", strconv.Itoa(benchCount), pattern}
+
+	out, err := runGoCommand(a.projectPath, args...)
+	if err != nil {
+		return nil, fmt.Errorf("benchmarks failed with current version: %w", err)
+	}
+	oldRuns := parseBenchOutput(out)
+
+	overlay, err := a.newUpgradeOverlay(module, version)
+	if err != nil {
+		return nil, err
+	}
+	defer overlay.Close()
+
+	args = append([]string{"test", overlay.flag()}, args[1:]...)
+	out, err = runGoCommand(a.projectPath, args...)
+	if err != nil {
+		return nil, fmt.Errorf("benchmarks failed with %s: %w", version, err)
+	}
+	newRuns := parseBenchOutput(out)
+
+	var deltas []BenchmarkDelta
+	for name, oldSamples := range oldRuns {
+		newSamples, ok := newRuns[name]
+		if !ok {
+			continue
+		}
+		deltas = append(deltas, compareSamples(name, oldSamples, newSamples))
+	}
+	sort.Slice(deltas, func(i, j int) bool { return deltas[i].Name < deltas[j].Name })
+	return deltas, nil
+}
+
+// compareSamples summarizes two sets of ns/op samples. A slowdown counts as a
+// regression only when it exceeds the threshold and the sample ranges do not
+// overlap, which filters out most run-to-run noise.
+func compareSamples(name string, oldSamples, newSamples []float64) BenchmarkDelta {
+	oldMean, newMean := mean(oldSamples), mean(newSamples)
+	delta := BenchmarkDelta{
+		Name:       name,
+		OldNsPerOp: oldMean,
+		NewNsPerOp: newMean,
+	}
+	if oldMean > 0 {
+		delta.DeltaPercent = (newMean - oldMean) / oldMean * 100
+	}
+	delta.Regression = delta.DeltaPercent > benchRegressionPercent && minOf(newSamples) > maxOf(oldSamples)
+	return delta
+}
+
+// parseBenchOutput extracts ns/op samples from `go test -bench` output, keyed by
+// package-qualified benchmark name
+func parseBenchOutput(out []byte) map[string][]float64 {
+	runs := make(map[string][]float64)
+	pkg := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "pkg: ") {
+			pkg = strings.TrimPrefix(line, "pkg: ")
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		for i := 2; i+1 < len(fields); i++ {
+			if fields[i+1] != "ns/op" {
+				continue
+			}
+			ns, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				break
+			}
+			name := trimProcs(fields[0])
+			if pkg != "" {
+				name = pkg + "." + name
+			}
+			runs[name] = append(runs[name], ns)
+			break
+		}
+	}
+
+	return runs
+}
+
+// trimProcs drops the -GOMAXPROCS suffix from a benchmark name
+func trimProcs(name string) string {
+	if i := strings.LastIndex(name, "-"); i > 0 {
+		if _, err := strconv.Atoi(name[i+1:]); err == nil {
+			return name[:i]
+		}
+	}
+	return name
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func minOf(values []float64) float64 {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+func maxOf(values []float64) float64 {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v > m {
+			m = v
+		}
+	}
+	return m
+}
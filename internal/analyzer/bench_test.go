@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBenchOutput(t *testing.T) {
+	out := []byte(`goos: linux
+goarch: amd64
+pkg: example.com/app/parser
+BenchmarkParse-8         	  100000	      1200 ns/op	     256 B/op	       4 allocs/op
+BenchmarkParse-8         	  100000	      1300 ns/op	     256 B/op	       4 allocs/op
+BenchmarkSmall           	 5000000	        12.5 ns/op
+PASS
+ok  	example.com/app/parser	2.1s
+`)
+
+	runs := parseBenchOutput(out)
+
+	parse := runs["example.com/app/parser.BenchmarkParse"]
+	if len(parse) != 2 || parse[0] != 1200 || parse[1] != 1300 {
+		t.Fatalf("parseBenchOutput() BenchmarkParse = %v", parse)
+	}
+	if small := runs["example.com/app/parser.BenchmarkSmall"]; len(small) != 1 || small[0] != 12.5 {
+		t.Fatalf("parseBenchOutput() BenchmarkSmall = %v", small)
+	}
+}
+
+func TestCompareSamples(t *testing.T) {
+	tests := []struct {
+		name           string
+		oldSamples     []float64
+		newSamples     []float64
+		wantRegression bool
+	}{
+		{"faster", []float64{100, 110}, []float64{80, 85}, false},
+		{"slower beyond noise", []float64{100, 105}, []float64{130, 135}, true},
+		{"slower but overlapping", []float64{100, 160}, []float64{120, 150}, false},
+		{"slower below threshold", []float64{100, 100}, []float64{105, 105}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := compareSamples("BenchmarkX", tt.oldSamples, tt.newSamples)
+			if got.Regression != tt.wantRegression {
+				t.Errorf("compareSamples() Regression = %v, want %v (delta %.1f%%)", got.Regression, tt.wantRegression, got.DeltaPercent)
+			}
+		})
+	}
+}
+
+func TestCompareBenchmarks(t *testing.T) {
+	project := writeProject(t, "module example.com/app\n\ngo 1.21\n")
+
+	restore := mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+		if args[0] != "test" {
+			return nil, nil
+		}
+		if strings.HasPrefix(args[1], "-modfile=") {
+			return []byte("pkg: example.com/app\nBenchmarkA-4 100 200 ns/op\nBenchmarkA-4 100 210 ns/op\n"), nil
+		}
+		return []byte("pkg: example.com/app\nBenchmarkA-4 100 100 ns/op\nBenchmarkA-4 100 110 ns/op\nBenchmarkOnlyOld-4 1 5 ns/op\n"), nil
+	})
+	defer restore()
+
+	a := &Analyzer{projectPath: project}
+	deltas, err := a.compareBenchmarks("./...", "example.com/lib", "v2.0.0")
+	if err != nil {
+		t.Fatalf("compareBenchmarks() error = %v", err)
+	}
+	if len(deltas) != 1 {
+		t.Fatalf("compareBenchmarks() = %+v, want only benchmarks present in both runs", deltas)
+	}
+	if deltas[0].Name != "example.com/app.BenchmarkA" || !deltas[0].Regression {
+		t.Fatalf("compareBenchmarks() = %+v, want regression for BenchmarkA", deltas[0])
+	}
+}
@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// measureBinaryImpact builds pkg against the current dependency version and again
+// against the upgrade, reporting both binary sizes. Build failures are recorded
+// on the result rather than aborting the analysis.
+func (a *Analyzer) measureBinaryImpact(pkg, module, version string) (*BinaryImpact, error) {
+	impact := &BinaryImpact{Package: pkg}
+
+	outDir, err := os.MkdirTemp("", "go-semver-audit-bin-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create build directory: %w", err)
+	}
+	defer os.RemoveAll(outDir)
+
+	oldBin := filepath.Join(outDir, "old")
+	if _, err := runGoCommand(a.projectPath, "build", "-o", oldBin, pkg); err != nil {
+		impact.Error = fmt.Sprintf("build with current version failed: %v", err)
+		return impact, nil
+	}
+
+	overlay, err := a.newUpgradeOverlay(module, version)
+	if err != nil {
+		return nil, err
+	}
+	defer overlay.Close()
+
+	newBin := filepath.Join(outDir, "new")
+	if _, err := runGoCommand(a.projectPath, "build", overlay.flag(), "-o", newBin, pkg); err != nil {
+		impact.Error = fmt.Sprintf("build with %s failed: %v", version, err)
+		return impact, nil
+	}
+
+	if impact.OldSize, err = fileSize(oldBin); err != nil {
+		return nil, err
+	}
+	if impact.NewSize, err = fileSize(newBin); err != nil {
+		return nil, err
+	}
+	return impact, nil
+}
+
+// fileSize returns the size of the file at path
+func fileSize(path string) (int64, error) {
+	fi, err := statFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	return fi.Size(), nil
+}
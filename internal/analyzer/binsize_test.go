@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMeasureBinaryImpact(t *testing.T) {
+	project := writeProject(t, "module example.com/app\n\ngo 1.21\n")
+
+	restore := mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+		if args[0] != "build" {
+			return nil, nil
+		}
+		size := 1000
+		if strings.HasPrefix(args[1], "-modfile=") {
+			size = 1500
+		}
+		out := args[len(args)-2]
+		return nil, os.WriteFile(out, make([]byte, size), 0o644)
+	})
+	defer restore()
+
+	a := &Analyzer{projectPath: project}
+	impact, err := a.measureBinaryImpact("./cmd/app", "example.com/lib", "v2.0.0")
+	if err != nil {
+		t.Fatalf("measureBinaryImpact() error = %v", err)
+	}
+	if impact.Error != "" {
+		t.Fatalf("measureBinaryImpact() unexpected build error %q", impact.Error)
+	}
+	if impact.OldSize != 1000 || impact.NewSize != 1500 {
+		t.Fatalf("measureBinaryImpact() = %d -> %d, want 1000 -> 1500", impact.OldSize, impact.NewSize)
+	}
+}
+
+func TestMeasureBinaryImpactRecordsBuildFailure(t *testing.T) {
+	project := writeProject(t, "module example.com/app\n\ngo 1.21\n")
+
+	restore := mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+		if args[0] == "build" && strings.HasPrefix(args[1], "-modfile=") {
+			return nil, errors.New("undefined: lib.OldHelper")
+		}
+		if args[0] == "build" {
+			return nil, os.WriteFile(args[len(args)-2], []byte("bin"), 0o644)
+		}
+		return nil, nil
+	})
+	defer restore()
+
+	a := &Analyzer{projectPath: project}
+	impact, err := a.measureBinaryImpact("./cmd/app", "example.com/lib", "v2.0.0")
+	if err != nil {
+		t.Fatalf("measureBinaryImpact() error = %v", err)
+	}
+	if !strings.Contains(impact.Error, "undefined: lib.OldHelper") {
+		t.Fatalf("measureBinaryImpact() Error = %q, want build failure", impact.Error)
+	}
+}
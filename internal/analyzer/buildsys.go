@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/packages"
+)
+
+// Build files that declare Go dependencies in Bazel workspaces
+const (
+	bzlmodFile = "MODULE.bazel"
+	bzlSuffix  = ".bzl"
+)
+
+var (
+	// go_repository(...) rules generated by Gazelle in go_deps.bzl
+	goRepositoryRule = regexp.MustCompile(`go_repository\(([^)]*)\)`)
+	// go_deps.module(...) and go_deps.from_file(...) calls in MODULE.bazel
+	goDepsCall = regexp.MustCompile(`\w+\.(module|from_file)\(([^)]*)\)`)
+	// name = "value" rule attributes
+	ruleAttr = regexp.MustCompile(`(\w+)\s*=\s*"([^"]*)"`)
+)
+
+// loadModuleList reads the dependency versions of a project built without a
+// go.mod at its root. path is a MODULE.bazel, a Gazelle go_deps.bzl, or a
+// plain list with one "module version" (or module@version) per line.
+func loadModuleList(path string) (map[string]string, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module list: %w", err)
+	}
+
+	var modules map[string]string
+	switch {
+	case filepath.Base(path) == bzlmodFile:
+		modules, err = parseModuleBazel(path, data)
+	case strings.HasSuffix(path, bzlSuffix):
+		modules = parseGoDepsBzl(data)
+	default:
+		modules, err = parseModuleListFile(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("no Go modules declared in %s", path)
+	}
+	return modules, nil
+}
+
+// parseModuleListFile parses "module version" lines, skipping blanks and # comments
+func parseModuleListFile(data []byte) (map[string]string, error) {
+	modules := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(strings.Replace(text, "@", " ", 1))
+		switch len(fields) {
+		case 0:
+			continue
+		case 2:
+			modules[fields[0]] = fields[1]
+		default:
+			return nil, fmt.Errorf("line %d: expected \"module version\"", line)
+		}
+	}
+	return modules, scanner.Err()
+}
+
+// parseGoDepsBzl collects the importpath and version of go_repository rules
+func parseGoDepsBzl(data []byte) map[string]string {
+	modules := make(map[string]string)
+	for _, rule := range goRepositoryRule.FindAllSubmatch(data, -1) {
+		attrs := ruleAttrs(rule[1])
+		if attrs["importpath"] != "" && attrs["version"] != "" {
+			modules[attrs["importpath"]] = attrs["version"]
+		}
+	}
+	return modules
+}
+
+// parseModuleBazel collects the modules of go_deps.module calls and of the
+// go.mod files imported with go_deps.from_file
+func parseModuleBazel(path string, data []byte) (map[string]string, error) {
+	modules := make(map[string]string)
+	for _, call := range goDepsCall.FindAllSubmatch(data, -1) {
+		attrs := ruleAttrs(call[2])
+		if string(call[1]) == "module" {
+			if attrs["path"] != "" && attrs["version"] != "" {
+				modules[attrs["path"]] = attrs["version"]
+			}
+			continue
+		}
+		if attrs["go_mod"] == "" {
+			continue
+		}
+		goMod := labelPath(filepath.Dir(path), attrs["go_mod"])
+		goModData, err := readFile(goMod)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", goMod, err)
+		}
+		f, err := modfile.ParseLax(goMod, goModData, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, req := range f.Require {
+			modules[req.Mod.Path] = req.Mod.Version
+		}
+	}
+	return modules, nil
+}
+
+// ruleAttrs returns the string attributes of a rule or call
+func ruleAttrs(args []byte) map[string]string {
+	attrs := make(map[string]string)
+	for _, attr := range ruleAttr.FindAllSubmatch(args, -1) {
+		attrs[string(attr[1])] = string(attr[2])
+	}
+	return attrs
+}
+
+// labelPath resolves a main-repository label such as //third_party:go.mod to
+// a file below the workspace root
+func labelPath(root, label string) string {
+	pkg, name, found := strings.Cut(strings.TrimPrefix(label, "//"), ":")
+	if !found {
+		name = filepath.Base(pkg)
+	}
+	return filepath.Join(root, filepath.FromSlash(pkg), name)
+}
+
+// assignModules fills in the module of every loaded package from the module
+// list, since build system drivers do not report module information
+func assignModules(pkgs []*packages.Package, modules map[string]string) {
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if pkg.Module != nil {
+			return
+		}
+		if path, ok := moduleForPackage(pkg.PkgPath, modules); ok {
+			pkg.Module = &packages.Module{Path: path, Version: modules[path]}
+		}
+	})
+}
+
+// moduleForPackage finds the listed module with the longest path containing pkgPath
+func moduleForPackage(pkgPath string, modules map[string]string) (string, bool) {
+	var longest string
+	for path := range modules {
+		if (pkgPath == path || strings.HasPrefix(pkgPath, path+"/")) && len(path) > len(longest) {
+			longest = path
+		}
+	}
+	return longest, longest != ""
+}
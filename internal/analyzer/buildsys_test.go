@@ -0,0 +1,105 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestLoadModuleList(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "deps.txt"), `# pinned by the build
+github.com/pkg/errors v0.9.1
+golang.org/x/sync@v0.6.0
+`)
+	writeFile(t, filepath.Join(dir, "go_deps.bzl"), `load("@bazel_gazelle//:deps.bzl", "go_repository")
+
+def go_dependencies():
+    go_repository(
+        name = "com_github_pkg_errors",
+        importpath = "github.com/pkg/errors",
+        sum = "h1:FEBLx1zS214owpjy7qsBeixbURkuhQAwrK5UwLGTwt4=",
+        version = "v0.9.1",
+    )
+    go_repository(
+        name = "org_golang_x_sync",
+        importpath = "golang.org/x/sync",
+        version = "v0.6.0",
+    )
+`)
+	writeFile(t, filepath.Join(dir, "MODULE.bazel"), `bazel_dep(name = "gazelle", version = "0.35.0")
+
+go_deps = use_extension("@gazelle//:extensions.bzl", "go_deps")
+go_deps.from_file(go_mod = "//third_party:go.mod")
+go_deps.module(
+    path = "golang.org/x/sync",
+    sum = "h1:abc=",
+    version = "v0.6.0",
+)
+`)
+	writeFile(t, filepath.Join(dir, "third_party", "go.mod"), "module example.com/deps\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.9.1\n")
+
+	want := map[string]string{"github.com/pkg/errors": "v0.9.1", "golang.org/x/sync": "v0.6.0"}
+	for _, name := range []string{"deps.txt", "go_deps.bzl", "MODULE.bazel"} {
+		got, err := loadModuleList(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("loadModuleList(%s) error = %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("loadModuleList(%s) = %v, want %v", name, got, want)
+		}
+	}
+
+	writeFile(t, filepath.Join(dir, "bad.txt"), "github.com/pkg/errors\n")
+	if _, err := loadModuleList(filepath.Join(dir, "bad.txt")); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("expected a line error, got %v", err)
+	}
+	writeFile(t, filepath.Join(dir, "empty.bzl"), "def go_dependencies():\n    pass\n")
+	if _, err := loadModuleList(filepath.Join(dir, "empty.bzl")); err == nil {
+		t.Error("expected an error for a file without modules")
+	}
+}
+
+func TestLoadProject_BuildSystem(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "deps.txt"), "example.com/lib v1.2.0\nexample.com/lib/v2 v2.0.0\n")
+
+	lib := &packages.Package{PkgPath: "example.com/lib/sub"}
+	libV2 := &packages.Package{PkgPath: "example.com/lib/v2"}
+	app := &packages.Package{PkgPath: "example.com/app", Imports: map[string]*packages.Package{
+		lib.PkgPath:   lib,
+		libV2.PkgPath: libV2,
+	}}
+	var gotEnv []string
+	restoreLoad := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		gotEnv = cfg.Env
+		return []*packages.Package{app}, nil
+	})
+	defer restoreLoad()
+	restorePrint := mockPackagesPrintErrors(func([]*packages.Package) int { return 0 })
+	defer restorePrint()
+
+	a := &Analyzer{projectPath: dir, opts: Options{Modules: filepath.Join(dir, "deps.txt"), PackagesDriver: "/bin/bazel-driver"}}
+	if err := a.loadProject(); err != nil {
+		t.Fatalf("loadProject() error = %v", err)
+	}
+
+	if len(gotEnv) == 0 || gotEnv[len(gotEnv)-1] != "GOPACKAGESDRIVER=/bin/bazel-driver" {
+		t.Errorf("expected the packages driver in the load environment, got %v", gotEnv)
+	}
+	if lib.Module == nil || lib.Module.Path != "example.com/lib" || lib.Module.Version != "v1.2.0" {
+		t.Errorf("lib module = %+v, want example.com/lib v1.2.0", lib.Module)
+	}
+	if libV2.Module == nil || libV2.Module.Path != "example.com/lib/v2" {
+		t.Errorf("lib/v2 module = %+v, want the longest matching module", libV2.Module)
+	}
+	if app.Module != nil {
+		t.Errorf("app module = %+v, want none for the project itself", app.Module)
+	}
+	if version, err := a.getCurrentVersion("example.com/lib/v2"); err != nil || version != "v2.0.0" {
+		t.Errorf("getCurrentVersion() = %q, %v", version, err)
+	}
+}
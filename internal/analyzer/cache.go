@@ -0,0 +1,134 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Cache keeps module APIs and type-checked projects in memory so repeated
+// analyses skip loading what has not changed. Share one Cache between
+// analyzers through Options.Cache; it is safe for concurrent use.
+type Cache struct {
+	mu       sync.Mutex
+	apis     map[string]*API
+	projects map[string]cachedProject
+}
+
+// cachedProject is a loaded project along with the fingerprint of its sources
+type cachedProject struct {
+	fingerprint string
+	pkgs        []*packages.Package
+}
+
+// NewCache creates an empty cache
+func NewCache() *Cache {
+	return &Cache{
+		apis:     make(map[string]*API),
+		projects: make(map[string]cachedProject),
+	}
+}
+
+// apiKey identifies a module API; package filtering changes its contents
+func apiKey(module, version string, includeTests bool) string {
+	return fmt.Sprintf("%s@%s|tests=%t", module, version, includeTests)
+}
+
+// api returns a cached module API. Module versions are immutable, so entries never expire.
+func (c *Cache) api(key string) (*API, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	api, ok := c.apis[key]
+	return api, ok
+}
+
+func (c *Cache) storeAPI(key string, api *API) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.apis[key] = api
+}
+
+// project returns the cached packages of a project if its sources are unchanged
+func (c *Cache) project(dir, fingerprint string) ([]*packages.Package, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p, ok := c.projects[pathKey(dir)]
+	if !ok || p.fingerprint != fingerprint {
+		return nil, false
+	}
+	return p.pkgs, true
+}
+
+func (c *Cache) storeProject(dir, fingerprint string, pkgs []*packages.Package) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.projects[pathKey(dir)] = cachedProject{fingerprint: fingerprint, pkgs: pkgs}
+}
+
+// workspaceFiles are the module and workspace files whose edits change how a
+// project loads
+var workspaceFiles = []string{"go.mod", "go.sum", "go.work", "go.work.sum"}
+
+// projectFingerprint hashes the name, size, and modification time of every Go
+// source and module file in the project, which is enough to notice edits
+// without reading file contents. The module and workspace files of parent
+// directories, and the workspace GOWORK names, count too, since the go command
+// finds them from the project directory.
+func projectFingerprint(dir string) (string, error) {
+	h := sha256.New()
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	stat := func(path string) {
+		if info, err := os.Stat(path); err == nil {
+			fmt.Fprintf(h, "%s|%d|%d\n", path, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	for parent := filepath.Dir(abs); ; parent = filepath.Dir(parent) {
+		for _, name := range workspaceFiles {
+			stat(filepath.Join(parent, name))
+		}
+		if filepath.Dir(parent) == parent {
+			break
+		}
+	}
+	if work := os.Getenv("GOWORK"); work != "" && work != "off" {
+		stat(work)
+		stat(work + ".sum")
+	}
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != dir && (strings.HasPrefix(name, ".") || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") && !slices.Contains(workspaceFiles, name) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s|%d|%d\n", path, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
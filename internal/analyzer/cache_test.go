@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestCacheReusesModuleAPI(t *testing.T) {
+	loads := 0
+	restore := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		loads++
+		return []*packages.Package{buildAPIPackage("example.com/lib")}, nil
+	})
+	defer restore()
+
+	cache := NewCache()
+	for i := 0; i < 2; i++ {
+		a := &Analyzer{opts: Options{Cache: cache}}
+		api, err := a.loadModuleAPI("example.com/lib", "v1.0.0")
+		if err != nil {
+			t.Fatalf("loadModuleAPI() error = %v", err)
+		}
+		if api.Funcs["Func"] == nil {
+			t.Fatalf("loadModuleAPI() API = %+v", api)
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("packages loaded %d times, want 1", loads)
+	}
+
+	// Filtering options are part of the key
+	a := &Analyzer{opts: Options{Cache: cache, IncludeTestPackages: true}}
+	if _, err := a.loadModuleAPI("example.com/lib", "v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if loads != 2 {
+		t.Fatalf("packages loaded %d times, want 2", loads)
+	}
+}
+
+func TestCacheInvalidatesChangedProject(t *testing.T) {
+	dir := writeProject(t, "module example.com/app\n\ngo 1.21\n")
+	main := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(main, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loads := 0
+	restoreLoad := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		loads++
+		return []*packages.Package{{PkgPath: "example.com/app"}}, nil
+	})
+	defer restoreLoad()
+	restoreErrs := mockPackagesPrintErrors(func(pkgs []*packages.Package) int { return 0 })
+	defer restoreErrs()
+
+	cache := NewCache()
+	load := func() {
+		a := &Analyzer{projectPath: dir, opts: Options{Cache: cache}}
+		if err := a.loadProject(); err != nil {
+			t.Fatalf("loadProject() error = %v", err)
+		}
+	}
+
+	load()
+	load()
+	if loads != 1 {
+		t.Fatalf("project loaded %d times, want 1", loads)
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(main, later, later); err != nil {
+		t.Fatal(err)
+	}
+	load()
+	if loads != 2 {
+		t.Fatalf("project loaded %d times after an edit, want 2", loads)
+	}
+}
+
+func TestProjectFingerprintCoversParentWorkspace(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "app")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOWORK", "")
+
+	before, err := projectFingerprint(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	work := "go 1.21\n\nuse ./app\n"
+	if err := os.WriteFile(filepath.Join(root, "go.work"), []byte(work), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	after, err := projectFingerprint(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before == after {
+		t.Error("fingerprint unchanged after adding a go.work in a parent directory")
+	}
+}
@@ -0,0 +1,146 @@
+package analyzer
+
+// Classifier decides which findings of a result fail an audit. Severity
+// overrides, the breaking and warning checks, and the exit code policies all
+// go through it, so the CLI and the reports agree on what is breaking.
+// Result.HasBreakingChanges and HasWarnings use the zero Classifier, which
+// keeps the severities recorded on the findings.
+type Classifier struct {
+	// Severities remaps finding categories to error, warning, or info; see
+	// ValidateSeverities
+	Severities map[string]string
+	// Strict also fails on warnings, and on breaking changes MaxAffected
+	// accepts
+	Strict bool
+	// MaxAffected accepts breaking changes touching no more than this many
+	// project locations; negative accepts none
+	MaxAffected int
+}
+
+// Classification is a Classifier's verdict on a result
+type Classification struct {
+	Breaking          bool // findings at error severity, or failing tests
+	Warnings          bool // findings only a strict audit fails on
+	AffectedLocations int  // project locations breaking findings touch
+	Accepted          bool // breaking, but within MaxAffected
+	FloorUnmet        bool // the project is below a required minimum version
+	Incomplete        bool // usage-only: the API could not be diffed, so breaking changes were not ruled out
+	ExitCode          int  // 1 when the audit fails, otherwise 0
+}
+
+// Classify judges a result. A project below a required minimum version, or
+// a usage-only audit that could not diff the API, always fails; breaking
+// changes fail unless MaxAffected accepts them, and in strict mode accepted
+// breakages and warnings fail too.
+func (c Classifier) Classify(r *Result) Classification {
+	cl := Classification{
+		Breaking:   c.Breaking(r),
+		Warnings:   c.Warnings(r),
+		FloorUnmet: r.Floor != nil && !r.Floor.Satisfied,
+		Incomplete: r.UsageOnly != nil,
+	}
+	if r.Changes != nil {
+		cl.AffectedLocations = c.AffectedLocations(r.Changes)
+	}
+	cl.Accepted = cl.Breaking && c.MaxAffected >= 0 && cl.AffectedLocations <= c.MaxAffected
+	if cl.FloorUnmet || cl.Incomplete || (cl.Breaking && (!cl.Accepted || c.Strict)) || (c.Strict && cl.Warnings) {
+		cl.ExitCode = 1
+	}
+	return cl
+}
+
+// Breaking reports whether a result has findings at error severity, or tests
+// that fail against the new version
+func (c Classifier) Breaking(r *Result) bool {
+	if len(r.TestFailures) > 0 {
+		return true
+	}
+	if r.Changes == nil {
+		return false
+	}
+	return c.Count(r.Changes, SeverityError) > 0
+}
+
+// Warnings reports whether a result has anything a strict audit fails on:
+// findings at warning severity, added symbols, collapsed generated churn,
+// unused dependencies, benchmark regressions, other majors of the module left
+// in the graph, or a usage-only audit that could not rule breaking changes out
+func (c Classifier) Warnings(r *Result) bool {
+	if r.UsageOnly != nil || len(r.DuplicateMajors) > 0 {
+		return true
+	}
+	if r.Changes == nil {
+		return false
+	}
+	return len(r.Changes.Added) > 0 || len(r.Changes.Generated) > 0 || len(r.UnusedDeps) > 0 ||
+		c.Count(r.Changes, SeverityWarning) > 0 || r.hasBenchmarkRegressions()
+}
+
+// Count returns the number of findings of a diff at a severity
+func (c Classifier) Count(d *Diff, severity string) int {
+	count := 0
+	c.eachFinding(d, func(level string, _ []Location) {
+		if level == severity {
+			count++
+		}
+	})
+	return count
+}
+
+// AffectedLocations returns the number of project locations touched by
+// findings of a diff at error severity
+func (c Classifier) AffectedLocations(d *Diff) int {
+	count := 0
+	c.eachFinding(d, func(level string, usedIn []Location) {
+		if level == SeverityError {
+			count += len(usedIn)
+		}
+	})
+	return count
+}
+
+// Apply records the overridden severity on every finding of a remapped
+// category, so that reports show it
+func (c Classifier) Apply(d *Diff) {
+	if len(c.Severities) == 0 {
+		return
+	}
+	for i := range d.Removed {
+		d.Removed[i].Severity = c.Severities[d.Removed[i].Category()]
+	}
+	for i := range d.Moved {
+		d.Moved[i].Severity = c.Severities[d.Moved[i].Category()]
+	}
+	for i := range d.Changed {
+		d.Changed[i].Severity = c.Severities[d.Changed[i].Category()]
+	}
+	for i := range d.InterfaceChanges {
+		d.InterfaceChanges[i].Severity = c.Severities[d.InterfaceChanges[i].Category()]
+	}
+}
+
+// level returns the severity a finding is classified at: the classifier's
+// override for its category, or else the severity it reports
+func (c Classifier) level(category, level string) string {
+	if override := c.Severities[category]; override != "" {
+		return override
+	}
+	return level
+}
+
+// eachFinding calls fn with the classified severity and the project uses of
+// every finding of a diff
+func (c Classifier) eachFinding(d *Diff, fn func(level string, usedIn []Location)) {
+	for _, removed := range d.Removed {
+		fn(c.level(removed.Category(), removed.Level()), removed.UsedIn)
+	}
+	for _, changed := range d.Changed {
+		fn(c.level(changed.Category(), changed.Level()), changed.UsedIn)
+	}
+	for _, iface := range d.InterfaceChanges {
+		fn(c.level(iface.Category(), iface.Level()), iface.UsedIn)
+	}
+	for _, moved := range d.Moved {
+		fn(c.level(moved.Category(), moved.Level()), moved.UsedIn)
+	}
+}
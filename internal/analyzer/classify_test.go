@@ -0,0 +1,97 @@
+package analyzer
+
+import "testing"
+
+func TestClassifierClassify(t *testing.T) {
+	used := []Location{{File: "a.go", Line: 1}, {File: "b.go", Line: 2}}
+	breaking := func() *Result {
+		return &Result{Changes: &Diff{
+			Removed: []RemovedSymbol{{Name: "Gone", Type: "function", UsedIn: used}},
+			Changed: []ChangedSignature{{Name: "Preview", UsedIn: used[:1], Unstable: true}},
+		}}
+	}
+
+	tests := []struct {
+		name       string
+		classifier Classifier
+		result     *Result
+		want       Classification
+	}{
+		{
+			name:       "breaking",
+			classifier: Classifier{MaxAffected: -1},
+			result:     breaking(),
+			want:       Classification{Breaking: true, Warnings: true, AffectedLocations: 2, ExitCode: 1},
+		},
+		{
+			name:       "accepted within max affected",
+			classifier: Classifier{MaxAffected: 2},
+			result:     breaking(),
+			want:       Classification{Breaking: true, Warnings: true, AffectedLocations: 2, Accepted: true},
+		},
+		{
+			name:       "strict fails accepted breakages",
+			classifier: Classifier{MaxAffected: 2, Strict: true},
+			result:     breaking(),
+			want:       Classification{Breaking: true, Warnings: true, AffectedLocations: 2, Accepted: true, ExitCode: 1},
+		},
+		{
+			name:       "severity override",
+			classifier: Classifier{MaxAffected: -1, Severities: map[string]string{CategoryRemoved: SeverityInfo}},
+			result:     breaking(),
+			want:       Classification{Warnings: true},
+		},
+		{
+			name:       "strict fails warnings",
+			classifier: Classifier{MaxAffected: -1, Strict: true, Severities: map[string]string{CategoryRemoved: SeverityWarning}},
+			result:     breaking(),
+			want:       Classification{Warnings: true, ExitCode: 1},
+		},
+		{
+			name:       "unmet floor",
+			classifier: Classifier{MaxAffected: -1},
+			result:     &Result{Floor: &Floor{Satisfied: false}},
+			want:       Classification{FloorUnmet: true, ExitCode: 1},
+		},
+		{
+			name:       "usage-only fails without strict",
+			classifier: Classifier{MaxAffected: -1},
+			result:     &Result{Changes: &Diff{}, UsageOnly: &UsageOnly{Reason: "offline"}},
+			want:       Classification{Warnings: true, Incomplete: true, ExitCode: 1},
+		},
+		{
+			name:       "duplicate majors fail strict audits",
+			classifier: Classifier{MaxAffected: -1, Strict: true},
+			result:     &Result{DuplicateMajors: []DuplicateMajor{{Module: "example.com/lib"}}},
+			want:       Classification{Warnings: true, ExitCode: 1},
+		},
+		{
+			name:       "clean",
+			classifier: Classifier{MaxAffected: -1, Strict: true},
+			result:     &Result{Changes: &Diff{}},
+			want:       Classification{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.classifier.Classify(tt.result); got != tt.want {
+				t.Errorf("Classify() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifierOverridesUnappliedSeverities(t *testing.T) {
+	diff := &Diff{InterfaceChanges: []InterfaceChange{{Name: "Doer", AddedMethods: []string{"Undo()"}, Unstable: true}}}
+	c := Classifier{Severities: map[string]string{CategoryInterfaceAddedMethod: SeverityError}}
+	if got := c.Count(diff, SeverityError); got != 1 {
+		t.Errorf("Count(error) = %d, want 1 before Apply", got)
+	}
+	if diff.BreakingCount() != 0 {
+		t.Errorf("BreakingCount() = %d, want 0 before Apply", diff.BreakingCount())
+	}
+	c.Apply(diff)
+	if diff.BreakingCount() != 1 {
+		t.Errorf("BreakingCount() = %d, want 1 after Apply", diff.BreakingCount())
+	}
+}
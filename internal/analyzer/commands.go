@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// isMainOnlyModule reports whether every package of module at version is a
+// command, so the module has no API a project could import. Failing to list
+// the packages is not an error: the audit then diffs the API as usual.
+func (a *Analyzer) isMainOnlyModule(module, version string) bool {
+	cfg := &packages.Config{Mode: packages.NeedName, Env: moduleCacheEnv(a.opts.ModFlag)}
+	pkgs, err := a.load(cfg, fmt.Sprintf("%s/...@%s", module, version))
+	if err != nil {
+		return false
+	}
+
+	commands := 0
+	for _, pkg := range pkgs {
+		if isInternalPackage(pkg) || (!a.opts.IncludeTestPackages && isTestOrExamplePackage(pkg)) {
+			continue
+		}
+		switch pkg.Name {
+		case "":
+			// Placeholder for a pattern that matched nothing
+		case "main":
+			commands++
+		default:
+			return false
+		}
+	}
+	return commands > 0
+}
+
+// analyzeMainOnlyModule audits a module that only contains commands. Diffing
+// its empty API would claim there are no breaking changes, so like a tool
+// dependency it reports how the module's go directive and requirements change.
+func (a *Analyzer) analyzeMainOnlyModule(upgrade *Upgrade, newDependency bool) (*Result, error) {
+	result := &Result{
+		Module:        upgrade.Module,
+		OldVersion:    upgrade.OldVersion,
+		NewVersion:    upgrade.NewVersion,
+		NewDependency: newDependency,
+		MainOnly:      true,
+		Changes:       &Diff{},
+	}
+
+	var err error
+	if newDependency {
+		result.Requirements, err = a.moduleRequirements(upgrade.Module, upgrade.NewVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load requirements: %w", err)
+		}
+		return result, nil
+	}
+
+	result.ModuleChanges, err = a.compareModuleFiles(upgrade.Module, upgrade.OldVersion, upgrade.NewVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare command module: %w", err)
+	}
+	return result, nil
+}
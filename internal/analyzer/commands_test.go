@@ -0,0 +1,85 @@
+package analyzer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestIsMainOnlyModule(t *testing.T) {
+	tests := []struct {
+		name string
+		pkgs []*packages.Package
+		err  error
+		want bool
+	}{
+		{
+			name: "commands only",
+			pkgs: []*packages.Package{
+				{PkgPath: "example.com/tool", Name: "main"},
+				{PkgPath: "example.com/tool/cmd/helper", Name: "main"},
+				{PkgPath: "example.com/tool/examples/demo", Name: "demo"},
+			},
+			want: true,
+		},
+		{
+			name: "library alongside commands",
+			pkgs: []*packages.Package{
+				{PkgPath: "example.com/tool", Name: "main"},
+				{PkgPath: "example.com/tool/lib", Name: "lib"},
+			},
+		},
+		{name: "nothing matched", pkgs: []*packages.Package{{PkgPath: "example.com/tool/..."}}},
+		{name: "load error", err: errors.New("go list failed")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPattern string
+			restore := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+				gotPattern = patterns[0]
+				return tt.pkgs, tt.err
+			})
+			defer restore()
+
+			a := &Analyzer{}
+			if got := a.isMainOnlyModule("example.com/tool", "v1.2.0"); got != tt.want {
+				t.Errorf("isMainOnlyModule() = %v, want %v", got, tt.want)
+			}
+			if gotPattern != "example.com/tool/...@v1.2.0" {
+				t.Errorf("loaded %q, want every package of the module", gotPattern)
+			}
+		})
+	}
+}
+
+func TestAnalyzeMainOnlyModule(t *testing.T) {
+	restoreCmd := mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+		switch args[len(args)-1] {
+		case "example.com/tool@v1.0.0":
+			return []byte(`{"Path":"example.com/tool","Version":"v1.0.0","GoMod":"old.mod"}`), nil
+		default:
+			return []byte(`{"Path":"example.com/tool","Version":"v1.1.0","GoMod":"new.mod"}`), nil
+		}
+	})
+	defer restoreCmd()
+	restoreRead := mockReadFile(map[string]string{
+		"old.mod": "module example.com/tool\n\ngo 1.20\n\nrequire example.com/a v1.0.0\n",
+		"new.mod": "module example.com/tool\n\ngo 1.22\n\nrequire example.com/a v1.3.0\n",
+	})
+	defer restoreRead()
+
+	a := &Analyzer{projectPath: "."}
+	result, err := a.analyzeMainOnlyModule(&Upgrade{Module: "example.com/tool", OldVersion: "v1.0.0", NewVersion: "v1.1.0"}, false)
+	if err != nil {
+		t.Fatalf("analyzeMainOnlyModule() error = %v", err)
+	}
+	if !result.MainOnly || result.HasBreakingChanges() {
+		t.Errorf("result = %+v, want a main-only result without findings", result)
+	}
+	want := &ModuleChanges{OldGoVersion: "1.20", NewGoVersion: "1.22", Upgraded: []string{"example.com/a v1.0.0 -> v1.3.0"}}
+	if !reflect.DeepEqual(result.ModuleChanges, want) {
+		t.Errorf("ModuleChanges = %+v, want %+v", result.ModuleChanges, want)
+	}
+}
@@ -0,0 +1,154 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// Conversion describes a package function that became a method, such as
+// lib.Close(c) becoming c.Close(), or a method that became a package function
+type Conversion struct {
+	NewName  string // the method as Type.Method, or the package function
+	ToMethod bool
+	Rewrite  string // how calls change, such as "lib.Close(c) -> c.Close()"
+}
+
+// detectConversions pairs removed functions with an added method of the same
+// name whose receiver is the function's first parameter type, and removed
+// methods with an added function taking the receiver as first parameter.
+// Matched removals carry the Conversion and the additions are no longer
+// reported as added. Symbols without type information are left alone.
+func detectConversions(oldAPI, newAPI *API, diff *Diff) {
+	added := make(map[string]bool, len(diff.Added))
+	for _, a := range diff.Added {
+		if a.Type == "function" {
+			added[a.Name] = true
+		}
+	}
+
+	matched := make(map[string]bool)
+	for i, r := range diff.Removed {
+		oldFunc := oldAPI.Funcs[r.Name]
+		if r.Type != "function" || oldFunc == nil || oldFunc.obj == nil {
+			continue
+		}
+		var conversion *Conversion
+		if oldFunc.IsMethod {
+			conversion = methodToFunction(oldFunc, newAPI, added)
+		} else {
+			conversion = functionToMethod(oldFunc, newAPI, added)
+		}
+		if conversion != nil && !matched[conversion.NewName] {
+			matched[conversion.NewName] = true
+			diff.Removed[i].Conversion = conversion
+		}
+	}
+
+	kept := diff.Added[:0]
+	for _, a := range diff.Added {
+		if !matched[a.Name] || a.Type != "function" {
+			kept = append(kept, a)
+		}
+	}
+	diff.Added = kept
+}
+
+// functionToMethod finds the added method a removed package function became
+func functionToMethod(oldFunc *Function, newAPI *API, added map[string]bool) *Conversion {
+	sig := oldFunc.obj.Type().(*types.Signature)
+	if sig.Params().Len() == 0 {
+		return nil
+	}
+	first := sig.Params().At(0)
+	recvName := namedTypeName(first.Type(), oldFunc.obj.Pkg())
+	if recvName == "" {
+		return nil
+	}
+	newName := recvName + "." + oldFunc.Name
+	method := newAPI.Funcs[newName]
+	if !added[newName] || method == nil || method.obj == nil || method.PromotedFrom != "" {
+		return nil
+	}
+	recv := method.obj.Type().(*types.Signature).Recv()
+	if recv == nil || types.TypeString(recv.Type(), nil) != types.TypeString(first.Type(), nil) {
+		return nil
+	}
+
+	arg := paramName(first, "x")
+	rest := callArgs(sig, 1)
+	return &Conversion{
+		NewName:  newName,
+		ToMethod: true,
+		Rewrite: fmt.Sprintf("%s.%s(%s) -> %s.%s(%s)",
+			oldFunc.obj.Pkg().Name(), oldFunc.Name, joinArgs(arg, rest), arg, oldFunc.Name, rest),
+	}
+}
+
+// methodToFunction finds the added package function a removed method became
+func methodToFunction(oldMethod *Function, newAPI *API, added map[string]bool) *Conversion {
+	if oldMethod.PromotedFrom != "" {
+		return nil
+	}
+	sig := oldMethod.obj.Type().(*types.Signature)
+	recvType, name, _ := strings.Cut(oldMethod.Name, ".")
+	fn := newAPI.Funcs[name]
+	if !added[name] || fn == nil || fn.obj == nil || fn.IsMethod || sig.Recv() == nil {
+		return nil
+	}
+	newSig := fn.obj.Type().(*types.Signature)
+	if newSig.Params().Len() == 0 || types.TypeString(newSig.Params().At(0).Type(), nil) != types.TypeString(sig.Recv().Type(), nil) {
+		return nil
+	}
+
+	recv := paramName(sig.Recv(), strings.ToLower(recvType[:1]))
+	args := callArgs(sig, 0)
+	return &Conversion{
+		NewName: name,
+		Rewrite: fmt.Sprintf("%s.%s(%s) -> %s.%s(%s)",
+			recv, name, args, fn.obj.Pkg().Name(), name, joinArgs(recv, args)),
+	}
+}
+
+// namedTypeName returns the name of a type declared in pkg, looking through
+// one pointer, or "" for other types
+func namedTypeName(t types.Type, pkg *types.Package) string {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() != pkg {
+		return ""
+	}
+	return named.Obj().Name()
+}
+
+// paramName returns a parameter's name, or fallback for unnamed and blank ones
+func paramName(v *types.Var, fallback string) string {
+	if v.Name() == "" || v.Name() == "_" {
+		return fallback
+	}
+	return v.Name()
+}
+
+// callArgs lists the parameter names from index start as call arguments
+func callArgs(sig *types.Signature, start int) string {
+	params := sig.Params()
+	var args []string
+	for i := start; i < params.Len(); i++ {
+		arg := paramName(params.At(i), fmt.Sprintf("arg%d", i))
+		if sig.Variadic() && i == params.Len()-1 {
+			arg += "..."
+		}
+		args = append(args, arg)
+	}
+	return strings.Join(args, ", ")
+}
+
+// joinArgs prepends an argument to a possibly empty argument list
+func joinArgs(first, rest string) string {
+	if rest == "" {
+		return first
+	}
+	return first + ", " + rest
+}
@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestDetectConversions(t *testing.T) {
+	oldLib := checkSource(t, "example.com/lib", `package lib
+
+type Conn struct{}
+
+func Close(c *Conn, reason string) error { return nil }
+
+func (c *Conn) Write(p []byte) error { return nil }
+
+func Open(name string) *Conn { return nil }
+`, nil)
+	newLib := checkSource(t, "example.com/lib", `package lib
+
+type Conn struct{}
+
+func (c *Conn) Close(reason string) error { return nil }
+
+func Write(c *Conn, p []byte) error { return nil }
+
+func Dial(name string) *Conn { return nil }
+`, nil)
+	oldAPI := extractAPI([]*packages.Package{oldLib})
+	newAPI := extractAPI([]*packages.Package{newLib})
+	usage := &Usage{All: true}
+
+	diff := diffAPIs(oldAPI, newAPI, usage)
+	conversions := make(map[string]*Conversion)
+	for _, r := range diff.Removed {
+		conversions[r.Name] = r.Conversion
+	}
+
+	tests := []struct {
+		name     string
+		want     *Conversion
+		notAdded string
+	}{
+		{"Close", &Conversion{NewName: "Conn.Close", ToMethod: true, Rewrite: "lib.Close(c, reason) -> c.Close(reason)"}, "Conn.Close"},
+		{"Conn.Write", &Conversion{NewName: "Write", Rewrite: "c.Write(p) -> lib.Write(c, p)"}, "Write"},
+		{"Open", nil, ""},
+	}
+	for _, tt := range tests {
+		got, ok := conversions[tt.name]
+		if !ok {
+			t.Errorf("%s not reported as removed", tt.name)
+			continue
+		}
+		if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+			t.Errorf("%s conversion = %+v, want %+v", tt.name, got, tt.want)
+		}
+		for _, a := range diff.Added {
+			if a.Name == tt.notAdded {
+				t.Errorf("%s still reported as added", a.Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// DependencyCopy is a directory in the project holding a copy or fork of the
+// audited dependency. References from inside it belong to the copy, not to
+// the project, so they are excluded from the findings.
+type DependencyCopy struct {
+	Dir        string // relative to the project root
+	Reason     string
+	References int // references to the dependency excluded from findings
+}
+
+// findDependencyCopies walks the project for directories whose go.mod declares
+// the dependency's module path, or whose path mirrors it as in
+// third_party/github.com/owner/repo. The vendor directory at the root is
+// managed by go mod vendor and never loaded, so it is skipped. Unreadable
+// directories are skipped too: missing a copy only makes findings noisier.
+func (a *Analyzer) findDependencyCopies(module string) ([]DependencyCopy, error) {
+	var copies []DependencyCopy
+	err := filepath.WalkDir(a.projectPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d == nil || d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() || path == a.projectPath {
+			return nil
+		}
+		name := d.Name()
+		rel, err := filepath.Rel(a.projectPath, path)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(name, ".") || name == "testdata" || rel == "vendor" {
+			return filepath.SkipDir
+		}
+
+		if slash := filepath.ToSlash(rel); slash == module || strings.HasSuffix(slash, "/"+module) {
+			copies = append(copies, DependencyCopy{Dir: rel, Reason: "path mirrors " + module})
+			return filepath.SkipDir
+		}
+		if data, err := readFile(filepath.Join(path, "go.mod")); err == nil {
+			if modPath := modfile.ModulePath(data); modPath == module || strings.HasPrefix(modPath, module+"/") {
+				copies = append(copies, DependencyCopy{Dir: rel, Reason: "go.mod declares module " + modPath})
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	return copies, err
+}
+
+// excludeCopies drops usage located inside copies of the dependency and
+// counts what was dropped per copy
+func (a *Analyzer) excludeCopies(usage *Usage, copies []DependencyCopy) {
+	if len(copies) == 0 {
+		return
+	}
+	for name, locations := range usage.Symbols {
+		kept := locations[:0]
+		for _, loc := range locations {
+			if i := a.copyIndex(loc.File, copies); i >= 0 {
+				copies[i].References++
+				continue
+			}
+			kept = append(kept, loc)
+		}
+		if len(kept) == 0 {
+			delete(usage.Symbols, name)
+		} else {
+			usage.Symbols[name] = kept
+		}
+	}
+}
+
+// copyIndex returns the copy containing file, or -1
+func (a *Analyzer) copyIndex(file string, copies []DependencyCopy) int {
+	for i, c := range copies {
+		if rel, ok := relPath(filepath.Join(a.projectPath, c.Dir), file); ok && rel != "." {
+			return i
+		}
+	}
+	return -1
+}
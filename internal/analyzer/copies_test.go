@@ -0,0 +1,46 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindDependencyCopies(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n")
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n")
+	writeFile(t, filepath.Join(dir, "third_party", "github.com", "owner", "lib", "lib.go"), "package lib\n")
+	writeFile(t, filepath.Join(dir, "forks", "lib", "go.mod"), "module github.com/owner/lib/v2\n")
+	writeFile(t, filepath.Join(dir, "forks", "other", "go.mod"), "module github.com/owner/other\n")
+	writeFile(t, filepath.Join(dir, "vendor", "github.com", "owner", "lib", "lib.go"), "package lib\n")
+
+	a := &Analyzer{projectPath: dir}
+	copies, err := a.findDependencyCopies("github.com/owner/lib")
+	if err != nil {
+		t.Fatalf("findDependencyCopies() error = %v", err)
+	}
+	want := []DependencyCopy{
+		{Dir: filepath.Join("forks", "lib"), Reason: "go.mod declares module github.com/owner/lib/v2"},
+		{Dir: filepath.Join("third_party", "github.com", "owner", "lib"), Reason: "path mirrors github.com/owner/lib"},
+	}
+	if !reflect.DeepEqual(copies, want) {
+		t.Fatalf("findDependencyCopies() = %+v, want %+v", copies, want)
+	}
+
+	copyFile := filepath.Join(dir, "third_party", "github.com", "owner", "lib", "lib.go")
+	mainFile := filepath.Join(dir, "main.go")
+	usage := &Usage{Symbols: map[string][]Location{
+		"Parse":  {{File: copyFile, Line: 3}, {File: mainFile, Line: 5}},
+		"Helper": {{File: copyFile, Line: 9}},
+	}}
+	a.excludeCopies(usage, copies)
+
+	wantUsage := map[string][]Location{"Parse": {{File: mainFile, Line: 5}}}
+	if !reflect.DeepEqual(usage.Symbols, wantUsage) {
+		t.Errorf("usage after excludeCopies = %+v, want %+v", usage.Symbols, wantUsage)
+	}
+	if copies[1].References != 2 || copies[0].References != 0 {
+		t.Errorf("excluded references = %d, %d, want 0, 2", copies[0].References, copies[1].References)
+	}
+}
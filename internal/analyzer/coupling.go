@@ -0,0 +1,72 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// Coupling measures how much of the project touches a dependency,
+// regardless of what the upgrade changes, to show where an abstraction
+// layer would pay off
+type Coupling struct {
+	Symbols         int // distinct top-level symbols of the dependency used
+	Packages        int // packages of the dependency imported
+	ProjectPackages int // project packages using the dependency, counted by directory
+	Files           int // project files using the dependency
+	Lines           int // project lines using the dependency
+}
+
+// measureCoupling counts the distinct symbols, packages, files, and lines
+// behind usage. Fields and methods count toward their type.
+func measureCoupling(usage *Usage) *Coupling {
+	dirs := make(map[string]bool)
+	files := make(map[string]bool)
+	lines := make(map[Location]bool)
+	for _, locations := range usage.Symbols {
+		for _, loc := range locations {
+			file := pathKey(loc.File)
+			dirs[filepath.Dir(file)] = true
+			files[file] = true
+			lines[Location{File: file, Line: loc.Line}] = true
+		}
+	}
+	return &Coupling{
+		Symbols:         usedObjects(usage),
+		Packages:        len(usage.Imports),
+		ProjectPackages: len(dirs),
+		Files:           len(files),
+		Lines:           len(lines),
+	}
+}
+
+// DefaultAdapterThreshold is the number of project packages a dependency
+// may be used from before an adapter package is suggested
+const DefaultAdapterThreshold = 5
+
+// adapterTopSites is how many of the most used symbols an adapter advisory lists
+const adapterTopSites = 5
+
+// AdapterAdvice suggests wrapping a dependency used from more project
+// packages than Threshold in an internal adapter package, starting with its
+// most used symbols
+type AdapterAdvice struct {
+	Threshold int
+	Sites     []SymbolUses // most used symbols first
+}
+
+// adviseAdapter returns an adapter advisory when coupling spreads the
+// dependency over more than threshold project packages, or nil. A threshold
+// of 0 disables the advisory.
+func adviseAdapter(usage *Usage, coupling *Coupling, threshold int) *AdapterAdvice {
+	if threshold <= 0 || coupling.ProjectPackages <= threshold {
+		return nil
+	}
+	sites := symbolUses(usage)
+	sort.SliceStable(sites, func(i, j int) bool {
+		return len(sites[i].UsedIn) > len(sites[j].UsedIn)
+	})
+	if len(sites) > adapterTopSites {
+		sites = sites[:adapterTopSites]
+	}
+	return &AdapterAdvice{Threshold: threshold, Sites: sites}
+}
@@ -0,0 +1,65 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMeasureCoupling(t *testing.T) {
+	usage := &Usage{
+		Symbols: map[string][]Location{
+			"Parse": {
+				{File: "/p/cmd/main.go", Line: 10, Column: 2},
+				{File: "/p/cmd/main.go", Line: 10, Column: 20},
+				{File: "/p/internal/conf/conf.go", Line: 4},
+			},
+			"Config.Load": {
+				{File: "/p/internal/conf/conf.go", Line: 4, Column: 9},
+				{File: "/p/internal/conf/load.go", Line: 7},
+			},
+			"Config.Path": {
+				{File: "/p/internal/conf/load.go", Line: 7, Column: 12},
+			},
+		},
+		Imports: map[string]bool{"example.com/lib": true, "example.com/lib/conf": true},
+	}
+
+	want := &Coupling{Symbols: 2, Packages: 2, ProjectPackages: 2, Files: 3, Lines: 3}
+	if got := measureCoupling(usage); !reflect.DeepEqual(got, want) {
+		t.Errorf("measureCoupling() = %+v, want %+v", got, want)
+	}
+
+	if got := measureCoupling(&Usage{}); !reflect.DeepEqual(got, &Coupling{}) {
+		t.Errorf("measureCoupling() of no usage = %+v, want zeros", got)
+	}
+}
+
+func TestAdviseAdapter(t *testing.T) {
+	usage := &Usage{Symbols: map[string][]Location{}}
+	for i, dir := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		usage.Symbols["Sym"+dir] = []Location{{File: "/p/" + dir + "/x.go", Line: 1}}
+		for j := 0; j < i; j++ {
+			usage.Symbols["Sym"+dir] = append(usage.Symbols["Sym"+dir], Location{File: "/p/" + dir + "/x.go", Line: j + 2})
+		}
+	}
+	coupling := measureCoupling(usage)
+
+	if got := adviseAdapter(usage, coupling, 7); got != nil {
+		t.Errorf("adviseAdapter() at the threshold = %+v, want nil", got)
+	}
+	if got := adviseAdapter(usage, coupling, 0); got != nil {
+		t.Errorf("adviseAdapter() with threshold 0 = %+v, want nil", got)
+	}
+
+	got := adviseAdapter(usage, coupling, 6)
+	if got == nil {
+		t.Fatal("adviseAdapter() = nil, want an advisory above the threshold")
+	}
+	var names []string
+	for _, site := range got.Sites {
+		names = append(names, site.Name)
+	}
+	if want := []string{"Symg", "Symf", "Syme", "Symd", "Symc"}; got.Threshold != 6 || !reflect.DeepEqual(names, want) {
+		t.Errorf("adviseAdapter() = threshold %d, sites %v, want 6, %v", got.Threshold, names, want)
+	}
+}
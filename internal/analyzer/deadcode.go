@@ -0,0 +1,350 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// testEntryPrefixes name the functions go test calls in _test.go files
+var testEntryPrefixes = []string{"Test", "Benchmark", "Fuzz", "Example"}
+
+// DeadFunction is a project function or method that no entry point reaches
+// and that holds the only uses of some findings. Deleting it resolves them
+// without a migration.
+type DeadFunction struct {
+	Name     string // such as oldSync or (*Client).legacy
+	Package  string // import path of the project package declaring it
+	Location Location
+	Symbols  []string // changed symbols it uses
+}
+
+// findDeadCode loads the project together with its tests, marks the findings
+// of diff that only unreachable functions use, and returns those functions
+func (a *Analyzer) findDeadCode(diff *Diff) ([]DeadFunction, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedTypes |
+			packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir:   a.projectPath,
+		Tests: true,
+	}
+	if a.opts.PackagesDriver != "" {
+		cfg.Env = append(os.Environ(), "GOPACKAGESDRIVER="+a.opts.PackagesDriver)
+	}
+	pkgs, err := a.load(cfg, "./...")
+	if err != nil {
+		return nil, err
+	}
+	return markDeadCode(diff, unreachableFuncs(pkgs)), nil
+}
+
+// declNode is a declaration in one of the packages that contain its file;
+// with tests loaded, a file belongs to a package and its test variant
+type declNode struct {
+	pkg  *packages.Package
+	node ast.Node
+}
+
+// reachability walks the references of project code from its entry points.
+// Declarations are identified by position, which is shared by every variant
+// of a package.
+type reachability struct {
+	funcs   map[token.Position][]declNode
+	types   map[token.Position][]declNode
+	methods map[token.Position][]token.Position // type -> methods declared on it
+	reached map[token.Position]bool
+	queue   []token.Position
+}
+
+// unreachableFuncs returns the project functions and methods that no entry
+// point reaches. Entry points are main, init, test functions, functions
+// exported to C or linked by name, and package-level variable initializers;
+// a project without commands is a library, whose exported API is an entry
+// point as well. A method is reached with its type, as it may be called
+// through an interface. Projects that fail to type-check could hide
+// references, so nothing in them is reported.
+func unreachableFuncs(pkgs []*packages.Package) []declNode {
+	r := &reachability{
+		funcs:   make(map[token.Position][]declNode),
+		types:   make(map[token.Position][]declNode),
+		methods: make(map[token.Position][]token.Position),
+		reached: make(map[token.Position]bool),
+	}
+
+	var loaded []*packages.Package
+	library := true
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.PkgPath, ".test") {
+			continue // the generated main of a test binary
+		}
+		if len(pkg.Errors) > 0 || pkg.TypesInfo == nil {
+			return nil
+		}
+		if pkg.Name == "main" {
+			library = false
+		}
+		loaded = append(loaded, pkg)
+	}
+
+	var roots []declNode
+	for _, pkg := range loaded {
+		for _, file := range pkg.Syntax {
+			test := strings.HasSuffix(pkg.Fset.Position(file.Pos()).Filename, "_test.go")
+			for _, decl := range file.Decls {
+				switch decl := decl.(type) {
+				case *ast.FuncDecl:
+					pos := pkg.Fset.Position(decl.Name.Pos())
+					r.funcs[pos] = append(r.funcs[pos], declNode{pkg, decl})
+					if recv := receiverTypeName(pkg, decl); recv != nil {
+						typePos := pkg.Fset.Position(recv.Pos())
+						if !containsPosition(r.methods[typePos], pos) {
+							r.methods[typePos] = append(r.methods[typePos], pos)
+						}
+					} else if entryPoint(pkg, decl, test, library) {
+						r.mark(pos)
+					}
+					if linkedExternally(decl) {
+						r.mark(pos)
+					}
+				case *ast.GenDecl:
+					switch decl.Tok {
+					case token.TYPE:
+						for _, spec := range decl.Specs {
+							spec := spec.(*ast.TypeSpec)
+							pos := pkg.Fset.Position(spec.Name.Pos())
+							r.types[pos] = append(r.types[pos], declNode{pkg, spec})
+							if library && pkg.Name != "main" && spec.Name.IsExported() {
+								r.mark(pos)
+							}
+						}
+					case token.VAR:
+						roots = append(roots, declNode{pkg, decl})
+					}
+				}
+			}
+		}
+	}
+
+	for _, root := range roots {
+		r.walk(root)
+	}
+	for len(r.queue) > 0 {
+		pos := r.queue[0]
+		r.queue = r.queue[1:]
+		for _, fn := range r.funcs[pos] {
+			r.walk(fn)
+		}
+		for _, typ := range r.types[pos] {
+			r.walk(typ)
+		}
+		for _, method := range r.methods[pos] {
+			r.mark(method)
+		}
+	}
+
+	var dead []declNode
+	for pos, fns := range r.funcs {
+		if !r.reached[pos] {
+			dead = append(dead, fns[0])
+		}
+	}
+	sort.Slice(dead, func(i, j int) bool {
+		pi, pj := dead[i].pkg.Fset.Position(dead[i].node.Pos()), dead[j].pkg.Fset.Position(dead[j].node.Pos())
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		return pi.Offset < pj.Offset
+	})
+	return dead
+}
+
+// mark records that a declaration is reached, queueing it for a walk
+func (r *reachability) mark(pos token.Position) {
+	if !r.reached[pos] {
+		r.reached[pos] = true
+		r.queue = append(r.queue, pos)
+	}
+}
+
+// walk marks the functions, methods, and types a declaration refers to
+func (r *reachability) walk(decl declNode) {
+	ast.Inspect(decl.node, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			switch obj := decl.pkg.TypesInfo.Uses[id].(type) {
+			case *types.Func:
+				r.mark(decl.pkg.Fset.Position(obj.Origin().Pos()))
+			case *types.TypeName:
+				r.mark(decl.pkg.Fset.Position(obj.Pos()))
+			}
+		}
+		return true
+	})
+}
+
+// entryPoint reports whether a function is called from outside the project's code
+func entryPoint(pkg *packages.Package, decl *ast.FuncDecl, test, library bool) bool {
+	name := decl.Name.Name
+	switch {
+	case name == "init", name == "main" && pkg.Name == "main":
+		return true
+	case library && pkg.Name != "main" && decl.Name.IsExported():
+		return true
+	case test:
+		for _, prefix := range testEntryPrefixes {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// linkedExternally reports whether a function is exported to C with cgo or
+// referenced by name with go:linkname, so callers may be outside Go code
+func linkedExternally(decl *ast.FuncDecl) bool {
+	if decl.Doc == nil {
+		return false
+	}
+	for _, c := range decl.Doc.List {
+		if strings.HasPrefix(c.Text, "//export ") || strings.HasPrefix(c.Text, "//go:linkname ") {
+			return true
+		}
+	}
+	return false
+}
+
+// receiverTypeName returns the type a method is declared on, or nil for
+// functions
+func receiverTypeName(pkg *packages.Package, decl *ast.FuncDecl) *types.TypeName {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return nil
+	}
+	expr := decl.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch x := expr.(type) {
+	case *ast.IndexExpr:
+		expr = x.X
+	case *ast.IndexListExpr:
+		expr = x.X
+	}
+	id, ok := expr.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	obj, _ := pkg.TypesInfo.Uses[id].(*types.TypeName)
+	return obj
+}
+
+func containsPosition(list []token.Position, pos token.Position) bool {
+	for _, p := range list {
+		if p == pos {
+			return true
+		}
+	}
+	return false
+}
+
+// markDeadCode marks the findings whose every use lies in an unreachable
+// function and returns those functions with the findings they use
+func markDeadCode(diff *Diff, dead []declNode) []DeadFunction {
+	if len(dead) == 0 {
+		return nil
+	}
+	funcs := make([]DeadFunction, len(dead))
+	for i, fn := range dead {
+		decl := fn.node.(*ast.FuncDecl)
+		pos := fn.pkg.Fset.Position(decl.Name.Pos())
+		funcs[i] = DeadFunction{
+			Name:     funcDeclName(decl),
+			Package:  fn.pkg.PkgPath,
+			Location: Location{File: pos.Filename, Line: pos.Line, Column: pos.Column},
+		}
+	}
+
+	// containing returns the dead functions holding every location, or nil
+	// when some location is reachable
+	containing := func(locs []Location) []int {
+		if len(locs) == 0 {
+			return nil
+		}
+		var holders []int
+		for _, loc := range locs {
+			found := -1
+			for i, fn := range dead {
+				if within(fn.pkg.Fset, fn.node, loc) {
+					found = i
+					break
+				}
+			}
+			if found < 0 {
+				return nil
+			}
+			holders = append(holders, found)
+		}
+		return holders
+	}
+	mark := func(name string, locs []Location) bool {
+		holders := containing(locs)
+		for _, i := range holders {
+			if n := len(funcs[i].Symbols); n == 0 || funcs[i].Symbols[n-1] != name {
+				funcs[i].Symbols = append(funcs[i].Symbols, name)
+			}
+		}
+		return holders != nil
+	}
+
+	for i := range diff.Removed {
+		diff.Removed[i].DeadCode = mark(diff.Removed[i].Name, diff.Removed[i].UsedIn)
+	}
+	for i := range diff.Moved {
+		diff.Moved[i].DeadCode = mark(diff.Moved[i].Name, diff.Moved[i].UsedIn)
+	}
+	for i := range diff.Changed {
+		diff.Changed[i].DeadCode = mark(diff.Changed[i].Name, diff.Changed[i].UsedIn)
+	}
+	for i := range diff.InterfaceChanges {
+		diff.InterfaceChanges[i].DeadCode = mark(diff.InterfaceChanges[i].Name, diff.InterfaceChanges[i].UsedIn)
+	}
+
+	var result []DeadFunction
+	for _, fn := range funcs {
+		if len(fn.Symbols) > 0 {
+			sort.Strings(fn.Symbols)
+			result = append(result, fn)
+		}
+	}
+	return result
+}
+
+// within reports whether loc lies inside node; locations without a column
+// match the whole line
+func within(fset *token.FileSet, node ast.Node, loc Location) bool {
+	start, end := fset.Position(node.Pos()), fset.Position(node.End())
+	if !samePath(start.Filename, loc.File) || loc.Line < start.Line || loc.Line > end.Line {
+		return false
+	}
+	if loc.Column == 0 {
+		return true
+	}
+	return (loc.Line > start.Line || loc.Column >= start.Column) && (loc.Line < end.Line || loc.Column <= end.Column)
+}
+
+// funcDeclName names a function, or a method with its receiver type, such as
+// (*Client).legacy
+func funcDeclName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return decl.Name.Name
+	}
+	recv := types.ExprString(decl.Recv.List[0].Type)
+	if strings.HasPrefix(recv, "*") {
+		recv = "(" + recv + ")"
+	}
+	return recv + "." + decl.Name.Name
+}
@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const deadCodeProgram = `package main
+
+import "example.com/lib"
+
+type legacy struct{}
+
+func (legacy) Sync() { lib.Old() }
+
+type server struct{}
+
+func (s *server) handle() { lib.Run() }
+
+func main() {
+	s := &server{}
+	go func() { s.handle() }()
+}
+
+func oldSync() {
+	lib.Old()
+	lib.Run()
+}
+
+//export callback
+func callback() {}
+
+func init() {}
+`
+
+func checkDeadCodeProgram(t *testing.T, src string) *packages.Package {
+	t.Helper()
+	lib := checkSource(t, "example.com/lib", "package lib\n\nfunc Old() {}\n\nfunc Run() {}\n", nil)
+	app := checkSource(t, "example.com/app", src, map[string]*types.Package{"example.com/lib": lib.Types})
+	app.Name = app.Types.Name()
+	return app
+}
+
+func deadNames(dead []declNode) []string {
+	var names []string
+	for _, fn := range dead {
+		names = append(names, funcDeclName(fn.node.(*ast.FuncDecl)))
+	}
+	return names
+}
+
+func TestUnreachableFuncs(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "program",
+			src:  deadCodeProgram,
+			want: []string{"legacy.Sync", "oldSync"},
+		},
+		{
+			name: "library exports are entry points",
+			src: `package app
+
+import "example.com/lib"
+
+type Client struct{}
+
+func (c *Client) Do() { c.helper() }
+
+func (c *Client) helper() { lib.Run() }
+
+type cache struct{}
+
+func (cache) get() {}
+
+func unused() { lib.Old() }
+`,
+			want: []string{"cache.get", "unused"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := deadNames(unreachableFuncs([]*packages.Package{checkDeadCodeProgram(t, tt.src)}))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unreachableFuncs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnreachableFuncs_LoadErrors(t *testing.T) {
+	app := checkDeadCodeProgram(t, deadCodeProgram)
+	app.Errors = []packages.Error{{Msg: "undefined: x"}}
+	if got := unreachableFuncs([]*packages.Package{app}); got != nil {
+		t.Errorf("unreachableFuncs() = %v, want nothing for a project with errors", deadNames(got))
+	}
+}
+
+func TestMarkDeadCode(t *testing.T) {
+	app := checkDeadCodeProgram(t, deadCodeProgram)
+	at := func(line int) Location { return Location{File: "example.com/app.go", Line: line, Column: 5} }
+	diff := &Diff{
+		Removed: []RemovedSymbol{{Name: "Old", Type: "function", UsedIn: []Location{at(7), at(19)}}},
+		Changed: []ChangedSignature{{Name: "Run", UsedIn: []Location{at(11), at(20)}}},
+	}
+
+	got := markDeadCode(diff, unreachableFuncs([]*packages.Package{app}))
+
+	if !diff.Removed[0].DeadCode || diff.Removed[0].Level() != SeverityWarning {
+		t.Errorf("Removed Old = %+v, want it downgraded to a warning", diff.Removed[0])
+	}
+	if diff.Changed[0].DeadCode || diff.Changed[0].Level() != SeverityError {
+		t.Errorf("Changed Run = %+v, want it kept as an error, since handle is reachable", diff.Changed[0])
+	}
+	want := []DeadFunction{
+		{Name: "legacy.Sync", Package: "example.com/app", Location: Location{File: "example.com/app.go", Line: 7, Column: 15}, Symbols: []string{"Old"}},
+		{Name: "oldSync", Package: "example.com/app", Location: Location{File: "example.com/app.go", Line: 18, Column: 6}, Symbols: []string{"Old"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("markDeadCode() = %+v, want %+v", got, want)
+	}
+	if diff.BreakingCount() != 1 || diff.WarningCount() != 1 {
+		t.Errorf("BreakingCount() = %d, WarningCount() = %d, want 1 and 1", diff.BreakingCount(), diff.WarningCount())
+	}
+}
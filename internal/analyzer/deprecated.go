@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// Deprecation is the "// Deprecated:" comment on the module directive of the
+// upgraded module's latest go.mod
+type Deprecation struct {
+	Message   string
+	Successor string // module path the message points to, if any
+}
+
+// checkDeprecation reports the deprecation the latest go.mod of the module
+// declares. Like the go command, only the latest version counts, so a module
+// that was deprecated and later revived is not reported.
+func checkDeprecation(latest *modfile.File) *Deprecation {
+	if latest == nil || latest.Module == nil || latest.Module.Deprecated == "" {
+		return nil
+	}
+	self := latest.Module.Mod.Path
+	return &Deprecation{
+		Message:   latest.Module.Deprecated,
+		Successor: successorModule(latest.Module.Deprecated, self),
+	}
+}
+
+// successorModule finds the first module path in a deprecation message, such
+// as github.com/org/lib/v2 in "use github.com/org/lib/v2 instead", ignoring
+// the deprecated module itself
+func successorModule(message, self string) string {
+	for _, word := range strings.Fields(message) {
+		word = strings.Trim(word, "`'\"()[],;:.")
+		if word == self || !strings.Contains(word, "/") {
+			continue
+		}
+		if first, _, _ := strings.Cut(word, "/"); !strings.Contains(first, ".") {
+			continue
+		}
+		if module.CheckPath(word) == nil {
+			return word
+		}
+	}
+	return ""
+}
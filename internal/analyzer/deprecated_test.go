@@ -0,0 +1,50 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/mod/modfile"
+)
+
+func TestCheckDeprecation(t *testing.T) {
+	tests := []struct {
+		name  string
+		gomod string
+		want  *Deprecation
+	}{
+		{
+			name:  "not deprecated",
+			gomod: "module example.com/lib\n",
+		},
+		{
+			name:  "successor module",
+			gomod: "// Deprecated: use github.com/org/lib/v2 instead.\nmodule example.com/lib\n",
+			want:  &Deprecation{Message: "use github.com/org/lib/v2 instead.", Successor: "github.com/org/lib/v2"},
+		},
+		{
+			name:  "quoted successor after the module itself",
+			gomod: "// Deprecated: example.com/lib is frozen; switch to `example.com/newlib`.\nmodule example.com/lib\n",
+			want:  &Deprecation{Message: "example.com/lib is frozen; switch to `example.com/newlib`.", Successor: "example.com/newlib"},
+		},
+		{
+			name:  "no successor",
+			gomod: "// Deprecated: no longer maintained, see the README at path/to/docs.\nmodule example.com/lib\n",
+			want:  &Deprecation{Message: "no longer maintained, see the README at path/to/docs."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := modfile.ParseLax("go.mod", []byte(tt.gomod), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := checkDeprecation(f); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkDeprecation() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+	if got := checkDeprecation(nil); got != nil {
+		t.Errorf("checkDeprecation(nil) = %+v, want nil", got)
+	}
+}
@@ -1,7 +1,14 @@
 package analyzer
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // diffAPIs compares two API surfaces and returns the differences
 func diffAPIs(oldAPI, newAPI *API, usage *Usage) *Diff {
+	oldAPI, newAPI = oldAPI.withoutNil(), newAPI.withoutNil()
 	diff := &Diff{
 		Removed:          []RemovedSymbol{},
 		Added:            []AddedSymbol{},
@@ -13,26 +20,37 @@ func diffAPIs(oldAPI, newAPI *API, usage *Usage) *Diff {
 	for name, oldFunc := range oldAPI.Funcs {
 		if _, exists := newAPI.Funcs[name]; !exists {
 			// Function was removed
-			locations := usage.Symbols[name]
-			if len(locations) > 0 {
+			locations, used := usage.uses(name)
+			if used {
 				// Only report if it's actually used
 				diff.Removed = append(diff.Removed, RemovedSymbol{
-					Name:   name,
-					Type:   "function",
-					UsedIn: locations,
+					Name:      name,
+					Type:      "function",
+					Package:   oldFunc.PkgPath,
+					UsedIn:    locations,
+					Unstable:  oldFunc.Unstable,
+					ViaModule: oldFunc.ViaModule,
 				})
 			}
 		} else {
 			// Function exists, check if signature changed
 			newFunc := newAPI.Funcs[name]
-			if oldFunc.Signature != newFunc.Signature {
-				locations := usage.Symbols[name]
-				if len(locations) > 0 {
+			if oldFunc.Signature.Text != newFunc.Signature.Text {
+				locations, used := usage.uses(name)
+				if used {
 					diff.Changed = append(diff.Changed, ChangedSignature{
-						Name:         name,
-						OldSignature: oldFunc.Signature,
-						NewSignature: newFunc.Signature,
-						UsedIn:       locations,
+						Name:           name,
+						OldSignature:   oldFunc.Signature.Text,
+						NewSignature:   newFunc.Signature.Text,
+						Package:        newFunc.PkgPath,
+						UsedIn:         locations,
+						Unstable:       oldFunc.Unstable || newFunc.Unstable,
+						PromotedFrom:   newFunc.PromotedFrom,
+						ViaModule:      viaModule(oldFunc.ViaModule, newFunc.ViaModule),
+						BehaviorChange: behaviorChange(oldFunc, newFunc),
+						ParamNamesOnly: onlyParamNamesChanged(oldFunc, newFunc),
+						ParamChanges:   paramChanges(oldFunc.Signature, newFunc.Signature),
+						Instantiations: usage.instantiations(name, newFunc.declType()),
 					})
 				}
 			}
@@ -40,35 +58,59 @@ func diffAPIs(oldAPI, newAPI *API, usage *Usage) *Diff {
 	}
 
 	// Check for added functions (informational)
-	for name := range newAPI.Funcs {
+	for name, newFunc := range newAPI.Funcs {
 		if _, exists := oldAPI.Funcs[name]; !exists {
 			diff.Added = append(diff.Added, AddedSymbol{
-				Name: name,
-				Type: "function",
+				Name:    name,
+				Type:    "function",
+				Package: newFunc.PkgPath,
 			})
 		}
 	}
 
-	// Check for removed types
-	for name := range oldAPI.Types {
-		if _, exists := newAPI.Types[name]; !exists {
-			locations := usage.Symbols[name]
-			if len(locations) > 0 {
+	// Check for removed types and fields, and for generic types whose type
+	// parameters changed
+	for name, oldType := range oldAPI.Types {
+		if newType, exists := newAPI.Types[name]; exists {
+			diffFields(name, oldType, newType, usage, diff)
+			if oldType.TypeParams == newType.TypeParams {
+				continue
+			}
+			locations, used := usage.uses(name)
+			if used {
+				diff.Changed = append(diff.Changed, ChangedSignature{
+					Name:           name,
+					OldSignature:   "type " + name + oldType.TypeParams,
+					NewSignature:   "type " + name + newType.TypeParams,
+					Package:        newType.PkgPath,
+					UsedIn:         locations,
+					Unstable:       oldType.Unstable || newType.Unstable,
+					ViaModule:      viaModule(oldType.ViaModule, newType.ViaModule),
+					Instantiations: usage.instantiations(name, newType.declType()),
+				})
+			}
+		} else {
+			locations, used := usage.uses(name)
+			if used {
 				diff.Removed = append(diff.Removed, RemovedSymbol{
-					Name:   name,
-					Type:   "type",
-					UsedIn: locations,
+					Name:      name,
+					Type:      "type",
+					Package:   oldType.PkgPath,
+					UsedIn:    locations,
+					Unstable:  oldType.Unstable,
+					ViaModule: oldType.ViaModule,
 				})
 			}
 		}
 	}
 
 	// Check for added types (informational)
-	for name := range newAPI.Types {
+	for name, newType := range newAPI.Types {
 		if _, exists := oldAPI.Types[name]; !exists {
 			diff.Added = append(diff.Added, AddedSymbol{
-				Name: name,
-				Type: "type",
+				Name:    name,
+				Type:    "type",
+				Package: newType.PkgPath,
 			})
 		}
 	}
@@ -82,30 +124,139 @@ func diffAPIs(oldAPI, newAPI *API, usage *Usage) *Diff {
 			}
 		} else {
 			// Interface was removed
-			locations := usage.Symbols[name]
-			if len(locations) > 0 {
+			locations, used := usage.uses(name)
+			if used {
 				diff.Removed = append(diff.Removed, RemovedSymbol{
-					Name:   name,
-					Type:   "interface",
-					UsedIn: locations,
+					Name:      name,
+					Type:      "interface",
+					Package:   oldIface.PkgPath,
+					UsedIn:    locations,
+					Unstable:  oldIface.Unstable,
+					ViaModule: oldIface.ViaModule,
 				})
 			}
 		}
 	}
 
 	// Check for added interfaces (informational)
-	for name := range newAPI.Interfaces {
+	for name, newIface := range newAPI.Interfaces {
 		if _, exists := oldAPI.Interfaces[name]; !exists {
 			diff.Added = append(diff.Added, AddedSymbol{
-				Name: name,
-				Type: "interface",
+				Name:    name,
+				Type:    "interface",
+				Package: newIface.PkgPath,
 			})
 		}
 	}
 
+	dedupeFindings(diff)
+	detectMoves(oldAPI, newAPI, usage, diff)
+	detectConversions(oldAPI, newAPI, diff)
+	groupGeneratedChurn(oldAPI, newAPI, usage, diff)
+	sortFindings(diff)
+
 	return diff
 }
 
+// sortFindings orders findings by name so identical inputs always produce
+// identical reports, independent of map iteration order
+func sortFindings(diff *Diff) {
+	sort.Slice(diff.Removed, func(i, j int) bool {
+		if diff.Removed[i].Name != diff.Removed[j].Name {
+			return diff.Removed[i].Name < diff.Removed[j].Name
+		}
+		return diff.Removed[i].Type < diff.Removed[j].Type
+	})
+	sort.Slice(diff.Added, func(i, j int) bool {
+		if diff.Added[i].Name != diff.Added[j].Name {
+			return diff.Added[i].Name < diff.Added[j].Name
+		}
+		return diff.Added[i].Type < diff.Added[j].Type
+	})
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Name < diff.Changed[j].Name })
+	sort.Slice(diff.InterfaceChanges, func(i, j int) bool { return diff.InterfaceChanges[i].Name < diff.InterfaceChanges[j].Name })
+	sort.Slice(diff.Moved, func(i, j int) bool { return diff.Moved[i].Name < diff.Moved[j].Name })
+}
+
+// dedupeFindings merges findings that describe the same symbol at the same
+// locations in more than one category, such as a name that disappeared both
+// as a function and as a type. Removals take precedence over signature and
+// interface changes for the same symbol.
+func dedupeFindings(diff *Diff) {
+	removedIdx := make(map[string]int)
+	var removed []RemovedSymbol
+	for _, r := range diff.Removed {
+		key := findingKey(r.Name, r.UsedIn)
+		if i, ok := removedIdx[key]; ok {
+			removed[i].Type += "/" + r.Type
+			removed[i].Unstable = removed[i].Unstable && r.Unstable
+			continue
+		}
+		removedIdx[key] = len(removed)
+		removed = append(removed, r)
+	}
+	diff.Removed = removed
+
+	changed := diff.Changed[:0]
+	for _, c := range diff.Changed {
+		if _, ok := removedIdx[findingKey(c.Name, c.UsedIn)]; !ok {
+			changed = append(changed, c)
+		}
+	}
+	diff.Changed = changed
+
+	ifaces := diff.InterfaceChanges[:0]
+	for _, ic := range diff.InterfaceChanges {
+		if _, ok := removedIdx[findingKey(ic.Name, ic.UsedIn)]; !ok {
+			ifaces = append(ifaces, ic)
+		}
+	}
+	diff.InterfaceChanges = ifaces
+}
+
+// findingKey identifies a finding by symbol name and the exact set of usage locations
+func findingKey(name string, locations []Location) string {
+	parts := make([]string, len(locations))
+	for i, loc := range locations {
+		parts[i] = fmt.Sprintf("%s:%d", pathKey(loc.File), loc.Line)
+	}
+	sort.Strings(parts)
+	return name + "|" + strings.Join(parts, ",")
+}
+
+// withoutNil returns the API without nil entries, such as null symbols in a
+// snapshot edited by hand, copying it only when it has some; a nil API is
+// empty
+func (api *API) withoutNil() *API {
+	if api == nil {
+		return emptyAPI()
+	}
+	funcs, types, ifaces := withoutNilValues(api.Funcs), withoutNilValues(api.Types), withoutNilValues(api.Interfaces)
+	if len(funcs) == len(api.Funcs) && len(types) == len(api.Types) && len(ifaces) == len(api.Interfaces) {
+		return api
+	}
+	clean := *api
+	clean.Funcs, clean.Types, clean.Interfaces = funcs, types, ifaces
+	return &clean
+}
+
+// withoutNilValues returns m, or a copy of it without its nil values
+func withoutNilValues[V any](m map[string]*V) map[string]*V {
+	for _, v := range m {
+		if v != nil {
+			continue
+		}
+		clean := make(map[string]*V, len(m))
+		for name, v := range m {
+			if v != nil {
+				clean[name] = v
+			}
+		}
+		return clean
+	}
+	return m
+}
+
 // diffInterfaces compares two interface definitions
 func diffInterfaces(name string, oldIface, newIface *Interface, usage *Usage) *InterfaceChange {
 	oldMethods := make(map[string]bool)
@@ -127,20 +278,39 @@ func diffInterfaces(name string, oldIface, newIface *Interface, usage *Usage) *I
 		}
 	}
 
-	// Find added methods
+	// Find added methods, noting those a newly embedded interface brings in
+	var addedVia map[string]string
 	for method := range newMethods {
-		if !oldMethods[method] {
-			added = append(added, method)
+		if oldMethods[method] {
+			continue
+		}
+		added = append(added, method)
+		name := interfaceMethodName(method)
+		if via := newIface.Embedded[name]; via != "" && via != oldIface.Embedded[name] {
+			if addedVia == nil {
+				addedVia = make(map[string]string)
+			}
+			addedVia[name] = via
 		}
 	}
 
+	sort.Strings(added)
+	sort.Strings(removed)
+
 	// If there are changes and the interface is used, report it
-	if (len(added) > 0 || len(removed) > 0) && len(usage.Symbols[name]) > 0 {
+	locations, used := usage.uses(name)
+	if (len(added) > 0 || len(removed) > 0) && used {
 		return &InterfaceChange{
 			Name:           name,
 			AddedMethods:   added,
+			AddedVia:       addedVia,
 			RemovedMethods: removed,
-			UsedIn:         usage.Symbols[name],
+			Package:        newIface.PkgPath,
+			UsedIn:         locations,
+			Unstable:       oldIface.Unstable || newIface.Unstable,
+			ViaModule:      viaModule(oldIface.ViaModule, newIface.ViaModule),
+
+			Implementations: usage.implementations(oldIface, newIface),
 		}
 	}
 
@@ -21,12 +21,12 @@ func TestDiffAPIs(t *testing.T) {
 			name: "no changes",
 			oldAPI: &API{
 				Funcs: map[string]*Function{
-					"Foo": {Name: "Foo", Signature: "func() error"},
+					"Foo": {Name: "Foo", Signature: Signature{Text: "func() error"}},
 				},
 			},
 			newAPI: &API{
 				Funcs: map[string]*Function{
-					"Foo": {Name: "Foo", Signature: "func() error"},
+					"Foo": {Name: "Foo", Signature: Signature{Text: "func() error"}},
 				},
 			},
 			usage: &Usage{
@@ -45,7 +45,7 @@ func TestDiffAPIs(t *testing.T) {
 			name: "function removed and used",
 			oldAPI: &API{
 				Funcs: map[string]*Function{
-					"OldFunc": {Name: "OldFunc", Signature: "func() error"},
+					"OldFunc": {Name: "OldFunc", Signature: Signature{Text: "func() error"}},
 				},
 			},
 			newAPI: &API{
@@ -67,7 +67,7 @@ func TestDiffAPIs(t *testing.T) {
 			name: "function removed but not used",
 			oldAPI: &API{
 				Funcs: map[string]*Function{
-					"UnusedFunc": {Name: "UnusedFunc", Signature: "func() error"},
+					"UnusedFunc": {Name: "UnusedFunc", Signature: Signature{Text: "func() error"}},
 				},
 			},
 			newAPI: &API{
@@ -90,7 +90,7 @@ func TestDiffAPIs(t *testing.T) {
 			},
 			newAPI: &API{
 				Funcs: map[string]*Function{
-					"NewFunc": {Name: "NewFunc", Signature: "func() error"},
+					"NewFunc": {Name: "NewFunc", Signature: Signature{Text: "func() error"}},
 				},
 			},
 			usage: &Usage{
@@ -107,12 +107,12 @@ func TestDiffAPIs(t *testing.T) {
 			name: "signature changed",
 			oldAPI: &API{
 				Funcs: map[string]*Function{
-					"Func": {Name: "Func", Signature: "func() error"},
+					"Func": {Name: "Func", Signature: Signature{Text: "func() error"}},
 				},
 			},
 			newAPI: &API{
 				Funcs: map[string]*Function{
-					"Func": {Name: "Func", Signature: "func(context.Context) error"},
+					"Func": {Name: "Func", Signature: Signature{Text: "func(context.Context) error"}},
 				},
 			},
 			usage: &Usage{
@@ -344,3 +344,70 @@ func TestDiffInterfaces(t *testing.T) {
 		})
 	}
 }
+
+func TestDiffAPIsMarksUnstableFindings(t *testing.T) {
+	oldAPI := &API{
+		Funcs: map[string]*Function{
+			"Fast":  {Name: "Fast", Signature: Signature{Text: "func()"}, Unstable: true},
+			"Parse": {Name: "Parse", Signature: Signature{Text: "func()"}},
+		},
+	}
+	newAPI := &API{
+		Funcs: map[string]*Function{
+			"Parse": {Name: "Parse", Signature: Signature{Text: "func(int)"}},
+		},
+	}
+	usage := &Usage{
+		Symbols: map[string][]Location{
+			"Fast":  {{File: "main.go", Line: 1}},
+			"Parse": {{File: "main.go", Line: 2}},
+		},
+	}
+
+	diff := diffAPIs(oldAPI, newAPI, usage)
+
+	if len(diff.Removed) != 1 || !diff.Removed[0].Unstable {
+		t.Fatalf("diffAPIs() Removed = %+v, want unstable Fast", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].Unstable {
+		t.Fatalf("diffAPIs() Changed = %+v, want stable Parse", diff.Changed)
+	}
+	if diff.BreakingCount() != 1 || diff.UnstableCount() != 1 {
+		t.Fatalf("diffAPIs() breaking = %d, unstable = %d, want 1 and 1", diff.BreakingCount(), diff.UnstableCount())
+	}
+}
+
+func TestDedupeFindings(t *testing.T) {
+	locs := []Location{{File: "main.go", Line: 10}, {File: "util.go", Line: 4}}
+	reversed := []Location{locs[1], locs[0]}
+
+	diff := &Diff{
+		Removed: []RemovedSymbol{
+			{Name: "Config", Type: "function", UsedIn: locs},
+			{Name: "Config", Type: "type", UsedIn: reversed},
+			{Name: "Config", Type: "interface", UsedIn: locs[:1]},
+		},
+		Changed: []ChangedSignature{
+			{Name: "Config", UsedIn: locs},
+			{Name: "Parse", UsedIn: locs},
+		},
+		InterfaceChanges: []InterfaceChange{
+			{Name: "Config", UsedIn: locs},
+		},
+	}
+
+	dedupeFindings(diff)
+
+	if len(diff.Removed) != 2 {
+		t.Fatalf("dedupeFindings() Removed = %+v, want 2 entries", diff.Removed)
+	}
+	if diff.Removed[0].Type != "function/type" {
+		t.Fatalf("dedupeFindings() merged type = %q, want %q", diff.Removed[0].Type, "function/type")
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].Name != "Parse" {
+		t.Fatalf("dedupeFindings() Changed = %+v, want only Parse", diff.Changed)
+	}
+	if len(diff.InterfaceChanges) != 0 {
+		t.Fatalf("dedupeFindings() InterfaceChanges = %+v, want none", diff.InterfaceChanges)
+	}
+}
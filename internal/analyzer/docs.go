@@ -0,0 +1,203 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+)
+
+// Reasons a doc comment change is reported
+const (
+	DocDeprecated    = "now deprecated"
+	DocUndeprecated  = "no longer deprecated"
+	DocErrorSemantic = "error or panic behavior wording changed"
+	DocRewritten     = "documentation rewritten"
+)
+
+// docRewriteSimilarity is the word overlap below which a doc comment counts as rewritten
+const docRewriteSimilarity = 0.5
+
+// errorWordPrefixes mark sentences that describe errors, panics, or nil
+// results; they match the start of a word, so ErrNotFound counts as well
+var errorWordPrefixes = []string{"err", "panic", "nil", "fail", "invalid"}
+
+// DocChange describes a significant change to the doc comment of a used symbol
+type DocChange struct {
+	Name    string
+	Package string
+	Reasons []string
+	Removed []string // notable sentences only in the old doc
+	Added   []string // notable sentences only in the new doc
+	UsedIn  []Location
+}
+
+// diffDocs compares the doc comments of symbols the project uses and present
+// in both versions, keeping only changes that likely affect callers
+func diffDocs(oldAPI, newAPI *API, usage *Usage) []DocChange {
+	var changes []DocChange
+	check := func(name, pkg, oldDoc, newDoc string) {
+		locations := usage.Symbols[name]
+		if len(locations) == 0 || oldDoc == newDoc {
+			return
+		}
+		if change := compareDocs(oldDoc, newDoc); change != nil {
+			change.Name = name
+			change.Package = pkg
+			change.UsedIn = locations
+			changes = append(changes, *change)
+		}
+	}
+
+	for name, oldFunc := range oldAPI.Funcs {
+		if newFunc, ok := newAPI.Funcs[name]; ok {
+			check(name, newFunc.PkgPath, oldFunc.Doc, newFunc.Doc)
+		}
+	}
+	for name, oldType := range oldAPI.Types {
+		if newType, ok := newAPI.Types[name]; ok {
+			check(name, newType.PkgPath, oldType.Doc, newType.Doc)
+		}
+	}
+	for name, oldIface := range oldAPI.Interfaces {
+		if newIface, ok := newAPI.Interfaces[name]; ok {
+			check(name, newIface.PkgPath, oldIface.Doc, newIface.Doc)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Name != changes[j].Name {
+			return changes[i].Name < changes[j].Name
+		}
+		return changes[i].Package < changes[j].Package
+	})
+	return changes
+}
+
+// compareDocs classifies the difference between two doc comments, returning
+// nil when the change is cosmetic
+func compareDocs(oldDoc, newDoc string) *DocChange {
+	change := &DocChange{}
+	oldDeprecated, newDeprecated := deprecationNotice(oldDoc), deprecationNotice(newDoc)
+	switch {
+	case newDeprecated != "" && oldDeprecated == "":
+		change.Reasons = append(change.Reasons, DocDeprecated)
+		change.Added = append(change.Added, newDeprecated)
+	case oldDeprecated != "" && newDeprecated == "":
+		change.Reasons = append(change.Reasons, DocUndeprecated)
+		change.Removed = append(change.Removed, oldDeprecated)
+	}
+
+	removed, added := sentenceDiff(errorSentences(oldDoc), errorSentences(newDoc))
+	if len(removed) > 0 || len(added) > 0 {
+		change.Reasons = append(change.Reasons, DocErrorSemantic)
+		change.Removed = append(change.Removed, removed...)
+		change.Added = append(change.Added, added...)
+	}
+
+	if len(change.Reasons) == 0 && oldDoc != "" && newDoc != "" && wordSimilarity(oldDoc, newDoc) < docRewriteSimilarity {
+		change.Reasons = append(change.Reasons, DocRewritten)
+	}
+
+	if len(change.Reasons) == 0 {
+		return nil
+	}
+	return change
+}
+
+// deprecationNotice returns the "Deprecated:" paragraph of a doc comment
+func deprecationNotice(doc string) string {
+	for _, para := range strings.Split(doc, "\n\n") {
+		para = strings.TrimSpace(para)
+		if strings.HasPrefix(para, "Deprecated:") {
+			return strings.Join(strings.Fields(para), " ")
+		}
+	}
+	return ""
+}
+
+// docSentences splits a doc comment into whitespace-normalized sentences
+func docSentences(doc string) []string {
+	var sentences []string
+	text := strings.Join(strings.Fields(doc), " ")
+	for text != "" {
+		end := strings.Index(text, ". ")
+		if end < 0 {
+			sentences = append(sentences, text)
+			break
+		}
+		sentences = append(sentences, text[:end+1])
+		text = text[end+2:]
+	}
+	return sentences
+}
+
+// errorSentences returns the sentences of a doc comment that describe
+// errors, panics, or nil results, excluding deprecation notices
+func errorSentences(doc string) []string {
+	var matches []string
+	for _, sentence := range docSentences(doc) {
+		if strings.HasPrefix(sentence, "Deprecated:") {
+			continue
+		}
+		if mentionsErrors(sentence) {
+			matches = append(matches, sentence)
+		}
+	}
+	return matches
+}
+
+// mentionsErrors reports whether any word of sentence starts with an error word
+func mentionsErrors(sentence string) bool {
+	for _, word := range strings.Fields(strings.ToLower(sentence)) {
+		word = strings.TrimLeft(word, "(\"'`*.")
+		for _, prefix := range errorWordPrefixes {
+			if strings.HasPrefix(word, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sentenceDiff returns the sentences only in old and only in new
+func sentenceDiff(old, new []string) (removed, added []string) {
+	inOld := make(map[string]bool, len(old))
+	for _, s := range old {
+		inOld[s] = true
+	}
+	inNew := make(map[string]bool, len(new))
+	for _, s := range new {
+		inNew[s] = true
+		if !inOld[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range old {
+		if !inNew[s] {
+			removed = append(removed, s)
+		}
+	}
+	return removed, added
+}
+
+// wordSimilarity is the Jaccard index of the lowercased words of two texts
+func wordSimilarity(a, b string) float64 {
+	words := func(s string) map[string]bool {
+		set := make(map[string]bool)
+		for _, w := range strings.Fields(strings.ToLower(s)) {
+			set[strings.Trim(w, ".,;:()\"'`")] = true
+		}
+		return set
+	}
+	wa, wb := words(a), words(b)
+	shared := 0
+	for w := range wa {
+		if wb[w] {
+			shared++
+		}
+	}
+	union := len(wa) + len(wb) - shared
+	if union == 0 {
+		return 1
+	}
+	return float64(shared) / float64(union)
+}
@@ -0,0 +1,118 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestCompareDocs(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldDoc      string
+		newDoc      string
+		wantReasons []string
+		wantAdded   []string
+		wantRemoved []string
+	}{
+		{
+			name:   "cosmetic rewording",
+			oldDoc: "Open opens the named file for reading.\n",
+			newDoc: "Open opens the named file for reading only.\n",
+		},
+		{
+			name:        "newly deprecated",
+			oldDoc:      "Open opens the named file.\n",
+			newDoc:      "Open opens the named file.\n\nDeprecated: use OpenFile\ninstead.\n",
+			wantReasons: []string{DocDeprecated},
+			wantAdded:   []string{"Deprecated: use OpenFile instead."},
+		},
+		{
+			name:        "no longer deprecated",
+			oldDoc:      "Open opens the named file.\n\nDeprecated: use OpenFile.\n",
+			newDoc:      "Open opens the named file.\n",
+			wantReasons: []string{DocUndeprecated},
+			wantRemoved: []string{"Deprecated: use OpenFile."},
+		},
+		{
+			name:        "error semantics",
+			oldDoc:      "Get fetches the value. It returns nil if the key is missing.\n",
+			newDoc:      "Get fetches the value. It returns ErrNotFound if the key is missing.\n",
+			wantReasons: []string{DocErrorSemantic},
+			wantRemoved: []string{"It returns nil if the key is missing."},
+			wantAdded:   []string{"It returns ErrNotFound if the key is missing."},
+		},
+		{
+			name:        "rewritten",
+			oldDoc:      "Flush writes buffered data to the underlying writer.\n",
+			newDoc:      "Flush is a no-op kept for compatibility; data is written immediately.\n",
+			wantReasons: []string{DocRewritten},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := compareDocs(tt.oldDoc, tt.newDoc)
+			if tt.wantReasons == nil {
+				if change != nil {
+					t.Fatalf("compareDocs() = %+v, want nil", change)
+				}
+				return
+			}
+			if change == nil {
+				t.Fatalf("compareDocs() = nil, want %v", tt.wantReasons)
+			}
+			if !reflect.DeepEqual(change.Reasons, tt.wantReasons) {
+				t.Errorf("Reasons = %v, want %v", change.Reasons, tt.wantReasons)
+			}
+			if !reflect.DeepEqual(change.Added, tt.wantAdded) || !reflect.DeepEqual(change.Removed, tt.wantRemoved) {
+				t.Errorf("Added = %q, Removed = %q, want %q, %q", change.Added, change.Removed, tt.wantAdded, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestDiffDocs_OnlyUsedSymbols(t *testing.T) {
+	oldPkg := checkSource(t, "example.com/lib", `package lib
+
+// Used does things.
+func Used() {}
+
+// Unused does things.
+func Unused() {}
+
+// Conn is a connection.
+type Conn struct{}
+`, nil)
+	newPkg := checkSource(t, "example.com/lib", `package lib
+
+// Used does things.
+//
+// Deprecated: use Other.
+func Used() {}
+
+// Unused does things.
+//
+// Deprecated: use Other.
+func Unused() {}
+
+// Conn is a connection. Close panics if called twice.
+type Conn struct{}
+`, nil)
+
+	usage := &Usage{Symbols: map[string][]Location{
+		"Used": {{File: "main.go", Line: 3}},
+		"Conn": {{File: "main.go", Line: 4}},
+	}}
+	changes := diffDocs(extractAPI([]*packages.Package{oldPkg}), extractAPI([]*packages.Package{newPkg}), usage)
+
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.Name+": "+c.Reasons[0])
+	}
+	want := []string{"Conn: " + DocErrorSemantic, "Used: " + DocDeprecated}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffDocs() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// Every mode that edits project code (Options.Fix and Options.Shims) goes
+// through editGo in this file: edits are only made to a clean git tree, so
+// they can be reviewed and reverted with git, and written files are
+// gofmt-formatted with the imports they need.
+
+// runGitCommand runs git in dir. Allow overriding in tests.
+var runGitCommand = func(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return out, fmt.Errorf("git %s: %s", args[0], bytes.TrimSpace(exitErr.Stderr))
+	}
+	return out, err
+}
+
+// editsCode reports whether the options make the analyzer write project
+// code; edits previewed with DryRun write nothing
+func (o Options) editsCode() bool {
+	return (o.Fix || o.Shims != "") && !o.DryRun
+}
+
+// checkClean refuses to edit a project whose git tree has uncommitted or
+// untracked changes, unless Options.AllowDirty is set. Projects outside a
+// git repository, or without git installed, cannot be checked and are
+// edited as is.
+func (a *Analyzer) checkClean() error {
+	if a.opts.AllowDirty {
+		return nil
+	}
+	out, err := runGitCommand(a.projectPath, "status", "--porcelain")
+	if err != nil {
+		return nil
+	}
+	var dirty []string
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if len(line) > 3 {
+			dirty = append(dirty, line[3:])
+		}
+	}
+	if len(dirty) == 0 {
+		return nil
+	}
+	const shown = 3
+	files := strings.Join(dirty[:min(len(dirty), shown)], ", ")
+	if len(dirty) > shown {
+		files += fmt.Sprintf(" and %d more", len(dirty)-shown)
+	}
+	return fmt.Errorf("refusing to edit a git tree with uncommitted changes (%s); commit or stash them, or use -allow-dirty", files)
+}
+
+// formatGo formats Go source like gofmt after adding the imports it needs,
+// which are skipped when already imported under any name
+func formatGo(path string, src []byte, imports []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("edited %s does not parse: %w", path, err)
+	}
+	for _, imp := range imports {
+		imported := false
+		for _, spec := range file.Imports {
+			if p, _ := strconv.Unquote(spec.Path.Value); p == imp {
+				imported = true
+			}
+		}
+		if !imported {
+			astutil.AddImport(fset, file, imp)
+		}
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, fmt.Errorf("failed to format %s: %w", path, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// FileDiff is the unified diff of an edit to a project file
+type FileDiff struct {
+	File string // slash-separated path relative to the project
+	Diff string
+}
+
+// editGo formats src with formatGo and returns its diff from the current
+// content of path, writing it unless Options.DryRun previews the edit. It is
+// the only function that writes project code, and a written file keeps the
+// permissions of the file it replaces.
+func (a *Analyzer) editGo(path string, src []byte, imports []string) (FileDiff, error) {
+	out, err := formatGo(path, src, imports)
+	if err != nil {
+		return FileDiff{}, err
+	}
+	old, err := os.ReadFile(path)
+	created := os.IsNotExist(err)
+	if err != nil && !created {
+		return FileDiff{}, err
+	}
+	name := path
+	if rel, ok := relPath(a.projectPath, path); ok {
+		name = rel
+	}
+	name = filepath.ToSlash(name)
+
+	diff := FileDiff{File: name, Diff: unifiedDiff(name, old, out)}
+	if created {
+		diff.Diff = newFileDiff(name, out)
+	}
+	if a.opts.DryRun {
+		return diff, nil
+	}
+	// analyze only checks the tree is clean when the options edit code
+	if !a.opts.editsCode() {
+		return FileDiff{}, fmt.Errorf("refusing to write %s: neither Fix nor Shims is set", name)
+	}
+
+	perm := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	return diff, os.WriteFile(path, out, perm)
+}
@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckClean(t *testing.T) {
+	orig := runGitCommand
+	defer func() { runGitCommand = orig }()
+
+	tests := []struct {
+		name       string
+		status     string
+		gitErr     error
+		allowDirty bool
+		wantErr    string
+	}{
+		{name: "clean tree", status: ""},
+		{name: "not a git repository", gitErr: errors.New("git status: fatal: not a git repository")},
+		{name: "dirty tree", status: " M main.go\n?? notes.txt\n", wantErr: "uncommitted changes (main.go, notes.txt)"},
+		{name: "many changes", status: " M a.go\n M b.go\n M c.go\n M d.go\n M e.go\n", wantErr: "(a.go, b.go, c.go and 2 more)"},
+		{name: "allowed dirty tree", status: " M main.go\n", allowDirty: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runGitCommand = func(dir string, args ...string) ([]byte, error) {
+				return []byte(tt.status), tt.gitErr
+			}
+			a := &Analyzer{projectPath: ".", opts: Options{Fix: true, AllowDirty: tt.allowDirty}}
+			err := a.checkClean()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkClean() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), "-allow-dirty") {
+				t.Errorf("checkClean() error = %v, want it to contain %q and mention -allow-dirty", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatGo(t *testing.T) {
+	src := "package p\n\nimport (\n\tstdctx \"context\"\n)\n\nfunc f() {\nvar _ stdctx.Context\n_ = errors.New(\"x\")\n}\n"
+	got, err := formatGo("p.go", []byte(src), []string{"context", "errors"})
+	if err != nil {
+		t.Fatalf("formatGo() error = %v", err)
+	}
+	want := "package p\n\nimport (\n\tstdctx \"context\"\n\t\"errors\"\n)\n\nfunc f() {\n\tvar _ stdctx.Context\n\t_ = errors.New(\"x\")\n}\n"
+	if string(got) != want {
+		t.Errorf("formatGo() =\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := formatGo("p.go", []byte("package p\nfunc {"), nil); err == nil {
+		t.Error("formatGo() accepted source that does not parse")
+	}
+}
+
+func TestEditGoKeepsPermissions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "p.go")
+	if err := os.WriteFile(path, []byte("package p\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	a := &Analyzer{projectPath: dir, opts: Options{Fix: true}}
+	if _, err := a.editGo(path, []byte("package  p\nvar x=1\n"), nil); err != nil {
+		t.Fatalf("editGo() error = %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(path); string(data) != "package p\n\nvar x = 1\n" {
+		t.Errorf("written file = %q", data)
+	}
+}
+
+func TestEditGoRefusesWithoutEditMode(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "p.go")
+	a := &Analyzer{projectPath: dir}
+	if _, err := a.editGo(path, []byte("package p\n"), nil); err == nil {
+		t.Fatal("editGo() wrote a file without Fix or Shims, which skip the clean tree check")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("p.go was written, stat error = %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	"bytes"
+	"go/types"
+	"strings"
+)
+
+// interfaceMethods flattens the method set of a named interface, including
+// methods of embedded interfaces from any package. Every method is printed
+// with the interface itself as receiver, so a method keeps its description
+// when it moves between the interface and one it embeds. Methods that come
+// from an embedded interface are mapped to the interface that introduced them.
+func interfaceMethods(named *types.Named, iface *types.Interface) ([]string, map[string]string) {
+	pkg := named.Obj().Pkg()
+	qualifier := func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		return p.Name()
+	}
+
+	explicit := make(map[string]bool, iface.NumExplicitMethods())
+	for i := 0; i < iface.NumExplicitMethods(); i++ {
+		explicit[iface.ExplicitMethod(i).Name()] = true
+	}
+	var embedded map[string]string
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		embed, ok := iface.EmbeddedType(i).Underlying().(*types.Interface)
+		if !ok {
+			continue // type sets such as ~int | ~string have no methods
+		}
+		for j := 0; j < embed.NumMethods(); j++ {
+			name := embed.Method(j).Name()
+			if explicit[name] || embedded[name] != "" {
+				continue
+			}
+			if embedded == nil {
+				embedded = make(map[string]string)
+			}
+			embedded[name] = types.TypeString(iface.EmbeddedType(i), qualifier)
+		}
+	}
+
+	methods := make([]string, iface.NumMethods())
+	for i := range methods {
+		method := iface.Method(i)
+		var sig bytes.Buffer
+		types.WriteSignature(&sig, method.Type().(*types.Signature), nil)
+		methods[i] = "func (" + types.TypeString(named, nil) + ")." + method.Name() + sig.String()
+	}
+	return methods, embedded
+}
+
+// interfaceMethodName extracts the method name from a method description
+// such as "func (example.com/lib.Handler).Close() error"
+func interfaceMethodName(method string) string {
+	if i := strings.Index(method, ")."); i >= 0 {
+		method = method[i+2:]
+	}
+	name, _, _ := strings.Cut(method, "(")
+	return name
+}
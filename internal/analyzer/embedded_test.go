@@ -0,0 +1,76 @@
+package analyzer
+
+import (
+	"go/types"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestDiffInterfaces_Embedded(t *testing.T) {
+	other := checkSource(t, "example.com/other", `package other
+
+type Closer interface{ Close() error }
+`, nil)
+	deps := map[string]*types.Package{"example.com/other": other.Types}
+	oldLib := checkSource(t, "example.com/lib", `package lib
+
+type Handler interface {
+	Serve()
+	Close() error
+}
+
+type Conn interface{ Serve() }
+`, deps)
+	newLib := checkSource(t, "example.com/lib", `package lib
+
+import "example.com/other"
+
+type Handler interface {
+	other.Closer
+	Serve()
+}
+
+type Conn interface {
+	other.Closer
+	Serve()
+}
+`, deps)
+
+	oldAPI := extractAPI([]*packages.Package{oldLib})
+	newAPI := extractAPI([]*packages.Package{newLib})
+	if got, want := newAPI.Interfaces["Conn"].Embedded, map[string]string{"Close": "other.Closer"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Conn embedded = %v, want %v", got, want)
+	}
+	if got, want := newAPI.Interfaces["Conn"].Methods, []string{
+		"func (example.com/lib.Conn).Close() error",
+		"func (example.com/lib.Conn).Serve()",
+	}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Conn methods = %v, want %v", got, want)
+	}
+
+	diff := diffAPIs(oldAPI, newAPI, &Usage{All: true})
+	if len(diff.InterfaceChanges) != 1 {
+		t.Fatalf("InterfaceChanges = %+v, want only Conn", diff.InterfaceChanges)
+	}
+	change := diff.InterfaceChanges[0]
+	if change.Name != "Conn" || !reflect.DeepEqual(change.AddedMethods, []string{"func (example.com/lib.Conn).Close() error"}) {
+		t.Errorf("change = %+v, want Conn adding Close", change)
+	}
+	if got, want := change.AddedVia, map[string]string{"Close": "other.Closer"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AddedVia = %v, want %v", got, want)
+	}
+}
+
+func TestInterfaceMethodName(t *testing.T) {
+	tests := map[string]string{
+		"func (example.com/lib.Handler).Close() error":                   "Close",
+		"func (example.com/lib.Handler).Serve(f func(int)) (int, error)": "Serve",
+	}
+	for method, want := range tests {
+		if got := interfaceMethodName(method); got != want {
+			t.Errorf("interfaceMethodName(%q) = %q, want %q", method, got, want)
+		}
+	}
+}
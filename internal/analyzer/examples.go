@@ -0,0 +1,272 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxExampleLines caps embedded example code so reports stay readable
+const maxExampleLines = 40
+
+// exampleDirs are directory names conventionally holding runnable examples
+var exampleDirs = map[string]bool{"example": true, "examples": true, "_example": true, "_examples": true}
+
+// Example is a usage example from the new version for a changed or removed symbol
+type Example struct {
+	Symbol string // finding the example is for
+	Name   string // Example function, or function in an example directory
+	Dir    string // directory within the module, "." for the root
+	Code   string
+	Output string // expected output of a testable example, if any
+}
+
+// exampleSource is a candidate example parsed from the dependency
+type exampleSource struct {
+	target string // symbol a testable example documents, empty for example dirs
+	name   string
+	dir    string
+	code   string
+	output string
+	uses   map[string]bool // selector names used in the code
+}
+
+// findExamples searches the new version of module for Example functions and
+// example directories that show how to use the changed, moved, and removed
+// symbols of diff. Removed methods fall back to examples of their type.
+func (a *Analyzer) findExamples(module, version string, diff *Diff) ([]Example, error) {
+	var symbols []string
+	for _, changed := range diff.Changed {
+		symbols = append(symbols, changed.Name)
+	}
+	for _, moved := range diff.Moved {
+		symbols = append(symbols, moved.NewName)
+	}
+	for _, removed := range diff.Removed {
+		symbols = append(symbols, removed.Name)
+	}
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	info, err := a.downloadModule(module, version)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := collectExamples(info.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read examples of %s@%s: %w", module, version, err)
+	}
+
+	var examples []Example
+	seen := make(map[string]bool)
+	for _, symbol := range symbols {
+		if seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		if src := matchExample(symbol, sources); src != nil {
+			examples = append(examples, Example{
+				Symbol: symbol,
+				Name:   src.name,
+				Dir:    src.dir,
+				Code:   src.code,
+				Output: src.output,
+			})
+		}
+	}
+	sort.Slice(examples, func(i, j int) bool { return examples[i].Symbol < examples[j].Symbol })
+	return examples, nil
+}
+
+// matchExample picks the most relevant example for symbol: a testable example
+// of the symbol itself, then one of its receiver type, then example code that
+// calls it
+func matchExample(symbol string, sources []exampleSource) *exampleSource {
+	candidates := []func(src exampleSource) bool{
+		func(src exampleSource) bool { return src.target == symbol },
+	}
+	recv, method, isMethod := strings.Cut(symbol, ".")
+	if isMethod {
+		candidates = append(candidates, func(src exampleSource) bool { return src.target == recv })
+	} else {
+		method = symbol
+	}
+	candidates = append(candidates, func(src exampleSource) bool { return src.target == "" && src.uses[method] })
+
+	for _, match := range candidates {
+		for i := range sources {
+			if match(sources[i]) {
+				return &sources[i]
+			}
+		}
+	}
+	return nil
+}
+
+// collectExamples parses the testable examples and example directories of a
+// module checked out at root. Nested modules, vendor, and testdata are skipped.
+func collectExamples(root string) ([]exampleSource, error) {
+	var sources []exampleSource
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != root {
+			if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") {
+				return filepath.SkipDir
+			}
+			if _, err := statFile(filepath.Join(path, "go.mod")); err == nil && !exampleDirs[name] {
+				return filepath.SkipDir
+			}
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		found, err := parseExampleDir(path, filepath.ToSlash(rel), inExampleDir(rel))
+		if err != nil {
+			return err
+		}
+		sources = append(sources, found...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Testable examples first, in a stable order
+	sort.SliceStable(sources, func(i, j int) bool {
+		if (sources[i].target == "") != (sources[j].target == "") {
+			return sources[i].target != ""
+		}
+		if sources[i].dir != sources[j].dir {
+			return sources[i].dir < sources[j].dir
+		}
+		return sources[i].name < sources[j].name
+	})
+	return sources, nil
+}
+
+// inExampleDir reports whether a module-relative directory lies in an example directory
+func inExampleDir(rel string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if exampleDirs[part] {
+			return true
+		}
+	}
+	return false
+}
+
+// parseExampleDir extracts the testable examples of a directory, and every
+// function of it when it is an example directory
+func parseExampleDir(dir, rel string, exampleDir bool) ([]exampleSource, error) {
+	entries, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var tests []*ast.File
+	var sources []exampleSource
+	for _, path := range entries {
+		isTest := strings.HasSuffix(path, "_test.go")
+		if !isTest && !exampleDir {
+			continue
+		}
+		data, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(fset, path, data, parser.ParseComments)
+		if err != nil {
+			continue // examples are best-effort; skip files that do not parse
+		}
+		if isTest {
+			tests = append(tests, file)
+			continue
+		}
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				sources = append(sources, exampleSource{
+					name: fn.Name.Name,
+					dir:  rel,
+					code: formatExampleCode(fset, fn),
+					uses: selectorNames(fn),
+				})
+			}
+		}
+	}
+
+	for _, ex := range doc.Examples(tests...) {
+		sources = append(sources, exampleSource{
+			target: exampleTarget(ex.Name),
+			name:   "Example" + ex.Name,
+			dir:    rel,
+			code:   formatExampleCode(fset, ex.Code),
+			output: ex.Output,
+		})
+	}
+	return sources, nil
+}
+
+// exampleTarget maps an example name such as "Client_Do_retry" to the symbol
+// it documents, "Client.Do". Lowercase suffixes only name the example.
+func exampleTarget(name string) string {
+	var parts []string
+	for _, part := range strings.Split(name, "_") {
+		if part == "" || !ast.IsExported(part) {
+			break
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// selectorNames collects the selected names of a function, such as Do in c.Do()
+func selectorNames(node ast.Node) map[string]bool {
+	names := make(map[string]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			names[sel.Sel.Name] = true
+		}
+		return true
+	})
+	return names
+}
+
+// formatExampleCode prints example code, dropping the braces around a body and
+// capping its length
+func formatExampleCode(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, node); err != nil {
+		return ""
+	}
+	code := buf.String()
+	if _, ok := node.(*ast.BlockStmt); ok {
+		code = strings.TrimSuffix(strings.TrimPrefix(code, "{\n"), "}")
+		var lines []string
+		for _, line := range strings.Split(strings.TrimRight(code, "\n"), "\n") {
+			lines = append(lines, strings.TrimPrefix(line, "\t"))
+		}
+		code = strings.Join(lines, "\n")
+	}
+
+	lines := strings.Split(strings.TrimRight(code, "\n"), "\n")
+	if len(lines) > maxExampleLines {
+		lines = append(lines[:maxExampleLines], "// ...")
+	}
+	return strings.Join(lines, "\n")
+}
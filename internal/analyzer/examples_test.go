@@ -0,0 +1,98 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindExamples(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/lib\n")
+	writeFile(t, filepath.Join(dir, "lib.go"), `package lib
+
+type Client struct{}
+
+func (c *Client) Do(name string, retries int) error { return nil }
+
+func Open(path string, mode int) (*Client, error) { return nil, nil }
+`)
+	writeFile(t, filepath.Join(dir, "example_test.go"), `package lib_test
+
+import (
+	"fmt"
+
+	"example.com/lib"
+)
+
+func ExampleClient_Do_retry() {
+	c := &lib.Client{}
+	fmt.Println(c.Do("job", 3))
+	// Output: <nil>
+}
+
+func ExampleClient() {
+	_ = &lib.Client{}
+}
+`)
+	writeFile(t, filepath.Join(dir, "_examples", "basic", "main.go"), `package main
+
+import "example.com/lib"
+
+func main() {
+	c, err := lib.Open("data.db", 0)
+	if err != nil {
+		panic(err)
+	}
+	_ = c
+}
+`)
+	writeFile(t, filepath.Join(dir, "testdata", "example_test.go"), "package broken\n\nfunc ExampleOpen() {}\n")
+
+	restore := mockGoCommand(func(_ string, args ...string) ([]byte, error) {
+		return json.Marshal(moduleDownload{Path: "example.com/lib", Version: "v2.0.0", Dir: dir})
+	})
+	defer restore()
+
+	diff := &Diff{
+		Changed: []ChangedSignature{{Name: "Client.Do"}, {Name: "Open"}},
+		Removed: []RemovedSymbol{{Name: "Client.Close"}, {Name: "Dial"}},
+	}
+	a := &Analyzer{projectPath: "."}
+	examples, err := a.findExamples("example.com/lib", "v2.0.0", diff)
+	if err != nil {
+		t.Fatalf("findExamples() error = %v", err)
+	}
+
+	got := make(map[string]Example)
+	for _, ex := range examples {
+		got[ex.Symbol] = ex
+	}
+	if len(got) != 3 {
+		t.Fatalf("findExamples() = %+v, want examples for Client.Do, Client.Close, and Open", examples)
+	}
+	if ex := got["Client.Do"]; ex.Name != "ExampleClient_Do_retry" || ex.Output != "<nil>\n" || ex.Code != "c := &lib.Client{}\nfmt.Println(c.Do(\"job\", 3))" {
+		t.Errorf("Client.Do example = %+v", ex)
+	}
+	if ex := got["Client.Close"]; ex.Name != "ExampleClient" {
+		t.Errorf("Client.Close example = %+v, want the example of its type", ex)
+	}
+	if ex := got["Open"]; ex.Name != "main" || ex.Dir != "_examples/basic" {
+		t.Errorf("Open example = %+v, want main in _examples/basic", ex)
+	}
+}
+
+func TestExampleTarget(t *testing.T) {
+	tests := map[string]string{
+		"":               "",
+		"Open":           "Open",
+		"Client_Do":      "Client.Do",
+		"Client_Do_fast": "Client.Do",
+		"Open_second":    "Open",
+	}
+	for name, want := range tests {
+		if got := exampleTarget(name); got != want {
+			t.Errorf("exampleTarget(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package analyzer
+
+import "sort"
+
+// firstBreaking returns a diff holding only the first stable, used breaking
+// change in symbol order, or nil when there is none. It only visits the
+// symbols the project uses, so it is much cheaper than a full diff.
+func firstBreaking(oldAPI, newAPI *API, usage *Usage) *Diff {
+	names := make([]string, 0, len(usage.Symbols))
+	for name, locations := range usage.Symbols {
+		if len(locations) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		locations := usage.Symbols[name]
+		if oldFunc, ok := oldAPI.Funcs[name]; ok && !oldFunc.Unstable {
+			newFunc, exists := newAPI.Funcs[name]
+			switch {
+			case !exists:
+				return &Diff{Removed: []RemovedSymbol{{Name: name, Type: "function", Package: oldFunc.PkgPath, UsedIn: locations}}}
+			case !newFunc.Unstable && oldFunc.Signature.Text != newFunc.Signature.Text:
+				return &Diff{Changed: []ChangedSignature{{
+					Name:           name,
+					OldSignature:   oldFunc.Signature.Text,
+					NewSignature:   newFunc.Signature.Text,
+					Package:        newFunc.PkgPath,
+					UsedIn:         locations,
+					PromotedFrom:   newFunc.PromotedFrom,
+					BehaviorChange: behaviorChange(oldFunc, newFunc),
+					ParamChanges:   paramChanges(oldFunc.Signature, newFunc.Signature),
+				}}}
+			}
+		}
+		if oldType, ok := oldAPI.Types[name]; ok && !oldType.Unstable {
+			if _, exists := newAPI.Types[name]; !exists {
+				return &Diff{Removed: []RemovedSymbol{{Name: name, Type: "type", Package: oldType.PkgPath, UsedIn: locations}}}
+			}
+		}
+		if oldIface, ok := oldAPI.Interfaces[name]; ok && !oldIface.Unstable {
+			newIface, exists := newAPI.Interfaces[name]
+			if !exists {
+				return &Diff{Removed: []RemovedSymbol{{Name: name, Type: "interface", Package: oldIface.PkgPath, UsedIn: locations}}}
+			}
+			if change := diffInterfaces(name, oldIface, newIface, usage); change != nil && !change.Unstable {
+				return &Diff{InterfaceChanges: []InterfaceChange{*change}}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package analyzer
+
+import "testing"
+
+func TestFirstBreaking(t *testing.T) {
+	oldAPI := &API{
+		Funcs: map[string]*Function{
+			"Alpha":   {Name: "Alpha", Signature: Signature{Text: "func()"}},
+			"Beta":    {Name: "Beta", Signature: Signature{Text: "func()"}},
+			"Gamma":   {Name: "Gamma", Signature: Signature{Text: "func()"}},
+			"Preview": {Name: "Preview", Signature: Signature{Text: "func()"}, Unstable: true},
+		},
+		Types:      map[string]*Type{"Config": {Name: "Config"}},
+		Interfaces: map[string]*Interface{},
+	}
+	newAPI := &API{
+		Funcs: map[string]*Function{
+			"Alpha": {Name: "Alpha", Signature: Signature{Text: "func()"}},
+			"Beta":  {Name: "Beta", Signature: Signature{Text: "func(int)"}},
+		},
+		Types:      map[string]*Type{},
+		Interfaces: map[string]*Interface{},
+	}
+	loc := []Location{{File: "main.go", Line: 1}}
+
+	tests := []struct {
+		name    string
+		used    []string
+		wantNil bool
+		want    string
+	}{
+		{name: "nothing used breaks", used: []string{"Alpha"}, wantNil: true},
+		{name: "unstable removal is not breaking", used: []string{"Preview"}, wantNil: true},
+		{name: "first in symbol order wins", used: []string{"Gamma", "Beta", "Config"}, want: "Beta"},
+		{name: "removed type", used: []string{"Config", "Gamma"}, want: "Config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage := &Usage{Symbols: map[string][]Location{}}
+			for _, name := range tt.used {
+				usage.Symbols[name] = loc
+			}
+			diff := firstBreaking(oldAPI, newAPI, usage)
+			if tt.wantNil {
+				if diff != nil {
+					t.Fatalf("firstBreaking() = %+v, want nil", diff)
+				}
+				return
+			}
+			if diff == nil || diff.BreakingCount() != 1 {
+				t.Fatalf("firstBreaking() = %+v, want exactly one finding", diff)
+			}
+			var got string
+			for _, r := range diff.Removed {
+				got = r.Name
+			}
+			for _, c := range diff.Changed {
+				got = c.Name
+			}
+			if got != tt.want {
+				t.Errorf("firstBreaking() found %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
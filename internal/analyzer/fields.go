@@ -0,0 +1,107 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// structFields lists the exported fields declared directly on a struct type,
+// which are the fields a keyed composite literal can set
+func structFields(named *types.Named) []string {
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	var fields []string
+	for i := 0; i < st.NumFields(); i++ {
+		if field := st.Field(i); field.Exported() {
+			fields = append(fields, field.Name())
+		}
+	}
+	return fields
+}
+
+// recordKeyedFields records the fields set by keyed composite literals of
+// structs from the target packages, such as lib.Config{Value: 1}, as
+// Type.Field symbols
+func recordKeyedFields(usage *Usage, pkg *packages.Package, approximate bool) {
+	for _, file := range pkg.Syntax {
+		ast.Inspect(file, func(n ast.Node) bool {
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+			named := literalStruct(pkg.TypesInfo.TypeOf(lit))
+			if named == nil {
+				return true
+			}
+			typeName, ok := usage.typeName(named)
+			if !ok {
+				return true
+			}
+			for _, elt := range lit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				key, ok := kv.Key.(*ast.Ident)
+				if !ok || !key.IsExported() {
+					continue
+				}
+				symbolName := typeName + "." + key.Name
+				pos := pkg.Fset.Position(key.Pos())
+				usage.Symbols[symbolName] = append(usage.Symbols[symbolName], Location{
+					File:        pos.Filename,
+					Line:        pos.Line,
+					Column:      pos.Column,
+					Kind:        UsageKeyedField,
+					Approximate: approximate,
+				})
+			}
+			return true
+		})
+	}
+}
+
+// literalStruct returns the named struct type a composite literal builds,
+// looking through the pointer of elided &T{...} elements
+func literalStruct(t types.Type) *types.Named {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return nil
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nil
+	}
+	return named
+}
+
+// diffFields reports the fields of a struct that the new version removed
+// while the project still sets them in keyed composite literals
+func diffFields(name string, oldType, newType *Type, usage *Usage, diff *Diff) {
+	kept := make(map[string]bool, len(newType.Fields))
+	for _, field := range newType.Fields {
+		kept[field] = true
+	}
+	for _, field := range oldType.Fields {
+		if kept[field] {
+			continue
+		}
+		symbolName := name + "." + field
+		if locations, used := usage.uses(symbolName); used {
+			diff.Removed = append(diff.Removed, RemovedSymbol{
+				Name:      symbolName,
+				Type:      "field",
+				Package:   oldType.PkgPath,
+				UsedIn:    locations,
+				Unstable:  oldType.Unstable,
+				ViaModule: oldType.ViaModule,
+			})
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"go/types"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestDiffAPIs_KeyedFieldRemoved(t *testing.T) {
+	oldLib := checkSource(t, "example.com/lib", `package lib
+
+type Config struct {
+	Name  string
+	Value int
+	Debug bool
+}
+`, nil)
+	newLib := checkSource(t, "example.com/lib", `package lib
+
+type Config struct {
+	Name  string
+	Debug bool
+}
+`, nil)
+	app := checkSource(t, "example.com/app", `package app
+
+import "example.com/lib"
+
+var configs = []*lib.Config{
+	{Name: "a", Value: 1},
+}
+
+func run() {
+	_ = lib.Config{Name: "x", Value: 1}
+	_ = &lib.Config{Debug: true}
+	_ = lib.Config{"y", 2, false}
+}
+`, map[string]*types.Package{"example.com/lib": oldLib.Types})
+	app.Imports = map[string]*packages.Package{
+		"example.com/lib": {PkgPath: "example.com/lib", Module: &packages.Module{Path: "example.com/lib"}},
+	}
+
+	a := &Analyzer{pkgs: []*packages.Package{app}}
+	usage := a.findUsage("example.com/lib")
+	oldAPI := extractAPI([]*packages.Package{oldLib})
+	if got, want := oldAPI.Types["Config"].Fields, []string{"Name", "Value", "Debug"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Config fields = %v, want %v", got, want)
+	}
+
+	diff := diffAPIs(oldAPI, extractAPI([]*packages.Package{newLib}), usage)
+	if len(diff.Removed) != 1 {
+		t.Fatalf("Removed = %+v, want only Config.Value", diff.Removed)
+	}
+	removed := diff.Removed[0]
+	if removed.Name != "Config.Value" || removed.Type != "field" {
+		t.Errorf("Removed = %s (%s), want Config.Value (field)", removed.Name, removed.Type)
+	}
+	var lines []int
+	for _, loc := range removed.UsedIn {
+		if loc.Kind != UsageKeyedField {
+			t.Errorf("location kind = %q, want %q", loc.Kind, UsageKeyedField)
+		}
+		lines = append(lines, loc.Line)
+	}
+	if want := []int{6, 10}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("Config.Value used on lines %v, want %v", lines, want)
+	}
+}
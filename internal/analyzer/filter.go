@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// filterPackages drops dependency packages that are not part of its public API
+// unless the analyzer was configured to include them. Other internal packages
+// and commands are always dropped.
+func (a *Analyzer) filterPackages(pkgs []*packages.Package) []*packages.Package {
+	var kept []*packages.Package
+	for _, pkg := range pkgs {
+		if isTestOrExamplePackage(pkg) {
+			if !a.opts.IncludeTestPackages {
+				continue
+			}
+		} else if pkg.Name == "main" || isInternalPackage(pkg) {
+			continue
+		}
+		kept = append(kept, pkg)
+	}
+	return kept
+}
+
+// isInternalPackage reports whether a package is internal to its module, so
+// the project cannot import it
+func isInternalPackage(pkg *packages.Package) bool {
+	rel := pkg.PkgPath
+	if pkg.Module != nil {
+		rel = strings.TrimPrefix(rel, pkg.Module.Path)
+	}
+	for _, elem := range strings.Split(rel, "/") {
+		if elem == "internal" {
+			return true
+		}
+	}
+	return false
+}
+
+// isTestOrExamplePackage reports whether a package only exists for tests or
+// documentation: external _test packages, and anything under examples or testdata
+func isTestOrExamplePackage(pkg *packages.Package) bool {
+	if strings.HasSuffix(pkg.Name, "_test") || strings.HasSuffix(pkg.PkgPath, "_test") || strings.HasSuffix(pkg.PkgPath, ".test") {
+		return true
+	}
+	for _, elem := range strings.Split(pkg.PkgPath, "/") {
+		switch elem {
+		case "testdata", "example", "examples", "_example", "_examples":
+			return true
+		}
+	}
+	return false
+}
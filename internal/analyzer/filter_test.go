@@ -0,0 +1,32 @@
+package analyzer
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestFilterPackages(t *testing.T) {
+	pkgs := []*packages.Package{
+		{Name: "lib", PkgPath: "example.com/lib"},
+		{Name: "lib_test", PkgPath: "example.com/lib_test"},
+		{Name: "main", PkgPath: "example.com/lib/examples/server"},
+		{Name: "fixture", PkgPath: "example.com/lib/internal/testdata/fixture"},
+		{Name: "codec", PkgPath: "example.com/lib/codec"},
+	}
+
+	a := &Analyzer{}
+	kept := a.filterPackages(pkgs)
+	if len(kept) != 2 || kept[0].PkgPath != "example.com/lib" || kept[1].PkgPath != "example.com/lib/codec" {
+		var paths []string
+		for _, pkg := range kept {
+			paths = append(paths, pkg.PkgPath)
+		}
+		t.Fatalf("filterPackages() kept %v, want [example.com/lib example.com/lib/codec]", paths)
+	}
+
+	a.opts.IncludeTestPackages = true
+	if kept := a.filterPackages(pkgs); len(kept) != len(pkgs) {
+		t.Fatalf("filterPackages() with IncludeTestPackages kept %d, want %d", len(kept), len(pkgs))
+	}
+}
@@ -0,0 +1,350 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strconv"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// Contexts passed to functions that gained a leading context.Context, see
+// Options.FixContext
+const (
+	FixContextTODO       = "todo"       // context.TODO(), marking the call for a real context later
+	FixContextBackground = "background" // context.Background()
+)
+
+// Policies for the error a function newly returns, see Options.FixPolicy
+const (
+	FixPolicyTODO    = "todo"    // assign it to err and check it under a TODO comment
+	FixPolicyDiscard = "discard" // assign it to _
+)
+
+// FixReport lists the sites rewritten by Options.Fix and the ones that were
+// left for a person to migrate
+type FixReport struct {
+	Files   []string   // rewritten files
+	Fixed   []FixSite  // rewritten sites
+	Skipped []FixSite  // sites of fixable changes that were not rewritten
+	Diffs   []FileDiff // the edits of each rewritten file
+	DryRun  bool       // the edits were only previewed, see Options.DryRun
+
+	// With Options.RunTests, the project's tests run again against the new
+	// version once files are rewritten
+	Tested       bool
+	TestFailures []TestFailure // tests failing after the rewrite that passed before the upgrade
+}
+
+// FixSite is a call site, or a project type declaration, considered by
+// Options.Fix
+type FixSite struct {
+	Symbol   string // the changed function or interface
+	Location Location
+	Fix      string // the rewrite, such as "pass context.TODO()"
+	Reason   string // why a skipped site was not rewritten
+}
+
+// rewrite is the fix of one call site: source edits and the imports they need
+type rewrite struct {
+	desc    string
+	edits   []textEdit
+	imports []string
+}
+
+// textEdit replaces the source between two file offsets
+type textEdit struct {
+	start, end int
+	text       string
+}
+
+// fixEdit computes the rewrite of one call in a parsed file, or an error
+// naming why the call is left alone
+type fixEdit func(src []byte, fset *token.FileSet, file *ast.File, call *ast.CallExpr) (*rewrite, error)
+
+// pendingFix is a site to fix in a file, with the function computing its
+// rewrite once the file is parsed
+type pendingFix struct {
+	symbol string
+	loc    Location
+	apply  func(src []byte, fset *token.FileSet, file *ast.File) (*rewrite, error)
+}
+
+// fixSites rewrites what can be migrated mechanically: calls of changed
+// functions whose only change is a new leading context.Context or a new
+// error result, and project types missing methods an interface gained (see
+// stubFixes). Files are parsed once and written back only when a site in
+// them changed.
+func (a *Analyzer) fixSites(diff *Diff, newAPI *API) (*FixReport, error) {
+	byFile := make(map[string][]pendingFix) // by pathKey of the file
+	for _, changed := range diff.Changed {
+		edit := a.fixFor(changed)
+		if edit == nil {
+			continue
+		}
+		seen := make(map[Location]bool)
+		for _, loc := range changed.UsedIn {
+			if seen[locationKey(loc)] {
+				continue
+			}
+			seen[locationKey(loc)] = true
+			byFile[pathKey(loc.File)] = append(byFile[pathKey(loc.File)], pendingFix{changed.Name, loc, callFix(loc, edit)})
+		}
+	}
+	for _, fix := range a.stubFixes(diff, newAPI) {
+		byFile[pathKey(fix.loc.File)] = append(byFile[pathKey(fix.loc.File)], fix)
+	}
+
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	fixes := &FixReport{DryRun: a.opts.DryRun}
+	for _, key := range files {
+		path := byFile[key][0].loc.File
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		var edits []textEdit
+		var imports []string
+		for _, fix := range byFile[key] {
+			fixSite := FixSite{Symbol: fix.symbol, Location: fix.loc}
+			rw, err := fix.apply(src, fset, file)
+			if err == nil && overlaps(edits, rw.edits) {
+				err = fmt.Errorf("overlaps another fix")
+			}
+			if err != nil {
+				fixSite.Reason = err.Error()
+				fixes.Skipped = append(fixes.Skipped, fixSite)
+				continue
+			}
+			fixSite.Fix = rw.desc
+			fixes.Fixed = append(fixes.Fixed, fixSite)
+			edits = append(edits, rw.edits...)
+			imports = append(imports, rw.imports...)
+		}
+		if len(edits) == 0 {
+			continue
+		}
+
+		diff, err := a.editGo(path, applyEdits(src, edits), imports)
+		if err != nil {
+			return nil, err
+		}
+		fixes.Files = append(fixes.Files, path)
+		fixes.Diffs = append(fixes.Diffs, diff)
+	}
+	return fixes, nil
+}
+
+// callFix applies edit to the call whose function name is at loc
+func callFix(loc Location, edit fixEdit) func(src []byte, fset *token.FileSet, file *ast.File) (*rewrite, error) {
+	return func(src []byte, fset *token.FileSet, file *ast.File) (*rewrite, error) {
+		call := callAt(fset, file, loc)
+		if loc.Kind != UsageCall || call == nil {
+			return nil, fmt.Errorf("not a call")
+		}
+		return edit(src, fset, file, call)
+	}
+}
+
+// fixFor returns the edit migrating calls of a changed function, or nil when
+// the change cannot be migrated mechanically
+func (a *Analyzer) fixFor(changed ChangedSignature) fixEdit {
+	switch {
+	case contextFirst(changed):
+		ctxFunc := "TODO"
+		if a.opts.FixContext == FixContextBackground {
+			ctxFunc = "Background"
+		}
+		return passContext(ctxFunc)
+	case errorAdded(changed):
+		return handleError(changed.Name, changed.ParamChanges[0].Position, a.opts.FixPolicy)
+	}
+	return nil
+}
+
+// contextFirst reports whether the only change to a function is a new
+// context.Context as its first parameter
+func contextFirst(changed ChangedSignature) bool {
+	if len(changed.ParamChanges) != 1 || changed.BehaviorChange != "" {
+		return false
+	}
+	pc := changed.ParamChanges[0]
+	return pc.Kind == ParamAdded && !pc.Result && !pc.Variadic && pc.Position == 1 && pc.NewType == "context.Context"
+}
+
+// errorAdded reports whether the only change to a function is a new error
+// result. Whether it is the last result is checked at each call site.
+func errorAdded(changed ChangedSignature) bool {
+	if len(changed.ParamChanges) != 1 || changed.BehaviorChange != "" {
+		return false
+	}
+	pc := changed.ParamChanges[0]
+	return pc.Kind == ParamAdded && pc.Result && pc.NewType == "error"
+}
+
+// passContext returns an edit that passes context.TODO() or
+// context.Background() as the first argument, importing context if needed
+func passContext(ctxFunc string) fixEdit {
+	return func(src []byte, fset *token.FileSet, file *ast.File, call *ast.CallExpr) (*rewrite, error) {
+		name := "context"
+		imported := false
+		for _, spec := range file.Imports {
+			if path, _ := strconv.Unquote(spec.Path.Value); path != "context" {
+				continue
+			}
+			imported = true
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+		}
+		if name == "_" || name == "." {
+			return nil, fmt.Errorf("context is imported as %s", name)
+		}
+
+		arg := name + "." + ctxFunc + "()"
+		text := arg
+		if len(call.Args) > 0 {
+			text += ", "
+		}
+		offset := fset.Position(call.Lparen).Offset + 1
+		rw := &rewrite{desc: "pass " + arg, edits: []textEdit{{offset, offset, text}}}
+		if !imported {
+			rw.imports = []string{"context"}
+		}
+		return rw, nil
+	}
+}
+
+// handleError returns an edit that receives the error a function now returns
+// as result number position: into err, checked under a TODO comment, or into
+// _ with FixPolicyDiscard
+func handleError(symbol string, position int, policy string) fixEdit {
+	todo := fmt.Sprintf("// TODO: handle the error %s now returns", symbol)
+	return func(src []byte, fset *token.FileSet, file *ast.File, call *ast.CallExpr) (*rewrite, error) {
+		path, _ := astutil.PathEnclosingInterval(file, call.Pos(), call.End())
+		if len(path) < 3 {
+			return nil, fmt.Errorf("result used in an expression")
+		}
+		offset := func(pos token.Pos) int { return fset.Position(pos).Offset }
+
+		switch stmt := path[1].(type) {
+		case *ast.ExprStmt:
+			// A dropped result still compiles, but a lone error is worth checking
+			if policy == FixPolicyDiscard || position != 1 {
+				return nil, fmt.Errorf("result is not used")
+			}
+			if !inBlock(path[2]) {
+				return nil, fmt.Errorf("call is part of a statement header")
+			}
+			text := fmt.Sprintf("if err := %s; err != nil {\n%s\n}", src[offset(call.Pos()):offset(call.End())], todo)
+			return &rewrite{desc: "check the returned error", edits: []textEdit{{offset(stmt.Pos()), offset(stmt.End()), text}}}, nil
+
+		case *ast.AssignStmt:
+			if len(stmt.Rhs) != 1 {
+				return nil, fmt.Errorf("result used in an expression")
+			}
+			if len(stmt.Lhs) != position-1 {
+				return nil, fmt.Errorf("error is not the last result")
+			}
+			end := offset(stmt.Lhs[len(stmt.Lhs)-1].End())
+			if policy == FixPolicyDiscard {
+				return &rewrite{desc: "discard the returned error", edits: []textEdit{{end, end, ", _"}}}, nil
+			}
+			if stmt.Tok != token.DEFINE {
+				return nil, fmt.Errorf("assignment has no error variable; use -fix-policy discard")
+			}
+			if !inBlock(path[2]) {
+				return nil, fmt.Errorf("call is part of a statement header")
+			}
+			after := offset(stmt.End())
+			check := fmt.Sprintf("\nif err != nil {\n%s\n}", todo)
+			return &rewrite{desc: "check the returned error", edits: []textEdit{{end, end, ", err"}, {after, after, check}}}, nil
+		}
+		return nil, fmt.Errorf("result used in an expression")
+	}
+}
+
+// inBlock reports whether a statement's parent is a list of statements,
+// where more statements can follow it
+func inBlock(parent ast.Node) bool {
+	switch parent.(type) {
+	case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+		return true
+	}
+	return false
+}
+
+// overlaps reports whether any of next touches the source replaced by edits.
+// Insertions at the same offset count as overlapping, as their order is
+// ambiguous.
+func overlaps(edits, next []textEdit) bool {
+	for _, e := range edits {
+		for _, n := range next {
+			if n.start <= e.end && e.start <= n.end {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyEdits splices edits into src, sorted by offset
+func applyEdits(src []byte, edits []textEdit) []byte {
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	out := append([]byte(nil), src...)
+	for _, e := range edits {
+		out = append(out[:e.start], append([]byte(e.text), out[e.end:]...)...)
+	}
+	return out
+}
+
+// callAt finds the call whose function name is at loc, such as lib.Foo in
+// lib.Foo(x) or M in v.M(x), looking through generic instantiations
+func callAt(fset *token.FileSet, file *ast.File, loc Location) *ast.CallExpr {
+	var found *ast.CallExpr
+	ast.Inspect(file, func(n ast.Node) bool {
+		if found != nil {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fun := astutil.Unparen(call.Fun)
+		switch f := fun.(type) {
+		case *ast.IndexExpr:
+			fun = f.X
+		case *ast.IndexListExpr:
+			fun = f.X
+		}
+		var ident *ast.Ident
+		switch f := fun.(type) {
+		case *ast.Ident:
+			ident = f
+		case *ast.SelectorExpr:
+			ident = f.Sel
+		}
+		if ident != nil {
+			if pos := fset.Position(ident.Pos()); pos.Line == loc.Line && pos.Column == loc.Column {
+				found = call
+			}
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,298 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFixSites_ContextFirst(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	src := `package main
+
+import "example.com/lib"
+
+func main() {
+	lib.Fetch("a")
+	f := lib.Fetch
+	_ = f
+	lib.Other(1)
+}
+`
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctxFirst := []ParamChange{{Kind: ParamAdded, Position: 1, Name: "ctx", NewType: "context.Context"}}
+	diff := &Diff{Changed: []ChangedSignature{
+		{Name: "Fetch", ParamChanges: ctxFirst, UsedIn: []Location{
+			{File: path, Line: 6, Column: 6, Kind: UsageCall},
+			{File: path, Line: 7, Column: 11},
+		}},
+		{Name: "Other", ParamChanges: []ParamChange{{Kind: ParamRetyped, Position: 1, OldType: "int", NewType: "int64"}}, UsedIn: []Location{
+			{File: path, Line: 9, Column: 6, Kind: UsageCall},
+		}},
+	}}
+
+	a := &Analyzer{projectPath: dir, opts: Options{Fix: true, FixContext: FixContextBackground}}
+	fixes, err := a.fixSites(diff, emptyAPI())
+	if err != nil {
+		t.Fatalf("fixSites() error = %v", err)
+	}
+
+	want := &FixReport{
+		Files:   []string{path},
+		Fixed:   []FixSite{{Symbol: "Fetch", Location: diff.Changed[0].UsedIn[0], Fix: "pass context.Background()"}},
+		Skipped: []FixSite{{Symbol: "Fetch", Location: diff.Changed[0].UsedIn[1], Reason: "not a call"}},
+		Diffs: []FileDiff{{File: "main.go", Diff: `--- a/main.go
++++ b/main.go
+@@ -1,9 +1,12 @@
+ package main
+ 
+-import "example.com/lib"
++import (
++	"context"
++	"example.com/lib"
++)
+ 
+ func main() {
+-	lib.Fetch("a")
++	lib.Fetch(context.Background(), "a")
+ 	f := lib.Fetch
+ 	_ = f
+ 	lib.Other(1)
+`}},
+	}
+	if !reflect.DeepEqual(fixes, want) {
+		t.Errorf("fixSites() = %+v, want %+v", fixes, want)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSrc := `package main
+
+import (
+	"context"
+	"example.com/lib"
+)
+
+func main() {
+	lib.Fetch(context.Background(), "a")
+	f := lib.Fetch
+	_ = f
+	lib.Other(1)
+}
+`
+	if string(got) != wantSrc {
+		t.Errorf("rewritten file:\n%s\nwant:\n%s", got, wantSrc)
+	}
+}
+
+func TestFixSites_DryRun(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	src := "package main\n\nimport \"example.com/lib\"\n\nfunc main() {\n\tlib.Fetch()\n}\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	diff := &Diff{Changed: []ChangedSignature{{
+		Name:         "Fetch",
+		ParamChanges: []ParamChange{{Kind: ParamAdded, Position: 1, Name: "ctx", NewType: "context.Context"}},
+		UsedIn:       []Location{{File: path, Line: 6, Column: 6, Kind: UsageCall}},
+	}}}
+
+	a := &Analyzer{projectPath: dir, opts: Options{Fix: true, DryRun: true}}
+	fixes, err := a.fixSites(diff, emptyAPI())
+	if err != nil {
+		t.Fatalf("fixSites() error = %v", err)
+	}
+	if !fixes.DryRun || len(fixes.Diffs) != 1 || !strings.Contains(fixes.Diffs[0].Diff, "+\tlib.Fetch(context.TODO())\n") {
+		t.Errorf("fixSites() = %+v, want a dry run previewing the fix", fixes)
+	}
+	if got, _ := os.ReadFile(path); string(got) != src {
+		t.Errorf("dry run wrote the file:\n%s", got)
+	}
+}
+
+func TestFixSites_ExistingContextImport(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "svc.go")
+	src := `package svc
+
+import (
+	stdctx "context"
+
+	"example.com/lib"
+)
+
+var _ stdctx.Context
+
+func run(c *lib.Client) error {
+	return c.Do()
+}
+`
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	diff := &Diff{Changed: []ChangedSignature{{
+		Name:         "Client.Do",
+		ParamChanges: []ParamChange{{Kind: ParamAdded, Position: 1, Name: "ctx", NewType: "context.Context"}},
+		UsedIn:       []Location{{File: path, Line: 12, Column: 11, Kind: UsageCall}},
+	}}}
+
+	a := &Analyzer{opts: Options{Fix: true}}
+	fixes, err := a.fixSites(diff, emptyAPI())
+	if err != nil {
+		t.Fatalf("fixSites() error = %v", err)
+	}
+	if len(fixes.Fixed) != 1 || fixes.Fixed[0].Fix != "pass stdctx.TODO()" {
+		t.Fatalf("Fixed = %+v, want one site passing stdctx.TODO()", fixes.Fixed)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "\treturn c.Do(stdctx.TODO())\n"; !strings.Contains(string(got), want) {
+		t.Errorf("rewritten file:\n%s\nwant it to contain %q", got, want)
+	}
+}
+
+func TestFixSites_ErrorAdded(t *testing.T) {
+	src := `package main
+
+import "example.com/lib"
+
+func main() {
+	v := lib.Load("a")
+	lib.Close()
+	w = lib.Load("b")
+	use(lib.Load("c"))
+	_, _ = v, w
+}
+`
+	errorResult := func(position int) []ParamChange {
+		return []ParamChange{{Kind: ParamAdded, Result: true, Position: position, NewType: "error"}}
+	}
+	tests := []struct {
+		name        string
+		policy      string
+		wantSrc     string
+		wantFixed   []string
+		wantSkipped []string
+	}{
+		{
+			name:   "todo",
+			policy: FixPolicyTODO,
+			wantSrc: `package main
+
+import "example.com/lib"
+
+func main() {
+	v, err := lib.Load("a")
+	if err != nil {
+		// TODO: handle the error Load now returns
+	}
+	if err := lib.Close(); err != nil {
+		// TODO: handle the error Close now returns
+	}
+	w = lib.Load("b")
+	use(lib.Load("c"))
+	_, _ = v, w
+}
+`,
+			wantFixed:   []string{"Load: check the returned error", "Close: check the returned error"},
+			wantSkipped: []string{"Load: assignment has no error variable; use -fix-policy discard", "Load: result used in an expression"},
+		},
+		{
+			name:   "discard",
+			policy: FixPolicyDiscard,
+			wantSrc: `package main
+
+import "example.com/lib"
+
+func main() {
+	v, _ := lib.Load("a")
+	lib.Close()
+	w, _ = lib.Load("b")
+	use(lib.Load("c"))
+	_, _ = v, w
+}
+`,
+			wantFixed:   []string{"Load: discard the returned error", "Load: discard the returned error"},
+			wantSkipped: []string{"Load: result used in an expression", "Close: result is not used"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "main.go")
+			if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			diff := &Diff{Changed: []ChangedSignature{
+				{Name: "Load", ParamChanges: errorResult(2), UsedIn: []Location{
+					{File: path, Line: 6, Column: 11, Kind: UsageCall},
+					{File: path, Line: 8, Column: 10, Kind: UsageCall},
+					{File: path, Line: 9, Column: 10, Kind: UsageCall},
+				}},
+				{Name: "Close", ParamChanges: errorResult(1), UsedIn: []Location{
+					{File: path, Line: 7, Column: 6, Kind: UsageCall},
+				}},
+			}}
+
+			a := &Analyzer{opts: Options{Fix: true, FixPolicy: tt.policy}}
+			fixes, err := a.fixSites(diff, emptyAPI())
+			if err != nil {
+				t.Fatalf("fixSites() error = %v", err)
+			}
+			var fixed, skipped []string
+			for _, site := range fixes.Fixed {
+				fixed = append(fixed, site.Symbol+": "+site.Fix)
+			}
+			for _, site := range fixes.Skipped {
+				skipped = append(skipped, site.Symbol+": "+site.Reason)
+			}
+			if !reflect.DeepEqual(fixed, tt.wantFixed) {
+				t.Errorf("Fixed = %q, want %q", fixed, tt.wantFixed)
+			}
+			if !reflect.DeepEqual(skipped, tt.wantSkipped) {
+				t.Errorf("Skipped = %q, want %q", skipped, tt.wantSkipped)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.wantSrc {
+				t.Errorf("rewritten file:\n%s\nwant:\n%s", got, tt.wantSrc)
+			}
+		})
+	}
+}
+
+func TestContextFirst(t *testing.T) {
+	ctx := ParamChange{Kind: ParamAdded, Position: 1, Name: "ctx", NewType: "context.Context"}
+	tests := []struct {
+		name    string
+		changed ChangedSignature
+		want    bool
+	}{
+		{"leading context", ChangedSignature{ParamChanges: []ParamChange{ctx}}, true},
+		{"context and result", ChangedSignature{ParamChanges: []ParamChange{ctx, {Kind: ParamAdded, Result: true, Position: 2, NewType: "error"}}}, false},
+		{"context second", ChangedSignature{ParamChanges: []ParamChange{{Kind: ParamAdded, Position: 2, NewType: "context.Context"}}}, false},
+		{"behavior change", ChangedSignature{ParamChanges: []ParamChange{ctx}, BehaviorChange: BehaviorPanicToError}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contextFirst(tt.changed); got != tt.want {
+				t.Errorf("contextFirst() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
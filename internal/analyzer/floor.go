@@ -0,0 +1,73 @@
+package analyzer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Floor is the outcome of checking the project against a minimum version of
+// a module, such as a security floor
+type Floor struct {
+	Required  string
+	Current   string
+	Satisfied bool
+	Gap       string // how far Current is behind Required, empty when satisfied
+}
+
+// checkFloor compares the version the project requires with a minimum version
+func checkFloor(current, required string) (*Floor, error) {
+	if !semver.IsValid(required) {
+		return nil, fmt.Errorf("invalid minimum version %q (expected a semantic version such as v1.2.3)", required)
+	}
+	floor := &Floor{
+		Required:  required,
+		Current:   current,
+		Satisfied: semver.Compare(current, required) >= 0,
+	}
+	if !floor.Satisfied {
+		floor.Gap = versionGap(current, required)
+	}
+	return floor, nil
+}
+
+// versionGap describes the most significant version component by which
+// current trails required, such as "2 minor version(s) behind"
+func versionGap(current, required string) string {
+	cur, req := versionParts(current), versionParts(required)
+	if cur == nil || req == nil {
+		return "behind"
+	}
+	for i, unit := range []string{"major", "minor", "patch"} {
+		if req[i] != cur[i] {
+			return fmt.Sprintf("%d %s version(s) behind", req[i]-cur[i], unit)
+		}
+	}
+	return "behind by a pre-release"
+}
+
+// versionParts returns the major, minor, and patch numbers of a semantic
+// version, or nil when it has none
+func versionParts(version string) []int {
+	if !semver.IsValid(version) {
+		return nil
+	}
+	core := strings.TrimPrefix(semver.Canonical(version), "v")
+	core, _, _ = strings.Cut(core, "-")
+	core, _, _ = strings.Cut(core, "+")
+
+	var parts []int
+	for _, field := range strings.Split(core, ".") {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return nil
+		}
+		parts = append(parts, n)
+	}
+	if len(parts) != 3 {
+		return nil
+	}
+	return parts
+}
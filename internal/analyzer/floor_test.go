@@ -0,0 +1,33 @@
+package analyzer
+
+import "testing"
+
+func TestCheckFloor(t *testing.T) {
+	tests := []struct {
+		current   string
+		required  string
+		satisfied bool
+		gap       string
+	}{
+		{"v1.5.0", "v1.5.0", true, ""},
+		{"v1.6.2", "v1.5.0", true, ""},
+		{"v1.2.9", "v1.5.0", false, "3 minor version(s) behind"},
+		{"v1.5.0", "v1.5.4", false, "4 patch version(s) behind"},
+		{"v1.9.0", "v2.1.0", false, "1 major version(s) behind"},
+		{"v1.5.0-rc.1", "v1.5.0", false, "behind by a pre-release"},
+		{"v0.0.0-20240101000000-abcdefabcdef", "v0.1.0", false, "1 minor version(s) behind"},
+	}
+	for _, tt := range tests {
+		floor, err := checkFloor(tt.current, tt.required)
+		if err != nil {
+			t.Fatalf("checkFloor(%q, %q) error = %v", tt.current, tt.required, err)
+		}
+		if floor.Satisfied != tt.satisfied || floor.Gap != tt.gap {
+			t.Errorf("checkFloor(%q, %q) = %+v, want satisfied %v gap %q", tt.current, tt.required, floor, tt.satisfied, tt.gap)
+		}
+	}
+
+	if _, err := checkFloor("v1.0.0", "latest"); err == nil {
+		t.Error("expected error for a non-semver minimum")
+	}
+}
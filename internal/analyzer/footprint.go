@@ -0,0 +1,168 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Allow overriding in tests
+var statFile = os.Stat
+
+// measureFootprint compares the download size, package count, and module requirements
+// of the old and new versions of a module, and the modules the upgrade adds to
+// or removes from the project's module graph
+func (a *Analyzer) measureFootprint(module, oldVersion, newVersion string, oldAPI, newAPI *API) (*Footprint, error) {
+	newInfo, newReqs, err := a.downloadWithRequirements(module, newVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	fp := &Footprint{
+		NewSize:     zipSize(newInfo),
+		OldPackages: len(oldAPI.Packages),
+		NewPackages: len(newAPI.Packages),
+	}
+
+	oldReqs := map[string]string{}
+	if oldVersion != "" {
+		oldInfo, reqs, err := a.downloadWithRequirements(module, oldVersion)
+		if err != nil {
+			return nil, err
+		}
+		fp.OldSize = zipSize(oldInfo)
+		oldReqs = reqs
+	}
+
+	// The graph needs the project's go.mod; without it the rest of the
+	// footprint still stands
+	fp.ModulesAdded, fp.ModulesRemoved, err = a.moduleGraphDelta(module, newVersion)
+	if err != nil {
+		a.warn(WarnFootprint, "could not compare the module graph with %s@%s: %v", module, newVersion, err)
+	}
+	fp.SharedBumps = a.sharedBumps(oldReqs, newReqs)
+
+	oldPkgs := make(map[string]bool)
+	for _, pkg := range oldAPI.Packages {
+		oldPkgs[pkg] = true
+	}
+	for _, pkg := range newAPI.Packages {
+		if !oldPkgs[pkg] {
+			fp.PackagesAdded = append(fp.PackagesAdded, pkg)
+		}
+	}
+	sort.Strings(fp.PackagesAdded)
+
+	return fp, nil
+}
+
+// sharedBumps finds the modules the project requires directly that the new
+// version of the dependency requires at a higher version. Minimal version
+// selection builds the project with the higher one, so the upgrade
+// implicitly upgrades them as well.
+func (a *Analyzer) sharedBumps(oldReqs, newReqs map[string]string) []SharedBump {
+	projFile, err := a.projectModFile()
+	if err != nil {
+		return nil
+	}
+	var bumps []SharedBump
+	for _, req := range projFile.Require {
+		version, ok := newReqs[req.Mod.Path]
+		if req.Indirect || !ok || semver.Compare(version, req.Mod.Version) <= 0 {
+			continue
+		}
+		bumps = append(bumps, SharedBump{
+			Path:           req.Mod.Path,
+			ProjectVersion: req.Mod.Version,
+			OldVersion:     oldReqs[req.Mod.Path],
+			NewVersion:     version,
+		})
+	}
+	sort.Slice(bumps, func(i, j int) bool { return bumps[i].Path < bumps[j].Path })
+	return bumps
+}
+
+// moduleGraphDelta lists, as "path version", the modules that upgrading to
+// module@version adds to the project's module graph, transitive ones
+// included, and the modules it drops from it
+func (a *Analyzer) moduleGraphDelta(module, version string) (added, removed []string, err error) {
+	before, err := a.moduleGraph()
+	if err != nil {
+		return nil, nil, err
+	}
+	overlay, err := a.newUpgradeOverlay(module, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer overlay.Close()
+	after, err := a.moduleGraph(overlay.flag())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for path, v := range after {
+		if _, ok := before[path]; !ok {
+			added = append(added, path+" "+v)
+		}
+	}
+	for path, v := range before {
+		if _, ok := after[path]; !ok {
+			removed = append(removed, path+" "+v)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed, nil
+}
+
+// moduleGraph returns the selected version of every module in the project's
+// module graph, by path, leaving out the project itself
+func (a *Analyzer) moduleGraph(flags ...string) (map[string]string, error) {
+	args := append([]string{"list", "-m"}, flags...)
+	args = append(args, "-f", "{{.Path}} {{.Version}}", "all")
+	out, err := runGoCommand(a.projectPath, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the module graph: %w", err)
+	}
+	modules := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		path, version, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if path != "" && version != "" {
+			modules[path] = version
+		}
+	}
+	return modules, nil
+}
+
+// downloadWithRequirements downloads module@version and returns its requirements keyed by path
+func (a *Analyzer) downloadWithRequirements(module, version string) (*moduleDownload, map[string]string, error) {
+	info, err := a.downloadModule(module, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := parseDownloadedModFile(info)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reqs := make(map[string]string)
+	for _, req := range f.Require {
+		reqs[req.Mod.Path] = req.Mod.Version
+	}
+	return info, reqs, nil
+}
+
+// zipSize returns the size of the downloaded module zip, or 0 if unknown
+func zipSize(info *moduleDownload) int64 {
+	if info.Zip == "" {
+		return 0
+	}
+	fi, err := statFile(info.Zip)
+	if err != nil {
+		return 0
+	}
+	return fi.Size()
+}
@@ -0,0 +1,118 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMeasureFootprint(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	infos := map[string]moduleDownload{
+		"v1.0.0": {
+			Path:    "example.com/lib",
+			Version: "v1.0.0",
+			GoMod:   writeFile("v1.mod", "module example.com/lib\n\nrequire (\n\texample.com/keep v1.0.0\n\texample.com/gone v1.0.0\n)\n"),
+			Zip:     writeFile("v1.zip", strings.Repeat("x", 100)),
+		},
+		"v2.0.0": {
+			Path:    "example.com/lib",
+			Version: "v2.0.0",
+			GoMod:   writeFile("v2.mod", "module example.com/lib\n\nrequire (\n\texample.com/keep v1.1.0\n\texample.com/fresh v0.2.0\n)\n"),
+			Zip:     writeFile("v2.zip", strings.Repeat("x", 250)),
+		},
+	}
+	restore := mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+		switch args[0] {
+		case "list":
+			// keep and gone come from lib, deep only through fresh
+			if strings.HasPrefix(args[2], "-modfile=") {
+				return []byte("example.com/app\nexample.com/lib v2.0.0\nexample.com/keep v1.1.0\nexample.com/fresh v0.2.0\nexample.com/deep v0.1.0\n"), nil
+			}
+			return []byte("example.com/app\nexample.com/lib v1.0.0\nexample.com/keep v1.0.0\nexample.com/gone v1.0.0\n"), nil
+		case "get":
+			return nil, nil
+		}
+		version := args[len(args)-1][strings.LastIndex(args[len(args)-1], "@")+1:]
+		return json.Marshal(infos[version])
+	})
+	defer restore()
+
+	oldAPI := &API{Packages: []string{"example.com/lib"}}
+	newAPI := &API{Packages: []string{"example.com/lib", "example.com/lib/extra"}}
+
+	a := &Analyzer{projectPath: dir}
+	fp, err := a.measureFootprint("example.com/lib", "v1.0.0", "v2.0.0", oldAPI, newAPI)
+	if err != nil {
+		t.Fatalf("measureFootprint() error = %v", err)
+	}
+
+	if fp.OldSize != 100 || fp.NewSize != 250 {
+		t.Fatalf("measureFootprint() sizes = %d -> %d, want 100 -> 250", fp.OldSize, fp.NewSize)
+	}
+	if fp.OldPackages != 1 || fp.NewPackages != 2 {
+		t.Fatalf("measureFootprint() packages = %d -> %d, want 1 -> 2", fp.OldPackages, fp.NewPackages)
+	}
+	if !reflect.DeepEqual(fp.PackagesAdded, []string{"example.com/lib/extra"}) {
+		t.Fatalf("measureFootprint() PackagesAdded = %v", fp.PackagesAdded)
+	}
+	if fp.ModulesAdded != nil || fp.ModulesRemoved != nil || len(a.warnings) != 1 || a.warnings[0].Code != WarnFootprint {
+		t.Fatalf("measureFootprint() modules = +%v -%v, warnings %v; want a footprint warning without a project go.mod", fp.ModulesAdded, fp.ModulesRemoved, a.warnings)
+	}
+	if fp.SharedBumps != nil {
+		t.Fatalf("measureFootprint() SharedBumps = %v without a project go.mod", fp.SharedBumps)
+	}
+
+	// The project requires keep and fresh directly, below what v2 requires
+	writeFile("go.mod", "module example.com/app\n\nrequire (\n\texample.com/keep v1.0.0\n\texample.com/fresh v0.1.0\n\texample.com/gone v1.0.0\n\texample.com/lib v1.0.0\n)\n")
+	fp, err = a.measureFootprint("example.com/lib", "v1.0.0", "v2.0.0", oldAPI, newAPI)
+	if err != nil {
+		t.Fatalf("measureFootprint() error = %v", err)
+	}
+	want := []SharedBump{
+		{Path: "example.com/fresh", ProjectVersion: "v0.1.0", NewVersion: "v0.2.0"},
+		{Path: "example.com/keep", ProjectVersion: "v1.0.0", OldVersion: "v1.0.0", NewVersion: "v1.1.0"},
+	}
+	if !reflect.DeepEqual(fp.SharedBumps, want) {
+		t.Fatalf("measureFootprint() SharedBumps = %+v, want %+v", fp.SharedBumps, want)
+	}
+	if !reflect.DeepEqual(fp.ModulesAdded, []string{"example.com/deep v0.1.0", "example.com/fresh v0.2.0"}) {
+		t.Fatalf("measureFootprint() ModulesAdded = %v, want the transitive deep too", fp.ModulesAdded)
+	}
+	if !reflect.DeepEqual(fp.ModulesRemoved, []string{"example.com/gone v1.0.0"}) {
+		t.Fatalf("measureFootprint() ModulesRemoved = %v", fp.ModulesRemoved)
+	}
+}
+
+func TestSharedBumps_SkipsIndirectAndHigherRequirements(t *testing.T) {
+	dir := t.TempDir()
+	mod := "module example.com/app\n\nrequire (\n\tgoogle.golang.org/grpc v1.62.0\n\texample.com/indirect v1.0.0 // indirect\n)\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(mod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	a := &Analyzer{projectPath: dir}
+	newReqs := map[string]string{"google.golang.org/grpc": "v1.60.0", "example.com/indirect": "v1.2.0"}
+	if got := a.sharedBumps(nil, newReqs); got != nil {
+		t.Errorf("sharedBumps() = %+v, want none", got)
+	}
+}
+
+func TestZipSizeUnknown(t *testing.T) {
+	if got := zipSize(&moduleDownload{}); got != 0 {
+		t.Fatalf("zipSize() = %d, want 0", got)
+	}
+	if got := zipSize(&moduleDownload{Zip: filepath.Join(t.TempDir(), "missing.zip")}); got != 0 {
+		t.Fatalf("zipSize() = %d, want 0 for missing file", got)
+	}
+}
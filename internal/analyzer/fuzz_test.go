@@ -0,0 +1,205 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func FuzzParseUpgrade(f *testing.F) {
+	for _, seed := range []string{
+		"github.com/pkg/errors@v0.9.1",
+		"golang.org/x/tools@v0.16.0",
+		"go@1.22",
+		"example.com/mod@latest",
+		"@v1.0.0",
+		"example.com/mod@",
+		"a@b@c",
+		"  example.com/mod  @  v1.0.0  ",
+		"example.com/été@v1.0.0",
+		"\xff\xfe@\x00",
+		"",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, spec string) {
+		u, err := ParseUpgrade(spec)
+		if err != nil {
+			if u != nil {
+				t.Errorf("ParseUpgrade(%q) = %+v with error %v", spec, u, err)
+			}
+			if !strings.Contains(err.Error(), spec) {
+				t.Errorf("ParseUpgrade(%q) error %q does not quote the spec", spec, err)
+			}
+			return
+		}
+		if u.Module == "" || u.NewVersion == "" || strings.Contains(u.Module, "@") || strings.Contains(u.NewVersion, "@") {
+			t.Fatalf("ParseUpgrade(%q) = %+v", spec, u)
+		}
+		again, err := ParseUpgrade(u.Module + "@" + u.NewVersion)
+		if err != nil || *again != *u {
+			t.Errorf("ParseUpgrade(%q) = %+v, but reparsing gives %+v, %v", spec, u, again, err)
+		}
+	})
+}
+
+// fuzzAPIs builds the APIs and usage of a diff from fuzz data, one byte per
+// choice. Names come from a small pool so that the versions share symbols,
+// and include duplicates, odd unicode, and invalid UTF-8; maps and entries
+// are sometimes nil, as in snapshots edited by hand.
+type fuzzAPIs struct {
+	data []byte
+}
+
+var fuzzNames = []string{
+	"Do", "Do", "Client", "Client.Do", "Client.Close", "Reader", "Reader.Read",
+	"Option", "Config.Value", "", ".", "日本", "Ünïcode.Méthod", "a​b", "\xff", "T.",
+}
+
+var fuzzTypes = []string{
+	"", "int", "string", "error", "func()", "[]byte", "...string", "context.Context",
+	"map[string]any", "*Client", "T", "chan<- int", "\xff", "日本",
+}
+
+func (g *fuzzAPIs) next() int {
+	if len(g.data) == 0 {
+		return 0
+	}
+	b := g.data[0]
+	g.data = g.data[1:]
+	return int(b)
+}
+
+func (g *fuzzAPIs) pick(pool []string) string { return pool[g.next()%len(pool)] }
+
+func (g *fuzzAPIs) params() []Param {
+	var params []Param
+	for n := g.next() % 4; n > 0; n-- {
+		params = append(params, Param{Name: g.pick(fuzzNames), Type: g.pick(fuzzTypes)})
+	}
+	return params
+}
+
+func (g *fuzzAPIs) signature() Signature {
+	sig := Signature{Text: "func(" + g.pick(fuzzTypes) + ") " + g.pick(fuzzTypes)}
+	if g.next()%2 == 0 {
+		sig.Params = g.params()
+		sig.Results = g.params()
+		sig.Variadic = g.next()%4 == 0
+	}
+	if g.next()%4 == 0 {
+		sig.TypeParams = []TypeParam{{Name: "T", Constraint: g.pick(fuzzTypes)}}
+	}
+	return sig
+}
+
+func (g *fuzzAPIs) api() *API {
+	if g.next()%32 == 31 {
+		return nil
+	}
+	api := &API{}
+	if g.next()%8 != 0 {
+		api.Funcs = make(map[string]*Function)
+		for n := g.next() % 8; n > 0; n-- {
+			name := g.pick(fuzzNames)
+			if g.next()%16 == 0 {
+				api.Funcs[name] = nil
+				continue
+			}
+			api.Funcs[name] = &Function{
+				Name:         name,
+				Signature:    g.signature(),
+				PkgPath:      g.pick(fuzzNames),
+				IsMethod:     strings.Contains(name, "."),
+				Unstable:     g.next()%4 == 0,
+				Doc:          g.pick(fuzzNames),
+				PromotedFrom: g.pick([]string{"", "", "Embedded", "\xff"}),
+			}
+		}
+	}
+	if g.next()%8 != 0 {
+		api.Types = make(map[string]*Type)
+		for n := g.next() % 6; n > 0; n-- {
+			name := g.pick(fuzzNames)
+			if g.next()%16 == 0 {
+				api.Types[name] = nil
+				continue
+			}
+			typ := &Type{Name: name, Kind: g.pick([]string{"struct", "interface", "basic", ""}), PkgPath: g.pick(fuzzNames)}
+			if g.next()%2 == 0 {
+				typ.TypeParams = "[T " + g.pick(fuzzTypes) + "]"
+			}
+			for f := g.next() % 4; f > 0; f-- {
+				typ.Fields = append(typ.Fields, g.pick(fuzzNames))
+			}
+			api.Types[name] = typ
+		}
+	}
+	if g.next()%8 != 0 {
+		api.Interfaces = make(map[string]*Interface)
+		for n := g.next() % 4; n > 0; n-- {
+			name := g.pick(fuzzNames)
+			if g.next()%16 == 0 {
+				api.Interfaces[name] = nil
+				continue
+			}
+			iface := &Interface{Name: name, PkgPath: g.pick(fuzzNames)}
+			for m := g.next() % 4; m > 0; m-- {
+				iface.Methods = append(iface.Methods, g.pick(fuzzNames))
+			}
+			if g.next()%2 == 0 {
+				iface.Embedded = map[string]string{g.pick(fuzzNames): g.pick(fuzzNames)}
+			}
+			api.Interfaces[name] = iface
+		}
+	}
+	for n := g.next() % 3; n > 0; n-- {
+		api.Packages = append(api.Packages, g.pick(fuzzNames))
+	}
+	return api
+}
+
+func (g *fuzzAPIs) usage() *Usage {
+	usage := &Usage{All: g.next()%4 == 0}
+	if g.next()%8 != 0 {
+		usage.Symbols = make(map[string][]Location)
+		for n := g.next() % 8; n > 0; n-- {
+			name := g.pick(fuzzNames)
+			usage.Symbols[name] = append(usage.Symbols[name], Location{
+				File: g.pick(fuzzNames) + ".go", Line: g.next(), Column: g.next() % 3,
+				Kind: g.pick([]string{"", UsageKeyedField, "\xff"}),
+			})
+		}
+	}
+	if g.next()%8 != 0 {
+		usage.Imports = map[string]bool{g.pick(fuzzNames): true}
+	}
+	return usage
+}
+
+func FuzzDiffAPIs(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte("\x01\x03\x00\x01\x02\x03\x04\x05\x06\x07\x08\x09"))
+	f.Add([]byte(strings.Repeat("\x07\x01\x02", 40)))
+	f.Add([]byte(strings.Repeat("\x10\x00\xff\x0f", 64)))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		g := &fuzzAPIs{data: data}
+		oldAPI, newAPI, usage := g.api(), g.api(), g.usage()
+
+		diff := diffAPIs(oldAPI, newAPI, usage)
+
+		for _, r := range diff.Removed {
+			if r.Type == "function" {
+				if oldAPI == nil || oldAPI.Funcs[r.Name] == nil {
+					t.Errorf("removed %q was not in the old API", r.Name)
+				}
+			}
+		}
+		for _, c := range diff.Changed {
+			if c.OldSignature == c.NewSignature {
+				t.Errorf("changed %q has the same signature %q", c.Name, c.OldSignature)
+			}
+		}
+		diff.BreakingCount()
+		diff.WarningCount()
+	})
+}
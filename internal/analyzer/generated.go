@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"go/ast"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// generatedFileSuffixes are file name patterns used by common code generators
+// (protoc, gRPC, k8s deepcopy, go generate conventions)
+var generatedFileSuffixes = []string{".pb.go", ".pb.gw.go", "_gen.go", "_generated.go", "zz_generated.deepcopy.go"}
+
+// isGeneratedPackage reports whether every file of a package is generated code,
+// either by the standard "Code generated ... DO NOT EDIT." header or by file name
+func isGeneratedPackage(pkg *packages.Package) bool {
+	if len(pkg.Syntax) == 0 {
+		return false
+	}
+	for _, file := range pkg.Syntax {
+		if !isGeneratedFile(pkg, file) {
+			return false
+		}
+	}
+	return true
+}
+
+// isGeneratedFile reports whether a single file is generated code
+func isGeneratedFile(pkg *packages.Package, file *ast.File) bool {
+	if ast.IsGenerated(file) {
+		return true
+	}
+	if pkg.Fset == nil {
+		return false
+	}
+	name := filepath.Base(pkg.Fset.Position(file.Pos()).Filename)
+	for _, suffix := range generatedFileSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupGeneratedChurn collapses changes in generated packages into one summary
+// finding per package. Changes the project uses stay listed individually, since
+// those still need fixing; everything else is only counted.
+func groupGeneratedChurn(oldAPI, newAPI *API, usage *Usage, diff *Diff) {
+	generated := make(map[string]bool)
+	for pkg := range oldAPI.Generated {
+		generated[pkg] = true
+	}
+	for pkg := range newAPI.Generated {
+		generated[pkg] = true
+	}
+	if len(generated) == 0 {
+		return
+	}
+
+	groups := make(map[string]*GeneratedGroup)
+	count := func(pkgPath, name string) {
+		if !generated[pkgPath] {
+			return
+		}
+		group, ok := groups[pkgPath]
+		if !ok {
+			group = &GeneratedGroup{Package: pkgPath}
+			groups[pkgPath] = group
+		}
+		group.Changes++
+		if _, used := usage.uses(name); used {
+			group.Used++
+		}
+	}
+
+	for name, oldFunc := range oldAPI.Funcs {
+		if newFunc, ok := newAPI.Funcs[name]; !ok || newFunc.Signature.Text != oldFunc.Signature.Text {
+			count(oldFunc.PkgPath, name)
+		}
+	}
+	for name, oldType := range oldAPI.Types {
+		if newType, ok := newAPI.Types[name]; !ok || newType.Kind != oldType.Kind {
+			count(oldType.PkgPath, name)
+		}
+	}
+	for name, oldIface := range oldAPI.Interfaces {
+		if newIface, ok := newAPI.Interfaces[name]; !ok || strings.Join(newIface.Methods, ";") != strings.Join(oldIface.Methods, ";") {
+			count(oldIface.PkgPath, name)
+		}
+	}
+
+	// Additions are never used yet, so in generated packages they are only counted
+	added := diff.Added[:0]
+	for _, a := range diff.Added {
+		if pkgPath := addedPkgPath(newAPI, a); generated[pkgPath] {
+			count(pkgPath, a.Name)
+			continue
+		}
+		added = append(added, a)
+	}
+	diff.Added = added
+
+	for _, group := range groups {
+		diff.Generated = append(diff.Generated, *group)
+	}
+	sort.Slice(diff.Generated, func(i, j int) bool { return diff.Generated[i].Package < diff.Generated[j].Package })
+}
+
+// addedPkgPath returns the package an added symbol belongs to
+func addedPkgPath(api *API, added AddedSymbol) string {
+	switch added.Type {
+	case "function":
+		if fn, ok := api.Funcs[added.Name]; ok {
+			return fn.PkgPath
+		}
+	case "type":
+		if t, ok := api.Types[added.Name]; ok {
+			return t.PkgPath
+		}
+	case "interface":
+		if iface, ok := api.Interfaces[added.Name]; ok {
+			return iface.PkgPath
+		}
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestIsGeneratedPackage(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  bool
+	}{
+		{
+			name:  "generated header",
+			files: map[string]string{"api.go": "// Code generated by smithy-go. DO NOT EDIT.\n\npackage api\n"},
+			want:  true,
+		},
+		{
+			name:  "protobuf file name",
+			files: map[string]string{"service.pb.go": "package service\n"},
+			want:  true,
+		},
+		{
+			name: "mixed",
+			files: map[string]string{
+				"service.pb.go": "package service\n",
+				"helpers.go":    "package service\n",
+			},
+			want: false,
+		},
+		{
+			name:  "hand written",
+			files: map[string]string{"lib.go": "// Package lib does things.\npackage lib\n"},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := &packages.Package{Fset: token.NewFileSet()}
+			for name, src := range tt.files {
+				file, err := parser.ParseFile(pkg.Fset, name, src, parser.ParseComments)
+				if err != nil {
+					t.Fatal(err)
+				}
+				pkg.Syntax = append(pkg.Syntax, file)
+			}
+			if got := isGeneratedPackage(pkg); got != tt.want {
+				t.Fatalf("isGeneratedPackage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsGeneratedPackageWithoutSyntax(t *testing.T) {
+	if isGeneratedPackage(&packages.Package{Syntax: []*ast.File{}}) {
+		t.Fatal("isGeneratedPackage() = true for a package without files")
+	}
+}
+
+func TestDiffAPIsGroupsGeneratedChurn(t *testing.T) {
+	const genPkg = "example.com/sdk/service/s3"
+
+	oldAPI := emptyAPI()
+	oldAPI.Generated[genPkg] = true
+	oldAPI.Funcs["GetObject"] = &Function{Name: "GetObject", Signature: Signature{Text: "func()"}, PkgPath: genPkg}
+	oldAPI.Funcs["ListBuckets"] = &Function{Name: "ListBuckets", Signature: Signature{Text: "func()"}, PkgPath: genPkg}
+	oldAPI.Types["Bucket"] = &Type{Name: "Bucket", Kind: "struct{}", PkgPath: genPkg}
+	oldAPI.Funcs["Config"] = &Function{Name: "Config", Signature: Signature{Text: "func()"}, PkgPath: "example.com/sdk/aws"}
+
+	newAPI := emptyAPI()
+	newAPI.Generated[genPkg] = true
+	newAPI.Funcs["GetObject"] = &Function{Name: "GetObject", Signature: Signature{Text: "func(ctx)"}, PkgPath: genPkg}
+	newAPI.Types["Bucket"] = &Type{Name: "Bucket", Kind: "struct{}", PkgPath: genPkg}
+	newAPI.Funcs["PutObject"] = &Function{Name: "PutObject", Signature: Signature{Text: "func()"}, PkgPath: genPkg}
+	newAPI.Types["Waiter"] = &Type{Name: "Waiter", Kind: "struct{}", PkgPath: genPkg}
+	newAPI.Funcs["Config"] = &Function{Name: "Config", Signature: Signature{Text: "func()"}, PkgPath: "example.com/sdk/aws"}
+	newAPI.Funcs["Retry"] = &Function{Name: "Retry", Signature: Signature{Text: "func()"}, PkgPath: "example.com/sdk/aws"}
+
+	usage := &Usage{Symbols: map[string][]Location{
+		"GetObject": {{File: "main.go", Line: 10}},
+	}}
+
+	diff := diffAPIs(oldAPI, newAPI, usage)
+
+	want := []GeneratedGroup{{Package: genPkg, Changes: 4, Used: 1}}
+	if !reflect.DeepEqual(diff.Generated, want) {
+		t.Fatalf("diffAPIs() Generated = %+v, want %+v", diff.Generated, want)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].Name != "GetObject" {
+		t.Fatalf("diffAPIs() Changed = %+v, want used GetObject kept", diff.Changed)
+	}
+	if len(diff.Added) != 1 || diff.Added[0].Name != "Retry" {
+		t.Fatalf("diffAPIs() Added = %+v, want only hand-written Retry", diff.Added)
+	}
+}
@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Instantiation is one set of type arguments the project instantiates a
+// generic function or type with, checked against the new version's constraints
+type Instantiation struct {
+	TypeArgs []string
+	UsedIn   []Location
+
+	// Error explains why the type arguments no longer satisfy the new type
+	// parameters, and is empty when the instantiation still compiles
+	Error string
+}
+
+// Compatible reports whether the instantiation still satisfies the new constraints
+func (i Instantiation) Compatible() bool { return i.Error == "" }
+
+// instance is a use of a generic symbol with the project's type arguments
+type instance struct {
+	args     []types.Type
+	location Location
+}
+
+// recordInstances collects the instantiations of generic symbols from the
+// target packages, including those with inferred type arguments
+func recordInstances(usage *Usage, pkg *packages.Package, approximate bool) {
+	for ident, inst := range pkg.TypesInfo.Instances {
+		obj := pkg.TypesInfo.Uses[ident]
+		if obj == nil || !obj.Exported() || obj.Pkg() == nil || !usage.Imports[obj.Pkg().Path()] || inst.TypeArgs == nil {
+			continue
+		}
+		args := make([]types.Type, inst.TypeArgs.Len())
+		for i := range args {
+			args[i] = inst.TypeArgs.At(i)
+		}
+		if usage.instances == nil {
+			usage.instances = make(map[string][]instance)
+		}
+		pos := pkg.Fset.Position(ident.Pos())
+		usage.instances[obj.Name()] = append(usage.instances[obj.Name()], instance{
+			args: args,
+			location: Location{
+				File:        pos.Filename,
+				Line:        pos.Line,
+				Column:      pos.Column,
+				Approximate: approximate,
+			},
+		})
+	}
+}
+
+// instantiations groups the project's instantiations of a generic symbol by
+// type arguments and checks each against the new declaration. Type arguments
+// come from the project's load, so named types are matched by their method
+// sets and underlying types rather than by identity.
+func (u *Usage) instantiations(name string, generic types.Type) []Instantiation {
+	if len(u.instances[name]) == 0 || generic == nil {
+		return nil
+	}
+
+	var result []Instantiation
+	index := make(map[string]int)
+	for _, inst := range u.instances[name] {
+		args := make([]string, len(inst.args))
+		for i, arg := range inst.args {
+			args[i] = types.TypeString(arg, packageName)
+		}
+		key := strings.Join(args, ", ")
+		i, ok := index[key]
+		if !ok {
+			i = len(result)
+			index[key] = i
+			checked := Instantiation{TypeArgs: args}
+			if _, err := types.Instantiate(nil, generic, inst.args, true); err != nil {
+				checked.Error = err.Error()
+			}
+			result = append(result, checked)
+		}
+		result[i].UsedIn = append(result[i].UsedIn, inst.location)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return strings.Join(result[i].TypeArgs, ", ") < strings.Join(result[j].TypeArgs, ", ")
+	})
+	return result
+}
+
+// declType returns the type-checked type of a function, or nil for APIs built by hand
+func (f *Function) declType() types.Type {
+	if f.obj == nil {
+		return nil
+	}
+	return f.obj.Type()
+}
+
+// declType returns the type-checked type of a type, or nil for APIs built by hand
+func (t *Type) declType() types.Type {
+	if t.obj == nil {
+		return nil
+	}
+	return t.obj.Type()
+}
+
+// packageName qualifies type arguments by package name, as the project writes them
+func packageName(pkg *types.Package) string {
+	return pkg.Name()
+}
+
+// typeParamsString prints a type parameter list such as [K comparable, V any],
+// or "" for types that are not generic
+func typeParamsString(tparams *types.TypeParamList) string {
+	if tparams.Len() == 0 {
+		return ""
+	}
+	params := make([]string, tparams.Len())
+	for i := range params {
+		tparam := tparams.At(i)
+		params[i] = tparam.Obj().Name() + " " + tparam.Constraint().String()
+	}
+	return "[" + strings.Join(params, ", ") + "]"
+}
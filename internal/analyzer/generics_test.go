@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"go/types"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestDiffAPIs_GenericInstantiations(t *testing.T) {
+	oldLib := checkSource(t, "example.com/lib", `package lib
+
+type Number interface{ ~int | ~float64 }
+
+func Max[T Number](a, b T) T { return a }
+
+type Set[T any] struct{ items []T }
+`, nil)
+	newLib := checkSource(t, "example.com/lib", `package lib
+
+type Number interface{ ~int | ~float64 }
+
+func Max[T ~int](a, b T) T { return a }
+
+type Set[T comparable] struct{ items []T }
+`, nil)
+	app := checkSource(t, "example.com/app", `package app
+
+import "example.com/lib"
+
+type celsius float64
+
+func run() {
+	_ = lib.Max(1, 2)
+	_ = lib.Max(celsius(1), 2)
+	_ = lib.Max[int](3, 4)
+	var _ lib.Set[string]
+	var _ lib.Set[[]byte]
+}
+`, map[string]*types.Package{"example.com/lib": oldLib.Types})
+	app.Imports = map[string]*packages.Package{
+		"example.com/lib": {PkgPath: "example.com/lib", Module: &packages.Module{Path: "example.com/lib"}},
+	}
+
+	a := &Analyzer{pkgs: []*packages.Package{app}}
+	usage := a.findUsage("example.com/lib")
+	oldAPI := extractAPI([]*packages.Package{oldLib})
+	newAPI := extractAPI([]*packages.Package{newLib})
+	if got, want := oldAPI.Types["Set"].TypeParams, "[T any]"; got != want {
+		t.Errorf("Set type params = %q, want %q", got, want)
+	}
+
+	diff := diffAPIs(oldAPI, newAPI, usage)
+	changed := make(map[string]ChangedSignature)
+	for _, c := range diff.Changed {
+		changed[c.Name] = c
+	}
+
+	type check struct {
+		args       string
+		uses       int
+		compatible bool
+	}
+	summarize := func(insts []Instantiation) []check {
+		var got []check
+		for _, inst := range insts {
+			got = append(got, check{strings.Join(inst.TypeArgs, ", "), len(inst.UsedIn), inst.Compatible()})
+		}
+		return got
+	}
+
+	if got, want := summarize(changed["Max"].Instantiations), []check{
+		{"app.celsius", 1, false},
+		{"int", 2, true},
+	}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Max instantiations = %+v, want %+v", got, want)
+	}
+
+	set, ok := changed["Set"]
+	if !ok {
+		t.Fatalf("Set type parameter change not reported, got %+v", diff.Changed)
+	}
+	if set.OldSignature != "type Set[T any]" || set.NewSignature != "type Set[T comparable]" {
+		t.Errorf("Set signatures = %q -> %q", set.OldSignature, set.NewSignature)
+	}
+	if got, want := summarize(set.Instantiations), []check{
+		{"[]byte", 1, false},
+		{"string", 1, true},
+	}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Set instantiations = %+v, want %+v", got, want)
+	}
+}
+
+func TestDiffAPIs_GenericWithoutTypeInfo(t *testing.T) {
+	oldAPI := emptyAPI()
+	oldAPI.Types["Set"] = &Type{Name: "Set", TypeParams: "[T any]"}
+	newAPI := emptyAPI()
+	newAPI.Types["Set"] = &Type{Name: "Set", TypeParams: "[T comparable]"}
+
+	diff := diffAPIs(oldAPI, newAPI, &Usage{Symbols: map[string][]Location{"Set": {{File: "a.go", Line: 1}}}})
+	if len(diff.Changed) != 1 || diff.Changed[0].Instantiations != nil {
+		t.Errorf("diffAPIs() = %+v, want one change without instantiations", diff.Changed)
+	}
+}
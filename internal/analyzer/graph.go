@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ImportGraph shows where the upgraded module enters the project: every
+// project package importing one of its packages, directly or through other
+// project packages
+type ImportGraph struct {
+	Nodes          []GraphNode // project packages, sorted by import path
+	ModulePackages []string    // packages of the upgraded module the project imports
+}
+
+// GraphNode is a project package in the import graph
+type GraphNode struct {
+	Package string
+	Dir     string   // directory of the package's files, to attribute findings to it
+	Imports []string // project and module packages in the graph it imports
+	Direct  bool     // imports the upgraded module itself
+}
+
+// buildImportGraph collects the project packages through which the upgrade
+// of module ripples
+func (a *Analyzer) buildImportGraph(module string) *ImportGraph {
+	inModule := func(imp *packages.Package) bool {
+		return imp.Module != nil && imp.Module.Path == module
+	}
+
+	project := make(map[string]bool, len(a.pkgs))
+	for _, pkg := range a.pkgs {
+		project[pkg.PkgPath] = true
+	}
+
+	// Packages importing the module directly, then their project importers
+	reaches := make(map[string]bool)
+	modulePkgs := make(map[string]bool)
+	for _, pkg := range a.pkgs {
+		for _, imp := range pkg.Imports {
+			if inModule(imp) {
+				reaches[pkg.PkgPath] = true
+				modulePkgs[imp.PkgPath] = true
+			}
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, pkg := range a.pkgs {
+			if reaches[pkg.PkgPath] {
+				continue
+			}
+			for _, imp := range pkg.Imports {
+				if project[imp.PkgPath] && reaches[imp.PkgPath] {
+					reaches[pkg.PkgPath] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	graph := &ImportGraph{}
+	for _, pkg := range a.pkgs {
+		if !reaches[pkg.PkgPath] {
+			continue
+		}
+		node := GraphNode{Package: pkg.PkgPath}
+		if len(pkg.GoFiles) > 0 {
+			node.Dir = filepath.Dir(cleanPath(pkg.GoFiles[0]))
+		}
+		for _, imp := range pkg.Imports {
+			switch {
+			case inModule(imp):
+				node.Direct = true
+				node.Imports = append(node.Imports, imp.PkgPath)
+			case project[imp.PkgPath] && reaches[imp.PkgPath]:
+				node.Imports = append(node.Imports, imp.PkgPath)
+			}
+		}
+		sort.Strings(node.Imports)
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool { return graph.Nodes[i].Package < graph.Nodes[j].Package })
+
+	for path := range modulePkgs {
+		graph.ModulePackages = append(graph.ModulePackages, path)
+	}
+	sort.Strings(graph.ModulePackages)
+	return graph
+}
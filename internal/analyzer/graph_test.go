@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestBuildImportGraph(t *testing.T) {
+	mod := &packages.Module{Path: "example.com/lib"}
+	lib := &packages.Package{PkgPath: "example.com/lib", Module: mod}
+	libSub := &packages.Package{PkgPath: "example.com/lib/sub", Module: mod}
+	other := &packages.Package{PkgPath: "example.com/other", Module: &packages.Module{Path: "example.com/other"}}
+
+	store := &packages.Package{
+		PkgPath: "example.com/app/store",
+		GoFiles: []string{"/src/app/store/store.go"},
+		Imports: map[string]*packages.Package{"example.com/lib": lib, "example.com/lib/sub": libSub},
+	}
+	api := &packages.Package{
+		PkgPath: "example.com/app/api",
+		GoFiles: []string{"/src/app/api/api.go"},
+		Imports: map[string]*packages.Package{"example.com/app/store": store, "example.com/other": other},
+	}
+	cmd := &packages.Package{
+		PkgPath: "example.com/app/cmd",
+		GoFiles: []string{"/src/app/cmd/main.go"},
+		Imports: map[string]*packages.Package{"example.com/app/api": api},
+	}
+	unrelated := &packages.Package{
+		PkgPath: "example.com/app/util",
+		GoFiles: []string{"/src/app/util/util.go"},
+		Imports: map[string]*packages.Package{"example.com/other": other},
+	}
+
+	a := &Analyzer{pkgs: []*packages.Package{cmd, unrelated, api, store}}
+	graph := a.buildImportGraph("example.com/lib")
+
+	want := &ImportGraph{
+		Nodes: []GraphNode{
+			{Package: "example.com/app/api", Dir: "/src/app/api", Imports: []string{"example.com/app/store"}},
+			{Package: "example.com/app/cmd", Dir: "/src/app/cmd", Imports: []string{"example.com/app/api"}},
+			{Package: "example.com/app/store", Dir: "/src/app/store", Imports: []string{"example.com/lib", "example.com/lib/sub"}, Direct: true},
+		},
+		ModulePackages: []string{"example.com/lib", "example.com/lib/sub"},
+	}
+	if !reflect.DeepEqual(graph, want) {
+		t.Errorf("buildImportGraph() = %+v, want %+v", graph, want)
+	}
+}
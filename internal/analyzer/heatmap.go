@@ -0,0 +1,37 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// DirectoryStats counts the Go files of a project directory, so reports can
+// weigh findings against the size of the code they land in
+type DirectoryStats struct {
+	Dir   string
+	Files int
+}
+
+// directoryStats counts the Go files of every loaded project package by directory
+func (a *Analyzer) directoryStats() []DirectoryStats {
+	files := make(map[string]map[string]bool) // by pathKey of the directory
+	dirs := make(map[string]string)           // pathKey -> spelling of the directory
+	for _, pkg := range a.pkgs {
+		for _, file := range pkg.GoFiles {
+			dir := filepath.Dir(cleanPath(file))
+			key := pathKey(dir)
+			if files[key] == nil {
+				files[key] = make(map[string]bool)
+				dirs[key] = dir
+			}
+			files[key][pathKey(file)] = true
+		}
+	}
+
+	stats := make([]DirectoryStats, 0, len(files))
+	for key, set := range files {
+		stats = append(stats, DirectoryStats{Dir: dirs[key], Files: len(set)})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Dir < stats[j].Dir })
+	return stats
+}
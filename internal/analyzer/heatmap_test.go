@@ -0,0 +1,25 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestDirectoryStats(t *testing.T) {
+	a := &Analyzer{pkgs: []*packages.Package{
+		{PkgPath: "example.com/app/api", GoFiles: []string{"/src/app/api/api.go", "/src/app/api/routes.go"}},
+		{PkgPath: "example.com/app", GoFiles: []string{"/src/app/main.go"}},
+		// An external test package shares its directory, and may repeat files
+		{PkgPath: "example.com/app/api_test", GoFiles: []string{"/src/app/api/api.go", "/src/app/api/api_test.go"}},
+	}}
+
+	want := []DirectoryStats{
+		{Dir: "/src/app", Files: 1},
+		{Dir: "/src/app/api", Files: 3},
+	}
+	if got := a.directoryStats(); !reflect.DeepEqual(got, want) {
+		t.Errorf("directoryStats() = %+v, want %+v", got, want)
+	}
+}
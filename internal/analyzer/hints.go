@@ -0,0 +1,117 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// Reasons an added symbol is suggested as a replacement for project code
+const (
+	HintSimilarName       = "similar name"
+	HintMatchingSignature = "similar name and identical signature"
+)
+
+// minHintNameLength keeps short, generic names such as New or Do from
+// matching half the project
+const minHintNameLength = 4
+
+// Hint suggests a symbol added in the new version that may replace a
+// function the project wrote itself, such as a hand-rolled retry helper
+type Hint struct {
+	Symbol   string   // added symbol that could be adopted
+	Package  string   // package of the added symbol
+	Replaces string   // project function it may replace
+	Location Location // declaration of the project function
+	Reason   string
+}
+
+// adoptionHints matches the functions added in newAPI against the functions
+// declared in the project by name and, where type information allows, by
+// signature
+func (a *Analyzer) adoptionHints(newAPI *API, diff *Diff) []Hint {
+	var added []*Function
+	for _, sym := range diff.Added {
+		fn, ok := newAPI.Funcs[sym.Name]
+		if sym.Type != "function" || !ok || fn.IsMethod || fn.Unstable || len(fn.Name) < minHintNameLength {
+			continue
+		}
+		added = append(added, fn)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	var hints []Hint
+	for _, pkg := range a.pkgs {
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				fd, ok := decl.(*ast.FuncDecl)
+				if !ok || len(fd.Name.Name) < minHintNameLength {
+					continue
+				}
+				for _, fn := range added {
+					if !similarNames(fd.Name.Name, fn.Name) {
+						continue
+					}
+					pos := pkg.Fset.Position(fd.Name.Pos())
+					hint := Hint{
+						Symbol:   fn.Name,
+						Package:  fn.PkgPath,
+						Replaces: fd.Name.Name,
+						Location: Location{File: pos.Filename, Line: pos.Line, Column: pos.Column},
+						Reason:   HintSimilarName,
+					}
+					if pkg.TypesInfo != nil && sameSignature(pkg.TypesInfo.Defs[fd.Name], fn.obj) {
+						hint.Reason = HintMatchingSignature
+					}
+					hints = append(hints, hint)
+				}
+			}
+		}
+	}
+
+	sort.Slice(hints, func(i, j int) bool {
+		if hints[i].Symbol != hints[j].Symbol {
+			return hints[i].Symbol < hints[j].Symbol
+		}
+		if hints[i].Location.File != hints[j].Location.File {
+			return hints[i].Location.File < hints[j].Location.File
+		}
+		return hints[i].Location.Line < hints[j].Location.Line
+	})
+	return hints
+}
+
+// sameSignature compares the parameter and result types of two functions,
+// leaving out parameter and package names since the project and the
+// dependency name them differently
+func sameSignature(local types.Object, dep *types.Func) bool {
+	if local == nil || dep == nil {
+		return false
+	}
+	localSig, ok := local.Type().(*types.Signature)
+	if !ok {
+		return false
+	}
+	unqualified := func(*types.Package) string { return "" }
+	return signatureShape(localSig, unqualified) == signatureShape(dep.Type().(*types.Signature), unqualified)
+}
+
+// signatureShape lists the parameter and result types of a signature without
+// their names, qualifying named types with qualifier
+func signatureShape(sig *types.Signature, qualifier types.Qualifier) string {
+	tuple := func(t *types.Tuple) string {
+		parts := make([]string, t.Len())
+		for i := range parts {
+			parts[i] = types.TypeString(t.At(i).Type(), qualifier)
+		}
+		return strings.Join(parts, ", ")
+	}
+	shape := "(" + tuple(sig.Params()) + ") (" + tuple(sig.Results()) + ")"
+	if sig.Variadic() {
+		shape += " variadic"
+	}
+	return shape
+}
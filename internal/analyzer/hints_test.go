@@ -0,0 +1,43 @@
+package analyzer
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestAdoptionHints(t *testing.T) {
+	lib := checkSource(t, "example.com/lib", `package lib
+
+func Retry(attempts int, fn func() error) error { return nil }
+
+func WithBackoff(seconds int) {}
+
+func New() {}
+`, nil)
+	app := checkSource(t, "example.com/app", `package app
+
+func retryCall(n int, call func() error) error { return nil }
+
+func backoff() {}
+
+func newClient() {}
+
+func parse() {}
+`, nil)
+
+	newAPI := extractAPI([]*packages.Package{lib})
+	diff := diffAPIs(emptyAPI(), newAPI, &Usage{Symbols: map[string][]Location{}})
+	a := &Analyzer{pkgs: []*packages.Package{app}}
+
+	hints := a.adoptionHints(newAPI, diff)
+	if len(hints) != 2 {
+		t.Fatalf("adoptionHints() = %+v, want Retry and WithBackoff", hints)
+	}
+	if h := hints[0]; h.Symbol != "Retry" || h.Replaces != "retryCall" || h.Reason != HintMatchingSignature || h.Location.Line != 3 {
+		t.Errorf("Retry hint = %+v", h)
+	}
+	if h := hints[1]; h.Symbol != "WithBackoff" || h.Replaces != "backoff" || h.Reason != HintSimilarName || h.Package != "example.com/lib" {
+		t.Errorf("WithBackoff hint = %+v", h)
+	}
+}
@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Implementation describes how a project type's satisfaction of a changed
+// dependency interface changes with the upgrade. Method sets follow the
+// language rules: methods with pointer receivers only belong to *T, so a
+// value of T can stop satisfying an interface that *T still satisfies.
+type Implementation struct {
+	Type    string   // project type, such as app.Handler
+	Before  string   // Type or *Type, whichever satisfied the old interface
+	After   string   // the same for the new interface, empty when neither does
+	Missing []string // new methods Type lacks, or only *Type has when After is *Type
+}
+
+// Broken reports whether the type no longer satisfies the interface at all
+func (i Implementation) Broken() bool { return i.After == "" }
+
+// methodSet maps method names to their signature shapes, which ignore
+// parameter names so implementations match the interface they satisfy
+type methodSet map[string]string
+
+// projectType is a project type with the method sets of T and *T
+type projectType struct {
+	name    string // package-qualified, such as app.Handler
+	value   methodSet
+	pointer methodSet
+}
+
+// typeMethodSets returns the exported methods of T and of *T, including
+// methods promoted from embedded fields
+func typeMethodSets(named *types.Named) (value, pointer methodSet) {
+	collect := func(t types.Type) methodSet {
+		set := make(methodSet)
+		mset := types.NewMethodSet(t)
+		for i := 0; i < mset.Len(); i++ {
+			if fn := mset.At(i).Obj(); fn.Exported() {
+				set[fn.Name()] = signatureShape(fn.Type().(*types.Signature), nil)
+			}
+		}
+		return set
+	}
+	return collect(named), collect(types.NewPointer(named))
+}
+
+// interfaceMethodSet returns the methods an interface requires
+func interfaceMethodSet(iface *types.Interface) methodSet {
+	set := make(methodSet, iface.NumMethods())
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		set[method.Name()] = signatureShape(method.Type().(*types.Signature), nil)
+	}
+	return set
+}
+
+// missing lists the required methods the set lacks or declares with another
+// signature, sorted by name
+func (m methodSet) missing(required methodSet) []string {
+	var names []string
+	for name, shape := range required {
+		if m[name] != shape {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// satisfier names the form of the type that satisfies required: T when its
+// value method set does, *T when only the pointer's does, and "" otherwise
+func (t projectType) satisfier(required methodSet) string {
+	switch {
+	case len(t.value.missing(required)) == 0:
+		return t.name
+	case len(t.pointer.missing(required)) == 0:
+		return "*" + t.name
+	default:
+		return ""
+	}
+}
+
+// projectTypes collects the concrete, non-generic types declared in the
+// project packages together with their method sets
+func projectTypes(pkgs []*packages.Package) []projectType {
+	var result []projectType
+	for _, pkg := range pkgs {
+		if pkg.Types == nil {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || obj.IsAlias() {
+				continue
+			}
+			named, ok := obj.Type().(*types.Named)
+			if !ok || types.IsInterface(named) || named.TypeParams().Len() > 0 {
+				continue
+			}
+			value, pointer := typeMethodSets(named)
+			if len(pointer) == 0 {
+				continue
+			}
+			result = append(result, projectType{
+				name:    pkg.Types.Name() + "." + name,
+				value:   value,
+				pointer: pointer,
+			})
+		}
+	}
+	return result
+}
+
+// implementations checks the project types that satisfied the old interface
+// against the new one and returns those whose satisfaction changed
+func (u *Usage) implementations(oldIface, newIface *Interface) []Implementation {
+	if oldIface.obj == nil || newIface.obj == nil {
+		return nil
+	}
+	oldRequired := interfaceMethodSet(oldIface.obj.Type().Underlying().(*types.Interface))
+	newRequired := interfaceMethodSet(newIface.obj.Type().Underlying().(*types.Interface))
+	if len(oldRequired) == 0 {
+		return nil // every type satisfies an empty interface
+	}
+
+	var result []Implementation
+	for _, t := range u.types {
+		before := t.satisfier(oldRequired)
+		if before == "" {
+			continue
+		}
+		after := t.satisfier(newRequired)
+		if after == before || after == t.name {
+			continue // unchanged, or satisfied by values as well now
+		}
+		missing := t.pointer.missing(newRequired)
+		if after != "" {
+			missing = t.value.missing(newRequired)
+		}
+		result = append(result, Implementation{
+			Type:    t.name,
+			Before:  before,
+			After:   after,
+			Missing: missing,
+		})
+	}
+	return result
+}
@@ -0,0 +1,188 @@
+package analyzer
+
+import (
+	"go/types"
+	"reflect"
+	"sort"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const implementsProject = `package app
+
+type Base struct{}
+
+func (Base) Name() string { return "" }
+
+func (*Base) Reset() {}
+
+type ByValue struct{}
+
+func (ByValue) Serve(n int) error { return nil }
+
+func (ByValue) Close() error { return nil }
+
+type ByPointer struct{}
+
+func (*ByPointer) Serve(count int) error { return nil }
+
+type Mixed struct{}
+
+func (Mixed) Serve(n int) error { return nil }
+
+func (*Mixed) Close() error { return nil }
+
+type EmbedsValue struct{ Base }
+
+type EmbedsPointer struct{ *Base }
+
+type Wrong struct{}
+
+func (Wrong) Serve(s string) error { return nil }
+
+func (Wrong) Close() error { return nil }
+
+type NoMethods struct{}
+
+type Generic[T any] struct{}
+
+func (Generic[T]) Serve(n int) error { return nil }
+
+type Iface interface{ Serve(n int) error }
+
+func (ByValue) hidden() {}
+`
+
+func loadImplementsProject(t *testing.T) map[string]projectType {
+	t.Helper()
+	pkg := checkSource(t, "example.com/app", implementsProject, nil)
+	byName := make(map[string]projectType)
+	for _, pt := range projectTypes([]*packages.Package{pkg}) {
+		byName[pt.name] = pt
+	}
+	return byName
+}
+
+func methodNames(set methodSet) []string {
+	var names []string
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestProjectTypes_MethodSets(t *testing.T) {
+	byName := loadImplementsProject(t)
+
+	tests := []struct {
+		name    string
+		value   []string
+		pointer []string
+	}{
+		{"app.Base", []string{"Name"}, []string{"Name", "Reset"}},
+		{"app.ByValue", []string{"Close", "Serve"}, []string{"Close", "Serve"}},
+		{"app.ByPointer", nil, []string{"Serve"}},
+		{"app.Mixed", []string{"Serve"}, []string{"Close", "Serve"}},
+		{"app.EmbedsValue", []string{"Name"}, []string{"Name", "Reset"}},
+		{"app.EmbedsPointer", []string{"Name", "Reset"}, []string{"Name", "Reset"}},
+	}
+	for _, tt := range tests {
+		pt, ok := byName[tt.name]
+		if !ok {
+			t.Errorf("%s not collected", tt.name)
+			continue
+		}
+		if got := methodNames(pt.value); !reflect.DeepEqual(got, tt.value) {
+			t.Errorf("%s value methods = %v, want %v", tt.name, got, tt.value)
+		}
+		if got := methodNames(pt.pointer); !reflect.DeepEqual(got, tt.pointer) {
+			t.Errorf("%s pointer methods = %v, want %v", tt.name, got, tt.pointer)
+		}
+	}
+
+	for _, skipped := range []string{"app.NoMethods", "app.Generic", "app.Iface"} {
+		if _, ok := byName[skipped]; ok {
+			t.Errorf("%s should not be collected", skipped)
+		}
+	}
+}
+
+func TestProjectType_Satisfier(t *testing.T) {
+	byName := loadImplementsProject(t)
+	serve := methodSet{"Serve": "(int) (error)"}
+	serveClose := methodSet{"Serve": "(int) (error)", "Close": "() (error)"}
+
+	tests := []struct {
+		typ      string
+		required methodSet
+		want     string
+	}{
+		{"app.ByValue", serve, "app.ByValue"},
+		{"app.ByValue", serveClose, "app.ByValue"},
+		{"app.ByPointer", serve, "*app.ByPointer"}, // parameter names do not matter
+		{"app.ByPointer", serveClose, ""},
+		{"app.Mixed", serve, "app.Mixed"},
+		{"app.Mixed", serveClose, "*app.Mixed"},
+		{"app.Wrong", serve, ""}, // same name, different signature
+	}
+	for _, tt := range tests {
+		if got := byName[tt.typ].satisfier(tt.required); got != tt.want {
+			t.Errorf("%s.satisfier(%v) = %q, want %q", tt.typ, methodNames(tt.required), got, tt.want)
+		}
+	}
+}
+
+func TestMethodSet_Missing(t *testing.T) {
+	set := methodSet{"Serve": "(int) (error)", "Close": "() (error)"}
+	required := methodSet{"Serve": "(string) (error)", "Close": "() (error)", "Flush": "() ()"}
+	if got, want := set.missing(required), []string{"Flush", "Serve"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("missing() = %v, want %v", got, want)
+	}
+	if got := set.missing(methodSet{}); got != nil {
+		t.Errorf("missing(empty) = %v, want nil", got)
+	}
+}
+
+func TestDiffInterfaces_Implementations(t *testing.T) {
+	oldLib := checkSource(t, "example.com/lib", `package lib
+
+type Handler interface{ Serve(n int) error }
+
+type Empty interface{}
+`, nil)
+	newLib := checkSource(t, "example.com/lib", `package lib
+
+type Handler interface {
+	Serve(n int) error
+	Close() error
+}
+
+type Empty interface{ Close() error }
+`, nil)
+	app := checkSource(t, "example.com/app", implementsProject, nil)
+	app.Imports = map[string]*packages.Package{
+		"example.com/lib": {PkgPath: "example.com/lib", Module: &packages.Module{Path: "example.com/lib"}, Types: types.NewPackage("example.com/lib", "lib")},
+	}
+
+	a := &Analyzer{pkgs: []*packages.Package{app}}
+	usage := a.findUsage("example.com/lib")
+	usage.All = true
+	diff := diffAPIs(extractAPI([]*packages.Package{oldLib}), extractAPI([]*packages.Package{newLib}), usage)
+
+	changes := make(map[string]InterfaceChange)
+	for _, ic := range diff.InterfaceChanges {
+		changes[ic.Name] = ic
+	}
+	want := []Implementation{
+		{Type: "app.ByPointer", Before: "*app.ByPointer", Missing: []string{"Close"}},
+		{Type: "app.Mixed", Before: "app.Mixed", After: "*app.Mixed", Missing: []string{"Close"}},
+	}
+	if got := changes["Handler"].Implementations; !reflect.DeepEqual(got, want) {
+		t.Errorf("Handler implementations = %+v, want %+v", got, want)
+	}
+	if got := changes["Empty"].Implementations; got != nil {
+		t.Errorf("Empty implementations = %+v, want none for a previously empty interface", got)
+	}
+}
@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Module modes for loading module versions, see Options.ModFlag
+const (
+	ModReadonly = "readonly" // fail rather than update a go.mod or go.sum
+	ModMod      = "mod"      // update them as needed
+)
+
+// Loader loads Go packages as packages.Load does. Embedders can supply their
+// own through Options.Loader, for example to load from a remote build system
+// or from pre-built export data.
+type Loader interface {
+	Load(cfg *packages.Config, patterns ...string) ([]*packages.Package, error)
+}
+
+// LoaderFunc adapts an ordinary function to the Loader interface
+type LoaderFunc func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error)
+
+// Load calls f(cfg, patterns...)
+func (f LoaderFunc) Load(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+	return f(cfg, patterns...)
+}
+
+// load loads packages with the configured Loader, or packages.Load by default
+func (a *Analyzer) load(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+	if a.opts.Loader != nil {
+		return a.opts.Loader.Load(cfg, patterns...)
+	}
+	return packagesLoad(cfg, patterns...)
+}
+
+// isolatedModulePath is the module path of the temporary module that
+// loadIsolated loads package versions in
+const isolatedModulePath = "go-semver-audit.local/load"
+
+// runGoEnv runs the go command in dir with env. Allow overriding in tests.
+var runGoEnv = func(dir string, env []string, args ...string) ([]byte, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	out, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return out, fmt.Errorf("go %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return out, err
+}
+
+// loadIsolated is packages.Load, except that package@version patterns, such
+// as example.com/lib/...@v1.2.0, are loaded inside a temporary module that
+// requires those versions. Whatever directory the analyzer runs in, the go
+// command then never reads or updates the project's go.mod, go.sum, or
+// go.work; only the module cache is shared.
+func loadIsolated(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+	var paths, versioned []string
+	for _, pattern := range patterns {
+		if i := strings.LastIndex(pattern, "@"); i > 0 {
+			paths = append(paths, pattern[:i])
+			versioned = append(versioned, pattern)
+		} else {
+			paths = append(paths, pattern)
+		}
+	}
+	if len(versioned) == 0 {
+		return packages.Load(cfg, patterns...)
+	}
+
+	dir, err := os.MkdirTemp("", "go-semver-audit-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	isolated := *cfg
+	isolated.Dir = dir
+	isolated.Env = append(append([]string(nil), cfg.Env...), "GOWORK=off")
+	if cfg.Env == nil {
+		isolated.Env = append(os.Environ(), "GOWORK=off")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module "+isolatedModulePath+"\n"), 0o644); err != nil {
+		return nil, err
+	}
+	if _, err := runGoEnv(dir, isolated.Env, append([]string{"get"}, versioned...)...); err != nil {
+		return nil, fmt.Errorf("failed to require %s in a temporary module: %w", strings.Join(versioned, " "), err)
+	}
+	return packages.Load(&isolated, paths...)
+}
+
+// moduleCacheEnv is the environment for loading module versions from the
+// module cache. Those loads always use the go command, since a
+// GOPACKAGESDRIVER serving the project's build system cannot resolve
+// module@version patterns. The user's GOFLAGS are kept, with -mod set as
+// mergeGOFLAGS decides.
+func moduleCacheEnv(modFlag string) []string {
+	return append(os.Environ(), "GOFLAGS="+mergeGOFLAGS(userGOFLAGS(), modFlag), "GOPACKAGESDRIVER=off")
+}
+
+// mergeGOFLAGS sets -mod in a GOFLAGS value, keeping its other flags such as
+// -tags. The mode is modFlag when set, otherwise the one flags already
+// names, unless that is vendor, which cannot load module versions; readonly
+// is the default.
+func mergeGOFLAGS(flags, modFlag string) string {
+	var kept []string
+	userMod := ""
+	for _, flag := range strings.Fields(flags) {
+		if name, value, _ := strings.Cut(strings.TrimLeft(flag, "-"), "="); name == "mod" {
+			userMod = value
+			continue
+		}
+		kept = append(kept, flag)
+	}
+	switch {
+	case modFlag != "":
+	case userMod != "" && userMod != "vendor":
+		modFlag = userMod
+	default:
+		modFlag = ModReadonly
+	}
+	return strings.Join(append(kept, "-mod="+modFlag), " ")
+}
+
+// userGOFLAGS returns the GOFLAGS the go command would use: the environment
+// variable when set, otherwise the value saved with go env -w. Setting the
+// variable hides the saved value from the go command, so it is merged here.
+func userGOFLAGS() string {
+	if flags, ok := os.LookupEnv("GOFLAGS"); ok {
+		return flags
+	}
+	file := os.Getenv("GOENV")
+	if file == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return ""
+		}
+		file = filepath.Join(dir, "go", "env")
+	}
+	if file == "off" {
+		return ""
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	flags := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "GOFLAGS="); ok {
+			flags = value
+		}
+	}
+	return flags
+}
@@ -0,0 +1,138 @@
+package analyzer
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestAnalyzer_CustomLoader(t *testing.T) {
+	restoreLoad := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		return nil, errors.New("packages.Load should not be used with a custom loader")
+	})
+	defer restoreLoad()
+	restorePrint := mockPackagesPrintErrors(func([]*packages.Package) int { return 0 })
+	defer restorePrint()
+
+	lib := checkSource(t, "example.com/lib", "package lib\n\nfunc Open() {}\n", nil)
+	var calls [][]string
+	var moduleEnv []string
+	loader := LoaderFunc(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		calls = append(calls, patterns)
+		if patterns[0] == "./..." {
+			return []*packages.Package{{PkgPath: "example.com/app"}}, nil
+		}
+		moduleEnv = cfg.Env
+		return []*packages.Package{lib}, nil
+	})
+
+	a := &Analyzer{projectPath: t.TempDir(), opts: Options{Loader: loader}}
+	if err := a.loadProject(); err != nil {
+		t.Fatalf("loadProject() error = %v", err)
+	}
+	api, err := a.loadModuleAPI("example.com/lib", "v1.0.0")
+	if err != nil {
+		t.Fatalf("loadModuleAPI() error = %v", err)
+	}
+
+	if len(calls) != 2 || calls[1][0] != "example.com/lib/...@v1.0.0" {
+		t.Errorf("loader calls = %v, want the project and then the module", calls)
+	}
+	if _, ok := api.Funcs["Open"]; !ok {
+		t.Errorf("expected the API loaded by the custom loader, got %+v", api.Funcs)
+	}
+	if len(moduleEnv) == 0 || moduleEnv[len(moduleEnv)-1] != "GOPACKAGESDRIVER=off" {
+		t.Errorf("module cache loads should bypass GOPACKAGESDRIVER, env ends with %v", moduleEnv[len(moduleEnv)-1:])
+	}
+}
+
+func TestMergeGOFLAGS(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   string
+		modFlag string
+		want    string
+	}{
+		{"default", "", "", "-mod=readonly"},
+		{"keeps other flags", "-tags=integration -trimpath", "", "-tags=integration -trimpath -mod=readonly"},
+		{"keeps the user's mode", "-mod=mod -tags=integration", "", "-tags=integration -mod=mod"},
+		{"vendor cannot load versions", "--mod=vendor", "", "-mod=readonly"},
+		{"option wins", "-mod=mod", ModReadonly, "-mod=readonly"},
+		{"option sets mod", "-tags=e2e", ModMod, "-tags=e2e -mod=mod"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeGOFLAGS(tt.flags, tt.modFlag); got != tt.want {
+				t.Errorf("mergeGOFLAGS(%q, %q) = %q, want %q", tt.flags, tt.modFlag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserGOFLAGS(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), "env")
+	if err := os.WriteFile(envFile, []byte("GOPROXY=direct\nGOFLAGS=-tags=saved\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOENV", envFile)
+
+	t.Setenv("GOFLAGS", "-tags=env")
+	if got := userGOFLAGS(); got != "-tags=env" {
+		t.Errorf("userGOFLAGS() = %q, want the environment variable", got)
+	}
+
+	os.Unsetenv("GOFLAGS")
+	if got := userGOFLAGS(); got != "-tags=saved" {
+		t.Errorf("userGOFLAGS() = %q, want the value saved with go env -w", got)
+	}
+	if env := moduleCacheEnv(ModMod); env[len(env)-2] != "GOFLAGS=-tags=saved -mod=mod" {
+		t.Errorf("moduleCacheEnv() sets %q, want the saved flags merged", env[len(env)-2])
+	}
+}
+
+func TestLoadIsolated(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not available")
+	}
+	lib := t.TempDir()
+	writeFile(t, filepath.Join(lib, "go.mod"), "module example.com/lib\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(lib, "lib.go"), "package lib\n\nfunc Open() {}\n")
+
+	// Resolve the version to the local copy instead of downloading it
+	var gotDir string
+	var gotArgs, gotEnv []string
+	orig := runGoEnv
+	runGoEnv = func(dir string, env []string, args ...string) ([]byte, error) {
+		gotDir, gotArgs, gotEnv = dir, args, env
+		modFile := "module " + isolatedModulePath + "\n\ngo 1.21\n\nrequire example.com/lib v1.2.0\n\nreplace example.com/lib => " + lib + "\n"
+		return nil, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(modFile), 0o644)
+	}
+	defer func() { runGoEnv = orig }()
+
+	cfg := &packages.Config{Mode: packages.NeedName | packages.NeedTypes, Env: append(os.Environ(), "GOPROXY=off", "GOFLAGS=-mod=mod")}
+	pkgs, err := loadIsolated(cfg, "example.com/lib@v1.2.0")
+	if err != nil {
+		t.Fatalf("loadIsolated() error = %v", err)
+	}
+	if len(pkgs) != 1 || pkgs[0].PkgPath != "example.com/lib" || pkgs[0].Types.Scope().Lookup("Open") == nil {
+		t.Fatalf("loadIsolated() = %v, want example.com/lib with Open", pkgs)
+	}
+
+	if !reflect.DeepEqual(gotArgs, []string{"get", "example.com/lib@v1.2.0"}) {
+		t.Errorf("go command args = %v, want go get of the versioned pattern", gotArgs)
+	}
+	if gotEnv[len(gotEnv)-1] != "GOWORK=off" {
+		t.Errorf("go get env ends with %q, want GOWORK=off", gotEnv[len(gotEnv)-1])
+	}
+	if _, err := os.Stat(gotDir); !os.IsNotExist(err) {
+		t.Errorf("temporary module %s should be removed, stat error = %v", gotDir, err)
+	}
+	if cfg.Dir != "" {
+		t.Errorf("cfg.Dir = %q, want the caller's config left alone", cfg.Dir)
+	}
+}
@@ -0,0 +1,45 @@
+package analyzer
+
+import (
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// LoadError is an error reported while loading a project package that
+// Options.AllowErrors let the analysis continue past
+type LoadError struct {
+	Package  string
+	Position string // file:line:column, empty if unknown
+	Message  string
+}
+
+// projectLoadErrors lists the errors of the loaded project packages and of
+// the packages they import, sorted and without duplicates
+func projectLoadErrors(pkgs []*packages.Package) []LoadError {
+	var loadErrors []LoadError
+	seen := make(map[LoadError]bool)
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, err := range pkg.Errors {
+			le := LoadError{Package: pkg.PkgPath, Position: err.Pos, Message: err.Msg}
+			if !seen[le] {
+				seen[le] = true
+				loadErrors = append(loadErrors, le)
+			}
+		}
+	})
+
+	sort.Slice(loadErrors, func(i, j int) bool {
+		if loadErrors[i].Package != loadErrors[j].Package {
+			return loadErrors[i].Package < loadErrors[j].Package
+		}
+		return loadErrors[i].Position < loadErrors[j].Position
+	})
+	return loadErrors
+}
+
+// approximateUsage reports whether type information for pkg may be incomplete
+// because it, or a package it imports, failed to load
+func approximateUsage(pkg *packages.Package) bool {
+	return pkg.IllTyped || len(pkg.Errors) > 0
+}
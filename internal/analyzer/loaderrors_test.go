@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"go/types"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestLoadProject_AllowErrors(t *testing.T) {
+	broken := &packages.Package{
+		PkgPath:  "example.com/app/broken",
+		IllTyped: true,
+		Errors: []packages.Error{
+			{Pos: "broken/b.go:3:2", Msg: "undefined: lib.Gone"},
+			{Pos: "broken/a.go:7:1", Msg: "missing return"},
+		},
+	}
+	app := &packages.Package{PkgPath: "example.com/app", IllTyped: true, Imports: map[string]*packages.Package{broken.PkgPath: broken}}
+	restoreLoad := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		return []*packages.Package{app, broken}, nil
+	})
+	defer restoreLoad()
+	restorePrint := mockPackagesPrintErrors(func(pkgs []*packages.Package) int { return 2 })
+	defer restorePrint()
+
+	a := &Analyzer{projectPath: t.TempDir()}
+	if err := a.loadProject(); err == nil {
+		t.Fatal("expected loadProject to fail on package errors by default")
+	}
+
+	a.opts.AllowErrors = true
+	if err := a.loadProject(); err != nil {
+		t.Fatalf("loadProject() with AllowErrors error = %v", err)
+	}
+	want := []LoadError{
+		{Package: "example.com/app/broken", Position: "broken/a.go:7:1", Message: "missing return"},
+		{Package: "example.com/app/broken", Position: "broken/b.go:3:2", Message: "undefined: lib.Gone"},
+	}
+	if got := projectLoadErrors(a.pkgs); !reflect.DeepEqual(got, want) {
+		t.Errorf("projectLoadErrors() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindUsage_ApproximateInBrokenPackages(t *testing.T) {
+	lib := checkSource(t, "example.com/lib", "package lib\n\nfunc Open() {}\n", nil)
+	app := checkSource(t, "example.com/app", `package app
+
+import "example.com/lib"
+
+func run() { lib.Open() }
+`, map[string]*types.Package{"example.com/lib": lib.Types})
+	app.Imports = map[string]*packages.Package{
+		"example.com/lib": {PkgPath: "example.com/lib", Module: &packages.Module{Path: "example.com/lib"}},
+	}
+	app.IllTyped = true
+
+	for _, allow := range []bool{false, true} {
+		a := &Analyzer{pkgs: []*packages.Package{app}, opts: Options{AllowErrors: allow}}
+		locations := a.findUsage("example.com/lib").Symbols["Open"]
+		if len(locations) != 1 || locations[0].Approximate != allow {
+			t.Errorf("AllowErrors=%v: Open locations = %+v, want approximate %v", allow, locations, allow)
+		}
+	}
+}
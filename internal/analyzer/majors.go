@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"golang.org/x/mod/module"
+	"golang.org/x/tools/go/packages"
+)
+
+// DuplicateMajor is another major version of the audited module that stays
+// in the project's module graph after the upgrade, such as example.com/lib
+// next to example.com/lib/v2
+type DuplicateMajor struct {
+	Module    string   // module path of the other major
+	Version   string   // version in the module graph, empty if unknown
+	Importers []string // project packages still importing it; empty when only dependencies require it
+}
+
+// findDuplicateMajors looks for other major versions of the module among the
+// modules the project builds with, and the project packages importing them.
+// The project is loaded before the upgrade, so a major it still imports
+// remains in the graph next to the upgraded one.
+func (a *Analyzer) findDuplicateMajors(target string) []DuplicateMajor {
+	prefix, _, ok := module.SplitPathVersion(target)
+	if !ok {
+		return nil
+	}
+	isOther := func(path string) bool {
+		if path == target {
+			return false
+		}
+		p, _, ok := module.SplitPathVersion(path)
+		return ok && p == prefix
+	}
+
+	others := make(map[string]*DuplicateMajor)
+	add := func(path, version string) *DuplicateMajor {
+		dup := others[path]
+		if dup == nil {
+			dup = &DuplicateMajor{Module: path}
+			others[path] = dup
+		}
+		if dup.Version == "" {
+			dup.Version = version
+		}
+		return dup
+	}
+
+	if f, err := a.projectModFile(); err == nil {
+		for _, req := range f.Require {
+			if isOther(req.Mod.Path) {
+				add(req.Mod.Path, req.Mod.Version)
+			}
+		}
+	}
+
+	// Project packages import the other majors directly; the rest of the
+	// graph only requires them
+	importers := make(map[string]map[string]bool)
+	for _, pkg := range a.pkgs {
+		for _, imp := range pkg.Imports {
+			if imp.Module == nil || !isOther(imp.Module.Path) {
+				continue
+			}
+			if importers[imp.Module.Path] == nil {
+				importers[imp.Module.Path] = make(map[string]bool)
+			}
+			importers[imp.Module.Path][pkg.PkgPath] = true
+		}
+	}
+	seen := make(map[*packages.Package]bool)
+	queue := append([]*packages.Package(nil), a.pkgs...)
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+		if seen[pkg] {
+			continue
+		}
+		seen[pkg] = true
+		if pkg.Module != nil && isOther(pkg.Module.Path) {
+			add(pkg.Module.Path, pkg.Module.Version)
+		}
+		for _, imp := range pkg.Imports {
+			queue = append(queue, imp)
+		}
+	}
+
+	var result []DuplicateMajor
+	for _, path := range sortedKeys(others) {
+		dup := others[path]
+		if len(importers[path]) > 0 {
+			dup.Importers = sortedKeys(importers[path])
+		}
+		result = append(result, *dup)
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestFindDuplicateMajors(t *testing.T) {
+	dir := t.TempDir()
+	mod := "module example.com/app\n\nrequire (\n\tgithub.com/example/lib v1.8.0\n\tgithub.com/example/lib/v2 v2.0.0\n)\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(mod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	v1 := &packages.Package{PkgPath: "github.com/example/lib", Module: &packages.Module{Path: "github.com/example/lib", Version: "v1.8.0"}}
+	v2 := &packages.Package{PkgPath: "github.com/example/lib/v2/client", Module: &packages.Module{Path: "github.com/example/lib/v2", Version: "v2.0.0"}}
+	v3 := &packages.Package{PkgPath: "github.com/example/lib/v3", Module: &packages.Module{Path: "github.com/example/lib/v3", Version: "v3.1.0"}}
+	other := &packages.Package{PkgPath: "example.com/other", Module: &packages.Module{Path: "example.com/other"},
+		Imports: map[string]*packages.Package{v3.PkgPath: v3}}
+	a := &Analyzer{projectPath: dir, pkgs: []*packages.Package{
+		{PkgPath: "example.com/app/db", Imports: map[string]*packages.Package{v1.PkgPath: v1, v2.PkgPath: v2}},
+		{PkgPath: "example.com/app/web", Imports: map[string]*packages.Package{v1.PkgPath: v1, other.PkgPath: other}},
+		{PkgPath: "example.com/app/api", Imports: map[string]*packages.Package{v2.PkgPath: v2}},
+	}}
+
+	got := a.findDuplicateMajors("github.com/example/lib/v2")
+	want := []DuplicateMajor{
+		{Module: "github.com/example/lib", Version: "v1.8.0", Importers: []string{"example.com/app/db", "example.com/app/web"}},
+		{Module: "github.com/example/lib/v3", Version: "v3.1.0"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findDuplicateMajors() = %+v, want %+v", got, want)
+	}
+
+	if got := a.findDuplicateMajors("example.com/other"); got != nil {
+		t.Errorf("findDuplicateMajors() = %+v for a module with one major", got)
+	}
+}
@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Allow overriding in tests
+var (
+	runGoCommand = func(dir string, args ...string) ([]byte, error) {
+		cmd := exec.Command("go", args...)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return out, fmt.Errorf("go %s: %s", args[0], exitErr.Stderr)
+		}
+		return out, err
+	}
+	readFile = os.ReadFile
+)
+
+// moduleDownload mirrors the fields of `go mod download -json` that we use
+type moduleDownload struct {
+	Path    string
+	Version string
+	Error   string
+	Sum     string
+	GoMod   string
+	Zip     string
+	Dir     string
+}
+
+// downloadModule fetches module@version into the module cache and reports its location
+func (a *Analyzer) downloadModule(module, version string) (*moduleDownload, error) {
+	out, err := runGoCommand(a.projectPath, "mod", "download", "-json", module+"@"+version)
+	var info moduleDownload
+	if jsonErr := json.Unmarshal(out, &info); jsonErr != nil {
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s@%s: %w", module, version, err)
+		}
+		return nil, fmt.Errorf("failed to parse download info for %s@%s: %w", module, version, jsonErr)
+	}
+	if info.Error != "" {
+		return nil, fmt.Errorf("failed to download %s@%s: %s", module, version, info.Error)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s@%s: %w", module, version, err)
+	}
+	return &info, nil
+}
+
+// loadModuleFile parses the go.mod of a module version from the module cache
+func (a *Analyzer) loadModuleFile(module, version string) (*modfile.File, error) {
+	info, err := a.downloadModule(module, version)
+	if err != nil {
+		return nil, err
+	}
+	return parseDownloadedModFile(info)
+}
+
+// parseDownloadedModFile parses the go.mod of a downloaded module
+func parseDownloadedModFile(info *moduleDownload) (*modfile.File, error) {
+	module, version := info.Path, info.Version
+	data, err := readFile(info.GoMod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod for %s@%s: %w", module, version, err)
+	}
+	f, err := modfile.ParseLax(info.GoMod, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod for %s@%s: %w", module, version, err)
+	}
+	return f, nil
+}
+
+// projectModFile parses the project's go.mod, searching parent directories as the go command does
+func (a *Analyzer) projectModFile() (*modfile.File, error) {
+	dir := a.projectPath
+	for {
+		path := filepath.Join(dir, "go.mod")
+		data, err := readFile(path)
+		if err == nil {
+			f, err := modfile.Parse(path, data, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			return f, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, fmt.Errorf("no go.mod found in %s or any parent directory", a.projectPath)
+		}
+		dir = parent
+	}
+}
+
+// moduleRequirements lists the modules required by module@version, annotated with
+// the version the project currently requires (if any)
+func (a *Analyzer) moduleRequirements(module, version string) ([]Requirement, error) {
+	depFile, err := a.loadModuleFile(module, version)
+	if err != nil {
+		return nil, err
+	}
+
+	projectVersions := make(map[string]string)
+	if projFile, err := a.projectModFile(); err == nil {
+		for _, req := range projFile.Require {
+			projectVersions[req.Mod.Path] = req.Mod.Version
+		}
+	}
+
+	var reqs []Requirement
+	for _, req := range depFile.Require {
+		reqs = append(reqs, Requirement{
+			Path:           req.Mod.Path,
+			Version:        req.Mod.Version,
+			ProjectVersion: projectVersions[req.Mod.Path],
+		})
+	}
+
+	sort.Slice(reqs, func(i, j int) bool { return reqs[i].Path < reqs[j].Path })
+	return reqs, nil
+}
@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDownloadModule(t *testing.T) {
+	restore := mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+		if strings.Join(args, " ") != "mod download -json example.com/lib@v1.0.0" {
+			t.Fatalf("unexpected go command: %v", args)
+		}
+		return []byte(`{"Path":"example.com/lib","Version":"v1.0.0","GoMod":"/cache/lib.mod"}`), nil
+	})
+	defer restore()
+
+	a := &Analyzer{projectPath: "."}
+	info, err := a.downloadModule("example.com/lib", "v1.0.0")
+	if err != nil {
+		t.Fatalf("downloadModule() error = %v", err)
+	}
+	if info.GoMod != "/cache/lib.mod" {
+		t.Fatalf("downloadModule() GoMod = %q", info.GoMod)
+	}
+}
+
+func TestDownloadModuleReportsError(t *testing.T) {
+	restore := mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+		return []byte(`{"Path":"example.com/lib","Version":"v9.9.9","Error":"unknown revision v9.9.9"}`), errors.New("exit status 1")
+	})
+	defer restore()
+
+	a := &Analyzer{projectPath: "."}
+	_, err := a.downloadModule("example.com/lib", "v9.9.9")
+	if err == nil || !strings.Contains(err.Error(), "unknown revision") {
+		t.Fatalf("downloadModule() expected unknown revision error, got %v", err)
+	}
+}
+
+func TestProjectModFileSearchesParents(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/app\n\ngo 1.21\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(root, "cmd", "app")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &Analyzer{projectPath: sub}
+	f, err := a.projectModFile()
+	if err != nil {
+		t.Fatalf("projectModFile() error = %v", err)
+	}
+	if f.Module.Mod.Path != "example.com/app" {
+		t.Fatalf("projectModFile() module = %q", f.Module.Mod.Path)
+	}
+}
+
+func TestModuleRequirements(t *testing.T) {
+	restoreCmd := mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+		return []byte(`{"Path":"example.com/lib","Version":"v1.0.0","GoMod":"lib.mod"}`), nil
+	})
+	defer restoreCmd()
+	restoreRead := mockReadFile(map[string]string{
+		"lib.mod":                       "module example.com/lib\n\nrequire (\n\texample.com/b v1.2.0\n\texample.com/a v0.3.0\n\texample.com/c v1.0.0\n)\n",
+		filepath.Join("proj", "go.mod"): "module example.com/app\n\nrequire (\n\texample.com/a v0.3.0\n\texample.com/b v1.1.0\n)\n",
+	})
+	defer restoreRead()
+
+	a := &Analyzer{projectPath: "proj"}
+	reqs, err := a.moduleRequirements("example.com/lib", "v1.0.0")
+	if err != nil {
+		t.Fatalf("moduleRequirements() error = %v", err)
+	}
+
+	want := []Requirement{
+		{Path: "example.com/a", Version: "v0.3.0", ProjectVersion: "v0.3.0"},
+		{Path: "example.com/b", Version: "v1.2.0", ProjectVersion: "v1.1.0"},
+		{Path: "example.com/c", Version: "v1.0.0"},
+	}
+	if len(reqs) != len(want) {
+		t.Fatalf("moduleRequirements() = %+v, want %+v", reqs, want)
+	}
+	for i := range want {
+		if reqs[i] != want[i] {
+			t.Fatalf("moduleRequirements()[%d] = %+v, want %+v", i, reqs[i], want[i])
+		}
+	}
+}
+
+// --- Helpers ---
+
+func mockGoCommand(fn func(dir string, args ...string) ([]byte, error)) func() {
+	orig := runGoCommand
+	runGoCommand = fn
+	return func() {
+		runGoCommand = orig
+	}
+}
+
+func mockReadFile(files map[string]string) func() {
+	orig := readFile
+	readFile = func(name string) ([]byte, error) {
+		if data, ok := files[name]; ok {
+			return []byte(data), nil
+		}
+		return nil, os.ErrNotExist
+	}
+	return func() {
+		readFile = orig
+	}
+}
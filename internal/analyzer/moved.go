@@ -0,0 +1,222 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+)
+
+// detectMoves finds used symbols that left their package for another one in the
+// new version. Symbols that kept their name are matched directly. Removed symbols
+// are also matched to a single added symbol in another package with the same
+// shape and a similar name; those removals become moves and the additions are
+// no longer reported as added. Names exported by more than one package in
+// either version are never taken for moves, since their package depends on
+// which one the API kept.
+func detectMoves(oldAPI, newAPI *API, usage *Usage, diff *Diff) {
+	// Same name, different package
+	for _, name := range sortedKeys(oldAPI.Funcs) {
+		oldFunc := oldAPI.Funcs[name]
+		newFunc, ok := newAPI.Funcs[name]
+		if !ok || oldFunc.IsMethod || ambiguous(oldAPI, newAPI, name) || !movedPackage(oldFunc.PkgPath, newFunc.PkgPath) {
+			continue
+		}
+		addMove(diff, usage, name, name, "function", oldFunc.PkgPath, newFunc.PkgPath, oldFunc.Unstable || newFunc.Unstable)
+	}
+	for _, name := range sortedKeys(oldAPI.Types) {
+		oldType := oldAPI.Types[name]
+		newType, ok := newAPI.Types[name]
+		if !ok || ambiguous(oldAPI, newAPI, name) || !movedPackage(oldType.PkgPath, newType.PkgPath) {
+			continue
+		}
+		addMove(diff, usage, name, name, "type", oldType.PkgPath, newType.PkgPath, oldType.Unstable || newType.Unstable)
+	}
+	for _, name := range sortedKeys(oldAPI.Interfaces) {
+		oldIface := oldAPI.Interfaces[name]
+		newIface, ok := newAPI.Interfaces[name]
+		if !ok || ambiguous(oldAPI, newAPI, name) || !movedPackage(oldIface.PkgPath, newIface.PkgPath) {
+			continue
+		}
+		addMove(diff, usage, name, name, "interface", oldIface.PkgPath, newIface.PkgPath, oldIface.Unstable || newIface.Unstable)
+	}
+
+	// A move already breaks every use, so it replaces signature and interface changes
+	moved := make(map[string]bool)
+	for _, m := range diff.Moved {
+		moved[m.Name] = true
+	}
+	changed := diff.Changed[:0]
+	for _, c := range diff.Changed {
+		if !moved[c.Name] {
+			changed = append(changed, c)
+		}
+	}
+	diff.Changed = changed
+	ifaces := diff.InterfaceChanges[:0]
+	for _, ic := range diff.InterfaceChanges {
+		if !moved[ic.Name] {
+			ifaces = append(ifaces, ic)
+		}
+	}
+	diff.InterfaceChanges = ifaces
+
+	// Renamed while moving: pair removals with additions elsewhere
+	matched := make(map[string]bool)
+	removed := diff.Removed[:0]
+	for _, r := range diff.Removed {
+		oldPkg, newName, newPkg, ok := findMoveTarget(oldAPI, newAPI, diff.Added, r)
+		if !ok || matched[newName] {
+			removed = append(removed, r)
+			continue
+		}
+		matched[newName] = true
+		diff.Moved = append(diff.Moved, MovedSymbol{
+			Name:       r.Name,
+			NewName:    newName,
+			Type:       r.Type,
+			OldPackage: oldPkg,
+			NewPackage: newPkg,
+			UsedIn:     r.UsedIn,
+			Unstable:   r.Unstable,
+		})
+	}
+	diff.Removed = removed
+
+	added := diff.Added[:0]
+	for _, a := range diff.Added {
+		if !matched[a.Name] {
+			added = append(added, a)
+		}
+	}
+	diff.Added = added
+}
+
+// addMove records a move of a used symbol that kept its name
+func addMove(diff *Diff, usage *Usage, name, newName, kind, oldPkg, newPkg string, unstable bool) {
+	locations, used := usage.uses(name)
+	if !used {
+		return
+	}
+	diff.Moved = append(diff.Moved, MovedSymbol{
+		Name:       name,
+		NewName:    newName,
+		Type:       kind,
+		OldPackage: oldPkg,
+		NewPackage: newPkg,
+		UsedIn:     locations,
+		Unstable:   unstable,
+	})
+}
+
+// ambiguous reports whether more than one package exports a name in either API
+func ambiguous(oldAPI, newAPI *API, name string) bool {
+	return oldAPI.Ambiguous[name] || newAPI.Ambiguous[name]
+}
+
+// movedPackage reports whether a symbol's package path changed between versions
+func movedPackage(oldPkg, newPkg string) bool {
+	return oldPkg != "" && newPkg != "" && oldPkg != newPkg
+}
+
+// findMoveTarget looks for exactly one added symbol of the same kind in another
+// package that matches the removed symbol's shape and has a similar name
+func findMoveTarget(oldAPI, newAPI *API, added []AddedSymbol, r RemovedSymbol) (oldPkg, newName, newPkg string, ok bool) {
+	if oldAPI.Ambiguous[r.Name] {
+		return "", "", "", false
+	}
+	var candidates []AddedSymbol
+	switch r.Type {
+	case "function":
+		oldFunc, exists := oldAPI.Funcs[r.Name]
+		if !exists || oldFunc.IsMethod {
+			return "", "", "", false
+		}
+		oldPkg = oldFunc.PkgPath
+		for _, a := range added {
+			if fn, exists := newAPI.Funcs[a.Name]; exists && a.Type == r.Type && !fn.IsMethod &&
+				movedPackage(oldPkg, fn.PkgPath) && fn.Signature.Text == oldFunc.Signature.Text {
+				candidates = append(candidates, a)
+			}
+		}
+	case "type":
+		oldType, exists := oldAPI.Types[r.Name]
+		if !exists {
+			return "", "", "", false
+		}
+		oldPkg = oldType.PkgPath
+		for _, a := range added {
+			if t, exists := newAPI.Types[a.Name]; exists && a.Type == r.Type &&
+				movedPackage(oldPkg, t.PkgPath) && t.Kind == oldType.Kind {
+				candidates = append(candidates, a)
+			}
+		}
+	case "interface":
+		oldIface, exists := oldAPI.Interfaces[r.Name]
+		if !exists {
+			return "", "", "", false
+		}
+		oldPkg = oldIface.PkgPath
+		for _, a := range added {
+			if iface, exists := newAPI.Interfaces[a.Name]; exists && a.Type == r.Type &&
+				movedPackage(oldPkg, iface.PkgPath) && sameMethods(iface.Methods, oldIface.Methods) {
+				candidates = append(candidates, a)
+			}
+		}
+	default:
+		return "", "", "", false
+	}
+
+	var best []AddedSymbol
+	for _, c := range candidates {
+		if similarNames(r.Name, c.Name) && !newAPI.Ambiguous[c.Name] {
+			best = append(best, c)
+		}
+	}
+	if len(best) != 1 {
+		return "", "", "", false
+	}
+
+	newName = best[0].Name
+	switch r.Type {
+	case "function":
+		newPkg = newAPI.Funcs[newName].PkgPath
+	case "type":
+		newPkg = newAPI.Types[newName].PkgPath
+	case "interface":
+		newPkg = newAPI.Interfaces[newName].PkgPath
+	}
+	return oldPkg, newName, newPkg, true
+}
+
+// similarNames reports whether one name contains the other, ignoring case,
+// such as ParseString and Parse
+func similarNames(a, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	return strings.Contains(a, b) || strings.Contains(b, a)
+}
+
+// sameMethods compares two method sets regardless of order
+func sameMethods(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
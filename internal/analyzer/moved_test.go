@@ -0,0 +1,181 @@
+package analyzer
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffAPIsDetectsMoves(t *testing.T) {
+	oldAPI := emptyAPI()
+	oldAPI.Funcs["Parse"] = &Function{Name: "Parse", Signature: Signature{Text: "func(s string) error"}, PkgPath: "example.com/lib/util"}
+	oldAPI.Funcs["ParseString"] = &Function{Name: "ParseString", Signature: Signature{Text: "func(s string) int"}, PkgPath: "example.com/lib/util"}
+	oldAPI.Types["Config"] = &Type{Name: "Config", Kind: "struct{}", PkgPath: "example.com/lib"}
+	oldAPI.Funcs["Config.Load"] = &Function{Name: "Config.Load", Signature: Signature{Text: "func()"}, PkgPath: "example.com/lib", IsMethod: true}
+	oldAPI.Funcs["Unused"] = &Function{Name: "Unused", Signature: Signature{Text: "func()"}, PkgPath: "example.com/lib/util"}
+
+	newAPI := emptyAPI()
+	newAPI.Funcs["Parse"] = &Function{Name: "Parse", Signature: Signature{Text: "func(s string) error"}, PkgPath: "example.com/lib/parser"}
+	newAPI.Funcs["String"] = &Function{Name: "String", Signature: Signature{Text: "func(s string) int"}, PkgPath: "example.com/lib/parser"}
+	newAPI.Types["Config"] = &Type{Name: "Config", Kind: "struct{}", PkgPath: "example.com/lib/config"}
+	newAPI.Funcs["Config.Load"] = &Function{Name: "Config.Load", Signature: Signature{Text: "func()"}, PkgPath: "example.com/lib/config", IsMethod: true}
+	newAPI.Funcs["Unused"] = &Function{Name: "Unused", Signature: Signature{Text: "func()"}, PkgPath: "example.com/lib/other"}
+
+	usage := &Usage{Symbols: map[string][]Location{
+		"Parse":       {{File: "main.go", Line: 3}},
+		"ParseString": {{File: "main.go", Line: 4}},
+		"Config":      {{File: "main.go", Line: 5}},
+		"Config.Load": {{File: "main.go", Line: 6}},
+	}}
+
+	diff := diffAPIs(oldAPI, newAPI, usage)
+
+	want := []MovedSymbol{
+		{Name: "Config", NewName: "Config", Type: "type", OldPackage: "example.com/lib", NewPackage: "example.com/lib/config", UsedIn: usage.Symbols["Config"]},
+		{Name: "Parse", NewName: "Parse", Type: "function", OldPackage: "example.com/lib/util", NewPackage: "example.com/lib/parser", UsedIn: usage.Symbols["Parse"]},
+		{Name: "ParseString", NewName: "String", Type: "function", OldPackage: "example.com/lib/util", NewPackage: "example.com/lib/parser", UsedIn: usage.Symbols["ParseString"]},
+	}
+	if !reflect.DeepEqual(diff.Moved, want) {
+		t.Fatalf("diffAPIs() Moved = %+v, want %+v", diff.Moved, want)
+	}
+	if len(diff.Removed) != 0 {
+		t.Fatalf("diffAPIs() Removed = %+v, want moves only", diff.Removed)
+	}
+	if len(diff.Added) != 0 {
+		t.Fatalf("diffAPIs() Added = %+v, want String consumed by the move", diff.Added)
+	}
+	if diff.BreakingCount() != 3 {
+		t.Fatalf("diffAPIs() BreakingCount = %d, want 3", diff.BreakingCount())
+	}
+}
+
+func TestDiffAPIsAmbiguousMoveStaysRemoved(t *testing.T) {
+	oldAPI := emptyAPI()
+	oldAPI.Funcs["Parse"] = &Function{Name: "Parse", Signature: Signature{Text: "func()"}, PkgPath: "example.com/lib/util"}
+
+	newAPI := emptyAPI()
+	newAPI.Funcs["ParseJSON"] = &Function{Name: "ParseJSON", Signature: Signature{Text: "func()"}, PkgPath: "example.com/lib/json"}
+	newAPI.Funcs["ParseYAML"] = &Function{Name: "ParseYAML", Signature: Signature{Text: "func()"}, PkgPath: "example.com/lib/yaml"}
+
+	usage := &Usage{Symbols: map[string][]Location{"Parse": {{File: "main.go", Line: 1}}}}
+
+	diff := diffAPIs(oldAPI, newAPI, usage)
+	if len(diff.Moved) != 0 || len(diff.Removed) != 1 {
+		t.Fatalf("diffAPIs() Moved = %+v, Removed = %+v, want one removal", diff.Moved, diff.Removed)
+	}
+	if len(diff.Added) != 2 {
+		t.Fatalf("diffAPIs() Added = %+v, want both candidates kept", diff.Added)
+	}
+}
+
+func TestDiffAPIsNameInSeveralPackagesIsNotMoved(t *testing.T) {
+	oldAPI := emptyAPI()
+	oldAPI.Funcs["New"] = &Function{Name: "New", Signature: Signature{Text: "func() *Client"}, PkgPath: "example.com/lib"}
+
+	// example.com/lib/mock also exports New, and the API happened to keep it
+	newAPI := emptyAPI()
+	newAPI.Funcs["New"] = &Function{Name: "New", Signature: Signature{Text: "func() *Mock"}, PkgPath: "example.com/lib/mock"}
+	newAPI.Ambiguous["New"] = true
+
+	usage := &Usage{Symbols: map[string][]Location{"New": {{File: "main.go", Line: 1}}}}
+
+	diff := diffAPIs(oldAPI, newAPI, usage)
+	if len(diff.Moved) != 0 {
+		t.Fatalf("diffAPIs() Moved = %+v, want no move for an ambiguous name", diff.Moved)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].Name != "New" {
+		t.Fatalf("diffAPIs() Changed = %+v, want the signature change kept", diff.Changed)
+	}
+}
+
+func TestMergeAPIMarksAmbiguousNames(t *testing.T) {
+	a := emptyAPI()
+	a.Funcs["New"] = &Function{Name: "New", PkgPath: "example.com/lib"}
+	a.Types["Client"] = &Type{Name: "Client", PkgPath: "example.com/lib"}
+	b := emptyAPI()
+	b.Types["New"] = &Type{Name: "New", PkgPath: "example.com/lib/mock"}
+	b.Types["Client"] = &Type{Name: "Client", PkgPath: "example.com/lib"}
+	b.Ambiguous["Option"] = true
+
+	merged := emptyAPI()
+	mergeAPI(merged, a)
+	mergeAPI(merged, b)
+
+	want := map[string]bool{"New": true, "Option": true}
+	if !reflect.DeepEqual(merged.Ambiguous, want) {
+		t.Errorf("Ambiguous = %v, want %v", merged.Ambiguous, want)
+	}
+}
+
+func TestSimilarNames(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"ParseString", "String", true},
+		{"Parse", "parse", true},
+		{"Parse", "Format", false},
+	}
+	for _, tt := range tests {
+		if got := similarNames(tt.a, tt.b); got != tt.want {
+			t.Errorf("similarNames(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestLoadModuleAPIFindsMoveIntoSubpackage(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not available")
+	}
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOFLAGS", "-mod=mod")
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "v1.0.0", "go.mod"), "module example.com/lib\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(root, "v1.0.0", "lib.go"), "package lib\n\nconst Version = 1\n")
+	writeFile(t, filepath.Join(root, "v1.0.0", "util", "util.go"), "package util\n\nfunc Parse(s string) error { return nil }\n")
+	writeFile(t, filepath.Join(root, "v1.1.0", "go.mod"), "module example.com/lib\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(root, "v1.1.0", "lib.go"), "package lib\n\nconst Version = 1\n")
+	writeFile(t, filepath.Join(root, "v1.1.0", "parser", "parser.go"), "package parser\n\nfunc Parse(s string) error { return nil }\n")
+	writeFile(t, filepath.Join(root, "v1.1.0", "internal", "scan", "scan.go"), "package scan\n\nfunc Parse(s string) error { return nil }\n")
+
+	resolveVersionsLocally(t, "example.com/lib", root)
+
+	a := &Analyzer{}
+	oldAPI, err := a.loadModuleAPI("example.com/lib", "v1.0.0")
+	if err != nil {
+		t.Fatalf("loadModuleAPI(v1.0.0) error = %v", err)
+	}
+	newAPI, err := a.loadModuleAPI("example.com/lib", "v1.1.0")
+	if err != nil {
+		t.Fatalf("loadModuleAPI(v1.1.0) error = %v", err)
+	}
+	if want := []string{"example.com/lib", "example.com/lib/parser"}; !reflect.DeepEqual(newAPI.Packages, want) {
+		t.Fatalf("loadModuleAPI(v1.1.0) packages = %v, want %v without the internal package", newAPI.Packages, want)
+	}
+
+	usage := &Usage{Symbols: map[string][]Location{"Parse": {{File: "main.go", Line: 3}}}}
+	diff := diffAPIs(oldAPI, newAPI, usage)
+	want := []MovedSymbol{{Name: "Parse", NewName: "Parse", Type: "function", OldPackage: "example.com/lib/util", NewPackage: "example.com/lib/parser", UsedIn: usage.Symbols["Parse"]}}
+	if !reflect.DeepEqual(diff.Moved, want) {
+		t.Fatalf("diffAPIs() Moved = %+v, want %+v", diff.Moved, want)
+	}
+}
+
+// resolveVersionsLocally makes module@version loads use the copy of the module
+// in root/version instead of downloading it
+func resolveVersionsLocally(t *testing.T, module, root string) {
+	t.Helper()
+	orig := runGoEnv
+	runGoEnv = func(dir string, env []string, args ...string) ([]byte, error) {
+		last := args[len(args)-1]
+		version := last[strings.LastIndex(last, "@")+1:]
+		modFile := "module " + isolatedModulePath + "\n\ngo 1.21\n\nrequire " + module + " " + version +
+			"\n\nreplace " + module + " => " + filepath.Join(root, version) + "\n"
+		return nil, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(modFile), 0o644)
+	}
+	t.Cleanup(func() { runGoEnv = orig })
+}
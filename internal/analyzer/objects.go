@@ -0,0 +1,40 @@
+package analyzer
+
+import (
+	"go/token"
+	"go/types"
+)
+
+// The accessors below expose the go/types objects an API was extracted
+// from, for embedders running their own analyses on top of the diff. They
+// return nil for APIs read from snapshots, loaded in worker processes (see
+// Options.Shards), or built by hand.
+//
+// An object keeps the type information of its whole module version alive
+// for as long as it is referenced, so hold on to objects only while they
+// are needed. Objects belong to the load that produced them: objects of two
+// loads, even of the same version, are never identical, and types from the
+// old and new API must be compared structurally rather than with ==. The
+// objects are shared with the analyzer and its cache and must not be
+// modified.
+
+// Object returns the type-checked declaration of the function or method
+func (f *Function) Object() *types.Func {
+	return f.obj
+}
+
+// Object returns the type-checked declaration of the type
+func (t *Type) Object() *types.TypeName {
+	return t.obj
+}
+
+// Object returns the type-checked declaration of the interface
+func (i *Interface) Object() *types.TypeName {
+	return i.obj
+}
+
+// FileSet returns the file set resolving the positions of the API's
+// objects, or nil when it has none
+func (api *API) FileSet() *token.FileSet {
+	return api.fset
+}
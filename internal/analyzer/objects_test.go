@@ -0,0 +1,52 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestAPIObjects(t *testing.T) {
+	pkg := checkSource(t, "example.com/lib", `package lib
+
+type Client struct{}
+
+func (c *Client) Do() error { return nil }
+
+type Handler interface{ Serve() }
+
+func Parse(s string) (int, error) { return 0, nil }
+`, nil)
+	api := extractAPI([]*packages.Package{pkg})
+
+	parse := api.Funcs["Parse"].Object()
+	if parse == nil || parse.Name() != "Parse" || parse.Pkg().Path() != "example.com/lib" {
+		t.Fatalf("Parse object = %v", parse)
+	}
+	if pos := api.FileSet().Position(parse.Pos()); pos.Line != 9 {
+		t.Errorf("Parse position = %v, want line 9", pos)
+	}
+	if m := api.Funcs["Client.Do"].Object(); m == nil || m.Name() != "Do" {
+		t.Errorf("Client.Do object = %v", m)
+	}
+	if tn := api.Types["Client"].Object(); tn == nil || tn.Name() != "Client" {
+		t.Errorf("Client object = %v", tn)
+	}
+	if tn := api.Interfaces["Handler"].Object(); tn == nil || tn.Name() != "Handler" {
+		t.Errorf("Handler object = %v", tn)
+	}
+
+	// Snapshots only carry the strings
+	data, err := json.Marshal(api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded API
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Funcs["Parse"].Object() != nil || decoded.FileSet() != nil {
+		t.Error("decoded snapshot should have no objects")
+	}
+}
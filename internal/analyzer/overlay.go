@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// upgradeOverlay is a scratch copy of the project's go.mod and go.sum with the
+// upgrade applied. Go commands run with -modfile pointing at it, so builds and
+// tests see the new version while the project's own files stay untouched.
+type upgradeOverlay struct {
+	dir     string
+	modFile string
+}
+
+// newUpgradeOverlay prepares an overlay module file requiring module@version
+func (a *Analyzer) newUpgradeOverlay(module, version string) (*upgradeOverlay, error) {
+	f, err := a.projectModFile()
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "go-semver-audit-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create overlay directory: %w", err)
+	}
+	o := &upgradeOverlay{dir: dir, modFile: filepath.Join(dir, "go.mod")}
+
+	if err := copyFile(f.Syntax.Name, o.modFile); err != nil {
+		o.Close()
+		return nil, err
+	}
+	// go.sum is optional; the go command derives its location from -modfile
+	sumFile := filepath.Join(filepath.Dir(f.Syntax.Name), "go.sum")
+	if err := copyFile(sumFile, filepath.Join(dir, "go.sum")); err != nil && !os.IsNotExist(err) {
+		o.Close()
+		return nil, err
+	}
+
+	if _, err := runGoCommand(a.projectPath, "get", o.flag(), module+"@"+version); err != nil {
+		o.Close()
+		return nil, fmt.Errorf("failed to apply %s@%s to overlay: %w", module, version, err)
+	}
+
+	return o, nil
+}
+
+// flag returns the go command flag that selects the overlay module file
+func (o *upgradeOverlay) flag() string {
+	return "-modfile=" + o.modFile
+}
+
+// Close removes the overlay files
+func (o *upgradeOverlay) Close() error {
+	return os.RemoveAll(o.dir)
+}
+
+// copyFile copies src to dst, preserving os.IsNotExist errors for missing sources
+func copyFile(src, dst string) error {
+	data, err := readFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0o644)
+}
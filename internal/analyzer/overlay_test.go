@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewUpgradeOverlay(t *testing.T) {
+	project := writeProject(t, "module example.com/app\n\ngo 1.21\n\nrequire example.com/lib v1.0.0\n")
+
+	var gotArgs []string
+	restore := mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+		gotArgs = args
+		return nil, nil
+	})
+	defer restore()
+
+	a := &Analyzer{projectPath: project}
+	o, err := a.newUpgradeOverlay("example.com/lib", "v2.0.0")
+	if err != nil {
+		t.Fatalf("newUpgradeOverlay() error = %v", err)
+	}
+
+	data, err := os.ReadFile(o.modFile)
+	if err != nil {
+		t.Fatalf("overlay go.mod missing: %v", err)
+	}
+	if !strings.Contains(string(data), "example.com/lib v1.0.0") {
+		t.Fatalf("overlay go.mod = %q, want copy of project go.mod", data)
+	}
+	if _, err := os.Stat(filepath.Join(o.dir, "go.sum")); err != nil {
+		t.Fatalf("overlay go.sum missing: %v", err)
+	}
+
+	want := "get " + o.flag() + " example.com/lib@v2.0.0"
+	if strings.Join(gotArgs, " ") != want {
+		t.Fatalf("overlay go command = %v, want %q", gotArgs, want)
+	}
+
+	if err := o.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := os.Stat(o.dir); !os.IsNotExist(err) {
+		t.Fatalf("Close() did not remove overlay directory")
+	}
+
+	// The project's own files must be untouched
+	orig, _ := os.ReadFile(filepath.Join(project, "go.mod"))
+	if !strings.Contains(string(orig), "example.com/lib v1.0.0") {
+		t.Fatalf("project go.mod was modified: %q", orig)
+	}
+}
+
+// writeProject creates a temporary project with the given go.mod and an empty go.sum
+func writeProject(t *testing.T, gomod string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.sum"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
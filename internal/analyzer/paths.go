@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// caseInsensitivePaths reports whether file paths name the same file
+// whatever their case, as on Windows. Allow overriding in tests.
+var caseInsensitivePaths = runtime.GOOS == "windows"
+
+// cleanPath returns the canonical spelling of a file path: cleaned, with the
+// separators of the platform and an upper-case drive letter, so the same file
+// reported by different loads, or given by the user, reads the same
+func cleanPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	path = filepath.Clean(filepath.FromSlash(path))
+	if vol := filepath.VolumeName(path); len(vol) == 2 && vol[1] == ':' {
+		path = strings.ToUpper(vol) + path[2:]
+	}
+	return path
+}
+
+// pathKey maps a file path to the key it is compared and cached by: its
+// canonical spelling, case-folded where the file system ignores case
+func pathKey(path string) string {
+	path = cleanPath(path)
+	if caseInsensitivePaths {
+		return strings.ToLower(path)
+	}
+	return path
+}
+
+// samePath reports whether two paths name the same file
+func samePath(a, b string) bool {
+	return pathKey(a) == pathKey(b)
+}
+
+// relPath returns target relative to base, and false when target is not
+// inside base. Unlike filepath.Rel, it ignores case where the file system
+// does and does not take a sibling named like "..vendor" for a parent.
+func relPath(base, target string) (string, bool) {
+	rel, err := filepath.Rel(pathKey(base), pathKey(target))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	// Keep the caller's spelling of the part below base
+	if target = cleanPath(target); rel != "." && len(rel) <= len(target) && strings.EqualFold(target[len(target)-len(rel):], rel) {
+		rel = target[len(target)-len(rel):]
+	}
+	return rel, true
+}
+
+// locationKey identifies a location for deduplication, whatever spelling of
+// its file it was reported with
+func locationKey(loc Location) Location {
+	loc.File = pathKey(loc.File)
+	return loc
+}
+
+// dedupeLocations spells every location's file canonically and drops
+// locations reported more than once, such as the same file reached through
+// paths differing in case on Windows
+func dedupeLocations(locations []Location) []Location {
+	seen := make(map[Location]bool, len(locations))
+	unique := locations[:0]
+	for _, loc := range locations {
+		loc.File = cleanPath(loc.File)
+		if key := locationKey(loc); !seen[key] {
+			seen[key] = true
+			unique = append(unique, loc)
+		}
+	}
+	return unique
+}
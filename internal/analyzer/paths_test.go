@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// caseInsensitive makes paths compare as on Windows for the rest of the test
+func caseInsensitive(t *testing.T) {
+	orig := caseInsensitivePaths
+	caseInsensitivePaths = true
+	t.Cleanup(func() { caseInsensitivePaths = orig })
+}
+
+func TestRelPath(t *testing.T) {
+	root := filepath.FromSlash("/src/App")
+	tests := []struct {
+		name            string
+		target          string
+		caseInsensitive bool
+		want            string
+		wantOK          bool
+	}{
+		{"inside", "/src/App/pkg/a.go", false, "pkg/a.go", true},
+		{"root itself", "/src/App/", false, ".", true},
+		{"outside", "/src/other/a.go", false, "", false},
+		{"dot-dot sibling is inside", "/src/App/..vendor/a.go", false, "..vendor/a.go", true},
+		{"case differs", "/src/app/Pkg/a.go", false, "", false},
+		{"case differs on a case-insensitive file system", "/src/app/Pkg/a.go", true, "Pkg/a.go", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.caseInsensitive {
+				caseInsensitive(t)
+			}
+			got, ok := relPath(root, filepath.FromSlash(tt.target))
+			if filepath.ToSlash(got) != tt.want || ok != tt.wantOK {
+				t.Errorf("relPath(%q, %q) = %q, %v, want %q, %v", root, tt.target, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestDedupeLocations(t *testing.T) {
+	caseInsensitive(t)
+	a := filepath.FromSlash("/src/app/main.go")
+	locations := []Location{
+		{File: a, Line: 3, Column: 2},
+		{File: filepath.FromSlash("/src/App/./main.go"), Line: 3, Column: 2},
+		{File: a, Line: 4, Column: 2},
+	}
+	want := []Location{{File: a, Line: 3, Column: 2}, {File: a, Line: 4, Column: 2}}
+	if got := dedupeLocations(locations); !reflect.DeepEqual(got, want) {
+		t.Errorf("dedupeLocations() = %v, want %v", got, want)
+	}
+}
+
+func TestCacheProjectIgnoresPathCase(t *testing.T) {
+	caseInsensitive(t)
+	c := NewCache()
+	c.storeProject(filepath.FromSlash("/src/App"), "fp", nil)
+	if _, ok := c.project(filepath.FromSlash("/src/app/"), "fp"); !ok {
+		t.Error("project() missed a project stored under another spelling of its path")
+	}
+}
@@ -0,0 +1,32 @@
+package analyzer
+
+import "testing"
+
+func TestCleanPath_Windows(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{`c:\src\app\main.go`, `C:\src\app\main.go`},
+		{`C:/src/app/./pkg/../main.go`, `C:\src\app\main.go`},
+		{`\\server\share\app\main.go`, `\\server\share\app\main.go`},
+		{``, ``},
+	}
+	for _, tt := range tests {
+		if got := cleanPath(tt.path); got != tt.want {
+			t.Errorf("cleanPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRelPath_WindowsDrives(t *testing.T) {
+	if rel, ok := relPath(`c:\src\App`, `C:\SRC\app\pkg\a.go`); !ok || rel != `pkg\a.go` {
+		t.Errorf("relPath() = %q, %v, want pkg\\a.go inside the project", rel, ok)
+	}
+	if _, ok := relPath(`C:\src\app`, `D:\src\app\a.go`); ok {
+		t.Error("relPath() should not relate files on another drive")
+	}
+	if !samePath(`c:/src/app/main.go`, `C:\SRC\App\main.go`) {
+		t.Error("samePath() should ignore case, separators, and drive letter case")
+	}
+}
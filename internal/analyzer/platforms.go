@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParsePlatforms splits a comma-separated list of GOOS/GOARCH pairs, such as
+// "linux/amd64,windows/amd64", dropping duplicates
+func ParsePlatforms(list string) ([]string, error) {
+	var platforms []string
+	seen := make(map[string]bool)
+	for _, platform := range strings.Split(list, ",") {
+		platform = strings.TrimSpace(platform)
+		if platform == "" || seen[platform] {
+			continue
+		}
+		seen[platform] = true
+		platforms = append(platforms, platform)
+	}
+	return platforms, ValidatePlatforms(platforms)
+}
+
+// ValidatePlatforms checks that every platform is a GOOS/GOARCH pair
+func ValidatePlatforms(platforms []string) error {
+	for _, platform := range platforms {
+		goos, goarch, ok := strings.Cut(platform, "/")
+		if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+			return fmt.Errorf("invalid platform %q (expected GOOS/GOARCH, such as linux/amd64)", platform)
+		}
+	}
+	return nil
+}
+
+// platformEnv targets loads at platform, a GOOS/GOARCH pair; an empty
+// platform leaves env as is and loads for the host
+func platformEnv(env []string, platform string) []string {
+	if platform == "" {
+		return env
+	}
+	goos, goarch, _ := strings.Cut(platform, "/")
+	return append(env, "GOOS="+goos, "GOARCH="+goarch)
+}
+
+// diffPlatforms diffs the module's API as built for each of Options.Platforms.
+// A finding that only occurs for some platforms, such as a symbol the new
+// version only declares under //go:build linux, lists them in its Platforms
+// field. The project's usage is still taken from its host build.
+func (a *Analyzer) diffPlatforms(upgrade *Upgrade, newDependency bool, usage *Usage) (*Diff, error) {
+	diffs := make([]*Diff, 0, len(a.opts.Platforms))
+	for _, platform := range a.opts.Platforms {
+		pa := *a
+		pa.platform = platform
+
+		oldAPI, _, err := pa.loadOldAPI(upgrade, newDependency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load old API for %s: %w", platform, err)
+		}
+		newAPI, err := pa.loadModuleAPI(upgrade.Module, upgrade.NewVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load new API for %s: %w", platform, err)
+		}
+		a.warnings = pa.warnings
+
+		diffs = append(diffs, diffAPIs(oldAPI, newAPI, usage))
+	}
+	return mergePlatformDiffs(a.opts.Platforms, diffs), nil
+}
+
+// mergePlatformDiffs unions the diffs of several platforms, in the same order
+func mergePlatformDiffs(platforms []string, diffs []*Diff) *Diff {
+	merged := &Diff{
+		Removed: mergePlatformFindings(platforms, diffs,
+			func(d *Diff) []RemovedSymbol { return d.Removed },
+			func(r RemovedSymbol) string { return r.Name + "|" + r.Type + "|" + r.Package },
+			func(r *RemovedSymbol, p []string) { r.Platforms = p }),
+		Changed: mergePlatformFindings(platforms, diffs,
+			func(d *Diff) []ChangedSignature { return d.Changed },
+			func(c ChangedSignature) string { return c.Name + "|" + c.OldSignature + "|" + c.NewSignature },
+			func(c *ChangedSignature, p []string) { c.Platforms = p }),
+		InterfaceChanges: mergePlatformFindings(platforms, diffs,
+			func(d *Diff) []InterfaceChange { return d.InterfaceChanges },
+			func(ic InterfaceChange) string {
+				return strings.Join([]string{ic.Name, strings.Join(ic.AddedMethods, ","), strings.Join(ic.RemovedMethods, ","), strings.Join(ic.ChangedMethods, ",")}, "|")
+			},
+			func(ic *InterfaceChange, p []string) { ic.Platforms = p }),
+		Moved: mergePlatformFindings(platforms, diffs,
+			func(d *Diff) []MovedSymbol { return d.Moved },
+			func(m MovedSymbol) string { return m.Name + "|" + m.NewPackage + "|" + m.NewName },
+			func(m *MovedSymbol, p []string) { m.Platforms = p }),
+		// Additions are informational, so they are not qualified
+		Added: mergePlatformFindings(platforms, diffs,
+			func(d *Diff) []AddedSymbol { return d.Added },
+			func(s AddedSymbol) string { return s.Name + "|" + s.Type + "|" + s.Package },
+			func(*AddedSymbol, []string) {}),
+	}
+	if len(diffs) > 0 {
+		merged.Generated = diffs[0].Generated
+	}
+	sortFindings(merged)
+	return merged
+}
+
+// mergePlatformFindings collects the findings of every platform once, keyed
+// by key, and qualifies those missing from some platforms with the platforms
+// they were found for
+func mergePlatformFindings[T any](platforms []string, diffs []*Diff, findings func(*Diff) []T, key func(T) string, qualify func(*T, []string)) []T {
+	var merged []T
+	index := make(map[string]int)
+	found := make(map[string][]string)
+	for i, d := range diffs {
+		for _, f := range findings(d) {
+			k := key(f)
+			if _, ok := index[k]; !ok {
+				index[k] = len(merged)
+				merged = append(merged, f)
+			}
+			found[k] = append(found[k], platforms[i])
+		}
+	}
+	for k, i := range index {
+		if len(found[k]) < len(platforms) {
+			qualify(&merged[i], found[k])
+		}
+	}
+	if merged == nil {
+		merged = []T{}
+	}
+	return merged
+}
@@ -0,0 +1,71 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestParsePlatforms(t *testing.T) {
+	got, err := ParsePlatforms(" linux/amd64, windows/amd64,linux/amd64,")
+	if err != nil || !reflect.DeepEqual(got, []string{"linux/amd64", "windows/amd64"}) {
+		t.Errorf("ParsePlatforms() = %v, %v", got, err)
+	}
+	for _, invalid := range []string{"linux", "linux/", "/amd64", "linux/amd64/v3"} {
+		if _, err := ParsePlatforms(invalid); err == nil {
+			t.Errorf("ParsePlatforms(%q) expected an error", invalid)
+		}
+	}
+	if got, err := ParsePlatforms(""); err != nil || got != nil {
+		t.Errorf("ParsePlatforms(\"\") = %v, %v, want none", got, err)
+	}
+}
+
+func TestDiffPlatforms(t *testing.T) {
+	// The new version only declares Watch on Linux, and changes Open everywhere
+	restore := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		goos := ""
+		for _, kv := range cfg.Env {
+			if strings.HasPrefix(kv, "GOOS=") {
+				goos = strings.TrimPrefix(kv, "GOOS=")
+			}
+		}
+		src := "package lib\n\nfunc Watch() {}\n\nfunc Open(name string) {}\n"
+		if strings.HasSuffix(patterns[0], "@v2.0.0") {
+			src = "package lib\n\nfunc Open(name string, flags int) {}\n"
+			if goos == "linux" {
+				src += "\nfunc Watch() {}\n"
+			}
+		}
+		return []*packages.Package{checkSource(t, "example.com/lib", src, nil)}, nil
+	})
+	defer restore()
+
+	a := &Analyzer{projectPath: t.TempDir(), opts: Options{Platforms: []string{"linux/amd64", "windows/amd64"}}}
+	usage := &Usage{Symbols: map[string][]Location{
+		"Watch": {{File: "main.go", Line: 3}},
+		"Open":  {{File: "main.go", Line: 4}},
+	}}
+	diff, err := a.diffPlatforms(&Upgrade{Module: "example.com/lib", OldVersion: "v1.0.0", NewVersion: "v2.0.0"}, false, usage)
+	if err != nil {
+		t.Fatalf("diffPlatforms() error = %v", err)
+	}
+
+	if len(diff.Removed) != 1 || diff.Removed[0].Name != "Watch" || !reflect.DeepEqual(diff.Removed[0].Platforms, []string{"windows/amd64"}) {
+		t.Errorf("Removed = %+v, want Watch only on windows/amd64", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].Name != "Open" || diff.Changed[0].Platforms != nil {
+		t.Errorf("Changed = %+v, want Open on every platform", diff.Changed)
+	}
+}
+
+func TestPlatformEnv(t *testing.T) {
+	if got := platformEnv([]string{"A=1"}, ""); !reflect.DeepEqual(got, []string{"A=1"}) {
+		t.Errorf("platformEnv(host) = %v", got)
+	}
+	if got := platformEnv([]string{"A=1"}, "windows/arm64"); !reflect.DeepEqual(got, []string{"A=1", "GOOS=windows", "GOARCH=arm64"}) {
+		t.Errorf("platformEnv(windows/arm64) = %v", got)
+	}
+}
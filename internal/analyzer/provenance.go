@@ -0,0 +1,101 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Provenance records the exact inputs of an analysis: the toolchain, its
+// environment, the checksums of both module versions, and the options used.
+// Two runs with equal provenance analyze identical inputs.
+type Provenance struct {
+	ToolVersion string // filled in by the CLI
+	GoVersion   string
+	GOFLAGS     string
+	GOOS        string
+	GOARCH      string
+	Module      string
+	OldVersion  string
+	NewVersion  string
+	OldSum      string // go.sum h1: hash of the module zip, empty for a new dependency
+	NewSum      string
+	Options     Options
+}
+
+// RecordProvenance captures the environment and module checksums of an
+// upgrade without analyzing it, such as to check that a recorded run can be
+// reproduced before repeating it. OldVersion must be set, or empty for a new
+// dependency.
+func (a *Analyzer) RecordProvenance(upgrade *Upgrade) (*Provenance, error) {
+	return a.call().recordProvenance(upgrade, upgrade.OldVersion == "")
+}
+
+// recordProvenance captures the environment and module checksums of this run
+func (a *Analyzer) recordProvenance(upgrade *Upgrade, newDependency bool) (*Provenance, error) {
+	out, err := runGoCommand(a.projectPath, "env", "-json", "GOVERSION", "GOFLAGS", "GOOS", "GOARCH")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go env: %w", err)
+	}
+	var env map[string]string
+	if err := json.Unmarshal(out, &env); err != nil {
+		return nil, fmt.Errorf("failed to parse go env: %w", err)
+	}
+
+	opts := a.opts
+	opts.Cache = nil
+	opts.Provenance = false
+
+	p := &Provenance{
+		GoVersion:  env["GOVERSION"],
+		GOFLAGS:    env["GOFLAGS"],
+		GOOS:       env["GOOS"],
+		GOARCH:     env["GOARCH"],
+		Module:     upgrade.Module,
+		OldVersion: upgrade.OldVersion,
+		NewVersion: upgrade.NewVersion,
+		Options:    opts,
+	}
+
+	if !newDependency {
+		info, err := a.downloadModule(upgrade.Module, upgrade.OldVersion)
+		if err != nil {
+			return nil, err
+		}
+		p.OldSum = info.Sum
+	}
+	info, err := a.downloadModule(upgrade.Module, upgrade.NewVersion)
+	if err != nil {
+		return nil, err
+	}
+	p.NewSum = info.Sum
+
+	return p, nil
+}
+
+// Mismatches lists every input that differs between a recorded provenance and
+// this one, as "field: recorded != actual". An empty list means the run
+// reproduced the recorded inputs.
+func (p *Provenance) Mismatches(recorded *Provenance) []string {
+	fields := map[string][2]string{
+		"tool version": {recorded.ToolVersion, p.ToolVersion},
+		"go version":   {recorded.GoVersion, p.GoVersion},
+		"GOFLAGS":      {recorded.GOFLAGS, p.GOFLAGS},
+		"GOOS":         {recorded.GOOS, p.GOOS},
+		"GOARCH":       {recorded.GOARCH, p.GOARCH},
+		"module":       {recorded.Module, p.Module},
+		"old version":  {recorded.OldVersion, p.OldVersion},
+		"new version":  {recorded.NewVersion, p.NewVersion},
+		"old sum":      {recorded.OldSum, p.OldSum},
+		"new sum":      {recorded.NewSum, p.NewSum},
+	}
+
+	var mismatches []string
+	for name, v := range fields {
+		if v[0] != v[1] {
+			mismatches = append(mismatches, fmt.Sprintf("%s: %q != %q", name, v[0], v[1]))
+		}
+	}
+	sort.Strings(mismatches)
+	return mismatches
+}
@@ -0,0 +1,71 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecordProvenance(t *testing.T) {
+	restore := mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+		switch strings.Join(args[:2], " ") {
+		case "env -json":
+			return []byte(`{"GOVERSION":"go1.21.13","GOFLAGS":"-mod=mod","GOOS":"linux","GOARCH":"amd64"}`), nil
+		case "mod download":
+			target := args[len(args)-1]
+			return []byte(fmt.Sprintf(`{"Path":"example.com/lib","Sum":"h1:%s="}`, target)), nil
+		}
+		return nil, fmt.Errorf("unexpected command %v", args)
+	})
+	defer restore()
+
+	a := &Analyzer{projectPath: ".", opts: Options{Footprint: true, Provenance: true, Cache: NewCache()}}
+	upgrade := &Upgrade{Module: "example.com/lib", OldVersion: "v1.0.0", NewVersion: "v1.1.0"}
+
+	got, err := a.recordProvenance(upgrade, false)
+	if err != nil {
+		t.Fatalf("recordProvenance() error = %v", err)
+	}
+	want := &Provenance{
+		GoVersion:  "go1.21.13",
+		GOFLAGS:    "-mod=mod",
+		GOOS:       "linux",
+		GOARCH:     "amd64",
+		Module:     "example.com/lib",
+		OldVersion: "v1.0.0",
+		NewVersion: "v1.1.0",
+		OldSum:     "h1:example.com/lib@v1.0.0=",
+		NewSum:     "h1:example.com/lib@v1.1.0=",
+		Options:    Options{Footprint: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("recordProvenance() = %+v, want %+v", got, want)
+	}
+
+	// A new dependency has no old version to checksum
+	got, err = a.recordProvenance(&Upgrade{Module: "example.com/lib", NewVersion: "v1.1.0"}, true)
+	if err != nil {
+		t.Fatalf("recordProvenance() error = %v", err)
+	}
+	if got.OldSum != "" || got.NewSum == "" {
+		t.Errorf("recordProvenance() sums = %q, %q, want only a new sum", got.OldSum, got.NewSum)
+	}
+}
+
+func TestProvenanceMismatches(t *testing.T) {
+	recorded := &Provenance{GoVersion: "go1.21.13", Module: "example.com/lib", NewVersion: "v1.1.0", NewSum: "h1:a="}
+
+	same := *recorded
+	if got := same.Mismatches(recorded); len(got) != 0 {
+		t.Errorf("Mismatches() = %v, want none", got)
+	}
+
+	changed := *recorded
+	changed.GoVersion = "go1.22.0"
+	changed.NewSum = "h1:b="
+	want := []string{`go version: "go1.21.13" != "go1.22.0"`, `new sum: "h1:a=" != "h1:b="`}
+	if got := changed.Mismatches(recorded); !reflect.DeepEqual(got, want) {
+		t.Errorf("Mismatches() = %v, want %v", got, want)
+	}
+}
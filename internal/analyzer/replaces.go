@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ReplaceDrift describes how far the target of a replace directive, usually a
+// fork, has diverged from a newer version of the module it replaces
+type ReplaceDrift struct {
+	Module          string   // replaced module
+	Fork            string   // replacement module path or local directory
+	ForkVersion     string   // empty for local directory replacements
+	UpstreamVersion string   // upstream version the fork is compared with
+	Missing         []string // upstream symbols the fork lacks
+	ForkOnly        []string // symbols only the fork has
+	Changed         []string // symbols whose signatures differ
+	Shared          int      // symbols identical in both
+	Error           string   // set when either side could not be loaded
+}
+
+// Divergence is the share of symbols that differ between fork and upstream,
+// from 0 (identical APIs) to 1 (nothing in common)
+func (d *ReplaceDrift) Divergence() float64 {
+	differing := len(d.Missing) + len(d.ForkOnly) + len(d.Changed)
+	total := differing + d.Shared
+	if total == 0 {
+		return 0
+	}
+	return float64(differing) / float64(total)
+}
+
+// AuditReplaces compares the target of every replace directive in the
+// project's go.mod with the upstream module it stands in for. upstream maps
+// modules to the version to compare with; other modules use their latest
+// version. A replacement that fails to load is reported with Error set
+// rather than aborting the audit.
+func (a *Analyzer) AuditReplaces(upstream map[string]string) ([]ReplaceDrift, error) {
+	a = a.call()
+	f, err := a.projectModFile()
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []ReplaceDrift
+	for _, rep := range f.Replace {
+		drift := ReplaceDrift{
+			Module:          rep.Old.Path,
+			Fork:            rep.New.Path,
+			ForkVersion:     rep.New.Version,
+			UpstreamVersion: upstream[rep.Old.Path],
+		}
+		if err := a.measureDrift(&drift, filepath.Dir(f.Syntax.Name)); err != nil {
+			drift.Error = err.Error()
+		}
+		drifts = append(drifts, drift)
+	}
+
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].Module < drifts[j].Module })
+	return drifts, nil
+}
+
+// measureDrift loads both sides of a replacement and fills in their differences.
+// Local directory replacements are resolved against modDir.
+func (a *Analyzer) measureDrift(drift *ReplaceDrift, modDir string) error {
+	if drift.UpstreamVersion == "" {
+		info, err := a.downloadModule(drift.Module, "latest")
+		if err != nil {
+			return err
+		}
+		drift.UpstreamVersion = info.Version
+	}
+	upstreamAPI, err := a.loadModuleAPI(drift.Module, drift.UpstreamVersion)
+	if err != nil {
+		return fmt.Errorf("failed to load upstream API: %w", err)
+	}
+
+	var forkAPI *API
+	if drift.ForkVersion == "" {
+		dir := drift.Fork
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(modDir, dir)
+		}
+		forkAPI, err = a.loadDirAPI(dir)
+	} else {
+		forkAPI, err = a.loadModuleAPI(drift.Fork, drift.ForkVersion)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load replacement API: %w", err)
+	}
+
+	// Signatures name the fork's own types by its module path
+	forkSymbols, upstreamSymbols := apiSymbols(forkAPI), apiSymbols(upstreamAPI)
+	if drift.ForkVersion != "" && drift.Fork != drift.Module {
+		for name, sig := range forkSymbols {
+			forkSymbols[name] = strings.ReplaceAll(sig, drift.Fork, drift.Module)
+		}
+	}
+	for name, sig := range upstreamSymbols {
+		forkSig, ok := forkSymbols[name]
+		switch {
+		case !ok:
+			drift.Missing = append(drift.Missing, name)
+		case forkSig != sig:
+			drift.Changed = append(drift.Changed, name)
+		default:
+			drift.Shared++
+		}
+	}
+	for name := range forkSymbols {
+		if _, ok := upstreamSymbols[name]; !ok {
+			drift.ForkOnly = append(drift.ForkOnly, name)
+		}
+	}
+	sort.Strings(drift.Missing)
+	sort.Strings(drift.ForkOnly)
+	sort.Strings(drift.Changed)
+	return nil
+}
+
+// apiSymbols flattens an API into symbol names and a comparable description.
+// Symbols are keyed by name alone, since a fork lives under a different module path.
+func apiSymbols(api *API) map[string]string {
+	symbols := make(map[string]string)
+	for name, fn := range api.Funcs {
+		symbols[name] = fn.Signature.Text
+	}
+	for name, typ := range api.Types {
+		symbols[name] = typ.Kind
+	}
+	for name, iface := range api.Interfaces {
+		methods := append([]string(nil), iface.Methods...)
+		sort.Strings(methods)
+		symbols[name] = "interface{" + strings.Join(methods, "; ") + "}"
+	}
+	return symbols
+}
@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestAuditReplaces(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), `module example.com/app
+
+go 1.21
+
+require (
+	example.com/lib v1.0.0
+	example.com/util v1.0.0
+)
+
+replace example.com/lib => github.com/me/lib v1.0.1-fork
+
+replace example.com/util => ./third_party/util
+`)
+
+	upstream := checkSource(t, "example.com/lib", `package lib
+
+type Client struct{}
+
+func New() *Client { return nil }
+
+func (c *Client) Do() error { return nil }
+
+func Retry() {}
+`, nil)
+	fork := checkSource(t, "github.com/me/lib", `package lib
+
+type Client struct{}
+
+func New() *Client { return nil }
+
+func (c *Client) Do() {}
+
+func Patched() {}
+`, nil)
+
+	restoreCmd := mockGoCommand(func(_ string, args ...string) ([]byte, error) {
+		return json.Marshal(moduleDownload{Path: "example.com/lib", Version: "v1.4.0"})
+	})
+	defer restoreCmd()
+	var loaded []string
+	restoreLoad := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		loaded = append(loaded, strings.Join(patterns, " "))
+		if strings.HasPrefix(patterns[0], "github.com/me/lib/...@") {
+			return []*packages.Package{fork}, nil
+		}
+		return []*packages.Package{upstream}, nil
+	})
+	defer restoreLoad()
+
+	a := &Analyzer{projectPath: dir}
+	drifts, err := a.AuditReplaces(map[string]string{"example.com/util": "v1.2.0"})
+	if err != nil {
+		t.Fatalf("AuditReplaces() error = %v", err)
+	}
+	if len(drifts) != 2 {
+		t.Fatalf("AuditReplaces() = %+v, want 2 replacements", drifts)
+	}
+
+	lib := drifts[0]
+	if lib.Module != "example.com/lib" || lib.Fork != "github.com/me/lib" || lib.UpstreamVersion != "v1.4.0" || lib.Error != "" {
+		t.Errorf("lib drift = %+v", lib)
+	}
+	if !reflect.DeepEqual(lib.Missing, []string{"Retry"}) || !reflect.DeepEqual(lib.ForkOnly, []string{"Patched"}) ||
+		!reflect.DeepEqual(lib.Changed, []string{"Client.Do"}) || lib.Shared != 2 {
+		t.Errorf("lib drift = missing %v, fork-only %v, changed %v, shared %d", lib.Missing, lib.ForkOnly, lib.Changed, lib.Shared)
+	}
+	if got := lib.Divergence(); got != 0.6 {
+		t.Errorf("Divergence() = %v, want 0.6", got)
+	}
+
+	util := drifts[1]
+	if util.Module != "example.com/util" || util.UpstreamVersion != "v1.2.0" || util.ForkVersion != "" {
+		t.Errorf("util drift = %+v", util)
+	}
+	if want := "example.com/util/...@v1.2.0"; loaded[len(loaded)-2] != want {
+		t.Errorf("loaded %v, want upstream %s before the local replacement", loaded, want)
+	}
+}
@@ -0,0 +1,54 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ResultVersion is the version of the saved result format written by
+// SaveResult. LoadResult reads files of this version and older.
+const ResultVersion = 1
+
+// resultFormat identifies saved result files, which are otherwise easy to
+// confuse with JSON reports and API snapshots
+const resultFormat = "go-semver-audit/result"
+
+// savedResult is the envelope of a saved result file
+type savedResult struct {
+	Format  string  `json:"format"`
+	Version int     `json:"version"`
+	Result  *Result `json:"result"`
+}
+
+// SaveResult writes a result to path with every field intact, so it can be
+// loaded later and rendered in any report format without analyzing again
+func SaveResult(path string, result *Result) error {
+	data, err := json.MarshalIndent(savedResult{Format: resultFormat, Version: ResultVersion, Result: result}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to save result: %w", err)
+	}
+	return nil
+}
+
+// LoadResult reads a result written by SaveResult
+func LoadResult(path string) (*Result, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read result: %w", err)
+	}
+	var saved savedResult
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse result %s: %w", path, err)
+	}
+	switch {
+	case saved.Format != resultFormat || saved.Result == nil:
+		return nil, fmt.Errorf("%s is not a saved result (write one with -save)", path)
+	case saved.Version > ResultVersion:
+		return nil, fmt.Errorf("%s was saved in result format %d, newer than the supported %d", path, saved.Version, ResultVersion)
+	}
+	return saved.Result, nil
+}
@@ -0,0 +1,83 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSaveLoadResult(t *testing.T) {
+	result := &Result{
+		Module:     "example.com/lib",
+		OldVersion: "v1.0.0",
+		NewVersion: "v2.0.0",
+		Changes: &Diff{
+			Removed: []RemovedSymbol{{
+				Name:       "Close",
+				Type:       "function",
+				UsedIn:     []Location{{File: "main.go", Line: 3, Column: 2, Kind: UsageCall}},
+				Severity:   SeverityWarning,
+				Conversion: &Conversion{NewName: "Conn.Close", ToMethod: true, Rewrite: "lib.Close(c) -> c.Close()"},
+			}},
+			Added: []AddedSymbol{{Name: "Dial", Type: "function", Package: "example.com/lib"}},
+			Changed: []ChangedSignature{{
+				Name:           "Set",
+				OldSignature:   "type Set[T any]",
+				NewSignature:   "type Set[T comparable]",
+				Instantiations: []Instantiation{{TypeArgs: []string{"[]byte"}, Error: "[]byte does not satisfy comparable"}},
+			}},
+			InterfaceChanges: []InterfaceChange{{
+				Name:            "Handler",
+				AddedMethods:    []string{"func (example.com/lib.Handler).Close() error"},
+				AddedVia:        map[string]string{"Close": "io.Closer"},
+				Implementations: []Implementation{{Type: "app.Server", Before: "*app.Server", Missing: []string{"Close"}}},
+			}},
+		},
+		Warnings:   []Warning{{Code: WarnCacheMiss, Message: "not cached"}},
+		Floor:      &Floor{Required: "v2.0.0", Current: "v1.0.0", Gap: "1 major version(s) behind"},
+		Provenance: &Provenance{GoVersion: "go1.21.13", Options: Options{Platforms: []string{"linux/amd64"}}},
+	}
+
+	path := filepath.Join(t.TempDir(), "result.json")
+	if err := SaveResult(path, result); err != nil {
+		t.Fatalf("SaveResult() error = %v", err)
+	}
+	loaded, err := LoadResult(path)
+	if err != nil {
+		t.Fatalf("LoadResult() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded, result) {
+		t.Errorf("LoadResult() = %+v, want %+v", loaded, result)
+	}
+	if loaded.Changes.Removed[0].Level() != SeverityWarning {
+		t.Errorf("loaded severity = %s, want %s", loaded.Changes.Removed[0].Level(), SeverityWarning)
+	}
+}
+
+func TestLoadResult_Errors(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"report.json": `{"module": "example.com/lib", "removed": []}`,
+		"newer.json":  `{"format": "go-semver-audit/result", "version": 99, "result": {}}`,
+		"broken.json": `{`,
+	}
+	wants := map[string]string{
+		"report.json": "is not a saved result",
+		"newer.json":  "newer than the supported",
+		"broken.json": "failed to parse result",
+	}
+	for name, content := range tests {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadResult(path); err == nil || !strings.Contains(err.Error(), wants[name]) {
+			t.Errorf("LoadResult(%s) error = %v, want %q", name, err, wants[name])
+		}
+	}
+	if _, err := LoadResult(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("LoadResult() of a missing file succeeded")
+	}
+}
@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// Retraction is a version of the upgraded module that its authors retracted
+// with a retract directive in the go.mod of the module's latest version
+type Retraction struct {
+	Version   string
+	Current   bool   // the version the project requires now, rather than the upgrade target
+	Rationale string // comment on the retract directive, if any
+}
+
+// latestModFile loads the go.mod of the module's latest version, which is
+// the one that declares its retractions and deprecation. A failed download is
+// recorded as a warning and returns nil.
+func (a *Analyzer) latestModFile(module string) *modfile.File {
+	latest, err := a.loadModuleFile(module, "latest")
+	if err != nil {
+		a.warn(WarnRetractions, "could not check %s for retracted versions or a deprecation: %v", module, err)
+		return nil
+	}
+	return latest
+}
+
+// checkModuleStatus records the retracted versions of the upgrade and the
+// deprecation of its module on result
+func (a *Analyzer) checkModuleStatus(result *Result, upgrade *Upgrade, newDependency bool) {
+	latest := a.latestModFile(upgrade.Module)
+	result.Retractions = checkRetractions(latest, upgrade, newDependency)
+	result.Deprecation = checkDeprecation(latest)
+}
+
+// checkRetractions reports which versions of the upgrade the latest go.mod
+// of the module retracts
+func checkRetractions(latest *modfile.File, upgrade *Upgrade, newDependency bool) []Retraction {
+	if latest == nil {
+		return nil
+	}
+	var result []Retraction
+	if rationale, ok := retracted(latest, upgrade.NewVersion); ok {
+		result = append(result, Retraction{Version: upgrade.NewVersion, Rationale: rationale})
+	}
+	if newDependency || upgrade.OldVersion == upgrade.NewVersion {
+		return result
+	}
+	if rationale, ok := retracted(latest, upgrade.OldVersion); ok {
+		result = append(result, Retraction{Version: upgrade.OldVersion, Current: true, Rationale: rationale})
+	}
+	return result
+}
+
+// retracted reports whether a retract directive of f covers version, and the
+// rationale given for it
+func retracted(f *modfile.File, version string) (string, bool) {
+	if !semver.IsValid(version) {
+		return "", false
+	}
+	for _, r := range f.Retract {
+		if semver.Compare(r.Low, version) <= 0 && semver.Compare(version, r.High) <= 0 {
+			return r.Rationale, true
+		}
+	}
+	return "", false
+}
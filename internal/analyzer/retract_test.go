@@ -0,0 +1,112 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/mod/modfile"
+)
+
+// mockLatestGoMod answers every `go mod download` with a go.mod holding content
+func mockLatestGoMod(t *testing.T, content string) func() {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "latest.mod")
+	writeFile(t, path, content)
+	return mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+		module, version, _ := strings.Cut(args[len(args)-1], "@")
+		return json.Marshal(moduleDownload{Path: module, Version: version, GoMod: path})
+	})
+}
+
+func TestCheckRetractions(t *testing.T) {
+	latest, err := modfile.ParseLax("go.mod", []byte(`module example.com/lib
+
+retract (
+	v1.4.0 // Publishes a broken Close.
+	[v1.1.0, v1.2.5]
+)
+`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		upgrade       Upgrade
+		newDependency bool
+		want          []Retraction
+	}{
+		{
+			name:    "retracted target",
+			upgrade: Upgrade{Module: "example.com/lib", OldVersion: "v1.3.0", NewVersion: "v1.4.0"},
+			want:    []Retraction{{Version: "v1.4.0", Rationale: "Publishes a broken Close."}},
+		},
+		{
+			name:    "retracted current version in a range",
+			upgrade: Upgrade{Module: "example.com/lib", OldVersion: "v1.2.0", NewVersion: "v1.5.0"},
+			want:    []Retraction{{Version: "v1.2.0", Current: true}},
+		},
+		{
+			name:          "new dependency has no current version",
+			upgrade:       Upgrade{Module: "example.com/lib", OldVersion: "v1.2.0", NewVersion: "v1.5.0"},
+			newDependency: true,
+		},
+		{
+			name:    "neither retracted",
+			upgrade: Upgrade{Module: "example.com/lib", OldVersion: "v1.0.0", NewVersion: "v1.3.0"},
+		},
+		{
+			name:    "queries and branches are not versions",
+			upgrade: Upgrade{Module: "example.com/lib", OldVersion: "v1.0.0", NewVersion: "master"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upgrade := tt.upgrade
+			if got := checkRetractions(latest, &upgrade, tt.newDependency); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkRetractions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckModuleStatus(t *testing.T) {
+	defer mockLatestGoMod(t, `// Deprecated: use example.com/lib/v2 instead.
+module example.com/lib
+
+retract v1.1.0
+`)()
+
+	a := &Analyzer{projectPath: "."}
+	result := &Result{}
+	a.checkModuleStatus(result, &Upgrade{Module: "example.com/lib", OldVersion: "v1.0.0", NewVersion: "v1.1.0"}, false)
+	if want := []Retraction{{Version: "v1.1.0"}}; !reflect.DeepEqual(result.Retractions, want) {
+		t.Errorf("Retractions = %+v, want %+v", result.Retractions, want)
+	}
+	if want := (&Deprecation{Message: "use example.com/lib/v2 instead.", Successor: "example.com/lib/v2"}); !reflect.DeepEqual(result.Deprecation, want) {
+		t.Errorf("Deprecation = %+v, want %+v", result.Deprecation, want)
+	}
+	if len(a.warnings) != 0 {
+		t.Errorf("warnings = %+v", a.warnings)
+	}
+}
+
+func TestCheckModuleStatusWarnsWhenOffline(t *testing.T) {
+	defer mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+		return nil, errors.New("proxy unreachable")
+	})()
+
+	a := &Analyzer{projectPath: "."}
+	result := &Result{}
+	a.checkModuleStatus(result, &Upgrade{Module: "example.com/lib", OldVersion: "v1.0.0", NewVersion: "v1.1.0"}, false)
+	if result.Retractions != nil || result.Deprecation != nil {
+		t.Errorf("checkModuleStatus() = %+v, %+v, want nothing", result.Retractions, result.Deprecation)
+	}
+	if len(a.warnings) != 1 || a.warnings[0].Code != WarnRetractions || !strings.Contains(a.warnings[0].Message, "proxy unreachable") {
+		t.Errorf("warnings = %+v, want one %s warning", a.warnings, WarnRetractions)
+	}
+}
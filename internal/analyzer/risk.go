@@ -0,0 +1,109 @@
+package analyzer
+
+import (
+	"math"
+	"strings"
+)
+
+// Risk weights: history dominates, how deeply the project leans on the
+// dependency comes next, and a large API surface adds a little
+const (
+	riskWeightHistory = 0.5
+	riskWeightUsage   = 0.3
+	riskWeightSurface = 0.2
+
+	// riskUsageSaturation is the number of call sites treated as "deeply used"
+	riskUsageSaturation = 50
+	// riskSurfaceSaturation is the exported symbol count treated as "huge"
+	riskSurfaceSaturation = 1000
+)
+
+// Risk is a heuristic score of how risky a dependency is to keep current
+type Risk struct {
+	Score        int     // 0 (safe) to 100 (risky)
+	Level        string  // "low", "medium", or "high"
+	Audits       int     // audits the breaking rate is based on, including this one
+	BreakingRate float64 // share of those audits that found breaking changes
+	APISurface   int     // exported top-level symbols in the new version
+	UsedSymbols  int     // distinct top-level symbols the project uses, at most APISurface
+	UsageSites   int     // locations in the project using the dependency
+}
+
+// assessRisk scores a dependency from this audit alone; AddHistory folds in past audits
+func assessRisk(newAPI *API, usage *Usage, diff *Diff) *Risk {
+	r := &Risk{
+		APISurface:  apiObjects(newAPI),
+		UsedSymbols: usedObjects(usage),
+	}
+	// Uses of symbols the API does not list, such as variables, would
+	// otherwise report more symbols used than exist
+	r.UsedSymbols = min(r.UsedSymbols, r.APISurface)
+	for _, locations := range usage.Symbols {
+		r.UsageSites += len(locations)
+	}
+
+	breaking := 0
+	if diff.BreakingCount() > 0 {
+		breaking = 1
+	}
+	r.AddHistory(1, breaking)
+	return r
+}
+
+// AddHistory adds past audits, of which breaking found breaking changes, and rescores
+func (r *Risk) AddHistory(audits, breaking int) {
+	total := float64(r.Audits)*r.BreakingRate + float64(breaking)
+	r.Audits += audits
+	if r.Audits > 0 {
+		r.BreakingRate = total / float64(r.Audits)
+	}
+
+	usage := math.Min(1, float64(r.UsageSites)/riskUsageSaturation)
+	surface := 0.0
+	if r.APISurface > 0 {
+		surface = math.Min(1, math.Log10(float64(r.APISurface))/math.Log10(riskSurfaceSaturation))
+	}
+	score := riskWeightHistory*r.BreakingRate + riskWeightUsage*usage + riskWeightSurface*surface
+	r.Score = int(math.Round(100 * score))
+
+	switch {
+	case r.Score >= 60:
+		r.Level = "high"
+	case r.Score >= 30:
+		r.Level = "medium"
+	default:
+		r.Level = "low"
+	}
+}
+
+// apiObjects counts the top-level symbols of an API: functions, types,
+// interfaces, and constants. Methods belong to their type.
+func apiObjects(api *API) int {
+	names := make(map[string]bool)
+	for name, fn := range api.Funcs {
+		if !fn.IsMethod {
+			names[name] = true
+		}
+	}
+	for name := range api.Types {
+		names[name] = true
+	}
+	for name := range api.Interfaces {
+		names[name] = true
+	}
+	for name := range api.Consts {
+		names[name] = true
+	}
+	return len(names)
+}
+
+// usedObjects counts the distinct top-level symbols the project uses; uses of
+// a field or method (Type.Name) count toward their type
+func usedObjects(usage *Usage) int {
+	names := make(map[string]bool)
+	for name := range usage.Symbols {
+		top, _, _ := strings.Cut(name, ".")
+		names[top] = true
+	}
+	return len(names)
+}
@@ -0,0 +1,87 @@
+package analyzer
+
+import "testing"
+
+func TestAssessRisk(t *testing.T) {
+	api := emptyAPI()
+	for _, name := range []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J"} {
+		api.Funcs[name] = &Function{Name: name}
+	}
+	usage := &Usage{Symbols: map[string][]Location{
+		"A": make([]Location, 20),
+		"B": make([]Location, 5),
+	}}
+
+	tests := []struct {
+		name      string
+		diff      *Diff
+		history   [2]int // past audits, breaking
+		wantScore int
+		wantLevel string
+		wantRate  float64
+	}{
+		{
+			name:      "clean audit",
+			diff:      &Diff{},
+			wantScore: 22, // 0.3*25/50 + 0.2*log10(10)/3
+			wantLevel: "low",
+		},
+		{
+			name:      "breaking audit",
+			diff:      &Diff{Removed: []RemovedSymbol{{Name: "A"}}},
+			wantScore: 72,
+			wantLevel: "high",
+			wantRate:  1,
+		},
+		{
+			name:      "breaking audit with clean history",
+			diff:      &Diff{Removed: []RemovedSymbol{{Name: "A"}}},
+			history:   [2]int{3, 0},
+			wantScore: 34,
+			wantLevel: "medium",
+			wantRate:  0.25,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			risk := assessRisk(api, usage, tt.diff)
+			if tt.history[0] > 0 {
+				risk.AddHistory(tt.history[0], tt.history[1])
+			}
+			if risk.Score != tt.wantScore || risk.Level != tt.wantLevel || risk.BreakingRate != tt.wantRate {
+				t.Errorf("risk = %+v, want score %d (%s), rate %v", risk, tt.wantScore, tt.wantLevel, tt.wantRate)
+			}
+			if risk.APISurface != 10 || risk.UsedSymbols != 2 || risk.UsageSites != 25 {
+				t.Errorf("risk inputs = %+v", risk)
+			}
+		})
+	}
+}
+
+func TestAssessRiskCountsTopLevelSymbols(t *testing.T) {
+	api := emptyAPI()
+	api.Types["Client"] = &Type{Name: "Client"}
+	api.Funcs["Client.Do"] = &Function{Name: "Client.Do", IsMethod: true}
+	api.Funcs["Client.Close"] = &Function{Name: "Client.Close", IsMethod: true}
+	api.Funcs["New"] = &Function{Name: "New"}
+	api.Consts["Version"] = &Const{Name: "Version"}
+	usage := &Usage{Symbols: map[string][]Location{
+		"Client":         {{File: "main.go", Line: 3}},
+		"Client.Do":      {{File: "main.go", Line: 4}},
+		"Client.Close":   {{File: "main.go", Line: 5}},
+		"Client.Timeout": {{File: "main.go", Line: 6}},
+		"New":            {{File: "main.go", Line: 7}},
+	}}
+
+	risk := assessRisk(api, usage, &Diff{})
+	if risk.APISurface != 3 || risk.UsedSymbols != 2 {
+		t.Fatalf("risk = %d of %d symbols used, want 2 of 3", risk.UsedSymbols, risk.APISurface)
+	}
+
+	usage.Symbols["ErrClosed"] = []Location{{File: "main.go", Line: 8}}
+	usage.Symbols["DefaultTimeout"] = []Location{{File: "main.go", Line: 9}}
+	if risk := assessRisk(api, usage, &Diff{}); risk.UsedSymbols != risk.APISurface {
+		t.Fatalf("risk = %d of %d symbols used, want at most the API surface", risk.UsedSymbols, risk.APISurface)
+	}
+}
@@ -0,0 +1,125 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// Severities a finding can be reported at. Only errors count as breaking.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Categories of findings whose severity can be overridden
+const (
+	CategoryRemoved                = "removed"
+	CategoryMoved                  = "moved"
+	CategorySignatureChanged       = "signature_changed"
+	CategorySignatureParamRename   = "signature_param_rename"
+	CategoryInterfaceAddedMethod   = "interface_added_method"
+	CategoryInterfaceRemovedMethod = "interface_removed_method"
+)
+
+// categories lists every category accepted in severity overrides
+var categories = map[string]bool{
+	CategoryRemoved:                true,
+	CategoryMoved:                  true,
+	CategorySignatureChanged:       true,
+	CategorySignatureParamRename:   true,
+	CategoryInterfaceAddedMethod:   true,
+	CategoryInterfaceRemovedMethod: true,
+}
+
+// defaultSeverities holds the categories that are not errors by default.
+// Renaming parameters keeps every call compiling, so it is only noted.
+var defaultSeverities = map[string]string{
+	CategorySignatureParamRename: SeverityInfo,
+}
+
+// ValidateSeverities checks that severity overrides map known categories to
+// known severities
+func ValidateSeverities(overrides map[string]string) error {
+	for _, category := range sortedKeys(overrides) {
+		if !categories[category] {
+			return fmt.Errorf("unknown finding category %q (expected one of %s)", category, strings.Join(sortedKeys(categories), ", "))
+		}
+		switch overrides[category] {
+		case SeverityError, SeverityWarning, SeverityInfo:
+		default:
+			return fmt.Errorf("invalid severity %q for %s (expected error, warning, or info)", overrides[category], category)
+		}
+	}
+	return nil
+}
+
+// effectiveSeverity resolves a finding's severity: an override if one
+// applies, otherwise the category's default, a warning for unstable APIs
+// and for findings only dead code uses, and an error for the rest
+func effectiveSeverity(category, override string, unstable, dead bool) string {
+	switch {
+	case override != "":
+		return override
+	case defaultSeverities[category] != "":
+		return defaultSeverities[category]
+	case unstable || dead:
+		return SeverityWarning
+	default:
+		return SeverityError
+	}
+}
+
+// Category classifies the finding for severity overrides
+func (r RemovedSymbol) Category() string { return CategoryRemoved }
+
+// Level is the severity the finding is reported at
+func (r RemovedSymbol) Level() string {
+	return effectiveSeverity(r.Category(), r.Severity, r.Unstable, r.DeadCode)
+}
+
+// Category classifies the finding for severity overrides
+func (m MovedSymbol) Category() string { return CategoryMoved }
+
+// Level is the severity the finding is reported at
+func (m MovedSymbol) Level() string {
+	return effectiveSeverity(m.Category(), m.Severity, m.Unstable, m.DeadCode)
+}
+
+// Category classifies the finding for severity overrides
+func (c ChangedSignature) Category() string {
+	if c.ParamNamesOnly {
+		return CategorySignatureParamRename
+	}
+	return CategorySignatureChanged
+}
+
+// Level is the severity the finding is reported at
+func (c ChangedSignature) Level() string {
+	return effectiveSeverity(c.Category(), c.Severity, c.Unstable, c.DeadCode)
+}
+
+// Category classifies the finding for severity overrides. Removing or
+// changing a method breaks callers, so it outranks added methods.
+func (i InterfaceChange) Category() string {
+	if len(i.RemovedMethods) > 0 || len(i.ChangedMethods) > 0 {
+		return CategoryInterfaceRemovedMethod
+	}
+	return CategoryInterfaceAddedMethod
+}
+
+// Level is the severity the finding is reported at
+func (i InterfaceChange) Level() string {
+	return effectiveSeverity(i.Category(), i.Severity, i.Unstable, i.DeadCode)
+}
+
+// onlyParamNamesChanged reports whether two functions differ in nothing but
+// the names of their parameters and results
+func onlyParamNamesChanged(oldFunc, newFunc *Function) bool {
+	if oldFunc.obj == nil || newFunc.obj == nil {
+		return false
+	}
+	oldSig, newSig := oldFunc.obj.Type().(*types.Signature), newFunc.obj.Type().(*types.Signature)
+	return signatureShape(oldSig, nil) == signatureShape(newSig, nil)
+}
@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestValidateSeverities(t *testing.T) {
+	if err := ValidateSeverities(map[string]string{CategoryInterfaceAddedMethod: SeverityError, CategorySignatureParamRename: SeverityInfo}); err != nil {
+		t.Errorf("ValidateSeverities() error = %v", err)
+	}
+	if err := ValidateSeverities(map[string]string{"renamed": SeverityInfo}); err == nil || !strings.Contains(err.Error(), "unknown finding category") {
+		t.Errorf("unknown category error = %v", err)
+	}
+	if err := ValidateSeverities(map[string]string{CategoryRemoved: "fatal"}); err == nil || !strings.Contains(err.Error(), "invalid severity") {
+		t.Errorf("invalid severity error = %v", err)
+	}
+}
+
+func TestApplySeverities(t *testing.T) {
+	used := []Location{{File: "main.go", Line: 1}}
+	diff := &Diff{
+		Removed: []RemovedSymbol{{Name: "Gone", UsedIn: used}},
+		Changed: []ChangedSignature{
+			{Name: "Open", UsedIn: used, ParamNamesOnly: true},
+			{Name: "Close", UsedIn: used},
+		},
+		InterfaceChanges: []InterfaceChange{
+			{Name: "Doer", AddedMethods: []string{"Undo()"}, UsedIn: used, Unstable: true},
+			{Name: "Runner", RemovedMethods: []string{"Run()"}, UsedIn: used},
+		},
+	}
+	// Open only renamed a parameter, so it is info by default
+	if got := diff.BreakingCount(); got != 3 {
+		t.Fatalf("BreakingCount() before overrides = %d, want 3", got)
+	}
+	if got := diff.Changed[0].Level(); got != SeverityInfo {
+		t.Fatalf("param rename Level() before overrides = %s, want info", got)
+	}
+
+	Classifier{Severities: map[string]string{
+		CategoryInterfaceAddedMethod: SeverityError,
+		CategorySignatureParamRename: SeverityWarning,
+		CategoryRemoved:              SeverityWarning,
+	}}.Apply(diff)
+	if diff.Changed[0].Level() != SeverityWarning || diff.Changed[1].Level() != SeverityError {
+		t.Errorf("Changed levels = %s, %s", diff.Changed[0].Level(), diff.Changed[1].Level())
+	}
+	if diff.InterfaceChanges[0].Level() != SeverityError || diff.InterfaceChanges[1].Category() != CategoryInterfaceRemovedMethod {
+		t.Errorf("InterfaceChanges = %+v", diff.InterfaceChanges)
+	}
+	// Close, Doer (unstable but remapped to error), and Runner
+	if got := diff.BreakingCount(); got != 3 {
+		t.Errorf("BreakingCount() = %d, want 3", got)
+	}
+	if got := diff.WarningCount(); got != 2 {
+		t.Errorf("WarningCount() = %d, want 2", got)
+	}
+	if got := diff.AffectedLocations(); got != 3 {
+		t.Errorf("AffectedLocations() = %d, want 3", got)
+	}
+}
+
+func TestDiffAPIs_ParamRename(t *testing.T) {
+	oldLib := checkSource(t, "example.com/lib", `package lib
+
+func Open(name string) error { return nil }
+
+func Close(force bool) {}
+`, nil)
+	newLib := checkSource(t, "example.com/lib", `package lib
+
+func Open(path string) error { return nil }
+
+func Close(force bool, timeout int) {}
+`, nil)
+	usage := &Usage{Symbols: map[string][]Location{
+		"Open":  {{File: "main.go", Line: 3}},
+		"Close": {{File: "main.go", Line: 4}},
+	}}
+
+	diff := diffAPIs(extractAPI([]*packages.Package{oldLib}), extractAPI([]*packages.Package{newLib}), usage)
+	if len(diff.Changed) != 2 {
+		t.Fatalf("Changed = %+v", diff.Changed)
+	}
+	if got := diff.Changed[0]; got.Name != "Close" || got.Category() != CategorySignatureChanged {
+		t.Errorf("Close category = %s", got.Category())
+	}
+	if got := diff.Changed[1]; got.Name != "Open" || got.Category() != CategorySignatureParamRename {
+		t.Errorf("Open category = %s", got.Category())
+	}
+}
@@ -0,0 +1,167 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ShardCommand is the hidden CLI subcommand that runs one shard of an API load.
+// It reads a ShardRequest as JSON on stdin and writes the API as JSON on stdout.
+const ShardCommand = "api-shard"
+
+// ShardRequest asks a worker process to load the API of a subset of packages
+type ShardRequest struct {
+	Patterns     []string // package@version patterns
+	IncludeTests bool
+	Platform     string // GOOS/GOARCH to load for, empty for the host
+	ModFlag      string // see Options.ModFlag
+}
+
+// Allow overriding in tests
+var runShard = func(req ShardRequest) (*API, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	in, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(exe, ShardCommand)
+	cmd.Stdin = bytes.NewReader(in)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("shard worker failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var api API
+	if err := json.Unmarshal(out, &api); err != nil {
+		return nil, fmt.Errorf("failed to parse shard output: %w", err)
+	}
+	return &api, nil
+}
+
+// LoadShardAPI loads the API of the packages in a shard. It runs inside the
+// worker process started for each shard.
+func LoadShardAPI(req ShardRequest) (*API, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax |
+			packages.NeedTypesInfo,
+		Env: platformEnv(moduleCacheEnv(req.ModFlag), req.Platform),
+	}
+
+	pkgs, err := packagesLoad(cfg, req.Patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load shard: %w", err)
+	}
+
+	a := &Analyzer{opts: Options{IncludeTestPackages: req.IncludeTests}}
+	return extractAPI(a.filterPackages(pkgs)), nil
+}
+
+// loadModuleAPISharded splits the module's packages across worker processes so
+// no single process type-checks the whole module, then merges their APIs. It
+// lists the same packages an unsharded load would: every importable package
+// of the module, or the imported ones of a golang.org/x/ module.
+func (a *Analyzer) loadModuleAPISharded(module, version string) (*API, error) {
+	// Names only: cheap compared to type-checking
+	cfg := &packages.Config{
+		Mode: packages.NeedName,
+		Env:  platformEnv(moduleCacheEnv(a.opts.ModFlag), a.platform),
+	}
+	patterns, _ := a.apiPatterns(module, version)
+	pkgs, err := a.load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages of %s@%s: %w", module, version, err)
+	}
+
+	var paths []string
+	for _, pkg := range a.filterPackages(pkgs) {
+		paths = append(paths, pkg.PkgPath)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no packages found for module %s@%s", module, version)
+	}
+	sort.Strings(paths)
+
+	shards := splitShards(paths, a.opts.Shards)
+	apis := make([]*API, len(shards))
+	errs := make([]error, len(shards))
+	var wg sync.WaitGroup
+	for i, shard := range shards {
+		patterns := make([]string, len(shard))
+		for j, path := range shard {
+			patterns[j] = path + "@" + version
+		}
+		wg.Add(1)
+		go func(i int, req ShardRequest) {
+			defer wg.Done()
+			apis[i], errs[i] = runShard(req)
+		}(i, ShardRequest{Patterns: patterns, IncludeTests: a.opts.IncludeTestPackages, Platform: a.platform, ModFlag: a.opts.ModFlag})
+	}
+	wg.Wait()
+
+	merged := emptyAPI()
+	for i, api := range apis {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("shard %d/%d: %w", i+1, len(shards), errs[i])
+		}
+		mergeAPI(merged, api)
+	}
+	return merged, nil
+}
+
+// splitShards divides sorted package paths into at most n contiguous groups, so
+// neighbouring packages that share dependencies are type-checked together
+func splitShards(paths []string, n int) [][]string {
+	if n > len(paths) {
+		n = len(paths)
+	}
+	shards := make([][]string, 0, n)
+	for i := 0; i < n; i++ {
+		start, end := i*len(paths)/n, (i+1)*len(paths)/n
+		shards = append(shards, paths[start:end])
+	}
+	return shards
+}
+
+// mergeAPI adds the symbols of src to dst, marking names both export from
+// different packages as ambiguous
+func mergeAPI(dst, src *API) {
+	for name, fn := range src.Funcs {
+		if !fn.IsMethod {
+			dst.noteOwner(name, fn.PkgPath)
+		}
+		dst.Funcs[name] = fn
+	}
+	for name, t := range src.Types {
+		dst.noteOwner(name, t.PkgPath)
+		dst.Types[name] = t
+	}
+	for name, iface := range src.Interfaces {
+		dst.noteOwner(name, iface.PkgPath)
+		dst.Interfaces[name] = iface
+	}
+	for name, c := range src.Consts {
+		dst.noteOwner(name, c.PkgPath)
+		dst.Consts[name] = c
+	}
+	for pkg := range src.Generated {
+		dst.Generated[pkg] = true
+	}
+	for name := range src.Ambiguous {
+		dst.Ambiguous[name] = true
+	}
+	dst.Packages = append(dst.Packages, src.Packages...)
+}
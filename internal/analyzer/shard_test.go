@@ -0,0 +1,174 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestLoadModuleAPISharded(t *testing.T) {
+	restoreLoad := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		// Listing call: names only
+		if cfg.Mode == packages.NeedName {
+			if len(patterns) != 1 || patterns[0] != "example.com/big/...@v1.0.0" {
+				t.Fatalf("unexpected listing patterns %v", patterns)
+			}
+			return []*packages.Package{
+				{PkgPath: "example.com/big/c", Name: "c"},
+				{PkgPath: "example.com/big/a", Name: "a"},
+				{PkgPath: "example.com/big/examples/demo", Name: "main"},
+				{PkgPath: "example.com/big/b", Name: "b"},
+			}, nil
+		}
+		var pkgs []*packages.Package
+		for _, p := range patterns {
+			pkgs = append(pkgs, buildAPIPackage(strings.TrimSuffix(p, "@v1.0.0")))
+		}
+		return pkgs, nil
+	})
+	defer restoreLoad()
+
+	var mu sync.Mutex
+	var requests [][]string
+	origRun := runShard
+	defer func() { runShard = origRun }()
+	runShard = func(req ShardRequest) (*API, error) {
+		mu.Lock()
+		requests = append(requests, req.Patterns)
+		mu.Unlock()
+
+		// Round-trip through JSON like the worker process does
+		api, err := LoadShardAPI(req)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(api)
+		if err != nil {
+			return nil, err
+		}
+		var decoded API
+		return &decoded, json.Unmarshal(data, &decoded)
+	}
+
+	a := &Analyzer{opts: Options{Shards: 2}}
+	api, err := a.loadModuleAPI("example.com/big", "v1.0.0")
+	if err != nil {
+		t.Fatalf("loadModuleAPI() error = %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("shards run = %d, want 2", len(requests))
+	}
+	for _, patterns := range requests {
+		for _, p := range patterns {
+			if strings.Contains(p, "examples") {
+				t.Errorf("example package %s was not filtered before sharding", p)
+			}
+		}
+	}
+
+	got := append([]string(nil), api.Packages...)
+	sort.Strings(got)
+	want := []string{"example.com/big/a", "example.com/big/b", "example.com/big/c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("merged Packages = %v, want %v", api.Packages, want)
+	}
+	if api.Funcs["Func"] == nil || api.Interfaces["Handler"] == nil || api.Types["Thing"] == nil {
+		t.Fatalf("merged API missing symbols: %+v", api)
+	}
+}
+
+func TestSplitShards(t *testing.T) {
+	paths := []string{"a", "b", "c", "d", "e"}
+
+	got := splitShards(paths, 2)
+	want := [][]string{{"a", "b"}, {"c", "d", "e"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("splitShards(2) = %v, want %v", got, want)
+	}
+
+	if got := splitShards(paths[:2], 8); len(got) != 2 {
+		t.Fatalf("splitShards() with more shards than packages = %v", got)
+	}
+}
+
+func TestLoadModuleAPISharded_XModuleListsImportedPackages(t *testing.T) {
+	var listed []string
+	restoreLoad := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		listed = patterns
+		var pkgs []*packages.Package
+		for _, p := range patterns {
+			pkgs = append(pkgs, &packages.Package{PkgPath: strings.TrimSuffix(p, "@v0.6.0"), Name: "pkg"})
+		}
+		return pkgs, nil
+	})
+	defer restoreLoad()
+
+	origRun := runShard
+	defer func() { runShard = origRun }()
+	runShard = func(req ShardRequest) (*API, error) { return emptyAPI(), nil }
+
+	a := projectImporting("golang.org/x/sync", "golang.org/x/sync/errgroup", "golang.org/x/sync/singleflight")
+	a.opts.Shards = 2
+	if _, err := a.loadModuleAPI("golang.org/x/sync", "v0.6.0"); err != nil {
+		t.Fatalf("loadModuleAPI() error = %v", err)
+	}
+	want := []string{"golang.org/x/sync/errgroup@v0.6.0", "golang.org/x/sync/singleflight@v0.6.0"}
+	if !reflect.DeepEqual(listed, want) {
+		t.Errorf("listed %v, want the imported packages %v", listed, want)
+	}
+}
+
+func TestLoadModuleAPIShardedSplitsModulePackages(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not available")
+	}
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOFLAGS", "-mod=mod")
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "v1.0.0", "go.mod"), "module example.com/big\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(root, "v1.0.0", "big.go"), "package big\n\nfunc Open() {}\n")
+	writeFile(t, filepath.Join(root, "v1.0.0", "codec", "codec.go"), "package codec\n\ntype Codec struct{}\n")
+	writeFile(t, filepath.Join(root, "v1.0.0", "wire", "wire.go"), "package wire\n\ntype Handler interface{ Handle() }\n")
+	writeFile(t, filepath.Join(root, "v1.0.0", "internal", "buf", "buf.go"), "package buf\n\nfunc Grow() {}\n")
+	resolveVersionsLocally(t, "example.com/big", root)
+
+	// Run the workers in process; they still load through the go command
+	var mu sync.Mutex
+	var requests [][]string
+	origRun := runShard
+	defer func() { runShard = origRun }()
+	runShard = func(req ShardRequest) (*API, error) {
+		mu.Lock()
+		requests = append(requests, req.Patterns)
+		mu.Unlock()
+		return LoadShardAPI(req)
+	}
+
+	a := &Analyzer{opts: Options{Shards: 2}}
+	api, err := a.loadModuleAPI("example.com/big", "v1.0.0")
+	if err != nil {
+		t.Fatalf("loadModuleAPI() error = %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("shards run = %v, want 2", requests)
+	}
+
+	got := append([]string(nil), api.Packages...)
+	sort.Strings(got)
+	want := []string{"example.com/big", "example.com/big/codec", "example.com/big/wire"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("merged Packages = %v, want %v", got, want)
+	}
+	if api.Funcs["Open"] == nil || api.Types["Codec"] == nil || api.Interfaces["Handler"] == nil || api.Funcs["Grow"] != nil {
+		t.Fatalf("merged API = %+v, want every importable package's symbols", api)
+	}
+}
@@ -0,0 +1,347 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// shimFileName is the file written into the project to hold compatibility shims
+const shimFileName = "semver_audit_shims.go"
+
+// writeShims generates adapters that keep the old shape of changed and moved
+// functions on top of the new API, so the project can upgrade first and
+// migrate call sites gradually. The file is written to dir, which is resolved
+// relative to the project, through editGo, so DryRun only previews it.
+func (a *Analyzer) writeShims(dir string, result *Result, oldAPI, newAPI *API) (*ShimFile, error) {
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(a.projectPath, dir)
+	}
+
+	pkgName, err := shimPackageName(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	src, shims, err := generateShims(pkgName, result, oldAPI, newAPI)
+	if err != nil {
+		return nil, err
+	}
+	if len(shims.Functions) == 0 {
+		return shims, nil
+	}
+
+	if !a.opts.DryRun {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	path := filepath.Join(dir, shimFileName)
+	diff, err := a.editGo(path, src, nil)
+	if err != nil {
+		return nil, err
+	}
+	shims.Path = path
+	shims.Diff = diff
+	shims.DryRun = a.opts.DryRun
+	return shims, nil
+}
+
+// shimPackageName returns the package declared by the Go files in dir, or a
+// name derived from the directory when it has none yet
+func shimPackageName(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == shimFileName {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, name), nil, parser.PackageClauseOnly)
+		if err != nil {
+			continue
+		}
+		return file.Name.Name, nil
+	}
+
+	name := strings.Map(func(r rune) rune {
+		if r == '-' || r == '.' {
+			return '_'
+		}
+		return r
+	}, filepath.Base(dir))
+	if !token.IsIdentifier(name) {
+		return "", fmt.Errorf("cannot derive a package name from %s", dir)
+	}
+	return name, nil
+}
+
+// generateShims renders the shim file for the changed and moved functions of a result
+func generateShims(pkgName string, result *Result, oldAPI, newAPI *API) ([]byte, *ShimFile, error) {
+	shims := &ShimFile{}
+	b := newShimBuilder()
+
+	var funcs bytes.Buffer
+	add := func(name, newName string) {
+		oldFunc, newFunc := oldAPI.Funcs[name], newAPI.Funcs[newName]
+		if oldFunc == nil || newFunc == nil || oldFunc.obj == nil || newFunc.obj == nil {
+			shims.Skipped = append(shims.Skipped, name+": type information unavailable")
+			return
+		}
+		if oldFunc.IsMethod || newFunc.IsMethod {
+			shims.Skipped = append(shims.Skipped, name+": methods cannot be shimmed")
+			return
+		}
+		oldSig := oldFunc.obj.Type().(*types.Signature)
+		newSig := newFunc.obj.Type().(*types.Signature)
+		if oldSig.TypeParams().Len() > 0 || newSig.TypeParams().Len() > 0 {
+			shims.Skipped = append(shims.Skipped, name+": generic functions cannot be shimmed")
+			return
+		}
+
+		shimName := name + "Compat"
+		funcs.WriteString(b.shim(shimName, newFunc.obj, oldSig, newSig))
+		shims.Functions = append(shims.Functions, shimName)
+	}
+
+	for _, changed := range result.Changes.Changed {
+		add(changed.Name, changed.Name)
+	}
+	for _, moved := range result.Changes.Moved {
+		if moved.Type == "function" {
+			add(moved.Name, moved.NewName)
+		}
+	}
+	if len(shims.Functions) == 0 {
+		return nil, shims, nil
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Compatibility shims generated by go-semver-audit for %s %s -> %s.\n", result.Module, result.OldVersion, result.NewVersion)
+	src.WriteString("// Each function keeps the old signature of an API that changed. Migrate the\n")
+	src.WriteString("// call sites to the new API, then delete this file.\n\n")
+	fmt.Fprintf(&src, "package %s\n\n", pkgName)
+	src.WriteString(b.importDecl())
+	src.Write(funcs.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, nil, fmt.Errorf("generated shims do not parse: %w", err)
+	}
+	return formatted, shims, nil
+}
+
+// shimBuilder renders shim functions and tracks the imports they need
+type shimBuilder struct {
+	imports map[string]string // import path -> name used in the file
+	taken   map[string]bool
+}
+
+func newShimBuilder() *shimBuilder {
+	return &shimBuilder{
+		imports: make(map[string]string),
+		taken:   make(map[string]bool),
+	}
+}
+
+// use imports path under a unique name and returns that name
+func (b *shimBuilder) use(path, name string) string {
+	if n, ok := b.imports[path]; ok {
+		return n
+	}
+	n := name
+	for i := 2; b.taken[n]; i++ {
+		n = fmt.Sprintf("%s%d", name, i)
+	}
+	b.imports[path] = n
+	b.taken[n] = true
+	return n
+}
+
+// qualifier is a types.Qualifier that imports every package it names
+func (b *shimBuilder) qualifier(pkg *types.Package) string {
+	return b.use(pkg.Path(), pkg.Name())
+}
+
+// importDecl renders the import block for everything used so far
+func (b *shimBuilder) importDecl() string {
+	paths := make([]string, 0, len(b.imports))
+	for path := range b.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var s strings.Builder
+	s.WriteString("import (\n")
+	for _, path := range paths {
+		name := b.imports[path]
+		if name == path[strings.LastIndex(path, "/")+1:] {
+			fmt.Fprintf(&s, "\t%q\n", path)
+		} else {
+			fmt.Fprintf(&s, "\t%s %q\n", name, path)
+		}
+	}
+	s.WriteString(")\n\n")
+	return s.String()
+}
+
+// shim renders one adapter with the old signature that calls the new function.
+// Arguments are matched to the new parameters by type in order; a new context
+// gets context.Background() and anything else unmatched gets its zero value,
+// flagged with a TODO.
+func (b *shimBuilder) shim(name string, target *types.Func, oldSig, newSig *types.Signature) string {
+	var todos []string
+	callee := b.qualifier(target.Pkg()) + "." + target.Name()
+
+	// Old parameters become the shim's parameters
+	oldParams := oldSig.Params()
+	params := make([]string, oldParams.Len())
+	for i := range params {
+		t := oldParams.At(i).Type()
+		if oldSig.Variadic() && i == oldParams.Len()-1 {
+			params[i] = fmt.Sprintf("p%d ...%s", i, types.TypeString(t.(*types.Slice).Elem(), b.qualifier))
+		} else {
+			params[i] = fmt.Sprintf("p%d %s", i, types.TypeString(t, b.qualifier))
+		}
+	}
+
+	// Map them onto the new parameters
+	newParams := newSig.Params()
+	next := 0
+	var args []string
+	for i := 0; i < newParams.Len(); i++ {
+		t := newParams.At(i).Type()
+		variadic := newSig.Variadic() && i == newParams.Len()-1
+		j := matchType(oldParams, next, t)
+		switch {
+		case j >= 0:
+			for k := next; k < j; k++ {
+				todos = append(todos, fmt.Sprintf("p%d is no longer accepted by %s", k, callee))
+			}
+			next = j + 1
+			arg := fmt.Sprintf("p%d", j)
+			if variadic {
+				arg += "..."
+			}
+			args = append(args, arg)
+		case variadic:
+			// optional arguments can be left out
+		case types.TypeString(t, nil) == "context.Context":
+			args = append(args, b.use("context", "context")+".Background()")
+		default:
+			typ := types.TypeString(t, b.qualifier)
+			args = append(args, fmt.Sprintf("*new(%s)", typ))
+			todos = append(todos, fmt.Sprintf("pass a real %s instead of its zero value", typ))
+		}
+	}
+	for k := next; k < oldParams.Len(); k++ {
+		todos = append(todos, fmt.Sprintf("p%d is no longer accepted by %s", k, callee))
+	}
+	call := fmt.Sprintf("%s(%s)", callee, strings.Join(args, ", "))
+
+	// Map the new results back onto the old ones
+	oldResults, newResults := oldSig.Results(), newSig.Results()
+	var body string
+	switch {
+	case oldResults.Len() == 0:
+		body = call
+	case sameTypes(oldResults, newResults):
+		body = "return " + call
+	default:
+		lhs := make([]string, newResults.Len())
+		for i := range lhs {
+			lhs[i] = "_"
+		}
+		rets := make([]string, oldResults.Len())
+		next := 0
+		for i := range rets {
+			t := oldResults.At(i).Type()
+			if j := matchType(newResults, next, t); j >= 0 {
+				lhs[j] = fmt.Sprintf("r%d", j)
+				rets[i] = lhs[j]
+				next = j + 1
+				continue
+			}
+			typ := types.TypeString(t, b.qualifier)
+			rets[i] = fmt.Sprintf("*new(%s)", typ)
+			todos = append(todos, fmt.Sprintf("return a real %s instead of its zero value", typ))
+		}
+		for j, v := range lhs {
+			if v == "_" {
+				todos = append(todos, fmt.Sprintf("result %d (%s) of %s is ignored", j, types.TypeString(newResults.At(j).Type(), b.qualifier), callee))
+			}
+		}
+
+		assign := "="
+		for _, v := range lhs {
+			if v != "_" {
+				assign = ":="
+			}
+		}
+		switch {
+		case newResults.Len() == 0:
+			body = call + "\n"
+		default:
+			body = fmt.Sprintf("%s %s %s\n", strings.Join(lhs, ", "), assign, call)
+		}
+		body += "return " + strings.Join(rets, ", ")
+	}
+
+	var s strings.Builder
+	fmt.Fprintf(&s, "// %s keeps the old signature of %s.\n", name, callee)
+	for _, todo := range todos {
+		fmt.Fprintf(&s, "// TODO: %s.\n", todo)
+	}
+	fmt.Fprintf(&s, "func %s(%s) %s {\n%s\n}\n\n", name, strings.Join(params, ", "), b.results(oldResults), body)
+	return s.String()
+}
+
+// results renders a result list for a function declaration
+func (b *shimBuilder) results(tuple *types.Tuple) string {
+	parts := make([]string, tuple.Len())
+	for i := range parts {
+		parts[i] = types.TypeString(tuple.At(i).Type(), b.qualifier)
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// matchType returns the index of the first variable in tuple at or after from
+// whose type matches t, or -1. Types from the two versions come from separate
+// loads, so they are compared by their fully qualified spelling.
+func matchType(tuple *types.Tuple, from int, t types.Type) int {
+	want := types.TypeString(t, nil)
+	for i := from; i < tuple.Len(); i++ {
+		if types.TypeString(tuple.At(i).Type(), nil) == want {
+			return i
+		}
+	}
+	return -1
+}
+
+// sameTypes reports whether two tuples have the same types in the same order
+func sameTypes(a, b *types.Tuple) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	for i := 0; i < a.Len(); i++ {
+		if types.TypeString(a.At(i).Type(), nil) != types.TypeString(b.At(i).Type(), nil) {
+			return false
+		}
+	}
+	return true
+}
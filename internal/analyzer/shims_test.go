@@ -0,0 +1,117 @@
+package analyzer
+
+import (
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateShims(t *testing.T) {
+	lib := types.NewPackage("example.com/lib", "lib")
+	ctxPkg := types.NewPackage("context", "context")
+	ctxType := types.NewNamed(types.NewTypeName(token.NoPos, ctxPkg, "Context", nil), types.NewInterfaceType(nil, nil), nil)
+	errType := types.Universe.Lookup("error").Type()
+	str := types.Typ[types.String]
+
+	param := func(typ types.Type) *types.Var { return types.NewVar(token.NoPos, lib, "", typ) }
+	fn := func(name string, params, results []*types.Var) *Function {
+		obj := types.NewFunc(token.NoPos, lib, name, newSignature(params, results))
+		return &Function{Name: name, Signature: signatureOf(obj.Type().(*types.Signature)), PkgPath: lib.Path(), obj: obj}
+	}
+
+	oldAPI := emptyAPI()
+	oldAPI.Funcs["Fetch"] = fn("Fetch", []*types.Var{param(str)}, []*types.Var{param(errType)})
+	oldAPI.Funcs["Name"] = fn("Name", nil, []*types.Var{param(str)})
+	oldAPI.Funcs["T.Do"] = &Function{Name: "T.Do", IsMethod: true, obj: types.NewFunc(token.NoPos, lib, "Do", newSignature(nil, nil))}
+
+	newAPI := emptyAPI()
+	newAPI.Funcs["Fetch"] = fn("Fetch", []*types.Var{param(ctxType), param(str)}, []*types.Var{param(errType)})
+	newAPI.Funcs["Name"] = fn("Name", nil, []*types.Var{param(str), param(errType)})
+	newAPI.Funcs["T.Do"] = &Function{Name: "T.Do", IsMethod: true, obj: types.NewFunc(token.NoPos, lib, "Do", newSignature(nil, nil))}
+
+	result := &Result{
+		Module:     "example.com/lib",
+		OldVersion: "v1.0.0",
+		NewVersion: "v2.0.0",
+		Changes: &Diff{Changed: []ChangedSignature{
+			{Name: "Fetch"}, {Name: "Name"}, {Name: "T.Do"},
+		}},
+	}
+
+	src, shims, err := generateShims("compat", result, oldAPI, newAPI)
+	if err != nil {
+		t.Fatalf("generateShims() error = %v", err)
+	}
+
+	got := string(src)
+	for _, want := range []string{
+		"package compat",
+		`"context"`,
+		`"example.com/lib"`,
+		"func FetchCompat(p0 string) error {\n\treturn lib.Fetch(context.Background(), p0)\n}",
+		"// TODO: result 1 (error) of lib.Name is ignored.",
+		"func NameCompat() string {\n\tr0, _ := lib.Name()\n\treturn r0\n}",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generateShims() missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Join(shims.Functions, ",") != "FetchCompat,NameCompat" {
+		t.Errorf("generateShims() Functions = %v", shims.Functions)
+	}
+	if len(shims.Skipped) != 1 || !strings.HasPrefix(shims.Skipped[0], "T.Do:") {
+		t.Errorf("generateShims() Skipped = %v, want T.Do", shims.Skipped)
+	}
+}
+
+func TestShimPackageName(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.go"), []byte("package app\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if name, err := shimPackageName(dir); err != nil || name != "app" {
+		t.Fatalf("shimPackageName() = %q, %v, want app", name, err)
+	}
+
+	if name, err := shimPackageName(filepath.Join(dir, "semver-shims")); err != nil || name != "semver_shims" {
+		t.Fatalf("shimPackageName() = %q, %v, want semver_shims", name, err)
+	}
+}
+
+func TestWriteShimsDryRun(t *testing.T) {
+	lib := types.NewPackage("example.com/lib", "lib")
+	str := types.Typ[types.String]
+	fn := func(params ...*types.Var) *Function {
+		obj := types.NewFunc(token.NoPos, lib, "Name", newSignature(params, []*types.Var{types.NewVar(token.NoPos, lib, "", str)}))
+		return &Function{Name: "Name", Signature: signatureOf(obj.Type().(*types.Signature)), PkgPath: lib.Path(), obj: obj}
+	}
+	oldAPI, newAPI := emptyAPI(), emptyAPI()
+	oldAPI.Funcs["Name"] = fn()
+	newAPI.Funcs["Name"] = fn(types.NewVar(token.NoPos, lib, "", str))
+	result := &Result{Changes: &Diff{Changed: []ChangedSignature{{Name: "Name"}}}}
+
+	project := t.TempDir()
+	a := &Analyzer{projectPath: project, opts: Options{Shims: "compat", DryRun: true}}
+	shims, err := a.writeShims("compat", result, oldAPI, newAPI)
+	if err != nil {
+		t.Fatalf("writeShims() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(project, "compat")); !os.IsNotExist(err) {
+		t.Fatalf("dry run created the shim directory, stat error = %v", err)
+	}
+	if !shims.DryRun || shims.Diff.File != "compat/"+shimFileName || !strings.Contains(shims.Diff.Diff, "+func NameCompat() string {") {
+		t.Fatalf("writeShims() = %+v, want a previewed file", shims)
+	}
+
+	a.opts.DryRun = false
+	if _, err := a.writeShims("compat", result, oldAPI, newAPI); err != nil {
+		t.Fatalf("writeShims() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(project, "compat", shimFileName))
+	if err != nil || !strings.Contains(string(data), "func NameCompat() string {") {
+		t.Fatalf("written shims = %q, %v", data, err)
+	}
+}
@@ -0,0 +1,174 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"go/types"
+)
+
+// Signature is the structured form of a function or method signature, so
+// parameters and results can be compared one by one. Text keeps the
+// signature as go/types prints it, for display and quick equality checks.
+// Signatures of hand-built APIs and of snapshots written before the
+// structured form existed only have Text.
+type Signature struct {
+	Text       string      `json:"text"`
+	TypeParams []TypeParam `json:"type_params,omitempty"`
+	Params     []Param     `json:"params,omitempty"`
+	Results    []Param     `json:"results,omitempty"`
+	Variadic   bool        `json:"variadic,omitempty"` // the last parameter is ...T, listed with type []T
+}
+
+// Param is a parameter or result of a signature
+type Param struct {
+	Name string `json:"name,omitempty"` // empty for unnamed parameters
+	Type string `json:"type"`
+}
+
+// TypeParam is a type parameter of a generic function
+type TypeParam struct {
+	Name       string `json:"name"`
+	Constraint string `json:"constraint"`
+}
+
+// String returns the signature as go/types prints it
+func (s Signature) String() string {
+	return s.Text
+}
+
+// UnmarshalJSON also accepts the plain signature strings of older snapshots
+func (s *Signature) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*s = Signature{Text: text}
+		return nil
+	}
+	type signature Signature // without this method
+	return json.Unmarshal(data, (*signature)(s))
+}
+
+// signatureOf converts a type-checked signature to its structured form
+func signatureOf(sig *types.Signature) Signature {
+	s := Signature{
+		Text:     sig.String(),
+		Params:   params(sig.Params()),
+		Results:  params(sig.Results()),
+		Variadic: sig.Variadic(),
+	}
+	for i := 0; i < sig.TypeParams().Len(); i++ {
+		tp := sig.TypeParams().At(i)
+		s.TypeParams = append(s.TypeParams, TypeParam{
+			Name:       tp.Obj().Name(),
+			Constraint: types.TypeString(tp.Constraint(), nil),
+		})
+	}
+	return s
+}
+
+// params converts a parameter or result tuple
+func params(tuple *types.Tuple) []Param {
+	var list []Param
+	for i := 0; i < tuple.Len(); i++ {
+		v := tuple.At(i)
+		list = append(list, Param{Name: v.Name(), Type: types.TypeString(v.Type(), nil)})
+	}
+	return list
+}
+
+// Kinds of parameter changes
+const (
+	ParamAdded   = "added"
+	ParamRemoved = "removed"
+	ParamRetyped = "retyped"
+)
+
+// ParamChange is a parameter or result added, removed, or retyped between
+// two signatures
+type ParamChange struct {
+	Kind     string // ParamAdded, ParamRemoved, or ParamRetyped
+	Result   bool   // the change is to a result rather than a parameter
+	Position int    // 1-based; in the old signature for removals, the new one otherwise
+	Name     string // parameter name, empty if unnamed
+	OldType  string // empty for additions
+	NewType  string // empty for removals
+	Variadic bool   // the parameter is a trailing ...T, with type []T
+}
+
+// paramChanges lists how the parameters and results of a signature
+// changed. Lists are aligned on their longest common run of types, so an
+// inserted parameter is reported as an addition rather than as every later
+// parameter being retyped.
+func paramChanges(oldSig, newSig Signature) []ParamChange {
+	changes := alignParams(oldSig.Params, newSig.Params, oldSig.Variadic, newSig.Variadic, false)
+	return append(changes, alignParams(oldSig.Results, newSig.Results, false, false, true)...)
+}
+
+// alignParams diffs two parameter lists
+func alignParams(oldList, newList []Param, oldVariadic, newVariadic, result bool) []ParamChange {
+	// lcs[i][j] is the common run length of oldList[i:] and newList[j:]
+	lcs := make([][]int, len(oldList)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newList)+1)
+	}
+	same := func(i, j int) bool {
+		oldVar := oldVariadic && i == len(oldList)-1
+		newVar := newVariadic && j == len(newList)-1
+		return oldList[i].Type == newList[j].Type && oldVar == newVar
+	}
+	for i := len(oldList) - 1; i >= 0; i-- {
+		for j := len(newList) - 1; j >= 0; j-- {
+			switch {
+			case same(i, j):
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var changes []ParamChange
+	var removed, added []int
+	flush := func() {
+		// Pair the parameters replaced between two matches as retypes
+		for len(removed) > 0 && len(added) > 0 {
+			i, j := removed[0], added[0]
+			changes = append(changes, ParamChange{
+				Kind: ParamRetyped, Result: result, Position: j + 1, Name: newList[j].Name,
+				OldType: oldList[i].Type, NewType: newList[j].Type,
+				Variadic: newVariadic && j == len(newList)-1,
+			})
+			removed, added = removed[1:], added[1:]
+		}
+		for _, i := range removed {
+			changes = append(changes, ParamChange{
+				Kind: ParamRemoved, Result: result, Position: i + 1, Name: oldList[i].Name,
+				OldType: oldList[i].Type, Variadic: oldVariadic && i == len(oldList)-1,
+			})
+		}
+		for _, j := range added {
+			changes = append(changes, ParamChange{
+				Kind: ParamAdded, Result: result, Position: j + 1, Name: newList[j].Name,
+				NewType: newList[j].Type, Variadic: newVariadic && j == len(newList)-1,
+			})
+		}
+		removed, added = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < len(oldList) || j < len(newList) {
+		switch {
+		case i < len(oldList) && j < len(newList) && same(i, j):
+			flush()
+			i, j = i+1, j+1
+		case j == len(newList) || (i < len(oldList) && lcs[i+1][j] >= lcs[i][j+1]):
+			removed = append(removed, i)
+			i++
+		default:
+			added = append(added, j)
+			j++
+		}
+	}
+	flush()
+	return changes
+}
@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"go/types"
+	"reflect"
+	"testing"
+)
+
+func TestSignatureOf(t *testing.T) {
+	pkg := checkSource(t, "example.com/lib", `package lib
+
+func Copy(dst []byte, src string, opts ...string) (n int, err error) { return 0, nil }
+
+func Map[K comparable, V any](m map[K]V) []V { return nil }
+`, nil)
+
+	copyFn := pkg.Types.Scope().Lookup("Copy").(*types.Func)
+	got := signatureOf(copyFn.Type().(*types.Signature))
+	want := Signature{
+		Text:     "func(dst []byte, src string, opts ...string) (n int, err error)",
+		Params:   []Param{{Name: "dst", Type: "[]byte"}, {Name: "src", Type: "string"}, {Name: "opts", Type: "[]string"}},
+		Results:  []Param{{Name: "n", Type: "int"}, {Name: "err", Type: "error"}},
+		Variadic: true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("signatureOf(Copy) = %+v, want %+v", got, want)
+	}
+
+	mapFn := pkg.Types.Scope().Lookup("Map").(*types.Func)
+	got = signatureOf(mapFn.Type().(*types.Signature))
+	wantTypeParams := []TypeParam{{Name: "K", Constraint: "comparable"}, {Name: "V", Constraint: "any"}}
+	if !reflect.DeepEqual(got.TypeParams, wantTypeParams) || got.String() != got.Text {
+		t.Errorf("signatureOf(Map) = %+v, want type params %+v", got, wantTypeParams)
+	}
+}
+
+func TestSignatureJSON(t *testing.T) {
+	sig := Signature{Text: "func(s string) error", Params: []Param{{Name: "s", Type: "string"}}, Results: []Param{{Type: "error"}}}
+	data, err := json.Marshal(sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Signature
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal(%s) error = %v", data, err)
+	}
+	if !reflect.DeepEqual(decoded, sig) {
+		t.Errorf("round trip = %+v, want %+v", decoded, sig)
+	}
+
+	// Snapshots written before the structured form hold plain strings
+	if err := json.Unmarshal([]byte(`"func()"`), &decoded); err != nil || !reflect.DeepEqual(decoded, Signature{Text: "func()"}) {
+		t.Errorf("Unmarshal of a plain string = %+v, %v", decoded, err)
+	}
+}
+
+func TestParamChanges(t *testing.T) {
+	p := func(name, typ string) Param { return Param{Name: name, Type: typ} }
+	tests := []struct {
+		name     string
+		old, new Signature
+		want     []ParamChange
+	}{
+		{
+			name: "leading context added",
+			old:  Signature{Params: []Param{p("id", "string")}},
+			new:  Signature{Params: []Param{p("ctx", "context.Context"), p("id", "string")}},
+			want: []ParamChange{{Kind: ParamAdded, Position: 1, Name: "ctx", NewType: "context.Context"}},
+		},
+		{
+			name: "param retyped",
+			old:  Signature{Params: []Param{p("s", "string"), p("n", "int")}},
+			new:  Signature{Params: []Param{p("s", "string"), p("n", "int64")}},
+			want: []ParamChange{{Kind: ParamRetyped, Position: 2, Name: "n", OldType: "int", NewType: "int64"}},
+		},
+		{
+			name: "param removed and result added",
+			old:  Signature{Params: []Param{p("a", "int"), p("b", "bool"), p("c", "int")}, Results: []Param{p("", "int")}},
+			new:  Signature{Params: []Param{p("a", "int"), p("c", "int")}, Results: []Param{p("", "int"), p("", "error")}},
+			want: []ParamChange{
+				{Kind: ParamRemoved, Position: 2, Name: "b", OldType: "bool"},
+				{Kind: ParamAdded, Result: true, Position: 2, NewType: "error"},
+			},
+		},
+		{
+			name: "variadic options added",
+			old:  Signature{Params: []Param{p("addr", "string")}},
+			new:  Signature{Params: []Param{p("addr", "string"), p("opts", "[]Option")}, Variadic: true},
+			want: []ParamChange{{Kind: ParamAdded, Position: 2, Name: "opts", NewType: "[]Option", Variadic: true}},
+		},
+		{
+			name: "slice made variadic",
+			old:  Signature{Params: []Param{p("xs", "[]int")}},
+			new:  Signature{Params: []Param{p("xs", "[]int")}, Variadic: true},
+			want: []ParamChange{{Kind: ParamRetyped, Position: 1, Name: "xs", OldType: "[]int", NewType: "[]int", Variadic: true}},
+		},
+		{
+			name: "names only",
+			old:  Signature{Params: []Param{p("a", "int")}},
+			new:  Signature{Params: []Param{p("b", "int")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := paramChanges(tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("paramChanges() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Snapshot is the exported API of one module version, as printed by the api
+// subcommand and archived for later comparison
+type Snapshot struct {
+	Module  string `json:"module"`
+	Version string `json:"version"`
+	API     *API   `json:"api"`
+}
+
+// Snapshot extracts the exported API of module@version. Queries such as
+// "latest" are resolved to the version they stand for.
+func (a *Analyzer) Snapshot(module, version string) (*Snapshot, error) {
+	a = a.call()
+	info, err := a.downloadModule(module, version)
+	if err != nil {
+		return nil, err
+	}
+	api, err := a.loadModuleAPI(module, info.Version)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{Module: module, Version: info.Version, API: api}, nil
+}
+
+// ReadSnapshot reads an API snapshot written by the api subcommand with -json
+func ReadSnapshot(path string) (*Snapshot, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", path, err)
+	}
+	if snap.API == nil {
+		return nil, fmt.Errorf("%s is not an API snapshot", path)
+	}
+	return &snap, nil
+}
+
+// DiffSnapshots compares two archived API snapshots without a project, so
+// every breaking change is reported rather than only those the project uses
+func DiffSnapshots(oldSnap, newSnap *Snapshot) *Result {
+	return &Result{
+		Module:     newSnap.Module,
+		OldVersion: oldSnap.Version,
+		NewVersion: newSnap.Version,
+		Changes:    diffAPIs(oldSnap.API, newSnap.API, &Usage{All: true}),
+	}
+}
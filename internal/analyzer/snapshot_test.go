@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestSnapshot(t *testing.T) {
+	lib := checkSource(t, "example.com/lib", `package lib
+
+// MaxRetries bounds retries.
+const MaxRetries = 3
+
+const (
+	ModeA Mode = iota
+	ModeB
+)
+
+type Mode int
+
+const internal = "x"
+
+func Open() {}
+`, nil)
+
+	var downloaded string
+	restoreCmd := mockGoCommand(func(_ string, args ...string) ([]byte, error) {
+		downloaded = args[len(args)-1]
+		return json.Marshal(moduleDownload{Path: "example.com/lib", Version: "v1.2.0"})
+	})
+	defer restoreCmd()
+	var loaded string
+	restoreLoad := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		loaded = strings.Join(patterns, " ")
+		return []*packages.Package{lib}, nil
+	})
+	defer restoreLoad()
+
+	a := &Analyzer{projectPath: t.TempDir()}
+	snap, err := a.Snapshot("example.com/lib", "latest")
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if downloaded != "example.com/lib@latest" || snap.Version != "v1.2.0" {
+		t.Errorf("Snapshot() resolved %s to %s", downloaded, snap.Version)
+	}
+	if !strings.Contains(loaded, "example.com/lib/...@v1.2.0") {
+		t.Errorf("loaded %q, want the resolved version", loaded)
+	}
+	if snap.API.Funcs["Open"] == nil {
+		t.Errorf("Snapshot() funcs = %v", snap.API.Funcs)
+	}
+
+	consts := snap.API.Consts
+	if len(consts) != 3 || consts["internal"] != nil {
+		t.Fatalf("Snapshot() consts = %v, want the 3 exported constants", consts)
+	}
+	if c := consts["MaxRetries"]; c.Type != "untyped int" || c.Value != "3" || c.Doc != "MaxRetries bounds retries.\n" {
+		t.Errorf("MaxRetries = %+v", c)
+	}
+	if c := consts["ModeB"]; c.Type != "example.com/lib.Mode" || c.Value != "1" {
+		t.Errorf("ModeB = %+v", c)
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	oldSnap := &Snapshot{Module: "example.com/lib", Version: "v1.0.0", API: &API{
+		Funcs: map[string]*Function{
+			"Open":  {Name: "Open", Signature: Signature{Text: "func()"}, PkgPath: "example.com/lib"},
+			"Close": {Name: "Close", Signature: Signature{Text: "func()"}, PkgPath: "example.com/lib"},
+		},
+		Interfaces: map[string]*Interface{"Doer": {Name: "Doer", Methods: []string{"Do()"}, PkgPath: "example.com/lib"}},
+	}}
+	newSnap := &Snapshot{Module: "example.com/lib", Version: "v1.1.0", API: &API{
+		Funcs: map[string]*Function{
+			"Open":  {Name: "Open", Signature: Signature{Text: "func(string)"}, PkgPath: "example.com/lib"},
+			"Flush": {Name: "Flush", Signature: Signature{Text: "func()"}, PkgPath: "example.com/lib"},
+		},
+		Interfaces: map[string]*Interface{"Doer": {Name: "Doer", Methods: []string{"Do()", "Undo()"}, PkgPath: "example.com/lib"}},
+	}}
+
+	result := DiffSnapshots(oldSnap, newSnap)
+	if result.OldVersion != "v1.0.0" || result.NewVersion != "v1.1.0" {
+		t.Errorf("DiffSnapshots() versions = %s -> %s", result.OldVersion, result.NewVersion)
+	}
+	changes := result.Changes
+	if len(changes.Removed) != 1 || changes.Removed[0].Name != "Close" {
+		t.Errorf("Removed = %+v, want unused Close reported", changes.Removed)
+	}
+	if len(changes.Changed) != 1 || changes.Changed[0].Name != "Open" {
+		t.Errorf("Changed = %+v", changes.Changed)
+	}
+	if len(changes.InterfaceChanges) != 1 || len(changes.Added) != 1 {
+		t.Errorf("InterfaceChanges = %+v, Added = %+v", changes.InterfaceChanges, changes.Added)
+	}
+	if got := changes.BreakingCount(); got != 3 {
+		t.Errorf("BreakingCount() = %d, want 3", got)
+	}
+}
+
+func TestReadSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "v1.json")
+	writeFile(t, path, `{"module": "example.com/lib", "version": "v1.0.0", "api": {"funcs": {"Open": {"name": "Open", "signature": "func()", "pkg_path": "example.com/lib"}}}}`)
+	snap, err := ReadSnapshot(path)
+	if err != nil {
+		t.Fatalf("ReadSnapshot() error = %v", err)
+	}
+	if snap.Version != "v1.0.0" || snap.API.Funcs["Open"].Signature.Text != "func()" {
+		t.Errorf("ReadSnapshot() = %+v", snap)
+	}
+
+	writeFile(t, path, `{"module": "example.com/lib"}`)
+	if _, err := ReadSnapshot(path); err == nil {
+		t.Error("ReadSnapshot() without an API should fail")
+	}
+	if _, err := ReadSnapshot(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("ReadSnapshot() of a missing file should fail")
+	}
+}
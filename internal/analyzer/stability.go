@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// unstableDocPattern matches the explicit markers of a knowingly unstable API
+// in a doc comment: a line starting with "Experimental:", "Unstable:", or
+// "Alpha:" in the style of "Deprecated:", an "# Experimental" heading, or an
+// all-caps EXPERIMENTAL or UNSTABLE notice. Merely mentioning the words does
+// not count.
+var unstableDocPattern = regexp.MustCompile(`(?m)^(?:(?:Experimental|Unstable|Alpha):|# (?:Experimental|Unstable)\s*$)|\b(?:EXPERIMENTAL|UNSTABLE)\b`)
+
+// unstableVersionElem matches path elements of pre-release API versions, as in
+// k8s.io/api/flowcontrol/v1alpha1
+var unstableVersionElem = regexp.MustCompile(`^v\d+alpha\d*$`)
+
+// isUnstablePath reports whether a package path follows a convention for
+// unstable APIs: internal, experimental, exp, unstable, or alpha path
+// elements, or alpha API versions such as v1alpha1
+func isUnstablePath(pkgPath string) bool {
+	for _, elem := range strings.Split(pkgPath, "/") {
+		switch elem {
+		case "internal", "experimental", "exp", "unstable", "alpha":
+			return true
+		}
+		if unstableVersionElem.MatchString(elem) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnstableDoc reports whether a doc comment explicitly marks its symbol as unstable
+func isUnstableDoc(doc string) bool {
+	return unstableDocPattern.MatchString(doc)
+}
+
+// collectDocs maps the position of each top-level declared name to its doc comment
+func collectDocs(pkg *packages.Package) map[token.Pos]string {
+	docs := make(map[token.Pos]string)
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				docs[decl.Name.Pos()] = decl.Doc.Text()
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						docs[spec.Name.Pos()] = specDoc(spec.Doc, decl.Doc)
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							docs[name.Pos()] = specDoc(spec.Doc, decl.Doc)
+						}
+					}
+				}
+			}
+		}
+	}
+	return docs
+}
+
+// specDoc prefers a spec's own doc comment over its enclosing declaration's
+func specDoc(spec, decl *ast.CommentGroup) string {
+	if spec != nil {
+		return spec.Text()
+	}
+	return decl.Text()
+}
+
+// packageUnstable reports whether a whole package is marked unstable by path
+// or by an explicit marker in its package doc
+func packageUnstable(pkg *packages.Package) bool {
+	if isUnstablePath(pkg.PkgPath) {
+		return true
+	}
+	for _, file := range pkg.Syntax {
+		if file.Doc != nil && isUnstableDoc(file.Doc.Text()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestIsUnstablePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"example.com/lib", false},
+		{"example.com/lib/internal/codec", true},
+		{"example.com/lib/experimental/fast", true},
+		{"golang.org/x/exp/slices", true},
+		{"k8s.io/api/flowcontrol/v1alpha1", true},
+		{"example.com/lib/internals", false},
+		{"example.com/alphabet", false},
+		{"example.com/lib/alpha", true},
+		{"example.com/lib/v2alpha", true},
+		{"example.com/lib/alphav1", false},
+		{"example.com/lib/expression", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := isUnstablePath(tt.path); got != tt.want {
+				t.Errorf("isUnstablePath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsUnstableDoc(t *testing.T) {
+	tests := []struct {
+		doc  string
+		want bool
+	}{
+		{"Parse parses input.", false},
+		{"Fast is EXPERIMENTAL and may change.", true},
+		{"Experimental: this API is unstable.", true},
+		{"Codec encodes values.\n\nUnstable: the wire format may change.", true},
+		{"# Experimental\n\nNotice: may be removed.", true},
+		{"Stable returns a stable sort.", false},
+		{"Retry is no longer experimental.", false},
+		{"Sort uses the alpha channel.", false},
+		{"Experimental returns whether experimental features are enabled.", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.doc, func(t *testing.T) {
+			if got := isUnstableDoc(tt.doc); got != tt.want {
+				t.Errorf("isUnstableDoc(%q) = %v, want %v", tt.doc, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectDocs(t *testing.T) {
+	const src = `package lib
+
+// Parse parses things.
+func Parse() {}
+
+// Group doc.
+type (
+	// Fast is experimental.
+	Fast struct{}
+	Slow struct{}
+)
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "lib.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs := collectDocs(&packages.Package{Syntax: []*ast.File{file}})
+
+	byName := make(map[string]string)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			if doc, ok := docs[ident.Pos()]; ok {
+				byName[ident.Name] = doc
+			}
+		}
+		return true
+	})
+
+	if byName["Parse"] != "Parse parses things.\n" {
+		t.Errorf("collectDocs() Parse = %q", byName["Parse"])
+	}
+	if byName["Fast"] != "Fast is experimental.\n" {
+		t.Errorf("collectDocs() Fast = %q", byName["Fast"])
+	}
+	if byName["Slow"] != "Group doc.\n" {
+		t.Errorf("collectDocs() Slow = %q, want enclosing declaration doc", byName["Slow"])
+	}
+}
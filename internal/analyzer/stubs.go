@@ -0,0 +1,323 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// contextSuffixes name the context-aware variants of methods, such as
+// QueryContext for Query, which stubs can implement by delegating
+var contextSuffixes = []string{"WithContext", "Context", "Ctx"}
+
+// stubFixes returns the fixes declaring the methods project types lack to
+// keep satisfying interfaces that gained them. Each stub returns an error
+// saying it is not implemented, or panics when it has no error result; a
+// context-aware variant of a method the type has, such as QueryContext next
+// to Query, delegates to it instead. Types are found by their package name,
+// so types of the same name in several packages are stubbed alike.
+func (a *Analyzer) stubFixes(diff *Diff, newAPI *API) []pendingFix {
+	var fixes []pendingFix
+	for _, ic := range diff.InterfaceChanges {
+		iface := newAPI.Interfaces[ic.Name]
+		if iface == nil || iface.obj == nil {
+			continue
+		}
+		seen := make(map[*types.TypeName]bool)
+		for _, impl := range ic.Implementations {
+			if !impl.Broken() {
+				continue // *T still satisfies it, so T has the methods
+			}
+			for _, pkg := range a.pkgs {
+				if pkg.Types == nil || !strings.HasPrefix(impl.Type, pkg.Types.Name()+".") {
+					continue
+				}
+				obj, ok := pkg.Types.Scope().Lookup(strings.TrimPrefix(impl.Type, pkg.Types.Name()+".")).(*types.TypeName)
+				if !ok || seen[obj] {
+					continue
+				}
+				seen[obj] = true
+				pos := pkg.Fset.Position(obj.Pos())
+				loc := Location{File: pos.Filename, Line: pos.Line, Column: pos.Column}
+				fixes = append(fixes, pendingFix{ic.Name, loc, stubMethods(obj, iface.obj, strings.HasPrefix(impl.Before, "*"))})
+			}
+		}
+	}
+	return fixes
+}
+
+// stubMethods returns a fix declaring the methods of iface that the project
+// type lacks right after the type's declaration, with pointer receivers when
+// *T is what satisfied the interface
+func stubMethods(obj, iface *types.TypeName, pointer bool) func(src []byte, fset *token.FileSet, file *ast.File) (*rewrite, error) {
+	return func(src []byte, fset *token.FileSet, file *ast.File) (*rewrite, error) {
+		decl := typeDecl(file, obj.Name())
+		if decl == nil {
+			return nil, fmt.Errorf("declaration of %s not found", obj.Name())
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a defined type", obj.Name())
+		}
+
+		have := make(map[string]*types.Func)
+		mset := types.NewMethodSet(types.NewPointer(named))
+		for i := 0; i < mset.Len(); i++ {
+			fn := mset.At(i).Obj().(*types.Func)
+			have[fn.Name()] = fn
+		}
+		var missing []*types.Func
+		required := iface.Type().Underlying().(*types.Interface)
+		for i := 0; i < required.NumMethods(); i++ {
+			method := required.Method(i)
+			if fn := have[method.Name()]; fn != nil {
+				if signatureShape(fn.Type().(*types.Signature), nil) != signatureShape(method.Type().(*types.Signature), nil) {
+					return nil, fmt.Errorf("%s has %s with another signature", obj.Name(), method.Name())
+				}
+				continue
+			}
+			missing = append(missing, method)
+		}
+		if len(missing) == 0 {
+			return nil, fmt.Errorf("%s has every method of %s", obj.Name(), iface.Name())
+		}
+
+		b := newStubBuilder(file, obj.Pkg())
+		recv := receiverOf(named)
+		recvType := obj.Name()
+		if pointer {
+			recvType = "*" + recvType
+		}
+		ifaceName := iface.Pkg().Name() + "." + iface.Name()
+
+		var text strings.Builder
+		var added []string
+		for _, method := range missing {
+			stub, delegate := b.method(recv, recvType, ifaceName, method, have)
+			text.WriteString("\n\n" + stub)
+			if delegate != "" {
+				added = append(added, fmt.Sprintf("%s (delegating to %s)", method.Name(), delegate))
+			} else {
+				added = append(added, method.Name())
+			}
+		}
+		if b.err != nil {
+			return nil, b.err
+		}
+
+		end := fset.Position(decl.End()).Offset
+		return &rewrite{
+			desc:    fmt.Sprintf("add %s to %s", strings.Join(added, ", "), recvType),
+			edits:   []textEdit{{end, end, text.String()}},
+			imports: b.added,
+		}, nil
+	}
+}
+
+// typeDecl returns the top-level declaration of the named type
+func typeDecl(file *ast.File, name string) *ast.GenDecl {
+	for _, d := range file.Decls {
+		decl, ok := d.(*ast.GenDecl)
+		if !ok || decl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range decl.Specs {
+			if spec.(*ast.TypeSpec).Name.Name == name {
+				return decl
+			}
+		}
+	}
+	return nil
+}
+
+// receiverOf returns the receiver name the type's methods already use, or
+// the lowercased first letter of its name
+func receiverOf(named *types.Named) string {
+	for i := 0; i < named.NumMethods(); i++ {
+		if recv := named.Method(i).Type().(*types.Signature).Recv(); recv != nil && recv.Name() != "" && recv.Name() != "_" {
+			return recv.Name()
+		}
+	}
+	r, _ := utf8.DecodeRuneInString(named.Obj().Name())
+	return string(unicode.ToLower(r))
+}
+
+// stubBuilder renders stub methods into a project file, naming packages the
+// way the file imports them and recording the imports it has to add
+type stubBuilder struct {
+	pkg   *types.Package    // the project package
+	names map[string]string // import path -> name in the file
+	added []string          // import paths the stubs need
+	err   error
+}
+
+func newStubBuilder(file *ast.File, pkg *types.Package) *stubBuilder {
+	b := &stubBuilder{pkg: pkg, names: make(map[string]string)}
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		switch {
+		case spec.Name != nil:
+			b.names[path] = spec.Name.Name
+		default:
+			for _, imp := range pkg.Imports() {
+				if imp.Path() == path {
+					b.names[path] = imp.Name()
+				}
+			}
+		}
+	}
+	return b
+}
+
+// qualifier is a types.Qualifier for the project file, importing packages
+// it does not import yet
+func (b *stubBuilder) qualifier(pkg *types.Package) string {
+	if pkg == nil || pkg.Path() == b.pkg.Path() {
+		return ""
+	}
+	return b.use(pkg.Path(), pkg.Name())
+}
+
+// use returns the name the file knows path by, importing it if needed
+func (b *stubBuilder) use(path, name string) string {
+	if n, ok := b.names[path]; ok {
+		switch n {
+		case ".":
+			return ""
+		case "_":
+			b.err = fmt.Errorf("%s is imported as _", path)
+		}
+		return n
+	}
+	b.names[path] = name
+	b.added = append(b.added, path)
+	return name
+}
+
+// method renders a stub of method on the receiver. It returns the name of
+// the method it delegates to, if any.
+func (b *stubBuilder) method(recv, recvType, ifaceName string, method *types.Func, have map[string]*types.Func) (string, string) {
+	sig := method.Type().(*types.Signature)
+
+	// Parameter names must not shadow the receiver or the packages in use
+	typeStrings := make([]string, sig.Params().Len())
+	for i := range typeStrings {
+		t := sig.Params().At(i).Type()
+		if sig.Variadic() && i == len(typeStrings)-1 {
+			typeStrings[i] = "..." + types.TypeString(t.(*types.Slice).Elem(), b.qualifier)
+		} else {
+			typeStrings[i] = types.TypeString(t, b.qualifier)
+		}
+	}
+	results := b.results(sig.Results())
+	taken := map[string]bool{recv: true, "errors": true}
+	for _, name := range b.names {
+		taken[name] = true
+	}
+	params := make([]string, len(typeStrings))
+	args := make([]string, len(typeStrings))
+	for i, typ := range typeStrings {
+		name := sig.Params().At(i).Name()
+		if name == "" || name == "_" || taken[name] {
+			name = fmt.Sprintf("p%d", i)
+		}
+		taken[name] = true
+		params[i] = name + " " + typ
+		args[i] = name
+		if strings.HasPrefix(typ, "...") {
+			args[i] += "..."
+		}
+	}
+
+	head := fmt.Sprintf("func (%s %s) %s(%s) %s", recv, recvType, method.Name(), strings.Join(params, ", "), results)
+	if target := delegateTarget(method, have); target != "" {
+		call := fmt.Sprintf("%s.%s(%s)", recv, target, strings.Join(args[1:], ", "))
+		if sig.Results().Len() > 0 {
+			call = "return " + call
+		}
+		doc := fmt.Sprintf("// %s satisfies %s by calling %s without the context.\n// TODO: pass the context on once %s accepts one.\n", method.Name(), ifaceName, target, target)
+		return fmt.Sprintf("%s%s {\n%s\n}", doc, head, call), target
+	}
+
+	body := `panic("not implemented")`
+	if n := sig.Results().Len(); n > 0 && types.TypeString(sig.Results().At(n-1).Type(), nil) == "error" {
+		values := make([]string, n)
+		for i := 0; i < n-1; i++ {
+			values[i] = b.zero(sig.Results().At(i).Type())
+		}
+		values[n-1] = b.use("errors", "errors") + `.New("not implemented")`
+		body = "return " + strings.Join(values, ", ")
+	}
+	doc := fmt.Sprintf("// %s is required by %s.\n// TODO: implement %s.\n", method.Name(), ifaceName, method.Name())
+	return fmt.Sprintf("%s%s {\n%s\n}", doc, head, body), ""
+}
+
+// results renders a result list for a method declaration
+func (b *stubBuilder) results(tuple *types.Tuple) string {
+	parts := make([]string, tuple.Len())
+	for i := range parts {
+		parts[i] = types.TypeString(tuple.At(i).Type(), b.qualifier)
+	}
+	switch len(parts) {
+	case 0:
+		return ""
+	case 1:
+		return parts[0]
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// zero renders the zero value of a type
+func (b *stubBuilder) zero(t types.Type) string {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "false"
+		case u.Info()&types.IsString != 0:
+			return `""`
+		case u.Info()&types.IsNumeric != 0:
+			return "0"
+		}
+		return "nil"
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		return "nil"
+	}
+	return types.TypeString(t, b.qualifier) + "{}"
+}
+
+// delegateTarget returns the method of the type that a context-aware method
+// can call, such as Query for QueryContext(ctx, q) when Query(q) has the
+// same results, or "" when there is none
+func delegateTarget(method *types.Func, have map[string]*types.Func) string {
+	sig := method.Type().(*types.Signature)
+	if sig.Params().Len() == 0 || types.TypeString(sig.Params().At(0).Type(), nil) != "context.Context" {
+		return ""
+	}
+	for _, suffix := range contextSuffixes {
+		name, ok := strings.CutSuffix(method.Name(), suffix)
+		target := have[name]
+		if !ok || name == "" || target == nil {
+			continue
+		}
+		tsig := target.Type().(*types.Signature)
+		if tsig.Variadic() != sig.Variadic() || tsig.Params().Len() != sig.Params().Len()-1 || !sameTypes(tsig.Results(), sig.Results()) {
+			continue
+		}
+		same := true
+		for i := 0; i < tsig.Params().Len(); i++ {
+			if types.TypeString(tsig.Params().At(i).Type(), nil) != types.TypeString(sig.Params().At(i+1).Type(), nil) {
+				same = false
+			}
+		}
+		if same {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,145 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// checkFile type-checks a project file on disk, so fixes can rewrite it
+func checkFile(t *testing.T, path, pkgPath string, deps map[string]*types.Package) *packages.Package {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := types.Config{Importer: importerFunc(func(path string) (*types.Package, error) {
+		if pkg, ok := deps[path]; ok {
+			return pkg, nil
+		}
+		return nil, fmt.Errorf("unknown import %s", path)
+	})}
+	pkg, err := conf.Check(pkgPath, fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &packages.Package{PkgPath: pkgPath, Fset: fset, Syntax: []*ast.File{file}, Types: pkg}
+}
+
+func TestFixSites_InterfaceStubs(t *testing.T) {
+	ctx := checkSource(t, "context", "package context\n\ntype Context interface{ Done() <-chan struct{} }\n", nil)
+	errs := checkSource(t, "errors", "package errors\n\nfunc New(text string) error { return nil }\n", nil)
+	oldLib := checkSource(t, "example.com/lib", `package lib
+
+type Record struct{ Key string }
+
+type Store interface {
+	Get(key string) (string, error)
+	Put(r Record) error
+}
+`, nil)
+	newLib := checkSource(t, "example.com/lib", `package lib
+
+import "context"
+
+type Record struct{ Key string }
+
+type Stats struct{ Rows int }
+
+type Store interface {
+	Get(key string) (string, error)
+	GetContext(ctx context.Context, key string) (string, error)
+	Put(r Record) error
+	Stats() (Stats, int, error)
+	Flush()
+	Close(db bool) error
+}
+`, map[string]*types.Package{"context": ctx.Types})
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "db.go")
+	src := `package app
+
+import "example.com/lib"
+
+// DB stores records in memory.
+type DB struct {
+	rows map[string]string
+}
+
+func (db *DB) Get(key string) (string, error) { return db.rows[key], nil }
+
+func (db *DB) Put(r lib.Record) error { return nil }
+
+// Cache has a Close of its own.
+type Cache struct{}
+
+func (Cache) Get(key string) (string, error) { return "", nil }
+
+func (Cache) Put(r lib.Record) error { return nil }
+
+func (Cache) Close() error { return nil }
+`
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	app := checkFile(t, path, "example.com/app", map[string]*types.Package{"example.com/lib": oldLib.Types})
+
+	diff := &Diff{InterfaceChanges: []InterfaceChange{{
+		Name:         "Store",
+		AddedMethods: []string{"Close", "Flush", "GetContext", "Stats"},
+		Implementations: []Implementation{
+			{Type: "app.DB", Before: "*app.DB", Missing: []string{"Close", "Flush", "GetContext", "Stats"}},
+			{Type: "app.Cache", Before: "app.Cache", Missing: []string{"Close", "Flush", "GetContext", "Stats"}},
+		},
+	}}}
+	a := &Analyzer{projectPath: dir, pkgs: []*packages.Package{app}, opts: Options{Fix: true}}
+	fixes, err := a.fixSites(diff, extractAPI([]*packages.Package{newLib}))
+	if err != nil {
+		t.Fatalf("fixSites() error = %v", err)
+	}
+
+	if len(fixes.Fixed) != 1 || fixes.Fixed[0].Fix != "add Close, Flush, GetContext (delegating to Get), Stats to *DB" ||
+		fixes.Fixed[0].Location.Line != 6 {
+		t.Errorf("Fixed = %+v, want DB at line 6 stubbed", fixes.Fixed)
+	}
+	if len(fixes.Skipped) != 1 || fixes.Skipped[0].Reason != "Cache has Close with another signature" {
+		t.Errorf("Skipped = %+v, want Cache skipped for its own Close", fixes.Skipped)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"import (\n\t\"context\"\n\t\"errors\"\n\t\"example.com/lib\"\n)\n",
+		"\trows map[string]string\n}\n\n// Close is required by lib.Store.\n// TODO: implement Close.\nfunc (db *DB) Close(p0 bool) error {\n\treturn errors.New(\"not implemented\")\n}\n",
+		"func (db *DB) Flush() {\n\tpanic(\"not implemented\")\n}\n",
+		"// GetContext satisfies lib.Store by calling Get without the context.\n",
+		"func (db *DB) GetContext(ctx context.Context, key string) (string, error) {\n\treturn db.Get(key)\n}\n",
+		"func (db *DB) Stats() (lib.Stats, int, error) {\n\treturn lib.Stats{}, 0, errors.New(\"not implemented\")\n}\n",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("stubbed file does not contain %q:\n%s", want, got)
+		}
+	}
+
+	// The stubbed type satisfies the new interface
+	stubbed := checkFile(t, path, "example.com/app", map[string]*types.Package{
+		"context": ctx.Types, "errors": errs.Types, "example.com/lib": newLib.Types,
+	})
+	db := stubbed.Types.Scope().Lookup("DB").Type()
+	store := newLib.Types.Scope().Lookup("Store").Type().Underlying().(*types.Interface)
+	if !types.Implements(types.NewPointer(db), store) {
+		t.Errorf("*DB does not implement the new Store:\n%s", got)
+	}
+}
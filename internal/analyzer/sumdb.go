@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// defaultSumDB is the checksum database the go command consults when GOSUMDB
+// is unset
+const defaultSumDB = "sum.golang.org"
+
+// SumDBStatus records whether the go command authenticated the new version
+// of the module against a checksum database
+type SumDBStatus struct {
+	Version  string
+	Sum      string // go.sum h1: hash of the module zip
+	Database string // checksum database the version was verified against, empty when bypassed
+	Bypass   string // setting that skipped verification, such as GOSUMDB=off or GOPRIVATE=*.corp.example.com
+}
+
+// Verified reports whether the version was checked against a checksum database
+func (s *SumDBStatus) Verified() bool { return s.Database != "" }
+
+// checkSumDB records how the new version of the module was authenticated.
+// The go command refuses to download a version whose hash the checksum
+// database disagrees with, so a successful download either verified it or
+// was configured to skip verification for the module.
+func (a *Analyzer) checkSumDB(upgrade *Upgrade) (*SumDBStatus, error) {
+	out, err := runGoCommand(a.projectPath, "env", "-json", "GOSUMDB", "GONOSUMDB", "GOPRIVATE")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go env: %w", err)
+	}
+	var env map[string]string
+	if err := json.Unmarshal(out, &env); err != nil {
+		return nil, fmt.Errorf("failed to parse go env: %w", err)
+	}
+	info, err := a.downloadModule(upgrade.Module, upgrade.NewVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &SumDBStatus{Version: upgrade.NewVersion, Sum: info.Sum}
+	switch {
+	case env["GOSUMDB"] == "off":
+		status.Bypass = "GOSUMDB=off"
+	case env["GOPRIVATE"] != "" && module.MatchPrefixPatterns(env["GOPRIVATE"], upgrade.Module):
+		status.Bypass = "GOPRIVATE=" + env["GOPRIVATE"]
+	case env["GONOSUMDB"] != "" && module.MatchPrefixPatterns(env["GONOSUMDB"], upgrade.Module):
+		status.Bypass = "GONOSUMDB=" + env["GONOSUMDB"]
+	default:
+		// GOSUMDB may carry a key and URL after the database name
+		status.Database = defaultSumDB
+		if fields := strings.Fields(env["GOSUMDB"]); len(fields) > 0 {
+			status.Database, _, _ = strings.Cut(fields[0], "+")
+		}
+	}
+	return status, nil
+}
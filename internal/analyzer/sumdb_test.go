@@ -0,0 +1,76 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckSumDB(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want SumDBStatus
+	}{
+		{
+			name: "default database",
+			env:  map[string]string{"GOSUMDB": "", "GONOSUMDB": "", "GOPRIVATE": ""},
+			want: SumDBStatus{Database: "sum.golang.org"},
+		},
+		{
+			name: "custom database with key",
+			env:  map[string]string{"GOSUMDB": "sum.corp.example.com+abc123 https://sum.corp.example.com"},
+			want: SumDBStatus{Database: "sum.corp.example.com"},
+		},
+		{
+			name: "disabled",
+			env:  map[string]string{"GOSUMDB": "off"},
+			want: SumDBStatus{Bypass: "GOSUMDB=off"},
+		},
+		{
+			name: "private module",
+			env:  map[string]string{"GOPRIVATE": "*.corp.example.com,example.com/lib"},
+			want: SumDBStatus{Bypass: "GOPRIVATE=*.corp.example.com,example.com/lib"},
+		},
+		{
+			name: "excluded from the database",
+			env:  map[string]string{"GONOSUMDB": "example.com"},
+			want: SumDBStatus{Bypass: "GONOSUMDB=example.com"},
+		},
+		{
+			name: "other private modules",
+			env:  map[string]string{"GOPRIVATE": "example.org"},
+			want: SumDBStatus{Database: "sum.golang.org"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restore := mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+				switch strings.Join(args[:2], " ") {
+				case "env -json":
+					return json.Marshal(tt.env)
+				case "mod download":
+					return []byte(`{"Path":"example.com/lib","Version":"v1.1.0","Sum":"h1:abc="}`), nil
+				}
+				return nil, fmt.Errorf("unexpected command %v", args)
+			})
+			defer restore()
+
+			a := &Analyzer{projectPath: "."}
+			got, err := a.checkSumDB(&Upgrade{Module: "example.com/lib", OldVersion: "v1.0.0", NewVersion: "v1.1.0"})
+			if err != nil {
+				t.Fatalf("checkSumDB() error = %v", err)
+			}
+			want := tt.want
+			want.Version, want.Sum = "v1.1.0", "h1:abc="
+			if !reflect.DeepEqual(*got, want) {
+				t.Errorf("checkSumDB() = %+v, want %+v", *got, want)
+			}
+			if got.Verified() != (want.Database != "") {
+				t.Errorf("Verified() = %v", got.Verified())
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// testEvent mirrors the fields of `go test -json` events that we use
+type testEvent struct {
+	Action  string
+	Package string
+	Test    string
+}
+
+// buildFailedLine matches the line go test prints instead of JSON events for
+// a package that does not compile, such as "FAIL\texample.com/app [build failed]"
+var buildFailedLine = regexp.MustCompile(`^FAIL\t(\S+) \[(?:build|setup) failed\]$`)
+
+// runProjectTests runs the project's tests against the current and the upgraded
+// dependency version and returns the failures that only occur after the upgrade,
+// along with the failures of the current version. Packages that stop compiling
+// with the upgrade are failures with an empty test name.
+func (a *Analyzer) runProjectTests(module, version string) ([]TestFailure, map[TestFailure]bool, error) {
+	before, err := a.goTestFailures()
+	if err != nil {
+		return nil, nil, fmt.Errorf("tests failed to run with current version: %w", err)
+	}
+	failures, err := a.runUpgradedTests(module, version, before)
+	if err != nil {
+		return nil, nil, err
+	}
+	return failures, before, nil
+}
+
+// runUpgradedTests runs the project's tests against the upgraded dependency
+// version and returns the failures that are not in before
+func (a *Analyzer) runUpgradedTests(module, version string, before map[TestFailure]bool) ([]TestFailure, error) {
+	overlay, err := a.newUpgradeOverlay(module, version)
+	if err != nil {
+		return nil, err
+	}
+	defer overlay.Close()
+
+	after, err := a.goTestFailures(overlay.flag())
+	if err != nil {
+		return nil, fmt.Errorf("tests failed to run with %s: %w", version, err)
+	}
+
+	var failures []TestFailure
+	for failure := range after {
+		if !before[failure] {
+			failures = append(failures, failure)
+		}
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].Package != failures[j].Package {
+			return failures[i].Package < failures[j].Package
+		}
+		return failures[i].Test < failures[j].Test
+	})
+	return failures, nil
+}
+
+// checkFixedTests reruns the project's tests against the upgraded version once
+// Fix has rewritten files, recording in fixes the failures that baseline, the
+// run before the upgrade and the rewrite, did not have
+func (a *Analyzer) checkFixedTests(fixes *FixReport, upgrade *Upgrade, baseline map[TestFailure]bool) error {
+	if !a.opts.RunTests || fixes == nil || fixes.DryRun || len(fixes.Files) == 0 {
+		return nil
+	}
+	failures, err := a.runUpgradedTests(upgrade.Module, upgrade.NewVersion, baseline)
+	if err != nil {
+		return fmt.Errorf("failed to run tests after fixing call sites: %w", err)
+	}
+	fixes.Tested = true
+	fixes.TestFailures = failures
+	return nil
+}
+
+// goTestFailures runs `go test -json ./...` and collects failing tests and packages
+func (a *Analyzer) goTestFailures(flags ...string) (map[TestFailure]bool, error) {
+	args := append([]string{"test"}, flags...)
+	args = append(args, "-json", "./...")
+
+	// go test exits non-zero when tests fail; only treat it as an error when
+	// nothing could be run at all
+	out, runErr := runGoCommand(a.projectPath, args...)
+	failures, events := parseTestEvents(out)
+	if runErr != nil && events == 0 {
+		return nil, runErr
+	}
+	return failures, nil
+}
+
+// parseTestEvents extracts failures from `go test -json` output. Package-level
+// failures (such as build errors) are recorded with an empty test name. Before
+// Go 1.24, a package that fails to build has no JSON events, only a
+// "[build failed]" line, which counts as an event.
+func parseTestEvents(out []byte) (map[TestFailure]bool, int) {
+	failures := make(map[TestFailure]bool)
+	events := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var ev testEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			if m := buildFailedLine.FindSubmatch(scanner.Bytes()); m != nil {
+				events++
+				failures[TestFailure{Package: string(m[1])}] = true
+			}
+			continue
+		}
+		events++
+		if ev.Action == "fail" {
+			failures[TestFailure{Package: ev.Package, Test: ev.Test}] = true
+		}
+	}
+
+	return failures, events
+}
@@ -0,0 +1,147 @@
+package analyzer
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTestEvents(t *testing.T) {
+	out := []byte(`{"Action":"run","Package":"example.com/app","Test":"TestA"}
+{"Action":"fail","Package":"example.com/app","Test":"TestA"}
+{"Action":"pass","Package":"example.com/app","Test":"TestB"}
+# example.com/app/broken
+broken/x.go:3:2: undefined: lib.OldHelper
+{"Action":"fail","Package":"example.com/app/broken"}
+FAIL	example.com/app/old [build failed]
+`)
+
+	failures, events := parseTestEvents(out)
+	if events != 5 {
+		t.Fatalf("parseTestEvents() events = %d, want 5", events)
+	}
+	if !failures[TestFailure{Package: "example.com/app", Test: "TestA"}] {
+		t.Fatalf("parseTestEvents() missing TestA failure: %v", failures)
+	}
+	if !failures[TestFailure{Package: "example.com/app/broken"}] {
+		t.Fatalf("parseTestEvents() missing package failure: %v", failures)
+	}
+	if !failures[TestFailure{Package: "example.com/app/old"}] {
+		t.Fatalf("parseTestEvents() missing build failure without JSON events: %v", failures)
+	}
+	if len(failures) != 3 {
+		t.Fatalf("parseTestEvents() = %v, want 3 failures", failures)
+	}
+}
+
+func TestRunProjectTestsReportsNewFailuresOnly(t *testing.T) {
+	project := writeProject(t, "module example.com/app\n\ngo 1.21\n")
+
+	restore := mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+		if args[0] != "test" {
+			return nil, nil
+		}
+		flaky := `{"Action":"fail","Package":"example.com/app","Test":"TestFlaky"}` + "\n"
+		if strings.HasPrefix(args[1], "-modfile=") {
+			return []byte(flaky + `{"Action":"fail","Package":"example.com/app","Test":"TestParse"}` + "\n"), errors.New("exit status 1")
+		}
+		return []byte(flaky), errors.New("exit status 1")
+	})
+	defer restore()
+
+	a := &Analyzer{projectPath: project}
+	failures, before, err := a.runProjectTests("example.com/lib", "v2.0.0")
+	if err != nil {
+		t.Fatalf("runProjectTests() error = %v", err)
+	}
+
+	want := []TestFailure{{Package: "example.com/app", Test: "TestParse"}}
+	if !reflect.DeepEqual(failures, want) {
+		t.Fatalf("runProjectTests() = %+v, want %+v", failures, want)
+	}
+	if len(before) != 1 || !before[TestFailure{Package: "example.com/app", Test: "TestFlaky"}] {
+		t.Fatalf("runProjectTests() current failures = %v, want TestFlaky", before)
+	}
+}
+
+func TestCheckFixedTests(t *testing.T) {
+	project := writeProject(t, "module example.com/app\n\ngo 1.21\n")
+
+	var runs [][]string
+	restore := mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+		if args[0] != "test" {
+			return nil, nil
+		}
+		runs = append(runs, args)
+		return []byte(`{"Action":"fail","Package":"example.com/app","Test":"TestFlaky"}` + "\n" +
+			`{"Action":"fail","Package":"example.com/app","Test":"TestParse"}` + "\n"), errors.New("exit status 1")
+	})
+	defer restore()
+
+	a := &Analyzer{projectPath: project, opts: Options{RunTests: true}}
+	upgrade := &Upgrade{Module: "example.com/lib", NewVersion: "v2.0.0"}
+	baseline := map[TestFailure]bool{{Package: "example.com/app", Test: "TestFlaky"}: true}
+
+	preview := &FixReport{Files: []string{"main.go"}, DryRun: true}
+	if err := a.checkFixedTests(preview, upgrade, baseline); err != nil || preview.Tested || len(runs) != 0 {
+		t.Fatalf("checkFixedTests() on a dry run = %v, tested %v after %d run(s), want nothing run", err, preview.Tested, len(runs))
+	}
+
+	fixes := &FixReport{Files: []string{"main.go"}}
+	if err := a.checkFixedTests(fixes, upgrade, baseline); err != nil {
+		t.Fatalf("checkFixedTests() error = %v", err)
+	}
+	if len(runs) != 1 || !strings.HasPrefix(runs[0][1], "-modfile=") {
+		t.Fatalf("go test runs = %v, want one run against the upgrade", runs)
+	}
+	want := []TestFailure{{Package: "example.com/app", Test: "TestParse"}}
+	if !fixes.Tested || !reflect.DeepEqual(fixes.TestFailures, want) {
+		t.Fatalf("checkFixedTests() = tested %v, %+v, want %+v", fixes.Tested, fixes.TestFailures, want)
+	}
+}
+
+func TestRunProjectTestsFailsWhenNothingRuns(t *testing.T) {
+	restore := mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+		return nil, errors.New("go: command not found")
+	})
+	defer restore()
+
+	a := &Analyzer{projectPath: "."}
+	if _, _, err := a.runProjectTests("example.com/lib", "v2.0.0"); err == nil {
+		t.Fatalf("runProjectTests() expected error when go test cannot run")
+	}
+}
+
+func TestRunProjectTestsReportsBuildBreak(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not available")
+	}
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOFLAGS", "-mod=mod")
+	t.Setenv("GOWORK", "off")
+
+	// Both versions of the dependency resolve to local copies instead of downloads
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "lib1", "go.mod"), "module example.com/lib\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(root, "lib1", "lib.go"), "package lib\n\nfunc Parse() int { return 1 }\n")
+	writeFile(t, filepath.Join(root, "lib2", "go.mod"), "module example.com/lib\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(root, "lib2", "lib.go"), "package lib\n\nfunc ParseString() int { return 1 }\n")
+	project := filepath.Join(root, "app")
+	writeFile(t, filepath.Join(project, "go.mod"), "module example.com/app\n\ngo 1.21\n\nrequire example.com/lib v1.0.0\n\n"+
+		"replace example.com/lib v1.0.0 => ../lib1\n\nreplace example.com/lib v1.1.0 => ../lib2\n")
+	writeFile(t, filepath.Join(project, "app.go"), "package app\n\nimport \"example.com/lib\"\n\nfunc F() int { return lib.Parse() }\n")
+	writeFile(t, filepath.Join(project, "app_test.go"), "package app\n\nimport \"testing\"\n\nfunc TestF(t *testing.T) { F() }\n")
+
+	a := &Analyzer{projectPath: project}
+	failures, _, err := a.runProjectTests("example.com/lib", "v1.1.0")
+	if err != nil {
+		t.Fatalf("runProjectTests() error = %v, want the build break reported", err)
+	}
+	want := []TestFailure{{Package: "example.com/app"}}
+	if !reflect.DeepEqual(failures, want) {
+		t.Fatalf("runProjectTests() = %+v, want %+v", failures, want)
+	}
+}
@@ -0,0 +1,211 @@
+package analyzer
+
+import (
+	"go/ast"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// todoMarkers start the comments scanned by Options.TODOs
+var todoMarkers = []string{"TODO", "FIXME"}
+
+// todoStopWords are words too common in TODOs and Go names to tie a TODO to
+// an added symbol
+var todoStopWords = map[string]bool{
+	"todo": true, "fixme": true, "with": true, "when": true, "once": true,
+	"this": true, "that": true, "from": true, "into": true, "have": true,
+	"should": true, "could": true, "would": true, "need": true, "needs": true,
+	"make": true, "must": true, "after": true, "before": true, "instead": true,
+	"upgrade": true, "version": true, "available": true, "support": true,
+	"supports": true, "new": true, "func": true, "type": true,
+}
+
+// TODOMatch is a project TODO comment mentioning the dependency that symbols
+// added in the new version may address
+type TODOMatch struct {
+	Text     string // the comment from its TODO marker on, on one line
+	Location Location
+	Symbols  []string // added symbols the TODO names, or shares a word with
+}
+
+// todoMatches finds the TODO and FIXME comments of the project that mention
+// the module, by path or by the name a file imports it under, and pairs them
+// with the added symbols they name, or share a word of at least
+// minHintNameLength letters with
+func (a *Analyzer) todoMatches(module string, diff *Diff) []TODOMatch {
+	type added struct {
+		name  string
+		words []string
+	}
+	var symbols []added
+	for _, sym := range diff.Added {
+		var words []string
+		for _, part := range strings.Split(sym.Name, ".") {
+			for _, w := range identWords(part) {
+				if len(w) >= minHintNameLength && !todoStopWords[w] {
+					words = append(words, singular(w))
+				}
+			}
+		}
+		symbols = append(symbols, added{sym.Name, words})
+	}
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	var matches []TODOMatch
+	seen := make(map[Location]bool)
+	for _, pkg := range a.pkgs {
+		for _, file := range pkg.Syntax {
+			names := moduleImportNames(pkg, file, module)
+			for _, group := range file.Comments {
+				text, c := todoText(group)
+				if c == nil || !mentionsModule(text, module, names) {
+					continue
+				}
+				pos := pkg.Fset.Position(c.Pos())
+				loc := Location{File: pos.Filename, Line: pos.Line, Column: pos.Column}
+				if seen[locationKey(loc)] {
+					continue // a file shared by a package and its test variant
+				}
+				seen[locationKey(loc)] = true
+
+				// Exact identifiers, and lower-cased words in singular
+				fields := make(map[string]bool)
+				words := make(map[string]bool)
+				for _, field := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+					fields[field] = true
+					for _, w := range append(identWords(field), strings.ToLower(field)) {
+						words[singular(w)] = true
+					}
+				}
+				for name := range names {
+					delete(words, singular(strings.ToLower(name)))
+				}
+
+				match := TODOMatch{Text: text, Location: loc}
+				for _, sym := range symbols {
+					base := sym.name[strings.LastIndex(sym.name, ".")+1:]
+					hit := fields[base] && len(base) >= minHintNameLength
+					for _, w := range sym.words {
+						hit = hit || words[w]
+					}
+					if hit {
+						match.Symbols = append(match.Symbols, sym.name)
+					}
+				}
+				if len(match.Symbols) > 0 {
+					sort.Strings(match.Symbols)
+					matches = append(matches, match)
+				}
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Location.File != matches[j].Location.File {
+			return matches[i].Location.File < matches[j].Location.File
+		}
+		return matches[i].Location.Line < matches[j].Location.Line
+	})
+	return matches
+}
+
+// todoText returns a comment group's text from its first TODO or FIXME
+// marker on, joined into one line, and the comment holding the marker
+func todoText(group *ast.CommentGroup) (string, *ast.Comment) {
+	for i, c := range group.List {
+		text := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(c.Text, "//"), "/*"), "*/"))
+		for _, marker := range todoMarkers {
+			if !strings.HasPrefix(text, marker) {
+				continue
+			}
+			lines := []string{text}
+			for _, next := range group.List[i+1:] {
+				lines = append(lines, strings.TrimSpace(strings.TrimPrefix(next.Text, "//")))
+			}
+			return strings.Join(strings.Fields(strings.Join(lines, " ")), " "), c
+		}
+	}
+	return "", nil
+}
+
+// moduleImportNames returns the names a file refers to the module's packages
+// by, such as pgx for github.com/jackc/pgx/v5
+func moduleImportNames(pkg *packages.Package, file *ast.File, module string) map[string]bool {
+	names := make(map[string]bool)
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || (path != module && !strings.HasPrefix(path, module+"/")) {
+			continue
+		}
+		if spec.Name != nil {
+			if spec.Name.Name != "_" && spec.Name.Name != "." {
+				names[spec.Name.Name] = true
+			}
+			continue
+		}
+		if imp := pkg.Imports[path]; imp != nil && imp.Name != "" {
+			names[imp.Name] = true
+		} else if pkg.Types != nil {
+			for _, imp := range pkg.Types.Imports() {
+				if imp.Path() == path {
+					names[imp.Name()] = true
+				}
+			}
+		}
+	}
+	return names
+}
+
+// mentionsModule reports whether a TODO names the module by path, or by one
+// of the names its file imports it under
+func mentionsModule(text, module string, names map[string]bool) bool {
+	if strings.Contains(text, module) {
+		return true
+	}
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' }) {
+		if names[field] {
+			return true
+		}
+	}
+	return false
+}
+
+// identWords splits an identifier into its lower-cased words, such as
+// send, batch, and context for SendBatchContext
+func identWords(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+	for i := 1; i <= len(runes); i++ {
+		boundary := i == len(runes) || runes[i] == '_' ||
+			(unicode.IsUpper(runes[i]) && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))))
+		if !boundary {
+			continue
+		}
+		if word := strings.Trim(string(runes[start:i]), "_"); word != "" {
+			words = append(words, strings.ToLower(word))
+		}
+		start = i
+	}
+	return words
+}
+
+// singular strips a plural ending from a lower-cased word, so batches and
+// batch match
+func singular(word string) string {
+	for _, suffix := range []string{"ches", "shes", "sses", "xes"} {
+		if strings.HasSuffix(word, suffix) {
+			return strings.TrimSuffix(word, "es")
+		}
+	}
+	if strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
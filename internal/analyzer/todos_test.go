@@ -0,0 +1,68 @@
+package analyzer
+
+import (
+	"go/types"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestTODOMatches(t *testing.T) {
+	lib := checkSource(t, "github.com/jackc/pgx/v5", "package pgx\n\nfunc Connect() {}\n", nil)
+	app := checkSource(t, "example.com/app", `package app
+
+import "github.com/jackc/pgx/v5"
+
+// TODO: send these inserts as one batch once pgx
+// supports batches.
+func save() { pgx.Connect() }
+
+// FIXME(ana): pgx has no Retry, so we wrap it ourselves
+func retry() {}
+
+// TODO: replace github.com/jackc/pgx/v5 logging with a tracer
+func trace() {}
+
+// TODO: add batching to the cache
+func cache() {}
+
+// TODO: pgx needs a better pool
+func pool() {}
+`, map[string]*types.Package{"github.com/jackc/pgx/v5": lib.Types})
+
+	diff := &Diff{Added: []AddedSymbol{
+		{Name: "Batch", Type: "type"},
+		{Name: "Conn.SendBatch", Type: "function"},
+		{Name: "Retry", Type: "function"},
+		{Name: "QueryTracer", Type: "interface"},
+		{Name: "NewPool", Type: "function"},
+	}}
+	a := &Analyzer{pkgs: []*packages.Package{app}}
+
+	got := a.todoMatches("github.com/jackc/pgx/v5", diff)
+	want := []TODOMatch{
+		{Text: "TODO: send these inserts as one batch once pgx supports batches.", Location: Location{File: "example.com/app.go", Line: 5, Column: 1}, Symbols: []string{"Batch", "Conn.SendBatch"}},
+		{Text: "FIXME(ana): pgx has no Retry, so we wrap it ourselves", Location: Location{File: "example.com/app.go", Line: 9, Column: 1}, Symbols: []string{"Retry"}},
+		{Text: "TODO: replace github.com/jackc/pgx/v5 logging with a tracer", Location: Location{File: "example.com/app.go", Line: 12, Column: 1}, Symbols: []string{"QueryTracer"}},
+		{Text: "TODO: pgx needs a better pool", Location: Location{File: "example.com/app.go", Line: 18, Column: 1}, Symbols: []string{"NewPool"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("todoMatches() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestIdentWords(t *testing.T) {
+	tests := map[string][]string{
+		"SendBatchContext": {"send", "batch", "context"},
+		"HTTPClient":       {"http", "client"},
+		"parseURL":         {"parse", "url"},
+		"snake_case":       {"snake", "case"},
+		"X":                {"x"},
+	}
+	for name, want := range tests {
+		if got := identWords(name); !reflect.DeepEqual(got, want) {
+			t.Errorf("identWords(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
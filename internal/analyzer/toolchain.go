@@ -0,0 +1,377 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/types"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// ToolchainModule is the pseudo-module name that audits a Go toolchain
+// upgrade, as in -upgrade go@1.23
+const ToolchainModule = "go"
+
+// Allow overriding in tests
+var (
+	goroot      = runtime.GOROOT
+	userHomeDir = os.UserHomeDir
+)
+
+// stdlibSymbol is one line of the api/go1.*.txt files shipped with Go
+type stdlibSymbol struct {
+	key  string // import path, then Name or Type.Method
+	kind string // "function", "type", "interface", "const", "var", or "field"
+	decl string // the declaration as written in the api file
+}
+
+// analyzeToolchain diffs the standard library API between the project's Go
+// version and the target one. Go 1 compatibility means the API only grows, so
+// breakage comes from the incompatible changes each release records in
+// api/except.txt. With the api files of both releases installed, exceptions
+// new in the target release are reported as removed or changed. With only one
+// release, except.txt cannot tell when a change was made, so only changes
+// whose replacement declaration was added in between are reported.
+func (a *Analyzer) analyzeToolchain(upgrade *Upgrade) (*Result, error) {
+	newMinor, err := goMinorVersion(upgrade.NewVersion)
+	if err != nil {
+		return nil, err
+	}
+	current, err := a.currentGoVersion()
+	if err != nil {
+		return nil, err
+	}
+	upgrade.OldVersion = current
+	oldMinor, err := goMinorVersion(current)
+	if err != nil {
+		return nil, err
+	}
+
+	// The api files are cumulative, so one release describes every older one
+	dir, _, err := stdlibAPIDir(upgrade.NewVersion, newMinor)
+	if err != nil {
+		return nil, err
+	}
+	oldAPI, err := readStdlibAPI(dir, oldMinor)
+	if err != nil {
+		return nil, err
+	}
+	newAPI, err := readStdlibAPI(dir, newMinor)
+	if err != nil {
+		return nil, err
+	}
+	exceptions, err := readStdlibExceptions(dir)
+	if err != nil {
+		return nil, err
+	}
+	var oldExceptions map[string]bool
+	if oldDir, exact, err := stdlibAPIDir(current, oldMinor); err == nil && exact && oldDir != dir {
+		lines, err := readStdlibExceptions(oldDir)
+		if err != nil {
+			return nil, err
+		}
+		oldExceptions = make(map[string]bool)
+		for _, line := range lines {
+			oldExceptions[line] = true
+		}
+	}
+
+	usage, imported := a.findStdlibUsage()
+	diff := &Diff{
+		Removed:          []RemovedSymbol{},
+		Added:            []AddedSymbol{},
+		Changed:          []ChangedSignature{},
+		InterfaceChanges: []InterfaceChange{},
+	}
+
+	for _, line := range exceptions {
+		if oldExceptions[line] || strings.HasSuffix(line, "//deprecated") {
+			continue
+		}
+		sym, ok := parseStdlibLine(line)
+		if !ok {
+			continue
+		}
+		if current := oldAPI[sym.key]; current == nil || current.decl != sym.decl {
+			continue
+		}
+		locations := usage[sym.key]
+		if len(locations) == 0 {
+			continue
+		}
+		replacement := newAPI[sym.key]
+		switch {
+		case replacement.decl != sym.decl:
+			diff.Changed = append(diff.Changed, ChangedSignature{
+				Name:         sym.key,
+				OldSignature: sym.decl,
+				NewSignature: replacement.decl,
+				Package:      stdlibPackage(sym.key),
+				UsedIn:       locations,
+			})
+		case oldExceptions != nil:
+			diff.Removed = append(diff.Removed, RemovedSymbol{
+				Name:    sym.key,
+				Type:    sym.kind,
+				Package: stdlibPackage(sym.key),
+				UsedIn:  locations,
+			})
+		}
+	}
+
+	// New APIs are only listed for packages the project already imports
+	for key, sym := range newAPI {
+		if oldAPI[key] == nil && imported[stdlibPackage(key)] {
+			diff.Added = append(diff.Added, AddedSymbol{Name: key, Type: sym.kind, Package: stdlibPackage(key)})
+		}
+	}
+	sortFindings(diff)
+
+	return &Result{
+		Module:     ToolchainModule,
+		OldVersion: upgrade.OldVersion,
+		NewVersion: upgrade.NewVersion,
+		Changes:    diff,
+	}, nil
+}
+
+// currentGoVersion returns the toolchain the project builds with: the go.mod
+// toolchain directive, or else its go directive
+func (a *Analyzer) currentGoVersion() (string, error) {
+	f, err := a.projectModFile()
+	if err != nil {
+		return "", err
+	}
+	if f.Toolchain != nil {
+		return strings.TrimPrefix(f.Toolchain.Name, "go"), nil
+	}
+	if f.Go != nil {
+		return f.Go.Version, nil
+	}
+	return "", fmt.Errorf("go.mod has no go directive")
+}
+
+// goMinorVersion extracts N from 1.N, 1.N.P, or go1.N
+func goMinorVersion(version string) (int, error) {
+	parts := strings.Split(strings.TrimPrefix(version, "go"), ".")
+	if len(parts) < 2 || parts[0] != "1" {
+		return 0, fmt.Errorf("invalid Go version %q (expected 1.N or 1.N.P)", version)
+	}
+	minor, err := strconv.Atoi(strings.TrimRightFunc(parts[1], func(r rune) bool { return r < '0' || r > '9' }))
+	if err != nil {
+		return 0, fmt.Errorf("invalid Go version %q (expected 1.N or 1.N.P)", version)
+	}
+	return minor, nil
+}
+
+// stdlibAPIDir finds an api directory describing Go 1.minor, and reports
+// whether it belongs to exactly that release. Toolchain modules on the proxy
+// do not ship api files, so it looks at SDKs installed by golang.org/dl and at
+// the local GOROOT.
+func stdlibAPIDir(version string, minor int) (string, bool, error) {
+	release := "go" + strings.TrimPrefix(version, "go")
+	var candidates []string
+	if home, err := userHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, "sdk", release, "api"))
+		patches, _ := filepath.Glob(filepath.Join(home, "sdk", release+".*", "api"))
+		candidates = append(candidates, patches...)
+	}
+	candidates = append(candidates, filepath.Join(goroot(), "api"))
+
+	fallback := ""
+	for _, dir := range candidates {
+		if _, err := statFile(filepath.Join(dir, fmt.Sprintf("go1.%d.txt", minor))); err != nil {
+			continue
+		}
+		if _, err := statFile(filepath.Join(dir, fmt.Sprintf("go1.%d.txt", minor+1))); err != nil {
+			return dir, true, nil
+		}
+		if fallback == "" {
+			fallback = dir
+		}
+	}
+	if fallback != "" {
+		return fallback, false, nil
+	}
+	return "", false, fmt.Errorf("no API data for %s: install it with `go install golang.org/dl/%s@latest && %s download`",
+		release, release, release)
+}
+
+// readStdlibAPI reads the portable API of Go 1.minor from dir: go1.txt plus
+// one file per release up to minor
+func readStdlibAPI(dir string, minor int) (map[string]*stdlibSymbol, error) {
+	api := make(map[string]*stdlibSymbol)
+	for i := 0; i <= minor; i++ {
+		name := "go1.txt"
+		if i > 0 {
+			name = fmt.Sprintf("go1.%d.txt", i)
+		}
+		data, err := readFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read standard library API: %w", err)
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			if sym, ok := parseStdlibLine(scanner.Text()); ok {
+				api[sym.key] = sym
+			}
+		}
+	}
+	return api, nil
+}
+
+// readStdlibExceptions reads the incompatible changes recorded in except.txt
+func readStdlibExceptions(dir string) ([]string, error) {
+	data, err := readFile(filepath.Join(dir, "except.txt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read standard library exceptions: %w", err)
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// parseStdlibLine parses lines such as
+//
+//	pkg net/http, func Get(string) (*Response, error)
+//	pkg os, method (*File) Close() error
+//	pkg io, type Reader interface { Read }
+//
+// Platform-specific lines ("pkg syscall (linux-386), ...") are skipped, and
+// "//deprecated" markers are dropped.
+func parseStdlibLine(line string) (*stdlibSymbol, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(line), "pkg ")
+	if !ok {
+		return nil, false
+	}
+	// Deprecation markers annotate an existing declaration
+	rest = strings.TrimSuffix(rest, " //deprecated")
+	pkg, decl, ok := strings.Cut(rest, ", ")
+	if !ok || strings.Contains(pkg, " ") {
+		return nil, false
+	}
+
+	word, body, _ := strings.Cut(decl, " ")
+	sym := &stdlibSymbol{decl: decl}
+	switch word {
+	case "func":
+		sym.kind = "function"
+		sym.key = pkg + "." + identPrefix(body)
+	case "method":
+		// method (*T) Name(...)
+		recv, after, ok := strings.Cut(body, ") ")
+		if !ok {
+			return nil, false
+		}
+		recv = strings.TrimLeft(strings.TrimPrefix(recv, "("), "*")
+		sym.kind = "function"
+		sym.key = pkg + "." + recv + "." + identPrefix(after)
+	case "type":
+		name, kind, _ := strings.Cut(body, " ")
+		switch {
+		case strings.HasPrefix(kind, "struct, "):
+			// pkg net/http, type Client struct, Timeout time.Duration
+			sym.kind = "field"
+			sym.key = pkg + "." + name + "." + identPrefix(strings.TrimPrefix(kind, "struct, "))
+		case strings.HasPrefix(kind, "interface, "):
+			sym.kind = "function"
+			sym.key = pkg + "." + name + "." + identPrefix(strings.TrimPrefix(kind, "interface, "))
+		case strings.HasPrefix(kind, "interface"):
+			sym.kind = "interface"
+			sym.key = pkg + "." + name
+		default:
+			sym.kind = "type"
+			sym.key = pkg + "." + name
+		}
+	case "const", "var":
+		sym.kind = word
+		sym.key = pkg + "." + identPrefix(body)
+	default:
+		return nil, false
+	}
+	return sym, true
+}
+
+// identPrefix returns the leading identifier of s
+func identPrefix(s string) string {
+	for i, r := range s {
+		if !(r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 0x7f) {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// stdlibPackage returns the import path of a stdlibSymbol key
+func stdlibPackage(key string) string {
+	slash := strings.LastIndex(key, "/")
+	if dot := strings.Index(key[slash+1:], "."); dot >= 0 {
+		return key[:slash+1+dot]
+	}
+	return key
+}
+
+// findStdlibUsage records the standard library symbols the project uses,
+// keyed like stdlibSymbol, and the standard packages it imports
+func (a *Analyzer) findStdlibUsage() (map[string][]Location, map[string]bool) {
+	usage := make(map[string][]Location)
+	imported := make(map[string]bool)
+	for _, pkg := range a.pkgs {
+		for path, imp := range pkg.Imports {
+			if imp.Module == nil && !strings.Contains(strings.SplitN(path, "/", 2)[0], ".") {
+				imported[path] = true
+			}
+		}
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		approximate := a.opts.AllowErrors && approximateUsage(pkg)
+
+		for ident, obj := range pkg.TypesInfo.Uses {
+			if obj == nil || !obj.Exported() || obj.Pkg() == nil || !imported[obj.Pkg().Path()] {
+				continue
+			}
+			name := obj.Name()
+			if fn, ok := obj.(*types.Func); ok {
+				if recv := receiverName(fn.Type().(*types.Signature)); recv != "" {
+					name = recv + "." + name
+				}
+			}
+			if v, ok := obj.(*types.Var); ok && v.IsField() {
+				continue // fields are recorded below with their struct
+			}
+			pos := pkg.Fset.Position(ident.Pos())
+			key := obj.Pkg().Path() + "." + name
+			usage[key] = append(usage[key], Location{File: pos.Filename, Line: pos.Line, Column: pos.Column, Approximate: approximate})
+		}
+
+		for expr, sel := range pkg.TypesInfo.Selections {
+			if sel.Kind() != types.FieldVal || !sel.Obj().Exported() || sel.Obj().Pkg() == nil || !imported[sel.Obj().Pkg().Path()] {
+				continue
+			}
+			recv := sel.Recv()
+			if ptr, ok := recv.(*types.Pointer); ok {
+				recv = ptr.Elem()
+			}
+			named, ok := recv.(*types.Named)
+			if !ok {
+				continue
+			}
+			pos := pkg.Fset.Position(expr.Sel.Pos())
+			key := sel.Obj().Pkg().Path() + "." + named.Obj().Name() + "." + sel.Obj().Name()
+			usage[key] = append(usage[key], Location{File: pos.Filename, Line: pos.Line, Column: pos.Column, Approximate: approximate})
+		}
+	}
+	for key, locations := range usage {
+		usage[key] = dedupeLocations(locations)
+	}
+	return usage, imported
+}
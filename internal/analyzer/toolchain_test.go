@@ -0,0 +1,135 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// writeAPIDir writes api files for Go 1.0 through 1.last, with extra lines per release
+func writeAPIDir(t *testing.T, dir string, last int, lines map[int]string, except string) {
+	t.Helper()
+	for i := 0; i <= last; i++ {
+		name := "go1.txt"
+		if i > 0 {
+			name = fmt.Sprintf("go1.%d.txt", i)
+		}
+		writeFile(t, filepath.Join(dir, name), lines[i])
+	}
+	writeFile(t, filepath.Join(dir, "except.txt"), except)
+}
+
+func TestAnalyzeToolchain(t *testing.T) {
+	std := checkSource(t, "strs", `package strs
+
+func Join(elems []string) string { return "" }
+
+func Split(s string) []string { return nil }
+
+func Count(s string) int { return 0 }
+`, nil)
+	app := checkSource(t, "example.com/app", `package app
+
+import "strs"
+
+func run() {
+	_ = strs.Join(nil)
+	_ = strs.Split("")
+}
+`, map[string]*types.Package{"strs": std.Types})
+	app.Imports = map[string]*packages.Package{"strs": {PkgPath: "strs"}}
+
+	lines := map[int]string{
+		0:  "pkg strs, func Join([]string) string\npkg strs, func Split(string) []string\npkg strs (linux-386), const X = 1\n",
+		21: "pkg strs, func Count(string) int\n",
+		22: "pkg strs, func Join([]string, string) string\npkg strs, func Fields(string) []string\n",
+	}
+	except := "pkg strs, func Join([]string) string\npkg strs, func Split(string) []string\n"
+
+	tests := []struct {
+		name        string
+		oldSDK      bool
+		wantChanged []string
+		wantRemoved []string
+	}{
+		{name: "only the new release installed", wantChanged: []string{"strs.Join"}},
+		{name: "both releases installed", oldSDK: true, wantChanged: []string{"strs.Join"}, wantRemoved: []string{"strs.Split"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, home, project := t.TempDir(), t.TempDir(), t.TempDir()
+			writeAPIDir(t, filepath.Join(root, "api"), 22, lines, except)
+			if tt.oldSDK {
+				writeAPIDir(t, filepath.Join(home, "sdk", "go1.21.5", "api"), 21, lines, "")
+			}
+			writeFile(t, filepath.Join(project, "go.mod"), "module example.com/app\n\ngo 1.21\n\ntoolchain go1.21.5\n")
+
+			origRoot, origHome := goroot, userHomeDir
+			goroot = func() string { return root }
+			userHomeDir = func() (string, error) { return home, nil }
+			defer func() { goroot, userHomeDir = origRoot, origHome }()
+
+			a := &Analyzer{projectPath: project, pkgs: []*packages.Package{app}}
+			upgrade := &Upgrade{Module: ToolchainModule, NewVersion: "1.22"}
+			result, err := a.analyzeToolchain(upgrade)
+			if err != nil {
+				t.Fatalf("analyzeToolchain() error = %v", err)
+			}
+			if result.OldVersion != "1.21.5" {
+				t.Errorf("OldVersion = %q, want the toolchain directive", result.OldVersion)
+			}
+
+			var changed, removed, added []string
+			for _, c := range result.Changes.Changed {
+				changed = append(changed, c.Name)
+			}
+			for _, r := range result.Changes.Removed {
+				removed = append(removed, r.Name)
+			}
+			for _, s := range result.Changes.Added {
+				added = append(added, s.Name)
+			}
+			if !reflect.DeepEqual(changed, tt.wantChanged) || !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("changed = %v, removed = %v, want %v, %v", changed, removed, tt.wantChanged, tt.wantRemoved)
+			}
+			if !reflect.DeepEqual(added, []string{"strs.Fields"}) {
+				t.Errorf("added = %v, want [strs.Fields]", added)
+			}
+		})
+	}
+}
+
+func TestParseStdlibLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		wantKey  string
+		wantKind string
+	}{
+		{"pkg net/http, func Get(string) (*Response, error)", "net/http.Get", "function"},
+		{"pkg os, method (*File) Close() error", "os.File.Close", "function"},
+		{"pkg io, type Reader interface { Read }", "io.Reader", "interface"},
+		{"pkg io, type Reader interface, Read([]uint8) (int, error)", "io.Reader.Read", "function"},
+		{"pkg net/http, type Client struct, Timeout time.Duration", "net/http.Client.Timeout", "field"},
+		{"pkg net/http, type Client struct", "net/http.Client", "type"},
+		{"pkg io, var EOF error", "io.EOF", "var"},
+		{"pkg crypto/tls, const VersionSSL30 = 768 //deprecated", "crypto/tls.VersionSSL30", "const"},
+		{"pkg syscall (linux-386), const AF_INET = 2", "", ""},
+	}
+	for _, tt := range tests {
+		sym, ok := parseStdlibLine(tt.line)
+		if tt.wantKey == "" {
+			if ok {
+				t.Errorf("parseStdlibLine(%q) = %+v, want skipped", tt.line, sym)
+			}
+			continue
+		}
+		if !ok || sym.key != tt.wantKey || sym.kind != tt.wantKind {
+			t.Errorf("parseStdlibLine(%q) = %+v, %v, want %s (%s)", tt.line, sym, ok, tt.wantKey, tt.wantKind)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// toolsBuildTag is the conventional build constraint of tools.go files that pin
+// tool dependencies with blank imports
+const toolsBuildTag = "tools"
+
+// toolModules returns the modules the project pins through tools.go blank imports.
+// Those files are excluded from normal builds, so their imports never show up in
+// the loaded packages.
+func (a *Analyzer) toolModules() (map[string]bool, error) {
+	imports, err := a.toolImports()
+	if err != nil {
+		return nil, err
+	}
+	if len(imports) == 0 {
+		return map[string]bool{}, nil
+	}
+
+	f, err := a.projectModFile()
+	if err != nil {
+		return nil, err
+	}
+	var required []string
+	for _, req := range f.Require {
+		required = append(required, req.Mod.Path)
+	}
+
+	modules := make(map[string]bool)
+	for _, imp := range imports {
+		if mod := owningModule(imp, required); mod != "" {
+			modules[mod] = true
+		}
+	}
+	return modules, nil
+}
+
+// toolImports collects blank import paths from files guarded by the tools build tag
+func (a *Analyzer) toolImports() ([]string, error) {
+	seen := make(map[string]bool)
+	err := filepath.WalkDir(a.projectPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != a.projectPath && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly|parser.ParseComments)
+		if err != nil {
+			return nil // unparsable files are the compiler's problem, not ours
+		}
+		if !hasBuildTag(file.Comments, toolsBuildTag) {
+			return nil
+		}
+		for _, imp := range file.Imports {
+			if imp.Name == nil || imp.Name.Name != "_" {
+				continue
+			}
+			if p, err := strconv.Unquote(imp.Path.Value); err == nil {
+				seen[p] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	imports := make([]string, 0, len(seen))
+	for imp := range seen {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	return imports, nil
+}
+
+// hasBuildTag reports whether the file's build constraints are satisfied when
+// only tag is set, which is how tools.go files are recognized
+func hasBuildTag(groups []*ast.CommentGroup, tag string) bool {
+	for _, group := range groups {
+		for _, c := range group.List {
+			if !constraint.IsGoBuild(c.Text) && !constraint.IsPlusBuild(c.Text) {
+				continue
+			}
+			expr, err := constraint.Parse(c.Text)
+			if err != nil {
+				continue
+			}
+			if expr.Eval(func(t string) bool { return t == tag }) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// owningModule returns the longest module path in modules that contains importPath
+func owningModule(importPath string, modules []string) string {
+	best := ""
+	for _, mod := range modules {
+		if (importPath == mod || strings.HasPrefix(importPath, mod+"/")) && len(mod) > len(best) {
+			best = mod
+		}
+	}
+	return best
+}
+
+// isToolDependency reports whether module is pinned through tools.go and not
+// imported by any regular package of the project
+func (a *Analyzer) isToolDependency(module string) bool {
+	tools, err := a.toolModules()
+	if err != nil || !tools[module] {
+		return false
+	}
+	return len(a.findUsage(module).Imports) == 0
+}
+
+// analyzeToolDependency audits a tool dependency. Tools are run, not called, so
+// instead of an API diff it reports how the tool's own module metadata changes.
+func (a *Analyzer) analyzeToolDependency(upgrade *Upgrade) (*Result, error) {
+	changes, err := a.compareModuleFiles(upgrade.Module, upgrade.OldVersion, upgrade.NewVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare tool module: %w", err)
+	}
+
+	// The tool is built from the replacement, if any
+	var replacement *Replacement
+	if a.opts.FromVersion == "" {
+		replacement = a.findReplacement(upgrade.Module, upgrade.OldVersion)
+		if replacement != nil && a.opts.IgnoreReplace {
+			replacement.Ignored = true
+		}
+	}
+
+	return &Result{
+		Module:         upgrade.Module,
+		OldVersion:     upgrade.OldVersion,
+		NewVersion:     upgrade.NewVersion,
+		ToolDependency: true,
+		Replacement:    replacement,
+		Changes:        &Diff{},
+		ModuleChanges:  changes,
+	}, nil
+}
+
+// compareModuleFiles diffs the go directive and requirements of two versions of a module
+func (a *Analyzer) compareModuleFiles(module, oldVersion, newVersion string) (*ModuleChanges, error) {
+	oldFile, err := a.loadModuleFile(module, oldVersion)
+	if err != nil {
+		return nil, err
+	}
+	newFile, err := a.loadModuleFile(module, newVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &ModuleChanges{}
+	if oldFile.Go != nil {
+		changes.OldGoVersion = oldFile.Go.Version
+	}
+	if newFile.Go != nil {
+		changes.NewGoVersion = newFile.Go.Version
+	}
+
+	oldReqs := make(map[string]string)
+	for _, req := range oldFile.Require {
+		oldReqs[req.Mod.Path] = req.Mod.Version
+	}
+	newReqs := make(map[string]string)
+	for _, req := range newFile.Require {
+		newReqs[req.Mod.Path] = req.Mod.Version
+		oldVer, ok := oldReqs[req.Mod.Path]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, req.Mod.Path+" "+req.Mod.Version)
+		case oldVer != req.Mod.Version:
+			changes.Upgraded = append(changes.Upgraded, fmt.Sprintf("%s %s -> %s", req.Mod.Path, oldVer, req.Mod.Version))
+		}
+	}
+	for _, req := range oldFile.Require {
+		if _, ok := newReqs[req.Mod.Path]; !ok {
+			changes.Removed = append(changes.Removed, req.Mod.Path+" "+req.Mod.Version)
+		}
+	}
+
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Removed)
+	sort.Strings(changes.Upgraded)
+	return changes, nil
+}
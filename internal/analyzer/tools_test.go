@@ -0,0 +1,196 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const toolsFile = `//go:build tools
+
+package tools
+
+import (
+	_ "github.com/golangci/golangci-lint/cmd/golangci-lint"
+	_ "golang.org/x/tools/cmd/stringer"
+)
+`
+
+func TestToolModules(t *testing.T) {
+	dir := writeProject(t, `module example.com/app
+
+go 1.21
+
+require (
+	github.com/golangci/golangci-lint v1.55.0
+	golang.org/x/tools v0.16.0
+	golang.org/x/tools/gopls v0.14.0
+	example.com/lib v1.0.0
+)
+`)
+	writeFile(t, filepath.Join(dir, "tools", "tools.go"), toolsFile)
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\nimport _ \"example.com/lib\"\n\nfunc main() {}\n")
+	writeFile(t, filepath.Join(dir, "testdata", "tools.go"), "//go:build tools\n\npackage tools\n\nimport _ \"example.com/ignored\"\n")
+
+	a := &Analyzer{projectPath: dir}
+	modules, err := a.toolModules()
+	if err != nil {
+		t.Fatalf("toolModules() error = %v", err)
+	}
+
+	want := map[string]bool{
+		"github.com/golangci/golangci-lint": true,
+		"golang.org/x/tools":                true,
+	}
+	if !reflect.DeepEqual(modules, want) {
+		t.Fatalf("toolModules() = %v, want %v", modules, want)
+	}
+}
+
+func TestHasBuildTag(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want bool
+	}{
+		{name: "go:build", src: "//go:build tools\n\npackage tools\n", want: true},
+		{name: "plus build", src: "// +build tools\n\npackage tools\n", want: true},
+		{name: "negated", src: "//go:build !tools\n\npackage tools\n", want: false},
+		{name: "other tag", src: "//go:build linux\n\npackage tools\n", want: false},
+		{name: "no constraint", src: "package tools\n", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := parseSource(t, tt.src)
+			if got := hasBuildTag(file.Comments, toolsBuildTag); got != tt.want {
+				t.Fatalf("hasBuildTag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOwningModule(t *testing.T) {
+	modules := []string{"golang.org/x/tools", "golang.org/x/tools/gopls", "golang.org/x/toolsmith"}
+
+	tests := []struct {
+		importPath string
+		want       string
+	}{
+		{importPath: "golang.org/x/tools/cmd/stringer", want: "golang.org/x/tools"},
+		{importPath: "golang.org/x/tools/gopls", want: "golang.org/x/tools/gopls"},
+		{importPath: "golang.org/x/toolsmith/cmd", want: "golang.org/x/toolsmith"},
+		{importPath: "example.com/other", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := owningModule(tt.importPath, modules); got != tt.want {
+			t.Errorf("owningModule(%q) = %q, want %q", tt.importPath, got, tt.want)
+		}
+	}
+}
+
+func TestCompareModuleFiles(t *testing.T) {
+	restoreCmd := mockGoCommand(func(dir string, args ...string) ([]byte, error) {
+		switch args[len(args)-1] {
+		case "example.com/tool@v1.0.0":
+			return []byte(`{"Path":"example.com/tool","Version":"v1.0.0","GoMod":"old.mod"}`), nil
+		default:
+			return []byte(`{"Path":"example.com/tool","Version":"v1.1.0","GoMod":"new.mod"}`), nil
+		}
+	})
+	defer restoreCmd()
+	restoreRead := mockReadFile(map[string]string{
+		"old.mod": "module example.com/tool\n\ngo 1.20\n\nrequire (\n\texample.com/a v1.0.0\n\texample.com/b v1.0.0\n)\n",
+		"new.mod": "module example.com/tool\n\ngo 1.21\n\nrequire (\n\texample.com/a v1.2.0\n\texample.com/c v0.1.0\n)\n",
+	})
+	defer restoreRead()
+
+	a := &Analyzer{projectPath: "."}
+	changes, err := a.compareModuleFiles("example.com/tool", "v1.0.0", "v1.1.0")
+	if err != nil {
+		t.Fatalf("compareModuleFiles() error = %v", err)
+	}
+
+	want := &ModuleChanges{
+		OldGoVersion: "1.20",
+		NewGoVersion: "1.21",
+		Added:        []string{"example.com/c v0.1.0"},
+		Removed:      []string{"example.com/b v1.0.0"},
+		Upgraded:     []string{"example.com/a v1.0.0 -> v1.2.0"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("compareModuleFiles() = %+v, want %+v", changes, want)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func parseSource(t *testing.T, src string) *ast.File {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "tools.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestAnalyzeToolDependencyMeasuresFootprint(t *testing.T) {
+	dir := writeProject(t, "module example.com/app\n\ngo 1.21\n\nrequire example.com/tool v1.0.0\n\nreplace example.com/tool => example.com/fork v1.0.1\n")
+	writeFile(t, filepath.Join(dir, "tools", "tools.go"), "//go:build tools\n\npackage tools\n\nimport _ \"example.com/tool/cmd/tool\"\n")
+	writeFile(t, filepath.Join(dir, "old.mod"), "module example.com/tool\n\ngo 1.20\n")
+	writeFile(t, filepath.Join(dir, "new.mod"), "module example.com/tool\n\ngo 1.21\n\nrequire example.com/dep v1.0.0\n")
+
+	restoreLoad := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		return []*packages.Package{{PkgPath: "example.com/app", Name: "main"}}, nil
+	})
+	defer restoreLoad()
+	restoreErrs := mockPackagesPrintErrors(func(pkgs []*packages.Package) int { return 0 })
+	defer restoreErrs()
+	restoreCmd := mockGoCommand(func(_ string, args ...string) ([]byte, error) {
+		switch args[len(args)-1] {
+		case "all":
+			if strings.HasPrefix(args[2], "-modfile=") {
+				return []byte("example.com/app\nexample.com/tool v1.1.0\nexample.com/dep v1.0.0\n"), nil
+			}
+			return []byte("example.com/app\nexample.com/tool v1.0.0\n"), nil
+		case "example.com/tool@v1.0.0":
+			return []byte(`{"Path":"example.com/tool","Version":"v1.0.0","GoMod":"` + filepath.ToSlash(filepath.Join(dir, "old.mod")) + `"}`), nil
+		case "example.com/tool@v1.1.0":
+			return []byte(`{"Path":"example.com/tool","Version":"v1.1.0","GoMod":"` + filepath.ToSlash(filepath.Join(dir, "new.mod")) + `"}`), nil
+		default:
+			return []byte(`{}`), nil
+		}
+	})
+	defer restoreCmd()
+
+	a := &Analyzer{projectPath: dir, opts: Options{Footprint: true}}
+	result, err := a.Analyze(&Upgrade{Module: "example.com/tool", NewVersion: "v1.1.0"})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if !result.ToolDependency {
+		t.Fatalf("ToolDependency = false, want a tool dependency result")
+	}
+	if result.Footprint == nil || !reflect.DeepEqual(result.Footprint.ModulesAdded, []string{"example.com/dep v1.0.0"}) {
+		t.Errorf("Footprint = %+v, want the tool's added requirement", result.Footprint)
+	}
+	if result.Replacement == nil || result.Replacement.Path != "example.com/fork" {
+		t.Errorf("Replacement = %+v, want the fork", result.Replacement)
+	}
+}
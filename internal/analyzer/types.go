@@ -1,6 +1,10 @@
 package analyzer
 
-import "strings"
+import (
+	"go/token"
+	"go/types"
+	"strings"
+)
 
 // Upgrade represents a dependency upgrade specification
 type Upgrade struct {
@@ -11,108 +15,411 @@ type Upgrade struct {
 
 // Result contains the analysis results
 type Result struct {
-	Module     string
-	OldVersion string
-	NewVersion string
-	Changes    *Diff
-	UnusedDeps []string
+	Module          string
+	OldVersion      string
+	NewVersion      string
+	RequiredVersion string       // version go.mod requires, when Options.FromVersion differs from it
+	NewDependency   bool         // true when the project does not require Module yet
+	ToolDependency  bool         // true when Module is only pinned through tools.go
+	MainOnly        bool         // true when Module only contains commands, so it has no API
+	Replacement     *Replacement // replace directive applied to Module, if any
+	Retractions     []Retraction // retracted versions among OldVersion and NewVersion
+	Deprecation     *Deprecation // deprecation of Module, if its authors declared one
+	Changes         *Diff
+	UnusedDeps      []string
+	Requirements    []Requirement // modules pulled in by a new dependency
+	Footprint       *Footprint    // size and dependency delta, if requested
+	BinaryImpact    *BinaryImpact // binary size delta, if requested
+	Benchmarks      []BenchmarkDelta
+	TestFailures    []TestFailure    // tests that fail only after the upgrade
+	ModuleChanges   *ModuleChanges   // go.mod changes of a tool dependency
+	Shims           *ShimFile        // compatibility shims, if requested
+	Fixes           *FixReport       // call sites rewritten with Options.Fix
+	Provenance      *Provenance      // inputs of the run, if requested
+	SumDB           *SumDBStatus     // checksum verification of NewVersion, if requested
+	Risk            *Risk            // heuristic risk of keeping the dependency current
+	Coupling        *Coupling        // how much of the project touches the dependency
+	Adapter         *AdapterAdvice   // suggestion to wrap a widely used dependency, if any
+	Copies          []DependencyCopy // copies of the dependency inside the project
+	DuplicateMajors []DuplicateMajor // other majors of Module left in the module graph
+	DocChanges      []DocChange      // doc comment changes of used symbols, if requested
+	Examples        []Example        // new-version usage examples for findings, if requested
+	Hints           []Hint           // added symbols that may replace project code, if requested
+	TODOs           []TODOMatch      // project TODOs that added symbols may address, if requested
+	DeadCode        []DeadFunction   // unreachable project functions holding the only uses of findings, if requested
+	ImportGraph     *ImportGraph     // project packages importing Module, if requested
+	Directories     []DirectoryStats // Go files per project directory, if requested
+	Warnings        []Warning        // non-fatal issues that may make the result incomplete
+	StoppedEarly    bool             // -fail-fast stopped at the first breaking change
+	Floor           *Floor           // minimum version check, if requested
+	LoadErrors      []LoadError      // project load errors analyzed past with AllowErrors
+	UsageOnly       *UsageOnly       // the project's uses of Module when its API could not be diffed
+}
+
+// ShimFile describes the generated compatibility shims
+type ShimFile struct {
+	Path      string   // written file, empty when nothing could be shimmed
+	Functions []string // generated adapter names
+	Skipped   []string // changed symbols that cannot be shimmed, with the reason
+	Diff      FileDiff // the edit of the file
+	DryRun    bool     // the file was only previewed, see Options.DryRun
+}
+
+// ModuleChanges describes how a dependency's own go.mod changes between versions
+type ModuleChanges struct {
+	OldGoVersion string
+	NewGoVersion string
+	Added        []string // "path version"
+	Removed      []string // "path version"
+	Upgraded     []string // "path old -> new"
+}
+
+// TestFailure identifies a failing test, or a failing package when Test is empty
+type TestFailure struct {
+	Package string
+	Test    string
+}
+
+// BenchmarkDelta compares a benchmark's ns/op before and after the upgrade
+type BenchmarkDelta struct {
+	Name         string
+	OldNsPerOp   float64
+	NewNsPerOp   float64
+	DeltaPercent float64
+	Regression   bool // significant slowdown
+}
+
+// BinaryImpact describes how the upgrade changes the size of a built binary
+type BinaryImpact struct {
+	Package string
+	OldSize int64
+	NewSize int64
+	Error   string // set when either build failed
+}
+
+// Footprint describes how the upgrade changes the weight of the dependency
+type Footprint struct {
+	OldSize        int64 // module zip size in bytes, 0 if unknown
+	NewSize        int64
+	OldPackages    int
+	NewPackages    int
+	PackagesAdded  []string
+	ModulesAdded   []string // "path version" of modules the upgrade adds to the project's module graph
+	ModulesRemoved []string // "path version" of modules the upgrade drops from it
+
+	// SharedBumps lists modules the project requires directly that the new
+	// version requires at a higher version, so the upgrade raises them too
+	SharedBumps []SharedBump
+}
+
+// SharedBump is a module both the project and the new version of the
+// dependency require, whose version the upgrade raises for the project
+type SharedBump struct {
+	Path           string
+	ProjectVersion string // version the project requires today
+	OldVersion     string // version the old version of the dependency requires, empty if none
+	NewVersion     string // version the new version requires, which the project will build with
+}
+
+// Replacement describes a go.mod replace directive for the audited module
+type Replacement struct {
+	Path    string
+	Version string // empty for local directory replacements
+	Dir     string // resolved directory for local replacements
+	Ignored bool   // true when the required version was diffed instead
+}
+
+// Requirement represents a module required by the audited dependency
+type Requirement struct {
+	Path           string
+	Version        string
+	ProjectVersion string // version the project requires today, empty if none
 }
 
 // HasBreakingChanges returns true if the result contains breaking changes
 func (r *Result) HasBreakingChanges() bool {
-	if r.Changes == nil {
-		return false
-	}
-	return len(r.Changes.Removed) > 0 ||
-		len(r.Changes.Changed) > 0 ||
-		len(r.Changes.InterfaceChanges) > 0
+	return Classifier{}.Breaking(r)
 }
 
 // HasWarnings returns true if the result contains warnings
 func (r *Result) HasWarnings() bool {
-	if r.Changes == nil {
-		return false
+	return Classifier{}.Warnings(r)
+}
+
+// hasBenchmarkRegressions reports whether any benchmark slowed down significantly
+func (r *Result) hasBenchmarkRegressions() bool {
+	for _, b := range r.Benchmarks {
+		if b.Regression {
+			return true
+		}
 	}
-	return len(r.Changes.Added) > 0 || len(r.UnusedDeps) > 0
+	return false
 }
 
-// API represents the exported API surface of a module
+// API represents the exported API surface of a module. Its JSON form is
+// the API snapshot format.
 type API struct {
-	Funcs      map[string]*Function
-	Types      map[string]*Type
-	Interfaces map[string]*Interface
+	Funcs      map[string]*Function  `json:"funcs"`
+	Types      map[string]*Type      `json:"types"`
+	Interfaces map[string]*Interface `json:"interfaces"`
+	Consts     map[string]*Const     `json:"consts,omitempty"`
+	Packages   []string              `json:"packages"`
+	Generated  map[string]bool       `json:"generated,omitempty"` // package paths made entirely of generated code
+	Ambiguous  map[string]bool       `json:"ambiguous,omitempty"` // names exported by more than one package; the maps keep only one
+
+	fset *token.FileSet // positions of the type-checked declarations, nil for APIs built by hand
 }
 
 // Function represents an exported function or method
 type Function struct {
-	Name      string
-	Signature string
-	PkgPath   string
-	IsMethod  bool
+	Name      string    `json:"name"`
+	Signature Signature `json:"signature"`
+	PkgPath   string    `json:"pkg_path"`
+	IsMethod  bool      `json:"is_method,omitempty"`
+	Unstable  bool      `json:"unstable,omitempty"` // internal, experimental, or documented as unstable
+	Doc       string    `json:"doc,omitempty"`      // doc comment text
+
+	// PromotedFrom names the embedded type a method is promoted from, and is
+	// empty for methods declared on the type itself
+	PromotedFrom string `json:"promoted_from,omitempty"`
+
+	// ViaModule is the module whose type an alias re-exports, for methods of
+	// an alias such as type Logger = zap.Logger; see Type.ViaModule
+	ViaModule string `json:"via_module,omitempty"`
+
+	obj *types.Func // type-checked declaration, nil for APIs built by hand
 }
 
 // Type represents an exported type
 type Type struct {
-	Name    string
-	Kind    string
-	PkgPath string
+	Name       string   `json:"name"`
+	Kind       string   `json:"kind"`
+	TypeParams string   `json:"type_params,omitempty"` // such as [T any], empty unless generic
+	Fields     []string `json:"fields,omitempty"`      // exported fields of a struct
+	PkgPath    string   `json:"pkg_path"`
+	Unstable   bool     `json:"unstable,omitempty"`
+	Doc        string   `json:"doc,omitempty"`
+
+	// ViaModule is set when the type is an alias re-exporting a type of
+	// another module, which can change when that module does even if the
+	// dependency's own code does not
+	ViaModule string `json:"via_module,omitempty"`
+
+	obj *types.TypeName // type-checked declaration, nil for APIs built by hand
 }
 
 // Interface represents an exported interface
 type Interface struct {
-	Name    string
-	Methods []string
-	PkgPath string
+	Name     string            `json:"name"`
+	Methods  []string          `json:"methods"`
+	Embedded map[string]string `json:"embedded,omitempty"` // method name -> embedded interface declaring it
+	PkgPath  string            `json:"pkg_path"`
+	Unstable bool              `json:"unstable,omitempty"`
+	Doc      string            `json:"doc,omitempty"`
+
+	ViaModule string `json:"via_module,omitempty"` // see Type.ViaModule
+
+	obj *types.TypeName // type-checked declaration, nil for APIs built by hand
+}
+
+// Const represents an exported constant. Constants are listed in API
+// snapshots but not diffed.
+type Const struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Value   string `json:"value"`
+	PkgPath string `json:"pkg_path"`
+	Doc     string `json:"doc,omitempty"`
 }
 
 // Usage tracks which symbols are used in the project
 type Usage struct {
 	Symbols map[string][]Location
 	Imports map[string]bool
+
+	// All treats every symbol as used, without locations, so diffs made
+	// without a project report the whole breaking surface
+	All bool
+
+	instances map[string][]instance      // generic symbols by name, with their type arguments
+	types     []projectType              // project types, for interface satisfaction checks
+	aliases   map[*types.TypeName]string // types re-exported by aliases of the target packages -> alias name
+}
+
+// uses returns where a symbol is used and whether changes to it should be reported
+func (u *Usage) uses(name string) ([]Location, bool) {
+	locations := u.Symbols[name]
+	return locations, len(locations) > 0 || u.All
 }
 
 // Location represents a source code location
 type Location struct {
-	File string
-	Line int
+	File   string
+	Line   int
+	Column int    // 1-based, 0 if unknown
+	Kind   string // how the symbol is used, see the UsageKind constants; empty for plain references
+
+	// Approximate is set when the location's package failed to load and its
+	// type information may be incomplete (see Options.AllowErrors)
+	Approximate bool
 }
 
+// Usage kinds with their own breakage semantics
+const (
+	UsageAssertion    = "assertion"         // x.(T) or a type switch case: fails to compile when T is removed
+	UsageConversion   = "conversion"        // T(v): invalid once T is removed or its underlying type changes
+	UsageCall         = "called"            // F(...) or x.M(...): breaks when the signature changes
+	UsageCompositeLit = "composite literal" // T{...}: breaks when fields are removed or renamed
+	UsageEmbedded     = "embedded"          // embedded in a project struct or interface: promoted members go with it
+	UsageImplemented  = "implemented"       // var _ I = T{}: the project type must keep up with I's methods
+	UsageTypeDecl     = "type declaration"  // type X dep.T: X inherits the underlying type of T
+	UsageKeyedField   = "keyed field"       // T{F: v}: breaks when field F is removed
+)
+
 // Diff represents the differences between two API surfaces
 type Diff struct {
 	Removed          []RemovedSymbol
 	Added            []AddedSymbol
 	Changed          []ChangedSignature
 	InterfaceChanges []InterfaceChange
+	Moved            []MovedSymbol    // symbols that moved to another package
+	Generated        []GeneratedGroup // churn collapsed per generated package
+}
+
+// GeneratedGroup summarizes the changes in a package of generated code
+type GeneratedGroup struct {
+	Package string
+	Changes int // added, removed, and changed symbols
+	Used    int // changes to symbols the project uses, also reported individually
+}
+
+// BreakingCount returns the number of findings reported as errors, by
+// default those in stable APIs
+func (d *Diff) BreakingCount() int {
+	return Classifier{}.Count(d, SeverityError)
+}
+
+// WarningCount returns the number of findings reported as warnings, by
+// default those in unstable APIs
+func (d *Diff) WarningCount() int {
+	return Classifier{}.Count(d, SeverityWarning)
+}
+
+// AffectedLocations returns the number of project locations touched by
+// breaking findings
+func (d *Diff) AffectedLocations() int {
+	return Classifier{}.AffectedLocations(d)
+}
+
+// UnstableCount returns the number of findings in knowingly unstable APIs,
+// which are reported as warnings rather than breaking changes
+func (d *Diff) UnstableCount() int {
+	count := 0
+	for _, removed := range d.Removed {
+		if removed.Unstable {
+			count++
+		}
+	}
+	for _, changed := range d.Changed {
+		if changed.Unstable {
+			count++
+		}
+	}
+	for _, iface := range d.InterfaceChanges {
+		if iface.Unstable {
+			count++
+		}
+	}
+	for _, moved := range d.Moved {
+		if moved.Unstable {
+			count++
+		}
+	}
+	return count
 }
 
 // RemovedSymbol represents a symbol that was removed
 type RemovedSymbol struct {
-	Name   string
-	Type   string // "function", "type", "interface"
-	UsedIn []Location
+	Name      string
+	Type      string // "function", "type", "interface", "field"
+	Package   string // import path of the declaring package
+	UsedIn    []Location
+	Unstable  bool     // the symbol belonged to an unstable API
+	Severity  string   // overridden severity, empty for the default; see Level
+	Platforms []string // GOOS/GOARCH pairs the finding is limited to, empty for all
+	DeadCode  bool     // only code unreachable from the project's entry points uses it, see Options.DeadCode
+	ViaModule string   // module a type alias re-exports the symbol from, see Type.ViaModule
+
+	// Conversion is set when a function became a method or a method a
+	// function, so the removal is really a change in how it is called
+	Conversion *Conversion
+}
+
+// MovedSymbol represents a symbol that now lives in another package, so every
+// import of it must change
+type MovedSymbol struct {
+	Name       string
+	NewName    string // equals Name unless the symbol was also renamed
+	Type       string
+	OldPackage string
+	NewPackage string
+	UsedIn     []Location
+	Unstable   bool
+	Severity   string
+	Platforms  []string
+	DeadCode   bool
 }
 
 // AddedSymbol represents a symbol that was added
 type AddedSymbol struct {
-	Name string
-	Type string
+	Name    string
+	Type    string
+	Package string
 }
 
 // ChangedSignature represents a function/method with changed signature
 type ChangedSignature struct {
-	Name         string
-	OldSignature string
-	NewSignature string
-	UsedIn       []Location
+	Name           string
+	OldSignature   string
+	NewSignature   string
+	Package        string
+	UsedIn         []Location
+	Unstable       bool
+	PromotedFrom   string // embedded type the method is promoted from, if any
+	ViaModule      string // module a type alias re-exports the symbol from, see Type.ViaModule
+	Severity       string
+	ParamNamesOnly bool          // only parameter or result names differ
+	ParamChanges   []ParamChange // parameters and results added, removed, or retyped
+	Platforms      []string
+	DeadCode       bool
+
+	// BehaviorChange labels changes whose calls need more than a mechanical
+	// update, such as BehaviorPanicToError; empty for plain signature changes
+	BehaviorChange string
+
+	// Instantiations checks the project's type arguments of a generic
+	// function or type against its new type parameters
+	Instantiations []Instantiation
 }
 
 // InterfaceChange represents changes to an interface
 type InterfaceChange struct {
 	Name           string
 	AddedMethods   []string
+	AddedVia       map[string]string // added method name -> embedded interface that introduced it
 	RemovedMethods []string
 	ChangedMethods []string
+	Package        string
 	UsedIn         []Location
+	Unstable       bool
+	Severity       string
+	Platforms      []string
+	DeadCode       bool
+	ViaModule      string // module a type alias re-exports the interface from, see Type.ViaModule
+
+	// Implementations lists project types whose satisfaction of the
+	// interface changes, such as types that lose a method it now requires
+	Implementations []Implementation
 }
 
 // ParseUpgrade parses an upgrade specification like "module@version"
@@ -136,6 +136,15 @@ func TestResultHasBreakingChanges(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "only unstable changes",
+			result: &Result{
+				Changes: &Diff{
+					Removed: []RemovedSymbol{{Name: "Fast", Type: "function", Unstable: true}},
+				},
+			},
+			want: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -187,6 +196,23 @@ func TestResultHasWarnings(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "benchmark regression",
+			result: &Result{
+				Changes:    &Diff{},
+				Benchmarks: []BenchmarkDelta{{Name: "BenchmarkA", DeltaPercent: 40, Regression: true}},
+			},
+			want: true,
+		},
+		{
+			name: "unstable changes",
+			result: &Result{
+				Changes: &Diff{
+					Changed: []ChangedSignature{{Name: "Fast", Unstable: true}},
+				},
+			},
+			want: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	lines := func(n int, change map[int]string) string {
+		var b strings.Builder
+		for i := 1; i <= n; i++ {
+			if s, ok := change[i]; ok {
+				b.WriteString(s)
+				continue
+			}
+			b.WriteString("line " + string(rune('a'+i-1)) + "\n")
+		}
+		return b.String()
+	}
+
+	tests := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{name: "equal", old: "a\n", new: "a\n", want: ""},
+		{
+			name: "separate hunks",
+			old:  lines(20, nil),
+			new:  lines(20, map[int]string{2: "line B\n", 18: "", 19: "line s\nline s2\n"}),
+			want: `--- a/f.go
++++ b/f.go
+@@ -1,5 +1,5 @@
+ line a
+-line b
++line B
+ line c
+ line d
+ line e
+@@ -15,6 +15,6 @@
+ line o
+ line p
+ line q
+-line r
+ line s
++line s2
+ line t
+`,
+		},
+		{
+			name: "close changes share a hunk",
+			old:  lines(8, nil),
+			new:  lines(8, map[int]string{1: "first\n", 8: "last\n"}),
+			want: `--- a/f.go
++++ b/f.go
+@@ -1,8 +1,8 @@
+-line a
++first
+ line b
+ line c
+ line d
+ line e
+ line f
+ line g
+-line h
++last
+`,
+		},
+		{
+			name: "empty file",
+			old:  "",
+			new:  "package p\n",
+			want: "--- a/f.go\n+++ b/f.go\n@@ -0,0 +1 @@\n+package p\n",
+		},
+		{
+			name: "missing final newline",
+			old:  "package p",
+			new:  "package p\n",
+			want: "--- a/f.go\n+++ b/f.go\n@@ -1 +1 @@\n-package p\n\\ No newline at end of file\n+package p\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("f.go", []byte(tt.old), []byte(tt.new)); got != tt.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFileDiff(t *testing.T) {
+	want := "--- /dev/null\n+++ b/compat/shims.go\n@@ -0,0 +1,2 @@\n+package compat\n+\n"
+	if got := newFileDiff("compat/shims.go", []byte("package compat\n\n")); got != want {
+		t.Errorf("newFileDiff() =\n%s\nwant:\n%s", got, want)
+	}
+}
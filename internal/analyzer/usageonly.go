@@ -0,0 +1,72 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+)
+
+// UsageOnly is the partial result of an upgrade whose API could not be
+// loaded, for example offline or without credentials for a private module.
+// Without a diff, every use of the module in the project is at risk.
+type UsageOnly struct {
+	Reason  string // why the API diff was unavailable
+	Symbols []SymbolUses
+}
+
+// SymbolUses is a symbol of the module together with the places using it
+type SymbolUses struct {
+	Name   string
+	UsedIn []Location
+}
+
+// unreachableMarkers are fragments of the go command's errors for module
+// downloads that failed on the network or for lack of credentials
+var unreachableMarkers = []string{
+	"401 Unauthorized",
+	"403 Forbidden",
+	"authentication required",
+	"terminal prompts disabled",
+	"could not read Username",
+	"Permission denied (publickey)",
+	"no such host",
+	"connection refused",
+	"connection reset",
+	"network is unreachable",
+	"i/o timeout",
+	"TLS handshake timeout",
+	"dial tcp",
+	"module lookup disabled by GOPROXY=off",
+}
+
+// isUnreachable reports whether loading an API failed because the module
+// could not be downloaded, as opposed to a version that does not exist or a
+// module that does not type-check. Only then is a usage-only result useful.
+func isUnreachable(err error) bool {
+	msg := err.Error()
+	for _, marker := range unreachableMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// usageOnly lists the symbols of usage for a result without a diff
+func usageOnly(reason string, usage *Usage) *UsageOnly {
+	return &UsageOnly{Reason: reason, Symbols: symbolUses(usage)}
+}
+
+// symbolUses lists the symbols of usage sorted by name
+func symbolUses(usage *Usage) []SymbolUses {
+	names := make([]string, 0, len(usage.Symbols))
+	for name := range usage.Symbols {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var symbols []SymbolUses
+	for _, name := range names {
+		symbols = append(symbols, SymbolUses{Name: name, UsedIn: usage.Symbols[name]})
+	}
+	return symbols
+}
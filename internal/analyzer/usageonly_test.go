@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestAnalyzeUsageOnlyWhenNewAPIUnavailable(t *testing.T) {
+	defer mockLatestGoMod(t, "module example.com/lib\n")()
+
+	const module = "example.com/lib"
+	projectPkg := buildUsagePackage(module)
+	oldAPIPkg := buildAPIPackageWithChanges(module, apiDefinition{})
+
+	restore := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		switch patterns[0] {
+		case "./...":
+			return []*packages.Package{projectPkg}, nil
+		case module + "/...@v1.0.0":
+			return []*packages.Package{oldAPIPkg}, nil
+		case module + "/...@v2.0.0":
+			return nil, errors.New("410 Gone: authentication required")
+		default:
+			return nil, nil
+		}
+	})
+	defer restore()
+
+	a := &Analyzer{projectPath: "."}
+	result, err := a.Analyze(&Upgrade{Module: module, NewVersion: "v2.0.0"})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v, want a usage-only result", err)
+	}
+	u := result.UsageOnly
+	if u == nil {
+		t.Fatal("UsageOnly = nil, want the project's uses of the module")
+	}
+	if !strings.Contains(u.Reason, "failed to load new API") || !strings.Contains(u.Reason, "authentication required") {
+		t.Errorf("Reason = %q, want the load error", u.Reason)
+	}
+
+	var names []string
+	for _, s := range u.Symbols {
+		if len(s.UsedIn) == 0 {
+			t.Errorf("symbol %s has no locations", s.Name)
+		}
+		names = append(names, s.Name)
+	}
+	if got := strings.Join(names, ","); got != "Handler,OldFunc,Parse" {
+		t.Errorf("Symbols = %s, want Handler,OldFunc,Parse", got)
+	}
+	if result.HasBreakingChanges() {
+		t.Error("HasBreakingChanges() = true, want false without a diff")
+	}
+	if !result.HasWarnings() {
+		t.Error("HasWarnings() = false, want true so -strict fails")
+	}
+}
+
+func TestAnalyzeFailsWhenNewVersionDoesNotExist(t *testing.T) {
+	defer mockLatestGoMod(t, "module example.com/lib\n")()
+
+	const module = "example.com/lib"
+	projectPkg := buildUsagePackage(module)
+	oldAPIPkg := buildAPIPackageWithChanges(module, apiDefinition{})
+
+	restore := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		switch patterns[0] {
+		case "./...":
+			return []*packages.Package{projectPkg}, nil
+		case module + "/...@v1.0.0":
+			return []*packages.Package{oldAPIPkg}, nil
+		case module + "/...@v2.0.0":
+			return nil, errors.New("example.com/lib@v2.0.0: invalid version: unknown revision v2.0.0")
+		default:
+			return nil, nil
+		}
+	})
+	defer restore()
+
+	a := &Analyzer{projectPath: "."}
+	result, err := a.Analyze(&Upgrade{Module: module, NewVersion: "v2.0.0"})
+	if err == nil || !strings.Contains(err.Error(), "unknown revision") {
+		t.Fatalf("Analyze() = %+v, %v; want the load error rather than a usage-only result", result, err)
+	}
+}
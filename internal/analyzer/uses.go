@@ -0,0 +1,50 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ParseSymbol splits a qualified symbol such as example.com/lib.Client.Do
+// into its package path and the symbol name within it. The symbol starts at
+// the first exported name after the last slash, so gopkg.in/yaml.v3.Node
+// names Node in gopkg.in/yaml.v3.
+func ParseSymbol(spec string) (pkgPath, symbol string, err error) {
+	start := strings.LastIndex(spec, "/") + 1
+	for i := start; i < len(spec); i++ {
+		if spec[i] == '.' && i > 0 && token.IsExported(spec[i+1:]) {
+			return spec[:i], spec[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("invalid symbol %q (expected package.Symbol, such as example.com/lib.Client.Do)", spec)
+}
+
+// FindUses returns every location in the project using symbol from the
+// package pkgPath, independent of any upgrade. Methods are named Type.Method.
+func (a *Analyzer) FindUses(pkgPath, symbol string) ([]Location, error) {
+	a = a.call()
+	if err := a.loadProject(); err != nil {
+		return nil, fmt.Errorf("failed to load project: %w", err)
+	}
+
+	usage := a.findUsageOf(func(imp *packages.Package) bool { return imp.PkgPath == pkgPath })
+	if len(usage.Imports) == 0 {
+		return nil, fmt.Errorf("the project does not import %s", pkgPath)
+	}
+
+	locations := append([]Location(nil), usage.Symbols[symbol]...)
+	sort.Slice(locations, func(i, j int) bool {
+		if locations[i].File != locations[j].File {
+			return locations[i].File < locations[j].File
+		}
+		if locations[i].Line != locations[j].Line {
+			return locations[i].Line < locations[j].Line
+		}
+		return locations[i].Column < locations[j].Column
+	})
+	return locations, nil
+}
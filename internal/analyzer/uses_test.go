@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestParseSymbol(t *testing.T) {
+	tests := []struct {
+		spec, pkgPath, symbol string
+	}{
+		{"example.com/lib.Open", "example.com/lib", "Open"},
+		{"example.com/lib/v2.Client.Do", "example.com/lib/v2", "Client.Do"},
+		{"gopkg.in/yaml.v3.Node", "gopkg.in/yaml.v3", "Node"},
+		{"errors.New", "errors", "New"},
+	}
+	for _, tt := range tests {
+		pkgPath, symbol, err := ParseSymbol(tt.spec)
+		if err != nil || pkgPath != tt.pkgPath || symbol != tt.symbol {
+			t.Errorf("ParseSymbol(%q) = %q, %q, %v; want %q, %q", tt.spec, pkgPath, symbol, err, tt.pkgPath, tt.symbol)
+		}
+	}
+	for _, spec := range []string{"example.com/lib", "example.com/lib.", "errors", "example.com/lib.open", ".Open"} {
+		if _, _, err := ParseSymbol(spec); err == nil {
+			t.Errorf("ParseSymbol(%q) expected error", spec)
+		}
+	}
+}
+
+func TestFindUses(t *testing.T) {
+	lib := checkSource(t, "example.com/lib", `package lib
+
+type Client struct{}
+
+func (c *Client) Do() {}
+
+func Open() *Client { return nil }
+`, nil)
+	other := checkSource(t, "example.com/lib/other", "package other\n\nfunc Open() {}\n", nil)
+	app := checkSource(t, "example.com/app", `package app
+
+import (
+	"example.com/lib"
+	"example.com/lib/other"
+)
+
+func run() {
+	c := lib.Open()
+	c.Do()
+	other.Open()
+	lib.Open().Do()
+}
+`, map[string]*types.Package{"example.com/lib": lib.Types, "example.com/lib/other": other.Types})
+	app.Imports = map[string]*packages.Package{
+		"example.com/lib":       {PkgPath: "example.com/lib"},
+		"example.com/lib/other": {PkgPath: "example.com/lib/other"},
+	}
+	restoreLoad := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		return []*packages.Package{app}, nil
+	})
+	defer restoreLoad()
+	restorePrint := mockPackagesPrintErrors(func([]*packages.Package) int { return 0 })
+	defer restorePrint()
+
+	a := &Analyzer{projectPath: t.TempDir()}
+	lines := func(locations []Location) []string {
+		var got []string
+		for _, loc := range locations {
+			got = append(got, fmt.Sprintf("%d:%d:%s", loc.Line, loc.Column, loc.Kind))
+		}
+		return got
+	}
+
+	opens, err := a.FindUses("example.com/lib", "Open")
+	if err != nil {
+		t.Fatalf("FindUses() error = %v", err)
+	}
+	if got, want := lines(opens), []string{"9:11:called", "12:6:called"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("uses of lib.Open = %v, want %v", got, want)
+	}
+
+	dos, err := a.FindUses("example.com/lib", "Client.Do")
+	if err != nil {
+		t.Fatalf("FindUses() error = %v", err)
+	}
+	if got, want := lines(dos), []string{"10:4:called", "12:13:called"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("uses of lib.Client.Do = %v, want %v", got, want)
+	}
+
+	if _, err := a.FindUses("example.com/unrelated", "Open"); err == nil {
+		t.Error("expected an error for a package the project does not import")
+	}
+}
@@ -0,0 +1,110 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Verification is the outcome of checking that a project finished migrating
+// to a new version of a dependency
+type Verification struct {
+	Module     string
+	OldVersion string // version the project migrated from
+	NewVersion string // version go.mod requires now
+	Stragglers []Straggler
+}
+
+// Complete reports whether no references to the old API remain
+func (v *Verification) Complete() bool {
+	return len(v.Stragglers) == 0
+}
+
+// Straggler is a project compile error left after the migration, attributed
+// to the breaking change it most likely stems from
+type Straggler struct {
+	Symbol   string // removed, moved, or changed symbol named by the error, empty if none
+	Position string // file:line:column, empty if unknown
+	Message  string
+}
+
+// Verify checks that a project requiring upgrade.NewVersion compiles against
+// it without references to symbols removed, moved, or changed since the
+// version in Options.FromVersion. References to changed symbols that type
+// check against the new version count as migrated.
+func (a *Analyzer) Verify(upgrade *Upgrade) (*Verification, error) {
+	a = a.call()
+	a.opts.AllowErrors = true // stragglers are compile errors
+	if a.opts.FromVersion == "" {
+		return nil, fmt.Errorf("verifying a migration needs the version it started from")
+	}
+	if err := a.loadProject(); err != nil {
+		return nil, fmt.Errorf("failed to load project: %w", err)
+	}
+
+	module := a.resolveModulePath(upgrade.Module)
+	current, err := a.getCurrentVersion(module)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine current version: %w", err)
+	}
+	if current != upgrade.NewVersion {
+		return nil, fmt.Errorf("the project requires %s %s; update go.mod to %s before verifying the migration", module, current, upgrade.NewVersion)
+	}
+
+	oldAPI, err := a.loadModuleAPI(module, a.opts.FromVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load old API: %w", err)
+	}
+	newAPI, err := a.loadModuleAPI(module, upgrade.NewVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load new API: %w", err)
+	}
+
+	v := &Verification{Module: module, OldVersion: a.opts.FromVersion, NewVersion: upgrade.NewVersion}
+	names := breakingNames(diffAPIs(oldAPI, newAPI, &Usage{All: true}))
+	for _, pkg := range a.pkgs {
+		for _, e := range pkg.Errors {
+			v.Stragglers = append(v.Stragglers, Straggler{
+				Symbol:   straggledSymbol(e.Msg, names),
+				Position: e.Pos,
+				Message:  e.Msg,
+			})
+		}
+	}
+	return v, nil
+}
+
+// breakingNames lists the symbols of a diff that old code may still
+// reference, in report order
+func breakingNames(diff *Diff) []string {
+	var names []string
+	for _, r := range diff.Removed {
+		names = append(names, r.Name)
+	}
+	for _, m := range diff.Moved {
+		names = append(names, m.Name)
+	}
+	for _, c := range diff.Changed {
+		names = append(names, c.Name)
+	}
+	for _, ic := range diff.InterfaceChanges {
+		for _, methods := range [][]string{ic.RemovedMethods, ic.ChangedMethods, ic.AddedMethods} {
+			for _, method := range methods {
+				names = append(names, ic.Name+"."+method)
+			}
+		}
+	}
+	return names
+}
+
+// straggledSymbol returns the first of names whose last element appears as
+// a word in a compile error, such as OldFunc in "undefined: lib.OldFunc"
+func straggledSymbol(msg string, names []string) string {
+	for _, name := range names {
+		word := name[strings.LastIndex(name, ".")+1:]
+		if regexp.MustCompile(`\b` + regexp.QuoteMeta(word) + `\b`).MatchString(msg) {
+			return name
+		}
+	}
+	return ""
+}
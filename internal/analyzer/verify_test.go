@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"go/types"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestVerify(t *testing.T) {
+	const module = "example.com/lib"
+	oldAPIPkg := buildAPIPackageWithChanges(module, apiDefinition{
+		funcs: map[string]*types.Signature{"OldFunc": newSignature(nil, nil), "Keep": newSignature(nil, nil)},
+	})
+	newAPIPkg := buildAPIPackageWithChanges(module, apiDefinition{
+		funcs: map[string]*types.Signature{"Keep": newSignature(nil, nil)},
+	})
+	projectPkg := &packages.Package{
+		PkgPath: "example.com/user",
+		Imports: map[string]*packages.Package{
+			module: {PkgPath: module, Module: &packages.Module{Path: module, Version: "v2.0.0"}},
+		},
+	}
+
+	restore := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		switch patterns[0] {
+		case "./...":
+			return []*packages.Package{projectPkg}, nil
+		case module + "/...@v1.0.0":
+			return []*packages.Package{oldAPIPkg}, nil
+		case module + "/...@v2.0.0":
+			return []*packages.Package{newAPIPkg}, nil
+		default:
+			return nil, nil
+		}
+	})
+	defer restore()
+
+	a := &Analyzer{projectPath: ".", opts: Options{FromVersion: "v1.0.0"}}
+	v, err := a.Verify(&Upgrade{Module: module, NewVersion: "v2.0.0"})
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if !v.Complete() {
+		t.Errorf("Verify() stragglers = %+v, want a complete migration", v.Stragglers)
+	}
+
+	projectPkg.Errors = []packages.Error{
+		{Pos: "main.go:9:6", Msg: "undefined: lib.OldFunc"},
+		{Pos: "main.go:12:2", Msg: "declared and not used: x"},
+	}
+	v, err = a.Verify(&Upgrade{Module: module, NewVersion: "v2.0.0"})
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	want := []Straggler{
+		{Symbol: "OldFunc", Position: "main.go:9:6", Message: "undefined: lib.OldFunc"},
+		{Position: "main.go:12:2", Message: "declared and not used: x"},
+	}
+	if v.Complete() || len(v.Stragglers) != 2 || v.Stragglers[0] != want[0] || v.Stragglers[1] != want[1] {
+		t.Errorf("Verify() stragglers = %+v, want %+v", v.Stragglers, want)
+	}
+
+	_, err = a.Verify(&Upgrade{Module: module, NewVersion: "v3.0.0"})
+	if err == nil || !strings.Contains(err.Error(), "update go.mod to v3.0.0") {
+		t.Errorf("Verify() before bumping go.mod error = %v", err)
+	}
+
+	a.opts.FromVersion = ""
+	if _, err := a.Verify(&Upgrade{Module: module, NewVersion: "v2.0.0"}); err == nil {
+		t.Error("Verify() without FromVersion should fail")
+	}
+}
+
+func TestStraggledSymbol(t *testing.T) {
+	names := []string{"Client.Do", "Handler.Close", "Parse"}
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{"c.Do undefined (type *lib.Client has no field or method Do)", "Client.Do"},
+		{"impl does not implement lib.Handler (missing method Close)", "Handler.Close"},
+		{"not enough arguments in call to lib.Parse", "Parse"},
+		{"undefined: lib.ParseAll", ""},
+	}
+	for _, tt := range tests {
+		if got := straggledSymbol(tt.msg, names); got != tt.want {
+			t.Errorf("straggledSymbol(%q) = %q, want %q", tt.msg, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Warning codes
+const (
+	WarnCacheMiss       = "cache-miss"
+	WarnFootprint       = "footprint"
+	WarnPartialLoad     = "partial-load"
+	WarnProvenance      = "provenance"
+	WarnRetractions     = "retractions"
+	WarnSkippedPackages = "skipped-packages"
+	WarnUnusedDeps      = "unused-deps"
+)
+
+// maxWarnedPackages caps how many package paths a warning lists
+const maxWarnedPackages = 5
+
+// Warning is a non-fatal issue that may make the result incomplete
+type Warning struct {
+	Code    string
+	Message string
+	File    string // file the warning is about, such as the project's go.mod
+}
+
+// warn records a warning against the project's go.mod
+func (a *Analyzer) warn(code, format string, args ...interface{}) {
+	w := Warning{Code: code, Message: fmt.Sprintf(format, args...)}
+	if f, err := a.projectModFile(); err == nil && f.Syntax != nil {
+		w.File = f.Syntax.Name
+	} else if a.projectPath != "" {
+		w.File = filepath.Join(a.projectPath, "go.mod")
+	}
+	a.warnings = append(a.warnings, w)
+}
+
+// checkLoadedPackages warns about dependency packages that loaded with errors,
+// whose API may be incomplete, and those without type information, which are
+// left out of the diff entirely
+func (a *Analyzer) checkLoadedPackages(target string, pkgs []*packages.Package) {
+	var partial, skipped []string
+	for _, pkg := range pkgs {
+		switch {
+		case pkg.Types == nil:
+			skipped = append(skipped, pkg.PkgPath)
+		case len(pkg.Errors) > 0:
+			partial = append(partial, pkg.PkgPath)
+		}
+	}
+	if len(partial) > 0 {
+		a.warn(WarnPartialLoad, "%d package(s) of %s loaded with errors, so their API may be incomplete: %s",
+			len(partial), target, summarizePackages(partial))
+	}
+	if len(skipped) > 0 {
+		a.warn(WarnSkippedPackages, "%d package(s) of %s could not be type-checked and were skipped: %s",
+			len(skipped), target, summarizePackages(skipped))
+	}
+}
+
+// summarizePackages lists the first few package paths in order
+func summarizePackages(paths []string) string {
+	sort.Strings(paths)
+	if len(paths) <= maxWarnedPackages {
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf("%s, and %d more", strings.Join(paths[:maxWarnedPackages], ", "), len(paths)-maxWarnedPackages)
+}
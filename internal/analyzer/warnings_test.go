@@ -0,0 +1,44 @@
+package analyzer
+
+import (
+	"go/types"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestCheckLoadedPackages(t *testing.T) {
+	project := t.TempDir()
+	writeFile(t, filepath.Join(project, "go.mod"), "module example.com/app\n\ngo 1.21\n")
+
+	pkgs := []*packages.Package{
+		{PkgPath: "example.com/lib", Types: types.NewPackage("example.com/lib", "lib")},
+		{PkgPath: "example.com/lib/broken", Types: types.NewPackage("example.com/lib/broken", "broken"), Errors: []packages.Error{{Msg: "undefined: x"}}},
+		{PkgPath: "example.com/lib/cgo"},
+	}
+
+	a := &Analyzer{projectPath: project}
+	a.checkLoadedPackages("example.com/lib@v1.2.0", pkgs)
+
+	if len(a.warnings) != 2 {
+		t.Fatalf("warnings = %+v, want partial-load and skipped-packages", a.warnings)
+	}
+	if w := a.warnings[0]; w.Code != WarnPartialLoad || !strings.Contains(w.Message, "example.com/lib/broken") {
+		t.Errorf("warnings[0] = %+v, want partial load of example.com/lib/broken", w)
+	}
+	if w := a.warnings[1]; w.Code != WarnSkippedPackages || !strings.Contains(w.Message, "example.com/lib/cgo") {
+		t.Errorf("warnings[1] = %+v, want example.com/lib/cgo skipped", w)
+	}
+	if want := filepath.Join(project, "go.mod"); a.warnings[0].File != want {
+		t.Errorf("File = %q, want %q", a.warnings[0].File, want)
+	}
+}
+
+func TestSummarizePackages(t *testing.T) {
+	got := summarizePackages([]string{"g", "f", "e", "d", "c", "b", "a"})
+	if want := "a, b, c, d, e, and 2 more"; got != want {
+		t.Errorf("summarizePackages() = %q, want %q", got, want)
+	}
+}
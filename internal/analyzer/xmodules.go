@@ -0,0 +1,87 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Modules under golang.org/x/ need targeted handling:
+//
+//   - They rarely have a package at the module root, so loading module@version
+//     finds nothing. Their packages are loaded by path instead.
+//   - Some, like golang.org/x/tools, have hundreds of packages. When the
+//     project imports the module, only the imported packages are loaded; the
+//     rest of the module cannot break the project.
+//   - Users often name a package (golang.org/x/tools/go/packages) rather than
+//     the module; resolveModulePath maps it to the containing module.
+const xModulePrefix = "golang.org/x/"
+
+// isXModule reports whether module gets golang.org/x/ handling
+func isXModule(module string) bool {
+	return strings.HasPrefix(module, xModulePrefix)
+}
+
+// apiPatterns returns the package patterns to load for module@version, and
+// the packages the load is scoped to, if any. Every package of the module is
+// loaded, so symbols that move into another package are still found;
+// golang.org/x/ modules the project imports are scoped to those packages.
+func (a *Analyzer) apiPatterns(module, version string) (patterns, scope []string) {
+	if isXModule(module) {
+		scope = a.importedPackages(module)
+	}
+	if len(scope) == 0 {
+		return []string{fmt.Sprintf("%s/...@%s", module, version)}, nil
+	}
+	for _, pkg := range scope {
+		patterns = append(patterns, pkg+"@"+version)
+	}
+	return patterns, scope
+}
+
+// importedPackages lists the packages of module the project imports
+func (a *Analyzer) importedPackages(module string) []string {
+	seen := make(map[string]bool)
+	for _, pkg := range a.pkgs {
+		for _, imp := range pkg.Imports {
+			if imp.Module != nil && imp.Module.Path == module {
+				seen[imp.PkgPath] = true
+			}
+		}
+	}
+	return sortedKeys(seen)
+}
+
+// resolveModulePath maps a package path to the dependency module containing
+// it, so golang.org/x/tools/go/packages@v0.20.0 audits golang.org/x/tools
+func (a *Analyzer) resolveModulePath(path string) string {
+	var longest string
+	for _, pkg := range a.pkgs {
+		for _, imp := range pkg.Imports {
+			if imp.Module == nil {
+				continue
+			}
+			mod := imp.Module.Path
+			if mod == path {
+				return path
+			}
+			if strings.HasPrefix(path, mod+"/") && len(mod) > len(longest) {
+				longest = mod
+			}
+		}
+	}
+	if longest == "" {
+		return path
+	}
+	return longest
+}
+
+// scopeKey distinguishes cached APIs loaded for different package scopes
+func scopeKey(scope []string) string {
+	if len(scope) == 0 {
+		return ""
+	}
+	sorted := append([]string(nil), scope...)
+	sort.Strings(sorted)
+	return "|scope=" + strings.Join(sorted, ",")
+}
@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// projectImporting returns an analyzer whose project imports the given packages of module
+func projectImporting(module string, pkgPaths ...string) *Analyzer {
+	imports := make(map[string]*packages.Package)
+	for _, path := range pkgPaths {
+		imports[path] = &packages.Package{PkgPath: path, Module: &packages.Module{Path: module, Version: "v0.5.0"}}
+	}
+	return &Analyzer{pkgs: []*packages.Package{{PkgPath: "example.com/app", Imports: imports}}}
+}
+
+func TestAPIPatterns(t *testing.T) {
+	tests := []struct {
+		name         string
+		a            *Analyzer
+		module       string
+		wantPatterns []string
+		wantScope    []string
+	}{
+		{
+			name:         "regular module",
+			a:            projectImporting("github.com/pkg/errors", "github.com/pkg/errors"),
+			module:       "github.com/pkg/errors",
+			wantPatterns: []string{"github.com/pkg/errors/...@v1.0.0"},
+		},
+		{
+			name:         "x module scoped to imported packages",
+			a:            projectImporting("golang.org/x/sync", "golang.org/x/sync/errgroup", "golang.org/x/sync/semaphore"),
+			module:       "golang.org/x/sync",
+			wantPatterns: []string{"golang.org/x/sync/errgroup@v1.0.0", "golang.org/x/sync/semaphore@v1.0.0"},
+			wantScope:    []string{"golang.org/x/sync/errgroup", "golang.org/x/sync/semaphore"},
+		},
+		{
+			name:         "x module not imported yet",
+			a:            &Analyzer{},
+			module:       "golang.org/x/sync",
+			wantPatterns: []string{"golang.org/x/sync/...@v1.0.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patterns, scope := tt.a.apiPatterns(tt.module, "v1.0.0")
+			if !reflect.DeepEqual(patterns, tt.wantPatterns) || !reflect.DeepEqual(scope, tt.wantScope) {
+				t.Errorf("apiPatterns() = %v, %v, want %v, %v", patterns, scope, tt.wantPatterns, tt.wantScope)
+			}
+		})
+	}
+}
+
+func TestResolveModulePath(t *testing.T) {
+	a := projectImporting("golang.org/x/tools", "golang.org/x/tools/go/packages")
+
+	tests := map[string]string{
+		"golang.org/x/tools":                "golang.org/x/tools",
+		"golang.org/x/tools/go/packages":    "golang.org/x/tools",
+		"golang.org/x/tools/go/ast/inspect": "golang.org/x/tools",
+		"golang.org/x/toolsmith":            "golang.org/x/toolsmith",
+		"github.com/other/mod":              "github.com/other/mod",
+	}
+	for path, want := range tests {
+		if got := a.resolveModulePath(path); got != want {
+			t.Errorf("resolveModulePath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestFilterPackages_InternalAndCommands(t *testing.T) {
+	pkgs := []*packages.Package{
+		{Name: "errgroup", PkgPath: "golang.org/x/sync/errgroup"},
+		{Name: "event", PkgPath: "golang.org/x/tools/internal/event"},
+		{Name: "main", PkgPath: "golang.org/x/tools/cmd/stringer"},
+		{Name: "main", PkgPath: "github.com/other/mod/cmd/tool"},
+		{Name: "util", PkgPath: "github.com/other/mod/internal/util"},
+		{Name: "parser", PkgPath: "github.com/internal/mod/parser", Module: &packages.Module{Path: "github.com/internal/mod"}},
+	}
+
+	for _, includeTests := range []bool{false, true} {
+		a := &Analyzer{opts: Options{IncludeTestPackages: includeTests}}
+		var got []string
+		for _, pkg := range a.filterPackages(pkgs) {
+			got = append(got, pkg.PkgPath)
+		}
+		want := []string{"golang.org/x/sync/errgroup", "github.com/internal/mod/parser"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("filterPackages(includeTests=%v) = %v, want %v", includeTests, got, want)
+		}
+	}
+}
+
+func TestLoadModuleAPI_XModuleLoadsImportedPackages(t *testing.T) {
+	var gotPatterns []string
+	restore := mockPackagesLoad(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		gotPatterns = patterns
+		return []*packages.Package{buildAPIPackage("golang.org/x/sync/errgroup")}, nil
+	})
+	defer restore()
+
+	a := projectImporting("golang.org/x/sync", "golang.org/x/sync/errgroup")
+	a.opts.Cache = NewCache()
+	api, err := a.loadModuleAPI("golang.org/x/sync", "v0.6.0")
+	if err != nil {
+		t.Fatalf("loadModuleAPI() error = %v", err)
+	}
+	if want := []string{"golang.org/x/sync/errgroup@v0.6.0"}; !reflect.DeepEqual(gotPatterns, want) {
+		t.Errorf("patterns = %v, want %v", gotPatterns, want)
+	}
+	if api.Funcs["Func"] == nil {
+		t.Errorf("API missing Func: %v", api.Funcs)
+	}
+
+	// A project importing more of the module must not reuse the narrower API
+	wider := projectImporting("golang.org/x/sync", "golang.org/x/sync/errgroup", "golang.org/x/sync/singleflight")
+	wider.opts.Cache = a.opts.Cache
+	gotPatterns = nil
+	if _, err := wider.loadModuleAPI("golang.org/x/sync", "v0.6.0"); err != nil {
+		t.Fatalf("loadModuleAPI() error = %v", err)
+	}
+	if len(gotPatterns) != 2 {
+		t.Errorf("patterns = %v, want a fresh load of both packages", gotPatterns)
+	}
+}
@@ -0,0 +1,131 @@
+// Package fixture generates synthetic module versions with a chosen number
+// of API changes, for tests and benchmarks that need modules larger or more
+// varied than hand-written testdata libraries.
+package fixture
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultModule is the module path of fixtures that do not name one
+const DefaultModule = "example.com/fixture"
+
+// Spec describes a fixture module and the changes its new version makes.
+// Counts are per package. Declarations are named after their package, such
+// as P3F8 for function 8 of package p3, since an API is keyed by name; the
+// changes apply to the first declarations of each kind.
+type Spec struct {
+	Module     string // module path, DefaultModule if empty
+	Packages   int    // packages p0, p1, ...
+	Funcs      int    // exported functions
+	Structs    int    // exported structs with fields A and B and a method
+	Interfaces int    // exported interfaces
+	Consts     int    // exported constants
+
+	RemovedFuncs      int // functions the new version removes
+	ChangedFuncs      int // functions that gain a parameter, after the removed ones
+	AddedFuncs        int // functions the new version adds
+	RemovedFields     int // structs that lose field B
+	RemovedMethods    int // structs that lose their method
+	ChangedInterfaces int // interfaces that gain a Close method
+}
+
+// Validate reports a spec whose changes need more declarations than it has
+func (s Spec) Validate() error {
+	switch {
+	case s.Packages < 1:
+		return fmt.Errorf("a fixture needs at least one package")
+	case s.Funcs < 0 || s.Structs < 0 || s.Interfaces < 0 || s.Consts < 0 ||
+		s.RemovedFuncs < 0 || s.ChangedFuncs < 0 || s.AddedFuncs < 0 ||
+		s.RemovedFields < 0 || s.RemovedMethods < 0 || s.ChangedInterfaces < 0:
+		return fmt.Errorf("fixture counts cannot be negative")
+	case s.RemovedFuncs+s.ChangedFuncs > s.Funcs:
+		return fmt.Errorf("%d removed and %d changed functions exceed the %d functions", s.RemovedFuncs, s.ChangedFuncs, s.Funcs)
+	case s.RemovedFields > s.Structs:
+		return fmt.Errorf("%d structs losing a field exceed the %d structs", s.RemovedFields, s.Structs)
+	case s.RemovedMethods > s.Structs:
+		return fmt.Errorf("%d structs losing a method exceed the %d structs", s.RemovedMethods, s.Structs)
+	case s.ChangedInterfaces > s.Interfaces:
+		return fmt.Errorf("%d changed interfaces exceed the %d interfaces", s.ChangedInterfaces, s.Interfaces)
+	}
+	return nil
+}
+
+// Write writes the old version of the fixture module to dir, or the new one
+// when newVersion is set
+func Write(dir string, spec Spec, newVersion bool) error {
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	module := spec.Module
+	if module == "" {
+		module = DefaultModule
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module "+module+"\n\ngo 1.21\n"), 0o644); err != nil {
+		return err
+	}
+	for p := 0; p < spec.Packages; p++ {
+		name := fmt.Sprintf("p%d", p)
+		if err := os.MkdirAll(filepath.Join(dir, name), 0o755); err != nil {
+			return err
+		}
+		src := Package(name, spec, newVersion)
+		if err := os.WriteFile(filepath.Join(dir, name, name+".go"), []byte(src), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Package renders the source of one fixture package
+func Package(name string, spec Spec, newVersion bool) string {
+	prefix := strings.ToUpper(name)
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Package %s is a generated fixture.\npackage %s\n", name, name)
+
+	for i := 0; i < spec.Funcs; i++ {
+		switch {
+		case newVersion && i < spec.RemovedFuncs:
+			continue
+		case newVersion && i < spec.RemovedFuncs+spec.ChangedFuncs:
+			fmt.Fprintf(&b, "\n// %[1]sF%[2]d is function %[2]d.\nfunc %[1]sF%[2]d(a int, s string, n int) (string, error) { return s, nil }\n", prefix, i)
+		default:
+			fmt.Fprintf(&b, "\n// %[1]sF%[2]d is function %[2]d.\nfunc %[1]sF%[2]d(a int, s string) (string, error) { return s, nil }\n", prefix, i)
+		}
+	}
+	if newVersion {
+		for i := 0; i < spec.AddedFuncs; i++ {
+			fmt.Fprintf(&b, "\n// %[1]sNew%[2]d is added function %[2]d.\nfunc %[1]sNew%[2]d() {}\n", prefix, i)
+		}
+	}
+
+	for i := 0; i < spec.Structs; i++ {
+		fmt.Fprintf(&b, "\n// %[1]sT%[2]d is struct %[2]d.\ntype %[1]sT%[2]d struct {\n\tA int\n", prefix, i)
+		if !newVersion || i >= spec.RemovedFields {
+			b.WriteString("\tB string\n")
+		}
+		b.WriteString("}\n")
+		if !newVersion || i >= spec.RemovedMethods {
+			fmt.Fprintf(&b, "\n// M%[2]d returns A.\nfunc (t *%[1]sT%[2]d) M%[2]d() int { return t.A }\n", prefix, i)
+		}
+	}
+
+	for i := 0; i < spec.Interfaces; i++ {
+		fmt.Fprintf(&b, "\n// %[1]sI%[2]d is interface %[2]d.\ntype %[1]sI%[2]d interface {\n\tGet(key string) (string, error)\n", prefix, i)
+		if newVersion && i < spec.ChangedInterfaces {
+			b.WriteString("\tClose() error\n")
+		}
+		b.WriteString("}\n")
+	}
+
+	for i := 0; i < spec.Consts; i++ {
+		fmt.Fprintf(&b, "\n// %[1]sC%[2]d is constant %[2]d.\nconst %[1]sC%[2]d = %[2]d\n", prefix, i)
+	}
+	return b.String()
+}
@@ -0,0 +1,97 @@
+package fixture
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func loadAPI(t *testing.T, dir string) *analyzer.API {
+	t.Helper()
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax |
+			packages.NeedTypesInfo,
+		Dir: dir,
+		Env: append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod"),
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		t.Fatalf("fixture in %s does not compile", dir)
+	}
+	return analyzer.ExtractAPI(pkgs)
+}
+
+func TestWrite(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not available")
+	}
+	spec := Spec{
+		Packages: 2, Funcs: 6, Structs: 3, Interfaces: 2, Consts: 1,
+		RemovedFuncs: 2, ChangedFuncs: 1, AddedFuncs: 3,
+		RemovedFields: 1, RemovedMethods: 2, ChangedInterfaces: 1,
+	}
+	dir := t.TempDir()
+	oldDir, newDir := filepath.Join(dir, "old"), filepath.Join(dir, "new")
+	if err := Write(oldDir, spec, false); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := Write(newDir, spec, true); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	changes := analyzer.DiffSnapshots(
+		&analyzer.Snapshot{Module: DefaultModule, Version: "v1.0.0", API: loadAPI(t, oldDir)},
+		&analyzer.Snapshot{Module: DefaultModule, Version: "v2.0.0", API: loadAPI(t, newDir)},
+	).Changes
+
+	counts := map[string]int{}
+	for _, r := range changes.Removed {
+		if r.Type == "function" && strings.Contains(r.Name, ".") {
+			r.Type = "method"
+		}
+		counts[r.Type]++
+	}
+	// Two packages, each removing 2 functions, 2 methods, and 1 field
+	if counts["function"] != 4 || counts["method"] != 4 || counts["field"] != 2 {
+		t.Errorf("removed = %v, want 4 functions, 4 methods, and 2 fields", counts)
+	}
+	if len(changes.Changed) != 2 || !strings.HasPrefix(changes.Changed[0].Name, "P") || !strings.HasSuffix(changes.Changed[0].Name, "F2") {
+		t.Errorf("changed = %+v, want P0F2 and P1F2", changes.Changed)
+	}
+	if len(changes.InterfaceChanges) != 2 || len(changes.Added) != 6 {
+		t.Errorf("%d interface changes and %d added, want 2 and 6", len(changes.InterfaceChanges), len(changes.Added))
+	}
+}
+
+func TestSpecValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		spec Spec
+		want string
+	}{
+		{"no packages", Spec{Funcs: 1}, "at least one package"},
+		{"negative", Spec{Packages: 1, Funcs: -1}, "cannot be negative"},
+		{"too many function changes", Spec{Packages: 1, Funcs: 3, RemovedFuncs: 2, ChangedFuncs: 2}, "exceed the 3 functions"},
+		{"too many field removals", Spec{Packages: 1, Structs: 1, RemovedFields: 2}, "exceed the 1 structs"},
+		{"too many method removals", Spec{Packages: 1, RemovedMethods: 1}, "exceed the 0 structs"},
+		{"too many interface changes", Spec{Packages: 1, Interfaces: 1, ChangedInterfaces: 2}, "exceed the 1 interfaces"},
+		{"valid", Spec{Packages: 1, Funcs: 3, RemovedFuncs: 1, ChangedFuncs: 2}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate()
+			if (err == nil) != (tt.want == "") || (err != nil && !strings.Contains(err.Error(), tt.want)) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+// Package github is a small GitHub REST API client shared by the integrations.
+// It authenticates with a token, revalidates cached responses with ETags so
+// repeated requests do not count against the rate limit, and backs off when
+// rate limited or when the server fails.
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBaseURL is the public GitHub API
+const DefaultBaseURL = "https://api.github.com"
+
+// Client is the subset of the GitHub API the integrations use. Tests
+// substitute their own implementation.
+type Client interface {
+	// Get fetches path (relative to the API base URL) and decodes the JSON body into out
+	Get(ctx context.Context, path string, out interface{}) error
+	// Release fetches the release of repo owner/name tagged tag
+	Release(ctx context.Context, owner, name, tag string) (*Release, error)
+}
+
+// Release is a GitHub release
+type Release struct {
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+// Options configures an HTTPClient
+type Options struct {
+	Token      string       // defaults to TokenFromEnv
+	BaseURL    string       // defaults to DefaultBaseURL
+	HTTPClient *http.Client // defaults to a client with a 30s timeout
+	MaxRetries int          // defaults to 3
+	MaxBackoff time.Duration
+}
+
+// HTTPClient implements Client over HTTP
+type HTTPClient struct {
+	opts Options
+
+	mu    sync.Mutex
+	cache map[string]cachedResponse
+}
+
+// cachedResponse is a body kept for conditional requests
+type cachedResponse struct {
+	etag string
+	body []byte
+}
+
+// APIError is a non-success response from GitHub
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("github: %d %s", e.StatusCode, e.Message)
+}
+
+// Allow overriding in tests
+var sleep = func(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// TokenFromEnv returns GITHUB_TOKEN, or GH_TOKEN as the gh CLI uses
+func TokenFromEnv() string {
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		return token
+	}
+	return os.Getenv("GH_TOKEN")
+}
+
+// New creates a client, filling unset options with defaults
+func New(opts Options) *HTTPClient {
+	if opts.Token == "" {
+		opts.Token = TokenFromEnv()
+	}
+	if opts.BaseURL == "" {
+		opts.BaseURL = DefaultBaseURL
+	}
+	opts.BaseURL = strings.TrimSuffix(opts.BaseURL, "/")
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = 3
+	}
+	if opts.MaxBackoff == 0 {
+		opts.MaxBackoff = time.Minute
+	}
+	return &HTTPClient{opts: opts, cache: make(map[string]cachedResponse)}
+}
+
+// Get implements Client
+func (c *HTTPClient) Get(ctx context.Context, path string, out interface{}) error {
+	body, err := c.get(ctx, path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("github: failed to decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// Release implements Client
+func (c *HTTPClient) Release(ctx context.Context, owner, name, tag string) (*Release, error) {
+	var release Release
+	if err := c.Get(ctx, fmt.Sprintf("/repos/%s/%s/releases/tags/%s", owner, name, tag), &release); err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// get returns the body of path, retrying rate limits and server errors
+func (c *HTTPClient) get(ctx context.Context, path string) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		body, wait, err := c.do(ctx, path)
+		if err == nil {
+			return body, nil
+		}
+		if wait < 0 || attempt >= c.opts.MaxRetries {
+			return nil, err
+		}
+		if wait == 0 {
+			wait = time.Second << attempt
+		}
+		if wait > c.opts.MaxBackoff {
+			wait = c.opts.MaxBackoff
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// do performs one request. On failure wait says how long to wait before
+// retrying: negative when the error is permanent, zero to use exponential backoff.
+func (c *HTTPClient) do(ctx context.Context, path string) (body []byte, wait time.Duration, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.opts.BaseURL+path, nil)
+	if err != nil {
+		return nil, -1, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.opts.Token)
+	}
+
+	c.mu.Lock()
+	cached, hasCached := c.cache[path]
+	c.mu.Unlock()
+	if hasCached {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := c.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("github: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("github: failed to read response: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && hasCached:
+		return cached.body, 0, nil
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			c.mu.Lock()
+			c.cache[path] = cachedResponse{etag: etag, body: body}
+			c.mu.Unlock()
+		}
+		return body, 0, nil
+	}
+
+	apiErr := &APIError{StatusCode: resp.StatusCode, Message: errorMessage(body, resp.Status)}
+	if wait, limited := rateLimitWait(resp); limited {
+		return nil, wait, apiErr
+	}
+	if resp.StatusCode >= 500 {
+		return nil, 0, apiErr
+	}
+	return nil, -1, apiErr
+}
+
+// rateLimitWait reports whether resp is a rate limit response and how long
+// GitHub asks us to wait
+func rateLimitWait(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusForbidden {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			if wait := time.Until(time.Unix(reset, 0)); wait > 0 {
+				return wait, true
+			}
+		}
+		return 0, true
+	}
+	// A 403 without rate limit headers is a permission problem
+	return 0, resp.StatusCode == http.StatusTooManyRequests
+}
+
+// errorMessage extracts the message of a GitHub error body
+func errorMessage(body []byte, status string) string {
+	var e struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &e) == nil && e.Message != "" {
+		return e.Message
+	}
+	return status
+}
+
+// RepoFromModule maps a github.com module path to its owner and repository
+func RepoFromModule(module string) (owner, name string, ok bool) {
+	parts := strings.Split(module, "/")
+	if len(parts) < 3 || parts[0] != "github.com" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
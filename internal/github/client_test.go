@@ -0,0 +1,156 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func mockSleep() (*[]time.Duration, func()) {
+	var waits []time.Duration
+	orig := sleep
+	sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return &waits, func() { sleep = orig }
+}
+
+func TestClient_ReleaseWithETag(t *testing.T) {
+	requests, notModified := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/repos/owner/repo/releases/tags/v1.2.0" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if r.Header.Get("If-None-Match") == `"abc"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"abc"`)
+		w.Write([]byte(`{"tag_name":"v1.2.0","body":"notes"}`))
+	}))
+	defer srv.Close()
+
+	c := New(Options{Token: "secret", BaseURL: srv.URL})
+	for i := 0; i < 2; i++ {
+		release, err := c.Release(context.Background(), "owner", "repo", "v1.2.0")
+		if err != nil {
+			t.Fatalf("Release() error = %v", err)
+		}
+		if release.TagName != "v1.2.0" || release.Body != "notes" {
+			t.Errorf("Release() = %+v", release)
+		}
+	}
+	if requests != 2 || notModified != 1 {
+		t.Errorf("requests = %d, not modified = %d, want the second request revalidated", requests, notModified)
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []func(w http.ResponseWriter)
+		wantErr   bool
+		wantWaits []time.Duration
+	}{
+		{
+			name: "retry after rate limit",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Retry-After", "7")
+					w.WriteHeader(http.StatusTooManyRequests)
+				},
+				func(w http.ResponseWriter) { w.Write([]byte(`{}`)) },
+			},
+			wantWaits: []time.Duration{7 * time.Second},
+		},
+		{
+			name: "exponential backoff on server errors",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+				func(w http.ResponseWriter) { w.Write([]byte(`{}`)) },
+			},
+			wantWaits: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name: "forbidden without rate limit is permanent",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.WriteHeader(http.StatusForbidden)
+					w.Write([]byte(`{"message":"Resource not accessible"}`))
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "gives up after max retries",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusInternalServerError) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusInternalServerError) },
+			},
+			wantErr:   true,
+			wantWaits: []time.Duration{time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waits, restore := mockSleep()
+			defer restore()
+
+			i := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.responses[i](w)
+				i++
+			}))
+			defer srv.Close()
+
+			var out map[string]interface{}
+			err := New(Options{BaseURL: srv.URL, MaxRetries: len(tt.responses) - 1}).Get(context.Background(), "/x", &out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var apiErr *APIError
+			if err != nil && !errors.As(err, &apiErr) {
+				t.Errorf("Get() error = %T, want *APIError", err)
+			}
+			if len(*waits) != len(tt.wantWaits) {
+				t.Fatalf("waits = %v, want %v", *waits, tt.wantWaits)
+			}
+			for j := range tt.wantWaits {
+				if (*waits)[j] != tt.wantWaits[j] {
+					t.Errorf("waits = %v, want %v", *waits, tt.wantWaits)
+				}
+			}
+		})
+	}
+}
+
+func TestRepoFromModule(t *testing.T) {
+	tests := []struct {
+		module      string
+		owner, name string
+		ok          bool
+	}{
+		{"github.com/pkg/errors", "pkg", "errors", true},
+		{"github.com/owner/repo/v2/sub", "owner", "repo", true},
+		{"golang.org/x/tools", "", "", false},
+		{"github.com/owner", "", "", false},
+	}
+	for _, tt := range tests {
+		owner, name, ok := RepoFromModule(tt.module)
+		if owner != tt.owner || name != tt.name || ok != tt.ok {
+			t.Errorf("RepoFromModule(%q) = %q, %q, %v", tt.module, owner, name, ok)
+		}
+	}
+}
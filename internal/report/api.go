@@ -0,0 +1,74 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// FormatAPIText lists the exported API of a module version by kind of symbol
+func FormatAPIText(snap *analyzer.Snapshot) string {
+	var b strings.Builder
+	api := snap.API
+	b.WriteString(fmt.Sprintf("Exported API of %s@%s (%d package(s))\n", snap.Module, snap.Version, len(api.Packages)))
+
+	if len(api.Funcs) > 0 {
+		b.WriteString("\nFunctions:\n")
+		for _, name := range sortedNames(api.Funcs) {
+			fn := api.Funcs[name]
+			b.WriteString(fmt.Sprintf("  - %s %s%s%s\n", fn.Name, fn.Signature, promotedTag(fn.PromotedFrom), unstableTag(fn.Unstable)))
+		}
+	}
+
+	if len(api.Types) > 0 {
+		b.WriteString("\nTypes:\n")
+		for _, name := range sortedNames(api.Types) {
+			typ := api.Types[name]
+			b.WriteString(fmt.Sprintf("  - %s %s%s\n", typ.Name, typ.Kind, unstableTag(typ.Unstable)))
+		}
+	}
+
+	if len(api.Interfaces) > 0 {
+		b.WriteString("\nInterfaces:\n")
+		for _, name := range sortedNames(api.Interfaces) {
+			iface := api.Interfaces[name]
+			b.WriteString(fmt.Sprintf("  - %s%s\n", iface.Name, unstableTag(iface.Unstable)))
+			for _, method := range iface.Methods {
+				b.WriteString(fmt.Sprintf("    %s\n", method))
+			}
+		}
+	}
+
+	if len(api.Consts) > 0 {
+		b.WriteString("\nConstants:\n")
+		for _, name := range sortedNames(api.Consts) {
+			c := api.Consts[name]
+			b.WriteString(fmt.Sprintf("  - %s %s = %s\n", c.Name, c.Type, c.Value))
+		}
+	}
+
+	return b.String()
+}
+
+// FormatAPIJSON writes a snapshot in the JSON form read back by the diff subcommand
+func FormatAPIJSON(snap *analyzer.Snapshot) (string, error) {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data) + "\n", nil
+}
+
+// sortedNames returns the keys of an API symbol map in order
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestFormatAPIText(t *testing.T) {
+	snap := &analyzer.Snapshot{
+		Module:  "example.com/lib",
+		Version: "v1.2.0",
+		API: &analyzer.API{
+			Funcs: map[string]*analyzer.Function{
+				"Open":      {Name: "Open", Signature: analyzer.Signature{Text: "func() *example.com/lib.Client"}},
+				"Client.Do": {Name: "Client.Do", Signature: analyzer.Signature{Text: "func() error"}, IsMethod: true, Unstable: true},
+			},
+			Types:      map[string]*analyzer.Type{"Client": {Name: "Client", Kind: "struct{}"}},
+			Interfaces: map[string]*analyzer.Interface{"Doer": {Name: "Doer", Methods: []string{"func (example.com/lib.Doer).Do() error"}}},
+			Consts:     map[string]*analyzer.Const{"MaxRetries": {Name: "MaxRetries", Type: "untyped int", Value: "3"}},
+			Packages:   []string{"example.com/lib"},
+		},
+	}
+
+	out := FormatAPIText(snap)
+	for _, want := range []string{
+		"Exported API of example.com/lib@v1.2.0 (1 package(s))",
+		"Functions:\n  - Client.Do func() error [unstable]\n  - Open func() *example.com/lib.Client\n",
+		"Types:\n  - Client struct{}\n",
+		"Interfaces:\n  - Doer\n    func (example.com/lib.Doer).Do() error\n",
+		"Constants:\n  - MaxRetries untyped int = 3\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatAPIJSON(t *testing.T) {
+	snap := &analyzer.Snapshot{
+		Module:  "example.com/lib",
+		Version: "v1.2.0",
+		API: &analyzer.API{
+			Funcs:    map[string]*analyzer.Function{"Open": {Name: "Open", Signature: analyzer.Signature{Text: "func()"}, PkgPath: "example.com/lib"}},
+			Packages: []string{"example.com/lib"},
+		},
+	}
+
+	out, err := FormatAPIJSON(snap)
+	if err != nil {
+		t.Fatalf("FormatAPIJSON() error = %v", err)
+	}
+	var decoded analyzer.Snapshot
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if decoded.Version != "v1.2.0" || decoded.API.Funcs["Open"].PkgPath != "example.com/lib" {
+		t.Errorf("round-tripped snapshot = %+v", decoded)
+	}
+	if !strings.Contains(out, `"pkg_path": "example.com/lib"`) {
+		t.Errorf("expected snake_case keys, got:\n%s", out)
+	}
+}
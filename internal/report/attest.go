@@ -0,0 +1,253 @@
+package report
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// in-toto and DSSE identifiers used in attestations
+const (
+	inTotoStatementType = "https://in-toto.io/Statement/v1"
+	inTotoPayloadType   = "application/vnd.in-toto+json"
+	AuditPredicateType  = "https://github.com/devblac/go-semver-audit/audit/v1"
+)
+
+// ReportSubjectName names the JSON report in the attestation subject
+const ReportSubjectName = "go-semver-audit.json"
+
+// Envelope is a DSSE envelope carrying a signed in-toto statement
+type Envelope struct {
+	PayloadType string              `json:"payloadType"`
+	Payload     string              `json:"payload"` // base64 encoded Statement
+	Signatures  []EnvelopeSignature `json:"signatures"`
+}
+
+// EnvelopeSignature is one signature over a DSSE envelope
+type EnvelopeSignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"` // base64 encoded
+}
+
+// Statement is an in-toto statement about the JSON report
+type Statement struct {
+	Type          string         `json:"_type"`
+	Subject       []Subject      `json:"subject"`
+	PredicateType string         `json:"predicateType"`
+	Predicate     AuditPredicate `json:"predicate"`
+}
+
+// Subject identifies the attested report by digest
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// AuditPredicate summarizes the audit so policies can check it without
+// parsing the report. The verdict is the one of the classifier that decided
+// the exit code, with its severity overrides and -max-affected.
+type AuditPredicate struct {
+	ToolVersion   string `json:"tool_version,omitempty"`
+	Module        string `json:"module"`
+	OldVersion    string `json:"old_version"`
+	NewVersion    string `json:"new_version"`
+	Passed        bool   `json:"passed"`
+	ExitCode      int    `json:"exit_code"`
+	Breaking      bool   `json:"breaking"`
+	BreakingCount int    `json:"breaking_count"`        // findings at error severity
+	AcceptedCount int    `json:"accepted_count"`        // breaking findings -max-affected accepted
+	IgnoredCount  int    `json:"ignored_count"`         // findings at info severity, which never fail
+	Incomplete    bool   `json:"incomplete,omitempty"`  // usage-only: the API could not be diffed
+	FloorUnmet    bool   `json:"floor_unmet,omitempty"` // below a required minimum version
+}
+
+// LoadSigningKey parses a PEM encoded Ed25519 or ECDSA private key
+func LoadSigningKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T: use Ed25519 or ECDSA", key)
+	}
+}
+
+// LoadPublicKey parses a PEM encoded PKIX public key for verification
+func LoadPublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// Attest signs an in-toto statement over the JSON report data, with the
+// verdict of c on result, and returns the DSSE envelope as JSON
+func Attest(result *analyzer.Result, c analyzer.Classifier, reportData []byte, key crypto.Signer) (string, error) {
+	cl := c.Classify(result)
+	digest := sha256.Sum256(reportData)
+	stmt := Statement{
+		Type: inTotoStatementType,
+		Subject: []Subject{{
+			Name:   ReportSubjectName,
+			Digest: map[string]string{"sha256": hex.EncodeToString(digest[:])},
+		}},
+		PredicateType: AuditPredicateType,
+		Predicate: AuditPredicate{
+			Module:     result.Module,
+			OldVersion: result.OldVersion,
+			NewVersion: result.NewVersion,
+			Passed:     cl.ExitCode == 0,
+			ExitCode:   cl.ExitCode,
+			Breaking:   cl.Breaking,
+			Incomplete: cl.Incomplete,
+			FloorUnmet: cl.FloorUnmet,
+		},
+	}
+	if result.Changes != nil {
+		stmt.Predicate.BreakingCount = c.Count(result.Changes, analyzer.SeverityError)
+		stmt.Predicate.IgnoredCount = c.Count(result.Changes, analyzer.SeverityInfo)
+		if cl.Accepted && !c.Strict {
+			stmt.Predicate.AcceptedCount = stmt.Predicate.BreakingCount
+		}
+	}
+	if result.Provenance != nil {
+		stmt.Predicate.ToolVersion = result.Provenance.ToolVersion
+	}
+
+	payload, err := json.Marshal(stmt)
+	if err != nil {
+		return "", err
+	}
+	sig, err := signPayload(key, pae(inTotoPayloadType, payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign attestation: %w", err)
+	}
+	keyID, err := publicKeyID(key.Public())
+	if err != nil {
+		return "", err
+	}
+
+	env := Envelope{
+		PayloadType: inTotoPayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []EnvelopeSignature{{KeyID: keyID, Sig: base64.StdEncoding.EncodeToString(sig)}},
+	}
+	data, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// VerifyAttestation checks that envelope is signed by pub and attests
+// exactly reportData, and returns the attested statement
+func VerifyAttestation(envelope, reportData []byte, pub crypto.PublicKey) (*Statement, error) {
+	var env Envelope
+	if err := json.Unmarshal(envelope, &env); err != nil {
+		return nil, fmt.Errorf("failed to parse envelope: %w", err)
+	}
+	if env.PayloadType != inTotoPayloadType {
+		return nil, fmt.Errorf("unexpected payload type %q", env.PayloadType)
+	}
+	payload, err := base64.StdEncoding.DecodeString(env.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode payload: %w", err)
+	}
+
+	msg := pae(env.PayloadType, payload)
+	verified := false
+	for _, s := range env.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err == nil && verifySignature(pub, msg, sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, errors.New("no valid signature for the given key")
+	}
+
+	var stmt Statement
+	if err := json.Unmarshal(payload, &stmt); err != nil {
+		return nil, fmt.Errorf("failed to parse statement: %w", err)
+	}
+	if stmt.PredicateType != AuditPredicateType {
+		return nil, fmt.Errorf("unexpected predicate type %q", stmt.PredicateType)
+	}
+	digest := sha256.Sum256(reportData)
+	want := hex.EncodeToString(digest[:])
+	for _, subject := range stmt.Subject {
+		if subject.Digest["sha256"] == want {
+			return &stmt, nil
+		}
+	}
+	return nil, errors.New("attestation does not cover this report")
+}
+
+// pae is the DSSE pre-authentication encoding that is actually signed
+func pae(payloadType string, payload []byte) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "DSSEv1 %d %s %d ", len(payloadType), payloadType, len(payload))
+	buf.Write(payload)
+	return buf.Bytes()
+}
+
+func signPayload(key crypto.Signer, msg []byte) ([]byte, error) {
+	if _, ok := key.(ed25519.PrivateKey); ok {
+		return key.Sign(rand.Reader, msg, crypto.Hash(0))
+	}
+	digest := sha256.Sum256(msg)
+	return key.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+func verifySignature(pub crypto.PublicKey, msg, sig []byte) bool {
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, msg, sig)
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(msg)
+		return ecdsa.VerifyASN1(k, digest[:], sig)
+	}
+	return false
+}
+
+// publicKeyID identifies a key by the SHA-256 of its PKIX encoding
+func publicKeyID(pub crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,139 @@
+package report
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestAttest_RoundTrip(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := &analyzer.Result{
+		Module:     "github.com/test/module",
+		OldVersion: "v1.0.0",
+		NewVersion: "v2.0.0",
+		Changes: &analyzer.Diff{
+			Removed: []analyzer.RemovedSymbol{{Name: "Gone", Type: "function"}},
+		},
+	}
+	reportData := []byte(`{"module":"github.com/test/module"}`)
+
+	for _, tt := range []struct {
+		name string
+		key  interface{}
+	}{
+		{name: "ed25519", key: edKey},
+		{name: "ecdsa", key: ecKey},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			der, err := x509.MarshalPKCS8PrivateKey(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			signer, err := LoadSigningKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+			if err != nil {
+				t.Fatalf("LoadSigningKey() error = %v", err)
+			}
+
+			envelope, err := Attest(result, analyzer.Classifier{MaxAffected: -1}, reportData, signer)
+			if err != nil {
+				t.Fatalf("Attest() error = %v", err)
+			}
+
+			stmt, err := VerifyAttestation([]byte(envelope), reportData, signer.Public())
+			if err != nil {
+				t.Fatalf("VerifyAttestation() error = %v", err)
+			}
+			if p := stmt.Predicate; !p.Breaking || p.BreakingCount != 1 || p.Passed || p.ExitCode != 1 || p.Module != result.Module {
+				t.Errorf("predicate = %+v, want a failed breaking audit of %s", p, result.Module)
+			}
+
+			_, err = VerifyAttestation([]byte(envelope), []byte(`{"module":"tampered"}`), signer.Public())
+			if err == nil || !strings.Contains(err.Error(), "does not cover") {
+				t.Errorf("VerifyAttestation() with another report error = %v", err)
+			}
+
+			otherPub, _, _ := ed25519.GenerateKey(rand.Reader)
+			if _, err := VerifyAttestation([]byte(envelope), reportData, otherPub); err == nil {
+				t.Error("VerifyAttestation() with another key should fail")
+			}
+		})
+	}
+}
+
+func TestAttest_ClassifierVerdict(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	used := []analyzer.Location{{File: "main.go", Line: 3}}
+
+	tests := []struct {
+		name   string
+		result *analyzer.Result
+		c      analyzer.Classifier
+		want   AuditPredicate
+	}{
+		{
+			name: "usage-only",
+			result: &analyzer.Result{
+				Changes:   &analyzer.Diff{},
+				UsageOnly: &analyzer.UsageOnly{Reason: "unknown revision v2.0.0", Symbols: []analyzer.SymbolUses{{Name: "Open", UsedIn: used}}},
+			},
+			c:    analyzer.Classifier{MaxAffected: -1},
+			want: AuditPredicate{ExitCode: 1, Incomplete: true},
+		},
+		{
+			name: "accepted by max affected",
+			result: &analyzer.Result{Changes: &analyzer.Diff{
+				Removed: []analyzer.RemovedSymbol{{Name: "Gone", Type: "function", UsedIn: used}},
+			}},
+			c:    analyzer.Classifier{MaxAffected: 1},
+			want: AuditPredicate{Passed: true, Breaking: true, BreakingCount: 1, AcceptedCount: 1},
+		},
+		{
+			name: "downgraded by a severity override",
+			result: &analyzer.Result{Changes: &analyzer.Diff{
+				Removed: []analyzer.RemovedSymbol{{Name: "Gone", Type: "function", UsedIn: used}},
+			}},
+			c:    analyzer.Classifier{MaxAffected: -1, Severities: map[string]string{analyzer.CategoryRemoved: analyzer.SeverityInfo}},
+			want: AuditPredicate{Passed: true, IgnoredCount: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envelope, err := Attest(tt.result, tt.c, []byte("{}"), key)
+			if err != nil {
+				t.Fatalf("Attest() error = %v", err)
+			}
+			stmt, err := VerifyAttestation([]byte(envelope), []byte("{}"), key.Public())
+			if err != nil {
+				t.Fatalf("VerifyAttestation() error = %v", err)
+			}
+			if stmt.Predicate != tt.want {
+				t.Errorf("predicate = %+v, want %+v", stmt.Predicate, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSigningKey_Invalid(t *testing.T) {
+	if _, err := LoadSigningKey([]byte("not a key")); err == nil {
+		t.Error("LoadSigningKey() should reject non-PEM data")
+	}
+}
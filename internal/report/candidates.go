@@ -0,0 +1,102 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// effortPerChange weighs each breaking change against the locations it
+// affects in a candidate's effort score: understanding a change costs about
+// as much as fixing several of its call sites
+const effortPerChange = 5
+
+// CandidatesReport represents a comparison of candidate upgrade targets in JSON
+type CandidatesReport struct {
+	Module      string          `json:"module"`
+	OldVersion  string          `json:"old_version"`
+	Candidates  []CandidateItem `json:"candidates"`
+	Recommended string          `json:"recommended,omitempty"`
+}
+
+// CandidateItem represents the audit of one candidate target in JSON
+type CandidateItem struct {
+	Version           string `json:"version"`
+	BreakingCount     int    `json:"breaking_count"`
+	AffectedLocations int    `json:"affected_locations"`
+	Effort            int    `json:"effort"`
+	Unavailable       bool   `json:"unavailable,omitempty"` // API diff failed, see the usage-only report
+}
+
+// compareCandidates scores the results of auditing each candidate and picks
+// the one with the lowest effort, preferring newer versions on ties
+func compareCandidates(results []*analyzer.Result) CandidatesReport {
+	var report CandidatesReport
+	if len(results) > 0 {
+		report.Module = results[0].Module
+		report.OldVersion = results[0].OldVersion
+	}
+	report.Candidates = []CandidateItem{}
+
+	best := -1
+	for _, result := range results {
+		item := CandidateItem{Version: result.NewVersion, Unavailable: result.UsageOnly != nil}
+		if !item.Unavailable {
+			item.BreakingCount = result.Changes.BreakingCount()
+			item.AffectedLocations = result.Changes.AffectedLocations()
+			item.Effort = effortPerChange*item.BreakingCount + item.AffectedLocations
+			if best < 0 || item.Effort < report.Candidates[best].Effort ||
+				(item.Effort == report.Candidates[best].Effort && semver.Compare(item.Version, report.Candidates[best].Version) > 0) {
+				best = len(report.Candidates)
+			}
+		}
+		report.Candidates = append(report.Candidates, item)
+	}
+	if best >= 0 {
+		report.Recommended = report.Candidates[best].Version
+	}
+	return report
+}
+
+// FormatCandidatesText prints a table comparing candidate upgrade targets
+func FormatCandidatesText(results []*analyzer.Result) string {
+	report := compareCandidates(results)
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Comparing upgrade candidates: %s %s -> %d candidate(s)\n\n", report.Module, report.OldVersion, len(report.Candidates)))
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  VERSION\tBREAKING\tAFFECTED\tEFFORT\t")
+	for _, c := range report.Candidates {
+		mark := ""
+		if c.Version == report.Recommended {
+			mark = "✓ lowest effort"
+		}
+		if c.Unavailable {
+			fmt.Fprintf(w, "  %s\t?\t?\t?\tAPI diff unavailable\n", c.Version)
+			continue
+		}
+		fmt.Fprintf(w, "  %s\t%d\t%d\t%d\t%s\n", c.Version, c.BreakingCount, c.AffectedLocations, c.Effort, mark)
+	}
+	w.Flush()
+
+	b.WriteString(fmt.Sprintf("\nEffort is the affected locations plus %d per breaking change.\n", effortPerChange))
+	if report.Recommended != "" {
+		b.WriteString(fmt.Sprintf("Recommended target: %s\n", report.Recommended))
+	}
+	return b.String()
+}
+
+// FormatCandidatesJSON generates a JSON comparison of candidate upgrade targets
+func FormatCandidatesJSON(results []*analyzer.Result) (string, error) {
+	data, err := json.MarshalIndent(compareCandidates(results), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,62 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func candidateResults() []*analyzer.Result {
+	removed := func(n int) []analyzer.RemovedSymbol {
+		var symbols []analyzer.RemovedSymbol
+		for i := 0; i < n; i++ {
+			symbols = append(symbols, analyzer.RemovedSymbol{Name: "F", Type: "function", UsedIn: []analyzer.Location{{File: "main.go", Line: i + 1}}})
+		}
+		return symbols
+	}
+	return []*analyzer.Result{
+		{Module: "example.com/lib", OldVersion: "v1.7.0", NewVersion: "v1.8.0", Changes: &analyzer.Diff{}},
+		{Module: "example.com/lib", OldVersion: "v1.7.0", NewVersion: "v1.9.0", Changes: &analyzer.Diff{}},
+		{Module: "example.com/lib", OldVersion: "v1.7.0", NewVersion: "v2.0.0", Changes: &analyzer.Diff{Removed: removed(2)}},
+		{Module: "example.com/lib", OldVersion: "v1.7.0", NewVersion: "v2.1.0", Changes: &analyzer.Diff{}, UsageOnly: &analyzer.UsageOnly{Reason: "offline"}},
+	}
+}
+
+func TestFormatCandidatesText(t *testing.T) {
+	out := FormatCandidatesText(candidateResults())
+	for _, want := range []string{
+		"Comparing upgrade candidates: example.com/lib v1.7.0 -> 4 candidate(s)\n",
+		"  VERSION  BREAKING  AFFECTED  EFFORT  \n",
+		"  v1.8.0   0         0         0       \n",
+		"  v1.9.0   0         0         0       ✓ lowest effort\n",
+		"  v2.0.0   2         2         12      \n",
+		"  v2.1.0   ?         ?         ?       API diff unavailable\n",
+		"Recommended target: v1.9.0\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatCandidatesJSON(t *testing.T) {
+	out, err := FormatCandidatesJSON(candidateResults())
+	if err != nil {
+		t.Fatalf("FormatCandidatesJSON() error = %v", err)
+	}
+	var report CandidatesReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	if report.Recommended != "v1.9.0" || len(report.Candidates) != 4 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if c := report.Candidates[2]; c.BreakingCount != 2 || c.AffectedLocations != 2 || c.Effort != 12 {
+		t.Errorf("v2.0.0 = %+v", c)
+	}
+	if !report.Candidates[3].Unavailable {
+		t.Errorf("v2.1.0 = %+v, want unavailable", report.Candidates[3])
+	}
+}
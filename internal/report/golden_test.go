@@ -0,0 +1,153 @@
+package report
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden with the current output")
+
+// goldenResults are the results every report format is rendered from
+var goldenResults = map[string]func() *analyzer.Result{
+	"breaking": func() *analyzer.Result {
+		return &analyzer.Result{
+			Module:     "github.com/example/lib",
+			OldVersion: "v1.4.0",
+			NewVersion: "v2.0.0",
+			Changes: &analyzer.Diff{
+				Removed: []analyzer.RemovedSymbol{
+					{Name: "OldFunc", Type: "function", Package: "github.com/example/lib", UsedIn: []analyzer.Location{
+						{File: "main.go", Line: 45, Column: 9},
+						{File: "cmd/tool/run.go", Line: 12, Column: 2},
+					}},
+					{Name: "Config.Timeout", Type: "field", Package: "github.com/example/lib", UsedIn: []analyzer.Location{
+						{File: "config.go", Line: 30, Column: 3, Kind: analyzer.UsageKeyedField},
+					}},
+				},
+				Moved: []analyzer.MovedSymbol{
+					{Name: "Dial", NewName: "Dial", Type: "function", OldPackage: "github.com/example/lib", NewPackage: "github.com/example/lib/net",
+						UsedIn: []analyzer.Location{{File: "client.go", Line: 8, Column: 10}}},
+				},
+				Changed: []analyzer.ChangedSignature{
+					{Name: "ParseConfig", Package: "github.com/example/lib", OldSignature: "func(path string) error",
+						NewSignature: "func(path string, opts ...Option) error",
+						UsedIn:       []analyzer.Location{{File: "config.go", Line: 23, Column: 12}}},
+				},
+				InterfaceChanges: []analyzer.InterfaceChange{
+					{Name: "Handler", Package: "github.com/example/lib",
+						RemovedMethods:  []string{"Handle(ctx context.Context) error"},
+						AddedMethods:    []string{"HandleWithContext(ctx context.Context, meta Metadata) error"},
+						UsedIn:          []analyzer.Location{{File: "handler.go", Line: 67, Column: 6}},
+						Implementations: []analyzer.Implementation{{Type: "app.Server", Before: "*app.Server", Missing: []string{"HandleWithContext"}}}},
+				},
+				Added: []analyzer.AddedSymbol{
+					{Name: "NewFunc", Type: "function", Package: "github.com/example/lib"},
+					{Name: "Option", Type: "type", Package: "github.com/example/lib"},
+				},
+			},
+			UnusedDeps: []string{"github.com/unused/dep"},
+			Warnings:   []analyzer.Warning{{Code: analyzer.WarnCacheMiss, Message: "github.com/example/lib@v2.0.0 was not cached and was loaded from scratch"}},
+		}
+	},
+	"clean": func() *analyzer.Result {
+		return &analyzer.Result{
+			Module:     "golang.org/x/text",
+			OldVersion: "v0.13.0",
+			NewVersion: "v0.14.0",
+			Changes: &analyzer.Diff{
+				Added: []analyzer.AddedSymbol{{Name: "Transform", Type: "function", Package: "golang.org/x/text/transform"}},
+			},
+		}
+	},
+	"warnings": func() *analyzer.Result {
+		return &analyzer.Result{
+			Module:      "github.com/example/exp",
+			OldVersion:  "v0.3.0",
+			NewVersion:  "v0.4.0",
+			Deprecation: &analyzer.Deprecation{Message: "use github.com/example/exp2 instead", Successor: "github.com/example/exp2"},
+			Retractions: []analyzer.Retraction{{Version: "v0.3.0", Current: true, Rationale: "data race in Pool"}},
+			Changes: &analyzer.Diff{
+				Removed: []analyzer.RemovedSymbol{
+					{Name: "Legacy", Type: "function", Package: "github.com/example/exp", DeadCode: true,
+						UsedIn: []analyzer.Location{{File: "legacy.go", Line: 5, Column: 2}}},
+				},
+				Changed: []analyzer.ChangedSignature{
+					{Name: "Pool.Get", Package: "github.com/example/exp/internal/pool", Unstable: true,
+						OldSignature: "func() any", NewSignature: "func() (any, bool)",
+						UsedIn: []analyzer.Location{{File: "pool.go", Line: 14, Column: 7}}},
+				},
+			},
+			DeadCode: []analyzer.DeadFunction{
+				{Name: "oldSync", Package: "example.com/app", Location: analyzer.Location{File: "legacy.go", Line: 3, Column: 6}, Symbols: []string{"Legacy"}},
+			},
+		}
+	},
+}
+
+// goldenFormats render a result in each format checked against golden
+// files, by file extension
+var goldenFormats = map[string]func(*analyzer.Result) (string, error){
+	"txt":         func(r *analyzer.Result) (string, error) { return FormatText(r, false) },
+	"verbose.txt": func(r *analyzer.Result) (string, error) { return FormatText(r, true) },
+	"json":        FormatJSON,
+	"html":        FormatHTML,
+	"sarif.json":  FormatSARIF,
+}
+
+func TestGolden(t *testing.T) {
+	for name, result := range goldenResults {
+		for ext, format := range goldenFormats {
+			file := name + "." + ext
+			t.Run(file, func(t *testing.T) {
+				got, err := format(result())
+				if err != nil {
+					t.Fatalf("format error = %v", err)
+				}
+				checkGolden(t, file, got)
+			})
+		}
+	}
+}
+
+// checkGolden compares got with testdata/golden/file, or rewrites the file
+// with -update
+func checkGolden(t *testing.T, file, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", file)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v; run go test -run TestGolden -update to create it", err)
+	}
+	want := strings.ReplaceAll(string(data), "\r\n", "\n") // checked out by git on Windows
+	if got == want {
+		return
+	}
+	gotLines, wantLines := strings.Split(got, "\n"), strings.Split(want, "\n")
+	for i := 0; i < len(gotLines) || i < len(wantLines); i++ {
+		var g, w string
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if g != w {
+			t.Fatalf("output differs from %s at line %d:\n got: %q\nwant: %q\nrun go test -run TestGolden -update if the change is intended", path, i+1, g, w)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"path/filepath"
+	"strings"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// severityRank orders severities so a package takes the color of its worst finding
+var severityRank = map[string]int{
+	analyzer.SeverityInfo:    1,
+	analyzer.SeverityWarning: 2,
+	analyzer.SeverityError:   3,
+}
+
+// graphColor is the fill and border of a graph node
+type graphColor struct {
+	Fill, Border string
+}
+
+// graphColors maps the worst severity in a package to its color; packages
+// without findings and module packages have their own
+var (
+	graphColors = map[string]graphColor{
+		analyzer.SeverityError:   {"#ffebe9", "#cf222e"},
+		analyzer.SeverityWarning: {"#fff8c5", "#bf8700"},
+		analyzer.SeverityInfo:    {"#ddf4ff", "#0969da"},
+	}
+	graphColorClean  = graphColor{"#dafbe1", "#1a7f37"}
+	graphColorModule = graphColor{"#f6f8fa", "#57606a"}
+)
+
+// packageFindings counts the uses of changed symbols in one package directory
+type packageFindings struct {
+	Worst string // most severe level among the uses, empty without any
+	Count int
+}
+
+// findingsByDir attributes every use of a changed symbol to the directory of its file
+func findingsByDir(result *analyzer.Result) map[string]packageFindings {
+	byDir := make(map[string]packageFindings)
+	visitFindings(result, func(locations []analyzer.Location, _, _, level, _ string) {
+		for _, loc := range locations {
+			dir := filepath.Dir(loc.File)
+			f := byDir[dir]
+			f.Count++
+			if severityRank[level] > severityRank[f.Worst] {
+				f.Worst = level
+			}
+			byDir[dir] = f
+		}
+	})
+	return byDir
+}
+
+// nodeColor picks the color of a project package from its findings
+func nodeColor(f packageFindings) graphColor {
+	if color, ok := graphColors[f.Worst]; ok {
+		return color
+	}
+	return graphColorClean
+}
+
+// formatPackageFindings describes a package's findings, such as "3 use(s), worst error"
+func formatPackageFindings(f packageFindings) string {
+	if f.Count == 0 {
+		return "no affected uses"
+	}
+	return fmt.Sprintf("%d affected use(s), worst %s", f.Count, f.Worst)
+}
+
+// FormatDOT generates a Graphviz graph of the project packages importing the
+// upgraded module, colored by the severity of the findings within them. It
+// returns an empty string when the result has no import graph.
+func FormatDOT(result *analyzer.Result) string {
+	graph := result.ImportGraph
+	if graph == nil {
+		return ""
+	}
+	byDir := findingsByDir(result)
+
+	var b strings.Builder
+	b.WriteString("digraph imports {\n")
+	b.WriteString("  rankdir=BT;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+	b.WriteString(fmt.Sprintf("  label=%q;\n", fmt.Sprintf("Packages importing %s (%s -> %s)", result.Module, result.OldVersion, result.NewVersion)))
+	for _, path := range graph.ModulePackages {
+		b.WriteString(fmt.Sprintf("  %q [shape=ellipse, fillcolor=%q, color=%q];\n", path, graphColorModule.Fill, graphColorModule.Border))
+	}
+	for _, node := range graph.Nodes {
+		f := byDir[node.Dir]
+		color := nodeColor(f)
+		b.WriteString(fmt.Sprintf("  %q [fillcolor=%q, color=%q, tooltip=%q];\n", node.Package, color.Fill, color.Border, formatPackageFindings(f)))
+	}
+	for _, node := range graph.Nodes {
+		for _, imp := range node.Imports {
+			b.WriteString(fmt.Sprintf("  %q -> %q;\n", node.Package, imp))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// formatGraphNode describes a package of the import graph for text and HTML reports
+func formatGraphNode(node analyzer.GraphNode, f packageFindings) string {
+	via := "imports the module directly"
+	if !node.Direct {
+		via = "via " + strings.Join(node.Imports, ", ")
+	}
+	return fmt.Sprintf("%s (%s; %s)", node.Package, via, formatPackageFindings(f))
+}
+
+// SVG layout of the import graph, in pixels
+const (
+	svgNodeWidth  = 240
+	svgNodeHeight = 32
+	svgColGap     = 24
+	svgRowGap     = 56
+	svgMargin     = 16
+	svgLabelChars = 34
+)
+
+// importGraphSVG lays the import graph out in rows, module packages at the
+// bottom and each project package one row above the highest package it
+// imports, so edges point down toward the module. Graphviz is not needed.
+func importGraphSVG(result *analyzer.Result) string {
+	graph := result.ImportGraph
+	if graph == nil || len(graph.Nodes) == 0 {
+		return ""
+	}
+	byDir := findingsByDir(result)
+
+	// Row of every package: 0 for the module, then by longest import chain
+	row := make(map[string]int)
+	for _, path := range graph.ModulePackages {
+		row[path] = 0
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, node := range graph.Nodes {
+			r := 1
+			for _, imp := range node.Imports {
+				if row[imp]+1 > r {
+					r = row[imp] + 1
+				}
+			}
+			// An import cycle cannot exist, but a bound keeps this loop finite
+			if r > row[node.Package] && r <= len(graph.Nodes) {
+				row[node.Package] = r
+				changed = true
+			}
+		}
+	}
+
+	rows := [][]string{append([]string(nil), graph.ModulePackages...)}
+	for _, node := range graph.Nodes {
+		r := row[node.Package]
+		for len(rows) <= r {
+			rows = append(rows, nil)
+		}
+		rows[r] = append(rows[r], node.Package)
+	}
+
+	type point struct{ x, y int }
+	widest := 0
+	for _, r := range rows {
+		if len(r) > widest {
+			widest = len(r)
+		}
+	}
+	width := 2*svgMargin + widest*svgNodeWidth + (widest-1)*svgColGap
+	height := 2*svgMargin + len(rows)*svgNodeHeight + (len(rows)-1)*svgRowGap
+	pos := make(map[string]point)
+	for r, paths := range rows {
+		y := svgMargin + (len(rows)-1-r)*(svgNodeHeight+svgRowGap)
+		rowWidth := len(paths)*svgNodeWidth + (len(paths)-1)*svgColGap
+		x := (width - rowWidth) / 2
+		for _, path := range paths {
+			pos[path] = point{x, y}
+			x += svgNodeWidth + svgColGap
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n", width, height, width, height))
+	b.WriteString(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto"><path d="M0,0 L10,5 L0,10 z" fill="#57606a"/></marker></defs>` + "\n")
+	for _, node := range graph.Nodes {
+		from := pos[node.Package]
+		for _, imp := range node.Imports {
+			to := pos[imp]
+			b.WriteString(fmt.Sprintf(`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#57606a" marker-end="url(#arrow)"/>`+"\n",
+				from.x+svgNodeWidth/2, from.y+svgNodeHeight, to.x+svgNodeWidth/2, to.y))
+		}
+	}
+	writeNode := func(path string, color graphColor, rx int, title string) {
+		p := pos[path]
+		b.WriteString(fmt.Sprintf(`<g><title>%s</title><rect x="%d" y="%d" width="%d" height="%d" rx="%d" fill="%s" stroke="%s"/>`,
+			html.EscapeString(title), p.x, p.y, svgNodeWidth, svgNodeHeight, rx, color.Fill, color.Border))
+		b.WriteString(fmt.Sprintf(`<text x="%d" y="%d" text-anchor="middle">%s</text></g>`+"\n",
+			p.x+svgNodeWidth/2, p.y+svgNodeHeight/2+4, html.EscapeString(shortenLabel(path))))
+	}
+	for _, path := range graph.ModulePackages {
+		writeNode(path, graphColorModule, svgNodeHeight/2, path)
+	}
+	for _, node := range graph.Nodes {
+		f := byDir[node.Dir]
+		writeNode(node.Package, nodeColor(f), 6, node.Package+": "+formatPackageFindings(f))
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// shortenLabel keeps the end of long import paths, which tells packages apart
+func shortenLabel(path string) string {
+	return truncateLabel(path, svgLabelChars)
+}
+
+// truncateLabel keeps the last max characters of a label, marking the cut
+func truncateLabel(label string, max int) string {
+	runes := []rune(label)
+	if len(runes) <= max {
+		return label
+	}
+	return "…" + string(runes[len(runes)-max+1:])
+}
@@ -0,0 +1,93 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func graphResult() *analyzer.Result {
+	return &analyzer.Result{
+		Module:     "example.com/lib",
+		OldVersion: "v1.0.0",
+		NewVersion: "v2.0.0",
+		Changes: &analyzer.Diff{
+			Removed: []analyzer.RemovedSymbol{
+				{Name: "Old", Type: "function", UsedIn: []analyzer.Location{{File: "/src/app/store/store.go", Line: 3}}},
+			},
+			Changed: []analyzer.ChangedSignature{
+				{Name: "Do", Severity: analyzer.SeverityWarning, UsedIn: []analyzer.Location{
+					{File: "/src/app/store/store.go", Line: 9},
+					{File: "/src/app/api/api.go", Line: 5},
+				}},
+			},
+		},
+		ImportGraph: &analyzer.ImportGraph{
+			Nodes: []analyzer.GraphNode{
+				{Package: "example.com/app/api", Dir: "/src/app/api", Imports: []string{"example.com/app/store"}},
+				{Package: "example.com/app/cmd", Dir: "/src/app/cmd", Imports: []string{"example.com/app/api"}},
+				{Package: "example.com/app/store", Dir: "/src/app/store", Imports: []string{"example.com/lib"}, Direct: true},
+			},
+			ModulePackages: []string{"example.com/lib"},
+		},
+	}
+}
+
+func TestFormatDOT(t *testing.T) {
+	output := FormatDOT(graphResult())
+	for _, want := range []string{
+		"digraph imports {",
+		`"example.com/lib" [shape=ellipse`,
+		`"example.com/app/store" [fillcolor="#ffebe9", color="#cf222e", tooltip="2 affected use(s), worst error"];`,
+		`"example.com/app/api" [fillcolor="#fff8c5", color="#bf8700", tooltip="1 affected use(s), worst warning"];`,
+		`"example.com/app/cmd" [fillcolor="#dafbe1", color="#1a7f37", tooltip="no affected uses"];`,
+		`"example.com/app/cmd" -> "example.com/app/api";`,
+		`"example.com/app/store" -> "example.com/lib";`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("FormatDOT() missing %q:\n%s", want, output)
+		}
+	}
+
+	if got := FormatDOT(&analyzer.Result{Changes: &analyzer.Diff{}}); got != "" {
+		t.Errorf("FormatDOT() without a graph = %q, want empty", got)
+	}
+}
+
+func TestImportGraphSVG(t *testing.T) {
+	svg := importGraphSVG(graphResult())
+	if !strings.HasPrefix(svg, "<svg ") || !strings.HasSuffix(svg, "</svg>\n") {
+		t.Fatalf("importGraphSVG() is not an SVG document:\n%s", svg)
+	}
+	// One edge per import, one box per package
+	if got := strings.Count(svg, "<line "); got != 3 {
+		t.Errorf("edges = %d, want 3", got)
+	}
+	if got := strings.Count(svg, "<rect "); got != 4 {
+		t.Errorf("nodes = %d, want 4", got)
+	}
+	// cmd sits in the top row, the module in the bottom row
+	if !strings.Contains(svg, `y="16" width="240" height="32" rx="6" fill="#dafbe1"`) {
+		t.Errorf("expected the unaffected top-level package in the first row:\n%s", svg)
+	}
+
+	html, err := FormatHTML(graphResult())
+	if err != nil {
+		t.Fatalf("FormatHTML() error = %v", err)
+	}
+	if !strings.Contains(html, "<svg ") || !strings.Contains(html, "Affected packages") {
+		t.Error("expected the import graph embedded in the HTML report")
+	}
+}
+
+func TestShortenLabel(t *testing.T) {
+	if got := shortenLabel("example.com/a"); got != "example.com/a" {
+		t.Errorf("shortenLabel() = %q", got)
+	}
+	long := "github.com/example/organization/project/internal/storage"
+	got := shortenLabel(long)
+	if len([]rune(got)) != svgLabelChars || !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "internal/storage") {
+		t.Errorf("shortenLabel(%q) = %q", long, got)
+	}
+}
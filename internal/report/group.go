@@ -0,0 +1,269 @@
+package report
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// Ways to group findings in text and HTML reports
+const (
+	GroupBySymbol  = "symbol"  // one entry per changed symbol (default)
+	GroupByFile    = "file"    // one entry per project file using changed symbols
+	GroupByPackage = "package" // one entry per project package directory
+)
+
+// ParseGroupBy validates a -group-by value, defaulting to GroupBySymbol
+func ParseGroupBy(value string) (string, error) {
+	switch value {
+	case "", GroupBySymbol:
+		return GroupBySymbol, nil
+	case GroupByFile, GroupByPackage:
+		return value, nil
+	default:
+		return "", fmt.Errorf("invalid grouping %q (expected file, symbol, or package)", value)
+	}
+}
+
+// locationGroup collects the findings touching one file or package
+type locationGroup struct {
+	Key   string
+	Items []locationItem
+}
+
+// locationItem is one use of a changed symbol
+type locationItem struct {
+	File        string
+	Line        int
+	Text        string
+	Unstable    bool
+	Approximate bool
+}
+
+// groupByLocation inverts the findings so each project file, or package
+// directory, lists the changes it has to absorb, ordered by line
+func groupByLocation(changes *analyzer.Diff, by string) []locationGroup {
+	groups := make(map[string][]locationItem)
+	add := func(locations []analyzer.Location, text string, unstable bool) {
+		for _, loc := range locations {
+			key := loc.File
+			if by == GroupByPackage {
+				key = filepath.Dir(loc.File)
+			}
+			itemText := text
+			if loc.Kind != "" {
+				itemText += fmt.Sprintf(" (%s)", loc.Kind)
+			}
+			groups[key] = append(groups[key], locationItem{
+				File:        loc.File,
+				Line:        loc.Line,
+				Text:        itemText,
+				Unstable:    unstable,
+				Approximate: loc.Approximate,
+			})
+		}
+	}
+
+	for _, removed := range changes.Removed {
+		add(removed.UsedIn, fmt.Sprintf("%s removed (%s)", removed.Name, removed.Type), removed.Unstable)
+	}
+	for _, moved := range changes.Moved {
+		add(moved.UsedIn, fmt.Sprintf("%s moved to %s.%s", moved.Name, moved.NewPackage, moved.NewName), moved.Unstable)
+	}
+	for _, changed := range changes.Changed {
+		add(changed.UsedIn, fmt.Sprintf("%s signature changed: %s -> %s", changed.Name, changed.OldSignature, changed.NewSignature), changed.Unstable)
+	}
+	for _, iface := range changes.InterfaceChanges {
+		add(iface.UsedIn, fmt.Sprintf("interface %s changed", iface.Name), iface.Unstable)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]locationGroup, 0, len(keys))
+	for _, key := range keys {
+		items := groups[key]
+		sort.SliceStable(items, func(i, j int) bool {
+			if items[i].File != items[j].File {
+				return items[i].File < items[j].File
+			}
+			return items[i].Line < items[j].Line
+		})
+		result = append(result, locationGroup{Key: key, Items: items})
+	}
+	return result
+}
+
+// formatLocationItem prints an item inside its group; package groups name the file too
+func formatLocationItem(item locationItem, by string) string {
+	where := fmt.Sprintf("line %d", item.Line)
+	if by == GroupByPackage {
+		where = fmt.Sprintf("%s:%d", filepath.Base(item.File), item.Line)
+	}
+	return fmt.Sprintf("%s: %s%s%s", where, item.Text, unstableTag(item.Unstable), approximateTag(item.Approximate))
+}
+
+// writeFindingsByLocation writes the findings grouped by file or package,
+// listing at most max per group when max is positive
+func writeFindingsByLocation(b *strings.Builder, changes *analyzer.Diff, by string, max int) {
+	groups := groupByLocation(changes, by)
+	if len(groups) == 0 {
+		return
+	}
+
+	if by == GroupByPackage {
+		b.WriteString("Findings by Package:\n")
+	} else {
+		b.WriteString("Findings by File:\n")
+	}
+	for _, group := range groups {
+		b.WriteString(fmt.Sprintf("  %s (%d use(s))\n", group.Key, len(group.Items)))
+		shown := capFindings(len(group.Items), max)
+		for _, item := range group.Items[:shown] {
+			b.WriteString(fmt.Sprintf("    - %s\n", formatLocationItem(item, by)))
+		}
+		if omitted := len(group.Items) - shown; omitted > 0 {
+			b.WriteString(fmt.Sprintf("    ... %d finding(s) omitted, see JSON for the full list\n", omitted))
+		}
+	}
+	b.WriteString("\n")
+}
+
+// addedKinds order the kinds of added symbols within a package, with the
+// headings they are listed under; other kinds follow under their own name
+var addedKinds = []struct{ kind, heading string }{
+	{"type", "Types"},
+	{"interface", "Interfaces"},
+	{"function", "Functions"},
+	{"method", "Methods on Existing Types"},
+	{"field", "Fields"},
+	{"const", "Constants"},
+	{"var", "Variables"},
+}
+
+// addedPackage lists the symbols a new version adds to one package
+type addedPackage struct {
+	Package string
+	Kinds   map[string][]addedEntry
+}
+
+// addedEntry is an added symbol with the methods added to it when it is a
+// type, or for the "method" kind an existing type with its added methods
+type addedEntry struct {
+	Name    string
+	Methods []string
+}
+
+// groupAdded groups added symbols by package and kind, collapsing methods
+// under their type: under the type itself when it is new as well, otherwise
+// under the existing type in the "method" kind. Names are shown without
+// their package, which toolchain upgrades include.
+func groupAdded(added []analyzer.AddedSymbol) []addedPackage {
+	type key struct{ pkg, name string }
+	newTypes := make(map[key]bool)
+	for _, sym := range added {
+		if sym.Type == "type" || sym.Type == "interface" {
+			newTypes[key{sym.Package, strings.TrimPrefix(sym.Name, sym.Package+".")}] = true
+		}
+	}
+
+	byPkg := make(map[string]*addedPackage)
+	methods := make(map[key][]string) // receiver type -> added methods
+	for _, sym := range added {
+		pkg := byPkg[sym.Package]
+		if pkg == nil {
+			pkg = &addedPackage{Package: sym.Package, Kinds: make(map[string][]addedEntry)}
+			byPkg[sym.Package] = pkg
+		}
+		name := strings.TrimPrefix(sym.Name, sym.Package+".")
+		if recv, method, ok := strings.Cut(name, "."); ok && sym.Type == "function" {
+			methods[key{sym.Package, recv}] = append(methods[key{sym.Package, recv}], method)
+			continue
+		}
+		pkg.Kinds[sym.Type] = append(pkg.Kinds[sym.Type], addedEntry{Name: name})
+	}
+
+	for recv, names := range methods {
+		sort.Strings(names)
+		pkg := byPkg[recv.pkg]
+		if !newTypes[recv] {
+			pkg.Kinds["method"] = append(pkg.Kinds["method"], addedEntry{Name: recv.name, Methods: names})
+			continue
+		}
+		for _, kind := range []string{"type", "interface"} {
+			for i, entry := range pkg.Kinds[kind] {
+				if entry.Name == recv.name {
+					pkg.Kinds[kind][i].Methods = names
+				}
+			}
+		}
+	}
+
+	result := make([]addedPackage, 0, len(byPkg))
+	for _, path := range sortedNames(byPkg) {
+		for _, entries := range byPkg[path].Kinds {
+			sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+		}
+		result = append(result, *byPkg[path])
+	}
+	return result
+}
+
+// writeAddedSymbols writes the added symbols grouped by package and kind.
+// When max is positive, it stops once max symbols, counting collapsed
+// methods, are listed.
+func writeAddedSymbols(b *strings.Builder, added []analyzer.AddedSymbol, max int) {
+	shown := 0
+	full := func() bool { return max > 0 && shown >= max }
+	for _, pkg := range groupAdded(added) {
+		if full() {
+			break
+		}
+		indent := "  "
+		if pkg.Package != "" {
+			b.WriteString(fmt.Sprintf("  %s:\n", pkg.Package))
+			indent = "    "
+		}
+
+		kinds := addedKinds
+		for _, kind := range sortedNames(pkg.Kinds) {
+			known := false
+			for _, k := range addedKinds {
+				known = known || k.kind == kind
+			}
+			if !known {
+				kinds = append(kinds, struct{ kind, heading string }{kind, kind})
+			}
+		}
+		for _, kind := range kinds {
+			entries := pkg.Kinds[kind.kind]
+			if len(entries) == 0 || full() {
+				continue
+			}
+			b.WriteString(fmt.Sprintf("%s%s:\n", indent, kind.heading))
+			for _, entry := range entries {
+				if full() {
+					break
+				}
+				switch {
+				case kind.kind == "method":
+					b.WriteString(fmt.Sprintf("%s  + %s: %s\n", indent, entry.Name, strings.Join(entry.Methods, ", ")))
+				case len(entry.Methods) > 0:
+					b.WriteString(fmt.Sprintf("%s  + %s (with %s)\n", indent, entry.Name, strings.Join(entry.Methods, ", ")))
+					shown++
+				default:
+					b.WriteString(fmt.Sprintf("%s  + %s\n", indent, entry.Name))
+					shown++
+				}
+				shown += len(entry.Methods)
+			}
+		}
+	}
+	writeOmitted(b, len(added)-shown)
+}
@@ -0,0 +1,139 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// SVG size of the directory heatmap, in pixels
+const (
+	heatmapWidth  = 800
+	heatmapHeight = 360
+	heatmapCharPx = 7 // approximate width of a label character
+)
+
+// heatmapTile is a directory placed in the heatmap
+type heatmapTile struct {
+	Label    string
+	Files    int
+	Affected int
+	X, Y     float64
+	W, H     float64
+}
+
+// directoryHeatmapSVG draws a treemap of the project's directories, sized by
+// Go file count and shaded by the number of affected locations within them,
+// to show which parts of the project bear the migration
+func directoryHeatmapSVG(result *analyzer.Result) string {
+	var tiles []heatmapTile
+	byDir := findingsByDir(result)
+	root := commonDir(result.Directories)
+	maxAffected := 0
+	for _, d := range result.Directories {
+		if d.Files == 0 {
+			continue
+		}
+		affected := byDir[d.Dir].Count
+		if affected > maxAffected {
+			maxAffected = affected
+		}
+		tiles = append(tiles, heatmapTile{Label: relativeDir(root, d.Dir), Files: d.Files, Affected: affected})
+	}
+	if len(tiles) == 0 {
+		return ""
+	}
+
+	// Largest first keeps the split balanced
+	sort.SliceStable(tiles, func(i, j int) bool { return tiles[i].Files > tiles[j].Files })
+	layoutTiles(tiles, 0, 0, heatmapWidth, heatmapHeight)
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`+"\n",
+		heatmapWidth, heatmapHeight, heatmapWidth, heatmapHeight))
+	for _, tile := range tiles {
+		title := fmt.Sprintf("%s: %d file(s), %d affected location(s)", tile.Label, tile.Files, tile.Affected)
+		b.WriteString(fmt.Sprintf(`<g><title>%s</title><rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" stroke="#0f1116"/>`,
+			html.EscapeString(title), tile.X, tile.Y, tile.W, tile.H, heatColor(tile.Affected, maxAffected)))
+		if chars := int(tile.W)/heatmapCharPx - 1; chars >= 4 && tile.H >= 16 {
+			b.WriteString(fmt.Sprintf(`<text x="%.1f" y="%.1f" fill="#1f2328">%s</text>`,
+				tile.X+4, tile.Y+13, html.EscapeString(truncateLabel(tile.Label, chars))))
+		}
+		b.WriteString("</g>\n")
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// layoutTiles fills the rectangle with tiles in proportion to their file
+// counts, splitting the list in two halves of similar weight along the
+// rectangle's longer side until every tile has its own area
+func layoutTiles(tiles []heatmapTile, x, y, w, h float64) {
+	if len(tiles) == 1 {
+		tiles[0].X, tiles[0].Y, tiles[0].W, tiles[0].H = x, y, w, h
+		return
+	}
+
+	total := 0
+	for _, tile := range tiles {
+		total += tile.Files
+	}
+	split, first := 0, 0
+	for split < len(tiles)-1 && 2*(first+tiles[split].Files) <= total {
+		first += tiles[split].Files
+		split++
+	}
+	if split == 0 {
+		first = tiles[0].Files
+		split = 1
+	}
+
+	share := float64(first) / float64(total)
+	if w >= h {
+		layoutTiles(tiles[:split], x, y, w*share, h)
+		layoutTiles(tiles[split:], x+w*share, y, w*(1-share), h)
+	} else {
+		layoutTiles(tiles[:split], x, y, w, h*share)
+		layoutTiles(tiles[split:], x, y+h*share, w, h*(1-share))
+	}
+}
+
+// heatColor shades directories from green (untouched) to red (most affected)
+func heatColor(affected, maxAffected int) string {
+	if affected == 0 || maxAffected == 0 {
+		return "#dafbe1"
+	}
+	alpha := 0.25 + 0.75*float64(affected)/float64(maxAffected)
+	return fmt.Sprintf("rgba(207,34,46,%.2f)", alpha)
+}
+
+// commonDir returns the deepest directory containing every directory
+func commonDir(dirs []analyzer.DirectoryStats) string {
+	if len(dirs) == 0 {
+		return ""
+	}
+	common := strings.Split(filepath.ToSlash(dirs[0].Dir), "/")
+	for _, d := range dirs[1:] {
+		parts := strings.Split(filepath.ToSlash(d.Dir), "/")
+		n := 0
+		for n < len(common) && n < len(parts) && common[n] == parts[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	return strings.Join(common, "/")
+}
+
+// relativeDir names dir relative to root, with "." for root itself
+func relativeDir(root, dir string) string {
+	rel := strings.TrimPrefix(filepath.ToSlash(dir), root)
+	rel = strings.TrimPrefix(rel, "/")
+	if rel == "" {
+		return "."
+	}
+	return rel
+}
@@ -0,0 +1,92 @@
+package report
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestLayoutTiles(t *testing.T) {
+	tiles := []heatmapTile{{Files: 6}, {Files: 3}, {Files: 2}, {Files: 1}}
+	layoutTiles(tiles, 0, 0, 800, 360)
+
+	total := 0.0
+	for _, tile := range tiles {
+		area := tile.W * tile.H
+		want := 800 * 360 * float64(tile.Files) / 12
+		if math.Abs(area-want) > 0.5 {
+			t.Errorf("tile with %d file(s) has area %.1f, want %.1f", tile.Files, area, want)
+		}
+		if tile.X < 0 || tile.Y < 0 || tile.X+tile.W > 800.01 || tile.Y+tile.H > 360.01 {
+			t.Errorf("tile %+v outside the heatmap", tile)
+		}
+		total += area
+	}
+	if math.Abs(total-800*360) > 1 {
+		t.Errorf("tiles cover %.1f, want the whole heatmap", total)
+	}
+	// The wide heatmap is split left to right first
+	if tiles[0].X != 0 || tiles[0].H != 360 {
+		t.Errorf("largest tile = %+v, want the left half at full height", tiles[0])
+	}
+}
+
+func TestDirectoryHeatmapSVG(t *testing.T) {
+	result := &analyzer.Result{
+		Changes: &analyzer.Diff{
+			Removed: []analyzer.RemovedSymbol{
+				{Name: "Old", Type: "function", UsedIn: []analyzer.Location{
+					{File: "/src/app/store/a.go", Line: 1},
+					{File: "/src/app/store/b.go", Line: 2},
+					{File: "/src/app/api/api.go", Line: 3},
+				}},
+			},
+		},
+		Directories: []analyzer.DirectoryStats{
+			{Dir: "/src/app/api", Files: 4},
+			{Dir: "/src/app/cmd", Files: 1},
+			{Dir: "/src/app/store", Files: 2},
+		},
+	}
+
+	svg := directoryHeatmapSVG(result)
+	for _, want := range []string{
+		"<title>store: 2 file(s), 2 affected location(s)</title>",
+		`fill="rgba(207,34,46,1.00)"`,
+		"<title>api: 4 file(s), 1 affected location(s)</title>",
+		`fill="rgba(207,34,46,0.62)"`,
+		"<title>cmd: 1 file(s), 0 affected location(s)</title>",
+		`fill="#dafbe1"`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("directoryHeatmapSVG() missing %q:\n%s", want, svg)
+		}
+	}
+
+	html, err := FormatHTML(result)
+	if err != nil {
+		t.Fatalf("FormatHTML() error = %v", err)
+	}
+	if !strings.Contains(html, "Findings by directory") {
+		t.Error("expected the heatmap in the HTML report")
+	}
+	if directoryHeatmapSVG(&analyzer.Result{Changes: &analyzer.Diff{}}) != "" {
+		t.Error("expected no heatmap without directories")
+	}
+}
+
+func TestRelativeDir(t *testing.T) {
+	dirs := []analyzer.DirectoryStats{{Dir: "/src/app"}, {Dir: "/src/app/internal/x"}}
+	root := commonDir(dirs)
+	if root != "/src/app" {
+		t.Fatalf("commonDir() = %q", root)
+	}
+	if got := relativeDir(root, "/src/app"); got != "." {
+		t.Errorf("relativeDir(root) = %q", got)
+	}
+	if got := relativeDir(root, "/src/app/internal/x"); got != "internal/x" {
+		t.Errorf("relativeDir() = %q", got)
+	}
+}
@@ -8,10 +8,38 @@ import (
 	"github.com/devblac/go-semver-audit/internal/analyzer"
 )
 
+// HTMLOptions controls the HTML report layout
+type HTMLOptions struct {
+	GroupBy     string   // GroupBySymbol, GroupByFile, or GroupByPackage; empty means by symbol
+	Attachments []string // relative paths of files shipped alongside the report, linked at the end
+}
+
 // FormatHTML generates a self-contained HTML report.
 func FormatHTML(result *analyzer.Result) (string, error) {
+	return FormatHTMLWithOptions(result, HTMLOptions{})
+}
+
+// FormatHTMLWithOptions generates a self-contained HTML report laid out by opts
+func FormatHTMLWithOptions(result *analyzer.Result, opts HTMLOptions) (string, error) {
 	data := buildHTMLData(result)
 
+	// Grouping by location replaces the per-symbol sections
+	if opts.GroupBy == GroupByFile || opts.GroupBy == GroupByPackage {
+		data.GroupTitle = "Findings by file"
+		if opts.GroupBy == GroupByPackage {
+			data.GroupTitle = "Findings by package"
+		}
+		for _, group := range groupByLocation(result.Changes, opts.GroupBy) {
+			g := htmlGroup{Key: group.Key}
+			for _, item := range group.Items {
+				g.Items = append(g.Items, formatLocationItem(item, opts.GroupBy))
+			}
+			data.Groups = append(data.Groups, g)
+		}
+		data.Removed, data.Moved, data.Changed, data.Interfaces = nil, nil, nil, nil
+	}
+	data.Attachments = opts.Attachments
+
 	tmpl, err := template.New("report").Funcs(template.FuncMap{
 		"join": join,
 	}).Parse(htmlTemplate)
@@ -28,43 +56,164 @@ func FormatHTML(result *analyzer.Result) (string, error) {
 }
 
 type htmlRemoved struct {
-	Name   string
-	Type   string
-	UsedIn string
+	Name        string
+	DocURL      string
+	Type        string
+	UsedIn      string
+	Unstable    bool
+	Approximate bool
+	Severity    string
+	Platforms   string
+	DeadCode    bool
+	ViaModule   string
+	Notes       []string
+}
+
+type htmlMoved struct {
+	Description string
+	DocURL      string
+	UsedIn      string
+	Unstable    bool
+	Approximate bool
+	Severity    string
+	Platforms   string
+	DeadCode    bool
 }
 
 type htmlChanged struct {
 	Name         string
+	DocURL       string
 	OldSignature string
 	NewSignature string
+	Diff         template.HTML
 	UsedIn       string
+	Unstable     bool
+	Approximate  bool
+	PromotedFrom string
+	ViaModule    string
+	Behavior     string
+	Severity     string
+	Platforms    string
+	DeadCode     bool
+
+	ParamChanges   []string
+	Instantiations []string
 }
 
 type htmlInterface struct {
 	Name           string
+	DocURL         string
 	AddedMethods   []string
 	RemovedMethods []string
 	UsedIn         string
+	Unstable       bool
+	Approximate    bool
+	Severity       string
+	Platforms      string
+	ViaModule      string
+	DeadCode       bool
+	Notes          []string
+}
+
+type htmlDocChange struct {
+	Name    string
+	DocURL  string
+	Reasons string
+	Lines   []string
+	UsedIn  string
+}
+
+type htmlExample struct {
+	Symbol string
+	Source string
+	Code   string
+	Output string
 }
 
 type htmlAdded struct {
-	Name string
-	Type string
+	Name   string
+	DocURL string
+	Type   string
+}
+
+type htmlGroup struct {
+	Key   string
+	Items []string
 }
 
 type htmlData struct {
 	Module            string
 	OldVersion        string
 	NewVersion        string
+	NewDependency     bool
+	ToolDependency    bool
+	MainOnly          bool
+	StoppedEarly      string
+	Floor             string
+	FloorSatisfied    bool
+	Replacement       string
+	Retractions       []string
+	DuplicateMajors   []string
+	Deprecation       string
+	SuccessorAudit    string
+	SumDB             string
+	SumDBVerified     bool
+	FromVersion       string
+	UsageOnly         string
+	SymbolsAtRisk     []string
 	Breaking          bool
 	SummaryCount      int
 	AffectedLocations int
+	GroupTitle        string
+	Groups            []htmlGroup
 	Removed           []htmlRemoved
+	Moved             []htmlMoved
 	Changed           []htmlChanged
 	Interfaces        []htmlInterface
 	Added             []htmlAdded
+	DocChanges        []htmlDocChange
+	Warnings          []string
+	LoadErrors        []string
+	Examples          []htmlExample
+	Hints             []string
+	TODOs             []string
+	DeadCode          []string
+	ImportGraph       template.HTML
+	AffectedPackages  []string
+	Heatmap           template.HTML
+	Generated         []string
 	UnusedDeps        []string
 	HasUnusedDeps     bool
+	Requirements      []string
+	Copies            []string
+	Risk              string
+	Footprint         []string
+	Adapter           string
+	AdapterSites      []string
+	BinaryImpact      string
+	Benchmarks        []string
+	TestFailures      []string
+	ModuleChanges     []string
+	Shims             []string
+	Fixes             []string
+	FixDiffs          []analyzer.FileDiff
+	Attachments       []string
+}
+
+// docURL links a dependency symbol to its pkg.go.dev documentation at the
+// given version. Symbols without a known package link to the module root.
+func docURL(module, version, pkg, name string) string {
+	if version == "" || name == "" {
+		return ""
+	}
+	if pkg == "" {
+		pkg = module
+	}
+	if module == analyzer.ToolchainModule {
+		version = "go" + strings.TrimPrefix(version, "go")
+	}
+	anchor := strings.TrimPrefix(name, pkg+".")
+	return "https://pkg.go.dev/" + pkg + "@" + version + "#" + anchor
 }
 
 func buildHTMLData(result *analyzer.Result) htmlData {
@@ -72,46 +221,237 @@ func buildHTMLData(result *analyzer.Result) htmlData {
 		Module:            result.Module,
 		OldVersion:        result.OldVersion,
 		NewVersion:        result.NewVersion,
+		NewDependency:     result.NewDependency,
+		ToolDependency:    result.ToolDependency,
+		MainOnly:          result.MainOnly,
 		Breaking:          result.HasBreakingChanges(),
-		SummaryCount:      len(result.Changes.Removed) + len(result.Changes.Changed) + len(result.Changes.InterfaceChanges),
+		SummaryCount:      result.Changes.BreakingCount(),
 		AffectedLocations: countAffectedLocations(result.Changes),
 		HasUnusedDeps:     len(result.UnusedDeps) > 0,
 		UnusedDeps:        result.UnusedDeps,
 	}
 
+	if result.Replacement != nil {
+		data.Replacement = formatReplacement(result)
+	}
+	for _, r := range result.Retractions {
+		data.Retractions = append(data.Retractions, formatRetraction(result.Module, r))
+	}
+	for _, d := range result.DuplicateMajors {
+		data.DuplicateMajors = append(data.DuplicateMajors, formatDuplicateMajor(result.Module, d))
+	}
+	if d := result.Deprecation; d != nil {
+		data.Deprecation = formatDeprecation(result.Module, d)
+		if d.Successor != "" {
+			data.SuccessorAudit = formatSuccessorAudit(d)
+		}
+	}
+	if s := result.SumDB; s != nil {
+		data.SumDB = formatSumDB(result.Module, s)
+		data.SumDBVerified = s.Verified()
+	}
+	if u := result.UsageOnly; u != nil {
+		data.UsageOnly = formatUsageOnly(u)
+		for _, s := range u.Symbols {
+			data.SymbolsAtRisk = append(data.SymbolsAtRisk, formatSymbolUses(s))
+		}
+	}
+	if result.RequiredVersion != "" {
+		data.FromVersion = formatFromVersion(result)
+	}
+	if result.Floor != nil {
+		data.Floor = formatFloor(result.Floor)
+		data.FloorSatisfied = result.Floor.Satisfied
+	}
+	if result.StoppedEarly {
+		data.StoppedEarly = stoppedEarlyNote
+	}
+
 	for _, removed := range result.Changes.Removed {
 		data.Removed = append(data.Removed, htmlRemoved{
-			Name:   removed.Name,
-			Type:   removed.Type,
-			UsedIn: formatLocations(removed.UsedIn, 5),
+			Name:        removed.Name,
+			DocURL:      docURL(result.Module, result.OldVersion, removed.Package, removed.Name),
+			Type:        removed.Type,
+			UsedIn:      formatLocations(removed.UsedIn, 5),
+			Unstable:    removed.Unstable,
+			Approximate: isApproximate(removed.UsedIn),
+			Severity:    removed.Severity,
+			Platforms:   strings.Join(removed.Platforms, ", "),
+			DeadCode:    removed.DeadCode,
+			ViaModule:   removed.ViaModule,
+			Notes:       usageNotes(removed),
+		})
+	}
+
+	for _, moved := range result.Changes.Moved {
+		data.Moved = append(data.Moved, htmlMoved{
+			Description: formatMove(moved),
+			DocURL:      docURL(result.Module, result.NewVersion, moved.NewPackage, moved.NewName),
+			UsedIn:      formatLocations(moved.UsedIn, 5),
+			Unstable:    moved.Unstable,
+			Approximate: isApproximate(moved.UsedIn),
+			Severity:    moved.Severity,
+			Platforms:   strings.Join(moved.Platforms, ", "),
+			DeadCode:    moved.DeadCode,
 		})
 	}
 
 	for _, changed := range result.Changes.Changed {
+		var paramChanges []string
+		for _, pc := range changed.ParamChanges {
+			paramChanges = append(paramChanges, formatParamChange(pc))
+		}
+		var instantiations []string
+		for _, inst := range changed.Instantiations {
+			instantiations = append(instantiations, "Instantiated as "+formatInstantiation(inst))
+		}
 		data.Changed = append(data.Changed, htmlChanged{
 			Name:         changed.Name,
+			DocURL:       docURL(result.Module, result.NewVersion, changed.Package, changed.Name),
 			OldSignature: changed.OldSignature,
 			NewSignature: changed.NewSignature,
+			Diff:         htmlSignatureDiff(diffSignature(changed.OldSignature, changed.NewSignature)),
 			UsedIn:       formatLocations(changed.UsedIn, 5),
+			Unstable:     changed.Unstable,
+			Approximate:  isApproximate(changed.UsedIn),
+			PromotedFrom: changed.PromotedFrom,
+			ViaModule:    changed.ViaModule,
+			Behavior:     changed.BehaviorChange,
+			Severity:     changedSeverity(changed),
+			Platforms:    strings.Join(changed.Platforms, ", "),
+			DeadCode:     changed.DeadCode,
+
+			ParamChanges:   paramChanges,
+			Instantiations: instantiations,
 		})
 	}
 
 	for _, iface := range result.Changes.InterfaceChanges {
 		data.Interfaces = append(data.Interfaces, htmlInterface{
 			Name:           iface.Name,
+			DocURL:         docURL(result.Module, result.NewVersion, iface.Package, iface.Name),
 			AddedMethods:   iface.AddedMethods,
 			RemovedMethods: iface.RemovedMethods,
 			UsedIn:         formatLocations(iface.UsedIn, 5),
+			Unstable:       iface.Unstable,
+			Approximate:    isApproximate(iface.UsedIn),
+			Severity:       iface.Severity,
+			Platforms:      strings.Join(iface.Platforms, ", "),
+			ViaModule:      iface.ViaModule,
+			DeadCode:       iface.DeadCode,
+			Notes:          interfaceNotes(iface),
 		})
 	}
 
 	for _, added := range result.Changes.Added {
 		data.Added = append(data.Added, htmlAdded{
-			Name: added.Name,
-			Type: added.Type,
+			Name:   added.Name,
+			DocURL: docURL(result.Module, result.NewVersion, added.Package, added.Name),
+			Type:   added.Type,
+		})
+	}
+
+	for _, change := range result.DocChanges {
+		data.DocChanges = append(data.DocChanges, htmlDocChange{
+			Name:    change.Name,
+			DocURL:  docURL(result.Module, result.NewVersion, change.Package, change.Name),
+			Reasons: strings.Join(change.Reasons, ", "),
+			Lines:   formatDocChange(change),
+			UsedIn:  formatLocations(change.UsedIn, 5),
+		})
+	}
+
+	for _, le := range result.LoadErrors {
+		data.LoadErrors = append(data.LoadErrors, formatLoadError(le))
+	}
+	for _, w := range result.Warnings {
+		data.Warnings = append(data.Warnings, formatWarning(w))
+	}
+
+	for _, ex := range result.Examples {
+		data.Examples = append(data.Examples, htmlExample{
+			Symbol: ex.Symbol,
+			Source: formatExampleSource(ex),
+			Code:   ex.Code,
+			Output: ex.Output,
 		})
 	}
 
+	for _, hint := range result.Hints {
+		data.Hints = append(data.Hints, formatHint(hint))
+	}
+
+	for _, todo := range result.TODOs {
+		data.TODOs = append(data.TODOs, formatTODOMatch(todo))
+	}
+
+	for _, fn := range result.DeadCode {
+		data.DeadCode = append(data.DeadCode, formatDeadFunction(fn))
+	}
+
+	if len(result.Directories) > 0 {
+		// Built from escaped strings only
+		data.Heatmap = template.HTML(directoryHeatmapSVG(result))
+	}
+
+	if graph := result.ImportGraph; graph != nil {
+		// Built from escaped strings only
+		data.ImportGraph = template.HTML(importGraphSVG(result))
+		byDir := findingsByDir(result)
+		for _, node := range graph.Nodes {
+			data.AffectedPackages = append(data.AffectedPackages, formatGraphNode(node, byDir[node.Dir]))
+		}
+	}
+
+	for _, group := range result.Changes.Generated {
+		data.Generated = append(data.Generated, formatGeneratedGroup(group))
+	}
+
+	for _, c := range result.Copies {
+		data.Copies = append(data.Copies, formatCopy(c))
+	}
+
+	for _, req := range result.Requirements {
+		data.Requirements = append(data.Requirements, formatRequirement(req))
+	}
+
+	if result.ModuleChanges != nil {
+		data.ModuleChanges = formatModuleChanges(result.ModuleChanges)
+	}
+
+	if result.Risk != nil {
+		data.Risk = formatRisk(result.Risk)
+	}
+
+	data.Footprint = formatFootprint(result)
+	if adapter := result.Adapter; adapter != nil && result.Coupling != nil {
+		data.Adapter = formatAdapter(result.Module, result.Coupling, adapter)
+		for _, site := range adapter.Sites {
+			data.AdapterSites = append(data.AdapterSites, formatSymbolUses(site))
+		}
+	}
+
+	if result.BinaryImpact != nil {
+		data.BinaryImpact = formatBinaryImpact(result.BinaryImpact)
+	}
+
+	for _, failure := range result.TestFailures {
+		data.TestFailures = append(data.TestFailures, formatTestFailure(failure))
+	}
+
+	if result.Shims != nil {
+		data.Shims = formatShims(result.Shims)
+	}
+
+	if result.Fixes != nil {
+		data.Fixes = formatFixes(result.Fixes)
+		data.FixDiffs = result.Fixes.Diffs
+	}
+
+	for _, bench := range result.Benchmarks {
+		data.Benchmarks = append(data.Benchmarks, formatBenchmark(bench))
+	}
+
 	return data
 }
 
@@ -121,7 +461,7 @@ const htmlTemplate = `<!DOCTYPE html>
 <head>
   <meta charset="UTF-8">
   <meta name="viewport" content="width=device-width, initial-scale=1.0">
-  <title>go-semver-audit: {{.Module}} {{.OldVersion}} → {{.NewVersion}}</title>
+  <title>go-semver-audit: {{.Module}} {{if .NewDependency}}{{.NewVersion}} (new){{else}}{{.OldVersion}} → {{.NewVersion}}{{end}}</title>
   <style>
     :root { color-scheme: light dark; }
     body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; margin: 0; padding: 24px; line-height: 1.5; background: #0f1116; color: #e7ecf3; }
@@ -139,13 +479,29 @@ const htmlTemplate = `<!DOCTYPE html>
     code { background: rgba(255,255,255,0.06); padding: 2px 5px; border-radius: 6px; }
     .muted { color: #9aa4b5; }
     .stacked { margin: 8px 0 0; }
+    .graph { overflow-x: auto; }
+    .stacked a { color: inherit; }
+    pre { background: rgba(255,255,255,0.04); padding: 8px 12px; border-radius: 6px; overflow-x: auto; }
+    .sigdiff del { color: #e74c3c; background: rgba(231,76,60,0.15); }
+    .sigdiff ins { color: #2ecc71; background: rgba(46,204,113,0.15); text-decoration: none; }
   </style>
 </head>
 <body>
   <section>
     <h1>go-semver-audit</h1>
-    <div class="muted">{{.Module}} {{.OldVersion}} → {{.NewVersion}}</div>
-    {{if .Breaking}}<span class="pill warn">Breaking changes detected</span>{{else}}<span class="pill ok">No breaking changes</span>{{end}}
+    <div class="muted">{{.Module}} {{if .NewDependency}}{{.NewVersion}} (new dependency){{else}}{{.OldVersion}} → {{.NewVersion}}{{end}}</div>
+    {{if .MainOnly}}<span class="pill ok">Commands only</span>{{else if .UsageOnly}}<span class="pill warn">API diff unavailable</span>{{else if .Breaking}}<span class="pill warn">Breaking changes detected</span>{{else}}<span class="pill ok">No breaking changes</span>{{end}}
+    {{range .Retractions}}<p><span class="pill warn">Retracted</span> {{.}}</p>{{end}}
+    {{range .DuplicateMajors}}<p><span class="pill warn">Duplicate major</span> {{.}}</p>{{end}}
+    {{if .Deprecation}}<p><span class="pill warn">Deprecated</span> {{.Deprecation}}</p>{{if .SuccessorAudit}}<p class="muted">{{.SuccessorAudit}}</p>{{end}}{{end}}
+    {{if .Replacement}}<p class="muted">⚠️ {{.Replacement}}</p>{{end}}
+    {{if .SumDB}}<p><span class="pill {{if .SumDBVerified}}ok{{else}}warn{{end}}">Checksum</span> {{.SumDB}}</p>{{end}}
+    {{if .FromVersion}}<p class="muted">{{.FromVersion}}</p>{{end}}
+    {{if .UsageOnly}}<p>⚠️ {{.UsageOnly}}</p><h2>Symbols at risk</h2><ul>{{range .SymbolsAtRisk}}<li><code>{{.}}</code></li>{{else}}<li class="muted">None: the project does not use the module.</li>{{end}}</ul>{{end}}
+    {{if .Floor}}<p><span class="pill {{if .FloorSatisfied}}ok{{else}}warn{{end}}">Minimum version</span> {{.Floor}}</p>{{end}}
+    {{if .StoppedEarly}}<p class="muted">{{.StoppedEarly}}</p>{{end}}
+    {{if .ToolDependency}}<p class="muted">Tool dependency pinned in tools.go; module metadata is compared instead of API usage.</p>{{end}}
+    {{if .MainOnly}}<p class="muted">The module only contains commands (package main), so it has no API to break; module metadata is compared instead.</p>{{end}}
   </section>
 
   <section>
@@ -166,12 +522,39 @@ const htmlTemplate = `<!DOCTYPE html>
     </div>
   </section>
 
+  {{if .Groups}}
+  <section>
+    <h2>{{.GroupTitle}}</h2>
+    {{range .Groups}}
+      <div class="stacked">
+        <strong>{{.Key}}</strong>
+        <ul>
+          {{range .Items}}<li>{{.}}</li>{{end}}
+        </ul>
+      </div>
+    {{end}}
+  </section>
+  {{end}}
+
   {{if .Removed}}
   <section>
     <h2>Removed symbols</h2>
     {{range .Removed}}
       <div class="stacked">
-        <strong>{{.Name}}</strong> <span class="muted">({{.Type}})</span><br>
+        <strong>{{template "symbol" .}}</strong> <span class="muted">({{.Type}})</span>{{if .ViaModule}} <span class="muted">(via transitive module {{.ViaModule}})</span>{{end}}{{if .Unstable}} <span class="pill warn">unstable</span>{{end}}{{if .Approximate}} <span class="pill warn">approximate</span>{{end}}{{if .Severity}} <span class="pill">{{.Severity}}</span>{{end}}{{if .DeadCode}} <span class="pill warn">dead code only</span>{{end}}{{if .Platforms}} <span class="pill warn">only on {{.Platforms}}</span>{{end}}<br>
+        {{if .UsedIn}}<span class="muted">Used in:</span> {{.UsedIn}}{{else}}<span class="muted">Not detected in use</span>{{end}}
+        {{range .Notes}}<div class="muted">{{.}}</div>{{end}}
+      </div>
+    {{end}}
+  </section>
+  {{end}}
+
+  {{if .Moved}}
+  <section>
+    <h2>Moved symbols</h2>
+    {{range .Moved}}
+      <div class="stacked">
+        <strong>{{if .DocURL}}<a href="{{.DocURL}}">{{.Description}}</a>{{else}}{{.Description}}{{end}}</strong>{{if .Unstable}} <span class="pill warn">unstable</span>{{end}}{{if .Approximate}} <span class="pill warn">approximate</span>{{end}}{{if .Severity}} <span class="pill">{{.Severity}}</span>{{end}}{{if .DeadCode}} <span class="pill warn">dead code only</span>{{end}}{{if .Platforms}} <span class="pill warn">only on {{.Platforms}}</span>{{end}}<br>
         {{if .UsedIn}}<span class="muted">Used in:</span> {{.UsedIn}}{{else}}<span class="muted">Not detected in use</span>{{end}}
       </div>
     {{end}}
@@ -183,10 +566,11 @@ const htmlTemplate = `<!DOCTYPE html>
     <h2>Changed signatures</h2>
     {{range .Changed}}
       <div class="stacked">
-        <strong>{{.Name}}</strong><br>
-        <span class="muted">Old:</span> <code>{{.OldSignature}}</code><br>
-        <span class="muted">New:</span> <code>{{.NewSignature}}</code><br>
+        <strong>{{template "symbol" .}}</strong>{{if .PromotedFrom}} <span class="muted">(promoted from {{.PromotedFrom}})</span>{{end}}{{if .ViaModule}} <span class="muted">(via transitive module {{.ViaModule}})</span>{{end}}{{if .Behavior}} <span class="pill warn">{{.Behavior}}</span>{{end}}{{if .Unstable}} <span class="pill warn">unstable</span>{{end}}{{if .Approximate}} <span class="pill warn">approximate</span>{{end}}{{if .Severity}} <span class="pill">{{.Severity}}</span>{{end}}{{if .DeadCode}} <span class="pill warn">dead code only</span>{{end}}{{if .Platforms}} <span class="pill warn">only on {{.Platforms}}</span>{{end}}<br>
+        <code class="sigdiff" title="{{.OldSignature}} → {{.NewSignature}}">{{.Diff}}</code><br>
         {{if .UsedIn}}<span class="muted">Used in:</span> {{.UsedIn}}{{else}}<span class="muted">Not detected in use</span>{{end}}
+        {{range .ParamChanges}}<div>{{.}}</div>{{end}}
+        {{range .Instantiations}}<div class="muted">{{.}}</div>{{end}}
       </div>
     {{end}}
   </section>
@@ -197,26 +581,209 @@ const htmlTemplate = `<!DOCTYPE html>
     <h2>Modified interfaces</h2>
     {{range .Interfaces}}
       <div class="stacked">
-        <strong>{{.Name}}</strong><br>
+        <strong>{{template "symbol" .}}</strong>{{if .ViaModule}} <span class="muted">(via transitive module {{.ViaModule}})</span>{{end}}{{if .Unstable}} <span class="pill warn">unstable</span>{{end}}{{if .Approximate}} <span class="pill warn">approximate</span>{{end}}{{if .Severity}} <span class="pill">{{.Severity}}</span>{{end}}{{if .DeadCode}} <span class="pill warn">dead code only</span>{{end}}{{if .Platforms}} <span class="pill warn">only on {{.Platforms}}</span>{{end}}<br>
         {{if .RemovedMethods}}<div><span class="muted">Removed:</span> {{join .RemovedMethods ", "}}</div>{{end}}
         {{if .AddedMethods}}<div><span class="muted">Added:</span> {{join .AddedMethods ", "}}</div>{{end}}
         {{if .UsedIn}}<span class="muted">Used in:</span> {{.UsedIn}}{{else}}<span class="muted">Not detected in use</span>{{end}}
+        {{range .Notes}}<div class="muted">{{.}}</div>{{end}}
+      </div>
+    {{end}}
+  </section>
+  {{end}}
+
+  {{if .DocChanges}}
+  <section>
+    <h2>Documentation changes</h2>
+    {{range .DocChanges}}
+      <div class="stacked">
+        <strong>{{template "symbol" .}}</strong> <span class="muted">({{.Reasons}})</span><br>
+        {{range .Lines}}<div><code>{{.}}</code></div>{{end}}
+        {{if .UsedIn}}<span class="muted">Used in:</span> {{.UsedIn}}{{end}}
+      </div>
+    {{end}}
+  </section>
+  {{end}}
+
+  {{if .Examples}}
+  <section>
+    <h2>Migration examples</h2>
+    {{range .Examples}}
+      <div class="stacked">
+        <strong>{{.Symbol}}</strong> <span class="muted">({{.Source}})</span>
+        <pre><code>{{.Code}}</code></pre>
+        {{if .Output}}<span class="muted">Output:</span><pre><code>{{.Output}}</code></pre>{{end}}
       </div>
     {{end}}
   </section>
   {{end}}
 
+  {{if .Heatmap}}
+  <section>
+    <h2>Findings by directory</h2>
+    <p class="muted">Directories sized by Go file count and shaded from green (untouched) to red (most affected locations).</p>
+    <div class="graph">{{.Heatmap}}</div>
+  </section>
+  {{end}}
+
+  {{if .AffectedPackages}}
+  <section>
+    <h2>Affected packages</h2>
+    <p class="muted">Project packages importing the module, colored by their most severe finding.</p>
+    <div class="graph">{{.ImportGraph}}</div>
+    <ul>
+      {{range .AffectedPackages}}<li>{{.}}</li>{{end}}
+    </ul>
+  </section>
+  {{end}}
+
+  {{if .Hints}}
+  <section>
+    <h2>Adoption hints</h2>
+    <ul>
+      {{range .Hints}}<li>{{.}}</li>{{end}}
+    </ul>
+  </section>
+  {{end}}
+
+  {{if .TODOs}}
+  <section>
+    <h2>TODOs the new version may address</h2>
+    <ul>
+      {{range .TODOs}}<li>{{.}}</li>{{end}}
+    </ul>
+  </section>
+  {{end}}
+
+  {{if .DeadCode}}
+  <section>
+    <h2>Dead code (delete instead of migrating)</h2>
+    <ul>
+      {{range .DeadCode}}<li>{{.}}</li>{{end}}
+    </ul>
+  </section>
+  {{end}}
+
+  {{if .Generated}}
+  <section>
+    <h2>Generated packages</h2>
+    <ul>
+      {{range .Generated}}<li>{{.}}</li>{{end}}
+    </ul>
+  </section>
+  {{end}}
+
   {{if .Added}}
   <section>
-    <h2>Added symbols (informational)</h2>
+    <h2>{{if .NewDependency}}API you would adopt{{else}}Added symbols (informational){{end}}</h2>
     {{range .Added}}
       <div class="stacked">
-        <strong>{{.Name}}</strong> <span class="muted">({{.Type}})</span>
+        <strong>{{template "symbol" .}}</strong> <span class="muted">({{.Type}})</span>
       </div>
     {{end}}
   </section>
   {{end}}
 
+  {{if .Copies}}
+  <section>
+    <h2>Copies of the dependency</h2>
+    <p class="muted">References inside these directories are excluded from the findings.</p>
+    <ul>
+      {{range .Copies}}<li>{{.}}</li>{{end}}
+    </ul>
+  </section>
+  {{end}}
+
+  {{if .Requirements}}
+  <section>
+    <h2>Required modules</h2>
+    <ul>
+      {{range .Requirements}}<li>{{.}}</li>{{end}}
+    </ul>
+  </section>
+  {{end}}
+
+  {{if .ModuleChanges}}
+  <section>
+    <h2>Tool module changes</h2>
+    <ul>
+      {{range .ModuleChanges}}<li><code>{{.}}</code></li>{{end}}
+    </ul>
+  </section>
+  {{end}}
+
+  {{if .Risk}}
+  <section>
+    <h2>Risk</h2>
+    <div>{{.Risk}}</div>
+  </section>
+  {{end}}
+
+  {{if .Footprint}}
+  <section>
+    <h2>Footprint</h2>
+    <ul>
+      {{range .Footprint}}<li>{{.}}</li>{{end}}
+    </ul>
+  </section>
+  {{end}}
+
+  {{if .Adapter}}
+  <section>
+    <h2>Advisory</h2>
+    <p>{{.Adapter}}</p>
+    <ul>
+      {{range .AdapterSites}}<li><code>{{.}}</code></li>{{end}}
+    </ul>
+  </section>
+  {{end}}
+
+  {{if .BinaryImpact}}
+  <section>
+    <h2>Binary size</h2>
+    <div>{{.BinaryImpact}}</div>
+  </section>
+  {{end}}
+
+  {{if .TestFailures}}
+  <section>
+    <h2>Failing tests after upgrade</h2>
+    <ul>
+      {{range .TestFailures}}<li><code>{{.}}</code></li>{{end}}
+    </ul>
+  </section>
+  {{end}}
+
+  {{if .Shims}}
+  <section>
+    <h2>Compatibility shims</h2>
+    <ul>
+      {{range .Shims}}<li><code>{{.}}</code></li>{{end}}
+    </ul>
+  </section>
+  {{end}}
+
+  {{if .Fixes}}
+  <section>
+    <h2>Automatic fixes</h2>
+    <ul>
+      {{range .Fixes}}<li><code>{{.}}</code></li>{{end}}
+    </ul>
+    {{range .FixDiffs}}
+    <h3><code>{{.File}}</code></h3>
+    <pre><code>{{.Diff}}</code></pre>
+    {{end}}
+  </section>
+  {{end}}
+
+  {{if .Benchmarks}}
+  <section>
+    <h2>Benchmarks (ns/op)</h2>
+    <ul>
+      {{range .Benchmarks}}<li><code>{{.}}</code></li>{{end}}
+    </ul>
+  </section>
+  {{end}}
+
   {{if .HasUnusedDeps}}
   <section>
     <h2>Unused dependencies</h2>
@@ -225,9 +792,37 @@ const htmlTemplate = `<!DOCTYPE html>
     </ul>
   </section>
   {{end}}
+
+  {{if .LoadErrors}}
+  <section>
+    <h2>Load errors</h2>
+    <p class="muted">The project did not load cleanly; findings marked approximate may be incomplete.</p>
+    <ul>
+      {{range .LoadErrors}}<li>{{.}}</li>{{end}}
+    </ul>
+  </section>
+  {{end}}
+
+  {{if .Warnings}}
+  <section>
+    <h2>Warnings</h2>
+    <ul>
+      {{range .Warnings}}<li>{{.}}</li>{{end}}
+    </ul>
+  </section>
+  {{end}}
+
+  {{if .Attachments}}
+  <section>
+    <h2>Also in this bundle</h2>
+    <ul>
+      {{range .Attachments}}<li><a href="{{.}}">{{.}}</a></li>{{end}}
+    </ul>
+  </section>
+  {{end}}
 </body>
 </html>
-`
+{{define "symbol"}}{{if .DocURL}}<a href="{{.DocURL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}{{end}}`
 
 // join provides comma-separated lists inside templates.
 func join(items []string, sep string) string {
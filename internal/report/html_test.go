@@ -69,3 +69,108 @@ func TestFormatHTML(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatHTMLLinksSymbolDocs(t *testing.T) {
+	result := &analyzer.Result{
+		Module:     "github.com/example/lib",
+		OldVersion: "v1.0.0",
+		NewVersion: "v1.2.0",
+		Changes: &analyzer.Diff{
+			Removed: []analyzer.RemovedSymbol{
+				{Name: "OldFunc", Type: "function", Package: "github.com/example/lib/sub", UsedIn: []analyzer.Location{{File: "a.go", Line: 1}}},
+			},
+			Changed: []analyzer.ChangedSignature{
+				{Name: "Client.Do", OldSignature: "func()", NewSignature: "func() error", Package: "github.com/example/lib"},
+			},
+			Added: []analyzer.AddedSymbol{{Name: "NewFunc", Type: "function"}},
+		},
+	}
+
+	out, err := FormatHTML(result)
+	if err != nil {
+		t.Fatalf("FormatHTML() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`<a href="https://pkg.go.dev/github.com/example/lib/sub@v1.0.0#OldFunc">OldFunc</a>`,
+		`<a href="https://pkg.go.dev/github.com/example/lib@v1.2.0#Client.Do">Client.Do</a>`,
+		`<a href="https://pkg.go.dev/github.com/example/lib@v1.2.0#NewFunc">NewFunc</a>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected HTML output to contain %q", want)
+		}
+	}
+}
+
+func TestDocURL(t *testing.T) {
+	tests := []struct {
+		module, version, pkg, name string
+		want                       string
+	}{
+		{"github.com/example/lib", "v1.2.0", "github.com/example/lib", "Func", "https://pkg.go.dev/github.com/example/lib@v1.2.0#Func"},
+		{"github.com/example/lib", "v1.2.0", "", "Type.Method", "https://pkg.go.dev/github.com/example/lib@v1.2.0#Type.Method"},
+		{analyzer.ToolchainModule, "1.22", "net/http", "net/http.Client.Timeout", "https://pkg.go.dev/net/http@go1.22#Client.Timeout"},
+		{"github.com/example/lib", "", "", "Func", ""},
+	}
+	for _, tt := range tests {
+		if got := docURL(tt.module, tt.version, tt.pkg, tt.name); got != tt.want {
+			t.Errorf("docURL(%q, %q, %q, %q) = %q, want %q", tt.module, tt.version, tt.pkg, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatHTMLGroupByFile(t *testing.T) {
+	result := &analyzer.Result{
+		Module:     "github.com/example/lib",
+		OldVersion: "v1.0.0",
+		NewVersion: "v2.0.0",
+		Changes: &analyzer.Diff{
+			Removed: []analyzer.RemovedSymbol{
+				{Name: "OldFunc", Type: "function", UsedIn: []analyzer.Location{{File: "main.go", Line: 7}}},
+			},
+		},
+	}
+
+	out, err := FormatHTMLWithOptions(result, HTMLOptions{GroupBy: GroupByFile})
+	if err != nil {
+		t.Fatalf("FormatHTMLWithOptions() error = %v", err)
+	}
+	for _, want := range []string{"Findings by file", "<strong>main.go</strong>", "<li>line 7: OldFunc removed (function)</li>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected HTML output to contain %q", want)
+		}
+	}
+	if strings.Contains(out, "Removed symbols") {
+		t.Error("expected per-symbol sections to be replaced by groups")
+	}
+}
+
+func TestFormatHTMLEmbedsFixDiffs(t *testing.T) {
+	result := &analyzer.Result{
+		Module:     "github.com/example/lib",
+		OldVersion: "v1.0.0",
+		NewVersion: "v1.1.0",
+		Changes:    &analyzer.Diff{},
+		Fixes: &analyzer.FixReport{
+			DryRun: true,
+			Files:  []string{"/src/app/main.go"},
+			Fixed:  []analyzer.FixSite{{Symbol: "Fetch", Location: analyzer.Location{File: "/src/app/main.go", Line: 6}, Fix: "pass context.TODO()"}},
+			Diffs:  []analyzer.FileDiff{{File: "main.go", Diff: "--- a/main.go\n+++ b/main.go\n@@ -6 +6 @@\n-\tlib.Fetch()\n+\tlib.Fetch(context.TODO())\n"}},
+		},
+	}
+
+	out, err := FormatHTML(result)
+	if err != nil {
+		t.Fatalf("FormatHTML() error = %v", err)
+	}
+	for _, want := range []string{
+		"Automatic fixes",
+		"Would rewrite 1 call site(s) in 1 file(s) (dry run, nothing written)",
+		"<h3><code>main.go</code></h3>",
+		"\tlib.Fetch(context.TODO())",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected HTML output to contain %q", want)
+		}
+	}
+}
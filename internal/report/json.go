@@ -2,6 +2,7 @@ package report
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/devblac/go-semver-audit/internal/analyzer"
 )
@@ -11,37 +12,325 @@ type JSONReport struct {
 	Module            string                `json:"module"`
 	OldVersion        string                `json:"old_version"`
 	NewVersion        string                `json:"new_version"`
+	RequiredVersion   string                `json:"required_version,omitempty"`
+	NewDependency     bool                  `json:"new_dependency,omitempty"`
+	ToolDependency    bool                  `json:"tool_dependency,omitempty"`
+	MainOnly          bool                  `json:"main_only,omitempty"`
+	Replacement       *ReplacementItem      `json:"replacement,omitempty"`
+	Retractions       []RetractionItem      `json:"retractions,omitempty"`
+	Deprecation       *DeprecationItem      `json:"deprecation,omitempty"`
+	DuplicateMajors   []DuplicateMajorItem  `json:"duplicate_majors,omitempty"`
+	UsageOnly         *UsageOnlyItem        `json:"usage_only,omitempty"`
 	Breaking          bool                  `json:"breaking"`
+	StoppedEarly      bool                  `json:"stopped_early,omitempty"`
+	Floor             *FloorItem            `json:"floor,omitempty"`
 	BreakingCount     int                   `json:"breaking_count"`
 	AffectedLocations int                   `json:"affected_locations"`
+	UnstableCount     int                   `json:"unstable_count,omitempty"`
+	Summary           SummaryItem           `json:"summary"`
 	Removed           []RemovedItem         `json:"removed,omitempty"`
 	Changed           []ChangedItem         `json:"changed,omitempty"`
 	InterfaceChanges  []InterfaceChangeItem `json:"interface_changes,omitempty"`
+	Moved             []MovedItem           `json:"moved,omitempty"`
 	Added             []AddedItem           `json:"added,omitempty"`
+	Generated         []GeneratedItem       `json:"generated,omitempty"`
 	UnusedDeps        []string              `json:"unused_dependencies,omitempty"`
+	Requirements      []RequirementItem     `json:"requirements,omitempty"`
+	Copies            []CopyItem            `json:"copies,omitempty"`
+	DocChanges        []DocChangeItem       `json:"doc_changes,omitempty"`
+	Examples          []ExampleItem         `json:"examples,omitempty"`
+	Hints             []HintItem            `json:"hints,omitempty"`
+	TODOs             []TODOItem            `json:"todos,omitempty"`
+	DeadCode          []DeadFunctionItem    `json:"dead_code,omitempty"`
+	ImportGraph       *ImportGraphItem      `json:"import_graph,omitempty"`
+	Directories       []DirectoryItem       `json:"directories,omitempty"`
+	Risk              *RiskItem             `json:"risk,omitempty"`
+	Footprint         *FootprintItem        `json:"footprint,omitempty"`
+	Coupling          *CouplingItem         `json:"coupling,omitempty"`
+	Adapter           *AdapterItem          `json:"adapter,omitempty"`
+	BinaryImpact      *BinaryImpactItem     `json:"binary_impact,omitempty"`
+	Benchmarks        []BenchmarkItem       `json:"benchmarks,omitempty"`
+	TestFailures      []TestFailureItem     `json:"test_failures,omitempty"`
+	ModuleChanges     *ModuleChangesItem    `json:"module_changes,omitempty"`
+	Shims             *ShimsItem            `json:"shims,omitempty"`
+	Fixes             *FixesItem            `json:"fixes,omitempty"`
+	Provenance        *ProvenanceItem       `json:"provenance,omitempty"`
+	SumDB             *SumDBItem            `json:"sumdb,omitempty"`
+	Warnings          []WarningItem         `json:"warnings,omitempty"`
+	LoadErrors        []LoadErrorItem       `json:"load_errors,omitempty"`
+}
+
+// RetractionItem represents a retracted version of the upgrade in JSON
+type RetractionItem struct {
+	Version   string `json:"version"`
+	Current   bool   `json:"current,omitempty"`
+	Rationale string `json:"rationale,omitempty"`
+}
+
+// DuplicateMajorItem represents another major of the upgraded module left
+// in the module graph in JSON
+type DuplicateMajorItem struct {
+	Module    string   `json:"module"`
+	Version   string   `json:"version,omitempty"`
+	Importers []string `json:"importers,omitempty"`
+}
+
+// DeprecationItem represents the deprecation of the upgraded module in JSON
+type DeprecationItem struct {
+	Message   string `json:"message"`
+	Successor string `json:"successor,omitempty"`
+}
+
+// UsageOnlyItem represents the project's uses of a module whose API could
+// not be diffed in JSON
+type UsageOnlyItem struct {
+	Reason  string           `json:"reason"`
+	Symbols []SymbolUsesItem `json:"symbols"`
+}
+
+// SymbolUsesItem represents a symbol at risk and where it is used in JSON
+type SymbolUsesItem struct {
+	Name   string     `json:"name"`
+	UsedIn []Location `json:"used_in"`
+}
+
+// SumDBItem represents the checksum verification of the new version in JSON
+type SumDBItem struct {
+	Version  string `json:"version"`
+	Sum      string `json:"sum,omitempty"`
+	Verified bool   `json:"verified"`
+	Database string `json:"database,omitempty"`
+	Bypass   string `json:"bypass,omitempty"`
+}
+
+// ProvenanceItem represents the inputs of a run in JSON
+type ProvenanceItem struct {
+	ToolVersion string           `json:"tool_version,omitempty"`
+	GoVersion   string           `json:"go_version"`
+	GOFLAGS     string           `json:"goflags"`
+	GOOS        string           `json:"goos"`
+	GOARCH      string           `json:"goarch"`
+	Module      string           `json:"module"`
+	OldVersion  string           `json:"old_version,omitempty"`
+	NewVersion  string           `json:"new_version"`
+	OldSum      string           `json:"old_sum,omitempty"`
+	NewSum      string           `json:"new_sum"`
+	Options     analyzer.Options `json:"options"`
+}
+
+// ShimsItem represents the generated compatibility shims in JSON
+type ShimsItem struct {
+	DryRun    bool          `json:"dry_run,omitempty"`
+	Path      string        `json:"path,omitempty"`
+	Functions []string      `json:"functions,omitempty"`
+	Skipped   []string      `json:"skipped,omitempty"`
+	Diff      *FileDiffItem `json:"diff,omitempty"`
+}
+
+// FixesItem represents the call sites rewritten by -fix in JSON
+type FixesItem struct {
+	DryRun  bool           `json:"dry_run,omitempty"`
+	Files   []string       `json:"files"`
+	Fixed   []FixSiteItem  `json:"fixed"`
+	Skipped []FixSiteItem  `json:"skipped"`
+	Diffs   []FileDiffItem `json:"diffs,omitempty"`
+
+	// Tests rerun against the new version after the rewrite, with -run-tests
+	Tested       bool              `json:"tested,omitempty"`
+	TestFailures []TestFailureItem `json:"test_failures,omitempty"`
+}
+
+// FileDiffItem represents the unified diff of a file edited by -fix in JSON
+type FileDiffItem struct {
+	File string `json:"file"`
+	Diff string `json:"diff"`
+}
+
+// FixSiteItem represents a call site considered by -fix in JSON
+type FixSiteItem struct {
+	Symbol string `json:"symbol"`
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column,omitempty"`
+	Fix    string `json:"fix,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ModuleChangesItem represents go.mod changes of a tool dependency in JSON
+type ModuleChangesItem struct {
+	OldGoVersion string   `json:"old_go_version,omitempty"`
+	NewGoVersion string   `json:"new_go_version,omitempty"`
+	Added        []string `json:"added,omitempty"`
+	Removed      []string `json:"removed,omitempty"`
+	Upgraded     []string `json:"upgraded,omitempty"`
+}
+
+// TestFailureItem represents a test that fails after the upgrade in JSON
+type TestFailureItem struct {
+	Package string `json:"package"`
+	Test    string `json:"test,omitempty"`
+}
+
+// BenchmarkItem represents a benchmark comparison in JSON
+type BenchmarkItem struct {
+	Name         string  `json:"name"`
+	OldNsPerOp   float64 `json:"old_ns_per_op"`
+	NewNsPerOp   float64 `json:"new_ns_per_op"`
+	DeltaPercent float64 `json:"delta_percent"`
+	Regression   bool    `json:"regression"`
+}
+
+// BinaryImpactItem represents the binary size delta in JSON
+type BinaryImpactItem struct {
+	Package      string `json:"package"`
+	OldSizeBytes int64  `json:"old_size_bytes"`
+	NewSizeBytes int64  `json:"new_size_bytes"`
+	DeltaBytes   int64  `json:"delta_bytes"`
+	Error        string `json:"error,omitempty"`
+}
+
+// RiskItem represents the dependency risk score in JSON
+type RiskItem struct {
+	Score        int     `json:"score"`
+	Level        string  `json:"level"`
+	Audits       int     `json:"audits"`
+	BreakingRate float64 `json:"breaking_rate"`
+	APISurface   int     `json:"api_surface"`
+	UsedSymbols  int     `json:"used_symbols"`
+	UsageSites   int     `json:"usage_sites"`
+}
+
+// FootprintItem represents the size and dependency delta in JSON
+type FootprintItem struct {
+	OldSizeBytes   int64    `json:"old_size_bytes"`
+	NewSizeBytes   int64    `json:"new_size_bytes"`
+	OldPackages    int      `json:"old_packages"`
+	NewPackages    int      `json:"new_packages"`
+	PackagesAdded  []string `json:"packages_added,omitempty"`
+	ModulesAdded   []string `json:"modules_added,omitempty"`
+	ModulesRemoved []string `json:"modules_removed,omitempty"`
+
+	SharedBumps []SharedBumpItem `json:"shared_bumps,omitempty"`
+}
+
+// SharedBumpItem represents a module the project requires whose version the
+// upgrade raises, in JSON
+type SharedBumpItem struct {
+	Path           string `json:"path"`
+	ProjectVersion string `json:"project_version"`
+	OldVersion     string `json:"old_version,omitempty"`
+	NewVersion     string `json:"new_version"`
+}
+
+// CouplingItem represents how much of the project touches the dependency in JSON
+type CouplingItem struct {
+	Symbols         int `json:"symbols"`
+	Packages        int `json:"packages"`
+	ProjectPackages int `json:"project_packages"`
+	Files           int `json:"files"`
+	Lines           int `json:"lines"`
+}
+
+// AdapterItem represents the suggestion to wrap a widely used dependency in JSON
+type AdapterItem struct {
+	Threshold int              `json:"threshold"`
+	Sites     []SymbolUsesItem `json:"sites"`
 }
 
 // RemovedItem represents a removed symbol in JSON
 type RemovedItem struct {
-	Name   string     `json:"name"`
-	Type   string     `json:"type"`
-	UsedIn []Location `json:"used_in,omitempty"`
+	Name      string     `json:"name"`
+	Type      string     `json:"type"`
+	UsedIn    []Location `json:"used_in,omitempty"`
+	Unstable  bool       `json:"unstable,omitempty"`
+	Category  string     `json:"category"`
+	Severity  string     `json:"severity"`
+	Platforms []string   `json:"platforms,omitempty"`
+	DeadCode  bool       `json:"dead_code,omitempty"`
+	ViaModule string     `json:"via_module,omitempty"` // module a type alias re-exports the symbol from
+
+	ConvertedTo string `json:"converted_to,omitempty"` // method or function the symbol became
+	Rewrite     string `json:"rewrite,omitempty"`
 }
 
 // ChangedItem represents a changed signature in JSON
 type ChangedItem struct {
-	Name         string     `json:"name"`
-	OldSignature string     `json:"old_signature"`
-	NewSignature string     `json:"new_signature"`
-	UsedIn       []Location `json:"used_in,omitempty"`
+	Name           string     `json:"name"`
+	OldSignature   string     `json:"old_signature"`
+	NewSignature   string     `json:"new_signature"`
+	UsedIn         []Location `json:"used_in,omitempty"`
+	Unstable       bool       `json:"unstable,omitempty"`
+	PromotedFrom   string     `json:"promoted_from,omitempty"`
+	ViaModule      string     `json:"via_module,omitempty"`
+	BehaviorChange string     `json:"behavior_change,omitempty"`
+	Category       string     `json:"category"`
+	Severity       string     `json:"severity"`
+	Platforms      []string   `json:"platforms,omitempty"`
+	DeadCode       bool       `json:"dead_code,omitempty"`
+
+	ParamChanges   []ParamChangeItem   `json:"param_changes,omitempty"`
+	Instantiations []InstantiationItem `json:"instantiations,omitempty"`
+}
+
+// ParamChangeItem represents a parameter or result change in JSON
+type ParamChangeItem struct {
+	Kind        string `json:"kind"`
+	Result      bool   `json:"result,omitempty"`
+	Position    int    `json:"position"`
+	Name        string `json:"name,omitempty"`
+	OldType     string `json:"old_type,omitempty"`
+	NewType     string `json:"new_type,omitempty"`
+	Variadic    bool   `json:"variadic,omitempty"`
+	Description string `json:"description"`
+}
+
+// InstantiationItem represents a generic instantiation check in JSON
+type InstantiationItem struct {
+	TypeArgs   []string   `json:"type_args"`
+	Compatible bool       `json:"compatible"`
+	Error      string     `json:"error,omitempty"`
+	UsedIn     []Location `json:"used_in,omitempty"`
 }
 
 // InterfaceChangeItem represents interface changes in JSON
 type InterfaceChangeItem struct {
-	Name           string     `json:"name"`
-	AddedMethods   []string   `json:"added_methods,omitempty"`
-	RemovedMethods []string   `json:"removed_methods,omitempty"`
-	UsedIn         []Location `json:"used_in,omitempty"`
+	Name           string            `json:"name"`
+	AddedMethods   []string          `json:"added_methods,omitempty"`
+	AddedVia       map[string]string `json:"added_via,omitempty"` // method name -> embedded interface
+	RemovedMethods []string          `json:"removed_methods,omitempty"`
+	UsedIn         []Location        `json:"used_in,omitempty"`
+	Unstable       bool              `json:"unstable,omitempty"`
+	Category       string            `json:"category"`
+	Severity       string            `json:"severity"`
+	Platforms      []string          `json:"platforms,omitempty"`
+	DeadCode       bool              `json:"dead_code,omitempty"`
+	ViaModule      string            `json:"via_module,omitempty"`
+
+	Implementations []ImplementationItem `json:"implementations,omitempty"`
+}
+
+// ImplementationItem represents a project type whose satisfaction of a
+// changed interface changes, in JSON
+type ImplementationItem struct {
+	Type    string   `json:"type"`
+	Before  string   `json:"before"`
+	After   string   `json:"after,omitempty"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+// MovedItem represents a symbol that moved to another package in JSON
+type MovedItem struct {
+	Name       string     `json:"name"`
+	NewName    string     `json:"new_name"`
+	Type       string     `json:"type"`
+	OldPackage string     `json:"old_package"`
+	NewPackage string     `json:"new_package"`
+	UsedIn     []Location `json:"used_in,omitempty"`
+	Unstable   bool       `json:"unstable,omitempty"`
+	Category   string     `json:"category"`
+	Severity   string     `json:"severity"`
+	Platforms  []string   `json:"platforms,omitempty"`
+	DeadCode   bool       `json:"dead_code,omitempty"`
 }
 
 // AddedItem represents an added symbol in JSON
@@ -50,10 +339,128 @@ type AddedItem struct {
 	Type string `json:"type"`
 }
 
+// GeneratedItem represents the collapsed churn of a generated package in JSON
+type GeneratedItem struct {
+	Package string `json:"package"`
+	Changes int    `json:"changes"`
+	Used    int    `json:"used"`
+}
+
+// FloorItem represents a minimum version check in JSON
+type FloorItem struct {
+	Required  string `json:"required"`
+	Current   string `json:"current"`
+	Satisfied bool   `json:"satisfied"`
+	Gap       string `json:"gap,omitempty"`
+}
+
+// ReplacementItem represents a go.mod replace directive in JSON
+type ReplacementItem struct {
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+	Ignored bool   `json:"ignored,omitempty"`
+}
+
+// RequirementItem represents a module required by a new dependency in JSON
+type RequirementItem struct {
+	Path           string `json:"path"`
+	Version        string `json:"version"`
+	ProjectVersion string `json:"project_version,omitempty"`
+}
+
+// CopyItem represents a copy of the dependency inside the project in JSON
+type CopyItem struct {
+	Dir        string `json:"dir"`
+	Reason     string `json:"reason"`
+	References int    `json:"excluded_references"`
+}
+
+// DocChangeItem represents a doc comment change of a used symbol in JSON
+type DocChangeItem struct {
+	Name             string     `json:"name"`
+	Package          string     `json:"package,omitempty"`
+	Reasons          []string   `json:"reasons"`
+	RemovedSentences []string   `json:"removed_sentences,omitempty"`
+	AddedSentences   []string   `json:"added_sentences,omitempty"`
+	UsedIn           []Location `json:"used_in,omitempty"`
+}
+
+// ExampleItem represents a new-version usage example for a finding in JSON
+type ExampleItem struct {
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+	Dir    string `json:"dir"`
+	Code   string `json:"code"`
+	Output string `json:"output,omitempty"`
+}
+
+// HintItem represents an added symbol that may replace project code in JSON
+type HintItem struct {
+	Symbol   string   `json:"symbol"`
+	Package  string   `json:"package,omitempty"`
+	Replaces string   `json:"replaces"`
+	Location Location `json:"location"`
+	Reason   string   `json:"reason"`
+}
+
+// TODOItem represents a project TODO that added symbols may address in JSON
+type TODOItem struct {
+	Text     string   `json:"text"`
+	Location Location `json:"location"`
+	Symbols  []string `json:"symbols"`
+}
+
+// DeadFunctionItem represents unreachable project code holding the only uses
+// of findings in JSON
+type DeadFunctionItem struct {
+	Name     string   `json:"name"`
+	Package  string   `json:"package"`
+	Location Location `json:"location"`
+	Symbols  []string `json:"symbols"`
+}
+
+// ImportGraphItem represents the project packages importing the module in JSON
+type ImportGraphItem struct {
+	Packages       []GraphNodeItem `json:"packages"`
+	ModulePackages []string        `json:"module_packages"`
+}
+
+// GraphNodeItem represents a project package of the import graph in JSON
+type GraphNodeItem struct {
+	Package           string   `json:"package"`
+	Imports           []string `json:"imports"`
+	Direct            bool     `json:"direct,omitempty"`
+	AffectedLocations int      `json:"affected_locations"`
+	Severity          string   `json:"severity,omitempty"`
+}
+
+// DirectoryItem represents the size and findings of a project directory in JSON
+type DirectoryItem struct {
+	Dir               string `json:"dir"`
+	Files             int    `json:"files"`
+	AffectedLocations int    `json:"affected_locations"`
+}
+
+// WarningItem represents a non-fatal issue in JSON
+type WarningItem struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	File    string `json:"file,omitempty"`
+}
+
 // Location represents a source code location in JSON
 type Location struct {
-	File string `json:"file"`
-	Line int    `json:"line"`
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+	Kind        string `json:"kind,omitempty"`
+	Approximate bool   `json:"approximate,omitempty"`
+}
+
+// LoadErrorItem represents a project load error in JSON
+type LoadErrorItem struct {
+	Package  string `json:"package"`
+	Position string `json:"position,omitempty"`
+	Message  string `json:"message"`
 }
 
 // FormatJSON generates a JSON report
@@ -62,21 +469,84 @@ func FormatJSON(result *analyzer.Result) (string, error) {
 		Module:            result.Module,
 		OldVersion:        result.OldVersion,
 		NewVersion:        result.NewVersion,
+		RequiredVersion:   result.RequiredVersion,
+		NewDependency:     result.NewDependency,
+		ToolDependency:    result.ToolDependency,
+		MainOnly:          result.MainOnly,
 		Breaking:          result.HasBreakingChanges(),
-		BreakingCount:     len(result.Changes.Removed) + len(result.Changes.Changed) + len(result.Changes.InterfaceChanges),
+		StoppedEarly:      result.StoppedEarly,
+		BreakingCount:     result.Changes.BreakingCount(),
 		AffectedLocations: countAffectedLocations(result.Changes),
+		UnstableCount:     result.Changes.UnstableCount(),
+		Summary:           summarize(result),
+	}
+
+	if floor := result.Floor; floor != nil {
+		report.Floor = &FloorItem{
+			Required:  floor.Required,
+			Current:   floor.Current,
+			Satisfied: floor.Satisfied,
+			Gap:       floor.Gap,
+		}
+	}
+
+	for _, r := range result.Retractions {
+		report.Retractions = append(report.Retractions, RetractionItem{
+			Version:   r.Version,
+			Current:   r.Current,
+			Rationale: r.Rationale,
+		})
+	}
+
+	for _, d := range result.DuplicateMajors {
+		report.DuplicateMajors = append(report.DuplicateMajors, DuplicateMajorItem{
+			Module:    d.Module,
+			Version:   d.Version,
+			Importers: d.Importers,
+		})
+	}
+
+	if d := result.Deprecation; d != nil {
+		report.Deprecation = &DeprecationItem{
+			Message:   d.Message,
+			Successor: d.Successor,
+		}
+	}
+
+	if u := result.UsageOnly; u != nil {
+		report.UsageOnly = &UsageOnlyItem{Reason: u.Reason, Symbols: symbolUsesItems(u.Symbols)}
+	}
+
+	if rep := result.Replacement; rep != nil {
+		report.Replacement = &ReplacementItem{
+			Path:    rep.Path,
+			Version: rep.Version,
+			Ignored: rep.Ignored,
+		}
 	}
 
 	// Convert removed symbols
 	for _, removed := range result.Changes.Removed {
 		item := RemovedItem{
-			Name: removed.Name,
-			Type: removed.Type,
+			Name:      removed.Name,
+			Type:      removed.Type,
+			Unstable:  removed.Unstable,
+			Category:  removed.Category(),
+			Severity:  removed.Level(),
+			Platforms: removed.Platforms,
+			DeadCode:  removed.DeadCode,
+			ViaModule: removed.ViaModule,
+		}
+		if removed.Conversion != nil {
+			item.ConvertedTo = removed.Conversion.NewName
+			item.Rewrite = removed.Conversion.Rewrite
 		}
 		for _, loc := range removed.UsedIn {
 			item.UsedIn = append(item.UsedIn, Location{
-				File: loc.File,
-				Line: loc.Line,
+				File:        loc.File,
+				Line:        loc.Line,
+				Kind:        loc.Kind,
+				Approximate: loc.Approximate,
 			})
 		}
 		report.Removed = append(report.Removed, item)
@@ -85,16 +555,53 @@ func FormatJSON(result *analyzer.Result) (string, error) {
 	// Convert changed signatures
 	for _, changed := range result.Changes.Changed {
 		item := ChangedItem{
-			Name:         changed.Name,
-			OldSignature: changed.OldSignature,
-			NewSignature: changed.NewSignature,
+			Name:           changed.Name,
+			OldSignature:   changed.OldSignature,
+			NewSignature:   changed.NewSignature,
+			Unstable:       changed.Unstable,
+			PromotedFrom:   changed.PromotedFrom,
+			ViaModule:      changed.ViaModule,
+			BehaviorChange: changed.BehaviorChange,
+			Category:       changed.Category(),
+			Severity:       changed.Level(),
+			Platforms:      changed.Platforms,
+			DeadCode:       changed.DeadCode,
 		}
 		for _, loc := range changed.UsedIn {
 			item.UsedIn = append(item.UsedIn, Location{
-				File: loc.File,
-				Line: loc.Line,
+				File:        loc.File,
+				Line:        loc.Line,
+				Kind:        loc.Kind,
+				Approximate: loc.Approximate,
 			})
 		}
+		for _, pc := range changed.ParamChanges {
+			item.ParamChanges = append(item.ParamChanges, ParamChangeItem{
+				Kind:        pc.Kind,
+				Result:      pc.Result,
+				Position:    pc.Position,
+				Name:        pc.Name,
+				OldType:     pc.OldType,
+				NewType:     pc.NewType,
+				Variadic:    pc.Variadic,
+				Description: formatParamChange(pc),
+			})
+		}
+		for _, inst := range changed.Instantiations {
+			instItem := InstantiationItem{
+				TypeArgs:   inst.TypeArgs,
+				Compatible: inst.Compatible(),
+				Error:      inst.Error,
+			}
+			for _, loc := range inst.UsedIn {
+				instItem.UsedIn = append(instItem.UsedIn, Location{
+					File:        loc.File,
+					Line:        loc.Line,
+					Approximate: loc.Approximate,
+				})
+			}
+			item.Instantiations = append(item.Instantiations, instItem)
+		}
 		report.Changed = append(report.Changed, item)
 	}
 
@@ -103,17 +610,59 @@ func FormatJSON(result *analyzer.Result) (string, error) {
 		item := InterfaceChangeItem{
 			Name:           iface.Name,
 			AddedMethods:   iface.AddedMethods,
+			AddedVia:       iface.AddedVia,
 			RemovedMethods: iface.RemovedMethods,
+			Unstable:       iface.Unstable,
+			Category:       iface.Category(),
+			Severity:       iface.Level(),
+			Platforms:      iface.Platforms,
+			DeadCode:       iface.DeadCode,
+			ViaModule:      iface.ViaModule,
+		}
+		for _, impl := range iface.Implementations {
+			item.Implementations = append(item.Implementations, ImplementationItem{
+				Type:    impl.Type,
+				Before:  impl.Before,
+				After:   impl.After,
+				Missing: impl.Missing,
+			})
 		}
 		for _, loc := range iface.UsedIn {
 			item.UsedIn = append(item.UsedIn, Location{
-				File: loc.File,
-				Line: loc.Line,
+				File:        loc.File,
+				Line:        loc.Line,
+				Kind:        loc.Kind,
+				Approximate: loc.Approximate,
 			})
 		}
 		report.InterfaceChanges = append(report.InterfaceChanges, item)
 	}
 
+	// Convert moved symbols
+	for _, moved := range result.Changes.Moved {
+		item := MovedItem{
+			Name:       moved.Name,
+			NewName:    moved.NewName,
+			Type:       moved.Type,
+			OldPackage: moved.OldPackage,
+			NewPackage: moved.NewPackage,
+			Unstable:   moved.Unstable,
+			Category:   moved.Category(),
+			Severity:   moved.Level(),
+			Platforms:  moved.Platforms,
+			DeadCode:   moved.DeadCode,
+		}
+		for _, loc := range moved.UsedIn {
+			item.UsedIn = append(item.UsedIn, Location{
+				File:        loc.File,
+				Line:        loc.Line,
+				Kind:        loc.Kind,
+				Approximate: loc.Approximate,
+			})
+		}
+		report.Moved = append(report.Moved, item)
+	}
+
 	// Convert added symbols
 	for _, added := range result.Changes.Added {
 		report.Added = append(report.Added, AddedItem{
@@ -122,9 +671,275 @@ func FormatJSON(result *analyzer.Result) (string, error) {
 		})
 	}
 
+	// Convert generated package groups
+	for _, group := range result.Changes.Generated {
+		report.Generated = append(report.Generated, GeneratedItem{
+			Package: group.Package,
+			Changes: group.Changes,
+			Used:    group.Used,
+		})
+	}
+
 	// Add unused dependencies
 	report.UnusedDeps = result.UnusedDeps
 
+	// Convert doc comment changes
+	for _, change := range result.DocChanges {
+		item := DocChangeItem{
+			Name:             change.Name,
+			Package:          change.Package,
+			Reasons:          change.Reasons,
+			RemovedSentences: change.Removed,
+			AddedSentences:   change.Added,
+		}
+		for _, loc := range change.UsedIn {
+			item.UsedIn = append(item.UsedIn, Location{
+				File:        loc.File,
+				Line:        loc.Line,
+				Kind:        loc.Kind,
+				Approximate: loc.Approximate,
+			})
+		}
+		report.DocChanges = append(report.DocChanges, item)
+	}
+
+	// Convert load errors
+	for _, le := range result.LoadErrors {
+		report.LoadErrors = append(report.LoadErrors, LoadErrorItem{
+			Package:  le.Package,
+			Position: le.Position,
+			Message:  le.Message,
+		})
+	}
+
+	// Convert warnings
+	for _, w := range result.Warnings {
+		report.Warnings = append(report.Warnings, WarningItem{
+			Code:    w.Code,
+			Message: w.Message,
+			File:    w.File,
+		})
+	}
+
+	// Convert usage examples
+	for _, ex := range result.Examples {
+		report.Examples = append(report.Examples, ExampleItem{
+			Symbol: ex.Symbol,
+			Name:   ex.Name,
+			Dir:    ex.Dir,
+			Code:   ex.Code,
+			Output: ex.Output,
+		})
+	}
+
+	// Convert adoption hints
+	for _, hint := range result.Hints {
+		report.Hints = append(report.Hints, HintItem{
+			Symbol:   hint.Symbol,
+			Package:  hint.Package,
+			Replaces: hint.Replaces,
+			Location: Location{File: hint.Location.File, Line: hint.Location.Line},
+			Reason:   hint.Reason,
+		})
+	}
+
+	// Convert TODOs added symbols may address
+	for _, todo := range result.TODOs {
+		report.TODOs = append(report.TODOs, TODOItem{
+			Text:     todo.Text,
+			Location: Location{File: todo.Location.File, Line: todo.Location.Line},
+			Symbols:  todo.Symbols,
+		})
+	}
+
+	// Convert dead code
+	for _, fn := range result.DeadCode {
+		report.DeadCode = append(report.DeadCode, DeadFunctionItem{
+			Name:     fn.Name,
+			Package:  fn.Package,
+			Location: Location{File: fn.Location.File, Line: fn.Location.Line},
+			Symbols:  fn.Symbols,
+		})
+	}
+
+	// Convert import graph and directories, attributing findings by directory
+	byDir := findingsByDir(result)
+	if graph := result.ImportGraph; graph != nil {
+		report.ImportGraph = &ImportGraphItem{ModulePackages: graph.ModulePackages}
+		for _, node := range graph.Nodes {
+			f := byDir[node.Dir]
+			report.ImportGraph.Packages = append(report.ImportGraph.Packages, GraphNodeItem{
+				Package:           node.Package,
+				Imports:           node.Imports,
+				Direct:            node.Direct,
+				AffectedLocations: f.Count,
+				Severity:          f.Worst,
+			})
+		}
+	}
+	for _, d := range result.Directories {
+		report.Directories = append(report.Directories, DirectoryItem{
+			Dir:               d.Dir,
+			Files:             d.Files,
+			AffectedLocations: byDir[d.Dir].Count,
+		})
+	}
+
+	// Convert in-repo copies of the dependency
+	for _, c := range result.Copies {
+		report.Copies = append(report.Copies, CopyItem{
+			Dir:        c.Dir,
+			Reason:     c.Reason,
+			References: c.References,
+		})
+	}
+
+	// Convert requirements of a new dependency
+	for _, req := range result.Requirements {
+		report.Requirements = append(report.Requirements, RequirementItem{
+			Path:           req.Path,
+			Version:        req.Version,
+			ProjectVersion: req.ProjectVersion,
+		})
+	}
+
+	if risk := result.Risk; risk != nil {
+		report.Risk = &RiskItem{
+			Score:        risk.Score,
+			Level:        risk.Level,
+			Audits:       risk.Audits,
+			BreakingRate: risk.BreakingRate,
+			APISurface:   risk.APISurface,
+			UsedSymbols:  risk.UsedSymbols,
+			UsageSites:   risk.UsageSites,
+		}
+	}
+
+	if fp := result.Footprint; fp != nil {
+		report.Footprint = &FootprintItem{
+			OldSizeBytes:   fp.OldSize,
+			NewSizeBytes:   fp.NewSize,
+			OldPackages:    fp.OldPackages,
+			NewPackages:    fp.NewPackages,
+			PackagesAdded:  fp.PackagesAdded,
+			ModulesAdded:   fp.ModulesAdded,
+			ModulesRemoved: fp.ModulesRemoved,
+		}
+		for _, bump := range fp.SharedBumps {
+			report.Footprint.SharedBumps = append(report.Footprint.SharedBumps, SharedBumpItem{
+				Path:           bump.Path,
+				ProjectVersion: bump.ProjectVersion,
+				OldVersion:     bump.OldVersion,
+				NewVersion:     bump.NewVersion,
+			})
+		}
+	}
+
+	if c := result.Coupling; c != nil {
+		report.Coupling = &CouplingItem{
+			Symbols:         c.Symbols,
+			Packages:        c.Packages,
+			ProjectPackages: c.ProjectPackages,
+			Files:           c.Files,
+			Lines:           c.Lines,
+		}
+	}
+
+	if adapter := result.Adapter; adapter != nil {
+		report.Adapter = &AdapterItem{Threshold: adapter.Threshold, Sites: symbolUsesItems(adapter.Sites)}
+	}
+
+	if impact := result.BinaryImpact; impact != nil {
+		report.BinaryImpact = &BinaryImpactItem{
+			Package:      impact.Package,
+			OldSizeBytes: impact.OldSize,
+			NewSizeBytes: impact.NewSize,
+			DeltaBytes:   impact.NewSize - impact.OldSize,
+			Error:        impact.Error,
+		}
+	}
+
+	for _, bench := range result.Benchmarks {
+		report.Benchmarks = append(report.Benchmarks, BenchmarkItem{
+			Name:         bench.Name,
+			OldNsPerOp:   bench.OldNsPerOp,
+			NewNsPerOp:   bench.NewNsPerOp,
+			DeltaPercent: bench.DeltaPercent,
+			Regression:   bench.Regression,
+		})
+	}
+
+	for _, failure := range result.TestFailures {
+		report.TestFailures = append(report.TestFailures, TestFailureItem{
+			Package: failure.Package,
+			Test:    failure.Test,
+		})
+	}
+
+	if mc := result.ModuleChanges; mc != nil {
+		report.ModuleChanges = &ModuleChangesItem{
+			OldGoVersion: mc.OldGoVersion,
+			NewGoVersion: mc.NewGoVersion,
+			Added:        mc.Added,
+			Removed:      mc.Removed,
+			Upgraded:     mc.Upgraded,
+		}
+	}
+
+	if shims := result.Shims; shims != nil {
+		report.Shims = &ShimsItem{
+			DryRun:    shims.DryRun,
+			Path:      shims.Path,
+			Functions: shims.Functions,
+			Skipped:   shims.Skipped,
+		}
+		if shims.Diff.Diff != "" {
+			report.Shims.Diff = &FileDiffItem{File: shims.Diff.File, Diff: shims.Diff.Diff}
+		}
+	}
+
+	if fixes := result.Fixes; fixes != nil {
+		report.Fixes = &FixesItem{
+			DryRun:  fixes.DryRun,
+			Files:   append([]string{}, fixes.Files...),
+			Fixed:   fixSiteItems(fixes.Fixed),
+			Skipped: fixSiteItems(fixes.Skipped),
+		}
+		for _, diff := range fixes.Diffs {
+			report.Fixes.Diffs = append(report.Fixes.Diffs, FileDiffItem{File: diff.File, Diff: diff.Diff})
+		}
+		report.Fixes.Tested = fixes.Tested
+		for _, failure := range fixes.TestFailures {
+			report.Fixes.TestFailures = append(report.Fixes.TestFailures, TestFailureItem{Package: failure.Package, Test: failure.Test})
+		}
+	}
+
+	if s := result.SumDB; s != nil {
+		report.SumDB = &SumDBItem{
+			Version:  s.Version,
+			Sum:      s.Sum,
+			Verified: s.Verified(),
+			Database: s.Database,
+			Bypass:   s.Bypass,
+		}
+	}
+
+	if p := result.Provenance; p != nil {
+		report.Provenance = &ProvenanceItem{
+			ToolVersion: p.ToolVersion,
+			GoVersion:   p.GoVersion,
+			GOFLAGS:     p.GOFLAGS,
+			GOOS:        p.GOOS,
+			GOARCH:      p.GOARCH,
+			Module:      p.Module,
+			OldVersion:  p.OldVersion,
+			NewVersion:  p.NewVersion,
+			OldSum:      p.OldSum,
+			NewSum:      p.NewSum,
+			Options:     p.Options,
+		}
+	}
+
 	// Marshal to JSON with indentation
 	data, err := json.MarshalIndent(report, "", "  ")
 	if err != nil {
@@ -133,3 +948,74 @@ func FormatJSON(result *analyzer.Result) (string, error) {
 
 	return string(data) + "\n", nil
 }
+
+// ParseProvenance reads the provenance block of a JSON report. data may be a
+// whole report or just its provenance object.
+func ParseProvenance(data []byte) (*analyzer.Provenance, error) {
+	var wrapper struct {
+		Provenance *ProvenanceItem `json:"provenance"`
+	}
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, err
+	}
+	item := wrapper.Provenance
+	if item == nil {
+		item = &ProvenanceItem{}
+		if err := json.Unmarshal(data, item); err != nil {
+			return nil, err
+		}
+	}
+	// A report without a provenance block shares module and new_version
+	// with it, but never has a new_sum
+	if item.Module == "" || item.NewVersion == "" || item.NewSum == "" {
+		return nil, fmt.Errorf("no provenance found: module, new_version and new_sum are required")
+	}
+
+	return &analyzer.Provenance{
+		ToolVersion: item.ToolVersion,
+		GoVersion:   item.GoVersion,
+		GOFLAGS:     item.GOFLAGS,
+		GOOS:        item.GOOS,
+		GOARCH:      item.GOARCH,
+		Module:      item.Module,
+		OldVersion:  item.OldVersion,
+		NewVersion:  item.NewVersion,
+		OldSum:      item.OldSum,
+		NewSum:      item.NewSum,
+		Options:     item.Options,
+	}, nil
+}
+
+// symbolUsesItems converts symbols and their locations to JSON items
+func symbolUsesItems(symbols []analyzer.SymbolUses) []SymbolUsesItem {
+	items := []SymbolUsesItem{}
+	for _, s := range symbols {
+		item := SymbolUsesItem{Name: s.Name, UsedIn: []Location{}}
+		for _, loc := range s.UsedIn {
+			item.UsedIn = append(item.UsedIn, Location{
+				File:        loc.File,
+				Line:        loc.Line,
+				Kind:        loc.Kind,
+				Approximate: loc.Approximate,
+			})
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// fixSiteItems converts call sites considered by -fix to their JSON form
+func fixSiteItems(sites []analyzer.FixSite) []FixSiteItem {
+	items := []FixSiteItem{}
+	for _, site := range sites {
+		items = append(items, FixSiteItem{
+			Symbol: site.Symbol,
+			File:   site.Location.File,
+			Line:   site.Location.Line,
+			Column: site.Location.Column,
+			Fix:    site.Fix,
+			Reason: site.Reason,
+		})
+	}
+	return items
+}
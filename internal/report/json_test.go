@@ -2,6 +2,7 @@ package report
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/devblac/go-semver-audit/internal/analyzer"
@@ -189,3 +190,198 @@ func TestJSONReportStructure(t *testing.T) {
 		t.Errorf("Removed.UsedIn[0].Line = %d, want 10", removed.UsedIn[0].Line)
 	}
 }
+
+func TestParseProvenance_RoundTrip(t *testing.T) {
+	want := &analyzer.Provenance{
+		ToolVersion: "1.2.0",
+		GoVersion:   "go1.21.13",
+		GOFLAGS:     "-mod=mod",
+		GOOS:        "linux",
+		GOARCH:      "amd64",
+		Module:      "github.com/test/module",
+		OldVersion:  "v1.0.0",
+		NewVersion:  "v1.1.0",
+		OldSum:      "h1:old=",
+		NewSum:      "h1:new=",
+		Options:     analyzer.Options{IncludeTestPackages: true, Shards: 2},
+	}
+	result := &analyzer.Result{
+		Module:     want.Module,
+		OldVersion: want.OldVersion,
+		NewVersion: want.NewVersion,
+		Changes:    &analyzer.Diff{},
+		Provenance: want,
+	}
+
+	output, err := FormatJSON(result)
+	if err != nil {
+		t.Fatalf("FormatJSON() error = %v", err)
+	}
+
+	got, err := ParseProvenance([]byte(output))
+	if err != nil {
+		t.Fatalf("ParseProvenance() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseProvenance() = %+v, want %+v", got, want)
+	}
+
+	// A bare provenance block parses the same way
+	var report JSONReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	block, _ := json.Marshal(report.Provenance)
+	if got, err := ParseProvenance(block); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseProvenance(block) = %+v, %v, want %+v", got, err, want)
+	}
+
+	// A report without provenance is rejected
+	result.Provenance = nil
+	output, _ = FormatJSON(result)
+	if _, err := ParseProvenance([]byte(output)); err == nil {
+		t.Error("ParseProvenance() without provenance should fail")
+	}
+}
+
+func TestFormatJSON_DocChanges(t *testing.T) {
+	result := &analyzer.Result{
+		Module:     "github.com/test/module",
+		OldVersion: "v1.0.0",
+		NewVersion: "v1.1.0",
+		Changes:    &analyzer.Diff{},
+		DocChanges: []analyzer.DocChange{
+			{
+				Name:    "Open",
+				Package: "github.com/test/module",
+				Reasons: []string{analyzer.DocDeprecated},
+				Added:   []string{"Deprecated: use OpenFile."},
+				UsedIn:  []analyzer.Location{{File: "main.go", Line: 7}},
+			},
+		},
+	}
+
+	output, err := FormatJSON(result)
+	if err != nil {
+		t.Fatalf("FormatJSON() error = %v", err)
+	}
+
+	var report JSONReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	if len(report.DocChanges) != 1 {
+		t.Fatalf("Expected 1 doc change, got %d", len(report.DocChanges))
+	}
+	change := report.DocChanges[0]
+	if change.Name != "Open" || change.Reasons[0] != analyzer.DocDeprecated || change.AddedSentences[0] != "Deprecated: use OpenFile." {
+		t.Errorf("DocChanges[0] = %+v", change)
+	}
+	if len(change.UsedIn) != 1 || change.UsedIn[0].Line != 7 {
+		t.Errorf("DocChanges[0].UsedIn = %+v, want main.go:7", change.UsedIn)
+	}
+}
+
+func TestFormatJSON_Summary(t *testing.T) {
+	result := &analyzer.Result{
+		Module:     "github.com/test/module",
+		OldVersion: "v1.0.0",
+		NewVersion: "v2.0.0",
+		Changes: &analyzer.Diff{
+			Removed: []analyzer.RemovedSymbol{
+				{Name: "Open", Type: "function"},
+				{Name: "Fast", Type: "function", Unstable: true},
+			},
+			Changed: []analyzer.ChangedSignature{{Name: "Parse", Severity: analyzer.SeverityInfo}},
+			Moved:   []analyzer.MovedSymbol{{Name: "Dial"}},
+		},
+		DocChanges: []analyzer.DocChange{
+			{Name: "Close", Reasons: []string{analyzer.DocDeprecated}},
+			{Name: "Read", Reasons: []string{"error wording changed"}},
+		},
+		Warnings: []analyzer.Warning{{Code: analyzer.WarnUnusedDeps, Message: "failed"}},
+	}
+
+	output, err := FormatJSON(result)
+	if err != nil {
+		t.Fatalf("FormatJSON() error = %v", err)
+	}
+	var report JSONReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	want := SummaryItem{Removed: 2, Changed: 1, Moved: 1, Deprecated: 1, Warnings: 1, Errors: 2, WarningFindings: 1, InfoFindings: 1}
+	if report.Summary != want {
+		t.Errorf("Summary = %+v, want %+v", report.Summary, want)
+	}
+}
+
+func TestFormatJSON_ModuleStatus(t *testing.T) {
+	result := &analyzer.Result{
+		Module:     "github.com/test/module",
+		OldVersion: "v1.2.0",
+		NewVersion: "v1.4.0",
+		Changes:    &analyzer.Diff{},
+		Retractions: []analyzer.Retraction{
+			{Version: "v1.4.0", Rationale: "Publishes a broken Close."},
+			{Version: "v1.2.0", Current: true},
+		},
+		Deprecation: &analyzer.Deprecation{Message: "use example.com/v2 instead.", Successor: "example.com/v2"},
+		SumDB:       &analyzer.SumDBStatus{Version: "v1.4.0", Sum: "h1:abc=", Database: "sum.golang.org"},
+		Coupling:    &analyzer.Coupling{Symbols: 4, Packages: 2, ProjectPackages: 3, Files: 5, Lines: 9},
+	}
+
+	output, err := FormatJSON(result)
+	if err != nil {
+		t.Fatalf("FormatJSON() error = %v", err)
+	}
+	var report JSONReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	want := []RetractionItem{
+		{Version: "v1.4.0", Rationale: "Publishes a broken Close."},
+		{Version: "v1.2.0", Current: true},
+	}
+	if !reflect.DeepEqual(report.Retractions, want) {
+		t.Errorf("Retractions = %+v, want %+v", report.Retractions, want)
+	}
+	if d := report.Deprecation; d == nil || d.Message != "use example.com/v2 instead." || d.Successor != "example.com/v2" {
+		t.Errorf("Deprecation = %+v", d)
+	}
+	if want := (&SumDBItem{Version: "v1.4.0", Sum: "h1:abc=", Verified: true, Database: "sum.golang.org"}); !reflect.DeepEqual(report.SumDB, want) {
+		t.Errorf("SumDB = %+v, want %+v", report.SumDB, want)
+	}
+	if want := (&CouplingItem{Symbols: 4, Packages: 2, ProjectPackages: 3, Files: 5, Lines: 9}); !reflect.DeepEqual(report.Coupling, want) {
+		t.Errorf("Coupling = %+v, want %+v", report.Coupling, want)
+	}
+}
+
+func TestFormatJSON_UsageOnly(t *testing.T) {
+	result := &analyzer.Result{
+		Module:     "github.com/test/module",
+		OldVersion: "v1.2.0",
+		NewVersion: "v1.4.0",
+		Changes:    &analyzer.Diff{},
+		UsageOnly: &analyzer.UsageOnly{
+			Reason:  "failed to load new API: 410 Gone",
+			Symbols: []analyzer.SymbolUses{{Name: "Parse", UsedIn: []analyzer.Location{{File: "main.go", Line: 12, Kind: "call"}}}},
+		},
+	}
+
+	output, err := FormatJSON(result)
+	if err != nil {
+		t.Fatalf("FormatJSON() error = %v", err)
+	}
+	var report JSONReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	want := &UsageOnlyItem{
+		Reason:  "failed to load new API: 410 Gone",
+		Symbols: []SymbolUsesItem{{Name: "Parse", UsedIn: []Location{{File: "main.go", Line: 12, Kind: "call"}}}},
+	}
+	if !reflect.DeepEqual(report.UsageOnly, want) {
+		t.Errorf("UsageOnly = %+v, want %+v", report.UsageOnly, want)
+	}
+}
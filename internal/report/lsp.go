@@ -0,0 +1,185 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// LSP diagnostic severities
+const (
+	lspSeverityError       = 1
+	lspSeverityWarning     = 2
+	lspSeverityInformation = 3
+)
+
+// lspSeverities maps finding severities onto LSP diagnostic severities
+var lspSeverities = map[string]int{
+	analyzer.SeverityError:   lspSeverityError,
+	analyzer.SeverityWarning: lspSeverityWarning,
+	analyzer.SeverityInfo:    lspSeverityInformation,
+}
+
+// lspCodes maps finding categories onto diagnostic codes
+var lspCodes = map[string]string{
+	analyzer.CategoryRemoved:                "removed",
+	analyzer.CategoryMoved:                  "moved",
+	analyzer.CategorySignatureChanged:       "changed-signature",
+	analyzer.CategorySignatureParamRename:   "changed-signature",
+	analyzer.CategoryInterfaceAddedMethod:   "interface-changed",
+	analyzer.CategoryInterfaceRemovedMethod: "interface-changed",
+}
+
+// lspSource identifies our diagnostics in the editor
+const lspSource = "go-semver-audit"
+
+// LSPPublishDiagnostics mirrors the params of an LSP textDocument/publishDiagnostics
+// notification, one per affected file
+type LSPPublishDiagnostics struct {
+	URI         string          `json:"uri"`
+	Diagnostics []LSPDiagnostic `json:"diagnostics"`
+}
+
+// LSPDiagnostic mirrors the LSP Diagnostic structure
+type LSPDiagnostic struct {
+	Range    LSPRange `json:"range"`
+	Severity int      `json:"severity"`
+	Code     string   `json:"code"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+// LSPRange is a zero-based range in a document
+type LSPRange struct {
+	Start LSPPosition `json:"start"`
+	End   LSPPosition `json:"end"`
+}
+
+// LSPPosition is a zero-based line and character offset
+type LSPPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// FormatLSP generates diagnostics in the shape editors consume from
+// textDocument/publishDiagnostics, so extensions can underline every use
+// that breaks on upgrade
+func FormatLSP(result *analyzer.Result) (string, error) {
+	byFile := make(map[string][]LSPDiagnostic)
+	visitFindings(result, func(locations []analyzer.Location, name, category, level, message string) {
+		for _, loc := range locations {
+			byFile[loc.File] = append(byFile[loc.File], LSPDiagnostic{
+				Range:    lspRange(loc, name),
+				Severity: lspSeverities[level],
+				Code:     lspCodes[category],
+				Source:   lspSource,
+				Message:  message,
+			})
+		}
+	})
+
+	// Warnings have no usage site, so they are shown on the file they concern
+	for _, w := range result.Warnings {
+		if w.File == "" {
+			continue
+		}
+		byFile[w.File] = append(byFile[w.File], LSPDiagnostic{
+			Range:    LSPRange{End: LSPPosition{Line: 1}},
+			Severity: lspSeverityWarning,
+			Code:     w.Code,
+			Source:   lspSource,
+			Message:  w.Message,
+		})
+	}
+
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	params := make([]LSPPublishDiagnostics, 0, len(files))
+	for _, file := range files {
+		diags := byFile[file]
+		sort.SliceStable(diags, func(i, j int) bool {
+			if diags[i].Range.Start.Line != diags[j].Range.Start.Line {
+				return diags[i].Range.Start.Line < diags[j].Range.Start.Line
+			}
+			return diags[i].Range.Start.Character < diags[j].Range.Start.Character
+		})
+		params = append(params, LSPPublishDiagnostics{URI: fileURI(file), Diagnostics: diags})
+	}
+
+	data, err := json.MarshalIndent(params, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// lspRange covers the identifier at loc, or the whole line when the column is unknown
+func lspRange(loc analyzer.Location, name string) LSPRange {
+	line := loc.Line - 1
+	if line < 0 {
+		line = 0
+	}
+	if loc.Column == 0 {
+		return LSPRange{Start: LSPPosition{Line: line}, End: LSPPosition{Line: line + 1}}
+	}
+
+	// Methods are reported as Type.Method, but only the method name is at loc
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	start := loc.Column - 1
+	return LSPRange{
+		Start: LSPPosition{Line: line, Character: start},
+		End:   LSPPosition{Line: line, Character: start + len(name)},
+	}
+}
+
+// visitFindings calls visit with the uses, symbol name, category, severity,
+// and a one-line message of every breaking change in result
+func visitFindings(result *analyzer.Result, visit func(locations []analyzer.Location, name, category, level, message string)) {
+	changes := result.Changes
+	for _, removed := range changes.Removed {
+		visit(removed.UsedIn, removed.Name, removed.Category(), removed.Level(),
+			fmt.Sprintf("%s (%s) is removed in %s %s", removed.Name, removed.Type, result.Module, result.NewVersion)+platformTag(removed.Platforms))
+	}
+	for _, moved := range changes.Moved {
+		visit(moved.UsedIn, moved.Name, moved.Category(), moved.Level(),
+			fmt.Sprintf("%s moves in %s %s: import %s from %s", moved.Name, result.Module, result.NewVersion, moved.NewName, moved.NewPackage)+platformTag(moved.Platforms))
+	}
+	for _, changed := range changes.Changed {
+		visit(changed.UsedIn, changed.Name, changed.Category(), changed.Level(),
+			fmt.Sprintf("%s changes signature in %s %s: %s -> %s", changed.Name, result.Module, result.NewVersion, changed.OldSignature, changed.NewSignature)+platformTag(changed.Platforms))
+	}
+	for _, iface := range changes.InterfaceChanges {
+		var parts []string
+		if len(iface.AddedMethods) > 0 {
+			parts = append(parts, "adds "+strings.Join(iface.AddedMethods, ", "))
+		}
+		if len(iface.RemovedMethods) > 0 {
+			parts = append(parts, "removes "+strings.Join(iface.RemovedMethods, ", "))
+		}
+		visit(iface.UsedIn, iface.Name, iface.Category(), iface.Level(),
+			fmt.Sprintf("interface %s changes in %s %s: %s", iface.Name, result.Module, result.NewVersion, strings.Join(parts, "; "))+platformTag(iface.Platforms))
+	}
+}
+
+// fileURI converts a file path to a file:// URI
+func fileURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // Windows drive letters
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
@@ -0,0 +1,113 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestFormatLSP(t *testing.T) {
+	result := &analyzer.Result{
+		Module:     "github.com/example/lib",
+		OldVersion: "v1.0.0",
+		NewVersion: "v2.0.0",
+		Changes: &analyzer.Diff{
+			Removed: []analyzer.RemovedSymbol{
+				{Name: "OldFunc", Type: "function", UsedIn: []analyzer.Location{{File: "/src/app/main.go", Line: 12, Column: 6}}},
+			},
+			Changed: []analyzer.ChangedSignature{
+				{
+					Name:         "Client.Do",
+					OldSignature: "func() error",
+					NewSignature: "func(ctx context.Context) error",
+					UsedIn:       []analyzer.Location{{File: "/src/app/main.go", Line: 3, Column: 4}},
+					Unstable:     true,
+				},
+			},
+			InterfaceChanges: []analyzer.InterfaceChange{
+				{Name: "Handler", AddedMethods: []string{"Close() error"}, UsedIn: []analyzer.Location{{File: "/src/app/handler.go", Line: 7}}},
+			},
+		},
+	}
+
+	output, err := FormatLSP(result)
+	if err != nil {
+		t.Fatalf("FormatLSP() error = %v", err)
+	}
+
+	var params []LSPPublishDiagnostics
+	if err := json.Unmarshal([]byte(output), &params); err != nil {
+		t.Fatalf("FormatLSP() produced invalid JSON: %v", err)
+	}
+	if len(params) != 2 {
+		t.Fatalf("FormatLSP() files = %d, want 2", len(params))
+	}
+	if params[0].URI != "file:///src/app/handler.go" || params[1].URI != "file:///src/app/main.go" {
+		t.Fatalf("FormatLSP() URIs = %q, %q", params[0].URI, params[1].URI)
+	}
+
+	// A missing column covers the whole line
+	handler := params[0].Diagnostics[0]
+	if handler.Range != (LSPRange{Start: LSPPosition{Line: 6}, End: LSPPosition{Line: 7}}) {
+		t.Errorf("handler range = %+v", handler.Range)
+	}
+	if !strings.Contains(handler.Message, "adds Close() error") || handler.Code != "interface-changed" {
+		t.Errorf("handler diagnostic = %+v", handler)
+	}
+
+	main := params[1].Diagnostics
+	if len(main) != 2 {
+		t.Fatalf("main.go diagnostics = %d, want 2", len(main))
+	}
+	// Sorted by line: the method call on line 3 comes first and spans "Do"
+	if main[0].Range != (LSPRange{Start: LSPPosition{Line: 2, Character: 3}, End: LSPPosition{Line: 2, Character: 5}}) {
+		t.Errorf("method range = %+v", main[0].Range)
+	}
+	if main[0].Severity != lspSeverityWarning {
+		t.Errorf("unstable finding severity = %d, want warning", main[0].Severity)
+	}
+	if main[1].Severity != lspSeverityError || main[1].Source != lspSource {
+		t.Errorf("removed diagnostic = %+v", main[1])
+	}
+	if main[1].Range.End.Character-main[1].Range.Start.Character != len("OldFunc") {
+		t.Errorf("removed range = %+v", main[1].Range)
+	}
+}
+
+func TestFormatLSPNoFindings(t *testing.T) {
+	output, err := FormatLSP(&analyzer.Result{Changes: &analyzer.Diff{}})
+	if err != nil {
+		t.Fatalf("FormatLSP() error = %v", err)
+	}
+	if strings.TrimSpace(output) != "[]" {
+		t.Fatalf("FormatLSP() = %q, want empty array", output)
+	}
+}
+
+func TestFormatLSPWarnings(t *testing.T) {
+	result := &analyzer.Result{
+		Changes: &analyzer.Diff{},
+		Warnings: []analyzer.Warning{
+			{Code: analyzer.WarnSkippedPackages, Message: "1 package(s) skipped", File: "/src/app/go.mod"},
+			{Code: analyzer.WarnUnusedDeps, Message: "failed to detect unused dependencies"},
+		},
+	}
+
+	output, err := FormatLSP(result)
+	if err != nil {
+		t.Fatalf("FormatLSP() error = %v", err)
+	}
+	var params []LSPPublishDiagnostics
+	if err := json.Unmarshal([]byte(output), &params); err != nil {
+		t.Fatalf("FormatLSP() produced invalid JSON: %v", err)
+	}
+	if len(params) != 1 || params[0].URI != "file:///src/app/go.mod" {
+		t.Fatalf("FormatLSP() = %+v, want one go.mod entry", params)
+	}
+	diag := params[0].Diagnostics[0]
+	if diag.Code != analyzer.WarnSkippedPackages || diag.Severity != lspSeverityWarning {
+		t.Errorf("warning diagnostic = %+v", diag)
+	}
+}
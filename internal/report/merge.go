@@ -0,0 +1,49 @@
+package report
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// MergedReport combines the JSON reports of several audits, such as the
+// upgrades of several modules or the same upgrade across several repos
+type MergedReport struct {
+	Breaking          bool           `json:"breaking"`
+	BreakingCount     int            `json:"breaking_count"`
+	AffectedLocations int            `json:"affected_locations"`
+	Modules           []string       `json:"modules"`
+	Sources           []MergedSource `json:"sources"`
+}
+
+// MergedSource is one audit of a merged report, attributed to the report it
+// was read from
+type MergedSource struct {
+	Source string `json:"source"`
+	JSONReport
+}
+
+// FormatMerged merges the reports in the order given and totals their
+// breaking changes
+func FormatMerged(sources []MergedSource) (string, error) {
+	merged := MergedReport{Modules: []string{}, Sources: sources}
+	if merged.Sources == nil {
+		merged.Sources = []MergedSource{}
+	}
+	seen := make(map[string]bool)
+	for _, s := range sources {
+		merged.Breaking = merged.Breaking || s.Breaking
+		merged.BreakingCount += s.BreakingCount
+		merged.AffectedLocations += s.AffectedLocations
+		if s.Module != "" && !seen[s.Module] {
+			seen[s.Module] = true
+			merged.Modules = append(merged.Modules, s.Module)
+		}
+	}
+	sort.Strings(merged.Modules)
+
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
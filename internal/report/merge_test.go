@@ -0,0 +1,40 @@
+package report
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFormatMerged(t *testing.T) {
+	sources := []MergedSource{
+		{Source: "api/report.json", JSONReport: JSONReport{Module: "example.com/lib", Breaking: true, BreakingCount: 2, AffectedLocations: 5}},
+		{Source: "web/report.json", JSONReport: JSONReport{Module: "example.com/lib", BreakingCount: 0}},
+		{Source: "web/other.json", JSONReport: JSONReport{Module: "example.com/aaa", Breaking: true, BreakingCount: 1, AffectedLocations: 1}},
+	}
+	out, err := FormatMerged(sources)
+	if err != nil {
+		t.Fatalf("FormatMerged() error = %v", err)
+	}
+	var merged MergedReport
+	if err := json.Unmarshal([]byte(out), &merged); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if !merged.Breaking || merged.BreakingCount != 3 || merged.AffectedLocations != 6 {
+		t.Errorf("totals = %+v", merged)
+	}
+	if want := []string{"example.com/aaa", "example.com/lib"}; !reflect.DeepEqual(merged.Modules, want) {
+		t.Errorf("modules = %v, want %v", merged.Modules, want)
+	}
+	if !reflect.DeepEqual(merged.Sources, sources) {
+		t.Errorf("sources = %+v, want %+v", merged.Sources, sources)
+	}
+
+	empty, err := FormatMerged(nil)
+	if err != nil {
+		t.Fatalf("FormatMerged(nil) error = %v", err)
+	}
+	if want := "{\n  \"breaking\": false,\n  \"breaking_count\": 0,\n  \"affected_locations\": 0,\n  \"modules\": [],\n  \"sources\": []\n}\n"; empty != want {
+		t.Errorf("empty merge = %q, want %q", empty, want)
+	}
+}
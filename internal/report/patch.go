@@ -0,0 +1,30 @@
+package report
+
+import (
+	"strings"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// FormatPatch joins the diffs of the files edited by -fix, and the shim file
+// created by -shims, into one patch, with paths relative to the project so
+// git apply can run from its root. Results without edits yield an empty patch.
+func FormatPatch(result *analyzer.Result) string {
+	var b strings.Builder
+	for _, diff := range PatchDiffs(result) {
+		b.WriteString(diff.Diff)
+	}
+	return b.String()
+}
+
+// PatchDiffs lists the file diffs FormatPatch joins
+func PatchDiffs(result *analyzer.Result) []analyzer.FileDiff {
+	var diffs []analyzer.FileDiff
+	if result.Shims != nil && result.Shims.Diff.Diff != "" {
+		diffs = append(diffs, result.Shims.Diff)
+	}
+	if result.Fixes != nil {
+		diffs = append(diffs, result.Fixes.Diffs...)
+	}
+	return diffs
+}
@@ -0,0 +1,27 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestFormatPatch(t *testing.T) {
+	if got := FormatPatch(&analyzer.Result{}); got != "" {
+		t.Errorf("FormatPatch() without fixes = %q, want empty", got)
+	}
+
+	result := &analyzer.Result{Fixes: &analyzer.FixReport{Diffs: []analyzer.FileDiff{
+		{File: "a.go", Diff: "--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-x\n+y\n"},
+		{File: "b/b.go", Diff: "--- a/b/b.go\n+++ b/b/b.go\n@@ -2 +2 @@\n-z\n+w\n"},
+	}}}
+	want := "--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-x\n+y\n--- a/b/b.go\n+++ b/b/b.go\n@@ -2 +2 @@\n-z\n+w\n"
+	if got := FormatPatch(result); got != want {
+		t.Errorf("FormatPatch() =\n%s\nwant:\n%s", got, want)
+	}
+
+	result.Shims = &analyzer.ShimFile{DryRun: true, Diff: analyzer.FileDiff{File: "compat/semver_audit_shims.go", Diff: "--- /dev/null\n+++ b/compat/semver_audit_shims.go\n@@ -0,0 +1 @@\n+package compat\n"}}
+	if got := FormatPatch(result); got != "--- /dev/null\n+++ b/compat/semver_audit_shims.go\n@@ -0,0 +1 @@\n+package compat\n"+want {
+		t.Errorf("FormatPatch() with shims =\n%s\nwant the new shim file first", got)
+	}
+}
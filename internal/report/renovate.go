@@ -0,0 +1,104 @@
+package report
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// RenovateOptions tunes how audit history maps onto Renovate rules
+type RenovateOptions struct {
+	// MinAudits is how many clean audits a dependency needs before its patch
+	// upgrades are automerged
+	MinAudits int
+	// BreakRatio is the share of breaking audits at or above which upgrades
+	// of a dependency require approval
+	BreakRatio float64
+}
+
+// DefaultRenovateOptions are used by the renovate-config subcommand
+var DefaultRenovateOptions = RenovateOptions{MinAudits: 3, BreakRatio: 0.5}
+
+// RenovateConfig is the part of a Renovate configuration we generate
+type RenovateConfig struct {
+	PackageRules []RenovatePackageRule `json:"packageRules"`
+}
+
+// RenovatePackageRule mirrors a Renovate packageRules entry
+type RenovatePackageRule struct {
+	Description                 string   `json:"description"`
+	MatchManagers               []string `json:"matchManagers"`
+	MatchPackageNames           []string `json:"matchPackageNames"`
+	MatchUpdateTypes            []string `json:"matchUpdateTypes,omitempty"`
+	Automerge                   *bool    `json:"automerge,omitempty"`
+	DependencyDashboardApproval bool     `json:"dependencyDashboardApproval,omitempty"`
+	Labels                      []string `json:"labels,omitempty"`
+}
+
+// auditHistory counts the audits of one module
+type auditHistory struct {
+	audits   int
+	breaking int
+}
+
+// FormatRenovateConfig derives Renovate packageRules from past audit reports:
+// patch upgrades of dependencies that always audited clean are automerged,
+// and dependencies that break often need approval from the dependency dashboard.
+// Usage-only reports could not diff the API, so they count neither way.
+func FormatRenovateConfig(reports []JSONReport, opts RenovateOptions) (string, error) {
+	history := make(map[string]*auditHistory)
+	for _, r := range reports {
+		if r.Module == "" || r.UsageOnly != nil {
+			continue
+		}
+		h := history[r.Module]
+		if h == nil {
+			h = &auditHistory{}
+			history[r.Module] = h
+		}
+		h.audits++
+		if r.Breaking {
+			h.breaking++
+		}
+	}
+
+	var clean, risky []string
+	for module, h := range history {
+		switch {
+		case h.breaking == 0 && h.audits >= opts.MinAudits:
+			clean = append(clean, module)
+		case h.breaking > 0 && float64(h.breaking)/float64(h.audits) >= opts.BreakRatio:
+			risky = append(risky, module)
+		}
+	}
+	sort.Strings(clean)
+	sort.Strings(risky)
+
+	config := RenovateConfig{PackageRules: []RenovatePackageRule{}}
+	if len(clean) > 0 {
+		automerge := true
+		config.PackageRules = append(config.PackageRules, RenovatePackageRule{
+			Description:       "go-semver-audit: always audited clean",
+			MatchManagers:     []string{"gomod"},
+			MatchPackageNames: clean,
+			MatchUpdateTypes:  []string{"patch"},
+			Automerge:         &automerge,
+		})
+	}
+	if len(risky) > 0 {
+		automerge := false
+		config.PackageRules = append(config.PackageRules, RenovatePackageRule{
+			Description:                 "go-semver-audit: frequent breaking upgrades",
+			MatchManagers:               []string{"gomod"},
+			MatchPackageNames:           risky,
+			Automerge:                   &automerge,
+			DependencyDashboardApproval: true,
+			Labels:                      []string{"breaking-risk"},
+		})
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
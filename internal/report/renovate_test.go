@@ -0,0 +1,60 @@
+package report
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFormatRenovateConfig(t *testing.T) {
+	audit := func(module string, breaking bool) JSONReport {
+		return JSONReport{Module: module, Breaking: breaking}
+	}
+	reports := []JSONReport{
+		audit("example.com/clean", false),
+		audit("example.com/clean", false),
+		audit("example.com/clean", false),
+		audit("example.com/young", false),
+		// Usage-only audits could not diff the API and are not clean
+		{Module: "example.com/young", UsageOnly: &UsageOnlyItem{Reason: "offline"}},
+		{Module: "example.com/young", UsageOnly: &UsageOnlyItem{Reason: "offline"}},
+		audit("example.com/flaky", true),
+		audit("example.com/flaky", false),
+		audit("example.com/rare", true),
+		audit("example.com/rare", false),
+		audit("example.com/rare", false),
+	}
+
+	out, err := FormatRenovateConfig(reports, DefaultRenovateOptions)
+	if err != nil {
+		t.Fatalf("FormatRenovateConfig() error = %v", err)
+	}
+	var config RenovateConfig
+	if err := json.Unmarshal([]byte(out), &config); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(config.PackageRules) != 2 {
+		t.Fatalf("got %d rules, want 2: %s", len(config.PackageRules), out)
+	}
+
+	clean := config.PackageRules[0]
+	if !reflect.DeepEqual(clean.MatchPackageNames, []string{"example.com/clean"}) ||
+		!reflect.DeepEqual(clean.MatchUpdateTypes, []string{"patch"}) || clean.Automerge == nil || !*clean.Automerge {
+		t.Errorf("clean rule = %+v", clean)
+	}
+	risky := config.PackageRules[1]
+	if !reflect.DeepEqual(risky.MatchPackageNames, []string{"example.com/flaky"}) ||
+		!risky.DependencyDashboardApproval || risky.Automerge == nil || *risky.Automerge {
+		t.Errorf("risky rule = %+v", risky)
+	}
+}
+
+func TestFormatRenovateConfig_NoHistory(t *testing.T) {
+	out, err := FormatRenovateConfig(nil, DefaultRenovateOptions)
+	if err != nil {
+		t.Fatalf("FormatRenovateConfig() error = %v", err)
+	}
+	if out != "{\n  \"packageRules\": []\n}\n" {
+		t.Errorf("FormatRenovateConfig() = %q", out)
+	}
+}
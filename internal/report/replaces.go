@@ -0,0 +1,100 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// maxDriftSymbols caps the symbols listed per kind of drift in text output
+const maxDriftSymbols = 10
+
+// ReplaceDriftItem represents a replace directive's drift from upstream in JSON
+type ReplaceDriftItem struct {
+	Module          string   `json:"module"`
+	Fork            string   `json:"fork"`
+	ForkVersion     string   `json:"fork_version,omitempty"`
+	UpstreamVersion string   `json:"upstream_version,omitempty"`
+	Divergence      float64  `json:"divergence"`
+	Missing         []string `json:"missing,omitempty"`
+	ForkOnly        []string `json:"fork_only,omitempty"`
+	Changed         []string `json:"changed,omitempty"`
+	Shared          int      `json:"shared"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// FormatReplacesText generates a text report of replace directive drift
+func FormatReplacesText(drifts []analyzer.ReplaceDrift) string {
+	var b strings.Builder
+	if len(drifts) == 0 {
+		b.WriteString("✓ No replace directives found.\n")
+		return b.String()
+	}
+
+	b.WriteString("Replace Directives:\n")
+	for _, d := range drifts {
+		b.WriteString(fmt.Sprintf("  - %s => %s\n", d.Module, formatFork(d)))
+		if d.Error != "" {
+			b.WriteString(fmt.Sprintf("    Could not compare: %s\n", d.Error))
+			continue
+		}
+		b.WriteString(fmt.Sprintf("    Compared with upstream %s: %.0f%% diverged (%d shared, %d missing, %d fork-only, %d changed)\n",
+			d.UpstreamVersion, d.Divergence()*100, d.Shared, len(d.Missing), len(d.ForkOnly), len(d.Changed)))
+		writeDriftSymbols(&b, "Missing from fork", d.Missing)
+		writeDriftSymbols(&b, "Only in fork", d.ForkOnly)
+		writeDriftSymbols(&b, "Changed", d.Changed)
+	}
+	return b.String()
+}
+
+// FormatReplacesJSON generates a JSON report of replace directive drift
+func FormatReplacesJSON(drifts []analyzer.ReplaceDrift) (string, error) {
+	items := make([]ReplaceDriftItem, 0, len(drifts))
+	for _, d := range drifts {
+		items = append(items, ReplaceDriftItem{
+			Module:          d.Module,
+			Fork:            d.Fork,
+			ForkVersion:     d.ForkVersion,
+			UpstreamVersion: d.UpstreamVersion,
+			Divergence:      d.Divergence(),
+			Missing:         d.Missing,
+			ForkOnly:        d.ForkOnly,
+			Changed:         d.Changed,
+			Shared:          d.Shared,
+			Error:           d.Error,
+		})
+	}
+
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data) + "\n", nil
+}
+
+// formatFork names a replacement, with its version for module replacements
+func formatFork(d analyzer.ReplaceDrift) string {
+	if d.ForkVersion == "" {
+		return d.Fork
+	}
+	return d.Fork + " " + d.ForkVersion
+}
+
+// writeDriftSymbols lists up to maxDriftSymbols symbols under a label
+func writeDriftSymbols(b *strings.Builder, label string, symbols []string) {
+	if len(symbols) == 0 {
+		return
+	}
+	shown := symbols
+	if len(shown) > maxDriftSymbols {
+		shown = shown[:maxDriftSymbols]
+	}
+	line := strings.Join(shown, ", ")
+	if more := len(symbols) - len(shown); more > 0 {
+		line += fmt.Sprintf(", and %d more", more)
+	}
+	b.WriteString(fmt.Sprintf("    %s: %s\n", label, line))
+}
@@ -0,0 +1,42 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestFormatReplacesText(t *testing.T) {
+	drifts := []analyzer.ReplaceDrift{
+		{
+			Module:          "example.com/lib",
+			Fork:            "github.com/me/lib",
+			ForkVersion:     "v1.0.1-fork",
+			UpstreamVersion: "v1.4.0",
+			Missing:         []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L"},
+			ForkOnly:        []string{"Patched"},
+			Changed:         []string{"Client.Do"},
+			Shared:          6,
+		},
+		{Module: "example.com/util", Fork: "./third_party/util", Error: "no packages found"},
+	}
+
+	out := FormatReplacesText(drifts)
+	for _, want := range []string{
+		"  - example.com/lib => github.com/me/lib v1.0.1-fork\n",
+		"Compared with upstream v1.4.0: 70% diverged (6 shared, 12 missing, 1 fork-only, 1 changed)",
+		"Missing from fork: A, B, C, D, E, F, G, H, I, J, and 2 more",
+		"Only in fork: Patched",
+		"Changed: Client.Do",
+		"  - example.com/util => ./third_party/util\n    Could not compare: no packages found",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	if out := FormatReplacesText(nil); !strings.Contains(out, "No replace directives") {
+		t.Errorf("unexpected output without replacements: %s", out)
+	}
+}
@@ -0,0 +1,115 @@
+package report
+
+import (
+	"encoding/json"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// SARIF 2.1.0 identifiers
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// sarifRules describes every finding category, which doubles as the rule id
+var sarifRules = []sarifRule{
+	{ID: analyzer.CategoryRemoved, ShortDescription: sarifText{"Used symbol removed in the upgrade"}},
+	{ID: analyzer.CategoryMoved, ShortDescription: sarifText{"Used symbol moved to another package"}},
+	{ID: analyzer.CategorySignatureChanged, ShortDescription: sarifText{"Signature of a used function changed"}},
+	{ID: analyzer.CategorySignatureParamRename, ShortDescription: sarifText{"Parameter names of a used function changed"}},
+	{ID: analyzer.CategoryInterfaceAddedMethod, ShortDescription: sarifText{"Method added to a used interface"}},
+	{ID: analyzer.CategoryInterfaceRemovedMethod, ShortDescription: sarifText{"Method removed from or changed in a used interface"}},
+}
+
+// sarifLevels maps finding severities onto SARIF result levels
+var sarifLevels = map[string]string{
+	analyzer.SeverityError:   "error",
+	analyzer.SeverityWarning: "warning",
+	analyzer.SeverityInfo:    "note",
+}
+
+// SARIFLog is the root of a SARIF 2.1.0 log, as consumed by code scanning UIs
+type SARIFLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string    `json:"id"`
+	ShortDescription sarifText `json:"shortDescription"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifText       `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           sarifRegion   `json:"region"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// FormatSARIF generates a SARIF log with one result per use of a changed
+// symbol, so findings can be uploaded to code scanning dashboards
+func FormatSARIF(result *analyzer.Result) (string, error) {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: lspSource, Rules: sarifRules}},
+		Results: []sarifResult{},
+	}
+	visitFindings(result, func(locations []analyzer.Location, _, category, level, message string) {
+		for _, loc := range locations {
+			run.Results = append(run.Results, sarifResult{
+				RuleID:  category,
+				Level:   sarifLevels[level],
+				Message: sarifText{message},
+				Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifact{URI: fileURI(loc.File)},
+					Region:           sarifRegion{StartLine: loc.Line, StartColumn: loc.Column},
+				}}},
+			})
+		}
+	})
+
+	log := SARIFLog{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}}
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,67 @@
+package report
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestFormatSARIF(t *testing.T) {
+	result := &analyzer.Result{
+		Module:     "github.com/example/lib",
+		OldVersion: "v1.0.0",
+		NewVersion: "v2.0.0",
+		Changes: &analyzer.Diff{
+			Removed: []analyzer.RemovedSymbol{
+				{Name: "OldFunc", Type: "function", UsedIn: []analyzer.Location{
+					{File: "/src/app/main.go", Line: 12, Column: 6},
+					{File: "/src/app/util.go", Line: 4},
+				}},
+			},
+			Changed: []analyzer.ChangedSignature{
+				{
+					Name:           "Client.Do",
+					OldSignature:   "func(a int)",
+					NewSignature:   "func(b int)",
+					ParamNamesOnly: true,
+					Severity:       analyzer.SeverityInfo,
+					UsedIn:         []analyzer.Location{{File: "/src/app/main.go", Line: 3, Column: 4}},
+				},
+			},
+		},
+	}
+
+	output, err := FormatSARIF(result)
+	if err != nil {
+		t.Fatalf("FormatSARIF() error = %v", err)
+	}
+
+	var log SARIFLog
+	if err := json.Unmarshal([]byte(output), &log); err != nil {
+		t.Fatalf("FormatSARIF() produced invalid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("FormatSARIF() version = %q, runs = %d", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "go-semver-audit" || len(run.Tool.Driver.Rules) == 0 {
+		t.Errorf("driver = %+v", run.Tool.Driver)
+	}
+	if len(run.Results) != 3 {
+		t.Fatalf("results = %d, want one per use", len(run.Results))
+	}
+
+	removed := run.Results[0]
+	loc := removed.Locations[0].PhysicalLocation
+	if removed.RuleID != analyzer.CategoryRemoved || removed.Level != "error" ||
+		loc.ArtifactLocation.URI != "file:///src/app/main.go" || loc.Region != (sarifRegion{StartLine: 12, StartColumn: 6}) {
+		t.Errorf("removed result = %+v", removed)
+	}
+
+	// Info findings become SARIF notes
+	renamed := run.Results[2]
+	if renamed.RuleID != analyzer.CategorySignatureParamRename || renamed.Level != "note" {
+		t.Errorf("renamed result = %+v", renamed)
+	}
+}
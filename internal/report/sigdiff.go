@@ -0,0 +1,176 @@
+package report
+
+import (
+	"html/template"
+	"strings"
+	"unicode"
+)
+
+// sigOp tells whether a segment of a signature diff is shared, removed or added
+type sigOp int
+
+const (
+	sigEqual sigOp = iota
+	sigDelete
+	sigInsert
+)
+
+// sigSegment is a run of signature text with the same diff operation
+type sigSegment struct {
+	Op   sigOp
+	Text string
+}
+
+// ANSI escapes used to highlight removed and added text
+const (
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiReset = "\x1b[0m"
+)
+
+// diffSignature computes a word-level diff between two signatures. Words are
+// identifiers (including qualified and variadic names), runs of spaces, and
+// single punctuation characters, so a new parameter shows up as its own insert.
+func diffSignature(oldSig, newSig string) []sigSegment {
+	a, b := tokenizeSignature(oldSig), tokenizeSignature(newSig)
+
+	// lcs[i][j] is the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var segments []sigSegment
+	add := func(op sigOp, text string) {
+		if n := len(segments); n > 0 && segments[n-1].Op == op {
+			segments[n-1].Text += text
+			return
+		}
+		segments = append(segments, sigSegment{Op: op, Text: text})
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			add(sigEqual, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			add(sigDelete, a[i])
+			i++
+		default:
+			add(sigInsert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		add(sigDelete, a[i])
+	}
+	for ; j < len(b); j++ {
+		add(sigInsert, b[j])
+	}
+	return segments
+}
+
+// tokenizeSignature splits a signature into words for diffSignature
+func tokenizeSignature(sig string) []string {
+	isWord := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'
+	}
+
+	var tokens []string
+	runes := []rune(sig)
+	for i := 0; i < len(runes); {
+		j := i + 1
+		switch {
+		case isWord(runes[i]):
+			for j < len(runes) && isWord(runes[j]) {
+				j++
+			}
+		case runes[i] == ' ':
+			for j < len(runes) && runes[j] == ' ' {
+				j++
+			}
+		}
+		tokens = append(tokens, string(runes[i:j]))
+		i = j
+	}
+	return tokens
+}
+
+// formatSignatureDiff renders a signature diff for the text report. Without
+// color, removed text is marked [-like this-] and added text {+like this+}.
+// With color, each highlighted word is wrapped separately so the line can
+// still be broken at spaces without carrying color into the indentation.
+func formatSignatureDiff(segments []sigSegment, color bool) string {
+	var b strings.Builder
+	for _, seg := range segments {
+		switch {
+		case seg.Op == sigEqual:
+			b.WriteString(seg.Text)
+		case !color && seg.Op == sigDelete:
+			b.WriteString("[-" + seg.Text + "-]")
+		case !color:
+			b.WriteString("{+" + seg.Text + "+}")
+		default:
+			code := ansiGreen
+			if seg.Op == sigDelete {
+				code = ansiRed
+			}
+			for i, word := range strings.Split(seg.Text, " ") {
+				if i > 0 {
+					b.WriteString(" ")
+				}
+				if word != "" {
+					b.WriteString(code + word + ansiReset)
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+// htmlSignatureDiff renders a signature diff with <del> and <ins> elements
+func htmlSignatureDiff(segments []sigSegment) template.HTML {
+	var b strings.Builder
+	for _, seg := range segments {
+		text := template.HTMLEscapeString(seg.Text)
+		switch seg.Op {
+		case sigDelete:
+			b.WriteString("<del>" + text + "</del>")
+		case sigInsert:
+			b.WriteString("<ins>" + text + "</ins>")
+		default:
+			b.WriteString(text)
+		}
+	}
+	return template.HTML(b.String())
+}
+
+// visibleLen counts the runes of s that take up space on a terminal,
+// skipping ANSI color escapes
+func visibleLen(s string) int {
+	n := 0
+	inEscape := false
+	for _, r := range s {
+		switch {
+		case inEscape:
+			inEscape = r != 'm'
+		case r == '\x1b':
+			inEscape = true
+		default:
+			n++
+		}
+	}
+	return n
+}
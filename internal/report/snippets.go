@@ -0,0 +1,67 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// snippetContext is the number of source lines shown around each use
+const snippetContext = 2
+
+// FormatSnippets quotes the project source around every use of a changed
+// symbol, file by file, so a report can be reviewed away from the checkout.
+// The affected line is marked with ">"; unreadable files are noted instead.
+func FormatSnippets(result *analyzer.Result) string {
+	var b strings.Builder
+	groups := groupByLocation(result.Changes, GroupByFile)
+	if len(groups) == 0 {
+		b.WriteString("✓ No affected source locations.\n")
+		return b.String()
+	}
+
+	for i, group := range groups {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(fmt.Sprintf("== %s\n", group.Key))
+		data, err := os.ReadFile(group.Key)
+		if err != nil {
+			b.WriteString(fmt.Sprintf("  (source unavailable: %v)\n", err))
+			for _, item := range group.Items {
+				b.WriteString("  " + formatLocationItem(item, GroupByFile) + "\n")
+			}
+			continue
+		}
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		for _, item := range group.Items {
+			b.WriteString("\n  " + formatLocationItem(item, GroupByFile) + "\n")
+			writeSnippet(&b, lines, item.Line)
+		}
+	}
+	return b.String()
+}
+
+// writeSnippet writes the lines around line (1-based), marking line itself
+func writeSnippet(b *strings.Builder, lines []string, line int) {
+	if line < 1 || line > len(lines) {
+		b.WriteString("    (line out of range)\n")
+		return
+	}
+	first, last := line-snippetContext, line+snippetContext
+	if first < 1 {
+		first = 1
+	}
+	if last > len(lines) {
+		last = len(lines)
+	}
+	for n := first; n <= last; n++ {
+		marker := " "
+		if n == line {
+			marker = ">"
+		}
+		b.WriteString(fmt.Sprintf("  %s %4d | %s\n", marker, n, lines[n-1]))
+	}
+}
@@ -0,0 +1,54 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestFormatSnippets(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	src := "package main\n\nimport \"lib\"\n\nfunc main() {\n\tlib.OldFunc()\n}\n"
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "gone.go")
+
+	result := &analyzer.Result{
+		Changes: &analyzer.Diff{
+			Removed: []analyzer.RemovedSymbol{
+				{Name: "OldFunc", Type: "function", UsedIn: []analyzer.Location{
+					{File: file, Line: 6},
+					{File: missing, Line: 2},
+				}},
+			},
+		},
+	}
+
+	output := FormatSnippets(result)
+	for _, want := range []string{
+		"== " + file,
+		"line 6: OldFunc removed (function)",
+		"      4 | \n",
+		"  >    6 | \tlib.OldFunc()\n",
+		"      7 | }\n",
+		"== " + missing,
+		"(source unavailable:",
+		"line 2: OldFunc removed (function)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("FormatSnippets() missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "   3 | ") {
+		t.Errorf("FormatSnippets() shows more than %d lines of context:\n%s", snippetContext, output)
+	}
+
+	if got := FormatSnippets(&analyzer.Result{Changes: &analyzer.Diff{}}); !strings.Contains(got, "No affected source locations") {
+		t.Errorf("FormatSnippets() without findings = %q", got)
+	}
+}
@@ -0,0 +1,56 @@
+package report
+
+import (
+	"fmt"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// SummaryItem counts the findings of a report per category and per severity,
+// so the top of a CI log tells the whole story
+type SummaryItem struct {
+	Removed    int `json:"removed"`
+	Changed    int `json:"changed"`
+	Interface  int `json:"interface"`
+	Moved      int `json:"moved"`
+	Deprecated int `json:"deprecated"`
+	Warnings   int `json:"warnings"`
+	Errors     int `json:"errors"`
+	// Findings reported as warnings or info, such as changes to unstable
+	// APIs or categories remapped with -config
+	WarningFindings int `json:"warning_findings"`
+	InfoFindings    int `json:"info_findings"`
+}
+
+// summarize counts the findings of a result
+func summarize(result *analyzer.Result) SummaryItem {
+	var s SummaryItem
+	if changes := result.Changes; changes != nil {
+		s.Removed = len(changes.Removed)
+		s.Changed = len(changes.Changed)
+		s.Interface = len(changes.InterfaceChanges)
+		s.Moved = len(changes.Moved)
+		s.Errors = changes.BreakingCount()
+		s.WarningFindings = changes.WarningCount()
+		s.InfoFindings = s.Removed + s.Changed + s.Interface + s.Moved - s.Errors - s.WarningFindings
+	}
+	for _, change := range result.DocChanges {
+		for _, reason := range change.Reasons {
+			if reason == analyzer.DocDeprecated {
+				s.Deprecated++
+				break
+			}
+		}
+	}
+	s.Warnings = len(result.Warnings)
+	return s
+}
+
+// formatSummary renders the counts as the two lines under the report header
+func formatSummary(s SummaryItem) []string {
+	return []string{
+		fmt.Sprintf("Findings: removed: %d, changed: %d, interface: %d, moved: %d, deprecated: %d, warnings: %d",
+			s.Removed, s.Changed, s.Interface, s.Moved, s.Deprecated, s.Warnings),
+		fmt.Sprintf("Severity: error: %d, warning: %d, info: %d", s.Errors, s.WarningFindings, s.InfoFindings),
+	}
+}
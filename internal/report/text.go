@@ -2,34 +2,119 @@ package report
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/devblac/go-semver-audit/internal/analyzer"
 )
 
+// DefaultTopFixes is how many items the "What to fix next" list shows by default
+const DefaultTopFixes = 3
+
+// TextOptions tunes the text report
+type TextOptions struct {
+	Verbose  bool
+	TopFixes int    // length of the "What to fix next" list; 0 hides it
+	Width    int    // maximum line width for signatures; 0 means unlimited
+	Color    bool   // highlight signature diffs with ANSI colors
+	GroupBy  string // GroupBySymbol, GroupByFile, or GroupByPackage; empty means by symbol
+	// MaxFindings caps the findings listed per category, or per file or
+	// package when grouped by location; 0 means unlimited
+	MaxFindings int
+}
+
 // FormatText generates a human-readable text report
 func FormatText(result *analyzer.Result, verbose bool) (string, error) {
+	return FormatTextWithOptions(result, TextOptions{Verbose: verbose, TopFixes: DefaultTopFixes})
+}
+
+// FormatTextWithOptions generates a human-readable text report tuned by opts
+func FormatTextWithOptions(result *analyzer.Result, opts TextOptions) (string, error) {
+	verbose := opts.Verbose
 	var b strings.Builder
 
 	// Header
-	b.WriteString(fmt.Sprintf("Analyzing upgrade: %s %s -> %s\n\n",
-		result.Module, result.OldVersion, result.NewVersion))
+	switch {
+	case result.Floor != nil:
+		b.WriteString(fmt.Sprintf("Checking minimum version: %s >= %s\n", result.Module, result.Floor.Required))
+		b.WriteString(formatFloor(result.Floor) + "\n")
+	case result.NewDependency:
+		b.WriteString(fmt.Sprintf("Analyzing new dependency: %s %s\n", result.Module, result.NewVersion))
+	default:
+		b.WriteString(fmt.Sprintf("Analyzing upgrade: %s %s -> %s\n",
+			result.Module, result.OldVersion, result.NewVersion))
+	}
+	if !result.MainOnly && !result.ToolDependency {
+		for _, line := range formatSummary(summarize(result)) {
+			b.WriteString(line + "\n")
+		}
+	}
+	b.WriteString("\n")
+
+	for _, r := range result.Retractions {
+		b.WriteString(fmt.Sprintf("⚠️  RETRACTED: %s\n\n", formatRetraction(result.Module, r)))
+	}
+
+	if d := result.Deprecation; d != nil {
+		b.WriteString(fmt.Sprintf("⚠️  DEPRECATED: %s\n", formatDeprecation(result.Module, d)))
+		if d.Successor != "" {
+			b.WriteString(fmt.Sprintf("   %s\n", formatSuccessorAudit(d)))
+		}
+		b.WriteString("\n")
+	}
+
+	if result.Replacement != nil {
+		b.WriteString(fmt.Sprintf("⚠️  %s\n\n", formatReplacement(result)))
+	}
+
+	for _, d := range result.DuplicateMajors {
+		b.WriteString(fmt.Sprintf("⚠️  DUPLICATE MAJOR: %s\n\n", formatDuplicateMajor(result.Module, d)))
+	}
+
+	if result.RequiredVersion != "" {
+		b.WriteString(formatFromVersion(result) + "\n\n")
+	}
+
+	if result.StoppedEarly {
+		b.WriteString(stoppedEarlyNote + "\n\n")
+	}
+
+	if result.ToolDependency {
+		b.WriteString(fmt.Sprintf("Note: %s is a tool dependency pinned in tools.go; comparing module metadata instead of API usage.\n\n", result.Module))
+	}
+
+	if result.MainOnly {
+		b.WriteString(fmt.Sprintf("Note: %s only contains commands (package main), so it has no API to break; comparing module metadata instead.\n\n", result.Module))
+	}
 
 	// Check if there are any breaking changes
 	hasBreaking := result.HasBreakingChanges()
-	breakingCount := len(result.Changes.Removed) + len(result.Changes.Changed) + len(result.Changes.InterfaceChanges)
+	breakingCount := result.Changes.BreakingCount()
 	usageCount := countAffectedLocations(result.Changes)
 
-	if !hasBreaking {
+	switch {
+	case result.MainOnly:
+		// An API check would be vacuous
+	case result.UsageOnly != nil:
+		b.WriteString(fmt.Sprintf("⚠️  %s\n\n", formatUsageOnly(result.UsageOnly)))
+		b.WriteString("Symbols at Risk (API diff unavailable):\n")
+		for _, s := range result.UsageOnly.Symbols {
+			b.WriteString(fmt.Sprintf("  - %s\n", formatSymbolUses(s)))
+		}
+		if len(result.UsageOnly.Symbols) == 0 {
+			b.WriteString("  (none: the project does not use the module)\n")
+		}
+		b.WriteString("\n")
+	case !hasBreaking:
 		b.WriteString("✓ No breaking changes detected.\n\n")
-	} else {
+	default:
 		b.WriteString("⚠️  BREAKING CHANGES DETECTED\n\n")
 	}
 
 	if hasBreaking {
 		b.WriteString(fmt.Sprintf("Summary: %d breaking change(s) affecting %d location(s).\n\n", breakingCount, usageCount))
 
-		if fixes := summarizeFixes(result.Changes, 3); len(fixes) > 0 {
+		if fixes := summarizeFixes(result.Changes, opts.TopFixes); len(fixes) > 0 {
 			b.WriteString("What to fix next:\n")
 			for _, fix := range fixes {
 				b.WriteString(fmt.Sprintf("  - %s\n", fix))
@@ -40,11 +125,267 @@ func FormatText(result *analyzer.Result, verbose bool) (string, error) {
 
 	changes := result.Changes
 
+	if unstable := changes.UnstableCount(); unstable > 0 {
+		b.WriteString(fmt.Sprintf("Note: %d change(s) affect unstable APIs (internal, experimental, or documented as unstable) and are reported as warnings.\n\n", unstable))
+	}
+
+	// Report findings per symbol, or per file or package of the project
+	if opts.GroupBy == GroupByFile || opts.GroupBy == GroupByPackage {
+		writeFindingsByLocation(&b, changes, opts.GroupBy, opts.MaxFindings)
+	} else {
+		writeFindingsBySymbol(&b, changes, opts)
+	}
+
+	// Report doc comment changes of used symbols
+	if len(result.DocChanges) > 0 {
+		b.WriteString("Documentation Changes:\n")
+		shown := capFindings(len(result.DocChanges), opts.MaxFindings)
+		for _, change := range result.DocChanges[:shown] {
+			b.WriteString(fmt.Sprintf("  - %s: %s\n", change.Name, strings.Join(change.Reasons, ", ")))
+			for _, line := range formatDocChange(change) {
+				b.WriteString(fmt.Sprintf("    %s\n", line))
+			}
+			if len(change.UsedIn) > 0 {
+				b.WriteString(fmt.Sprintf("    Used in: %s\n", formatLocations(change.UsedIn, 3)))
+			}
+		}
+		writeOmitted(&b, len(result.DocChanges)-shown)
+		b.WriteString("\n")
+	}
+
+	// Report examples from the new version for migrating call sites
+	if len(result.Examples) > 0 {
+		b.WriteString("Migration Examples:\n")
+		for _, ex := range result.Examples {
+			b.WriteString(fmt.Sprintf("  - %s: %s\n", ex.Symbol, formatExampleSource(ex)))
+			for _, line := range strings.Split(ex.Code, "\n") {
+				b.WriteString(strings.TrimRight("      "+line, " ") + "\n")
+			}
+			if ex.Output != "" {
+				b.WriteString("      // Output:\n")
+				for _, line := range strings.Split(strings.TrimRight(ex.Output, "\n"), "\n") {
+					b.WriteString(strings.TrimRight("      // "+line, " ") + "\n")
+				}
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	// Report churn in generated packages as one line per package
+	if len(changes.Generated) > 0 {
+		b.WriteString("Generated Packages:\n")
+		for _, group := range changes.Generated {
+			b.WriteString(fmt.Sprintf("  - %s\n", formatGeneratedGroup(group)))
+		}
+		b.WriteString("\n")
+	}
+
+	// Report added symbols (informational, only in verbose mode unless adopting a new dependency)
+	if (verbose || result.NewDependency) && len(changes.Added) > 0 {
+		if result.NewDependency {
+			b.WriteString("API You Would Adopt:\n")
+		} else {
+			b.WriteString("Added Symbols (informational):\n")
+		}
+		writeAddedSymbols(&b, changes.Added, opts.MaxFindings)
+		b.WriteString("\n")
+	}
+
+	// Report added symbols that may replace project code
+	if len(result.Hints) > 0 {
+		b.WriteString("Adoption Hints:\n")
+		for _, hint := range result.Hints {
+			b.WriteString(fmt.Sprintf("  - %s\n", formatHint(hint)))
+		}
+		b.WriteString("\n")
+	}
+
+	// Report project TODOs that added symbols may address
+	if len(result.TODOs) > 0 {
+		b.WriteString("TODOs the New Version May Address:\n")
+		for _, todo := range result.TODOs {
+			b.WriteString(fmt.Sprintf("  - %s\n", formatTODOMatch(todo)))
+		}
+		b.WriteString("\n")
+	}
+
+	// Report unreachable project code holding the only uses of findings
+	if len(result.DeadCode) > 0 {
+		b.WriteString("Dead Code (delete instead of migrating):\n")
+		for _, fn := range result.DeadCode {
+			b.WriteString(fmt.Sprintf("  - %s\n", formatDeadFunction(fn)))
+		}
+		b.WriteString("\n")
+	}
+
+	// Report the project packages the upgrade ripples through
+	if graph := result.ImportGraph; graph != nil && len(graph.Nodes) > 0 {
+		byDir := findingsByDir(result)
+		b.WriteString("Affected Packages:\n")
+		for _, node := range graph.Nodes {
+			b.WriteString(fmt.Sprintf("  - %s\n", formatGraphNode(node, byDir[node.Dir])))
+		}
+		b.WriteString("\n")
+	}
+
+	// Report in-repo copies whose references were left out
+	if len(result.Copies) > 0 {
+		b.WriteString("Copies of the Dependency (excluded from findings):\n")
+		for _, c := range result.Copies {
+			b.WriteString(fmt.Sprintf("  - %s\n", formatCopy(c)))
+		}
+		b.WriteString("\n")
+	}
+
+	// Report modules pulled in by a new dependency
+	if len(result.Requirements) > 0 {
+		b.WriteString("Required Modules:\n")
+		for _, req := range result.Requirements {
+			b.WriteString(fmt.Sprintf("  - %s\n", formatRequirement(req)))
+		}
+		b.WriteString("\n")
+	}
+
+	// Report go.mod changes of a tool dependency
+	if mc := result.ModuleChanges; mc != nil {
+		b.WriteString("Tool Module Changes:\n")
+		for _, line := range formatModuleChanges(mc) {
+			b.WriteString(fmt.Sprintf("  %s\n", line))
+		}
+		b.WriteString("\n")
+	}
+
+	// Report the dependency's risk score
+	if result.Risk != nil {
+		b.WriteString(fmt.Sprintf("Risk:\n  %s\n\n", formatRisk(result.Risk)))
+	}
+
+	// Report how the new version was authenticated
+	if s := result.SumDB; s != nil {
+		mark := "✓"
+		if !s.Verified() {
+			mark = "⚠️ "
+		}
+		b.WriteString(fmt.Sprintf("Supply Chain:\n  %s %s\n\n", mark, formatSumDB(result.Module, s)))
+	}
+
+	// Report how much of the project touches the dependency and, when
+	// requested, how the upgrade changes its weight
+	if result.Coupling != nil || result.Footprint != nil {
+		b.WriteString("Footprint:\n")
+		for _, line := range formatFootprint(result) {
+			b.WriteString(fmt.Sprintf("  %s\n", line))
+		}
+		b.WriteString("\n")
+	}
+
+	// Suggest an adapter package for a dependency used all over the project
+	if adapter := result.Adapter; adapter != nil && result.Coupling != nil {
+		b.WriteString(fmt.Sprintf("Advisory:\n  %s\n", formatAdapter(result.Module, result.Coupling, adapter)))
+		for _, site := range adapter.Sites {
+			b.WriteString(fmt.Sprintf("    - %s\n", formatSymbolUses(site)))
+		}
+		b.WriteString("\n")
+	}
+
+	// Report binary size impact
+	if result.BinaryImpact != nil {
+		b.WriteString(fmt.Sprintf("Binary Size:\n  %s\n\n", formatBinaryImpact(result.BinaryImpact)))
+	}
+
+	// Report tests broken by the upgrade
+	if len(result.TestFailures) > 0 {
+		b.WriteString("Failing Tests After Upgrade:\n")
+		for _, failure := range result.TestFailures {
+			b.WriteString(fmt.Sprintf("  - %s\n", formatTestFailure(failure)))
+		}
+		b.WriteString("\n")
+	}
+
+	// Report generated compatibility shims
+	if shims := result.Shims; shims != nil {
+		b.WriteString("Compatibility Shims:\n")
+		for _, line := range formatShims(shims) {
+			b.WriteString(fmt.Sprintf("  %s\n", line))
+		}
+		b.WriteString("\n")
+		if shims.DryRun && shims.Diff.Diff != "" {
+			b.WriteString("Proposed Shims:\n")
+			b.WriteString(shims.Diff.Diff)
+			b.WriteString("\n")
+		}
+	}
+
+	// Report call sites rewritten by -fix
+	if fixes := result.Fixes; fixes != nil {
+		b.WriteString("Automatic Fixes:\n")
+		for _, line := range formatFixes(fixes) {
+			b.WriteString(fmt.Sprintf("  %s\n", line))
+		}
+		b.WriteString("\n")
+		if fixes.DryRun && len(fixes.Diffs) > 0 {
+			b.WriteString("Proposed Changes:\n")
+			for _, diff := range fixes.Diffs {
+				b.WriteString(diff.Diff)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	// Report benchmark deltas
+	if len(result.Benchmarks) > 0 {
+		b.WriteString("Benchmarks (ns/op):\n")
+		for _, bench := range result.Benchmarks {
+			b.WriteString(fmt.Sprintf("  %s\n", formatBenchmark(bench)))
+		}
+		b.WriteString("\n")
+	}
+
+	// Report unused dependencies
+	if len(result.UnusedDeps) > 0 {
+		b.WriteString("Unused Dependencies:\n")
+		for _, dep := range result.UnusedDeps {
+			b.WriteString(fmt.Sprintf("  - %s\n", dep))
+		}
+		b.WriteString("\n")
+	}
+
+	// Report the project load errors the analysis continued past
+	if len(result.LoadErrors) > 0 {
+		b.WriteString("Load Errors (findings marked [approximate] may be incomplete):\n")
+		for _, le := range result.LoadErrors {
+			b.WriteString(fmt.Sprintf("  - %s\n", formatLoadError(le)))
+		}
+		b.WriteString("\n")
+	}
+
+	// Report non-fatal issues that may make the result incomplete
+	if len(result.Warnings) > 0 {
+		b.WriteString("Warnings:\n")
+		for _, w := range result.Warnings {
+			b.WriteString(fmt.Sprintf("  - %s\n", formatWarning(w)))
+		}
+		b.WriteString("\n")
+	}
+
+	// Summary
+	if hasBreaking {
+		b.WriteString(fmt.Sprintf("Summary: %d breaking change(s) affecting %d location(s) in your code.\n",
+			breakingCount, usageCount))
+	}
+
+	return b.String(), nil
+}
+
+// writeFindingsBySymbol writes one section per kind of finding, listing each
+// symbol with the places that use it
+func writeFindingsBySymbol(b *strings.Builder, changes *analyzer.Diff, opts TextOptions) {
 	// Report removed symbols
 	if len(changes.Removed) > 0 {
 		b.WriteString("Removed Symbols:\n")
-		for _, removed := range changes.Removed {
-			b.WriteString(fmt.Sprintf("  - %s (%s)", removed.Name, removed.Type))
+		shown := capFindings(len(changes.Removed), opts.MaxFindings)
+		for _, removed := range changes.Removed[:shown] {
+			b.WriteString(fmt.Sprintf("  - %s (%s)%s%s%s%s%s%s", removed.Name, removed.Type, viaTag(removed.ViaModule), unstableTag(removed.Unstable), severityTag(removed.Severity), deadCodeTag(removed.DeadCode), platformTag(removed.Platforms), approximateTag(isApproximate(removed.UsedIn))))
 			if len(removed.UsedIn) > 0 {
 				b.WriteString(" (used in: ")
 				locations := formatLocations(removed.UsedIn, 3)
@@ -52,95 +393,127 @@ func FormatText(result *analyzer.Result, verbose bool) (string, error) {
 				b.WriteString(")")
 			}
 			b.WriteString("\n")
+			for _, note := range usageNotes(removed) {
+				b.WriteString(fmt.Sprintf("    %s\n", note))
+			}
 		}
+		writeOmitted(b, len(changes.Removed)-shown)
+		b.WriteString("\n")
+	}
+
+	// Report symbols that moved to another package
+	if len(changes.Moved) > 0 {
+		b.WriteString("Moved Symbols:\n")
+		shown := capFindings(len(changes.Moved), opts.MaxFindings)
+		for _, moved := range changes.Moved[:shown] {
+			b.WriteString(fmt.Sprintf("  - %s%s%s%s%s%s", formatMove(moved), unstableTag(moved.Unstable), severityTag(moved.Severity), deadCodeTag(moved.DeadCode), platformTag(moved.Platforms), approximateTag(isApproximate(moved.UsedIn))))
+			if len(moved.UsedIn) > 0 {
+				b.WriteString(fmt.Sprintf(" (used in: %s)", formatLocations(moved.UsedIn, 3)))
+			}
+			b.WriteString("\n")
+		}
+		writeOmitted(b, len(changes.Moved)-shown)
 		b.WriteString("\n")
 	}
 
 	// Report changed signatures
 	if len(changes.Changed) > 0 {
 		b.WriteString("Changed Signatures:\n")
-		for _, changed := range changes.Changed {
-			b.WriteString(fmt.Sprintf("  - %s\n", changed.Name))
-			if verbose {
-				b.WriteString(fmt.Sprintf("    Old: %s\n", changed.OldSignature))
-				b.WriteString(fmt.Sprintf("    New: %s\n", changed.NewSignature))
+		shown := capFindings(len(changes.Changed), opts.MaxFindings)
+		for _, changed := range changes.Changed[:shown] {
+			b.WriteString(fmt.Sprintf("  - %s%s%s%s%s%s%s%s%s\n", changed.Name, promotedTag(changed.PromotedFrom), viaTag(changed.ViaModule), behaviorTag(changed.BehaviorChange), unstableTag(changed.Unstable), severityTag(changedSeverity(changed)), deadCodeTag(changed.DeadCode), platformTag(changed.Platforms), approximateTag(isApproximate(changed.UsedIn))))
+			for _, pc := range changed.ParamChanges {
+				b.WriteString(fmt.Sprintf("    %s\n", formatParamChange(pc)))
+			}
+			if opts.Verbose {
+				diff := diffSignature(changed.OldSignature, changed.NewSignature)
+				writeWrapped(b, "    Diff: ", formatSignatureDiff(diff, opts.Color), opts.Width)
 			}
 			if len(changed.UsedIn) > 0 {
 				locations := formatLocations(changed.UsedIn, 3)
 				b.WriteString(fmt.Sprintf("    Used in: %s\n", locations))
 			}
+			for _, inst := range changed.Instantiations {
+				b.WriteString(fmt.Sprintf("    Instantiated as %s\n", formatInstantiation(inst)))
+			}
 		}
+		writeOmitted(b, len(changes.Changed)-shown)
 		b.WriteString("\n")
 	}
 
 	// Report interface changes
 	if len(changes.InterfaceChanges) > 0 {
 		b.WriteString("Modified Interfaces:\n")
-		for _, iface := range changes.InterfaceChanges {
-			b.WriteString(fmt.Sprintf("  - %s\n", iface.Name))
+		shown := capFindings(len(changes.InterfaceChanges), opts.MaxFindings)
+		for _, iface := range changes.InterfaceChanges[:shown] {
+			b.WriteString(fmt.Sprintf("  - %s%s%s%s%s%s%s\n", iface.Name, viaTag(iface.ViaModule), unstableTag(iface.Unstable), severityTag(iface.Severity), deadCodeTag(iface.DeadCode), platformTag(iface.Platforms), approximateTag(isApproximate(iface.UsedIn))))
 			if len(iface.RemovedMethods) > 0 {
 				b.WriteString("    Removed methods:\n")
 				for _, method := range iface.RemovedMethods {
-					b.WriteString(fmt.Sprintf("      - %s\n", method))
+					writeSignature(b, "      - ", method, opts)
 				}
 			}
 			if len(iface.AddedMethods) > 0 {
 				b.WriteString("    Added methods:\n")
 				for _, method := range iface.AddedMethods {
-					b.WriteString(fmt.Sprintf("      - %s\n", method))
+					writeSignature(b, "      - ", method, opts)
 				}
 			}
 			if len(iface.UsedIn) > 0 {
 				locations := formatLocations(iface.UsedIn, 3)
 				b.WriteString(fmt.Sprintf("    Used in: %s\n", locations))
 			}
+			for _, note := range interfaceNotes(iface) {
+				b.WriteString(fmt.Sprintf("    %s\n", note))
+			}
 		}
+		writeOmitted(b, len(changes.InterfaceChanges)-shown)
 		b.WriteString("\n")
 	}
-
-	// Report added symbols (informational, only in verbose mode)
-	if verbose && len(changes.Added) > 0 {
-		b.WriteString("Added Symbols (informational):\n")
-		for _, added := range changes.Added {
-			b.WriteString(fmt.Sprintf("  + %s (%s)\n", added.Name, added.Type))
-		}
-		b.WriteString("\n")
-	}
-
-	// Report unused dependencies
-	if len(result.UnusedDeps) > 0 {
-		b.WriteString("Unused Dependencies:\n")
-		for _, dep := range result.UnusedDeps {
-			b.WriteString(fmt.Sprintf("  - %s\n", dep))
-		}
-		b.WriteString("\n")
-	}
-
-	// Summary
-	if hasBreaking {
-		b.WriteString(fmt.Sprintf("Summary: %d breaking change(s) affecting %d location(s) in your code.\n",
-			breakingCount, usageCount))
-	}
-
-	return b.String(), nil
 }
 
-// summarizeFixes returns a short list of items to address first.
+// summarizeFixes returns a short list of items to address first, ranked so the
+// findings touching the most code come first. Unstable findings rank below
+// stable ones since they are only warnings.
 func summarizeFixes(changes *analyzer.Diff, max int) []string {
-	var fixes []string
+	type fix struct {
+		text     string
+		uses     int
+		files    int
+		unstable bool
+	}
+	var fixes []fix
+	add := func(text string, usedIn []analyzer.Location, unstable bool) {
+		fixes = append(fixes, fix{text: text, uses: len(usedIn), files: countFiles(usedIn), unstable: unstable})
+	}
 
 	for _, removed := range changes.Removed {
 		if len(removed.UsedIn) == 0 {
 			continue
 		}
-		fixes = append(fixes, fmt.Sprintf("Remove/replace %s (%s) at %s", removed.Name, removed.Type, formatLocations(removed.UsedIn, 1)))
+		if removed.Conversion != nil {
+			add(fmt.Sprintf("Rewrite calls to %s as %s at %s", removed.Name, removed.Conversion.NewName, formatLocations(removed.UsedIn, 1)), removed.UsedIn, removed.Unstable)
+			continue
+		}
+		add(fmt.Sprintf("Remove/replace %s (%s) at %s", removed.Name, removed.Type, formatLocations(removed.UsedIn, 1)), removed.UsedIn, removed.Unstable)
+	}
+
+	for _, moved := range changes.Moved {
+		if len(moved.UsedIn) == 0 {
+			continue
+		}
+		add(fmt.Sprintf("Import %s from %s at %s", moved.NewName, moved.NewPackage, formatLocations(moved.UsedIn, 1)), moved.UsedIn, moved.Unstable)
 	}
 
 	for _, changed := range changes.Changed {
 		if len(changed.UsedIn) == 0 {
 			continue
 		}
-		fixes = append(fixes, fmt.Sprintf("Update call to %s at %s", changed.Name, formatLocations(changed.UsedIn, 1)))
+		text := fmt.Sprintf("Update call to %s at %s", changed.Name, formatLocations(changed.UsedIn, 1))
+		if changed.BehaviorChange == analyzer.BehaviorPanicToError {
+			text += " and handle the error it returns instead of panicking"
+		}
+		add(text, changed.UsedIn, changed.Unstable)
 	}
 
 	for _, iface := range changes.InterfaceChanges {
@@ -148,13 +521,674 @@ func summarizeFixes(changes *analyzer.Diff, max int) []string {
 			continue
 		}
 		action := "Update implementations"
-		fixes = append(fixes, fmt.Sprintf("%s of %s at %s", action, iface.Name, formatLocations(iface.UsedIn, 1)))
+		add(fmt.Sprintf("%s of %s at %s", action, iface.Name, formatLocations(iface.UsedIn, 1)), iface.UsedIn, iface.Unstable)
 	}
 
+	// Stable sort keeps category order (removals, moves, calls, interfaces) for ties
+	sort.SliceStable(fixes, func(i, j int) bool {
+		if fixes[i].unstable != fixes[j].unstable {
+			return !fixes[i].unstable
+		}
+		if fixes[i].uses != fixes[j].uses {
+			return fixes[i].uses > fixes[j].uses
+		}
+		return fixes[i].files > fixes[j].files
+	})
+
 	if len(fixes) > max {
-		return fixes[:max]
+		fixes = fixes[:max]
+	}
+	lines := make([]string, len(fixes))
+	for i, f := range fixes {
+		lines[i] = f.text
+	}
+	return lines
+}
+
+// capFindings returns how many of n findings a category lists when capped
+// at max, where 0 means no cap
+func capFindings(n, max int) int {
+	if max > 0 && n > max {
+		return max
+	}
+	return n
+}
+
+// writeOmitted notes how many findings a capped category left out
+func writeOmitted(b *strings.Builder, omitted int) {
+	if omitted > 0 {
+		b.WriteString(fmt.Sprintf("  ... %d finding(s) omitted, see JSON for the full list\n", omitted))
+	}
+}
+
+// countFiles returns the number of distinct files among locations
+func countFiles(locations []analyzer.Location) int {
+	files := make(map[string]bool)
+	for _, loc := range locations {
+		files[loc.File] = true
+	}
+	return len(files)
+}
+
+// formatFloor states whether the project meets a minimum version and, if
+// not, how far behind it is
+func formatFloor(floor *analyzer.Floor) string {
+	if floor.Satisfied {
+		return fmt.Sprintf("✓ %s satisfies the required minimum %s.", floor.Current, floor.Required)
+	}
+	return fmt.Sprintf("✗ %s is below the required minimum %s (%s); breaking cost of upgrading to it:", floor.Current, floor.Required, floor.Gap)
+}
+
+// formatReplacement explains how a replace directive shaped the comparison
+func formatReplacement(result *analyzer.Result) string {
+	rep := result.Replacement
+	target := rep.Path
+	if rep.Version != "" {
+		target += " " + rep.Version
+	}
+
+	if rep.Ignored {
+		return fmt.Sprintf("go.mod replaces %s with %s; ignoring the replacement and comparing %s %s instead.",
+			result.Module, target, result.OldVersion, result.NewVersion)
+	}
+	return fmt.Sprintf("go.mod replaces %s with %s; the current API was taken from the replacement, so findings compare the fork against %s %s (use -ignore-replace to compare %s instead).",
+		result.Module, target, result.Module, result.NewVersion, result.OldVersion)
+}
+
+// formatRetraction explains that a version of the upgrade was retracted
+func formatRetraction(module string, r analyzer.Retraction) string {
+	text := fmt.Sprintf("%s %s, the upgrade target, was retracted by its authors", module, r.Version)
+	if r.Current {
+		text = fmt.Sprintf("%s %s, the version the project requires now, was retracted by its authors", module, r.Version)
+	}
+	if r.Rationale != "" {
+		return text + ": " + r.Rationale
+	}
+	return text + "."
+}
+
+// formatDuplicateMajor describes another major of the module left in the
+// module graph, and the project packages keeping it there
+func formatDuplicateMajor(module string, d analyzer.DuplicateMajor) string {
+	other := strings.TrimSpace(d.Module + " " + d.Version)
+	if len(d.Importers) == 0 {
+		return fmt.Sprintf("%s stays in the module graph next to %s, required by other dependencies.", other, module)
+	}
+	return fmt.Sprintf("%s stays in the module graph next to %s, still imported by %s", other, module, strings.Join(d.Importers, ", "))
+}
+
+// formatDeprecation quotes the deprecation notice of a module
+func formatDeprecation(module string, d *analyzer.Deprecation) string {
+	return fmt.Sprintf("%s is deprecated by its authors: %s", module, d.Message)
+}
+
+// formatSuccessorAudit suggests auditing a switch to the module a
+// deprecation notice points to
+func formatSuccessorAudit(d *analyzer.Deprecation) string {
+	return fmt.Sprintf("To audit switching to %s instead, run: go-semver-audit -new -upgrade %s@latest", d.Successor, d.Successor)
+}
+
+// formatSumDB states whether a version was verified against a checksum database
+func formatSumDB(module string, s *analyzer.SumDBStatus) string {
+	if s.Verified() {
+		return fmt.Sprintf("%s %s (%s) was verified against %s.", module, s.Version, s.Sum, s.Database)
+	}
+	return fmt.Sprintf("%s %s (%s) was not verified against a checksum database: %s bypasses it.", module, s.Version, s.Sum, s.Bypass)
+}
+
+// formatUsageOnly explains why a report only lists the project's uses of
+// the module
+func formatUsageOnly(u *analyzer.UsageOnly) string {
+	return fmt.Sprintf("API DIFF UNAVAILABLE: %s. Breaking changes could not be checked, so every use of the module below is at risk.", u.Reason)
+}
+
+// formatSymbolUses lists where a symbol of the dependency is used
+func formatSymbolUses(s analyzer.SymbolUses) string {
+	return fmt.Sprintf("%s (used in: %s)", s.Name, formatLocations(s.UsedIn, 3))
+}
+
+// formatFromVersion notes that the upgrade was diffed from a version other
+// than the one go.mod requires
+func formatFromVersion(result *analyzer.Result) string {
+	return fmt.Sprintf("Diffing from %s as requested with -from, instead of %s required by go.mod.", result.OldVersion, result.RequiredVersion)
+}
+
+// formatRisk explains the risk score with the inputs behind it
+func formatRisk(risk *analyzer.Risk) string {
+	return fmt.Sprintf("%d/100 (%s): %.0f%% of %d audit(s) breaking, %d of %d exported symbol(s) used at %d site(s)",
+		risk.Score, risk.Level, 100*risk.BreakingRate, risk.Audits, risk.UsedSymbols, risk.APISurface, risk.UsageSites)
+}
+
+// formatFootprint summarizes the project's coupling to the dependency and
+// the size, package, and module deltas line by line
+func formatFootprint(result *analyzer.Result) []string {
+	var lines []string
+	if c := result.Coupling; c != nil {
+		lines = append(lines, formatCoupling(c))
+	}
+	fp := result.Footprint
+	if fp == nil {
+		return lines
+	}
+	lines = append(lines,
+		fmt.Sprintf("Download size: %s -> %s (%s)", formatSize(fp.OldSize), formatSize(fp.NewSize), formatSizeDelta(fp.NewSize-fp.OldSize)),
+		fmt.Sprintf("Packages: %d -> %d (%d new)", fp.OldPackages, fp.NewPackages, len(fp.PackagesAdded)),
+		fmt.Sprintf("Modules in the build: %d added, %d removed", len(fp.ModulesAdded), len(fp.ModulesRemoved)),
+	)
+	for _, mod := range fp.ModulesAdded {
+		lines = append(lines, "  + "+mod)
+	}
+	for _, mod := range fp.ModulesRemoved {
+		lines = append(lines, "  - "+mod)
+	}
+	if len(fp.SharedBumps) > 0 {
+		lines = append(lines, fmt.Sprintf("Modules you also require that the upgrade raises: %d", len(fp.SharedBumps)))
+		for _, bump := range fp.SharedBumps {
+			lines = append(lines, "  ^ "+formatSharedBump(bump))
+		}
+	}
+	return lines
+}
+
+// formatSharedBump describes the version change an upgrade implies for a
+// module the project requires directly
+func formatSharedBump(bump analyzer.SharedBump) string {
+	via := "newly required by the upgrade"
+	if bump.OldVersion != "" {
+		via = fmt.Sprintf("the dependency requires %s instead of %s", bump.NewVersion, bump.OldVersion)
+	}
+	return fmt.Sprintf("%s %s -> %s (%s)", bump.Path, bump.ProjectVersion, bump.NewVersion, via)
+}
+
+// formatCoupling counts the symbols of the dependency the project uses and
+// the packages, files, and lines using them
+func formatCoupling(c *analyzer.Coupling) string {
+	return fmt.Sprintf("Coupling: %d symbol(s) from %d package(s), used on %d line(s) in %d file(s) across %d project package(s)",
+		c.Symbols, c.Packages, c.Lines, c.Files, c.ProjectPackages)
+}
+
+// formatAdapter suggests consolidating a widely used dependency behind an
+// internal adapter package
+func formatAdapter(module string, c *analyzer.Coupling, adapter *analyzer.AdapterAdvice) string {
+	return fmt.Sprintf("%s is used from %d project packages (more than %d). Consider wrapping it in an internal adapter package, starting with its most used symbols:",
+		module, c.ProjectPackages, adapter.Threshold)
+}
+
+// formatShims summarizes the shim file and the symbols it could not cover
+func formatShims(shims *analyzer.ShimFile) []string {
+	var lines []string
+	if shims.Path == "" {
+		lines = append(lines, "No shims generated")
+	} else {
+		lines = append(lines, fmt.Sprintf("Wrote %d adapter(s) to %s", len(shims.Functions), shims.Path))
+	}
+	if shims.DryRun && shims.Path != "" {
+		lines[0] = fmt.Sprintf("Would write %d adapter(s) to %s (dry run, nothing written)", len(shims.Functions), shims.Path)
+	}
+	for _, fn := range shims.Functions {
+		lines = append(lines, "+ "+fn)
+	}
+	for _, skipped := range shims.Skipped {
+		lines = append(lines, "skipped "+skipped)
+	}
+	return lines
+}
+
+// formatFixes summarizes the call sites -fix rewrote and the ones it left
+func formatFixes(fixes *analyzer.FixReport) []string {
+	lines := []string{fmt.Sprintf("Rewrote %d call site(s) in %d file(s)", len(fixes.Fixed), len(fixes.Files))}
+	if fixes.DryRun {
+		lines[0] = fmt.Sprintf("Would rewrite %d call site(s) in %d file(s) (dry run, nothing written)", len(fixes.Fixed), len(fixes.Files))
+	}
+	for _, site := range fixes.Fixed {
+		lines = append(lines, fmt.Sprintf("+ %s:%d: %s: %s", site.Location.File, site.Location.Line, site.Symbol, site.Fix))
+	}
+	for _, site := range fixes.Skipped {
+		lines = append(lines, fmt.Sprintf("skipped %s:%d: %s: %s", site.Location.File, site.Location.Line, site.Symbol, site.Reason))
+	}
+	if fixes.Tested {
+		if len(fixes.TestFailures) == 0 {
+			lines = append(lines, "Tests pass with the new version after the rewrite")
+		}
+		for _, failure := range fixes.TestFailures {
+			lines = append(lines, fmt.Sprintf("still failing after the rewrite: %s", formatTestFailure(failure)))
+		}
+	}
+	return lines
+}
+
+// formatMove describes where a symbol moved, including the new import path
+func formatMove(moved analyzer.MovedSymbol) string {
+	return fmt.Sprintf("%s (%s) moved to %s.%s (was %s)", moved.Name, moved.Type, moved.NewPackage, moved.NewName, moved.OldPackage)
+}
+
+// formatCopy describes a copy of the dependency found in the project
+func formatCopy(c analyzer.DependencyCopy) string {
+	return fmt.Sprintf("%s (%s): %d reference(s) excluded", c.Dir, c.Reason, c.References)
+}
+
+// formatDocChange lists the doc sentences a change dropped and added
+func formatDocChange(change analyzer.DocChange) []string {
+	var lines []string
+	for _, s := range change.Removed {
+		lines = append(lines, "- "+s)
+	}
+	for _, s := range change.Added {
+		lines = append(lines, "+ "+s)
+	}
+	return lines
+}
+
+// stoppedEarlyNote explains why a -fail-fast report holds a single finding
+const stoppedEarlyNote = "Note: stopped at the first breaking change (-fail-fast); other findings and optional checks were skipped."
+
+// formatWarning prefixes a warning with its code
+func formatWarning(w analyzer.Warning) string {
+	return fmt.Sprintf("[%s] %s", w.Code, w.Message)
+}
+
+// formatLoadError prints a load error with its package and position
+func formatLoadError(le analyzer.LoadError) string {
+	if le.Position == "" {
+		return fmt.Sprintf("%s: %s", le.Package, le.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", le.Package, le.Position, le.Message)
+}
+
+// formatExampleSource names where an example comes from in the dependency
+func formatExampleSource(ex analyzer.Example) string {
+	if ex.Dir == "." {
+		return ex.Name
+	}
+	return fmt.Sprintf("%s in %s", ex.Name, ex.Dir)
+}
+
+// formatGeneratedGroup summarizes the collapsed changes of a generated package
+func formatGeneratedGroup(group analyzer.GeneratedGroup) string {
+	return fmt.Sprintf("%d change(s) in generated package %s, %d used by you", group.Changes, group.Package, group.Used)
+}
+
+// formatModuleChanges summarizes the go directive and requirement changes line by line
+func formatModuleChanges(mc *analyzer.ModuleChanges) []string {
+	var lines []string
+	if mc.OldGoVersion != mc.NewGoVersion {
+		lines = append(lines, fmt.Sprintf("go directive: %s -> %s", valueOrNone(mc.OldGoVersion), valueOrNone(mc.NewGoVersion)))
+	}
+	for _, mod := range mc.Added {
+		lines = append(lines, "+ "+mod)
+	}
+	for _, mod := range mc.Removed {
+		lines = append(lines, "- "+mod)
+	}
+	for _, mod := range mc.Upgraded {
+		lines = append(lines, "~ "+mod)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "No go.mod changes")
+	}
+	return lines
+}
+
+// valueOrNone renders empty values explicitly
+func valueOrNone(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+	return v
+}
+
+// formatBinaryImpact summarizes the binary size change of a main package
+func formatBinaryImpact(impact *analyzer.BinaryImpact) string {
+	if impact.Error != "" {
+		return fmt.Sprintf("%s: not measured (%s)", impact.Package, impact.Error)
+	}
+	return fmt.Sprintf("%s: %s -> %s (%s)", impact.Package,
+		formatSize(impact.OldSize), formatSize(impact.NewSize), formatSizeDelta(impact.NewSize-impact.OldSize))
+}
+
+// formatTestFailure names a failing test or package
+func formatTestFailure(failure analyzer.TestFailure) string {
+	if failure.Test == "" {
+		return fmt.Sprintf("%s (package failed)", failure.Package)
+	}
+	return fmt.Sprintf("%s.%s", failure.Package, failure.Test)
+}
+
+// formatBenchmark renders a benchmark comparison in benchstat style
+func formatBenchmark(bench analyzer.BenchmarkDelta) string {
+	line := fmt.Sprintf("%s: %.1f -> %.1f (%+.1f%%)", bench.Name, bench.OldNsPerOp, bench.NewNsPerOp, bench.DeltaPercent)
+	if bench.Regression {
+		line += " REGRESSION"
+	}
+	return line
+}
+
+// formatSize renders a byte count in human-readable units
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// formatSizeDelta renders a signed byte difference
+func formatSizeDelta(n int64) string {
+	if n < 0 {
+		return "-" + formatSize(-n)
+	}
+	return "+" + formatSize(n)
+}
+
+// formatRequirement describes a required module relative to the project's own requirements
+func formatRequirement(req analyzer.Requirement) string {
+	switch {
+	case req.ProjectVersion == "":
+		return fmt.Sprintf("%s %s (new to your project)", req.Path, req.Version)
+	case req.ProjectVersion == req.Version:
+		return fmt.Sprintf("%s %s (already required)", req.Path, req.Version)
+	default:
+		return fmt.Sprintf("%s %s (your project requires %s)", req.Path, req.Version, req.ProjectVersion)
+	}
+}
+
+// unstableTag marks findings in unstable APIs
+func unstableTag(unstable bool) string {
+	if unstable {
+		return " [unstable]"
+	}
+	return ""
+}
+
+// severityTag shows a severity set by an override in the config file
+func severityTag(severity string) string {
+	if severity != "" {
+		return fmt.Sprintf(" [%s]", severity)
+	}
+	return ""
+}
+
+// changedSeverity is the severity to show for a changed signature: its
+// override, or info for parameter renames, which are not breaking by default
+func changedSeverity(changed analyzer.ChangedSignature) string {
+	if changed.Severity == "" && changed.ParamNamesOnly {
+		return changed.Level()
+	}
+	return changed.Severity
+}
+
+// deadCodeTag marks findings only unreachable project code uses
+func deadCodeTag(dead bool) string {
+	if dead {
+		return " [dead code only]"
+	}
+	return ""
+}
+
+// platformTag names the only platforms a finding occurs on
+func platformTag(platforms []string) string {
+	if len(platforms) > 0 {
+		return fmt.Sprintf(" [only on %s]", strings.Join(platforms, ", "))
+	}
+	return ""
+}
+
+// approximateTag marks findings used in packages that failed to load
+func approximateTag(approximate bool) string {
+	if approximate {
+		return " [approximate]"
+	}
+	return ""
+}
+
+// isApproximate reports whether any of the locations has incomplete type information
+func isApproximate(locations []analyzer.Location) bool {
+	for _, loc := range locations {
+		if loc.Approximate {
+			return true
+		}
+	}
+	return false
+}
+
+// usageNotes explains how a removal breaks type assertions, conversions,
+// embedding, and composite literals, which fail differently from plain references
+func usageNotes(removed analyzer.RemovedSymbol) []string {
+	counts := make(map[string]int)
+	for _, loc := range removed.UsedIn {
+		counts[loc.Kind]++
+	}
+
+	var notes []string
+	if c := removed.Conversion; c != nil {
+		kind := "function"
+		if c.ToMethod {
+			kind = "method"
+		}
+		notes = append(notes, fmt.Sprintf("Converted to %s %s: rewrite %s", kind, c.NewName, c.Rewrite))
+	}
+	if n := counts[analyzer.UsageAssertion]; n > 0 {
+		notes = append(notes, fmt.Sprintf("%d type assertion(s) to %s no longer compile: the assertion target is removed", n, removed.Name))
+	}
+	if n := counts[analyzer.UsageConversion]; n > 0 {
+		notes = append(notes, fmt.Sprintf("%d conversion(s) to %s are no longer valid: convert to a replacement type instead", n, removed.Name))
+	}
+	if n := counts[analyzer.UsageEmbedded]; n > 0 {
+		notes = append(notes, fmt.Sprintf("%d type(s) embed %s: the fields and methods promoted from it disappear too", n, removed.Name))
+	}
+	if n := counts[analyzer.UsageCompositeLit]; n > 0 {
+		notes = append(notes, fmt.Sprintf("%d composite literal(s) of %s must construct a replacement type", n, removed.Name))
+	}
+	if n := counts[analyzer.UsageKeyedField]; n > 0 {
+		notes = append(notes, fmt.Sprintf("%d keyed composite literal(s) set the removed field %s", n, removed.Name))
+	}
+	return notes
+}
+
+// interfaceNotes tells project implementations of a changed interface apart
+// from its callers: implementers need the added methods, callers lose the removed ones
+func interfaceNotes(iface analyzer.InterfaceChange) []string {
+	counts := make(map[string]int)
+	for _, loc := range iface.UsedIn {
+		counts[loc.Kind]++
+	}
+
+	var notes []string
+	byEmbedded := make(map[string][]string)
+	var embedded []string
+	for method, via := range iface.AddedVia {
+		if byEmbedded[via] == nil {
+			embedded = append(embedded, via)
+		}
+		byEmbedded[via] = append(byEmbedded[via], method)
+	}
+	sort.Strings(embedded)
+	for _, via := range embedded {
+		methods := byEmbedded[via]
+		sort.Strings(methods)
+		notes = append(notes, fmt.Sprintf("Embedding %s adds %s", via, strings.Join(methods, ", ")))
+	}
+	for _, impl := range iface.Implementations {
+		if impl.Broken() {
+			notes = append(notes, fmt.Sprintf("%s no longer implements %s: missing %s", impl.Before, iface.Name, strings.Join(impl.Missing, ", ")))
+		} else {
+			notes = append(notes, fmt.Sprintf("%s values no longer implement %s, only %s does: %s need(s) a pointer receiver", impl.Type, iface.Name, impl.After, strings.Join(impl.Missing, ", ")))
+		}
+	}
+	if n := counts[analyzer.UsageImplemented] + counts[analyzer.UsageEmbedded]; n > 0 && len(iface.AddedMethods) > 0 {
+		notes = append(notes, fmt.Sprintf("%d project type(s) implement or embed %s: add the new methods to them", n, iface.Name))
+	}
+	if n := len(iface.UsedIn) - counts[analyzer.UsageImplemented]; n > 0 && len(iface.RemovedMethods) > 0 {
+		notes = append(notes, fmt.Sprintf("%d use(s) of %s may call removed methods", n, iface.Name))
+	}
+	return notes
+}
+
+// writeSignature writes a signature after prefix, wrapped to the width in
+// verbose mode and truncated with "..." otherwise
+func writeSignature(b *strings.Builder, prefix, sig string, opts TextOptions) {
+	if opts.Verbose {
+		writeWrapped(b, prefix, sig, opts.Width)
+		return
+	}
+	b.WriteString(prefix + truncateSignature(sig, opts.Width-len([]rune(prefix))) + "\n")
+}
+
+// writeWrapped writes sig after prefix, continuing long signatures on lines
+// indented to line up with the first
+func writeWrapped(b *strings.Builder, prefix, sig string, width int) {
+	indent := strings.Repeat(" ", len([]rune(prefix)))
+	for i, line := range wrapSignature(sig, width-len([]rune(prefix))) {
+		if i == 0 {
+			b.WriteString(prefix + line + "\n")
+		} else {
+			b.WriteString(indent + line + "\n")
+		}
+	}
+}
+
+// minSignatureWidth keeps narrow terminals from shredding signatures
+const minSignatureWidth = 20
+
+// truncateSignature shortens sig to width runes, ending in "..."
+func truncateSignature(sig string, width int) string {
+	runes := []rune(sig)
+	if width <= 0 || len(runes) <= width {
+		return sig
+	}
+	if width < minSignatureWidth {
+		width = minSignatureWidth
+	}
+	return strings.TrimRight(string(runes[:width-3]), " ") + "..."
+}
+
+// wrapSignature breaks sig into lines of at most width runes, preferring to
+// break after commas and spaces so parameters and constraints stay intact.
+// Words longer than width are kept whole, and ANSI color escapes take no width.
+func wrapSignature(sig string, width int) []string {
+	if width <= 0 || visibleLen(sig) <= width {
+		return []string{sig}
+	}
+	if width < minSignatureWidth {
+		width = minSignatureWidth
+	}
+
+	// Split after each ", " and between other space-separated words
+	var words []string
+	start := 0
+	for i := 0; i < len(sig); i++ {
+		if sig[i] == ' ' {
+			words = append(words, sig[start:i+1])
+			start = i + 1
+		}
+	}
+	words = append(words, sig[start:])
+
+	var lines []string
+	var line strings.Builder
+	for _, word := range words {
+		if line.Len() > 0 && visibleLen(line.String()+strings.TrimRight(word, " ")) > width {
+			lines = append(lines, strings.TrimRight(line.String(), " "))
+			line.Reset()
+		}
+		line.WriteString(word)
+	}
+	if line.Len() > 0 {
+		lines = append(lines, strings.TrimRight(line.String(), " "))
+	}
+	return lines
+}
+
+// promotedTag notes the embedded type a changed method is promoted from
+func promotedTag(from string) string {
+	if from != "" {
+		return fmt.Sprintf(" (promoted from %s)", from)
+	}
+	return ""
+}
+
+// viaTag notes a finding in a type another module's type an alias
+// re-exports, which changed with that module
+func viaTag(module string) string {
+	if module != "" {
+		return fmt.Sprintf(" (via transitive module %s)", module)
+	}
+	return ""
+}
+
+// formatHint suggests adopting an added symbol in place of a project function
+func formatHint(hint analyzer.Hint) string {
+	return fmt.Sprintf("You may be able to adopt %s in place of %s at %s:%d (%s)",
+		hint.Symbol, hint.Replaces, hint.Location.File, hint.Location.Line, hint.Reason)
+}
+
+// formatTODOMatch points a project TODO to the added symbols that may
+// address it
+func formatTODOMatch(todo analyzer.TODOMatch) string {
+	return fmt.Sprintf("%s:%d: %q may be addressed by %s",
+		todo.Location.File, todo.Location.Line, todo.Text, strings.Join(todo.Symbols, ", "))
+}
+
+// formatDeadFunction suggests deleting an unreachable function instead of
+// migrating the uses it holds
+func formatDeadFunction(fn analyzer.DeadFunction) string {
+	return fmt.Sprintf("%s at %s:%d is unreachable and holds the only uses of %s",
+		fn.Name, fn.Location.File, fn.Location.Line, strings.Join(fn.Symbols, ", "))
+}
+
+// behaviorTag labels a signature change with the behavior change it implies
+func behaviorTag(behavior string) string {
+	if behavior != "" {
+		return fmt.Sprintf(" [behavior change: %s]", behavior)
+	}
+	return ""
+}
+
+// formatInstantiation describes whether the project's type arguments still
+// satisfy a generic symbol's new type parameters
+func formatInstantiation(inst analyzer.Instantiation) string {
+	verdict := "still compatible"
+	if !inst.Compatible() {
+		verdict = "breaks: " + inst.Error
+	}
+	return fmt.Sprintf("[%s]: %s (used in: %s)", strings.Join(inst.TypeArgs, ", "), verdict, formatLocations(inst.UsedIn, 3))
+}
+
+// formatParamChange describes a parameter or result change, such as
+// "New required param `ctx context.Context` at position 1"
+func formatParamChange(pc analyzer.ParamChange) string {
+	kind, label, adjective := "param", "Param", "required "
+	if pc.Result {
+		kind, label, adjective = "result", "Result", ""
+	} else if pc.Variadic {
+		adjective = "variadic "
+	}
+	typ := func(t string) string {
+		if pc.Variadic {
+			return "..." + strings.TrimPrefix(t, "[]")
+		}
+		return t
+	}
+	named := func(t string) string {
+		if pc.Name == "" {
+			return typ(t)
+		}
+		return pc.Name + " " + typ(t)
+	}
+
+	switch pc.Kind {
+	case analyzer.ParamAdded:
+		return fmt.Sprintf("New %s%s `%s` at position %d", adjective, kind, named(pc.NewType), pc.Position)
+	case analyzer.ParamRemoved:
+		return fmt.Sprintf("%s %d `%s` removed", label, pc.Position, named(pc.OldType))
+	default:
+		return fmt.Sprintf("%s %d changed from `%s` to `%s`", label, pc.Position, pc.OldType, typ(pc.NewType))
 	}
-	return fixes
 }
 
 // formatLocations formats a list of locations for display
@@ -169,27 +1203,17 @@ func formatLocations(locations []analyzer.Location, max int) string {
 			parts = append(parts, fmt.Sprintf("and %d more", len(locations)-max))
 			break
 		}
-		parts = append(parts, fmt.Sprintf("%s:%d", loc.File, loc.Line))
+		if loc.Kind != "" {
+			parts = append(parts, fmt.Sprintf("%s:%d (%s)", loc.File, loc.Line, loc.Kind))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s:%d", loc.File, loc.Line))
+		}
 	}
 
 	return strings.Join(parts, ", ")
 }
 
-// countAffectedLocations counts total number of affected code locations
+// countAffectedLocations counts affected code locations in stable APIs
 func countAffectedLocations(changes *analyzer.Diff) int {
-	count := 0
-
-	for _, removed := range changes.Removed {
-		count += len(removed.UsedIn)
-	}
-
-	for _, changed := range changes.Changed {
-		count += len(changed.UsedIn)
-	}
-
-	for _, iface := range changes.InterfaceChanges {
-		count += len(iface.UsedIn)
-	}
-
-	return count
+	return changes.AffectedLocations()
 }
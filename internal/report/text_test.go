@@ -1,6 +1,8 @@
 package report
 
 import (
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -87,8 +89,7 @@ func TestFormatText(t *testing.T) {
 				"BREAKING CHANGES",
 				"Changed Signatures",
 				"ParseConfig",
-				"func(string) error",
-				"func(string, ...Option) error",
+				"Diff: func(string{+, ...Option+}) error",
 				"config.go:23",
 			},
 		},
@@ -171,6 +172,1004 @@ func TestFormatText(t *testing.T) {
 				"github.com/unused/dep",
 			},
 		},
+		{
+			name: "replaced module",
+			result: &analyzer.Result{
+				Module:      "github.com/example/lib",
+				OldVersion:  "v1.2.0",
+				NewVersion:  "v1.4.0",
+				Replacement: &analyzer.Replacement{Path: "github.com/ourfork/lib", Version: "v1.3.0"},
+				Changes:     &analyzer.Diff{},
+			},
+			want: []string{
+				"go.mod replaces github.com/example/lib with github.com/ourfork/lib v1.3.0",
+				"compare the fork against github.com/example/lib v1.4.0",
+				"-ignore-replace",
+			},
+		},
+		{
+			name: "footprint",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes:    &analyzer.Diff{},
+				Footprint: &analyzer.Footprint{
+					OldSize:       2048,
+					NewSize:       1536,
+					OldPackages:   3,
+					NewPackages:   4,
+					PackagesAdded: []string{"github.com/example/lib/extra"},
+					ModulesAdded:  []string{"github.com/dep/new v1.0.0"},
+					SharedBumps: []analyzer.SharedBump{
+						{Path: "google.golang.org/grpc", ProjectVersion: "v1.58.0", OldVersion: "v1.57.0", NewVersion: "v1.60.0"},
+						{Path: "github.com/dep/new", ProjectVersion: "v0.9.0", NewVersion: "v1.0.0"},
+					},
+				},
+			},
+			want: []string{
+				"Footprint:",
+				"Download size: 2.0 KB -> 1.5 KB (-512 B)",
+				"Packages: 3 -> 4 (1 new)",
+				"Modules in the build: 1 added, 0 removed",
+				"+ github.com/dep/new v1.0.0",
+				"Modules you also require that the upgrade raises: 2",
+				"^ google.golang.org/grpc v1.58.0 -> v1.60.0 (the dependency requires v1.60.0 instead of v1.57.0)",
+				"^ github.com/dep/new v0.9.0 -> v1.0.0 (newly required by the upgrade)",
+			},
+		},
+		{
+			name: "binary impact",
+			result: &analyzer.Result{
+				Module:       "github.com/example/lib",
+				OldVersion:   "v1.0.0",
+				NewVersion:   "v2.0.0",
+				Changes:      &analyzer.Diff{},
+				BinaryImpact: &analyzer.BinaryImpact{Package: "./cmd/app", OldSize: 1024 * 1024, NewSize: 1024*1024 + 2048},
+			},
+			want: []string{
+				"Binary Size:",
+				"./cmd/app: 1.0 MB -> 1.0 MB (+2.0 KB)",
+			},
+		},
+		{
+			name: "benchmarks",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes:    &analyzer.Diff{},
+				Benchmarks: []analyzer.BenchmarkDelta{
+					{Name: "app.BenchmarkParse", OldNsPerOp: 100, NewNsPerOp: 150, DeltaPercent: 50, Regression: true},
+				},
+			},
+			want: []string{
+				"Benchmarks (ns/op):",
+				"app.BenchmarkParse: 100.0 -> 150.0 (+50.0%) REGRESSION",
+			},
+		},
+		{
+			name: "failing tests",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes:    &analyzer.Diff{},
+				TestFailures: []analyzer.TestFailure{
+					{Package: "example.com/app", Test: "TestParse"},
+					{Package: "example.com/app/broken"},
+				},
+			},
+			want: []string{
+				"BREAKING CHANGES",
+				"Failing Tests After Upgrade:",
+				"example.com/app.TestParse",
+				"example.com/app/broken (package failed)",
+			},
+		},
+		{
+			name: "unstable removal",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v1.1.0",
+				Changes: &analyzer.Diff{
+					Removed: []analyzer.RemovedSymbol{
+						{Name: "Fast", Type: "function", Unstable: true, UsedIn: []analyzer.Location{{File: "main.go", Line: 3}}},
+					},
+				},
+			},
+			want: []string{
+				"No breaking changes",
+				"1 change(s) affect unstable APIs",
+				"Fast (function) [unstable]",
+			},
+			wantNot: []string{
+				"BREAKING CHANGES",
+			},
+		},
+		{
+			name: "tool dependency",
+			result: &analyzer.Result{
+				Module:         "golang.org/x/tools",
+				OldVersion:     "v0.15.0",
+				NewVersion:     "v0.16.0",
+				ToolDependency: true,
+				Changes:        &analyzer.Diff{},
+				ModuleChanges: &analyzer.ModuleChanges{
+					OldGoVersion: "1.18",
+					NewGoVersion: "1.21",
+					Added:        []string{"golang.org/x/sync v0.5.0"},
+					Upgraded:     []string{"golang.org/x/mod v0.13.0 -> v0.14.0"},
+				},
+			},
+			want: []string{
+				"No breaking changes",
+				"tool dependency pinned in tools.go",
+				"Tool Module Changes:",
+				"go directive: 1.18 -> 1.21",
+				"+ golang.org/x/sync v0.5.0",
+				"~ golang.org/x/mod v0.13.0 -> v0.14.0",
+			},
+		},
+		{
+			name: "generated package churn",
+			result: &analyzer.Result{
+				Module:     "github.com/example/sdk",
+				OldVersion: "v1.0.0",
+				NewVersion: "v1.1.0",
+				Changes: &analyzer.Diff{
+					Generated: []analyzer.GeneratedGroup{
+						{Package: "github.com/example/sdk/service/s3", Changes: 312},
+					},
+				},
+			},
+			want: []string{
+				"Generated Packages:",
+				"312 change(s) in generated package github.com/example/sdk/service/s3, 0 used by you",
+			},
+		},
+		{
+			name: "moved symbol",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes: &analyzer.Diff{
+					Moved: []analyzer.MovedSymbol{
+						{Name: "Parse", NewName: "Parse", Type: "function", OldPackage: "github.com/example/lib/util", NewPackage: "github.com/example/lib/parser", UsedIn: []analyzer.Location{{File: "main.go", Line: 7}}},
+					},
+				},
+			},
+			want: []string{
+				"BREAKING CHANGES",
+				"Moved Symbols:",
+				"Parse (function) moved to github.com/example/lib/parser.Parse (was github.com/example/lib/util) (used in: main.go:7)",
+				"Import Parse from github.com/example/lib/parser at main.go:7",
+			},
+		},
+		{
+			name: "compatibility shims",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes:    &analyzer.Diff{},
+				Shims: &analyzer.ShimFile{
+					Path:      "/project/compat/semver_audit_shims.go",
+					Functions: []string{"FetchCompat"},
+					Skipped:   []string{"Client.Do: methods cannot be shimmed"},
+				},
+			},
+			want: []string{
+				"Compatibility Shims:",
+				"Wrote 1 adapter(s) to /project/compat/semver_audit_shims.go",
+				"+ FetchCompat",
+				"skipped Client.Do: methods cannot be shimmed",
+			},
+		},
+		{
+			name: "dry run shims",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes:    &analyzer.Diff{},
+				Shims: &analyzer.ShimFile{
+					Path:      "/project/compat/semver_audit_shims.go",
+					Functions: []string{"FetchCompat"},
+					Diff:      analyzer.FileDiff{File: "compat/semver_audit_shims.go", Diff: "--- a/compat/semver_audit_shims.go\n+++ b/compat/semver_audit_shims.go\n@@ -0,0 +1 @@\n+package compat\n"},
+					DryRun:    true,
+				},
+			},
+			want: []string{
+				"Would write 1 adapter(s) to /project/compat/semver_audit_shims.go (dry run, nothing written)",
+				"Proposed Shims:\n--- a/compat/semver_audit_shims.go\n",
+			},
+		},
+		{
+			name: "risk score",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes:    &analyzer.Diff{},
+				Risk:       &analyzer.Risk{Score: 34, Level: "medium", Audits: 4, BreakingRate: 0.25, APISurface: 10, UsedSymbols: 2, UsageSites: 25},
+			},
+			want: []string{
+				"Risk:",
+				"34/100 (medium): 25% of 4 audit(s) breaking, 2 of 10 exported symbol(s) used at 25 site(s)",
+			},
+		},
+		{
+			name: "removed assertion and conversion targets",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes: &analyzer.Diff{
+					Removed: []analyzer.RemovedSymbol{
+						{Name: "Handler", Type: "interface", UsedIn: []analyzer.Location{{File: "main.go", Line: 6, Kind: analyzer.UsageAssertion}}},
+						{Name: "Config", Type: "type", UsedIn: []analyzer.Location{{File: "main.go", Line: 7, Kind: analyzer.UsageConversion}}},
+					},
+				},
+			},
+			want: []string{
+				"Handler (interface) (used in: main.go:6 (assertion))",
+				"1 type assertion(s) to Handler no longer compile: the assertion target is removed",
+				"1 conversion(s) to Config are no longer valid: convert to a replacement type instead",
+			},
+		},
+		{
+			name: "copies of the dependency",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes:    &analyzer.Diff{},
+				Copies:     []analyzer.DependencyCopy{{Dir: "third_party/lib", Reason: "go.mod declares module github.com/example/lib", References: 4}},
+			},
+			want: []string{
+				"Copies of the Dependency (excluded from findings):",
+				"third_party/lib (go.mod declares module github.com/example/lib): 4 reference(s) excluded",
+			},
+		},
+		{
+			name: "doc changes",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v1.1.0",
+				Changes:    &analyzer.Diff{},
+				DocChanges: []analyzer.DocChange{
+					{
+						Name:    "Get",
+						Reasons: []string{analyzer.DocErrorSemantic},
+						Removed: []string{"It returns nil if the key is missing."},
+						Added:   []string{"It returns ErrNotFound if the key is missing."},
+						UsedIn:  []analyzer.Location{{File: "cache.go", Line: 12}},
+					},
+				},
+			},
+			want: []string{
+				"Documentation Changes:",
+				"  - Get: error or panic behavior wording changed",
+				"    - It returns nil if the key is missing.",
+				"    + It returns ErrNotFound if the key is missing.",
+				"    Used in: cache.go:12",
+			},
+		},
+		{
+			name: "migration examples",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes:    &analyzer.Diff{},
+				Examples: []analyzer.Example{
+					{Symbol: "Client.Do", Name: "ExampleClient_Do", Dir: ".", Code: "c := lib.New()\nfmt.Println(c.Do(ctx))", Output: "ok\n"},
+					{Symbol: "Open", Name: "main", Dir: "_examples/basic", Code: "lib.Open(\"x\", 0)"},
+				},
+			},
+			want: []string{
+				"Migration Examples:",
+				"  - Client.Do: ExampleClient_Do\n      c := lib.New()\n      fmt.Println(c.Do(ctx))\n      // Output:\n      // ok\n",
+				"  - Open: main in _examples/basic\n",
+			},
+		},
+		{
+			name: "warnings",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v1.1.0",
+				Changes:    &analyzer.Diff{},
+				Warnings: []analyzer.Warning{
+					{Code: analyzer.WarnPartialLoad, Message: "1 package(s) of github.com/example/lib@v1.1.0 loaded with errors"},
+				},
+			},
+			want: []string{
+				"Warnings:",
+				"  - [partial-load] 1 package(s) of github.com/example/lib@v1.1.0 loaded with errors",
+			},
+		},
+		{
+			name: "stopped early",
+			result: &analyzer.Result{
+				Module:       "github.com/example/lib",
+				OldVersion:   "v1.0.0",
+				NewVersion:   "v2.0.0",
+				StoppedEarly: true,
+				Changes: &analyzer.Diff{
+					Removed: []analyzer.RemovedSymbol{{Name: "Open", Type: "function", UsedIn: []analyzer.Location{{File: "main.go", Line: 4}}}},
+				},
+			},
+			want: []string{
+				"stopped at the first breaking change (-fail-fast)",
+				"BREAKING CHANGES DETECTED",
+			},
+		},
+		{
+			name: "usage roles of removed symbols and changed interfaces",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes: &analyzer.Diff{
+					Removed: []analyzer.RemovedSymbol{
+						{Name: "Base", Type: "type", UsedIn: []analyzer.Location{{File: "server.go", Line: 5, Kind: analyzer.UsageEmbedded}, {File: "server.go", Line: 9, Kind: analyzer.UsageCompositeLit}}},
+					},
+					InterfaceChanges: []analyzer.InterfaceChange{
+						{Name: "Handler", AddedMethods: []string{"Close() error"}, UsedIn: []analyzer.Location{{File: "server.go", Line: 7, Kind: analyzer.UsageImplemented}}},
+					},
+				},
+			},
+			want: []string{
+				"server.go:5 (embedded), server.go:9 (composite literal)",
+				"1 type(s) embed Base: the fields and methods promoted from it disappear too",
+				"1 composite literal(s) of Base must construct a replacement type",
+				"Used in: server.go:7 (implemented)",
+				"1 project type(s) implement or embed Handler: add the new methods to them",
+			},
+			wantNot: []string{"may call removed methods"},
+		},
+		{
+			name: "below a required minimum version",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.2.0",
+				NewVersion: "v1.5.0",
+				Changes:    &analyzer.Diff{},
+				Floor:      &analyzer.Floor{Required: "v1.5.0", Current: "v1.2.0", Gap: "3 minor version(s) behind"},
+			},
+			want: []string{
+				"Checking minimum version: github.com/example/lib >= v1.5.0",
+				"✗ v1.2.0 is below the required minimum v1.5.0 (3 minor version(s) behind); breaking cost of upgrading to it:",
+			},
+			wantNot: []string{"Analyzing upgrade"},
+		},
+		{
+			name: "analysis past load errors",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes: &analyzer.Diff{
+					Removed: []analyzer.RemovedSymbol{
+						{Name: "Gone", Type: "function", UsedIn: []analyzer.Location{{File: "broken/b.go", Line: 3, Approximate: true}}},
+					},
+				},
+				LoadErrors: []analyzer.LoadError{{Package: "example.com/app/broken", Position: "broken/a.go:7:1", Message: "missing return"}},
+			},
+			want: []string{
+				"  - Gone (function) [approximate] (used in: broken/b.go:3)",
+				"Load Errors (findings marked [approximate] may be incomplete):\n  - example.com/app/broken: broken/a.go:7:1: missing return",
+			},
+		},
+		{
+			name: "todos",
+			result: &analyzer.Result{
+				Module:     "github.com/jackc/pgx/v5",
+				OldVersion: "v5.4.0",
+				NewVersion: "v5.5.0",
+				Changes:    &analyzer.Diff{},
+				TODOs: []analyzer.TODOMatch{
+					{Text: "TODO: send inserts as one pgx batch", Location: analyzer.Location{File: "store.go", Line: 14}, Symbols: []string{"Batch", "Conn.SendBatch"}},
+				},
+			},
+			want: []string{
+				"TODOs the New Version May Address:\n  - store.go:14: \"TODO: send inserts as one pgx batch\" may be addressed by Batch, Conn.SendBatch",
+			},
+		},
+		{
+			name: "adoption hints",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v1.1.0",
+				Changes:    &analyzer.Diff{},
+				Hints: []analyzer.Hint{
+					{Symbol: "Retry", Replaces: "retryCall", Location: analyzer.Location{File: "client.go", Line: 12}, Reason: analyzer.HintMatchingSignature},
+				},
+			},
+			want: []string{
+				"Adoption Hints:\n  - You may be able to adopt Retry in place of retryCall at client.go:12 (similar name and identical signature)",
+			},
+		},
+		{
+			name: "dead code",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes: &analyzer.Diff{
+					Removed: []analyzer.RemovedSymbol{
+						{Name: "Old", Type: "function", UsedIn: []analyzer.Location{{File: "sync.go", Line: 19}}, DeadCode: true},
+					},
+				},
+				DeadCode: []analyzer.DeadFunction{
+					{Name: "oldSync", Package: "example.com/app", Location: analyzer.Location{File: "sync.go", Line: 18}, Symbols: []string{"Old"}},
+				},
+			},
+			want: []string{
+				"  - Old (function) [dead code only]",
+				"Dead Code (delete instead of migrating):\n  - oldSync at sync.go:18 is unreachable and holds the only uses of Old",
+			},
+		},
+		{
+			name: "affected packages",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes: &analyzer.Diff{
+					Removed: []analyzer.RemovedSymbol{
+						{Name: "Old", Type: "function", UsedIn: []analyzer.Location{{File: "/src/app/store/store.go", Line: 3}}},
+					},
+				},
+				ImportGraph: &analyzer.ImportGraph{
+					Nodes: []analyzer.GraphNode{
+						{Package: "example.com/app/api", Dir: "/src/app/api", Imports: []string{"example.com/app/store"}},
+						{Package: "example.com/app/store", Dir: "/src/app/store", Imports: []string{"github.com/example/lib"}, Direct: true},
+					},
+					ModulePackages: []string{"github.com/example/lib"},
+				},
+			},
+			want: []string{
+				"Affected Packages:\n",
+				"  - example.com/app/api (via example.com/app/store; no affected uses)\n",
+				"  - example.com/app/store (imports the module directly; 1 affected use(s), worst error)\n",
+			},
+		},
+		{
+			name: "main-only module",
+			result: &analyzer.Result{
+				Module:        "example.com/tool",
+				OldVersion:    "v1.0.0",
+				NewVersion:    "v1.1.0",
+				MainOnly:      true,
+				Changes:       &analyzer.Diff{},
+				ModuleChanges: &analyzer.ModuleChanges{OldGoVersion: "1.20", NewGoVersion: "1.22"},
+			},
+			want: []string{
+				"Note: example.com/tool only contains commands (package main), so it has no API to break",
+				"Tool Module Changes:\n",
+			},
+			wantNot: []string{"No breaking changes detected"},
+		},
+		{
+			name: "platform-specific findings",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes: &analyzer.Diff{
+					Removed: []analyzer.RemovedSymbol{
+						{Name: "Watch", Type: "function", UsedIn: []analyzer.Location{{File: "main.go", Line: 3}}, Platforms: []string{"windows/amd64"}},
+					},
+				},
+			},
+			want: []string{"  - Watch (function) [only on windows/amd64]"},
+		},
+		{
+			name: "generic instantiations",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v1.1.0",
+				Changes: &analyzer.Diff{
+					Changed: []analyzer.ChangedSignature{
+						{
+							Name:         "Set",
+							OldSignature: "type Set[T any]",
+							NewSignature: "type Set[T comparable]",
+							UsedIn:       []analyzer.Location{{File: "main.go", Line: 4}},
+							Instantiations: []analyzer.Instantiation{
+								{TypeArgs: []string{"[]byte"}, UsedIn: []analyzer.Location{{File: "main.go", Line: 4}}, Error: "[]byte does not satisfy comparable"},
+								{TypeArgs: []string{"string"}, UsedIn: []analyzer.Location{{File: "main.go", Line: 9}}},
+							},
+						},
+					},
+				},
+			},
+			want: []string{
+				"    Instantiated as [[]byte]: breaks: []byte does not satisfy comparable (used in: main.go:4)",
+				"    Instantiated as [string]: still compatible (used in: main.go:9)",
+			},
+		},
+		{
+			name: "removed field set in keyed literals",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes: &analyzer.Diff{
+					Removed: []analyzer.RemovedSymbol{
+						{Name: "Config.Value", Type: "field", UsedIn: []analyzer.Location{{File: "main.go", Line: 7, Kind: analyzer.UsageKeyedField}}},
+					},
+				},
+			},
+			want: []string{
+				"  - Config.Value (field)",
+				"1 keyed composite literal(s) set the removed field Config.Value",
+			},
+		},
+		{
+			name: "function converted to method",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes: &analyzer.Diff{
+					Removed: []analyzer.RemovedSymbol{
+						{
+							Name:       "Close",
+							Type:       "function",
+							UsedIn:     []analyzer.Location{{File: "main.go", Line: 12}},
+							Conversion: &analyzer.Conversion{NewName: "Conn.Close", ToMethod: true, Rewrite: "lib.Close(c) -> c.Close()"},
+						},
+					},
+				},
+			},
+			want: []string{
+				"    Converted to method Conn.Close: rewrite lib.Close(c) -> c.Close()",
+				"Rewrite calls to Close as Conn.Close at main.go:12",
+			},
+		},
+		{
+			name: "methods added by an embedded interface",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes: &analyzer.Diff{
+					InterfaceChanges: []analyzer.InterfaceChange{
+						{
+							Name:         "Handler",
+							AddedMethods: []string{"func (github.com/example/lib.Handler).Close() error"},
+							AddedVia:     map[string]string{"Close": "io.Closer"},
+							UsedIn:       []analyzer.Location{{File: "main.go", Line: 8}},
+						},
+					},
+				},
+			},
+			want: []string{"    Embedding io.Closer adds Close"},
+		},
+		{
+			name: "project implementations of a changed interface",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes: &analyzer.Diff{
+					InterfaceChanges: []analyzer.InterfaceChange{
+						{
+							Name:         "Handler",
+							AddedMethods: []string{"func (github.com/example/lib.Handler).Close() error"},
+							UsedIn:       []analyzer.Location{{File: "main.go", Line: 8}},
+							Implementations: []analyzer.Implementation{
+								{Type: "app.Server", Before: "*app.Server", Missing: []string{"Close"}},
+								{Type: "app.Mixed", Before: "app.Mixed", After: "*app.Mixed", Missing: []string{"Close"}},
+							},
+						},
+					},
+				},
+			},
+			want: []string{
+				"    *app.Server no longer implements Handler: missing Close",
+				"    app.Mixed values no longer implement Handler, only *app.Mixed does: Close need(s) a pointer receiver",
+			},
+		},
+		{
+			name: "summary counts per category and severity",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes: &analyzer.Diff{
+					Removed: []analyzer.RemovedSymbol{
+						{Name: "Open", Type: "function", UsedIn: []analyzer.Location{{File: "main.go", Line: 3}}},
+						{Name: "Fast", Type: "function", Unstable: true},
+					},
+					InterfaceChanges: []analyzer.InterfaceChange{{Name: "Handler", Severity: analyzer.SeverityInfo}},
+				},
+				DocChanges: []analyzer.DocChange{{Name: "Close", Reasons: []string{analyzer.DocDeprecated}}},
+			},
+			want: []string{
+				"Analyzing upgrade: github.com/example/lib v1.0.0 -> v2.0.0\n" +
+					"Findings: removed: 2, changed: 0, interface: 1, moved: 0, deprecated: 1, warnings: 0\n" +
+					"Severity: error: 1, warning: 1, info: 1\n\n",
+			},
+		},
+		{
+			name: "diffed from a version other than go.mod's",
+			result: &analyzer.Result{
+				Module:          "github.com/example/lib",
+				OldVersion:      "v1.2.0",
+				NewVersion:      "v1.3.0",
+				RequiredVersion: "v1.2.1-0.20240101000000-abcdef123456",
+				Changes:         &analyzer.Diff{},
+			},
+			want: []string{
+				"Analyzing upgrade: github.com/example/lib v1.2.0 -> v1.3.0",
+				"Diffing from v1.2.0 as requested with -from, instead of v1.2.1-0.20240101000000-abcdef123456 required by go.mod.\n\n",
+			},
+		},
+		{
+			name: "retracted versions",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.2.0",
+				NewVersion: "v1.4.0",
+				Retractions: []analyzer.Retraction{
+					{Version: "v1.4.0", Rationale: "Publishes a broken Close."},
+					{Version: "v1.2.0", Current: true},
+				},
+				Changes: &analyzer.Diff{},
+			},
+			want: []string{
+				"⚠️  RETRACTED: github.com/example/lib v1.4.0, the upgrade target, was retracted by its authors: Publishes a broken Close.\n\n",
+				"⚠️  RETRACTED: github.com/example/lib v1.2.0, the version the project requires now, was retracted by its authors.\n\n",
+			},
+		},
+		{
+			name: "deprecated module with a successor",
+			result: &analyzer.Result{
+				Module:      "github.com/example/lib",
+				OldVersion:  "v1.2.0",
+				NewVersion:  "v1.3.0",
+				Deprecation: &analyzer.Deprecation{Message: "use github.com/example/lib/v2 instead.", Successor: "github.com/example/lib/v2"},
+				Changes:     &analyzer.Diff{},
+			},
+			want: []string{
+				"⚠️  DEPRECATED: github.com/example/lib is deprecated by its authors: use github.com/example/lib/v2 instead.\n" +
+					"   To audit switching to github.com/example/lib/v2 instead, run: go-semver-audit -new -upgrade github.com/example/lib/v2@latest\n\n",
+			},
+		},
+		{
+			name: "checksum database bypassed",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.2.0",
+				NewVersion: "v1.3.0",
+				Changes:    &analyzer.Diff{},
+				SumDB:      &analyzer.SumDBStatus{Version: "v1.3.0", Sum: "h1:abc=", Bypass: "GOPRIVATE=github.com/example"},
+			},
+			want: []string{
+				"Supply Chain:\n  ⚠️  github.com/example/lib v1.3.0 (h1:abc=) was not verified against a checksum database: GOPRIVATE=github.com/example bypasses it.\n",
+			},
+		},
+		{
+			name: "usage only when the API diff is unavailable",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.2.0",
+				NewVersion: "v1.3.0",
+				Changes:    &analyzer.Diff{},
+				UsageOnly: &analyzer.UsageOnly{
+					Reason: "failed to load new API: 410 Gone",
+					Symbols: []analyzer.SymbolUses{
+						{Name: "Parse", UsedIn: []analyzer.Location{{File: "main.go", Line: 12}}},
+					},
+				},
+			},
+			want: []string{
+				"⚠️  API DIFF UNAVAILABLE: failed to load new API: 410 Gone. Breaking changes could not be checked, so every use of the module below is at risk.\n\n",
+				"Symbols at Risk (API diff unavailable):\n  - Parse (used in: main.go:12)\n\n",
+			},
+			wantNot: []string{"No breaking changes detected"},
+		},
+		{
+			name: "coupling in footprint",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.2.0",
+				NewVersion: "v1.3.0",
+				Changes:    &analyzer.Diff{},
+				Coupling:   &analyzer.Coupling{Symbols: 4, Packages: 2, ProjectPackages: 3, Files: 5, Lines: 9},
+			},
+			want: []string{
+				"Footprint:\n  Coupling: 4 symbol(s) from 2 package(s), used on 9 line(s) in 5 file(s) across 3 project package(s)\n\n",
+			},
+		},
+		{
+			name: "adapter advisory",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.2.0",
+				NewVersion: "v1.3.0",
+				Changes:    &analyzer.Diff{},
+				Coupling:   &analyzer.Coupling{Symbols: 4, Packages: 2, ProjectPackages: 7, Files: 9, Lines: 30},
+				Adapter: &analyzer.AdapterAdvice{
+					Threshold: 5,
+					Sites:     []analyzer.SymbolUses{{Name: "Parse", UsedIn: []analyzer.Location{{File: "a/a.go", Line: 3}, {File: "b/b.go", Line: 8}}}},
+				},
+			},
+			want: []string{
+				"Advisory:\n  github.com/example/lib is used from 7 project packages (more than 5). Consider wrapping it in an internal adapter package, starting with its most used symbols:\n" +
+					"    - Parse (used in: a/a.go:3, b/b.go:8)\n\n",
+			},
+		},
+		{
+			name: "parameter changes",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.2.0",
+				NewVersion: "v1.3.0",
+				Changes: &analyzer.Diff{
+					Changed: []analyzer.ChangedSignature{{
+						Name:         "Fetch",
+						OldSignature: "func(id string, n int) []byte",
+						NewSignature: "func(ctx context.Context, id string, n int64, opts ...Option) ([]byte, error)",
+						ParamChanges: []analyzer.ParamChange{
+							{Kind: analyzer.ParamAdded, Position: 1, Name: "ctx", NewType: "context.Context"},
+							{Kind: analyzer.ParamRetyped, Position: 3, Name: "n", OldType: "int", NewType: "int64"},
+							{Kind: analyzer.ParamAdded, Position: 4, Name: "opts", NewType: "[]Option", Variadic: true},
+							{Kind: analyzer.ParamAdded, Result: true, Position: 2, NewType: "error"},
+							{Kind: analyzer.ParamRemoved, Result: true, Position: 3, OldType: "bool"},
+						},
+					}},
+				},
+			},
+			want: []string{
+				"  - Fetch\n" +
+					"    New required param `ctx context.Context` at position 1\n" +
+					"    Param 3 changed from `int` to `int64`\n" +
+					"    New variadic param `opts ...Option` at position 4\n" +
+					"    New result `error` at position 2\n" +
+					"    Result 3 `bool` removed\n",
+			},
+		},
+		{
+			name: "automatic fixes",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.2.0",
+				NewVersion: "v1.3.0",
+				Changes:    &analyzer.Diff{},
+				Fixes: &analyzer.FixReport{
+					Files:   []string{"main.go"},
+					Fixed:   []analyzer.FixSite{{Symbol: "Fetch", Location: analyzer.Location{File: "main.go", Line: 6}, Fix: "pass context.TODO()"}},
+					Skipped: []analyzer.FixSite{{Symbol: "Fetch", Location: analyzer.Location{File: "main.go", Line: 7}, Reason: "not a call"}},
+				},
+			},
+			want: []string{
+				"Automatic Fixes:\n" +
+					"  Rewrote 1 call site(s) in 1 file(s)\n" +
+					"  + main.go:6: Fetch: pass context.TODO()\n" +
+					"  skipped main.go:7: Fetch: not a call\n",
+			},
+		},
+		{
+			name: "fixes tested after the rewrite",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.2.0",
+				NewVersion: "v1.3.0",
+				Changes:    &analyzer.Diff{},
+				Fixes: &analyzer.FixReport{
+					Files:        []string{"main.go"},
+					Fixed:        []analyzer.FixSite{{Symbol: "Fetch", Location: analyzer.Location{File: "main.go", Line: 6}, Fix: "pass context.TODO()"}},
+					Tested:       true,
+					TestFailures: []analyzer.TestFailure{{Package: "example.com/app", Test: "TestFetch"}},
+				},
+			},
+			want: []string{
+				"Automatic Fixes:\n" +
+					"  Rewrote 1 call site(s) in 1 file(s)\n" +
+					"  + main.go:6: Fetch: pass context.TODO()\n" +
+					"  still failing after the rewrite: example.com/app.TestFetch\n",
+			},
+		},
+		{
+			name: "dry run fixes",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.2.0",
+				NewVersion: "v1.3.0",
+				Changes:    &analyzer.Diff{},
+				Fixes: &analyzer.FixReport{
+					DryRun: true,
+					Files:  []string{"main.go"},
+					Fixed:  []analyzer.FixSite{{Symbol: "Fetch", Location: analyzer.Location{File: "main.go", Line: 6}, Fix: "pass context.TODO()"}},
+					Diffs:  []analyzer.FileDiff{{File: "main.go", Diff: "--- a/main.go\n+++ b/main.go\n@@ -6 +6 @@\n-\tlib.Fetch()\n+\tlib.Fetch(context.TODO())\n"}},
+				},
+			},
+			want: []string{
+				"Automatic Fixes:\n" +
+					"  Would rewrite 1 call site(s) in 1 file(s) (dry run, nothing written)\n" +
+					"  + main.go:6: Fetch: pass context.TODO()\n\n" +
+					"Proposed Changes:\n" +
+					"--- a/main.go\n+++ b/main.go\n@@ -6 +6 @@\n-\tlib.Fetch()\n+\tlib.Fetch(context.TODO())\n\n",
+			},
+		},
+		{
+			name: "panic to error migration",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v2.0.0",
+				Changes: &analyzer.Diff{
+					Changed: []analyzer.ChangedSignature{
+						{Name: "MustParse", OldSignature: "func(string) int", NewSignature: "func(string) (int, error)", UsedIn: []analyzer.Location{{File: "main.go", Line: 8}}, BehaviorChange: analyzer.BehaviorPanicToError},
+					},
+				},
+			},
+			want: []string{
+				"  - MustParse [behavior change: panic-to-error]\n",
+				"Update call to MustParse at main.go:8 and handle the error it returns instead of panicking",
+			},
+		},
+		{
+			name: "severity overrides",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v1.1.0",
+				Changes: &analyzer.Diff{
+					Changed: []analyzer.ChangedSignature{
+						{Name: "Open", OldSignature: "func(name string)", NewSignature: "func(path string)", UsedIn: []analyzer.Location{{File: "main.go", Line: 5}}, ParamNamesOnly: true, Severity: analyzer.SeverityInfo},
+					},
+				},
+			},
+			want: []string{
+				"✓ No breaking changes detected.",
+				"  - Open [info]\n",
+			},
+		},
+		{
+			name: "param rename is info by default",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v1.1.0",
+				Changes: &analyzer.Diff{
+					Changed: []analyzer.ChangedSignature{
+						{Name: "Open", OldSignature: "func(name string)", NewSignature: "func(path string)", UsedIn: []analyzer.Location{{File: "main.go", Line: 5}}, ParamNamesOnly: true},
+					},
+				},
+			},
+			want: []string{
+				"✓ No breaking changes detected.",
+				"  - Open [info]\n",
+			},
+		},
+		{
+			name: "added symbols grouped",
+			result: &analyzer.Result{
+				Module:     "github.com/jackc/pgx/v5",
+				OldVersion: "v5.4.0",
+				NewVersion: "v5.5.0",
+				Changes: &analyzer.Diff{
+					Added: []analyzer.AddedSymbol{
+						{Name: "Conn.SendBatch", Type: "function", Package: "github.com/jackc/pgx/v5"},
+						{Name: "Batch", Type: "type", Package: "github.com/jackc/pgx/v5"},
+						{Name: "Batch.Queue", Type: "function", Package: "github.com/jackc/pgx/v5"},
+						{Name: "Batch.Len", Type: "function", Package: "github.com/jackc/pgx/v5"},
+						{Name: "Conn.Ping", Type: "function", Package: "github.com/jackc/pgx/v5"},
+						{Name: "Connect", Type: "function", Package: "github.com/jackc/pgx/v5"},
+						{Name: "Config.Tracer", Type: "field", Package: "github.com/jackc/pgx/v5"},
+						{Name: "Rows", Type: "interface", Package: "github.com/jackc/pgx/v5/pgconn"},
+						{Name: "TextFormatCode", Type: "const", Package: "github.com/jackc/pgx/v5/pgconn"},
+					},
+				},
+			},
+			verbose: true,
+			want: []string{
+				"  github.com/jackc/pgx/v5:\n" +
+					"    Types:\n      + Batch (with Len, Queue)\n" +
+					"    Functions:\n      + Connect\n" +
+					"    Methods on Existing Types:\n      + Conn: Ping, SendBatch\n" +
+					"    Fields:\n      + Config.Tracer\n" +
+					"  github.com/jackc/pgx/v5/pgconn:\n" +
+					"    Interfaces:\n      + Rows\n" +
+					"    Constants:\n      + TextFormatCode\n",
+			},
+			wantNot: []string{
+				"+ Batch.Queue",
+				"(function)",
+			},
+		},
+		{
+			name: "toolchain added symbols drop their package",
+			result: &analyzer.Result{
+				Module:     "go",
+				OldVersion: "1.21",
+				NewVersion: "1.22",
+				Changes: &analyzer.Diff{
+					Added: []analyzer.AddedSymbol{
+						{Name: "slices.Concat", Type: "function", Package: "slices"},
+						{Name: "net/http.Request.PathValue", Type: "function", Package: "net/http"},
+					},
+				},
+			},
+			verbose: true,
+			want: []string{
+				"  net/http:\n    Methods on Existing Types:\n      + Request: PathValue\n",
+				"  slices:\n    Functions:\n      + Concat\n",
+			},
+		},
+		{
+			name: "re-exported alias",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib",
+				OldVersion: "v1.0.0",
+				NewVersion: "v1.1.0",
+				Changes: &analyzer.Diff{
+					Changed: []analyzer.ChangedSignature{
+						{Name: "Logger.Info", OldSignature: "func(msg string)", NewSignature: "func(msg string, fields ...any)",
+							ViaModule: "go.uber.org/zap", UsedIn: []analyzer.Location{{File: "main.go", Line: 5}}},
+					},
+				},
+			},
+			want: []string{"  - Logger.Info (via transitive module go.uber.org/zap)\n"},
+		},
+		{
+			name: "duplicate majors",
+			result: &analyzer.Result{
+				Module:     "github.com/example/lib/v2",
+				OldVersion: "v2.0.0",
+				NewVersion: "v2.1.0",
+				Changes:    &analyzer.Diff{},
+				DuplicateMajors: []analyzer.DuplicateMajor{
+					{Module: "github.com/example/lib", Version: "v1.8.0", Importers: []string{"example.com/app/db", "example.com/app/web"}},
+					{Module: "github.com/example/lib/v3", Version: "v3.0.0"},
+				},
+			},
+			want: []string{
+				"⚠️  DUPLICATE MAJOR: github.com/example/lib v1.8.0 stays in the module graph next to github.com/example/lib/v2, still imported by example.com/app/db, example.com/app/web\n",
+				"⚠️  DUPLICATE MAJOR: github.com/example/lib/v3 v3.0.0 stays in the module graph next to github.com/example/lib/v2, required by other dependencies.\n",
+			},
+		},
+		{
+			name: "new dependency",
+			result: &analyzer.Result{
+				Module:        "github.com/example/lib",
+				NewVersion:    "v1.0.0",
+				NewDependency: true,
+				Changes: &analyzer.Diff{
+					Added: []analyzer.AddedSymbol{
+						{Name: "NewFunc", Type: "function"},
+					},
+				},
+				Requirements: []analyzer.Requirement{
+					{Path: "github.com/dep/a", Version: "v1.2.0", ProjectVersion: "v1.1.0"},
+					{Path: "github.com/dep/b", Version: "v0.1.0"},
+				},
+			},
+			verbose: false,
+			want: []string{
+				"Analyzing new dependency: github.com/example/lib v1.0.0",
+				"API You Would Adopt:",
+				"  Functions:\n    + NewFunc\n",
+				"Required Modules:",
+				"github.com/dep/a v1.2.0 (your project requires v1.1.0)",
+				"github.com/dep/b v0.1.0 (new to your project)",
+			},
+			wantNot: []string{
+				"Analyzing upgrade",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -269,3 +1268,294 @@ func TestCountAffectedLocations(t *testing.T) {
 		t.Errorf("countAffectedLocations() = %d, want %d", got, want)
 	}
 }
+
+func TestSummarizeFixesRanksByUsage(t *testing.T) {
+	changes := &analyzer.Diff{
+		Removed: []analyzer.RemovedSymbol{
+			{Name: "Once", Type: "function", UsedIn: []analyzer.Location{{File: "a.go", Line: 1}}},
+			{Name: "Fast", Type: "function", Unstable: true, UsedIn: []analyzer.Location{
+				{File: "a.go", Line: 2}, {File: "b.go", Line: 2}, {File: "c.go", Line: 2}, {File: "d.go", Line: 2},
+			}},
+		},
+		Changed: []analyzer.ChangedSignature{
+			{Name: "Parse", UsedIn: []analyzer.Location{{File: "a.go", Line: 3}, {File: "b.go", Line: 3}, {File: "c.go", Line: 3}}},
+			{Name: "Open", UsedIn: []analyzer.Location{{File: "a.go", Line: 4}, {File: "a.go", Line: 5}}},
+			{Name: "Dial", UsedIn: []analyzer.Location{{File: "a.go", Line: 6}, {File: "b.go", Line: 6}}},
+		},
+	}
+
+	got := summarizeFixes(changes, 3)
+	want := []string{
+		"Update call to Parse at a.go:3, and 2 more",
+		"Update call to Dial at a.go:6, and 1 more",
+		"Update call to Open at a.go:4, and 1 more",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("summarizeFixes() = %q, want %q", got, want)
+	}
+
+	if got := summarizeFixes(changes, 0); len(got) != 0 {
+		t.Fatalf("summarizeFixes(max=0) = %q, want none", got)
+	}
+}
+
+func TestWrapSignature(t *testing.T) {
+	sig := "func Map[K comparable, V any](m map[K]V, keep func(K, V) bool) map[K]V"
+
+	if got := wrapSignature(sig, 0); !reflect.DeepEqual(got, []string{sig}) {
+		t.Errorf("wrapSignature(unlimited) = %q, want the signature unchanged", got)
+	}
+
+	got := wrapSignature(sig, 30)
+	want := []string{
+		"func Map[K comparable, V",
+		"any](m map[K]V, keep func(K,",
+		"V) bool) map[K]V",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrapSignature() = %q, want %q", got, want)
+	}
+	if strings.Join(got, " ") != sig {
+		t.Errorf("wrapped lines do not rejoin to the signature: %q", got)
+	}
+}
+
+func TestTruncateSignature(t *testing.T) {
+	sig := "func Map[K comparable, V any](m map[K]V) map[K]V"
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{0, sig},
+		{100, sig},
+		{30, "func Map[K comparable, V an..."},
+		{5, "func Map[K compar..."},
+	}
+	for _, tt := range tests {
+		if got := truncateSignature(sig, tt.width); got != tt.want {
+			t.Errorf("truncateSignature(%d) = %q, want %q", tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestFormatTextWidth(t *testing.T) {
+	method := "Range(ctx context.Context, fn func(key string, value any) bool) error"
+	result := &analyzer.Result{
+		Module:     "example.com/lib",
+		OldVersion: "v1.0.0",
+		NewVersion: "v2.0.0",
+		Changes: &analyzer.Diff{
+			InterfaceChanges: []analyzer.InterfaceChange{
+				{Name: "Store", AddedMethods: []string{method}, UsedIn: []analyzer.Location{{File: "a.go", Line: 1}}},
+			},
+		},
+	}
+
+	got, err := FormatTextWithOptions(result, TextOptions{Width: 40})
+	if err != nil {
+		t.Fatalf("FormatTextWithOptions() error = %v", err)
+	}
+	if !strings.Contains(got, "      - Range(ctx context.Context, fn...\n") {
+		t.Errorf("expected truncated method, got:\n%s", got)
+	}
+
+	got, err = FormatTextWithOptions(result, TextOptions{Width: 40, Verbose: true})
+	if err != nil {
+		t.Fatalf("FormatTextWithOptions() error = %v", err)
+	}
+	if !strings.Contains(got, "      - Range(ctx context.Context, fn\n        func(key string, value any)\n        bool) error\n") {
+		t.Errorf("expected wrapped method in verbose mode, got:\n%s", got)
+	}
+}
+
+func TestDiffSignature(t *testing.T) {
+	tests := []struct {
+		name      string
+		old, new  string
+		wantPlain string
+		wantHTML  string
+	}{
+		{
+			name:      "added parameter",
+			old:       "func(path string) error",
+			new:       "func(path string, opts ...Option) error",
+			wantPlain: "func(path string{+, opts ...Option+}) error",
+			wantHTML:  "func(path string<ins>, opts ...Option</ins>) error",
+		},
+		{
+			name:      "changed type",
+			old:       "func(m map[string]int) error",
+			new:       "func(m map[string]int64) (int, error)",
+			wantPlain: "func(m map[string][-int-]{+int64+}) {+(int, +}error{+)+}",
+			wantHTML:  "func(m map[string]<del>int</del><ins>int64</ins>) <ins>(int, </ins>error<ins>)</ins>",
+		},
+		{
+			name:      "escaped in HTML",
+			old:       "func(a <-chan int)",
+			new:       "func(a chan int)",
+			wantPlain: "func(a [-<--]chan int)",
+			wantHTML:  "func(a <del>&lt;-</del>chan int)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := diffSignature(tt.old, tt.new)
+			if got := formatSignatureDiff(diff, false); got != tt.wantPlain {
+				t.Errorf("formatSignatureDiff() = %q, want %q", got, tt.wantPlain)
+			}
+			if got := string(htmlSignatureDiff(diff)); got != tt.wantHTML {
+				t.Errorf("htmlSignatureDiff() = %q, want %q", got, tt.wantHTML)
+			}
+		})
+	}
+}
+
+func TestFormatSignatureDiffColor(t *testing.T) {
+	diff := diffSignature("func(path string) error", "func(path string, opts ...Option) error")
+	got := formatSignatureDiff(diff, true)
+	want := "func(path string" + ansiGreen + "," + ansiReset + " " + ansiGreen + "opts" + ansiReset + " " + ansiGreen + "...Option" + ansiReset + ") error"
+	if got != want {
+		t.Errorf("formatSignatureDiff(color) = %q, want %q", got, want)
+	}
+	if n := visibleLen(got); n != len("func(path string, opts ...Option) error") {
+		t.Errorf("visibleLen() = %d, want the uncolored length", n)
+	}
+}
+
+func TestFormatTextGroupBy(t *testing.T) {
+	result := &analyzer.Result{
+		Module:     "github.com/example/lib",
+		OldVersion: "v1.0.0",
+		NewVersion: "v2.0.0",
+		Changes: &analyzer.Diff{
+			Removed: []analyzer.RemovedSymbol{
+				{Name: "OldFunc", Type: "function", UsedIn: []analyzer.Location{{File: "cmd/main.go", Line: 12}, {File: "pkg/a.go", Line: 3}}},
+			},
+			Changed: []analyzer.ChangedSignature{
+				{Name: "Client.Do", OldSignature: "func()", NewSignature: "func() error", UsedIn: []analyzer.Location{{File: "pkg/a.go", Line: 1}}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		groupBy string
+		want    []string
+		wantNot []string
+	}{
+		{
+			name:    "by file",
+			groupBy: GroupByFile,
+			want: []string{
+				"Findings by File:",
+				"  cmd/main.go (1 use(s))\n    - line 12: OldFunc removed (function)",
+				"  pkg/a.go (2 use(s))\n    - line 1: Client.Do signature changed: func() -> func() error\n    - line 3: OldFunc removed (function)",
+			},
+			wantNot: []string{"Removed Symbols:", "Changed Signatures:"},
+		},
+		{
+			name:    "by package",
+			groupBy: GroupByPackage,
+			want: []string{
+				"Findings by Package:",
+				"  pkg (2 use(s))\n    - a.go:1: Client.Do signature changed",
+			},
+		},
+		{
+			name:    "by symbol",
+			groupBy: GroupBySymbol,
+			want:    []string{"Removed Symbols:", "Changed Signatures:"},
+			wantNot: []string{"Findings by"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := FormatTextWithOptions(result, TextOptions{GroupBy: tt.groupBy})
+			if err != nil {
+				t.Fatalf("FormatTextWithOptions() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, out)
+				}
+			}
+			for _, notWant := range tt.wantNot {
+				if strings.Contains(out, notWant) {
+					t.Errorf("expected output not to contain %q", notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatTextMaxFindings(t *testing.T) {
+	changes := &analyzer.Diff{}
+	for i := 0; i < 5; i++ {
+		changes.Removed = append(changes.Removed, analyzer.RemovedSymbol{
+			Name:   fmt.Sprintf("Func%d", i),
+			Type:   "function",
+			UsedIn: []analyzer.Location{{File: "main.go", Line: i + 1}},
+		})
+	}
+	changes.Changed = []analyzer.ChangedSignature{{Name: "Parse", OldSignature: "func()", NewSignature: "func() error"}}
+	result := &analyzer.Result{Module: "github.com/example/lib", OldVersion: "v1.0.0", NewVersion: "v2.0.0", Changes: changes}
+
+	out, err := FormatTextWithOptions(result, TextOptions{MaxFindings: 2})
+	if err != nil {
+		t.Fatalf("FormatTextWithOptions() error = %v", err)
+	}
+	for _, want := range []string{"  - Func1 (function)", "  ... 3 finding(s) omitted, see JSON for the full list\n\nChanged Signatures:\n  - Parse\n\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "  - Func2 (function)") {
+		t.Errorf("capped output lists Func2:\n%s", out)
+	}
+
+	out, err = FormatTextWithOptions(result, TextOptions{MaxFindings: 4, GroupBy: GroupByFile})
+	if err != nil {
+		t.Fatalf("FormatTextWithOptions() error = %v", err)
+	}
+	if want := "    - line 4: Func3 removed (function)\n    ... 1 finding(s) omitted, see JSON for the full list\n"; !strings.Contains(out, want) {
+		t.Errorf("expected output to contain %q, got:\n%s", want, out)
+	}
+
+	out, err = FormatTextWithOptions(result, TextOptions{})
+	if err != nil {
+		t.Fatalf("FormatTextWithOptions() error = %v", err)
+	}
+	if strings.Contains(out, "omitted") || !strings.Contains(out, "  - Func4 (function)") {
+		t.Errorf("uncapped output should list every finding:\n%s", out)
+	}
+
+	// Collapsed methods count towards the cap
+	result = &analyzer.Result{Module: "github.com/example/lib", OldVersion: "v1.0.0", NewVersion: "v1.1.0", Changes: &analyzer.Diff{
+		Added: []analyzer.AddedSymbol{
+			{Name: "Client", Type: "type"},
+			{Name: "Client.Do", Type: "function"},
+			{Name: "Client.Close", Type: "function"},
+			{Name: "Dial", Type: "function"},
+		},
+	}}
+	out, err = FormatTextWithOptions(result, TextOptions{Verbose: true, MaxFindings: 3})
+	if err != nil {
+		t.Fatalf("FormatTextWithOptions() error = %v", err)
+	}
+	if want := "  Types:\n    + Client (with Close, Do)\n  ... 1 finding(s) omitted"; !strings.Contains(out, want) {
+		t.Errorf("expected output to contain %q, got:\n%s", want, out)
+	}
+}
+
+func TestParseGroupBy(t *testing.T) {
+	for value, want := range map[string]string{"": GroupBySymbol, "symbol": GroupBySymbol, "file": GroupByFile, "package": GroupByPackage} {
+		got, err := ParseGroupBy(value)
+		if err != nil || got != want {
+			t.Errorf("ParseGroupBy(%q) = %q, %v; want %q", value, got, err, want)
+		}
+	}
+	if _, err := ParseGroupBy("module"); err == nil {
+		t.Error("expected error for unknown grouping")
+	}
+}
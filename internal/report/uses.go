@@ -0,0 +1,54 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// UsesReport represents the uses of a symbol in JSON
+type UsesReport struct {
+	Symbol string     `json:"symbol"`
+	UsedIn []Location `json:"used_in"`
+}
+
+// FormatUsesText lists the uses of a symbol, one file:line:column per line
+func FormatUsesText(symbol string, locations []analyzer.Location) string {
+	var b strings.Builder
+	if len(locations) == 0 {
+		b.WriteString(fmt.Sprintf("No uses of %s found.\n", symbol))
+		return b.String()
+	}
+
+	for _, loc := range locations {
+		b.WriteString(fmt.Sprintf("%s:%d:%d", loc.File, loc.Line, loc.Column))
+		if loc.Kind != "" {
+			b.WriteString(fmt.Sprintf(" (%s)", loc.Kind))
+		}
+		b.WriteString(approximateTag(loc.Approximate) + "\n")
+	}
+	b.WriteString(fmt.Sprintf("\n%d use(s) of %s\n", len(locations), symbol))
+	return b.String()
+}
+
+// FormatUsesJSON generates a JSON report of the uses of a symbol
+func FormatUsesJSON(symbol string, locations []analyzer.Location) (string, error) {
+	report := UsesReport{Symbol: symbol, UsedIn: []Location{}}
+	for _, loc := range locations {
+		report.UsedIn = append(report.UsedIn, Location{
+			File:        loc.File,
+			Line:        loc.Line,
+			Kind:        loc.Kind,
+			Approximate: loc.Approximate,
+		})
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data) + "\n", nil
+}
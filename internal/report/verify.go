@@ -0,0 +1,75 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// VerifyReport represents the verification of a migration in JSON
+type VerifyReport struct {
+	Module     string          `json:"module"`
+	OldVersion string          `json:"old_version"`
+	NewVersion string          `json:"new_version"`
+	Complete   bool            `json:"complete"`
+	Stragglers []StragglerItem `json:"stragglers"`
+}
+
+// StragglerItem represents a compile error left after a migration in JSON
+type StragglerItem struct {
+	Symbol   string `json:"symbol,omitempty"`
+	Position string `json:"position,omitempty"`
+	Message  string `json:"message"`
+}
+
+// FormatVerifyText confirms a completed migration or lists its stragglers
+func FormatVerifyText(v *analyzer.Verification) string {
+	var b strings.Builder
+	if v.Complete() {
+		b.WriteString(fmt.Sprintf("✓ Migration complete: the project compiles against %s %s with no references to APIs changed since %s.\n",
+			v.Module, v.NewVersion, v.OldVersion))
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("⚠️  MIGRATION INCOMPLETE: %d straggler(s) left migrating %s %s -> %s\n\n",
+		len(v.Stragglers), v.Module, v.OldVersion, v.NewVersion))
+	for _, s := range v.Stragglers {
+		line := "  - "
+		if s.Position != "" {
+			line += s.Position + ": "
+		}
+		line += s.Message
+		if s.Symbol != "" {
+			line += fmt.Sprintf(" (%s changed since %s)", s.Symbol, v.OldVersion)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// FormatVerifyJSON generates a JSON report of the verification of a migration
+func FormatVerifyJSON(v *analyzer.Verification) (string, error) {
+	report := VerifyReport{
+		Module:     v.Module,
+		OldVersion: v.OldVersion,
+		NewVersion: v.NewVersion,
+		Complete:   v.Complete(),
+		Stragglers: []StragglerItem{},
+	}
+	for _, s := range v.Stragglers {
+		report.Stragglers = append(report.Stragglers, StragglerItem{
+			Symbol:   s.Symbol,
+			Position: s.Position,
+			Message:  s.Message,
+		})
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,51 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestFormatVerifyText(t *testing.T) {
+	v := &analyzer.Verification{Module: "example.com/lib", OldVersion: "v1.0.0", NewVersion: "v2.0.0"}
+	if out := FormatVerifyText(v); !strings.Contains(out, "✓ Migration complete: the project compiles against example.com/lib v2.0.0") {
+		t.Errorf("unexpected output for a complete migration:\n%s", out)
+	}
+
+	v.Stragglers = []analyzer.Straggler{
+		{Symbol: "OldFunc", Position: "main.go:9:6", Message: "undefined: lib.OldFunc"},
+		{Message: "declared and not used: x"},
+	}
+	out := FormatVerifyText(v)
+	for _, want := range []string{
+		"MIGRATION INCOMPLETE: 2 straggler(s) left migrating example.com/lib v1.0.0 -> v2.0.0\n",
+		"  - main.go:9:6: undefined: lib.OldFunc (OldFunc changed since v1.0.0)\n",
+		"  - declared and not used: x\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatVerifyJSON(t *testing.T) {
+	v := &analyzer.Verification{
+		Module:     "example.com/lib",
+		OldVersion: "v1.0.0",
+		NewVersion: "v2.0.0",
+		Stragglers: []analyzer.Straggler{{Symbol: "OldFunc", Position: "main.go:9:6", Message: "undefined: lib.OldFunc"}},
+	}
+	out, err := FormatVerifyJSON(v)
+	if err != nil {
+		t.Fatalf("FormatVerifyJSON() error = %v", err)
+	}
+	var report VerifyReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	if report.Complete || len(report.Stragglers) != 1 || report.Stragglers[0].Symbol != "OldFunc" {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
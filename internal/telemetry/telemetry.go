@@ -0,0 +1,165 @@
+// Package telemetry records anonymous usage statistics for users who opt in,
+// showing which features and finding categories their audits use. Events
+// hold counts, durations, and flag names only: never module names, paths,
+// symbols, or flag values. Each event is appended to a local log and, only
+// when the user configures an endpoint of their own with
+// $GO_SEMVER_AUDIT_TELEMETRY_URL, posted to it as the JSON of Event; there is
+// no default endpoint, so nothing is sent to the maintainers. Setting
+// $GO_SEMVER_AUDIT_TELEMETRY to "off", or $DO_NOT_TRACK to anything but 0,
+// disables telemetry regardless of any other setting.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+// Environment variables controlling telemetry
+const (
+	EnvMode       = "GO_SEMVER_AUDIT_TELEMETRY"     // "on" opts in; "off" is the kill switch
+	EnvEndpoint   = "GO_SEMVER_AUDIT_TELEMETRY_URL" // where events are posted; unset posts nothing
+	EnvDoNotTrack = "DO_NOT_TRACK"
+)
+
+// Telemetry modes
+const (
+	ModeOn  = "on"
+	ModeOff = "off"
+)
+
+// postTimeout bounds how long a run waits for the endpoint
+const postTimeout = 2 * time.Second
+
+// Event describes one audit run
+type Event struct {
+	Version    string         `json:"version"`
+	GOOS       string         `json:"goos"`
+	GOARCH     string         `json:"goarch"`
+	DurationMS int64          `json:"duration_ms"`
+	Formats    []string       `json:"formats,omitempty"`
+	Flags      []string       `json:"flags,omitempty"`    // names of the flags set, never their values
+	Findings   map[string]int `json:"findings,omitempty"` // finding count per category
+	Breaking   bool           `json:"breaking"`
+}
+
+// Enabled resolves whether telemetry is on. mode is the user's setting, "on",
+// "off", or empty to defer to $GO_SEMVER_AUDIT_TELEMETRY; telemetry is off
+// unless opted into. The kill switches win over an explicit "on".
+func Enabled(mode string, getenv func(string) string) (bool, error) {
+	env := getenv(EnvMode)
+	if env == ModeOff {
+		return false, nil
+	}
+	if dnt := getenv(EnvDoNotTrack); dnt != "" && dnt != "0" {
+		return false, nil
+	}
+	if mode == "" {
+		mode = env
+	}
+	switch mode {
+	case ModeOn:
+		return true, nil
+	case "", ModeOff:
+		return false, nil
+	default:
+		return false, fmt.Errorf("telemetry must be on or off, got %q", mode)
+	}
+}
+
+// Findings counts the findings of a result by category
+func Findings(result *analyzer.Result) map[string]int {
+	counts := make(map[string]int)
+	if result.Changes == nil {
+		return counts
+	}
+	for _, f := range result.Changes.Removed {
+		counts[f.Category()]++
+	}
+	for _, f := range result.Changes.Moved {
+		counts[f.Category()]++
+	}
+	for _, f := range result.Changes.Changed {
+		counts[f.Category()]++
+	}
+	for _, f := range result.Changes.InterfaceChanges {
+		counts[f.Category()]++
+	}
+	return counts
+}
+
+// Recorder stores and forwards events
+type Recorder struct {
+	LogPath    string       // local JSON lines log, skipped when empty
+	Endpoint   string       // URL events are posted to, skipped when empty
+	HTTPClient *http.Client // defaults to a client with a short timeout
+}
+
+// NewRecorder logs events under the user cache directory and posts them to
+// $GO_SEMVER_AUDIT_TELEMETRY_URL when set
+func NewRecorder(getenv func(string) string) *Recorder {
+	r := &Recorder{Endpoint: getenv(EnvEndpoint)}
+	if dir, err := os.UserCacheDir(); err == nil {
+		r.LogPath = filepath.Join(dir, "go-semver-audit", "telemetry.jsonl")
+	}
+	return r
+}
+
+// Record logs and posts an event
+func (r *Recorder) Record(ctx context.Context, ev Event) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	if r.LogPath != "" {
+		if err := appendLine(r.LogPath, data); err != nil {
+			return fmt.Errorf("failed to log telemetry: %w", err)
+		}
+	}
+
+	if r.Endpoint == "" {
+		return nil
+	}
+	client := r.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: postTimeout}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.Endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send telemetry: %s", resp.Status)
+	}
+	return nil
+}
+
+// appendLine appends data and a newline to path, creating its directory
+func appendLine(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,94 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/devblac/go-semver-audit/internal/analyzer"
+)
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		env     map[string]string
+		want    bool
+		wantErr bool
+	}{
+		{name: "off by default", want: false},
+		{name: "opt in with flag", mode: "on", want: true},
+		{name: "opt in with env", env: map[string]string{EnvMode: "on"}, want: true},
+		{name: "flag overrides env opt in", mode: "off", env: map[string]string{EnvMode: "on"}, want: false},
+		{name: "env kill switch", mode: "on", env: map[string]string{EnvMode: "off"}, want: false},
+		{name: "do not track", mode: "on", env: map[string]string{EnvDoNotTrack: "1"}, want: false},
+		{name: "do not track disabled", mode: "on", env: map[string]string{EnvDoNotTrack: "0"}, want: true},
+		{name: "invalid", mode: "yes", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Enabled(tt.mode, func(key string) string { return tt.env[key] })
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Enabled(%q) = %v, %v, want %v (error %v)", tt.mode, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFindings(t *testing.T) {
+	result := &analyzer.Result{Changes: &analyzer.Diff{
+		Removed: []analyzer.RemovedSymbol{{Name: "A"}, {Name: "B"}},
+		Changed: []analyzer.ChangedSignature{{Name: "C", ParamNamesOnly: true}},
+	}}
+	want := map[string]int{analyzer.CategoryRemoved: 2, analyzer.CategorySignatureParamRename: 1}
+	if got := Findings(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("Findings() = %v, want %v", got, want)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	var posted Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &posted); err != nil {
+			t.Errorf("posted invalid JSON: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	logPath := filepath.Join(t.TempDir(), "telemetry", "events.jsonl")
+	r := &Recorder{LogPath: logPath, Endpoint: server.URL}
+	ev := Event{Version: "0.1.0", DurationMS: 42, Findings: map[string]int{analyzer.CategoryMoved: 1}}
+	for i := 0; i < 2; i++ {
+		if err := r.Record(context.Background(), ev); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	if !reflect.DeepEqual(posted, ev) {
+		t.Errorf("posted %+v, want %+v", posted, ev)
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 || !strings.Contains(lines[0], `"duration_ms":42`) {
+		t.Errorf("log = %q, want two events", data)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	if err := (&Recorder{Endpoint: failing.URL}).Record(context.Background(), ev); err == nil {
+		t.Error("expected an error from a failing endpoint")
+	}
+}
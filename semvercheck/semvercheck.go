@@ -0,0 +1,179 @@
+// Package semvercheck exposes go-semver-audit findings as a go/analysis
+// Analyzer, so uses of APIs that break in a dependency upgrade can be reported
+// by go vet style drivers and multichecker binaries.
+//
+// The analyzer does not compare module versions itself. It is configured with
+// a pre-computed diff: either a report written by `go-semver-audit -json` via
+// the -diff flag, or findings passed to New.
+package semvercheck
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/devblac/go-semver-audit/internal/report"
+)
+
+// Finding is a breaking change of one symbol of the audited module
+type Finding struct {
+	Symbol   string // Name for functions and types, Type.Method for methods
+	Message  string
+	Unstable bool // reported with an "unstable API" note instead of as breaking
+}
+
+// Analyzer reports uses of symbols listed in the report given by -diff
+var Analyzer = newFlagAnalyzer()
+
+// New returns an Analyzer that reports uses of the given findings in module
+func New(module string, findings []Finding) *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name: "semvercheck",
+		Doc:  "report uses of APIs that break in a dependency upgrade",
+	}
+	a.Run = func(pass *analysis.Pass) (interface{}, error) {
+		return nil, run(pass, module, findings)
+	}
+	return a
+}
+
+func newFlagAnalyzer() *analysis.Analyzer {
+	a := New("", nil)
+	a.Doc += "\n\nThe -diff flag names a report written by go-semver-audit -json."
+
+	var (
+		diffPath string
+		once     sync.Once
+		module   string
+		findings []Finding
+		loadErr  error
+	)
+	a.Flags.StringVar(&diffPath, "diff", "", "go-semver-audit JSON report to check against")
+	a.Run = func(pass *analysis.Pass) (interface{}, error) {
+		once.Do(func() {
+			if diffPath == "" {
+				loadErr = errors.New("-diff is required")
+				return
+			}
+			module, findings, loadErr = LoadReport(diffPath)
+		})
+		if loadErr != nil {
+			return nil, loadErr
+		}
+		return nil, run(pass, module, findings)
+	}
+	return a
+}
+
+// LoadReport reads the module and findings from a go-semver-audit JSON report
+func LoadReport(path string) (string, []Finding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read report: %w", err)
+	}
+	var r report.JSONReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return "", nil, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	return r.Module, findingsFromReport(&r), nil
+}
+
+// findingsFromReport turns the breaking sections of a JSON report into findings
+func findingsFromReport(r *report.JSONReport) []Finding {
+	target := r.Module + " " + r.NewVersion
+	var findings []Finding
+	for _, removed := range r.Removed {
+		findings = append(findings, Finding{
+			Symbol:   removed.Name,
+			Message:  fmt.Sprintf("%s (%s) is removed in %s", removed.Name, removed.Type, target),
+			Unstable: removed.Unstable,
+		})
+	}
+	for _, moved := range r.Moved {
+		findings = append(findings, Finding{
+			Symbol:   moved.Name,
+			Message:  fmt.Sprintf("%s moves in %s: import %s from %s", moved.Name, target, moved.NewName, moved.NewPackage),
+			Unstable: moved.Unstable,
+		})
+	}
+	for _, changed := range r.Changed {
+		findings = append(findings, Finding{
+			Symbol:   changed.Name,
+			Message:  fmt.Sprintf("%s changes signature in %s: %s -> %s", changed.Name, target, changed.OldSignature, changed.NewSignature),
+			Unstable: changed.Unstable,
+		})
+	}
+	for _, iface := range r.InterfaceChanges {
+		findings = append(findings, Finding{
+			Symbol:   iface.Name,
+			Message:  fmt.Sprintf("interface %s changes in %s", iface.Name, target),
+			Unstable: iface.Unstable,
+		})
+	}
+	return findings
+}
+
+// run reports every identifier in the package that refers to a finding's symbol
+func run(pass *analysis.Pass, module string, findings []Finding) error {
+	if module == "" || len(findings) == 0 {
+		return nil
+	}
+	bySymbol := make(map[string][]Finding)
+	for _, f := range findings {
+		bySymbol[f.Symbol] = append(bySymbol[f.Symbol], f)
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			obj := pass.TypesInfo.Uses[ident]
+			if obj == nil || obj.Pkg() == nil || !inModule(obj.Pkg().Path(), module) {
+				return true
+			}
+			for _, f := range bySymbol[symbolName(obj)] {
+				msg := f.Message
+				if f.Unstable {
+					msg += " (unstable API)"
+				}
+				pass.Reportf(ident.Pos(), "%s", msg)
+			}
+			return true
+		})
+	}
+	return nil
+}
+
+// symbolName spells obj the way findings name it: Type.Method for methods
+func symbolName(obj types.Object) string {
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return obj.Name()
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return obj.Name()
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return named.Obj().Name() + "." + obj.Name()
+	}
+	return obj.Name()
+}
+
+// inModule reports whether pkgPath belongs to module
+func inModule(pkgPath, module string) bool {
+	return pkgPath == module || strings.HasPrefix(pkgPath, module+"/")
+}
@@ -0,0 +1,47 @@
+package semvercheck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+const testReport = `{
+  "module": "example.com/lib",
+  "old_version": "v1.0.0",
+  "new_version": "v2.0.0",
+  "removed": [{"name": "Client.Do", "type": "function", "unstable": true}],
+  "changed": [{"name": "Parse", "old_signature": "func(s string) error", "new_signature": "func(s string, strict bool) error"}]
+}`
+
+func TestAnalyzerFromReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := os.WriteFile(path, []byte(testReport), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	module, findings, err := LoadReport(path)
+	if err != nil {
+		t.Fatalf("LoadReport() error = %v", err)
+	}
+	if module != "example.com/lib" || len(findings) != 2 {
+		t.Fatalf("LoadReport() = %q, %+v", module, findings)
+	}
+
+	analysistest.Run(t, analysistest.TestData(), New(module, findings), "app")
+}
+
+func TestFlagAnalyzerRequiresDiff(t *testing.T) {
+	a := newFlagAnalyzer()
+	results := analysistest.Run(&recordingT{}, analysistest.TestData(), a, "app")
+	if len(results) != 1 || results[0].Err == nil {
+		t.Fatalf("expected a missing -diff error, got %+v", results)
+	}
+}
+
+// recordingT swallows the failures analysistest reports for the expected error
+type recordingT struct{}
+
+func (recordingT) Errorf(format string, args ...interface{}) {}
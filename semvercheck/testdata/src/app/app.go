@@ -0,0 +1,10 @@
+package app
+
+import "example.com/lib"
+
+func run() {
+	_ = lib.Parse("x") // want `Parse changes signature in example.com/lib v2.0.0`
+	lib.Keep()
+	var c lib.Client
+	c.Do() // want `Client.Do \(function\) is removed in example.com/lib v2.0.0 \(unstable API\)`
+}
@@ -0,0 +1,9 @@
+package lib
+
+func Parse(s string) error { return nil }
+
+func Keep() {}
+
+type Client struct{}
+
+func (c *Client) Do() {}